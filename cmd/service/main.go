@@ -65,7 +65,18 @@ func main() {
 		WriteTimeout: 30 * time.Second,
 	}
 
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	// TLS opcional (incluye pedir client certs para mTLS, RFC 8705)
+	tlsCfg, err := v2server.TLSConfigFromEnv()
+	if err != nil {
+		log.Fatalf("TLS config failed: %v", err)
+	}
+	if tlsCfg != nil {
+		srv.TLSConfig = tlsCfg
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		log.Fatalf("    Server failed: %v", err)
 	}
 }
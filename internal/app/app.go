@@ -1,6 +1,7 @@
 package appv2
 
 import (
+	"crypto/x509"
 	"net/http"
 	"os"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	securityctrl "github.com/dropDatabas3/hellojohn/internal/http/controllers/security"
 	sessionctrl "github.com/dropDatabas3/hellojohn/internal/http/controllers/session"
 	socialctrl "github.com/dropDatabas3/hellojohn/internal/http/controllers/social"
+	sessiondto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
 	mw "github.com/dropDatabas3/hellojohn/internal/http/middlewares"
 	"github.com/dropDatabas3/hellojohn/internal/http/router"
 	"github.com/dropDatabas3/hellojohn/internal/http/services"
@@ -24,6 +26,7 @@ import (
 	oauth "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
 	socialsvc "github.com/dropDatabas3/hellojohn/internal/http/services/social"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -54,6 +57,14 @@ type Deps struct {
 	OAuthCache       oauth.CacheClient
 	OAuthCookieName  string
 	OAuthAllowBearer bool
+	DPoPRequireNonce bool
+
+	// ─── Mutual TLS (RFC 8705) ───
+	MTLSClientCAs        *x509.CertPool // roots for tls_client_auth (nil = trust the TLS terminator)
+	MTLSClientCertHeader string         // header with the client cert forwarded by a proxy
+
+	// ─── Phone verification / SMS MFA ───
+	SMS sms.Sender // nil = disabled
 }
 
 // App represents the wired V2 application.
@@ -82,6 +93,9 @@ func New(cfg Config, deps Deps) (*App, error) {
 		OAuthCache:       deps.OAuthCache,
 		OAuthCookieName:  deps.OAuthCookieName,
 		OAuthAllowBearer: deps.OAuthAllowBearer,
+		DPoPRequireNonce: deps.DPoPRequireNonce,
+		MTLSClientCAs:    deps.MTLSClientCAs,
+		SMS:              deps.SMS,
 		// Health Check
 		HealthDeps: healthsvc.Deps{
 			ControlPlane: deps.ControlPlane,
@@ -89,22 +103,28 @@ func New(cfg Config, deps Deps) (*App, error) {
 		},
 	})
 
+	// Resource routes check certificate-bound tokens against the same forwarded cert
+	mw.ClientCertHeader = deps.MTLSClientCertHeader
+
 	// 2. Build Controllers
 	authControllers := authctrl.NewControllers(svcs.Auth)
 	adminControllers := adminctrl.NewControllers(svcs.Admin, adminctrl.ControllerDeps{DAL: deps.DAL})
 	oidcControllers := oidcctrl.NewControllers(svcs.OIDC)
 
+	// /oauth2/logout clears the session cookie exactly like /v2/session/logout
+	sessionLogout := sessiondto.SessionLogoutConfig{}
+
 	oauthControllers := oauthctrl.NewControllers(svcs.OAuth, oauthctrl.ControllerDeps{
-		// Deps... inferred from services or passed explicitly?
-		// Checking codebase, OAuth NewControllers takes 2 args.
-		// Assuming empty deps structure is accepted or need to fill it.
-		// For wiring check, we pass zero value.
+		ClientCertHeader: deps.MTLSClientCertHeader,
+		SessionCookies:   svcs.Session.Logout,
+		SessionLogout:    sessionLogout,
 	})
 
 	socialControllers := socialctrl.NewControllers(svcs.Social)
 
 	sessionControllers := sessionctrl.NewControllers(svcs.Session, sessionctrl.ControllerDeps{
 		// Same assumption
+		LogoutConfig: sessionLogout,
 	})
 
 	emailControllers := emailctrl.NewControllers(svcs.Email)
@@ -21,6 +21,15 @@ type Client interface {
 	// Si ttl es 0, no expira.
 	Set(ctx context.Context, key, value string, ttl time.Duration) error
 
+	// SetNX guarda el valor solo si la key no existe (o expiró), de forma atómica.
+	// Retorna false si la key ya existía. Útil para marcas de un solo uso (jti).
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+
+	// Incr incrementa de forma atómica el contador en key y retorna el nuevo valor.
+	// Si la key no existe (o expiró) arranca en 1 con el TTL dado; los incrementos
+	// siguientes conservan la expiración original.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
 	// Delete elimina una key.
 	Delete(ctx context.Context, key string) error
 
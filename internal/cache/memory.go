@@ -2,7 +2,9 @@ package cache
 
 import (
 	"context"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,8 +14,8 @@ type memoryClient struct {
 	prefix string
 	data   map[string]memoryEntry
 	mu     sync.RWMutex
-	hits   int64
-	misses int64
+	hits   atomic.Int64 // Get corre bajo RLock: contadores atómicos
+	misses atomic.Int64
 }
 
 type memoryEntry struct {
@@ -43,17 +45,17 @@ func (c *memoryClient) Get(ctx context.Context, key string) (string, error) {
 
 	entry, ok := c.data[c.key(key)]
 	if !ok {
-		c.misses++
+		c.misses.Add(1)
 		return "", ErrNotFound
 	}
 
 	// Verificar expiración
 	if !entry.noExpire && time.Now().After(entry.expiresAt) {
-		c.misses++
+		c.misses.Add(1)
 		return "", ErrNotFound
 	}
 
-	c.hits++
+	c.hits.Add(1)
 	return entry.value, nil
 }
 
@@ -73,6 +75,47 @@ func (c *memoryClient) Set(ctx context.Context, key, value string, ttl time.Dura
 	return nil
 }
 
+func (c *memoryClient) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := c.key(key)
+	if entry, ok := c.data[k]; ok && (entry.noExpire || time.Now().Before(entry.expiresAt)) {
+		return false, nil
+	}
+
+	entry := memoryEntry{
+		value:    value,
+		noExpire: ttl == 0,
+	}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.data[k] = entry
+	return true, nil
+}
+
+func (c *memoryClient) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := c.key(key)
+	entry, ok := c.data[k]
+	if !ok || (!entry.noExpire && time.Now().After(entry.expiresAt)) {
+		entry = memoryEntry{noExpire: ttl == 0}
+		if ttl > 0 {
+			entry.expiresAt = time.Now().Add(ttl)
+		}
+	}
+
+	n, _ := strconv.ParseInt(entry.value, 10, 64)
+	n++
+	entry.value = strconv.FormatInt(n, 10)
+	c.data[k] = entry
+	return n, nil
+}
+
 func (c *memoryClient) Delete(ctx context.Context, key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -124,8 +167,8 @@ func (c *memoryClient) Stats(ctx context.Context) (Stats, error) {
 	return Stats{
 		Driver: "memory",
 		Keys:   count,
-		Hits:   c.hits,
-		Misses: c.misses,
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
 	}, nil
 }
 
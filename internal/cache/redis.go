@@ -64,6 +64,25 @@ func (c *redisClient) Set(ctx context.Context, key, value string, ttl time.Durat
 	return c.client.Set(ctx, c.key(key), value, ttl).Err()
 }
 
+func (c *redisClient) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, c.key(key), value, ttl).Result()
+}
+
+func (c *redisClient) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	k := c.key(key)
+	n, err := c.client.Incr(ctx, k).Result()
+	if err != nil {
+		return 0, err
+	}
+	// Primer incremento: la key se acaba de crear, fijar su expiración
+	if n == 1 && ttl > 0 {
+		if err := c.client.Expire(ctx, k, ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
 func (c *redisClient) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, c.key(key)).Err()
 }
@@ -1 +1,321 @@
 package claims
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Subset de JSON Schema (draft 2020-12) soportado para validar documentos JSON
+// decodificados con encoding/json (map[string]any, []any, float64, string, bool, nil).
+// Las keywords de anotación se aceptan y se ignoran; cualquier otra se rechaza
+// en CheckSchema para que un schema nunca aparente restricciones que no aplica.
+var (
+	schemaAnnotations = map[string]bool{
+		"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+		"default": true, "examples": true, "format": true, "deprecated": true,
+	}
+	schemaKeywords = map[string]bool{
+		"type": true, "enum": true, "const": true,
+		"properties": true, "required": true, "additionalProperties": true,
+		"items": true, "minItems": true, "maxItems": true,
+		"minLength": true, "maxLength": true, "pattern": true,
+		"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true,
+	}
+	schemaTypes = map[string]bool{
+		"object": true, "array": true, "string": true, "number": true,
+		"integer": true, "boolean": true, "null": true,
+	}
+)
+
+// CheckSchema verifica que schema use sólo keywords soportadas con valores válidos.
+func CheckSchema(schema map[string]any) error {
+	return checkSchema(schema, "")
+}
+
+func checkSchema(schema map[string]any, path string) error {
+	for k, v := range schema {
+		if schemaAnnotations[k] {
+			continue
+		}
+		if !schemaKeywords[k] {
+			return fmt.Errorf("%s: unsupported keyword %q", schemaPath(path), k)
+		}
+		switch k {
+		case "type":
+			types := schemaTypeList(v)
+			if len(types) == 0 {
+				return fmt.Errorf("%s: invalid type", schemaPath(path))
+			}
+			for _, t := range types {
+				if !schemaTypes[t] {
+					return fmt.Errorf("%s: invalid type %q", schemaPath(path), t)
+				}
+			}
+		case "enum":
+			if _, ok := v.([]any); !ok {
+				return fmt.Errorf("%s: enum must be an array", schemaPath(path))
+			}
+		case "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: properties must be an object", schemaPath(path))
+			}
+			for name, sub := range props {
+				m, ok := sub.(map[string]any)
+				if !ok {
+					return fmt.Errorf("%s: property schema must be an object", schemaPath(path+"/"+name))
+				}
+				if err := checkSchema(m, path+"/"+name); err != nil {
+					return err
+				}
+			}
+		case "required":
+			list, ok := v.([]any)
+			if !ok {
+				return fmt.Errorf("%s: required must be an array", schemaPath(path))
+			}
+			for _, r := range list {
+				if _, ok := r.(string); !ok {
+					return fmt.Errorf("%s: required must list property names", schemaPath(path))
+				}
+			}
+		case "additionalProperties", "items":
+			switch sub := v.(type) {
+			case bool:
+				if k == "items" {
+					return fmt.Errorf("%s: items must be a schema", schemaPath(path))
+				}
+			case map[string]any:
+				if err := checkSchema(sub, path+"/"+k); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s: %s must be a schema", schemaPath(path), k)
+			}
+		case "pattern":
+			p, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("%s: pattern must be a string", schemaPath(path))
+			}
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("%s: invalid pattern: %v", schemaPath(path), err)
+			}
+		case "const":
+		default: // límites numéricos
+			if _, ok := schemaNumber(v); !ok {
+				return fmt.Errorf("%s: %s must be a number", schemaPath(path), k)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateSchema valida value contra schema (previamente aceptado por CheckSchema).
+// El error indica la ruta JSON Pointer del primer valor inválido.
+func ValidateSchema(schema map[string]any, value any) error {
+	return validateSchema(schema, value, "")
+}
+
+func validateSchema(schema map[string]any, value any, path string) error {
+	if v, ok := schema["type"]; ok {
+		matched := false
+		for _, t := range schemaTypeList(v) {
+			if schemaTypeMatches(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %s", schemaPath(path), strings.Join(schemaTypeList(v), " or "))
+		}
+	}
+	if c, ok := schema["const"]; ok && !schemaEqual(c, value) {
+		return fmt.Errorf("%s: must be %v", schemaPath(path), c)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if schemaEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value not allowed", schemaPath(path))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(schema, v, path)
+	case []any:
+		if n, ok := schemaNumber(schema["minItems"]); ok && float64(len(v)) < n {
+			return fmt.Errorf("%s: too few items", schemaPath(path))
+		}
+		if n, ok := schemaNumber(schema["maxItems"]); ok && float64(len(v)) > n {
+			return fmt.Errorf("%s: too many items", schemaPath(path))
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateSchema(items, item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := schemaNumber(schema["minLength"]); ok && length < n {
+			return fmt.Errorf("%s: too short", schemaPath(path))
+		}
+		if n, ok := schemaNumber(schema["maxLength"]); ok && length > n {
+			return fmt.Errorf("%s: too long", schemaPath(path))
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err != nil || !re.MatchString(v) {
+				return fmt.Errorf("%s: does not match pattern", schemaPath(path))
+			}
+		}
+	case float64:
+		if n, ok := schemaNumber(schema["minimum"]); ok && v < n {
+			return fmt.Errorf("%s: must be >= %v", schemaPath(path), n)
+		}
+		if n, ok := schemaNumber(schema["maximum"]); ok && v > n {
+			return fmt.Errorf("%s: must be <= %v", schemaPath(path), n)
+		}
+		if n, ok := schemaNumber(schema["exclusiveMinimum"]); ok && v <= n {
+			return fmt.Errorf("%s: must be > %v", schemaPath(path), n)
+		}
+		if n, ok := schemaNumber(schema["exclusiveMaximum"]); ok && v >= n {
+			return fmt.Errorf("%s: must be < %v", schemaPath(path), n)
+		}
+	}
+	return nil
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string) error {
+	if req, ok := schema["required"].([]any); ok {
+		for _, r := range req {
+			if name, _ := r.(string); name != "" {
+				if _, present := obj[name]; !present {
+					return fmt.Errorf("%s: missing required property %q", schemaPath(path), name)
+				}
+			}
+		}
+	}
+	props, _ := schema["properties"].(map[string]any)
+	for name, val := range obj {
+		if sub, ok := props[name].(map[string]any); ok {
+			if err := validateSchema(sub, val, path+"/"+name); err != nil {
+				return err
+			}
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				return fmt.Errorf("%s: unknown property %q", schemaPath(path), name)
+			}
+		case map[string]any:
+			if err := validateSchema(extra, val, path+"/"+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypeList normaliza "type" (string o array de strings).
+func schemaTypeList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, x := range t {
+			s, ok := x.(string)
+			if !ok {
+				return nil
+			}
+			out = append(out, s)
+		}
+		return out
+	}
+	return nil
+}
+
+func schemaTypeMatches(t string, v any) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+// schemaNumber acepta los números de un schema venido de JSON (float64) o YAML (int).
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// schemaEqual compara valores JSON tratando igual los números de JSON y de YAML.
+func schemaEqual(a, b any) bool {
+	return reflect.DeepEqual(schemaNormalize(a), schemaNormalize(b))
+}
+
+func schemaNormalize(v any) any {
+	if n, ok := schemaNumber(v); ok {
+		return n
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, x := range t {
+			out[k] = schemaNormalize(x)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, x := range t {
+			out[i] = schemaNormalize(x)
+		}
+		return out
+	}
+	return v
+}
+
+func schemaPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,82 @@
+package claims
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustJSON(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+const paymentSchema = `{
+	"type": "object",
+	"required": ["type", "instructedAmount"],
+	"properties": {
+		"type": {"const": "payment_initiation"},
+		"actions": {"type": "array", "items": {"enum": ["initiate", "status"]}, "minItems": 1},
+		"instructedAmount": {
+			"type": "object",
+			"required": ["currency", "amount"],
+			"additionalProperties": false,
+			"properties": {
+				"currency": {"type": "string", "pattern": "^[A-Z]{3}$"},
+				"amount": {"type": "number", "exclusiveMinimum": 0, "maximum": 10000}
+			}
+		},
+		"creditorName": {"type": "string", "maxLength": 10},
+		"installments": {"type": "integer", "minimum": 1}
+	}
+}`
+
+func TestCheckSchema(t *testing.T) {
+	if err := CheckSchema(mustJSON(t, paymentSchema)); err != nil {
+		t.Fatalf("valid schema rejected: %v", err)
+	}
+	for _, bad := range []string{
+		`{"$ref": "#/defs/x"}`,
+		`{"type": "decimal"}`,
+		`{"properties": {"a": 1}}`,
+		`{"required": "a"}`,
+		`{"items": true}`,
+		`{"pattern": "("}`,
+		`{"minimum": "1"}`,
+		`{"enum": "a"}`,
+	} {
+		if CheckSchema(mustJSON(t, bad)) == nil {
+			t.Errorf("%s accepted", bad)
+		}
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := mustJSON(t, paymentSchema)
+	valid := `{"type": "payment_initiation", "actions": ["initiate"], "instructedAmount": {"currency": "EUR", "amount": 123.5}, "installments": 3}`
+	if err := ValidateSchema(schema, mustJSON(t, valid)); err != nil {
+		t.Fatalf("valid document rejected: %v", err)
+	}
+
+	tests := []struct{ name, doc string }{
+		{"missing required", `{"type": "payment_initiation"}`},
+		{"wrong const", `{"type": "account_information", "instructedAmount": {"currency": "EUR", "amount": 1}}`},
+		{"pattern", `{"type": "payment_initiation", "instructedAmount": {"currency": "eur", "amount": 1}}`},
+		{"exclusive minimum", `{"type": "payment_initiation", "instructedAmount": {"currency": "EUR", "amount": 0}}`},
+		{"maximum", `{"type": "payment_initiation", "instructedAmount": {"currency": "EUR", "amount": 20000}}`},
+		{"additional property", `{"type": "payment_initiation", "instructedAmount": {"currency": "EUR", "amount": 1, "fee": 2}}`},
+		{"enum item", `{"type": "payment_initiation", "actions": ["refund"], "instructedAmount": {"currency": "EUR", "amount": 1}}`},
+		{"min items", `{"type": "payment_initiation", "actions": [], "instructedAmount": {"currency": "EUR", "amount": 1}}`},
+		{"max length", `{"type": "payment_initiation", "creditorName": "Merchant A GmbH", "instructedAmount": {"currency": "EUR", "amount": 1}}`},
+		{"integer", `{"type": "payment_initiation", "installments": 1.5, "instructedAmount": {"currency": "EUR", "amount": 1}}`},
+		{"wrong type", `{"type": "payment_initiation", "instructedAmount": "1 EUR"}`},
+	}
+	for _, tt := range tests {
+		if ValidateSchema(schema, mustJSON(t, tt.doc)) == nil {
+			t.Errorf("%s: invalid document accepted", tt.name)
+		}
+	}
+}
@@ -87,6 +87,21 @@ func defaultTemplatesES() map[string]repository.EmailTemplate {
         </div>
         <p>Si no solicitaste este cambio, por favor ignora este correo. Tu contraseña actual seguirá funcionando.</p>
         <div class="warning-box">Por seguridad, este enlace solo es válido por <strong>{{.TTL}}</strong>.</div>
+        `, footerES),
+		},
+		"passwordless_login": {
+			Subject: "Tu acceso",
+			Body: wrapHTML(`
+        <h2 style="margin-top: 0; color: #333;">Iniciar sesión en {{.Tenant}}</h2>
+        <p>Hola <strong>{{.UserEmail}}</strong>,</p>
+        {{if .Link}}<p>Usa el siguiente botón para iniciar sesión. Ábrelo en el mismo navegador donde lo solicitaste.</p>
+        <div style="text-align: center; margin: 30px 0;">
+          <a href="{{.Link}}" class="button" style="color: #ffffff;">Iniciar sesión</a>
+        </div>
+        {{else}}<p>Tu código para iniciar sesión es:</p>
+        <p style="text-align: center; font-size: 32px; font-weight: 600; letter-spacing: 8px; margin: 30px 0;">{{.Code}}</p>
+        {{end}}<div class="info-box">Vence en <strong>{{.TTL}}</strong> y solo puede usarse una vez.</div>
+        <p>Si no intentaste iniciar sesión, puedes ignorar este mensaje.</p>
         `, footerES),
 		},
 		"user_blocked": {
@@ -96,10 +111,14 @@ func defaultTemplatesES() map[string]repository.EmailTemplate {
         <p>Hola <strong>{{.UserEmail}}</strong>,</p>
         <p>Tu acceso a {{.Tenant}} ha sido suspendido temporalmente debido a una acción administrativa o de seguridad.</p>
         <div class="warning-box" style="border-color: #d93025; background-color: #fff5f5; color: #b71c1c;">
-          <strong>Motivo:</strong> {{.Reason}}<br>
-          <strong>Vigencia:</strong> Hasta {{.Until}}
+          <strong>Motivo:</strong> {{.Reason}}{{if .Until}}<br>
+          <strong>Vigencia:</strong> Hasta {{.Until}}{{end}}
+        </div>
+        {{if .Link}}<p>Si fuiste tú, puedes desbloquear tu cuenta ahora mismo:</p>
+        <div style="text-align: center; margin: 30px 0;">
+          <a href="{{.Link}}" class="button" style="background-color: #27272a; color: #ffffff;">Desbloquear Cuenta</a>
         </div>
-        <p>Si consideras que esto es un error, por favor ponte en contacto con nuestro equipo de soporte inmediatamente.</p>
+        {{end}}<p>Si consideras que esto es un error, por favor ponte en contacto con nuestro equipo de soporte inmediatamente.</p>
         `, footerES),
 		},
 		"user_unblocked": {
@@ -148,6 +167,21 @@ func defaultTemplatesEN() map[string]repository.EmailTemplate {
         </div>
         <p>If you didn't request this change, please ignore this email. Your current password will continue to work.</p>
         <div class="warning-box">For security, this link is only valid for <strong>{{.TTL}}</strong>.</div>
+        `, footerEN),
+		},
+		"passwordless_login": {
+			Subject: "Your sign-in",
+			Body: wrapHTML(`
+        <h2 style="margin-top: 0; color: #333;">Sign in to {{.Tenant}}</h2>
+        <p>Hello <strong>{{.UserEmail}}</strong>,</p>
+        {{if .Link}}<p>Use the button below to sign in. Open it in the same browser where you requested it.</p>
+        <div style="text-align: center; margin: 30px 0;">
+          <a href="{{.Link}}" class="button" style="color: #ffffff;">Sign in</a>
+        </div>
+        {{else}}<p>Your sign-in code is:</p>
+        <p style="text-align: center; font-size: 32px; font-weight: 600; letter-spacing: 8px; margin: 30px 0;">{{.Code}}</p>
+        {{end}}<div class="info-box">It expires in <strong>{{.TTL}}</strong> and can only be used once.</div>
+        <p>If you didn't try to sign in, you can ignore this message.</p>
         `, footerEN),
 		},
 		"user_blocked": {
@@ -157,10 +191,14 @@ func defaultTemplatesEN() map[string]repository.EmailTemplate {
         <p>Hello <strong>{{.UserEmail}}</strong>,</p>
         <p>Your access to {{.Tenant}} has been temporarily suspended due to an administrative or security action.</p>
         <div class="warning-box" style="border-color: #d93025; background-color: #fff5f5; color: #b71c1c;">
-          <strong>Reason:</strong> {{.Reason}}<br>
-          <strong>Until:</strong> {{.Until}}
+          <strong>Reason:</strong> {{.Reason}}{{if .Until}}<br>
+          <strong>Until:</strong> {{.Until}}{{end}}
+        </div>
+        {{if .Link}}<p>If this was you, you can unlock your account right away:</p>
+        <div style="text-align: center; margin: 30px 0;">
+          <a href="{{.Link}}" class="button" style="background-color: #27272a; color: #ffffff;">Unlock Account</a>
         </div>
-        <p>If you believe this is an error, please contact our support team immediately.</p>
+        {{end}}<p>If you believe this is an error, please contact our support team immediately.</p>
         `, footerEN),
 		},
 		"user_unblocked": {
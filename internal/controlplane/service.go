@@ -47,6 +47,12 @@
 //	CreateScope(ctx context.Context, slug, name, description string) (*repository.Scope, error)
 //	DeleteScope(ctx context.Context, slug, name string) error
 //
+//	// ─── API Resources ───
+//	ListAPIResources(ctx context.Context, slug string) ([]repository.APIResource, error)
+//	GetAPIResource(ctx context.Context, slug, identifier string) (*repository.APIResource, error)
+//	UpsertAPIResource(ctx context.Context, slug string, input repository.APIResourceInput) (*repository.APIResource, error)
+//	DeleteAPIResource(ctx context.Context, slug, identifier string) error
+//
 //	// ─── Validations ───
 //	ValidateClientID(id string) bool
 //	ValidateRedirectURI(uri string) bool
@@ -55,13 +61,18 @@ package controlplane
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
 	sec "github.com/dropDatabas3/hellojohn/internal/security/secretbox"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
@@ -105,6 +116,12 @@ type Service interface {
 	UpsertScope(ctx context.Context, slug string, input repository.ScopeInput) (*repository.Scope, error)
 	DeleteScope(ctx context.Context, slug, name string) error
 
+	// ─── API Resources (RFC 8707) ───
+	ListAPIResources(ctx context.Context, slug string) ([]repository.APIResource, error)
+	GetAPIResource(ctx context.Context, slug, identifier string) (*repository.APIResource, error)
+	UpsertAPIResource(ctx context.Context, slug string, input repository.APIResourceInput) (*repository.APIResource, error)
+	DeleteAPIResource(ctx context.Context, slug, identifier string) error
+
 	// ─── Claims ───
 	GetClaimsConfig(ctx context.Context, slug string) (*ClaimsConfig, error)
 	ListCustomClaims(ctx context.Context, slug string) ([]repository.ClaimDefinition, error)
@@ -162,6 +179,43 @@ type ClientInput struct {
 	IDTokenTTL      int
 	PostLogoutURIs  []string
 	Description     string
+	TokenExchange   *repository.TokenExchangePolicy
+
+	// Autenticación en el token endpoint
+	TokenEndpointAuthMethod string
+	JWKS                    string // JSON inline
+	JWKSURI                 string
+	TLSClientAuthSubjectDN  string // tls_client_auth (RFC 8705)
+	TLSClientCertThumbprint string // self_signed_tls_client_auth: x5t#S256
+
+	RequirePAR bool // Pushed Authorization Requests (RFC 9126)
+
+	IDTokenSignedResponseAlg string // "" = algoritmo del tenant
+	FirstParty               bool   // client propio: puede saltar el consentimiento
+
+	// OIDC Back-Channel / Front-Channel Logout
+	BackchannelLogoutURI              string
+	BackchannelLogoutSessionRequired  bool
+	FrontchannelLogoutURI             string
+	FrontchannelLogoutSessionRequired bool
+
+	// Subject identifiers (OIDC Core §8): "" / "public" o "pairwise"
+	SubjectType         string
+	SectorIdentifierURI string
+
+	// JWT-secured authorization requests (RFC 9101) y JARM
+	RequestObjectSigningAlg        string
+	RequireSignedRequestObject     bool
+	AuthorizationSignedResponseAlg string
+
+	// Respuestas cifradas (JWE) y userinfo firmado
+	IDTokenEncryptedResponseAlg  string
+	IDTokenEncryptedResponseEnc  string
+	UserinfoSignedResponseAlg    string
+	UserinfoEncryptedResponseAlg string
+	UserinfoEncryptedResponseEnc string
+
+	RegistrationAccessTokenHash string // Dynamic Client Registration (RFC 7592); "" en Update = conservar
 }
 
 // CreateAdminInput contiene los datos para crear un admin.
@@ -357,6 +411,16 @@ func (s *service) CreateClient(ctx context.Context, slug string, input ClientInp
 		}
 	}
 
+	if err := validateClientAuth(input); err != nil {
+		return nil, err
+	}
+	if err := s.validateLogoutURIs(input); err != nil {
+		return nil, err
+	}
+	if err := validateSubjectType(ctx, input); err != nil {
+		return nil, err
+	}
+
 	// Cifrar secret para confidential clients
 	var secretEnc string
 	var plainSecret string
@@ -401,6 +465,36 @@ func (s *service) CreateClient(ctx context.Context, slug string, input ClientInp
 		IDTokenTTL:      input.IDTokenTTL,
 		PostLogoutURIs:  uniqueStrings(input.PostLogoutURIs),
 		Description:     input.Description,
+		TokenExchange:   input.TokenExchange,
+
+		TokenEndpointAuthMethod:  input.TokenEndpointAuthMethod,
+		JWKS:                     input.JWKS,
+		JWKSURI:                  input.JWKSURI,
+		TLSClientAuthSubjectDN:   input.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  input.TLSClientCertThumbprint,
+		RequirePAR:               input.RequirePAR,
+		IDTokenSignedResponseAlg: input.IDTokenSignedResponseAlg,
+		FirstParty:               input.FirstParty,
+
+		BackchannelLogoutURI:              input.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  input.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             input.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: input.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         input.SubjectType,
+		SectorIdentifierURI: input.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        input.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     input.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: input.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  input.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  input.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    input.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: input.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: input.UserinfoEncryptedResponseEnc,
+
+		RegistrationAccessTokenHash: input.RegistrationAccessTokenHash,
 	}
 
 	client, err := s.store.ConfigAccess().Clients(slug).Create(ctx, slug, repoInput)
@@ -429,6 +523,16 @@ func (s *service) UpdateClient(ctx context.Context, slug string, input ClientInp
 		}
 	}
 
+	if err := validateClientAuth(input); err != nil {
+		return nil, err
+	}
+	if err := s.validateLogoutURIs(input); err != nil {
+		return nil, err
+	}
+	if err := validateSubjectType(ctx, input); err != nil {
+		return nil, err
+	}
+
 	// Cifrar secret si viene nuevo
 	var secretEnc string
 	if input.Type == "confidential" && input.Secret != "" {
@@ -460,6 +564,36 @@ func (s *service) UpdateClient(ctx context.Context, slug string, input ClientInp
 		IDTokenTTL:      input.IDTokenTTL,
 		PostLogoutURIs:  uniqueStrings(input.PostLogoutURIs),
 		Description:     input.Description,
+		TokenExchange:   input.TokenExchange,
+
+		TokenEndpointAuthMethod:  input.TokenEndpointAuthMethod,
+		JWKS:                     input.JWKS,
+		JWKSURI:                  input.JWKSURI,
+		TLSClientAuthSubjectDN:   input.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  input.TLSClientCertThumbprint,
+		RequirePAR:               input.RequirePAR,
+		IDTokenSignedResponseAlg: input.IDTokenSignedResponseAlg,
+		FirstParty:               input.FirstParty,
+
+		BackchannelLogoutURI:              input.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  input.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             input.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: input.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         input.SubjectType,
+		SectorIdentifierURI: input.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        input.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     input.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: input.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  input.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  input.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    input.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: input.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: input.UserinfoEncryptedResponseEnc,
+
+		RegistrationAccessTokenHash: input.RegistrationAccessTokenHash,
 	}
 
 	return s.store.ConfigAccess().Clients(slug).Update(ctx, slug, repoInput)
@@ -528,6 +662,72 @@ func (s *service) DeleteScope(ctx context.Context, slug, name string) error {
 	return s.store.ConfigAccess().Scopes(slug).Delete(ctx, slug, name)
 }
 
+// ─── API Resources ───
+
+func (s *service) ListAPIResources(ctx context.Context, slug string) ([]repository.APIResource, error) {
+	return s.store.ConfigAccess().APIResources(slug).List(ctx, slug)
+}
+
+func (s *service) GetAPIResource(ctx context.Context, slug, identifier string) (*repository.APIResource, error) {
+	return s.store.ConfigAccess().APIResources(slug).Get(ctx, slug, identifier)
+}
+
+func (s *service) UpsertAPIResource(ctx context.Context, slug string, input repository.APIResourceInput) (*repository.APIResource, error) {
+	input.Identifier = strings.TrimSpace(input.Identifier)
+	input.Name = strings.TrimSpace(input.Name)
+	input.Scopes = uniqueStrings(input.Scopes)
+	if err := validateAPIResource(input); err != nil {
+		return nil, err
+	}
+	return s.store.ConfigAccess().APIResources(slug).Upsert(ctx, slug, input)
+}
+
+func (s *service) DeleteAPIResource(ctx context.Context, slug, identifier string) error {
+	res, err := s.GetAPIResource(ctx, slug, identifier)
+	if err != nil {
+		return err
+	}
+
+	// Verificar que ningún client tenga scopes de la API
+	clients, err := s.ListClients(ctx, slug)
+	if err != nil {
+		return err
+	}
+	for _, c := range clients {
+		for _, sc := range c.Scopes {
+			if _, ok := res.SplitQualifiedScope(sc); ok {
+				return fmt.Errorf("%w: resource %s is used by client %s", ErrScopeInUse, identifier, c.ClientID)
+			}
+		}
+	}
+
+	return s.store.ConfigAccess().APIResources(slug).Delete(ctx, slug, identifier)
+}
+
+// validateAPIResource valida un API resource: identifier URI absoluta sin
+// fragmento (RFC 8707 §2), scopes sin espacios, alg de firma y perfil soportados.
+func validateAPIResource(input repository.APIResourceInput) error {
+	u, err := url.Parse(input.Identifier)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || strings.Contains(input.Identifier, "#") {
+		return fmt.Errorf("%w: resource identifier must be an absolute URI without fragment", ErrBadInput)
+	}
+	for _, sc := range input.Scopes {
+		if sc == "" || strings.ContainsAny(sc, " \t\n\"\\") {
+			return fmt.Errorf("%w: invalid resource scope %q", ErrBadInput, sc)
+		}
+	}
+	if input.AccessTokenTTL < 0 {
+		return fmt.Errorf("%w: access_token_ttl must be >= 0", ErrBadInput)
+	}
+	if input.SigningAlg != "" && !jwtx.IsSigningAlgSupported(input.SigningAlg) {
+		return fmt.Errorf("%w: unsupported signing_alg %s", ErrBadInput, input.SigningAlg)
+	}
+	if input.AccessTokenProfile != repository.AccessTokenProfileDefault && input.AccessTokenProfile != repository.AccessTokenProfileRFC9068 {
+		return fmt.Errorf("%w: unsupported access_token_profile %s", ErrBadInput, input.AccessTokenProfile)
+	}
+	return nil
+}
+
 // ─── Claims ───
 
 // ClaimsConfig representa la configuración completa de claims de un tenant.
@@ -636,8 +836,23 @@ func (s *service) ValidateRedirectURI(uri string) bool {
 	return false
 }
 
+// IsScopeAllowed verifica si el client puede pedir el scope.
+// Los scopes calificados por una API registrada ("<identifier>/<scope>") además
+// deben existir en la API; el client los habilita uno a uno o todos con "<identifier>/*".
 func (s *service) IsScopeAllowed(client *repository.Client, scope string) bool {
 	scope = strings.TrimSpace(scope)
+	if res := s.resourceForScope(client.TenantID, scope); res != nil {
+		name, _ := res.SplitQualifiedScope(scope)
+		if !res.HasScope(name) {
+			return false
+		}
+		for _, sc := range client.Scopes {
+			if sc == scope || sc == res.QualifiedScope("*") {
+				return true
+			}
+		}
+		return false
+	}
 	for _, s := range client.Scopes {
 		if s == scope {
 			return true
@@ -646,6 +861,167 @@ func (s *service) IsScopeAllowed(client *repository.Client, scope string) bool {
 	return false
 }
 
+// resourceForScope devuelve la API registrada que califica al scope, o nil.
+func (s *service) resourceForScope(tenantSlug, scope string) *repository.APIResource {
+	if tenantSlug == "" || !strings.Contains(scope, ":") {
+		return nil
+	}
+	repo := s.store.ConfigAccess().APIResources(tenantSlug)
+	if repo == nil {
+		return nil
+	}
+	resources, err := repo.List(context.Background(), tenantSlug)
+	if err != nil {
+		return nil
+	}
+	var best *repository.APIResource
+	for i := range resources {
+		// El identifier más largo gana (APIs anidadas: https://api/x y https://api/x/y)
+		if _, ok := resources[i].SplitQualifiedScope(scope); ok {
+			if best == nil || len(resources[i].Identifier) > len(best.Identifier) {
+				best = &resources[i]
+			}
+		}
+	}
+	return best
+}
+
+// validateLogoutURIs valida las URIs de back/front-channel logout:
+// mismas reglas que redirect_uri y sin fragmento (OIDC Logout 1.0).
+func (s *service) validateLogoutURIs(input ClientInput) error {
+	for _, u := range []struct{ name, uri string }{
+		{"backchannel_logout_uri", input.BackchannelLogoutURI},
+		{"frontchannel_logout_uri", input.FrontchannelLogoutURI},
+	} {
+		if u.uri == "" {
+			continue
+		}
+		if !s.ValidateRedirectURI(u.uri) || strings.Contains(u.uri, "#") {
+			return fmt.Errorf("%w: invalid %s", ErrBadInput, u.name)
+		}
+	}
+	return nil
+}
+
+// validateSubjectType valida subject_type y sector_identifier_uri (OIDC Registration §5).
+// Un client pairwise sin sector_identifier_uri debe usar un único host en sus
+// redirect_uris: ese host es el sector.
+func validateSubjectType(ctx context.Context, input ClientInput) error {
+	switch input.SubjectType {
+	case "", pairwise.SubjectTypePublic, pairwise.SubjectTypePairwise:
+	default:
+		return fmt.Errorf("%w: unsupported subject_type", ErrBadInput)
+	}
+
+	if input.SectorIdentifierURI != "" {
+		if err := pairwise.VerifySectorIdentifier(ctx, input.SectorIdentifierURI, input.RedirectURIs); err != nil {
+			return fmt.Errorf("%w: invalid sector_identifier_uri: %v", ErrBadInput, err)
+		}
+		return nil
+	}
+
+	if input.SubjectType == pairwise.SubjectTypePairwise {
+		hosts := map[string]bool{}
+		for _, r := range input.RedirectURIs {
+			if u, err := url.Parse(r); err == nil {
+				hosts[strings.ToLower(u.Host)] = true
+			}
+		}
+		if len(hosts) > 1 {
+			return fmt.Errorf("%w: sector_identifier_uri required for pairwise clients with redirect_uris on several hosts", ErrBadInput)
+		}
+	}
+	return nil
+}
+
+// validateClientAuth valida token_endpoint_auth_method y las claves asociadas.
+func validateClientAuth(input ClientInput) error {
+	switch input.TokenEndpointAuthMethod {
+	case "":
+	case repository.AuthMethodNone:
+		if input.Type == "confidential" {
+			return fmt.Errorf("%w: auth method none requires public client", ErrBadInput)
+		}
+	case repository.AuthMethodClientSecretBasic, repository.AuthMethodClientSecretPost,
+		repository.AuthMethodClientSecretJWT, repository.AuthMethodPrivateKeyJWT,
+		repository.AuthMethodTLSClientAuth, repository.AuthMethodSelfSignedTLS:
+		if input.Type != "confidential" {
+			return fmt.Errorf("%w: auth method %s requires confidential client", ErrBadInput, input.TokenEndpointAuthMethod)
+		}
+	default:
+		return fmt.Errorf("%w: invalid token_endpoint_auth_method", ErrBadInput)
+	}
+
+	if input.JWKS != "" && input.JWKSURI != "" {
+		return fmt.Errorf("%w: jwks and jwks_uri are mutually exclusive", ErrBadInput)
+	}
+	if input.JWKS != "" {
+		set, err := jwtx.ParseJWKS([]byte(input.JWKS))
+		if err != nil || len(set.Keys) == 0 {
+			return fmt.Errorf("%w: invalid jwks", ErrBadInput)
+		}
+		for i := range set.Keys {
+			if _, err := set.Keys[i].PublicKey(); err != nil {
+				return fmt.Errorf("%w: invalid jwks: %v", ErrBadInput, err)
+			}
+		}
+	}
+	if input.JWKSURI != "" && !strings.HasPrefix(strings.ToLower(input.JWKSURI), "https://") {
+		return fmt.Errorf("%w: jwks_uri must be https", ErrBadInput)
+	}
+	if input.TokenEndpointAuthMethod == repository.AuthMethodPrivateKeyJWT && input.JWKS == "" && input.JWKSURI == "" {
+		return fmt.Errorf("%w: private_key_jwt requires jwks or jwks_uri", ErrBadInput)
+	}
+	if input.IDTokenSignedResponseAlg != "" && !jwtx.IsSigningAlgSupported(input.IDTokenSignedResponseAlg) {
+		return fmt.Errorf("%w: unsupported id_token_signed_response_alg", ErrBadInput)
+	}
+	if input.RequestObjectSigningAlg != "" && !jwtx.IsRequestObjectAlgSupported(input.RequestObjectSigningAlg) {
+		return fmt.Errorf("%w: unsupported request_object_signing_alg", ErrBadInput)
+	}
+	if input.RequireSignedRequestObject && input.JWKS == "" && input.JWKSURI == "" {
+		return fmt.Errorf("%w: require_signed_request_object requires jwks or jwks_uri", ErrBadInput)
+	}
+	if input.AuthorizationSignedResponseAlg != "" && !jwtx.IsSigningAlgSupported(input.AuthorizationSignedResponseAlg) {
+		return fmt.Errorf("%w: unsupported authorization_signed_response_alg", ErrBadInput)
+	}
+	if err := validateResponseEncryption("id_token", input.IDTokenEncryptedResponseAlg, input.IDTokenEncryptedResponseEnc, input); err != nil {
+		return err
+	}
+	if input.UserinfoSignedResponseAlg != "" && !jwtx.IsSigningAlgSupported(input.UserinfoSignedResponseAlg) {
+		return fmt.Errorf("%w: unsupported userinfo_signed_response_alg", ErrBadInput)
+	}
+	if err := validateResponseEncryption("userinfo", input.UserinfoEncryptedResponseAlg, input.UserinfoEncryptedResponseEnc, input); err != nil {
+		return err
+	}
+	if input.TokenEndpointAuthMethod == repository.AuthMethodTLSClientAuth && strings.TrimSpace(input.TLSClientAuthSubjectDN) == "" {
+		return fmt.Errorf("%w: tls_client_auth requires tls_client_auth_subject_dn", ErrBadInput)
+	}
+	if input.TokenEndpointAuthMethod == repository.AuthMethodSelfSignedTLS {
+		if b, err := base64.RawURLEncoding.DecodeString(input.TLSClientCertThumbprint); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("%w: self_signed_tls_client_auth requires a SHA-256 certificate thumbprint", ErrBadInput)
+		}
+	}
+	return nil
+}
+
+// validateResponseEncryption valida <prefix>_encrypted_response_alg/enc: enc exige
+// alg, y cifrar exige una clave pública del client (jwks o jwks_uri).
+func validateResponseEncryption(prefix, alg, enc string, input ClientInput) error {
+	if alg == "" {
+		if enc != "" {
+			return fmt.Errorf("%w: %s_encrypted_response_enc requires %s_encrypted_response_alg", ErrBadInput, prefix, prefix)
+		}
+		return nil
+	}
+	if !jwtx.IsResponseEncryptionSupported(alg, enc) {
+		return fmt.Errorf("%w: unsupported %s_encrypted_response_alg/enc", ErrBadInput, prefix)
+	}
+	if input.JWKS == "" && input.JWKSURI == "" {
+		return fmt.Errorf("%w: %s_encrypted_response_alg requires jwks or jwks_uri", ErrBadInput, prefix)
+	}
+	return nil
+}
+
 // ─── Helpers ───
 
 func isValidSlug(s string) bool {
@@ -35,6 +35,76 @@ type Client struct {
 	IDTokenTTL      int      // Segundos, default: 3600 (1 hora)
 	PostLogoutURIs  []string // URIs válidas para post-logout redirect
 	Description     string   // Descripción del cliente
+
+	// TokenExchange define qué puede solicitar el client vía Token Exchange (RFC 8693).
+	// nil = el client no puede intercambiar tokens.
+	TokenExchange *TokenExchangePolicy
+
+	// Autenticación del client en el token endpoint (RFC 7591 §2, RFC 7523).
+	TokenEndpointAuthMethod string // "" = según Type (none | client_secret_basic)
+	JWKS                    string // JWKS inline (JSON) para private_key_jwt
+	JWKSURI                 string // URL del JWKS del client (alternativa a JWKS)
+
+	// Mutual-TLS (RFC 8705 §2): identidad esperada del certificado del client.
+	TLSClientAuthSubjectDN  string // tls_client_auth: subject DN registrado (RFC 4514)
+	TLSClientCertThumbprint string // self_signed_tls_client_auth: SHA-256 del cert (base64url, x5t#S256)
+
+	// RequirePAR obliga a usar Pushed Authorization Requests (RFC 9126) en /authorize.
+	RequirePAR bool
+
+	// IDTokenSignedResponseAlg fuerza el algoritmo de firma del ID Token
+	// (OIDC Registration §2). "" = el algoritmo del tenant.
+	IDTokenSignedResponseAlg string
+
+	// FirstParty marca un client propio del tenant: con
+	// ConsentPolicy.AllowSkipConsentForFirstParty no pasa por la pantalla de consentimiento.
+	FirstParty bool
+
+	// Logout de RPs (OIDC Back-Channel / Front-Channel Logout 1.0).
+	BackchannelLogoutURI              string // recibe el logout_token (POST) al terminar la sesión
+	BackchannelLogoutSessionRequired  bool   // el RP necesita el claim sid en el logout_token
+	FrontchannelLogoutURI             string // se carga en un iframe desde la página de logout
+	FrontchannelLogoutSessionRequired bool   // agregar iss y sid como query params
+
+	// Subject identifiers (OIDC Core §8). "" o "public": sub = ID del usuario;
+	// "pairwise": sub distinto por sector, no correlacionable entre RPs.
+	SubjectType         string
+	SectorIdentifierURI string // https; su host es el sector (si no, el host de las redirect_uris)
+
+	// JWT-secured authorization requests (RFC 9101) y respuestas JARM
+	RequestObjectSigningAlg        string // alg exigido al request object; "" = cualquier alg asimétrico
+	RequireSignedRequestObject     bool   // /authorize solo acepta los parámetros dentro de un request object
+	AuthorizationSignedResponseAlg string // alg de las respuestas JARM; "" = el del tenant
+
+	// Respuestas cifradas (JWE) y userinfo firmado (OIDC Registration §2)
+	IDTokenEncryptedResponseAlg  string // alg de cifrado del ID Token (RSA-OAEP-256, ECDH-ES...); "" = sin cifrar
+	IDTokenEncryptedResponseEnc  string // enc del ID Token; "" = A128CBC-HS256
+	UserinfoSignedResponseAlg    string // userinfo como JWT firmado (application/jwt); "" = JSON
+	UserinfoEncryptedResponseAlg string // alg de cifrado de userinfo; "" = sin cifrar
+	UserinfoEncryptedResponseEnc string // enc de userinfo; "" = A128CBC-HS256
+
+	// RegistrationAccessTokenHash es el SHA-256 (base64url) del registration_access_token
+	// de un client registrado dinámicamente (RFC 7592). Vacío = no gestionable vía /oauth2/register.
+	RegistrationAccessTokenHash string
+}
+
+// Métodos de autenticación en el token endpoint.
+const (
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodClientSecretJWT   = "client_secret_jwt"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+	AuthMethodTLSClientAuth     = "tls_client_auth"
+	AuthMethodSelfSignedTLS     = "self_signed_tls_client_auth"
+)
+
+// TokenExchangePolicy restringe el grant token-exchange de un client.
+type TokenExchangePolicy struct {
+	AllowedAudiences   []string // Audiencias que puede pedir ("*" = cualquiera)
+	AllowedScopes      []string // Scopes que puede pedir (vacío = los del subject_token)
+	AllowImpersonation bool     // Sin actor_token: el token se emite a nombre del subject
+	AllowDelegation    bool     // Con actor_token: el token lleva claim "act"
 }
 
 // ClientVersion representa una versión de configuración de un client.
@@ -71,6 +141,37 @@ type ClientInput struct {
 	IDTokenTTL      int
 	PostLogoutURIs  []string
 	Description     string
+	TokenExchange   *TokenExchangePolicy
+
+	TokenEndpointAuthMethod string
+	JWKS                    string
+	JWKSURI                 string
+	TLSClientAuthSubjectDN  string
+	TLSClientCertThumbprint string
+	RequirePAR              bool
+
+	IDTokenSignedResponseAlg string
+	FirstParty               bool
+
+	BackchannelLogoutURI              string
+	BackchannelLogoutSessionRequired  bool
+	FrontchannelLogoutURI             string
+	FrontchannelLogoutSessionRequired bool
+
+	SubjectType         string
+	SectorIdentifierURI string
+
+	RequestObjectSigningAlg        string
+	RequireSignedRequestObject     bool
+	AuthorizationSignedResponseAlg string
+
+	IDTokenEncryptedResponseAlg  string
+	IDTokenEncryptedResponseEnc  string
+	UserinfoSignedResponseAlg    string
+	UserinfoEncryptedResponseAlg string
+	UserinfoEncryptedResponseEnc string
+
+	RegistrationAccessTokenHash string // "" en Update = conservar el actual
 }
 
 // ClientRepository define operaciones sobre OIDC clients.
@@ -58,6 +58,8 @@ const (
 	MutationClientDelete   MutationType = "client.delete"
 	MutationScopeCreate    MutationType = "scope.create"
 	MutationScopeDelete    MutationType = "scope.delete"
+	MutationResourceUpsert MutationType = "resource.upsert"
+	MutationResourceDelete MutationType = "resource.delete"
 	MutationKeyRotate      MutationType = "key.rotate"
 	MutationSettingsUpdate MutationType = "settings.update"
 )
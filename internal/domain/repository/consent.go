@@ -7,14 +7,17 @@ import (
 
 // Consent representa el consentimiento de un usuario a un client.
 type Consent struct {
-	ID        string
-	UserID    string
-	ClientID  string
-	TenantID  string
-	Scopes    []string
-	GrantedAt time.Time
-	UpdatedAt time.Time
-	RevokedAt *time.Time
+	ID           string
+	UserID       string
+	ClientID     string
+	TenantID     string
+	Scopes       []string
+	DeniedScopes []string // scopes rechazados explícitamente (consent per_scope)
+	GrantedAt    time.Time
+	UpdatedAt    time.Time
+	RevokedAt    *time.Time
+
+	AuthorizationDetails []map[string]any // authorization_details aprobados (RFC 9396)
 }
 
 // ConsentRepository define operaciones sobre user consents.
@@ -22,6 +25,11 @@ type ConsentRepository interface {
 	// Upsert crea o actualiza un consent, reemplazando los scopes otorgados.
 	Upsert(ctx context.Context, tenantID, userID, clientID string, scopes []string) (*Consent, error)
 
+	// UpsertDecision registra una decisión de consentimiento por scope: reemplaza
+	// los scopes otorgados y denegados, los authorization_details aprobados y
+	// reinicia granted_at.
+	UpsertDecision(ctx context.Context, tenantID, userID, clientID string, granted, denied []string, details []map[string]any) (*Consent, error)
+
 	// Get obtiene el consent de un usuario para un client específico.
 	// Retorna ErrNotFound si no existe.
 	Get(ctx context.Context, tenantID, userID, clientID string) (*Consent, error)
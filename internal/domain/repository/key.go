@@ -7,11 +7,11 @@ import (
 	"time"
 )
 
-// SigningKey representa una clave de firma (EdDSA, ECDSA, RSA).
+// SigningKey representa una clave de firma (EdDSA, ECDSA, RSA) o de cifrado (RSA-OAEP-256).
 type SigningKey struct {
 	ID         string // KID
 	TenantID   string // "" para global
-	Algorithm  string // "EdDSA", "ES256", "RS256"
+	Algorithm  string // "EdDSA", "ES256", "RS256", "RSA-OAEP-256" (cifrado JWE)
 	PrivateKey any    // ed25519.PrivateKey, *ecdsa.PrivateKey, etc.
 	PublicKey  any    // ed25519.PublicKey, *ecdsa.PublicKey, etc.
 	Status     KeyStatus
@@ -56,6 +56,10 @@ type KeyRepository interface {
 	// tenantID vacío = clave global.
 	GetActive(ctx context.Context, tenantID string) (*SigningKey, error)
 
+	// GetActiveByAlgorithm obtiene la clave activa de un algoritmo ("EdDSA", "RS256", "ES256").
+	// Para "EdDSA" equivale a GetActive. ErrNotFound si el keyring no tiene clave de ese algoritmo.
+	GetActiveByAlgorithm(ctx context.Context, tenantID, algorithm string) (*SigningKey, error)
+
 	// GetByKID busca una clave por su Key ID.
 	GetByKID(ctx context.Context, kid string) (*SigningKey, error)
 
@@ -76,6 +80,7 @@ type KeyRepository interface {
 	Generate(ctx context.Context, tenantID, algorithm string) (*SigningKey, error)
 
 	// Rotate rota las claves: genera nueva, retira la anterior.
+	// Rota cada algoritmo que tenga clave activa en el keyring; devuelve la nueva EdDSA.
 	// gracePeriod indica cuánto tiempo mantener la clave anterior para verificación.
 	Rotate(ctx context.Context, tenantID string, gracePeriod time.Duration) (*SigningKey, error)
 
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttempt representa los intentos fallidos de login acumulados para una key
+// de bloqueo ("user:<id>" o "identifier:<email>") y su bloqueo vigente.
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time // nil si no está bloqueada
+}
+
+// IsLocked indica si la key sigue bloqueada en el instante now.
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a != nil && a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// LoginAttemptRepository define operaciones sobre el conteo de intentos fallidos
+// de login (account lockout de SecurityPolicy).
+type LoginAttemptRepository interface {
+	// Get obtiene los intentos de una key.
+	// Retorna ErrNotFound si no hay intentos registrados.
+	Get(ctx context.Context, key string) (*LoginAttempt, error)
+
+	// RegisterFailure suma un intento fallido a la key y retorna el estado resultante.
+	// Si el último fallo es anterior a window, el conteo reinicia en 1.
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (*LoginAttempt, error)
+
+	// Lock bloquea la key hasta until.
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// Reset elimina los intentos y el bloqueo de la key (login exitoso o desbloqueo).
+	Reset(ctx context.Context, key string) error
+
+	// ListLocked lista las keys bloqueadas vigentes, ordenadas por vencimiento.
+	ListLocked(ctx context.Context) ([]LoginAttempt, error)
+}
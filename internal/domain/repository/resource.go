@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// APIResource representa una API protegida registrada en el tenant (RFC 8707).
+// Su Identifier es el valor del parámetro resource y el aud de los access tokens
+// emitidos para ella.
+type APIResource struct {
+	ID          string
+	TenantID    string
+	Identifier  string // URI absoluta sin fragmento (ej: https://api.example.com)
+	Name        string // Nombre amigable para admin/consent
+	Description string
+	Scopes      []string // Scopes propios de la API (sin calificar)
+
+	AccessTokenTTL int    // Segundos; 0 = TTL del client/tenant
+	SigningAlg     string // Alg de firma de los access tokens; vacío = default del tenant
+
+	AccessTokenProfile string // AccessTokenProfile* de sus access tokens; vacío = el del tenant
+
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// APIResourceInput contiene los datos para crear/actualizar un API resource.
+type APIResourceInput struct {
+	Identifier     string
+	Name           string
+	Description    string
+	Scopes         []string
+	AccessTokenTTL int
+	SigningAlg     string
+
+	AccessTokenProfile string
+}
+
+// QualifiedScope devuelve el scope calificado por la API: "<identifier>/<scope>".
+func (r *APIResource) QualifiedScope(scope string) string {
+	return strings.TrimSuffix(r.Identifier, "/") + "/" + scope
+}
+
+// SplitQualifiedScope separa un scope calificado de esta API.
+// Retorna el scope sin calificar y true si pertenece a la API.
+func (r *APIResource) SplitQualifiedScope(scope string) (string, bool) {
+	prefix := strings.TrimSuffix(r.Identifier, "/") + "/"
+	if !strings.HasPrefix(scope, prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(scope, prefix)
+	return name, name != ""
+}
+
+// HasScope indica si la API define el scope (sin calificar).
+func (r *APIResource) HasScope(name string) bool {
+	for _, s := range r.Scopes {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// APIResourceRepository define operaciones sobre el registro de APIs del tenant.
+// Los API resources viven en el Control Plane (FileSystem) y se replican por Raft.
+type APIResourceRepository interface {
+	// Get busca un API resource por su identifier.
+	// Retorna ErrNotFound si no existe.
+	Get(ctx context.Context, tenantID, identifier string) (*APIResource, error)
+
+	// List lista todos los API resources de un tenant.
+	List(ctx context.Context, tenantID string) ([]APIResource, error)
+
+	// Upsert crea el API resource si no existe, o lo actualiza si ya existe.
+	// El identifier no cambia: es el aud de los tokens ya emitidos.
+	Upsert(ctx context.Context, tenantID string, input APIResourceInput) (*APIResource, error)
+
+	// Delete elimina un API resource.
+	// Retorna ErrNotFound si no existe.
+	Delete(ctx context.Context, tenantID, identifier string) error
+}
@@ -25,6 +25,7 @@ type TenantSettings struct {
 	FaviconURL                  string                `json:"faviconUrl" yaml:"faviconUrl"`
 	SessionLifetimeSeconds      int                   `json:"sessionLifetimeSeconds" yaml:"sessionLifetimeSeconds"`
 	RefreshTokenLifetimeSeconds int                   `json:"refreshTokenLifetimeSeconds" yaml:"refreshTokenLifetimeSeconds"`
+	RefreshReuseGraceSeconds    int                   `json:"refreshReuseGraceSeconds,omitempty" yaml:"refreshReuseGraceSeconds,omitempty"` // 0 = todo reuso revoca la familia
 	MFAEnabled                  bool                  `json:"mfaEnabled" yaml:"mfaEnabled"`
 	SocialLoginEnabled          bool                  `json:"social_login_enabled" yaml:"social_login_enabled"`
 	SMTP                        *SMTPSettings         `json:"smtp,omitempty" yaml:"smtp,omitempty"`
@@ -34,12 +35,79 @@ type TenantSettings struct {
 	UserFields                  []UserFieldDefinition `json:"userFields,omitempty" yaml:"userFields,omitempty"`
 	Mailing                     *MailingSettings      `json:"mailing,omitempty" yaml:"mailing,omitempty"`
 	// IssuerMode configura cómo se construye el issuer/JWKS por tenant.
-	IssuerMode      string                 `json:"issuerMode,omitempty" yaml:"issuerMode,omitempty"`
-	IssuerOverride  string                 `json:"issuerOverride,omitempty" yaml:"issuerOverride,omitempty"`
-	SocialProviders *SocialConfig          `json:"socialProviders,omitempty" yaml:"socialProviders,omitempty"`
-	ConsentPolicy   *ConsentPolicySettings `json:"consentPolicy,omitempty" yaml:"consentPolicy,omitempty"`
+	IssuerMode     string `json:"issuerMode,omitempty" yaml:"issuerMode,omitempty"`
+	IssuerOverride string `json:"issuerOverride,omitempty" yaml:"issuerOverride,omitempty"`
+	// SigningAlgorithm es el algoritmo con que se firman los tokens del tenant: EdDSA (default), RS256 o ES256.
+	SigningAlgorithm string                 `json:"signingAlgorithm,omitempty" yaml:"signingAlgorithm,omitempty"`
+	SocialProviders  *SocialConfig          `json:"socialProviders,omitempty" yaml:"socialProviders,omitempty"`
+	ConsentPolicy    *ConsentPolicySettings `json:"consentPolicy,omitempty" yaml:"consentPolicy,omitempty"`
+	// ClientRegistration controla el Dynamic Client Registration (RFC 7591). nil = deshabilitado.
+	ClientRegistration *ClientRegistrationSettings `json:"clientRegistration,omitempty" yaml:"clientRegistration,omitempty"`
+	// AccessTokenProfile es el formato de los access tokens JWT: "" (propio) o "rfc9068".
+	// Un API resource puede fijar el suyo.
+	AccessTokenProfile string `json:"accessTokenProfile,omitempty" yaml:"accessTokenProfile,omitempty"`
+	// AuthorizationDetailsTypes son los tipos de authorization_details aceptados
+	// (Rich Authorization Requests, RFC 9396). Vacío = RAR no disponible.
+	AuthorizationDetailsTypes []AuthorizationDetailsType `json:"authorizationDetailsTypes,omitempty" yaml:"authorizationDetailsTypes,omitempty"`
+	// Passwordless habilita el login sólo con email (magic link / código OTP). nil = deshabilitado.
+	Passwordless *PasswordlessSettings `json:"passwordless,omitempty" yaml:"passwordless,omitempty"`
+	// Phone habilita la verificación de teléfono (y con MFAEnabled el factor SMS/voz). nil = deshabilitado.
+	Phone *PhoneSettings `json:"phone,omitempty" yaml:"phone,omitempty"`
 }
 
+// PasswordlessSettings configura el login sin password por email. Cada client
+// además tiene que habilitarlo en sus providers ("email_link" / "email_otp").
+type PasswordlessSettings struct {
+	MagicLinkEnabled bool `json:"magicLinkEnabled" yaml:"magicLinkEnabled"`
+	EmailOTPEnabled  bool `json:"emailOtpEnabled" yaml:"emailOtpEnabled"`
+	// AllowSignup crea el usuario (just-in-time) si el email no existe.
+	AllowSignup bool `json:"allowSignup,omitempty" yaml:"allowSignup,omitempty"`
+	TTLSeconds  int  `json:"ttlSeconds,omitempty" yaml:"ttlSeconds,omitempty"`   // vigencia del link/código; 0 = 10 minutos
+	MaxAttempts int  `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"` // códigos errados por intento de login; 0 = 5
+}
+
+// PhoneSettings configura la verificación de teléfono y el factor OTP por SMS/voz.
+// Requiere un provider de SMS configurado (SMS_PROVIDER).
+type PhoneSettings struct {
+	// MFAEnabled hace que un teléfono verificado cuente como segundo factor.
+	MFAEnabled   bool `json:"mfaEnabled" yaml:"mfaEnabled"`
+	VoiceEnabled bool `json:"voiceEnabled,omitempty" yaml:"voiceEnabled,omitempty"` // permite pedir el código por llamada
+	// MaxSendsPerHour limita los envíos a un mismo número; 0 = 5.
+	MaxSendsPerHour int `json:"maxSendsPerHour,omitempty" yaml:"maxSendsPerHour,omitempty"`
+	// Templates son los textos de los mensajes por idioma: map[lang]map[templateID]texto.
+	// IDs: "sms_code" y "voice_code" (variables {{.Code}} y {{.Minutes}}); lo que
+	// falte usa el texto por defecto del idioma.
+	// Ejemplo: Templates["en"]["sms_code"] = "Your code is {{.Code}}"
+	Templates map[string]map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
+}
+
+// AuthorizationDetailsType registra un tipo de authorization_details del tenant.
+// Cada objeto con ese "type" debe validar contra Schema (JSON Schema, ver claims.CheckSchema).
+type AuthorizationDetailsType struct {
+	Type        string         `json:"type" yaml:"type"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// FindAuthorizationDetailsType retorna el tipo registrado con ese nombre (nil si no existe).
+func (s *TenantSettings) FindAuthorizationDetailsType(name string) *AuthorizationDetailsType {
+	if s == nil {
+		return nil
+	}
+	for i := range s.AuthorizationDetailsTypes {
+		if s.AuthorizationDetailsTypes[i].Type == name {
+			return &s.AuthorizationDetailsTypes[i]
+		}
+	}
+	return nil
+}
+
+// Perfiles de access token JWT.
+const (
+	AccessTokenProfileDefault = ""        // claims propios (scp, custom), typ JWT
+	AccessTokenProfileRFC9068 = "rfc9068" // JWT Profile for OAuth 2.0 Access Tokens, typ at+jwt
+)
+
 // SMTPSettings configuración de email.
 type SMTPSettings struct {
 	Host        string `json:"host" yaml:"host"`
@@ -143,6 +211,25 @@ type ConsentPolicySettings struct {
 	AllowSkipConsentForFirstParty bool   `json:"allow_skip_consent_for_first_party" yaml:"allowSkipConsentForFirstParty"`
 }
 
+// Modos de consentimiento (ConsentPolicySettings.ConsentMode).
+const (
+	ConsentModeSingle   = "single"    // se acepta o rechaza el conjunto de scopes
+	ConsentModePerScope = "per_scope" // el usuario elige scope por scope
+)
+
+// Modos de Dynamic Client Registration.
+const (
+	ClientRegistrationDisabled           = "disabled"
+	ClientRegistrationOpen               = "open"                 // cualquiera puede registrar clients
+	ClientRegistrationInitialAccessToken = "initial_access_token" // requiere un initial access token (RFC 7591 §3)
+)
+
+// ClientRegistrationSettings configuración del endpoint /oauth2/register.
+type ClientRegistrationSettings struct {
+	Mode                     string   `json:"mode" yaml:"mode"`                                                             // disabled | open | initial_access_token
+	InitialAccessTokenHashes []string `json:"initialAccessTokenHashes,omitempty" yaml:"initialAccessTokenHashes,omitempty"` // SHA-256 (base64url) de los tokens válidos
+}
+
 // SocialConfig: habilitación/config de IdPs sociales.
 type SocialConfig struct {
 	// Google OAuth
@@ -17,15 +17,28 @@ type RefreshToken struct {
 	ExpiresAt   time.Time
 	RotatedFrom *string
 	RevokedAt   *time.Time
+	DPoPJKT     string // thumbprint de la clave DPoP a la que está ligado ("" = bearer)
+	SessionID   string // session_id_hash de la sesión del navegador ("" = sin sesión)
+
+	Resources []string // API resources otorgados (RFC 8707); vacío = aud del client
+
+	AuthorizationDetails []map[string]any // authorization_details otorgados (RFC 9396)
 }
 
 // CreateRefreshTokenInput contiene los datos para crear un refresh token.
 type CreateRefreshTokenInput struct {
-	TenantID   string
-	ClientID   string
-	UserID     string
-	TokenHash  string
-	TTLSeconds int
+	TenantID    string
+	ClientID    string
+	UserID      string
+	TokenHash   string
+	TTLSeconds  int
+	DPoPJKT     string // opcional: liga el token a una clave DPoP (RFC 9449)
+	RotatedFrom string // opcional: ID del token que reemplaza (rotación)
+	SessionID   string // opcional: session_id_hash de la sesión del navegador
+
+	Resources []string // opcional: API resources otorgados (RFC 8707)
+
+	AuthorizationDetails []map[string]any // opcional: authorization_details otorgados (RFC 9396)
 }
 
 // ListTokensFilter contiene los filtros para listar tokens.
@@ -78,6 +91,15 @@ type TokenRepository interface {
 	// RevokeAllByClient revoca todos los tokens de un client (todos los usuarios).
 	RevokeAllByClient(ctx context.Context, clientID string) error
 
+	// RevokeFamily revoca la familia de rotación del token: sube por rotated_from
+	// hasta la raíz y revoca todos sus descendientes.
+	// Retorna el número de tokens que seguían activos.
+	RevokeFamily(ctx context.Context, tokenID string) (int, error)
+
+	// HasSuccessor indica si el token fue reemplazado por rotación
+	// (otro token tiene rotated_from = tokenID).
+	HasSuccessor(ctx context.Context, tokenID string) (bool, error)
+
 	// ─── Admin Operations ───
 
 	// List lista tokens con filtros y paginación.
@@ -24,6 +24,10 @@ type User struct {
 	DisabledUntil  *time.Time
 	DisabledReason *string
 	SourceClientID *string
+
+	// PhoneNumber en formato E.164 ("+5491122334455"); vacío = sin teléfono.
+	PhoneNumber         string
+	PhoneNumberVerified bool
 }
 
 // Identity representa una identidad de autenticación (password, social, etc).
@@ -103,6 +107,8 @@ type UserRepository interface {
 
 	// CheckPassword verifica si el password coincide con el hash.
 	// Este método no accede a la BD, solo hace la comparación.
+	// Acepta argon2id y los formatos importados (bcrypt, PBKDF2, scrypt, SHA-crypt);
+	// ver password.Verify y password.NeedsRehash.
 	CheckPassword(hash *string, password string) bool
 
 	// SetEmailVerified marca el email de un usuario como verificado o no.
@@ -110,4 +116,8 @@ type UserRepository interface {
 
 	// UpdatePasswordHash actualiza el hash del password en la identity "password".
 	UpdatePasswordHash(ctx context.Context, userID, newHash string) error
+
+	// SetPhoneNumber guarda el teléfono (E.164) del usuario y si está verificado.
+	// phone vacío borra el teléfono.
+	SetPhoneNumber(ctx context.Context, userID, phone string, verified bool) error
 }
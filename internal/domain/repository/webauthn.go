@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// WebAuthnCredential representa una credencial FIDO2/WebAuthn (security key o passkey)
+// registrada por un usuario.
+type WebAuthnCredential struct {
+	ID                string
+	UserID            string
+	CredentialID      string // base64url del credential ID del autenticador
+	PublicKey         []byte // COSE_Key
+	SignCount         uint32
+	AAGUID            string // modelo del autenticador (UUID; ceros si no se informa)
+	Transports        []string
+	AttestationFormat string
+	Name              string // nombre visible elegido por el usuario
+	BackupEligible    bool   // passkey sincronizable
+	BackupState       bool   // sincronizada actualmente
+	CreatedAt         time.Time
+	LastUsedAt        *time.Time
+}
+
+// WebAuthnRepository define operaciones sobre credenciales WebAuthn.
+type WebAuthnRepository interface {
+	// Create registra una credencial. Completa ID y CreatedAt.
+	Create(ctx context.Context, cred *WebAuthnCredential) error
+
+	// GetByCredentialID obtiene una credencial por su credential ID (base64url).
+	// Retorna ErrNotFound si no existe.
+	GetByCredentialID(ctx context.Context, credentialID string) (*WebAuthnCredential, error)
+
+	// ListByUser lista las credenciales de un usuario (más recientes primero).
+	ListByUser(ctx context.Context, userID string) ([]WebAuthnCredential, error)
+
+	// UpdateUsage registra una autenticación: contador de firmas, backup state y last_used_at.
+	UpdateUsage(ctx context.Context, id string, signCount uint32, backupState bool) error
+
+	// Rename cambia el nombre visible. Retorna ErrNotFound si no es del usuario.
+	Rename(ctx context.Context, userID, id, name string) error
+
+	// Delete elimina una credencial. Retorna ErrNotFound si no es del usuario.
+	Delete(ctx context.Context, userID, id string) error
+}
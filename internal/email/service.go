@@ -44,6 +44,16 @@ type Service interface {
 	// SendNotificationEmail envía una notificación genérica.
 	SendNotificationEmail(ctx context.Context, req SendNotificationRequest) error
 
+	// SendBlockedEmail avisa al usuario que su cuenta fue bloqueada (template "user_blocked").
+	// Si req.UnlockToken no está vacío, incluye el link de desbloqueo self-service.
+	SendBlockedEmail(ctx context.Context, req SendBlockedRequest) error
+
+	// SendUnblockedEmail avisa al usuario que su cuenta fue desbloqueada (template "user_unblocked").
+	SendUnblockedEmail(ctx context.Context, req SendUnblockedRequest) error
+
+	// SendPasswordlessEmail envía un magic link o código de login (template "passwordless_login").
+	SendPasswordlessEmail(ctx context.Context, req SendPasswordlessRequest) error
+
 	// TestSMTP prueba la configuración SMTP de un tenant.
 	// Si override no es nil, usa esa configuración en lugar de la del tenant.
 	TestSMTP(ctx context.Context, tenantSlugOrID, recipientEmail string, override *SMTPConfig) error
@@ -347,6 +357,178 @@ func (s *service) SendNotificationEmail(ctx context.Context, req SendNotificatio
 	return nil
 }
 
+// ─── SendBlockedEmail / SendUnblockedEmail ───
+
+func (s *service) SendBlockedEmail(ctx context.Context, req SendBlockedRequest) error {
+	log := logger.From(ctx).With(
+		logger.String("op", "SendBlockedEmail"),
+		logger.String("tenant", req.TenantSlugOrID),
+		logger.String("email", req.Email),
+	)
+
+	if req.TenantSlugOrID == "" || req.Email == "" {
+		return ErrInvalidInput
+	}
+
+	tenant, err := s.resolveTenant(ctx, req.TenantSlugOrID)
+	if err != nil {
+		log.Error("failed to resolve tenant", logger.Err(err))
+		return ErrTenantNotFound
+	}
+
+	vars := BlockedVars{
+		UserEmail: req.Email,
+		Tenant:    tenant.Name,
+		Reason:    req.Reason,
+	}
+	if req.Until != nil {
+		vars.Until = req.Until.UTC().Format("2006-01-02 15:04 MST")
+	}
+	if req.UnlockToken != "" {
+		vars.Link = s.buildUnlockLink(req.UnlockToken, req.TenantSlugOrID)
+	}
+
+	// Fallback mínimo si el tenant no tiene template
+	fallbackHTML := fmt.Sprintf(`<p>Hola %s,</p><p>Tu cuenta fue bloqueada: %s</p>`, vars.UserEmail, vars.Reason)
+	fallbackText := fmt.Sprintf("Hola %s, tu cuenta fue bloqueada: %s", vars.UserEmail, vars.Reason)
+	if vars.Link != "" {
+		fallbackHTML += fmt.Sprintf(`<p>Desbloqueala: <a href="%s">%s</a></p>`, vars.Link, vars.Link)
+		fallbackText += ". Desbloqueala visitando: " + vars.Link
+	}
+
+	if err := s.sendTemplated(ctx, tenant, req.TenantSlugOrID, req.Email, "user_blocked", vars, "Cuenta bloqueada", fallbackHTML, fallbackText); err != nil {
+		log.Error("failed to send email", logger.Err(err))
+		return err
+	}
+
+	log.Info("blocked email sent")
+	return nil
+}
+
+func (s *service) SendUnblockedEmail(ctx context.Context, req SendUnblockedRequest) error {
+	log := logger.From(ctx).With(
+		logger.String("op", "SendUnblockedEmail"),
+		logger.String("tenant", req.TenantSlugOrID),
+		logger.String("email", req.Email),
+	)
+
+	if req.TenantSlugOrID == "" || req.Email == "" {
+		return ErrInvalidInput
+	}
+
+	tenant, err := s.resolveTenant(ctx, req.TenantSlugOrID)
+	if err != nil {
+		log.Error("failed to resolve tenant", logger.Err(err))
+		return ErrTenantNotFound
+	}
+
+	vars := UnblockedVars{
+		UserEmail: req.Email,
+		Tenant:    tenant.Name,
+	}
+	fallbackHTML := fmt.Sprintf(`<p>Hola %s,</p><p>Tu cuenta fue desbloqueada.</p>`, vars.UserEmail)
+	fallbackText := fmt.Sprintf("Hola %s, tu cuenta fue desbloqueada.", vars.UserEmail)
+
+	if err := s.sendTemplated(ctx, tenant, req.TenantSlugOrID, req.Email, "user_unblocked", vars, "Cuenta desbloqueada", fallbackHTML, fallbackText); err != nil {
+		log.Error("failed to send email", logger.Err(err))
+		return err
+	}
+
+	log.Info("unblocked email sent")
+	return nil
+}
+
+// ─── SendPasswordlessEmail ───
+
+func (s *service) SendPasswordlessEmail(ctx context.Context, req SendPasswordlessRequest) error {
+	log := logger.From(ctx).With(
+		logger.String("op", "SendPasswordlessEmail"),
+		logger.String("tenant", req.TenantSlugOrID),
+		logger.String("email", req.Email),
+	)
+
+	if req.TenantSlugOrID == "" || req.Email == "" || (req.Token == "") == (req.Code == "") {
+		return ErrInvalidInput
+	}
+
+	tenant, err := s.resolveTenant(ctx, req.TenantSlugOrID)
+	if err != nil {
+		log.Error("failed to resolve tenant", logger.Err(err))
+		return ErrTenantNotFound
+	}
+
+	vars := PasswordlessVars{
+		UserEmail: req.Email,
+		Tenant:    tenant.Name,
+		Code:      req.Code,
+		TTL:       formatDuration(req.TTL),
+	}
+	if req.Token != "" {
+		if req.LinkURL != "" {
+			vars.Link = addQueryParam(req.LinkURL, "token", req.Token)
+		} else {
+			vars.Link = s.buildPasswordlessLink(req.Token, req.TenantSlugOrID)
+		}
+	}
+
+	var fallbackHTML, fallbackText string
+	if vars.Link != "" {
+		fallbackHTML = fmt.Sprintf(`<p>Hola %s,</p><p>Ingresá a %s: <a href="%s">%s</a></p>`, vars.UserEmail, vars.Tenant, vars.Link, vars.Link)
+		fallbackText = fmt.Sprintf("Hola %s, ingresá a %s visitando: %s", vars.UserEmail, vars.Tenant, vars.Link)
+	} else {
+		fallbackHTML = fmt.Sprintf(`<p>Hola %s,</p><p>Tu código para ingresar a %s es <strong>%s</strong></p>`, vars.UserEmail, vars.Tenant, vars.Code)
+		fallbackText = fmt.Sprintf("Hola %s, tu código para ingresar a %s es %s", vars.UserEmail, vars.Tenant, vars.Code)
+	}
+	if vars.TTL != "" {
+		fallbackHTML += fmt.Sprintf(`<p>Vence en %s.</p>`, vars.TTL)
+		fallbackText += ". Vence en " + vars.TTL
+	}
+
+	if err := s.sendTemplated(ctx, tenant, req.TenantSlugOrID, req.Email, "passwordless_login", vars, "Tu acceso a "+tenant.Name, fallbackHTML, fallbackText); err != nil {
+		log.Error("failed to send email", logger.Err(err))
+		return err
+	}
+
+	log.Info("passwordless email sent")
+	return nil
+}
+
+// sendTemplated renderiza templateID del tenant con vars (o el fallback si el
+// tenant no lo tiene) y lo envía.
+func (s *service) sendTemplated(ctx context.Context, tenant *repository.Tenant, tenantSlugOrID, to, templateID string, vars any, subject, fallbackHTML, fallbackText string) error {
+	lang := tenant.Language
+	if lang == "" {
+		lang = "es"
+	}
+
+	htmlBody, textBody := fallbackHTML, fallbackText
+	if tpl := s.getTemplateForLang(tenant, templateID, lang); tpl != nil && tpl.Body != "" {
+		var err error
+		htmlBody, textBody, err = s.renderTemplateStrings(tpl.Body, "", vars)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrTemplateRender, err)
+		}
+		if tpl.Subject != "" {
+			subject = tpl.Subject
+		}
+	}
+
+	sender, err := s.senderProvider.GetSender(ctx, tenantSlugOrID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoSMTPConfig, err)
+	}
+	if err := sender.Send(to, subject, htmlBody, textBody); err != nil {
+		diag := DiagnoseSMTP(err)
+		logger.From(ctx).Error("smtp send failed",
+			logger.Err(err),
+			logger.String("diag_code", diag.Code),
+			logger.Bool("temporary", diag.Temporary),
+		)
+		return fmt.Errorf("%w: %v", ErrSendFailed, err)
+	}
+	return nil
+}
+
 // ─── TestSMTP ───
 
 func (s *service) TestSMTP(ctx context.Context, tenantSlugOrID, recipientEmail string, override *SMTPConfig) error {
@@ -457,6 +639,30 @@ func (s *service) buildResetLink(token, redirect, clientID, tenantID string) str
 	return u.String()
 }
 
+func (s *service) buildUnlockLink(token, tenantID string) string {
+	u, _ := url.Parse(s.baseURL)
+	u.Path = "/v2/auth/unlock"
+	q := u.Query()
+	q.Set("token", token)
+	if tenantID != "" {
+		q.Set("tenant_id", tenantID)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (s *service) buildPasswordlessLink(token, tenantID string) string {
+	u, _ := url.Parse(s.baseURL)
+	u.Path = "/v2/session/passwordless/link"
+	q := u.Query()
+	q.Set("token", token)
+	if tenantID != "" {
+		q.Set("tenant_id", tenantID)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 func (s *service) renderVerify(tenant *repository.Tenant, vars VerifyVars, lang string) (html, text string, err error) {
 	// Intentar usar template del tenant si existe
 	if tpl := s.getTemplateForLang(tenant, "verify_email", lang); tpl != nil && tpl.Body != "" {
@@ -36,6 +36,32 @@ type SendNotificationRequest struct {
 	Subject        string         // Subject del email (override del template)
 }
 
+// SendBlockedRequest contiene los datos para avisar el bloqueo de una cuenta.
+type SendBlockedRequest struct {
+	TenantSlugOrID string     // Puede ser UUID o slug del tenant
+	Email          string     // Email destino
+	Reason         string     // Motivo del bloqueo
+	Until          *time.Time // Fin del bloqueo (nil = indefinido)
+	UnlockToken    string     // Token de desbloqueo self-service (opcional)
+}
+
+// SendUnblockedRequest contiene los datos para avisar el desbloqueo de una cuenta.
+type SendUnblockedRequest struct {
+	TenantSlugOrID string // Puede ser UUID o slug del tenant
+	Email          string // Email destino
+}
+
+// SendPasswordlessRequest contiene los datos para enviar un login sin password
+// (magic link o código OTP; se envía el que no esté vacío).
+type SendPasswordlessRequest struct {
+	TenantSlugOrID string        // Puede ser UUID o slug del tenant
+	Email          string        // Email destino
+	Token          string        // Token del magic link
+	Code           string        // Código OTP de 6 dígitos
+	LinkURL        string        // Destino del link (redirect_uri del client); vacío = endpoint de sesión
+	TTL            time.Duration // TTL para mostrar en el email
+}
+
 // ─── Configuración SMTP ───
 
 // SMTPConfig contiene la configuración para conectarse a un servidor SMTP.
@@ -73,6 +99,7 @@ type BlockedVars struct {
 	Tenant    string
 	Reason    string
 	Until     string
+	Link      string // link de desbloqueo self-service (vacío si no aplica)
 }
 
 // UnblockedVars son las variables para el template de usuario desbloqueado.
@@ -80,3 +107,12 @@ type UnblockedVars struct {
 	UserEmail string
 	Tenant    string
 }
+
+// PasswordlessVars son las variables para el template de login sin password.
+type PasswordlessVars struct {
+	UserEmail string
+	Tenant    string
+	Link      string // vacío si se envía código
+	Code      string // vacío si se envía link
+	TTL       string
+}
@@ -293,6 +293,46 @@ func toClientInput(req dto.ClientRequest) controlplane.ClientInput {
 		IDTokenTTL:      req.IDTokenTTL,
 		PostLogoutURIs:  req.PostLogoutURIs,
 		Description:     req.Description,
+		TokenExchange:   toTokenExchangePolicy(req.TokenExchange),
+
+		TokenEndpointAuthMethod:  req.TokenEndpointAuthMethod,
+		JWKS:                     string(req.JWKS),
+		JWKSURI:                  req.JWKSURI,
+		TLSClientAuthSubjectDN:   req.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  req.TLSClientCertThumbprint,
+		RequirePAR:               req.RequirePAR,
+		IDTokenSignedResponseAlg: req.IDTokenSignedResponseAlg,
+		FirstParty:               req.FirstParty,
+
+		BackchannelLogoutURI:              req.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  req.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             req.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: req.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         req.SubjectType,
+		SectorIdentifierURI: req.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        req.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     req.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: req.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  req.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  req.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    req.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: req.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: req.UserinfoEncryptedResponseEnc,
+	}
+}
+
+func toTokenExchangePolicy(p *dto.TokenExchangePolicy) *repository.TokenExchangePolicy {
+	if p == nil {
+		return nil
+	}
+	return &repository.TokenExchangePolicy{
+		AllowedAudiences:   p.AllowedAudiences,
+		AllowedScopes:      p.AllowedScopes,
+		AllowImpersonation: p.AllowImpersonation,
+		AllowDelegation:    p.AllowDelegation,
 	}
 }
 
@@ -317,6 +357,44 @@ func toClientResponse(cl repository.Client) dto.ClientResponse {
 		PostLogoutURIs:  cl.PostLogoutURIs,
 		Description:     cl.Description,
 		// CreatedAt/UpdatedAt no existen en repository.Client, se omiten
+
+		TokenEndpointAuthMethod:  cl.TokenEndpointAuthMethod,
+		JWKSURI:                  cl.JWKSURI,
+		TLSClientAuthSubjectDN:   cl.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  cl.TLSClientCertThumbprint,
+		RequirePAR:               cl.RequirePAR,
+		IDTokenSignedResponseAlg: cl.IDTokenSignedResponseAlg,
+		FirstParty:               cl.FirstParty,
+
+		BackchannelLogoutURI:              cl.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  cl.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             cl.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: cl.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         cl.SubjectType,
+		SectorIdentifierURI: cl.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        cl.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     cl.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: cl.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  cl.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  cl.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    cl.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: cl.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: cl.UserinfoEncryptedResponseEnc,
+	}
+	if cl.JWKS != "" {
+		resp.JWKS = json.RawMessage(cl.JWKS)
+	}
+
+	if cl.TokenExchange != nil {
+		resp.TokenExchange = &dto.TokenExchangePolicy{
+			AllowedAudiences:   cl.TokenExchange.AllowedAudiences,
+			AllowedScopes:      cl.TokenExchange.AllowedScopes,
+			AllowImpersonation: cl.TokenExchange.AllowImpersonation,
+			AllowDelegation:    cl.TokenExchange.AllowDelegation,
+		}
 	}
 
 	// Si SecretEnc es plaintext (no empieza con "enc:"), es un secret recién generado
@@ -14,6 +14,7 @@ type Controllers struct {
 	Users     *UsersController
 	UsersCRUD *UsersCRUDController
 	Scopes    *ScopesController
+	Resources *ResourcesController
 	Claims    *ClaimsController
 	RBAC      *RBACController
 	Tenants   *TenantsController
@@ -38,6 +39,7 @@ func NewControllers(s svc.Services, deps ControllerDeps) *Controllers {
 		// UsersCRUD ahora recibe actionService y DAL para soportar acciones tenant-scoped
 		UsersCRUD: NewUsersCRUDControllerWithActions(s.UserCRUD, s.Users, deps.DAL),
 		Scopes:    NewScopesController(s.Scopes),
+		Resources: NewResourcesController(s.Resources),
 		Claims:    NewClaimsController(s.Claims),
 		RBAC:      NewRBACController(s.RBAC),
 		Tenants:   NewTenantsController(s.Tenants),
@@ -0,0 +1,196 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/admin"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	mw "github.com/dropDatabas3/hellojohn/internal/http/middlewares"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/admin"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+// ResourcesController maneja las rutas /v2/admin/tenants/{tenant_id}/resources
+type ResourcesController struct {
+	service svc.APIResourceService
+}
+
+// NewResourcesController crea un nuevo controller de API resources.
+func NewResourcesController(service svc.APIResourceService) *ResourcesController {
+	return &ResourcesController{service: service}
+}
+
+// ListResources maneja GET /v2/admin/tenants/{tenant_id}/resources
+func (c *ResourcesController) ListResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("ResourcesController.ListResources"))
+
+	tda := mw.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail(tenantRequired))
+		return
+	}
+
+	resources, err := c.service.List(ctx, tda.Slug())
+	if err != nil {
+		log.Error("list failed", logger.Err(err))
+		httperrors.WriteError(w, mapResourceError(err))
+		return
+	}
+
+	resp := make([]dto.APIResourceResponse, 0, len(resources))
+	for _, res := range resources {
+		resp = append(resp, toAPIResourceResponse(res))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetResource maneja GET /v2/admin/tenants/{tenant_id}/resources/{resourceId}
+func (c *ResourcesController) GetResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tda := mw.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail(tenantRequired))
+		return
+	}
+
+	res, err := c.service.Get(ctx, tda.Slug(), r.PathValue("resourceId"))
+	if err != nil {
+		httperrors.WriteError(w, mapResourceError(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIResourceResponse(*res))
+}
+
+// UpsertResource maneja POST /resources y PUT /resources/{resourceId}
+func (c *ResourcesController) UpsertResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("ResourcesController.UpsertResource"))
+
+	tda := mw.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail(tenantRequired))
+		return
+	}
+
+	var req dto.APIResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+	req.Identifier = strings.TrimSpace(req.Identifier)
+
+	// PUT: el identifier es inmutable (es el aud de los tokens ya emitidos)
+	if id := r.PathValue("resourceId"); id != "" {
+		existing, err := c.service.Get(ctx, tda.Slug(), id)
+		if err != nil {
+			httperrors.WriteError(w, mapResourceError(err))
+			return
+		}
+		if req.Identifier == "" {
+			req.Identifier = existing.Identifier
+		}
+		if req.Identifier != existing.Identifier {
+			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("identifier cannot be changed"))
+			return
+		}
+	}
+
+	if req.Identifier == "" {
+		httperrors.WriteError(w, httperrors.ErrMissingFields.WithDetail("identifier requerido"))
+		return
+	}
+
+	res, err := c.service.Upsert(ctx, tda.Slug(), repository.APIResourceInput{
+		Identifier:     req.Identifier,
+		Name:           req.Name,
+		Description:    req.Description,
+		Scopes:         req.Scopes,
+		AccessTokenTTL: req.AccessTokenTTL,
+		SigningAlg:     req.SigningAlg,
+
+		AccessTokenProfile: req.AccessTokenProfile,
+	})
+	if err != nil {
+		log.Error("upsert failed", logger.Err(err))
+		httperrors.WriteError(w, mapResourceError(err))
+		return
+	}
+
+	log.Info("api resource upserted", logger.String("resource", res.Identifier))
+	writeJSON(w, http.StatusOK, toAPIResourceResponse(*res))
+}
+
+// DeleteResource maneja DELETE /v2/admin/tenants/{tenant_id}/resources/{resourceId}
+func (c *ResourcesController) DeleteResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("ResourcesController.DeleteResource"))
+
+	tda := mw.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail(tenantRequired))
+		return
+	}
+
+	id := r.PathValue("resourceId")
+	if id == "" {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("missing resource id"))
+		return
+	}
+
+	if err := c.service.Delete(ctx, tda.Slug(), id); err != nil {
+		log.Error("delete failed", logger.Err(err))
+		httperrors.WriteError(w, mapResourceError(err))
+		return
+	}
+
+	log.Info("api resource deleted", logger.String("resource_id", id))
+	writeJSON(w, http.StatusOK, dto.StatusResponse{Status: "ok"})
+}
+
+// ─── Helpers ───
+
+func toAPIResourceResponse(r repository.APIResource) dto.APIResourceResponse {
+	resp := dto.APIResourceResponse{
+		ID:             r.ID,
+		Identifier:     r.Identifier,
+		Name:           r.Name,
+		Description:    r.Description,
+		Scopes:         r.Scopes,
+		AccessTokenTTL: r.AccessTokenTTL,
+		SigningAlg:     r.SigningAlg,
+
+		AccessTokenProfile: r.AccessTokenProfile,
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	if !r.CreatedAt.IsZero() {
+		resp.CreatedAt = r.CreatedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if r.UpdatedAt != nil && !r.UpdatedAt.IsZero() {
+		resp.UpdatedAt = r.UpdatedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
+
+func mapResourceError(err error) *httperrors.AppError {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return httperrors.ErrNotFound.WithDetail("resource not found")
+	case errors.Is(err, controlplane.ErrScopeInUse):
+		return httperrors.ErrConflict.WithDetail(err.Error())
+	case errors.Is(err, controlplane.ErrBadInput), strings.Contains(err.Error(), "required"):
+		return httperrors.ErrBadRequest.WithDetail(err.Error())
+	default:
+		return httperrors.ErrInternalServerError.WithCause(err)
+	}
+}
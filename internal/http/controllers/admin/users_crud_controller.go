@@ -365,6 +365,88 @@ func (c *UsersCRUDController) EnableUser(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(dto.UserActionResponse{Status: "enabled"})
 }
 
+// ListLockedUsers maneja GET /v2/admin/tenants/{id}/users/locked
+func (c *UsersCRUDController) ListLockedUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx)
+
+	if c.actionService == nil || c.dal == nil {
+		httperrors.WriteError(w, httperrors.ErrInternalServerError.WithDetail("action service not configured"))
+		return
+	}
+
+	tenantID := extractTenantIDFromPath(r.URL.Path)
+	if tenantID == "" {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant_id is required in path"))
+		return
+	}
+
+	tda, err := c.dal.ForTenant(ctx, tenantID)
+	if err != nil {
+		log.Warn("tenant not found", logger.TenantID(tenantID), logger.Err(err))
+		httperrors.WriteError(w, httperrors.ErrNotFound.WithDetail("tenant not found"))
+		return
+	}
+
+	locked, err := c.actionService.ListLocked(ctx, tda)
+	if err != nil {
+		log.Error("list locked failed", logger.Err(err))
+		httperrors.WriteError(w, httperrors.ErrInternalServerError)
+		return
+	}
+
+	resp := dto.ListLockedAccountsResponse{Accounts: make([]dto.LockedAccountResponse, 0, len(locked))}
+	for _, a := range locked {
+		resp.Accounts = append(resp.Accounts, dto.LockedAccountResponse{
+			UserID:      a.UserID,
+			Email:       a.Email,
+			Failures:    a.Failures,
+			LockedUntil: a.LockedUntil,
+		})
+	}
+	resp.Total = len(resp.Accounts)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// UnlockUser maneja POST /v2/admin/tenants/{id}/users/{userId}/unlock
+func (c *UsersCRUDController) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx)
+
+	if c.actionService == nil || c.dal == nil {
+		httperrors.WriteError(w, httperrors.ErrInternalServerError.WithDetail("action service not configured"))
+		return
+	}
+
+	tenantID, userID := extractTenantUserAndActionFromPath(r.URL.Path)
+	if tenantID == "" || userID == "" {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant_id and user_id are required in path"))
+		return
+	}
+
+	tda, err := c.dal.ForTenant(ctx, tenantID)
+	if err != nil {
+		log.Warn("tenant not found", logger.TenantID(tenantID), logger.Err(err))
+		httperrors.WriteError(w, httperrors.ErrNotFound.WithDetail("tenant not found"))
+		return
+	}
+
+	if err := c.actionService.Unlock(ctx, tda, userID, "admin"); err != nil {
+		if errors.Is(err, svc.ErrUserNotFound) {
+			httperrors.WriteError(w, httperrors.ErrNotFound.WithDetail("user not found"))
+			return
+		}
+		log.Error("unlock failed", logger.Err(err))
+		httperrors.WriteError(w, httperrors.ErrInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(dto.UserActionResponse{Status: "unlocked"})
+}
+
 // SetEmailVerified maneja POST /v2/admin/tenants/{id}/users/{userId}/set-email-verified
 func (c *UsersCRUDController) SetEmailVerified(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
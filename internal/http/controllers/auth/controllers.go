@@ -18,6 +18,9 @@ type Controllers struct {
 	Me              *MeController
 	Profile         *ProfileController
 	MFATOTP         *MFATOTPController
+	WebAuthn        *WebAuthnController
+	MFASMS          *MFASMSController
+	Passwordless    *PasswordlessController
 	Social          *social.Controllers
 }
 
@@ -34,6 +37,23 @@ func NewControllers(s svc.Services) *Controllers {
 		Me:              NewMeController(),
 		Profile:         NewProfileController(s.Profile),
 		MFATOTP:         NewMFATOTPController(s.MFATOTP),
+		WebAuthn:        NewWebAuthnController(s.WebAuthn),
+		MFASMS:          newMFASMSController(s.MFASMS),
+		Passwordless:    newPasswordlessController(s.Passwordless),
 		Social:          social.NewControllers(s.Social),
 	}
 }
+
+func newMFASMSController(s svc.MFASMSService) *MFASMSController {
+	if s == nil {
+		return nil
+	}
+	return NewMFASMSController(s)
+}
+
+func newPasswordlessController(s svc.PasswordlessService) *PasswordlessController {
+	if s == nil {
+		return nil
+	}
+	return NewPasswordlessController(s)
+}
@@ -93,6 +93,7 @@ func (c *LoginController) Login(w http.ResponseWriter, r *http.Request) {
 			MFARequired: true,
 			MFAToken:    result.MFAToken,
 			AMR:         result.AMR,
+			MFAMethods:  result.MFAMethods,
 		})
 		return
 	}
@@ -131,6 +132,14 @@ func writeLoginError(w http.ResponseWriter, err error) {
 			"message": "usuario deshabilitado",
 		})
 
+	case errors.Is(err, svc.ErrUserLocked):
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.WriteHeader(http.StatusLocked)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"code":    "account_locked",
+			"message": "cuenta bloqueada temporalmente por intentos fallidos",
+		})
+
 	case errors.Is(err, svc.ErrEmailNotVerified):
 		httperrors.WriteError(w, httperrors.ErrForbidden.WithDetail("email no verificado"))
 
@@ -0,0 +1,202 @@
+// Package auth contains the SMS / voice OTP factor controller.
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/middlewares"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/auth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"go.uber.org/zap"
+)
+
+// MFASMSController handles phone verification and SMS / voice MFA endpoints.
+type MFASMSController struct {
+	service svc.MFASMSService
+}
+
+// NewMFASMSController creates the controller.
+func NewMFASMSController(s svc.MFASMSService) *MFASMSController {
+	return &MFASMSController{service: s}
+}
+
+// Enroll handles POST /v2/mfa/sms/enroll
+// Requires: authenticated user (claims in context)
+func (c *MFASMSController) Enroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("mfa.sms.enroll"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tenantSlug, userID, _, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SMSEnrollRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	res, err := c.service.Enroll(ctx, tenantSlug, userID, req)
+	if err != nil {
+		writeMFASMSError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Verify handles POST /v2/mfa/sms/verify
+// Requires: authenticated user (claims in context)
+func (c *MFASMSController) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("mfa.sms.verify"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tenantSlug, userID, _, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SMSVerifyRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	res, err := c.service.Verify(ctx, tenantSlug, userID, req.Code)
+	if err != nil {
+		writeMFASMSError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Remove handles DELETE /v2/mfa/sms
+// Requires: authenticated user (claims in context)
+func (c *MFASMSController) Remove(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("mfa.sms.remove"))
+
+	tenantSlug, userID, _, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Remove(ctx, tenantSlug, userID); err != nil {
+		writeMFASMSError(w, err, log)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChallengeBegin handles POST /v2/mfa/sms/challenge/begin (mfa_token driven, no JWT)
+func (c *MFASMSController) ChallengeBegin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("mfa.sms.challenge.begin"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tda := middlewares.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant required"))
+		return
+	}
+
+	var req dto.SMSChallengeBeginRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	res, err := c.service.BeginChallenge(ctx, tda.Slug(), req)
+	if err != nil {
+		writeMFASMSError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Challenge handles POST /v2/mfa/sms/challenge (mfa_token driven, no JWT)
+func (c *MFASMSController) Challenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("mfa.sms.challenge"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tda := middlewares.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant required"))
+		return
+	}
+
+	var req dto.SMSChallengeRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	result, err := c.service.Challenge(ctx, tda.Slug(), req)
+	if err != nil {
+		writeMFASMSError(w, err, log)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+func writeMFASMSError(w http.ResponseWriter, err error, log *zap.Logger) {
+	switch err {
+	case svc.ErrSMSNotEnabled:
+		httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("phone verification not enabled for this tenant"))
+	case svc.ErrSMSFactorNotEnabled:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "mfa_method_not_enabled", "SMS factor not enabled for this tenant"))
+	case svc.ErrSMSInvalidPhone:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_phone_number", "Phone number must be in E.164 format (e.g. +5491122334455)"))
+	case svc.ErrSMSChannelNotAllowed:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "channel_not_allowed", "Delivery channel not allowed"))
+	case svc.ErrSMSRateLimited:
+		httperrors.WriteError(w, httperrors.ErrRateLimitExceeded.WithDetail("too many codes sent to this number"))
+	case svc.ErrSMSSendFailed:
+		httperrors.WriteError(w, httperrors.ErrBadGateway.WithDetail("failed to send code"))
+	case svc.ErrSMSCodeNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "Code expired or not found"))
+	case svc.ErrSMSTooManyAttempts:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "Too many failed attempts, request a new code"))
+	case svc.ErrSMSPhoneNotVerified:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "mfa_not_initialized", "No verified phone number"))
+	case svc.ErrMFAInvalidCode:
+		httperrors.WriteError(w, httperrors.New(http.StatusUnauthorized, "invalid_mfa_code", "Invalid code"))
+	case svc.ErrMFAMissingFields:
+		httperrors.WriteError(w, httperrors.ErrMissingFields)
+	case svc.ErrMFAUserNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusNotFound, "user_not_found", "User not found"))
+	case svc.ErrMFATokenNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "MFA token expired or not found"))
+	case svc.ErrMFATokenInvalid:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "Invalid MFA token payload"))
+	case svc.ErrMFATenantMismatch:
+		httperrors.WriteError(w, httperrors.New(http.StatusUnauthorized, "invalid_client", "Tenant mismatch"))
+	case svc.ErrMFALocked:
+		httperrors.WriteError(w, httperrors.New(http.StatusLocked, "account_locked", "Account temporarily locked after too many failed attempts"))
+	case svc.ErrMFAStoreFailed, svc.ErrMFACryptoFailed:
+		log.Error("store error", zap.Error(err))
+		httperrors.WriteError(w, httperrors.New(http.StatusInternalServerError, "store_error", "Storage operation failed"))
+	default:
+		// Client, user-state and token issuance errors are shared with password login
+		log.Debug("mfa sms error", zap.Error(err))
+		writeLoginError(w, err)
+	}
+}
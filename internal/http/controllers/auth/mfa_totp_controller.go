@@ -331,6 +331,8 @@ func (c *MFATOTPController) handleServiceError(w http.ResponseWriter, err error,
 		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "Invalid MFA token payload"))
 	case svc.ErrMFATenantMismatch:
 		httperrors.WriteError(w, httperrors.New(http.StatusUnauthorized, "invalid_client", "Tenant mismatch"))
+	case svc.ErrMFALocked:
+		httperrors.WriteError(w, httperrors.New(http.StatusLocked, "account_locked", "Account temporarily locked after too many failed attempts"))
 	default:
 		log.Error("unexpected error", zap.Error(err))
 		httperrors.WriteError(w, httperrors.ErrInternalServerError)
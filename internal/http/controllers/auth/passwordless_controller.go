@@ -0,0 +1,107 @@
+// Package auth contains the passwordless (email) login controller.
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/auth"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"go.uber.org/zap"
+)
+
+// PasswordlessController handles passwordless email login endpoints.
+type PasswordlessController struct {
+	service svc.PasswordlessService
+}
+
+// NewPasswordlessController creates the controller.
+func NewPasswordlessController(s svc.PasswordlessService) *PasswordlessController {
+	return &PasswordlessController{service: s}
+}
+
+// Start handles POST /v2/auth/passwordless/start
+// The response is the same whether or not the email has an account.
+func (c *PasswordlessController) Start(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("passwordless.start"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req dto.PasswordlessStartRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+	req.ClientIP = helpers.ClientIP(r)
+
+	res, err := c.service.Start(ctx, req)
+	if err != nil {
+		writePasswordlessError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Verify handles POST /v2/auth/passwordless/verify
+func (c *PasswordlessController) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("passwordless.verify"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req dto.PasswordlessVerifyRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	result, err := c.service.Verify(ctx, req)
+	if err != nil {
+		writePasswordlessError(w, err, log)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+func writePasswordlessError(w http.ResponseWriter, err error, log *zap.Logger) {
+	switch {
+	case errors.Is(err, passwordless.ErrNotEnabled):
+		httperrors.WriteError(w, httperrors.New(http.StatusForbidden, "passwordless_disabled", "Passwordless login not enabled"))
+	case errors.Is(err, passwordless.ErrInvalidRequest):
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "Invalid email, method or redirect_uri"))
+	case errors.Is(err, passwordless.ErrInvalidClient):
+		writeLoginError(w, svc.ErrInvalidClient)
+	case errors.Is(err, passwordless.ErrInvalidCode):
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "Code or link invalid or expired"))
+	case errors.Is(err, passwordless.ErrTooManyAttempts):
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "Too many failed attempts, request a new code"))
+	case errors.Is(err, passwordless.ErrRateLimited):
+		httperrors.WriteError(w, httperrors.ErrRateLimitExceeded.WithDetail("Too many codes requested, try again later"))
+	case errors.Is(err, passwordless.ErrUserDisabled):
+		writeLoginError(w, svc.ErrUserDisabled)
+	case errors.Is(err, lockout.ErrLocked):
+		writeLoginError(w, svc.ErrUserLocked)
+	case errors.Is(err, passwordless.ErrNoDatabase):
+		writeLoginError(w, svc.ErrNoDatabase)
+	case errors.Is(err, passwordless.ErrUnavailable):
+		httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("passwordless login unavailable"))
+	case errors.Is(err, svc.ErrMissingFields):
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant_id and client_id are required"))
+	default:
+		log.Debug("passwordless error", zap.Error(err))
+		writeLoginError(w, err)
+	}
+}
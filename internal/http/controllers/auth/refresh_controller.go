@@ -100,7 +100,7 @@ func writeRefreshError(w http.ResponseWriter, err error) {
 	case errors.Is(err, svc.ErrInvalidRefreshToken):
 		httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("refresh token inválido o expirado"))
 
-	case errors.Is(err, svc.ErrRefreshTokenRevoked):
+	case errors.Is(err, svc.ErrRefreshTokenRevoked), errors.Is(err, svc.ErrRefreshTokenReused):
 		httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("refresh token revocado"))
 
 	case errors.Is(err, svc.ErrClientMismatch):
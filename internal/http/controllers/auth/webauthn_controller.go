@@ -0,0 +1,339 @@
+// Package auth contains the WebAuthn / passkey controller.
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/middlewares"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/auth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"go.uber.org/zap"
+)
+
+// webAuthnBodyLimit bounds request bodies (attestation objects can carry certificates).
+const webAuthnBodyLimit = 64 << 10
+
+// WebAuthnController handles WebAuthn registration, MFA and passkey login endpoints.
+type WebAuthnController struct {
+	service svc.WebAuthnService
+}
+
+// NewWebAuthnController creates the controller.
+func NewWebAuthnController(s svc.WebAuthnService) *WebAuthnController {
+	return &WebAuthnController{service: s}
+}
+
+// RegisterBegin handles POST /v2/mfa/webauthn/register/begin
+// Requires: authenticated user (claims in context)
+func (c *WebAuthnController) RegisterBegin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.register.begin"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tenantSlug, userID, clientID, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	opts, err := c.service.BeginRegistration(ctx, tenantSlug, userID, clientID)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.WebAuthnCreationOptionsResponse{PublicKey: *opts})
+}
+
+// RegisterFinish handles POST /v2/mfa/webauthn/register/finish
+// Requires: authenticated user (claims in context)
+func (c *WebAuthnController) RegisterFinish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.register.finish"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tenantSlug, userID, clientID, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.WebAuthnRegisterFinishRequest
+	r.Body = http.MaxBytesReader(w, r.Body, webAuthnBodyLimit)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	cred, err := c.service.FinishRegistration(ctx, tenantSlug, userID, clientID, req)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusCreated, cred)
+}
+
+// ListCredentials handles GET /v2/mfa/webauthn/credentials
+func (c *WebAuthnController) ListCredentials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.credentials.list"))
+
+	tenantSlug, userID, _, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	creds, err := c.service.ListCredentials(ctx, tenantSlug, userID)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.WebAuthnCredentialsResponse{Credentials: creds})
+}
+
+// RenameCredential handles PATCH /v2/mfa/webauthn/credentials/{id}
+func (c *WebAuthnController) RenameCredential(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.credentials.rename"))
+
+	tenantSlug, userID, _, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.WebAuthnRenameRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	if err := c.service.RenameCredential(ctx, tenantSlug, userID, r.PathValue("id"), req.Name); err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteCredential handles DELETE /v2/mfa/webauthn/credentials/{id}
+func (c *WebAuthnController) DeleteCredential(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.credentials.delete"))
+
+	tenantSlug, userID, _, ok := authenticatedUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteCredential(ctx, tenantSlug, userID, r.PathValue("id")); err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MFABegin handles POST /v2/mfa/webauthn/challenge/begin (mfa_token driven, no JWT)
+func (c *WebAuthnController) MFABegin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.mfa.begin"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tda := middlewares.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant required"))
+		return
+	}
+
+	var req dto.WebAuthnMFABeginRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	opts, err := c.service.BeginMFA(ctx, tda.Slug(), req.MFAToken)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.WebAuthnRequestOptionsResponse{PublicKey: *opts})
+}
+
+// MFAChallenge handles POST /v2/mfa/webauthn/challenge (mfa_token driven, no JWT)
+func (c *WebAuthnController) MFAChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("webauthn.mfa.challenge"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	tda := middlewares.GetTenant(ctx)
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant required"))
+		return
+	}
+
+	var req dto.WebAuthnMFAFinishRequest
+	r.Body = http.MaxBytesReader(w, r.Body, webAuthnBodyLimit)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	result, err := c.service.FinishMFA(ctx, tda.Slug(), req)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+// PasskeyBegin handles POST /v2/auth/passkey/begin
+// Tenant and client come in the body, like POST /v2/auth/login.
+func (c *WebAuthnController) PasskeyBegin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("passkey.begin"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req dto.PasskeyLoginBeginRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	opts, err := c.service.BeginLogin(ctx, req)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.WebAuthnRequestOptionsResponse{PublicKey: *opts})
+}
+
+// PasskeyFinish handles POST /v2/auth/passkey/finish
+func (c *WebAuthnController) PasskeyFinish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("passkey.finish"))
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req dto.PasskeyLoginFinishRequest
+	r.Body = http.MaxBytesReader(w, r.Body, webAuthnBodyLimit)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrInvalidJSON)
+		return
+	}
+
+	result, err := c.service.FinishLogin(ctx, req)
+	if err != nil {
+		writeWebAuthnError(w, err, log)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+// ─── Helpers ───
+
+func requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+// authenticatedUser resolves tenant, subject and client (aud) from the request context.
+func authenticatedUser(w http.ResponseWriter, r *http.Request) (tenantSlug, userID, clientID string, ok bool) {
+	tda := middlewares.GetTenant(r.Context())
+	if tda == nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant required"))
+		return "", "", "", false
+	}
+	claims := middlewares.GetClaims(r.Context())
+	if claims != nil {
+		userID = middlewares.ClaimString(claims, "sub")
+		clientID = middlewares.ClaimString(claims, "aud")
+	}
+	if userID == "" {
+		httperrors.WriteError(w, httperrors.ErrUnauthorized)
+		return "", "", "", false
+	}
+	return tda.Slug(), userID, clientID, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeLoginResult answers like POST /v2/auth/login: tokens, or the MFA challenge.
+func writeLoginResult(w http.ResponseWriter, result *dto.LoginResult) {
+	if result.MFARequired {
+		writeJSON(w, http.StatusOK, dto.MFARequiredResponse{
+			MFARequired: true,
+			MFAToken:    result.MFAToken,
+			AMR:         result.AMR,
+			MFAMethods:  result.MFAMethods,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.LoginResponse{
+		AccessToken:  result.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    result.ExpiresIn,
+		RefreshToken: result.RefreshToken,
+	})
+}
+
+func writeWebAuthnError(w http.ResponseWriter, err error, log *zap.Logger) {
+	switch err {
+	case svc.ErrWebAuthnNotSupported:
+		httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("WebAuthn not supported for this tenant"))
+	case svc.ErrWebAuthnInvalidRequest:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "Malformed WebAuthn credential"))
+	case svc.ErrWebAuthnCeremonyNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "WebAuthn challenge expired or not found"))
+	case svc.ErrWebAuthnVerificationFailed:
+		httperrors.WriteError(w, httperrors.New(http.StatusUnauthorized, "webauthn_verification_failed", "WebAuthn verification failed"))
+	case svc.ErrWebAuthnCredentialExists:
+		httperrors.WriteError(w, httperrors.ErrConflict.WithDetail("credential already registered"))
+	case svc.ErrWebAuthnCredentialNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusNotFound, "credential_not_found", "Credential not found"))
+	case svc.ErrMFANotInitialized:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "mfa_not_initialized", "No WebAuthn credentials registered"))
+	case svc.ErrMFAMissingFields:
+		httperrors.WriteError(w, httperrors.ErrMissingFields)
+	case svc.ErrMFAUserNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusNotFound, "user_not_found", "User not found"))
+	case svc.ErrMFATokenNotFound:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "MFA token expired or not found"))
+	case svc.ErrMFATokenInvalid:
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "Invalid MFA token payload"))
+	case svc.ErrMFATenantMismatch:
+		httperrors.WriteError(w, httperrors.New(http.StatusUnauthorized, "invalid_client", "Tenant mismatch"))
+	case svc.ErrMFALocked:
+		httperrors.WriteError(w, httperrors.New(http.StatusLocked, "account_locked", "Account temporarily locked after too many failed attempts"))
+	case svc.ErrMFAStoreFailed:
+		log.Error("store error", zap.Error(err))
+		httperrors.WriteError(w, httperrors.New(http.StatusInternalServerError, "store_error", "Storage operation failed"))
+	default:
+		// Client, user-state and token issuance errors are shared with password login
+		log.Debug("webauthn error", zap.Error(err))
+		writeLoginError(w, err)
+	}
+}
@@ -253,3 +253,95 @@ func (c *FlowsController) ResetPassword(w http.ResponseWriter, r *http.Request)
 
 	log.Debug("password reset completed")
 }
+
+// UnlockRequest handles POST /v2/auth/unlock/request.
+func (c *FlowsController) UnlockRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("FlowsController.UnlockRequest"))
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	// Limit body size
+	r.Body = http.MaxBytesReader(w, r.Body, 32<<10) // 32KB
+
+	// Parse request
+	var req dto.UnlockRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("invalid JSON"))
+		return
+	}
+
+	// Get tenant from middleware
+	tda := mw.MustGetTenant(ctx)
+
+	// Call service
+	if err := c.service.UnlockRequest(ctx, tda, req); err != nil {
+		switch err {
+		case svc.ErrFlowsMissingEmail, svc.ErrFlowsTenantMismatch:
+			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail(err.Error()))
+		case svc.ErrFlowsNoDatabase:
+			httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("database not available"))
+		default:
+			log.Error("unlock request error", logger.Err(err))
+			httperrors.WriteError(w, httperrors.ErrInternalServerError)
+		}
+		return
+	}
+
+	// Always return OK (anti-enumeration)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+
+	log.Debug("unlock requested")
+}
+
+// UnlockConfirm handles GET /v2/auth/unlock (the link in the account locked email).
+func (c *FlowsController) UnlockConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("FlowsController.UnlockConfirm"))
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	req := dto.UnlockConfirmRequest{
+		Token:    q.Get("token"),
+		TenantID: q.Get("tenant_id"),
+	}
+
+	if req.Token == "" {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("token is required"))
+		return
+	}
+
+	// Get tenant from middleware
+	tda := mw.MustGetTenant(ctx)
+
+	// Call service
+	if err := c.service.UnlockConfirm(ctx, tda, req); err != nil {
+		switch err {
+		case svc.ErrFlowsMissingToken, svc.ErrFlowsInvalidToken, svc.ErrFlowsTenantMismatch:
+			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail(err.Error()))
+		case svc.ErrFlowsNoDatabase:
+			httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("database not available"))
+		default:
+			log.Error("unlock confirm error", logger.Err(err))
+			httperrors.WriteError(w, httperrors.ErrInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "unlocked"})
+
+	log.Debug("account unlocked")
+}
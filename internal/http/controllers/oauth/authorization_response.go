@@ -0,0 +1,78 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+)
+
+// formPostPage posts the authorization response to the client's redirect_uri
+// (OAuth 2.0 Form Post Response Mode §2).
+var formPostPage = template.Must(template.New("form_post").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Submit This Form</title></head>
+<body>
+<form method="post" action="{{.Action}}">
+{{range $name, $values := .Params}}{{range $values}}<input type="hidden" name="{{$name}}" value="{{.}}">
+{{end}}{{end}}<noscript><button type="submit">Continue</button></noscript>
+</form>
+<script nonce="{{.Nonce}}">document.forms[0].submit();</script>
+</body>
+</html>
+`))
+
+// writeAuthorizationResponse delivers the authorization response params to the
+// redirect_uri in the given response mode (query, fragment or form_post).
+// Empty params are omitted.
+func writeAuthorizationResponse(w http.ResponseWriter, r *http.Request, redirectURI, mode string, params map[string]string) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	values := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+
+	switch mode {
+	case svc.ResponseModeFormPost:
+		writeFormPostPage(w, redirectURI, values)
+	case svc.ResponseModeFragment:
+		http.Redirect(w, r, redirectURI+"#"+values.Encode(), http.StatusFound)
+	default:
+		loc := redirectURI
+		for k := range values {
+			loc = addQueryParam(loc, k, values.Get(k))
+		}
+		http.Redirect(w, r, loc, http.StatusFound)
+	}
+}
+
+// writeFormPostPage renders the auto-submitting form. The API-wide CSP forbids
+// scripts and cross-origin form targets, so it is relaxed here to the
+// redirect_uri origin and a per-response nonce.
+func writeFormPostPage(w http.ResponseWriter, action string, params url.Values) {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	n := base64.RawStdEncoding.EncodeToString(nonce)
+
+	target := "'self'"
+	if u, err := url.Parse(action); err == nil && u.Scheme != "" && u.Host != "" {
+		target = u.Scheme + "://" + u.Host
+	}
+	w.Header().Set("Content-Security-Policy",
+		"default-src 'none'; form-action "+target+"; script-src 'nonce-"+n+"'; frame-ancestors 'none'; base-uri 'none'")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = formPostPage.Execute(w, struct {
+		Action string
+		Params url.Values
+		Nonce  string
+	}{action, params, n})
+}
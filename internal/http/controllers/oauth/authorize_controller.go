@@ -24,7 +24,8 @@ func NewAuthorizeController(s svc.AuthorizeService) *AuthorizeController {
 }
 
 // Authorize handles GET /oauth2/authorize.
-// Implements: PKCE, session/bearer auth, MFA step-up, auth code issuance.
+// Implements: PKCE, PAR request_uri, JAR request objects, response modes (incl. JARM), OIDC prompt/max_age/id_token_hint, session/bearer auth,
+// MFA step-up, consent hand-off, auth code issuance.
 func (c *AuthorizeController) Authorize(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("AuthorizeController.Authorize"))
@@ -52,6 +53,17 @@ func (c *AuthorizeController) Authorize(w http.ResponseWriter, r *http.Request)
 		CodeChallenge:       strings.TrimSpace(q.Get("code_challenge")),
 		CodeChallengeMethod: strings.TrimSpace(q.Get("code_challenge_method")),
 		Prompt:              strings.TrimSpace(q.Get("prompt")),
+		RequestURI:          strings.TrimSpace(q.Get("request_uri")),
+		MaxAge:              strings.TrimSpace(q.Get("max_age")),
+		LoginHint:           strings.TrimSpace(q.Get("login_hint")),
+		IDTokenHint:         strings.TrimSpace(q.Get("id_token_hint")),
+		UILocales:           strings.TrimSpace(q.Get("ui_locales")),
+		ACRValues:           strings.TrimSpace(q.Get("acr_values")),
+		Resource:            formResources(q),
+		ResponseMode:        strings.TrimSpace(q.Get("response_mode")),
+		Request:             strings.TrimSpace(q.Get("request")),
+
+		AuthorizationDetails: strings.TrimSpace(q.Get("authorization_details")),
 	}
 
 	log.Debug("authorize request",
@@ -74,6 +86,14 @@ func (c *AuthorizeController) Authorize(w http.ResponseWriter, r *http.Request)
 			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_client", "client not found"))
 		case svc.ErrInvalidRedirect:
 			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_redirect_uri", "redirect_uri not allowed"))
+		case svc.ErrInvalidRequestURI:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request_uri", "request_uri is invalid or expired"))
+		case svc.ErrPARRequired:
+			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("pushed authorization request required"))
+		case svc.ErrInvalidRequestObject:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request_object", "request object is invalid"))
+		case svc.ErrRequestObjectRequired:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request_object", "signed request object required"))
 		default:
 			log.Error("authorize failed", logger.Err(err))
 			httperrors.WriteError(w, httperrors.ErrInternalServerError)
@@ -92,30 +112,34 @@ func (c *AuthorizeController) Authorize(w http.ResponseWriter, r *http.Request)
 	case dto.AuthResultMFARequired:
 		c.respondMFARequired(w, result.MFAToken)
 
+	case dto.AuthResultNeedConsent:
+		http.Redirect(w, r, result.ConsentURL, http.StatusFound)
+
 	case dto.AuthResultError:
 		c.redirectError(w, r, result)
 	}
 }
 
-// redirectSuccess redirects with the auth code.
+// redirectSuccess sends the auth code in the requested response mode.
 func (c *AuthorizeController) redirectSuccess(w http.ResponseWriter, r *http.Request, result dto.AuthResult) {
-	loc := addQueryParam(result.RedirectURI, "code", result.Code)
-	if result.State != "" {
-		loc = addQueryParam(loc, "state", result.State)
+	params := map[string]string{"code": result.Code, "state": result.State}
+	if result.Response != "" {
+		params = map[string]string{"response": result.Response}
 	}
-	http.Redirect(w, r, loc, http.StatusFound)
+	writeAuthorizationResponse(w, r, result.RedirectURI, result.ResponseMode, params)
 }
 
-// redirectError redirects with error params.
+// redirectError sends the error params in the requested response mode.
 func (c *AuthorizeController) redirectError(w http.ResponseWriter, r *http.Request, result dto.AuthResult) {
-	w.Header().Set("Cache-Control", "no-store")
-	w.Header().Set("Pragma", "no-cache")
-
-	loc := addQueryParam(result.RedirectURI, "error", result.ErrorCode)
-	if result.ErrorDescription != "" {
-		loc = addQueryParam(loc, "error_description", result.ErrorDescription)
+	params := map[string]string{
+		"error":             result.ErrorCode,
+		"error_description": result.ErrorDescription,
+		"state":             result.State,
+	}
+	if result.Response != "" {
+		params = map[string]string{"response": result.Response}
 	}
-	http.Redirect(w, r, loc, http.StatusFound)
+	writeAuthorizationResponse(w, r, result.RedirectURI, result.ResponseMode, params)
 }
 
 // respondMFARequired sends JSON response for MFA step-up.
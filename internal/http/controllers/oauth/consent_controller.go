@@ -68,6 +68,12 @@ func (c *ConsentController) Accept(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// fragment / form_post response modes have no ready-made URL
+	if res.URL == "" && res.Params != nil {
+		writeAuthorizationResponse(w, r, res.RedirectURI, res.ResponseMode, res.Params)
+		return
+	}
+
 	// Success Redirect
 	w.Header().Set("Cache-Control", "no-store")
 	w.Header().Set("Pragma", "no-cache")
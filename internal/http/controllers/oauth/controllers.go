@@ -1,11 +1,19 @@
 // Package oauth contains controllers for OAuth2/OIDC endpoints.
 package oauth
 
-import svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+import (
+	sessiondto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+)
 
 // ControllerDeps contains additional dependencies for controllers.
 type ControllerDeps struct {
-	ClientAuth ClientAuthenticator // Optional: for /introspect client auth
+	ClientAuth       ClientAuthenticator // Optional: for /introspect client auth
+	ClientCertHeader string              // Optional: header carrying the mTLS client cert from a TLS-terminating proxy
+
+	// Session cookie deletion shared with /v2/session/logout
+	SessionCookies SessionCookieDeleter
+	SessionLogout  sessiondto.SessionLogoutConfig
 }
 
 // Controllers agrupa todos los controllers del dominio OAuth.
@@ -15,15 +23,23 @@ type Controllers struct {
 	Introspect *IntrospectController
 	Revoke     *RevokeController
 	Consent    *ConsentController
+	EndSession *EndSessionController
+	Device     *DeviceController
+	PAR        *PARController
+	Register   *RegistrationController
 }
 
 // NewControllers creates the OAuth controllers aggregator.
 func NewControllers(s svc.Services, deps ControllerDeps) *Controllers {
 	return &Controllers{
 		Authorize:  NewAuthorizeController(s.Authorize),
-		Token:      NewTokenController(s.Token),
-		Revoke:     NewRevokeController(s.Revoke),
-		Introspect: NewIntrospectController(s.Introspect, deps.ClientAuth),
+		Token:      NewTokenController(s.Token, s.DPoP, deps.ClientCertHeader),
+		Revoke:     NewRevokeController(s.Revoke, s.ClientAuth),
+		Introspect: NewIntrospectController(s.Introspect, deps.ClientAuth, s.ClientAuth, deps.ClientCertHeader),
 		Consent:    NewConsentController(s.Consent),
+		EndSession: NewEndSessionController(s.EndSession, deps.SessionCookies, deps.SessionLogout),
+		Device:     NewDeviceController(s.Device, deps.ClientCertHeader),
+		PAR:        NewPARController(s.PAR, deps.ClientCertHeader),
+		Register:   NewRegistrationController(s.Register),
 	}
 }
@@ -0,0 +1,96 @@
+// Package oauth - DeviceController handles the Device Authorization Grant (RFC 8628)
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+// DeviceController handles /oauth2/device_authorization and /oauth2/device.
+type DeviceController struct {
+	service    svc.DeviceService
+	tokens     *TokenController // reused for OAuth JSON error responses
+	certHeader string
+}
+
+// NewDeviceController creates the controller.
+// certHeader names the header a TLS-terminating proxy forwards the client certificate in ("" = none).
+func NewDeviceController(s svc.DeviceService, certHeader string) *DeviceController {
+	return &DeviceController{service: s, tokens: &TokenController{}, certHeader: certHeader}
+}
+
+// DeviceAuthorization handles POST /oauth2/device_authorization (RFC 8628 §3.1).
+func (c *DeviceController) DeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("oauth.device_authorization"))
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		c.tokens.writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "Only POST method is allowed")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+	if err := r.ParseForm(); err != nil {
+		log.Warn("failed to parse form", logger.Err(err))
+		c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Invalid form data")
+		return
+	}
+
+	// client_secret_basic/post, a JWT assertion (private_key_jwt, client_secret_jwt) or mTLS
+	clientID, clientSecret, assertion := clientAuthFromRequest(r)
+
+	req := dto.DeviceAuthorizationRequest{
+		ClientID:            clientID,
+		ClientSecret:        clientSecret,
+		ClientAssertionType: assertion.Type,
+		ClientAssertion:     assertion.Value,
+		Scope:               strings.TrimSpace(r.PostForm.Get("scope")),
+		TenantSlug:          resolveTenantSlug(r),
+		ClientCert:          helpers.ClientCertificate(r, c.certHeader),
+	}
+
+	resp, err := c.service.Authorize(ctx, req)
+	if err != nil {
+		c.tokens.handleServiceError(w, err, ctx)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Verify handles GET /oauth2/device (verification_uri).
+// Always redirects the browser: to the code entry page, login or consent.
+func (c *DeviceController) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("oauth.device.verify"))
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	w.Header().Add("Vary", "Cookie")
+
+	res, err := c.service.Verify(ctx, r, strings.TrimSpace(r.URL.Query().Get("user_code")))
+	if err != nil {
+		log.Error("device verification failed", logger.Err(err))
+		httperrors.WriteError(w, httperrors.ErrInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, res.RedirectURL, http.StatusFound)
+}
@@ -0,0 +1,203 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	sessiondto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+const maxEndSessionBodySize = 32 * 1024 // 32KB
+
+// loggedOutPage is rendered when no post_logout_redirect_uri was requested.
+var loggedOutPage = template.Must(template.New("logged_out").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Signed out</title></head>
+<body>
+<p>You have been signed out.</p>
+</body>
+</html>
+`))
+
+// frontchannelPage loads every RP frontchannel_logout_uri in a hidden iframe
+// (OIDC Front-Channel Logout 1.0 §3) and then continues to the post_logout_redirect_uri.
+var frontchannelPage = template.Must(template.New("frontchannel_logout").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Signing out</title></head>
+<body>
+<p>You have been signed out.</p>
+{{range .URIs}}<iframe src="{{.}}" hidden width="0" height="0"></iframe>
+{{end}}{{if .Redirect}}<p><a href="{{.Redirect}}">Continue</a></p>
+<script nonce="{{.Nonce}}">
+(function () {
+  var left = {{.Pending}}, done = false;
+  function next() { if (!done) { done = true; window.location.replace({{.Redirect}}); } }
+  document.querySelectorAll("iframe").forEach(function (f) {
+    f.addEventListener("load", function () { if (--left <= 0) next(); });
+  });
+  setTimeout(next, 5000);
+})();
+</script>
+{{end}}</body>
+</html>
+`))
+
+// confirmLogoutPage asks the user to confirm a logout that came without id_token_hint.
+// The form posts the original parameters back together with the single-use token.
+var confirmLogoutPage = template.Must(template.New("confirm_logout").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign out</title></head>
+<body>
+<p>Do you want to sign out?</p>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="logout_confirm" value="{{.Token}}">
+{{if .ClientID}}<input type="hidden" name="client_id" value="{{.ClientID}}">
+{{end}}{{if .PostLogoutRedirectURI}}<input type="hidden" name="post_logout_redirect_uri" value="{{.PostLogoutRedirectURI}}">
+{{end}}{{if .State}}<input type="hidden" name="state" value="{{.State}}">
+{{end}}<button type="submit">Sign out</button>
+</form>
+</body>
+</html>
+`))
+
+// SessionCookieDeleter builds the cookie that clears the browser session
+// (implemented by the session logout service).
+type SessionCookieDeleter interface {
+	BuildDeletionCookie(config sessiondto.SessionLogoutConfig) *http.Cookie
+}
+
+// EndSessionController handles GET/POST /oauth2/logout (RP-Initiated Logout).
+type EndSessionController struct {
+	service svc.EndSessionService
+	cookies SessionCookieDeleter
+	config  sessiondto.SessionLogoutConfig
+}
+
+// NewEndSessionController creates the controller. The session cookie is cleared
+// with the same attributes (domain, SameSite, Secure) as /v2/session/logout.
+func NewEndSessionController(s svc.EndSessionService, cookies SessionCookieDeleter, config sessiondto.SessionLogoutConfig) *EndSessionController {
+	return &EndSessionController{service: s, cookies: cookies, config: config}
+}
+
+// EndSession handles the end_session_endpoint.
+// Params may arrive as query (GET) or form (POST).
+func (c *EndSessionController) EndSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("EndSessionController.EndSession"))
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	if r.Method == http.MethodPost {
+		r.Body = http.MaxBytesReader(w, r.Body, maxEndSessionBodySize)
+		defer r.Body.Close()
+		if err := r.ParseForm(); err != nil {
+			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("invalid form body"))
+			return
+		}
+		params = r.PostForm
+	}
+
+	req := dto.EndSessionRequest{
+		IDTokenHint:           strings.TrimSpace(params.Get("id_token_hint")),
+		ClientID:              strings.TrimSpace(params.Get("client_id")),
+		PostLogoutRedirectURI: strings.TrimSpace(params.Get("post_logout_redirect_uri")),
+		State:                 params.Get("state"),
+		Confirm:               strings.TrimSpace(params.Get("logout_confirm")),
+	}
+
+	result, err := c.service.EndSession(ctx, r, req)
+	if err != nil {
+		switch err {
+		case svc.ErrEndSessionInvalidHint:
+			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("invalid id_token_hint"))
+		case svc.ErrEndSessionClientMismatch:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "client_id does not match id_token_hint"))
+		case svc.ErrEndSessionClientRequired:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "client_id or id_token_hint required"))
+		case svc.ErrEndSessionInvalidRedirect:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "post_logout_redirect_uri not allowed"))
+		case svc.ErrEndSessionInvalidConfirm:
+			httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_request", "logout confirmation expired or invalid"))
+		default:
+			log.Error("end session failed", logger.Err(err))
+			httperrors.WriteError(w, httperrors.ErrInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if result.ConfirmToken != "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = confirmLogoutPage.Execute(w, struct {
+			Action, Token, ClientID, PostLogoutRedirectURI, State string
+		}{r.URL.Path, result.ConfirmToken, req.ClientID, req.PostLogoutRedirectURI, req.State})
+		return
+	}
+
+	if result.ClearCookie != "" && c.cookies != nil {
+		cfg := c.config
+		cfg.CookieName = result.ClearCookie
+		http.SetCookie(w, c.cookies.BuildDeletionCookie(cfg))
+	}
+
+	target := result.RedirectURI
+	if target != "" && result.State != "" {
+		target = addQueryParam(target, "state", result.State)
+	}
+
+	if len(result.FrontchannelLogoutURIs) > 0 {
+		writeFrontchannelPage(w, result.FrontchannelLogoutURIs, target)
+		return
+	}
+
+	if target != "" {
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = loggedOutPage.Execute(w, nil)
+}
+
+// writeFrontchannelPage renders the iframe page. The API-wide CSP forbids frames
+// and scripts, so it is relaxed here to the RP origins and a per-response nonce.
+func writeFrontchannelPage(w http.ResponseWriter, uris []string, redirect string) {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	n := base64.RawStdEncoding.EncodeToString(nonce)
+
+	origins := make([]string, 0, len(uris))
+	for _, u := range uris {
+		if p, err := url.Parse(u); err == nil && p.Scheme != "" && p.Host != "" {
+			origins = append(origins, p.Scheme+"://"+p.Host)
+		}
+	}
+	w.Header().Set("Content-Security-Policy",
+		"default-src 'none'; frame-src "+strings.Join(origins, " ")+"; script-src 'nonce-"+n+"'; frame-ancestors 'none'; base-uri 'none'")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = frontchannelPage.Execute(w, struct {
+		URIs     []string
+		Redirect string
+		Nonce    string
+		Pending  int
+	}{uris, redirect, n, len(uris)})
+}
@@ -7,7 +7,9 @@ import (
 
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
 	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
 	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	"go.uber.org/zap"
 )
@@ -21,19 +23,26 @@ type ClientAuthenticator interface {
 type IntrospectController struct {
 	service    svc.IntrospectService
 	clientAuth ClientAuthenticator
+	assertions svc.ClientAuthService
+	certHeader string
 }
 
 // NewIntrospectController creates a new introspect controller.
-func NewIntrospectController(service svc.IntrospectService, clientAuth ClientAuthenticator) *IntrospectController {
+// certHeader names the header a TLS-terminating proxy forwards the client certificate in ("" = none).
+func NewIntrospectController(service svc.IntrospectService, clientAuth ClientAuthenticator, assertions svc.ClientAuthService, certHeader string) *IntrospectController {
 	return &IntrospectController{
 		service:    service,
 		clientAuth: clientAuth,
+		assertions: assertions,
+		certHeader: certHeader,
 	}
 }
 
 // Introspect handles the token introspection request (RFC 7662).
-// Requires client authentication via Basic Auth.
-// Always returns 200 OK with active=true/false.
+// Requires client authentication via Basic Auth; client assertions (RFC 7523) are verified when sent.
+// Always returns 200 OK with active=true/false, as a signed JWT when the client
+// accepts application/token-introspection+jwt (RFC 9701). The signed response
+// requires the client to authenticate with its registered method.
 func (c *IntrospectController) Introspect(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("IntrospectController.Introspect"))
@@ -62,12 +71,30 @@ func (c *IntrospectController) Introspect(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !verifyRequestAssertion(r, c.assertions) {
+		httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("invalid client assertion"))
+		return
+	}
+
 	token := strings.TrimSpace(r.PostForm.Get("token"))
 	if token == "" {
 		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("token is required"))
 		return
 	}
 
+	// The signed response is addressed to the client, so it must be authenticated
+	audience := ""
+	if acceptsIntrospectionJWT(r) {
+		clientID, clientSecret, assertion := clientAuthFromRequest(r)
+		authenticated, err := c.service.AuthenticateClient(ctx, resolveTenantSlug(r), clientID, clientSecret, assertion, helpers.ClientCertificate(r, c.certHeader))
+		if err != nil {
+			log.Debug("introspection client authentication failed", logger.Err(err))
+			httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("invalid client credentials"))
+			return
+		}
+		audience = authenticated
+	}
+
 	// Check include_sys flag
 	includeSys := false
 	if v := r.URL.Query().Get("include_sys"); v == "1" || strings.EqualFold(v, "true") {
@@ -83,25 +110,69 @@ func (c *IntrospectController) Introspect(w http.ResponseWriter, r *http.Request
 		}
 		// For any error, return inactive (don't leak info)
 		log.Debug("introspect error", logger.Err(err))
-		c.writeInactiveResponse(w)
+		c.writeResponse(w, r, resolveTenantSlug(r), audience, dto.IntrospectResponse{Active: false})
 		return
 	}
 
-	// Build response
+	// Build response; the signed variant is issued by the token's tenant
 	resp := c.buildResponse(result)
+	tenantSlug := result.Tid
+	if tenantSlug == "" {
+		tenantSlug = resolveTenantSlug(r)
+	}
+	c.writeResponse(w, r, tenantSlug, audience, resp)
+
+	log.Debug("introspection completed", zap.Bool("active", result.Active))
+}
+
+// writeResponse writes the introspection response as JSON, or as a JWT signed
+// for the authenticated client (audience) when it asks for one (RFC 9701 §4).
+func (c *IntrospectController) writeResponse(w http.ResponseWriter, r *http.Request, tenantSlug, audience string, resp dto.IntrospectResponse) {
+	if audience != "" {
+		signed, err := c.signResponse(r, tenantSlug, audience, resp)
+		if err != nil {
+			logger.From(r.Context()).Error("failed to sign introspection response", logger.Err(err))
+			httperrors.WriteError(w, httperrors.ErrInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/"+jwtx.IntrospectionResponseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(signed))
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
+}
 
-	log.Debug("introspection completed", zap.Bool("active", result.Active))
+// signResponse signs resp for the authenticated client. Per RFC 9701 §5 an
+// inactive token is reported with active=false only.
+func (c *IntrospectController) signResponse(r *http.Request, tenantSlug, audience string, resp dto.IntrospectResponse) (string, error) {
+	if !resp.Active {
+		resp = dto.IntrospectResponse{Active: false}
+	}
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return "", err
+	}
+	return c.service.SignResponse(r.Context(), tenantSlug, audience, claims)
 }
 
-// writeInactiveResponse writes a simple inactive response.
-func (c *IntrospectController) writeInactiveResponse(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(dto.IntrospectResponse{Active: false})
+// acceptsIntrospectionJWT reports whether the Accept header asks for a signed
+// introspection response.
+func acceptsIntrospectionJWT(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if strings.EqualFold(mt, "application/"+jwtx.IntrospectionResponseType) {
+			return true
+		}
+	}
+	return false
 }
 
 // buildResponse builds the HTTP response from service result.
@@ -118,12 +189,33 @@ func (c *IntrospectController) buildResponse(result *dto.IntrospectResult) dto.I
 		Jti:       result.Jti,
 		Tid:       result.Tid,
 		Acr:       result.Acr,
+
+		AuthorizationDetails: result.AuthorizationDetails,
 	}
 
 	if len(result.Amr) > 0 {
 		resp.Amr = result.Amr
 	}
 
+	switch len(result.Aud) {
+	case 0:
+	case 1:
+		resp.Aud = result.Aud[0]
+	default:
+		resp.Aud = result.Aud
+	}
+
+	if result.DPoPJKT != "" || result.X5TS256 != "" {
+		cnf := map[string]string{}
+		if result.DPoPJKT != "" {
+			cnf["jkt"] = result.DPoPJKT
+		}
+		if result.X5TS256 != "" {
+			cnf["x5t#S256"] = result.X5TS256
+		}
+		resp.Cnf = cnf
+	}
+
 	if len(result.Roles) > 0 {
 		resp.Roles = result.Roles
 	}
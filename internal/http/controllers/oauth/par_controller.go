@@ -0,0 +1,92 @@
+// Package oauth - PARController handles Pushed Authorization Requests (RFC 9126)
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+// PARController handles POST /oauth2/par.
+type PARController struct {
+	service    svc.PARService
+	tokens     *TokenController // reused for OAuth JSON error responses
+	certHeader string
+}
+
+// NewPARController creates the controller.
+// certHeader names the header a TLS-terminating proxy forwards the client certificate in ("" = none).
+func NewPARController(s svc.PARService, certHeader string) *PARController {
+	return &PARController{service: s, tokens: &TokenController{}, certHeader: certHeader}
+}
+
+// Push handles POST /oauth2/par (RFC 9126 §2).
+// The client authenticates as at the token endpoint and pushes the same params
+// it would send to /oauth2/authorize; the response carries a one-time request_uri.
+func (c *PARController) Push(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("oauth.par"))
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		c.tokens.writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "Only POST method is allowed")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+	if err := r.ParseForm(); err != nil {
+		log.Warn("failed to parse form", logger.Err(err))
+		c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Invalid form data")
+		return
+	}
+
+	clientID, clientSecret, assertion := clientAuthFromRequest(r)
+	f := r.PostForm
+	req := dto.PushedAuthorizationRequest{
+		ClientID:            clientID,
+		ClientSecret:        clientSecret,
+		ClientAssertionType: assertion.Type,
+		ClientAssertion:     assertion.Value,
+		TenantSlug:          resolveTenantSlug(r),
+		ClientCert:          helpers.ClientCertificate(r, c.certHeader),
+		Authorize: dto.AuthorizeRequest{
+			ResponseType:        strings.TrimSpace(f.Get("response_type")),
+			ClientID:            strings.TrimSpace(f.Get("client_id")),
+			RedirectURI:         strings.TrimSpace(f.Get("redirect_uri")),
+			Scope:               strings.TrimSpace(f.Get("scope")),
+			State:               strings.TrimSpace(f.Get("state")),
+			Nonce:               strings.TrimSpace(f.Get("nonce")),
+			CodeChallenge:       strings.TrimSpace(f.Get("code_challenge")),
+			CodeChallengeMethod: strings.TrimSpace(f.Get("code_challenge_method")),
+			Prompt:              strings.TrimSpace(f.Get("prompt")),
+			RequestURI:          strings.TrimSpace(f.Get("request_uri")),
+			MaxAge:              strings.TrimSpace(f.Get("max_age")),
+			LoginHint:           strings.TrimSpace(f.Get("login_hint")),
+			IDTokenHint:         strings.TrimSpace(f.Get("id_token_hint")),
+			UILocales:           strings.TrimSpace(f.Get("ui_locales")),
+			ACRValues:           strings.TrimSpace(f.Get("acr_values")),
+			ResponseMode:        strings.TrimSpace(f.Get("response_mode")),
+			Request:             strings.TrimSpace(f.Get("request")),
+			Resource:            formResources(f),
+
+			AuthorizationDetails: strings.TrimSpace(f.Get("authorization_details")),
+		},
+	}
+
+	resp, err := c.service.Push(ctx, req)
+	if err != nil {
+		c.tokens.handleServiceError(w, err, ctx)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
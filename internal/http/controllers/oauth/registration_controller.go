@@ -0,0 +1,145 @@
+// Package oauth - RegistrationController handles Dynamic Client Registration (RFC 7591/7592)
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+const registrationPath = "/oauth2/register"
+
+// RegistrationController handles /oauth2/register and /oauth2/register/{client_id}.
+type RegistrationController struct {
+	service svc.RegistrationService
+	tokens  *TokenController // reused for OAuth JSON error responses
+}
+
+// NewRegistrationController creates the controller.
+func NewRegistrationController(s svc.RegistrationService) *RegistrationController {
+	return &RegistrationController{service: s, tokens: &TokenController{}}
+}
+
+// Register handles POST /oauth2/register (RFC 7591 §3).
+// An initial access token, when the tenant requires one, is sent as a Bearer token.
+func (c *RegistrationController) Register(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("oauth.register"))
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		c.tokens.writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "Only POST method is allowed")
+		return
+	}
+
+	var md dto.ClientMetadata
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+	if err := json.NewDecoder(r.Body).Decode(&md); err != nil {
+		log.Debug("invalid registration body", logger.Err(err))
+		c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_client_metadata", "Invalid JSON body")
+		return
+	}
+
+	resp, err := c.service.Register(ctx, dto.ClientRegistrationRequest{
+		TenantSlug:  resolveTenantSlug(r),
+		BearerToken: helpers.GetBearerToken(r),
+		Metadata:    md,
+	})
+	if err != nil {
+		c.handleServiceError(w, r, err)
+		return
+	}
+	c.writeJSON(w, http.StatusCreated, resp)
+}
+
+// Manage handles GET, PUT and DELETE on the client configuration endpoint (RFC 7592 §2),
+// authorized by the registration_access_token issued at registration.
+func (c *RegistrationController) Manage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("oauth.register.manage"))
+
+	clientID := strings.TrimPrefix(r.URL.Path, registrationPath+"/")
+	if clientID == "" || strings.Contains(clientID, "/") {
+		c.tokens.writeOAuthError(w, http.StatusNotFound, "invalid_request", "Unknown client configuration endpoint")
+		return
+	}
+
+	req := dto.ClientRegistrationRequest{
+		TenantSlug:  resolveTenantSlug(r),
+		ClientID:    clientID,
+		BearerToken: helpers.GetBearerToken(r),
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		resp, err := c.service.Read(ctx, req)
+		if err != nil {
+			c.handleServiceError(w, r, err)
+			return
+		}
+		c.writeJSON(w, http.StatusOK, resp)
+
+	case http.MethodPut:
+		var body dto.ClientUpdateRequest
+		r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			log.Debug("invalid update body", logger.Err(err))
+			c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_client_metadata", "Invalid JSON body")
+			return
+		}
+		// client_id is required and must match the endpoint (RFC 7592 §2.2)
+		if body.ClientID != clientID {
+			c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_request", "client_id mismatch")
+			return
+		}
+		req.Metadata = body.ClientMetadata
+		resp, err := c.service.Update(ctx, req)
+		if err != nil {
+			c.handleServiceError(w, r, err)
+			return
+		}
+		c.writeJSON(w, http.StatusOK, resp)
+
+	case http.MethodDelete:
+		if err := c.service.Delete(ctx, req); err != nil {
+			c.handleServiceError(w, r, err)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		c.tokens.writeOAuthError(w, http.StatusMethodNotAllowed, "invalid_request", "Method not allowed")
+	}
+}
+
+func (c *RegistrationController) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case svc.ErrRegistrationDisabled:
+		c.tokens.writeOAuthError(w, http.StatusForbidden, "access_denied", "Client registration is not allowed")
+	case svc.ErrRegistrationInvalidToken:
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.tokens.writeOAuthError(w, http.StatusUnauthorized, "invalid_token", "Invalid or missing access token")
+	case svc.ErrInvalidRedirectURI:
+		c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_redirect_uri", "Invalid redirect_uris")
+	case svc.ErrInvalidClientMetadata:
+		c.tokens.writeOAuthError(w, http.StatusBadRequest, "invalid_client_metadata", "Invalid client metadata")
+	default:
+		logger.From(r.Context()).Error("client registration error", logger.Err(err))
+		c.tokens.writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal server error")
+	}
+}
+
+func (c *RegistrationController) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -17,12 +17,13 @@ const maxRevokeBodySize = 32 * 1024 // 32KB
 
 // RevokeController handles POST /oauth2/revoke.
 type RevokeController struct {
-	service svc.RevokeService
+	service    svc.RevokeService
+	assertions svc.ClientAuthService
 }
 
 // NewRevokeController creates a new revoke controller.
-func NewRevokeController(service svc.RevokeService) *RevokeController {
-	return &RevokeController{service: service}
+func NewRevokeController(service svc.RevokeService, assertions svc.ClientAuthService) *RevokeController {
+	return &RevokeController{service: service, assertions: assertions}
 }
 
 // Revoke handles the token revocation request.
@@ -49,6 +50,12 @@ func (c *RevokeController) Revoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Client assertion (RFC 7523), when sent, must verify
+	if !verifyRequestAssertion(r, c.assertions) {
+		httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("invalid client assertion"))
+		return
+	}
+
 	// Revoke the token (idempotent - always succeeds)
 	if err := c.service.Revoke(ctx, token); err != nil {
 		if err == svc.ErrRevokeTokenEmpty {
@@ -4,10 +4,13 @@ package oauth
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"net/http"
+	"net/url"
 	"strings"
 
 	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 
 	svc "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
@@ -15,16 +18,24 @@ import (
 
 // TokenController handles the OAuth2 token endpoint.
 type TokenController struct {
-	service svc.TokenService
+	service    svc.TokenService
+	dpop       svc.DPoPService
+	certHeader string
 }
 
 // NewTokenController creates the controller.
-func NewTokenController(s svc.TokenService) *TokenController {
-	return &TokenController{service: s}
+// dpop may be nil, in which case DPoP proofs are rejected.
+// certHeader names the header a TLS-terminating proxy forwards the client certificate in ("" = none).
+func NewTokenController(s svc.TokenService, dpop svc.DPoPService, certHeader string) *TokenController {
+	return &TokenController{service: s, dpop: dpop, certHeader: certHeader}
 }
 
 // Token handles POST /oauth2/token
-// Implements: Authorization Code (PKCE), Refresh Token, Client Credentials grants.
+// Implements: Authorization Code (PKCE), Refresh Token, Client Credentials, Device Code, Token Exchange grants.
+// Client authentication: client_secret_basic, client_secret_post, client_secret_jwt, private_key_jwt,
+// tls_client_auth, self_signed_tls_client_auth.
+// A DPoP header binds the issued tokens to the client's key (RFC 9449); a client
+// certificate binds them to the certificate (RFC 8705).
 func (c *TokenController) Token(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("oauth.token"))
@@ -52,18 +63,29 @@ func (c *TokenController) Token(w http.ResponseWriter, r *http.Request) {
 	// Resolve tenant slug from request (header/query)
 	tenantSlug := resolveTenantSlug(r)
 
+	dpopJKT, ok := c.verifyDPoP(w, r)
+	if !ok {
+		return
+	}
+
 	var resp *svc.TokenResponse
 	var err error
 
 	switch grantType {
 	case "authorization_code":
-		resp, err = c.handleAuthorizationCode(ctx, r, tenantSlug)
+		resp, err = c.handleAuthorizationCode(ctx, r, tenantSlug, dpopJKT)
 
 	case "refresh_token":
-		resp, err = c.handleRefreshToken(ctx, r, tenantSlug)
+		resp, err = c.handleRefreshToken(ctx, r, tenantSlug, dpopJKT)
 
 	case "client_credentials":
-		resp, err = c.handleClientCredentials(ctx, r, tenantSlug)
+		resp, err = c.handleClientCredentials(ctx, r, tenantSlug, dpopJKT)
+
+	case svc.GrantTypeDeviceCode:
+		resp, err = c.handleDeviceCode(ctx, r, tenantSlug, dpopJKT)
+
+	case svc.GrantTypeTokenExchange:
+		resp, err = c.handleTokenExchange(ctx, r, tenantSlug, dpopJKT)
 
 	default:
 		c.writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "Grant type not supported")
@@ -79,13 +101,8 @@ func (c *TokenController) Token(w http.ResponseWriter, r *http.Request) {
 	c.writeTokenResponse(w, resp)
 }
 
-func (c *TokenController) handleAuthorizationCode(ctx context.Context, r *http.Request, tenantSlug string) (*svc.TokenResponse, error) {
-	// Support client_secret_basic: extract client_id from Basic Auth header if present
-	basicID, _ := extractBasicAuth(r)
-	clientID := strings.TrimSpace(r.PostForm.Get("client_id"))
-	if clientID == "" && basicID != "" {
-		clientID = basicID
-	}
+func (c *TokenController) handleAuthorizationCode(ctx context.Context, r *http.Request, tenantSlug, dpopJKT string) (*svc.TokenResponse, error) {
+	clientID, _, assertion := clientAuthFromRequest(r)
 
 	req := svc.AuthCodeRequest{
 		Code:         strings.TrimSpace(r.PostForm.Get("code")),
@@ -93,50 +110,124 @@ func (c *TokenController) handleAuthorizationCode(ctx context.Context, r *http.R
 		ClientID:     clientID,
 		CodeVerifier: strings.TrimSpace(r.PostForm.Get("code_verifier")),
 		TenantSlug:   tenantSlug,
+		Assertion:    assertion,
+		DPoPJKT:      dpopJKT,
+		ClientCert:   helpers.ClientCertificate(r, c.certHeader),
+		Resource:     formResources(r.PostForm),
+
+		AuthorizationDetails: r.PostForm.Get("authorization_details"),
 	}
 	return c.service.ExchangeAuthorizationCode(ctx, req)
 }
 
-func (c *TokenController) handleRefreshToken(ctx context.Context, r *http.Request, tenantSlug string) (*svc.TokenResponse, error) {
-	// Support client_secret_basic: extract client_id from Basic Auth header if present
-	basicID, _ := extractBasicAuth(r)
-	clientID := strings.TrimSpace(r.PostForm.Get("client_id"))
-	if clientID == "" && basicID != "" {
-		clientID = basicID
-	}
+func (c *TokenController) handleRefreshToken(ctx context.Context, r *http.Request, tenantSlug, dpopJKT string) (*svc.TokenResponse, error) {
+	clientID, _, assertion := clientAuthFromRequest(r)
 
 	req := svc.RefreshTokenRequest{
 		ClientID:     clientID,
 		RefreshToken: strings.TrimSpace(r.PostForm.Get("refresh_token")),
 		TenantSlug:   tenantSlug,
+		Assertion:    assertion,
+		DPoPJKT:      dpopJKT,
+		ClientCert:   helpers.ClientCertificate(r, c.certHeader),
+		Resource:     formResources(r.PostForm),
+
+		AuthorizationDetails: r.PostForm.Get("authorization_details"),
 	}
 	return c.service.ExchangeRefreshToken(ctx, req)
 }
 
-func (c *TokenController) handleClientCredentials(ctx context.Context, r *http.Request, tenantSlug string) (*svc.TokenResponse, error) {
-	// Support client_secret_basic (RFC 6749 §2.3.1):
-	// Authorization: Basic base64(client_id:client_secret)
-	// Falls back to client_secret_post (form body) if no Basic header.
-	basicID, basicSecret := extractBasicAuth(r)
-
-	clientID := strings.TrimSpace(r.PostForm.Get("client_id"))
-	clientSecret := strings.TrimSpace(r.PostForm.Get("client_secret"))
-
-	// Basic Auth takes precedence when present
-	if basicID != "" {
-		clientID = basicID
-		clientSecret = basicSecret
-	}
+func (c *TokenController) handleClientCredentials(ctx context.Context, r *http.Request, tenantSlug, dpopJKT string) (*svc.TokenResponse, error) {
+	clientID, clientSecret, assertion := clientAuthFromRequest(r)
 
 	req := svc.ClientCredentialsRequest{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		Scope:        strings.TrimSpace(r.PostForm.Get("scope")),
 		TenantSlug:   tenantSlug,
+		Assertion:    assertion,
+		DPoPJKT:      dpopJKT,
+		ClientCert:   helpers.ClientCertificate(r, c.certHeader),
+		Resource:     formResources(r.PostForm),
+
+		AuthorizationDetails: r.PostForm.Get("authorization_details"),
 	}
 	return c.service.ExchangeClientCredentials(ctx, req)
 }
 
+func (c *TokenController) handleDeviceCode(ctx context.Context, r *http.Request, tenantSlug, dpopJKT string) (*svc.TokenResponse, error) {
+	clientID, clientSecret, assertion := clientAuthFromRequest(r)
+
+	req := svc.DeviceCodeRequest{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		DeviceCode:   strings.TrimSpace(r.PostForm.Get("device_code")),
+		TenantSlug:   tenantSlug,
+		Assertion:    assertion,
+		DPoPJKT:      dpopJKT,
+		ClientCert:   helpers.ClientCertificate(r, c.certHeader),
+	}
+	return c.service.ExchangeDeviceCode(ctx, req)
+}
+
+func (c *TokenController) handleTokenExchange(ctx context.Context, r *http.Request, tenantSlug, dpopJKT string) (*svc.TokenResponse, error) {
+	clientID, clientSecret, assertion := clientAuthFromRequest(r)
+
+	// audience and resource may both be repeated (RFC 8693 §2.1)
+	var audience []string
+	for _, v := range append(r.PostForm["audience"], r.PostForm["resource"]...) {
+		if v = strings.TrimSpace(v); v != "" {
+			audience = append(audience, v)
+		}
+	}
+
+	req := svc.TokenExchangeRequest{
+		ClientID:           clientID,
+		ClientSecret:       clientSecret,
+		SubjectToken:       strings.TrimSpace(r.PostForm.Get("subject_token")),
+		SubjectTokenType:   strings.TrimSpace(r.PostForm.Get("subject_token_type")),
+		ActorToken:         strings.TrimSpace(r.PostForm.Get("actor_token")),
+		ActorTokenType:     strings.TrimSpace(r.PostForm.Get("actor_token_type")),
+		Audience:           audience,
+		Scope:              strings.TrimSpace(r.PostForm.Get("scope")),
+		RequestedTokenType: strings.TrimSpace(r.PostForm.Get("requested_token_type")),
+		TenantSlug:         tenantSlug,
+		Assertion:          assertion,
+		DPoPJKT:            dpopJKT,
+		ClientCert:         helpers.ClientCertificate(r, c.certHeader),
+	}
+	return c.service.ExchangeToken(ctx, req)
+}
+
+// verifyDPoP validates the DPoP header, if any, and returns the key thumbprint.
+// On failure it writes the error response and returns ok=false.
+func (c *TokenController) verifyDPoP(w http.ResponseWriter, r *http.Request) (string, bool) {
+	proof, single := helpers.GetDPoPProof(r)
+	if !single {
+		c.writeOAuthError(w, http.StatusBadRequest, "invalid_dpop_proof", "Multiple DPoP proofs")
+		return "", false
+	}
+	if proof == "" {
+		return "", true
+	}
+	if c.dpop == nil {
+		c.writeOAuthError(w, http.StatusBadRequest, "invalid_dpop_proof", "DPoP is not supported")
+		return "", false
+	}
+
+	jkt, err := c.dpop.VerifyProof(r.Context(), proof, r.Method, helpers.RequestURL(r))
+	switch err {
+	case nil:
+		return jkt, true
+	case svc.ErrTokenUseDPoPNonce:
+		w.Header().Set("DPoP-Nonce", c.dpop.Nonce())
+		c.writeOAuthError(w, http.StatusBadRequest, "use_dpop_nonce", "Authorization server requires nonce in DPoP proof")
+	default:
+		c.writeOAuthError(w, http.StatusBadRequest, "invalid_dpop_proof", "Invalid DPoP proof")
+	}
+	return "", false
+}
+
 func (c *TokenController) handleServiceError(w http.ResponseWriter, err error, ctx context.Context) {
 	log := logger.From(ctx)
 	switch err {
@@ -152,6 +243,20 @@ func (c *TokenController) handleServiceError(w http.ResponseWriter, err error, c
 		c.writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "Grant type not supported")
 	case svc.ErrTokenInvalidScope:
 		c.writeOAuthError(w, http.StatusBadRequest, "invalid_scope", "Requested scope is invalid or not allowed")
+	case svc.ErrTokenInvalidTarget:
+		c.writeOAuthError(w, http.StatusBadRequest, "invalid_target", "Requested audience is invalid or not allowed")
+	case svc.ErrTokenInvalidRequestObject:
+		c.writeOAuthError(w, http.StatusBadRequest, "invalid_request_object", "Request object is invalid or required")
+	case svc.ErrTokenInvalidAuthorizationDetails:
+		c.writeOAuthError(w, http.StatusBadRequest, "invalid_authorization_details", "Authorization details are invalid or not allowed")
+	case svc.ErrTokenAuthorizationPending:
+		c.writeOAuthError(w, http.StatusBadRequest, "authorization_pending", "The user has not yet completed authorization")
+	case svc.ErrTokenSlowDown:
+		c.writeOAuthError(w, http.StatusBadRequest, "slow_down", "Polling too frequently, increase the interval by 5 seconds")
+	case svc.ErrTokenAccessDenied:
+		c.writeOAuthError(w, http.StatusBadRequest, "access_denied", "The user denied the authorization request")
+	case svc.ErrTokenExpiredToken:
+		c.writeOAuthError(w, http.StatusBadRequest, "expired_token", "The device_code has expired")
 	case svc.ErrTokenDBNotConfigured:
 		c.writeOAuthError(w, http.StatusServiceUnavailable, "server_error", "Database not configured")
 	default:
@@ -185,6 +290,14 @@ func (c *TokenController) writeTokenResponse(w http.ResponseWriter, resp *svc.To
 	if resp.Scope != "" {
 		out += `,"scope":"` + resp.Scope + `"`
 	}
+	if resp.IssuedTokenType != "" {
+		out += `,"issued_token_type":"` + resp.IssuedTokenType + `"`
+	}
+	if len(resp.AuthorizationDetails) > 0 {
+		if b, err := json.Marshal(resp.AuthorizationDetails); err == nil {
+			out += `,"authorization_details":` + string(b)
+		}
+	}
 	out += `}`
 	_, _ = w.Write([]byte(out))
 }
@@ -208,6 +321,53 @@ func extractBasicAuth(r *http.Request) (string, string) {
 	return parts[0], parts[1]
 }
 
+// clientAuthFromRequest collects client credentials from the request:
+// client_secret_basic (Basic header, takes precedence), client_secret_post (form)
+// and client assertions (private_key_jwt / client_secret_jwt, RFC 7523).
+// With an assertion, client_id is optional and defaults to the assertion "sub".
+func clientAuthFromRequest(r *http.Request) (clientID, clientSecret string, assertion svc.ClientAssertion) {
+	clientID = strings.TrimSpace(r.PostForm.Get("client_id"))
+	clientSecret = strings.TrimSpace(r.PostForm.Get("client_secret"))
+	if basicID, basicSecret := extractBasicAuth(r); basicID != "" {
+		clientID = basicID
+		clientSecret = basicSecret
+	}
+
+	assertion = svc.ClientAssertion{
+		Type:  strings.TrimSpace(r.PostForm.Get("client_assertion_type")),
+		Value: strings.TrimSpace(r.PostForm.Get("client_assertion")),
+	}
+	if clientID == "" {
+		clientID = svc.ClientIDFromAssertion(assertion.Value)
+	}
+	return clientID, clientSecret, assertion
+}
+
+// formResources returns the repeated resource parameter (RFC 8707 §2), trimmed.
+func formResources(values url.Values) []string {
+	var out []string
+	for _, v := range values["resource"] {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// verifyRequestAssertion verifies a client assertion sent to introspection or revocation.
+// Requests without an assertion pass; the form must already be parsed.
+func verifyRequestAssertion(r *http.Request, auth svc.ClientAuthService) bool {
+	clientID, _, assertion := clientAuthFromRequest(r)
+	if !assertion.Present() {
+		return true
+	}
+	if auth == nil {
+		return false
+	}
+	_, _, err := auth.Authenticate(r.Context(), resolveTenantSlug(r), clientID, assertion)
+	return err == nil
+}
+
 func resolveTenantSlug(r *http.Request) string {
 	// Check headers first (X-Tenant-Slug, X-Tenant-ID)
 	if slug := r.Header.Get("X-Tenant-Slug"); slug != "" {
@@ -42,16 +42,29 @@ func (c *UserInfoController) GetUserInfo(w http.ResponseWriter, r *http.Request)
 	resp, err := c.service.GetUserInfo(ctx, bearerToken)
 	if err != nil {
 		log.Debug("userinfo failed", logger.Err(err))
+		if errors.Is(err, svc.ErrResponseSecure) {
+			httperrors.WriteError(w, httperrors.ErrInternalServerError)
+			return
+		}
 		writeOIDCAuthError(w, "userinfo", "invalid_token", mapUserInfoError(err), http.StatusUnauthorized)
 		return
 	}
 
 	// Headers OIDC estándar
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.Header().Set("Cache-Control", "no-store")
 	w.Header().Set("Pragma", "no-cache")
 	w.Header().Add("Vary", "Authorization")
 
+	// Respuesta firmada y/o cifrada (OIDC Core §5.3.2)
+	if resp.JWT != "" {
+		w.Header().Set("Content-Type", "application/jwt")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(resp.JWT))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -60,10 +73,15 @@ func (c *UserInfoController) GetUserInfo(w http.ResponseWriter, r *http.Request)
 
 func extractBearerToken(r *http.Request) string {
 	ah := strings.TrimSpace(r.Header.Get("Authorization"))
-	if ah == "" || !strings.HasPrefix(strings.ToLower(ah), "bearer ") {
-		return ""
+	lower := strings.ToLower(ah)
+	switch {
+	case strings.HasPrefix(lower, "bearer "):
+		return strings.TrimSpace(ah[len("Bearer "):])
+	case strings.HasPrefix(lower, "dpop "):
+		// DPoP-bound token: el proof ya lo verificó RequireAuth
+		return strings.TrimSpace(ah[len("DPoP "):])
 	}
-	return strings.TrimSpace(ah[len("Bearer "):])
+	return ""
 }
 
 func writeOIDCAuthError(w http.ResponseWriter, realm, errorCode, errorDesc string, status int) {
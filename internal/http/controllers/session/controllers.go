@@ -16,12 +16,18 @@ type ControllerDeps struct {
 type Controllers struct {
 	Logout *SessionLogoutController
 	Login  *LoginController
+
+	Passwordless *PasswordlessController // nil si está deshabilitado
 }
 
 // NewControllers creates the session controllers aggregator.
 func NewControllers(s svc.Services, deps ControllerDeps) *Controllers {
-	return &Controllers{
+	c := &Controllers{
 		Logout: NewSessionLogoutController(s.Logout, deps.LogoutConfig),
 		Login:  NewLoginController(s.Login, deps.LoginConfig),
 	}
+	if s.Passwordless != nil {
+		c.Passwordless = NewPasswordlessController(s.Passwordless, s.Login, deps.LoginConfig)
+	}
+	return c
 }
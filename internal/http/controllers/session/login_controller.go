@@ -62,6 +62,10 @@ func (c *LoginController) Login(w http.ResponseWriter, r *http.Request) {
 			httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("password is required"))
 		case svc.ErrLoginInvalidCredentials:
 			httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("invalid credentials"))
+		case svc.ErrLoginUserDisabled:
+			httperrors.WriteError(w, httperrors.New(http.StatusLocked, "user_disabled", "user disabled"))
+		case svc.ErrLoginLocked:
+			httperrors.WriteError(w, httperrors.New(http.StatusLocked, "account_locked", "account temporarily locked after too many failed attempts"))
 		case svc.ErrLoginNoDatabase:
 			httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("database not available"))
 		case svc.ErrLoginSessionFailed:
@@ -0,0 +1,213 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
+	svc "github.com/dropDatabas3/hellojohn/internal/http/services/session"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// passwordlessCookie keeps the flow ID on the browser that started the login.
+	passwordlessCookie     = "hj_passwordless"
+	passwordlessCookiePath = "/v2/session/passwordless"
+)
+
+// PasswordlessController handles the /v2/session/passwordless endpoints.
+type PasswordlessController struct {
+	service svc.PasswordlessService
+	login   svc.LoginService
+	config  dto.LoginConfig
+}
+
+// NewPasswordlessController creates a new session passwordless controller.
+func NewPasswordlessController(service svc.PasswordlessService, login svc.LoginService, config dto.LoginConfig) *PasswordlessController {
+	return &PasswordlessController{
+		service: service,
+		login:   login,
+		config:  config,
+	}
+}
+
+// Start handles POST /v2/session/passwordless/start.
+// Sends the code or link and sets the flow cookie; return_to must be this
+// server's /oauth2/authorize.
+func (c *PasswordlessController) Start(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("PasswordlessController.Start"))
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var req dto.PasswordlessStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("invalid JSON"))
+		return
+	}
+	if req.ReturnTo != "" && !isAuthorizeURL(r, req.ReturnTo) {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("return_to must point to /oauth2/authorize"))
+		return
+	}
+	req.ClientIP = helpers.ClientIP(r)
+
+	started, err := c.service.Start(ctx, req)
+	if err != nil {
+		writePasswordlessError(w, err, log)
+		return
+	}
+
+	http.SetCookie(w, c.flowCookie(started.FlowID, started.ExpiresIn))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dto.PasswordlessStartResponse{
+		Method:    started.Method,
+		ExpiresIn: started.ExpiresIn,
+	})
+}
+
+// Verify handles POST /v2/session/passwordless/verify (email code).
+// Creates the session cookie; the page then resumes return_to.
+func (c *PasswordlessController) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("PasswordlessController.Verify"))
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var req dto.PasswordlessVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("invalid JSON"))
+		return
+	}
+
+	result, ok := c.verify(w, r, req.TenantID, passwordless.VerifyInput{Code: req.Code}, log)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dto.PasswordlessVerifyResponse{ReturnTo: result.ReturnTo})
+}
+
+// Link handles GET /v2/session/passwordless/link?token=...&tenant_id=...
+// (the magic link). Only works in the browser that started the flow.
+func (c *PasswordlessController) Link(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx).With(logger.Layer("controller"), logger.Op("PasswordlessController.Link"))
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	result, ok := c.verify(w, r, q.Get("tenant_id"), passwordless.VerifyInput{Token: q.Get("token")}, log)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	if result.ReturnTo == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, result.ReturnTo, http.StatusFound)
+}
+
+// verify completes the flow named by the cookie and sets the session cookie.
+func (c *PasswordlessController) verify(w http.ResponseWriter, r *http.Request, tenantID string, in passwordless.VerifyInput, log *zap.Logger) (*svc.PasswordlessResult, bool) {
+	ck, err := r.Cookie(passwordlessCookie)
+	if err != nil || ck.Value == "" {
+		// Link opened in another browser (or the flow cookie expired)
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "sign-in must be completed in the browser where it started"))
+		return nil, false
+	}
+	in.FlowID = ck.Value
+
+	result, err := c.service.Verify(r.Context(), tenantID, in)
+	if err != nil {
+		writePasswordlessError(w, err, log)
+		return nil, false
+	}
+
+	http.SetCookie(w, c.login.BuildSessionCookie(result.SessionID, c.config))
+	http.SetCookie(w, c.flowCookie("", -1))
+	log.Debug("passwordless session login successful")
+	return result, true
+}
+
+func (c *PasswordlessController) flowCookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     passwordlessCookie,
+		Value:    value,
+		Path:     passwordlessCookiePath,
+		Domain:   c.config.CookieDomain,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   c.config.Secure,
+		SameSite: http.SameSiteLaxMode, // the magic link is a top-level navigation from the mail client
+	}
+}
+
+// isAuthorizeURL reports whether raw is /oauth2/authorize on the host serving r,
+// so return_to can't be used as an open redirect.
+func isAuthorizeURL(r *http.Request, raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.User != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host) && u.Path == "/oauth2/authorize"
+}
+
+func writePasswordlessError(w http.ResponseWriter, err error, log *zap.Logger) {
+	switch {
+	case errors.Is(err, svc.ErrPasswordlessMissingFields):
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("tenant_id and client_id are required"))
+	case errors.Is(err, svc.ErrPasswordlessInvalidTenant), errors.Is(err, passwordless.ErrInvalidClient):
+		httperrors.WriteError(w, httperrors.ErrUnauthorized.WithDetail("invalid tenant or client"))
+	case errors.Is(err, passwordless.ErrNotEnabled):
+		httperrors.WriteError(w, httperrors.New(http.StatusForbidden, "passwordless_disabled", "passwordless login not enabled"))
+	case errors.Is(err, passwordless.ErrInvalidRequest):
+		httperrors.WriteError(w, httperrors.ErrBadRequest.WithDetail("invalid email, method or return_to"))
+	case errors.Is(err, passwordless.ErrInvalidCode):
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "code or link invalid or expired"))
+	case errors.Is(err, passwordless.ErrTooManyAttempts):
+		httperrors.WriteError(w, httperrors.New(http.StatusBadRequest, "invalid_grant", "too many failed attempts, request a new code"))
+	case errors.Is(err, passwordless.ErrRateLimited):
+		httperrors.WriteError(w, httperrors.ErrRateLimitExceeded.WithDetail("too many codes requested, try again later"))
+	case errors.Is(err, passwordless.ErrUserDisabled):
+		httperrors.WriteError(w, httperrors.New(http.StatusLocked, "user_disabled", "user disabled"))
+	case errors.Is(err, lockout.ErrLocked):
+		httperrors.WriteError(w, httperrors.New(http.StatusLocked, "account_locked", "account temporarily locked after too many failed attempts"))
+	case errors.Is(err, passwordless.ErrNoDatabase):
+		httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("database not available"))
+	case errors.Is(err, passwordless.ErrUnavailable):
+		httperrors.WriteError(w, httperrors.ErrServiceUnavailable.WithDetail("passwordless login unavailable"))
+	default:
+		log.Error("passwordless error", logger.Err(err))
+		httperrors.WriteError(w, httperrors.ErrInternalServerError)
+	}
+}
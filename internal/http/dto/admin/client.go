@@ -1,6 +1,8 @@
 // Package admin contiene DTOs para endpoints administrativos.
 package admin
 
+import "encoding/json"
+
 // ClientRequest representa la entrada para crear o actualizar un client.
 type ClientRequest struct {
 	Name                     string   `json:"name"`
@@ -22,6 +24,49 @@ type ClientRequest struct {
 	IDTokenTTL      int      `json:"id_token_ttl,omitempty"`
 	PostLogoutURIs  []string `json:"post_logout_uris,omitempty"`
 	Description     string   `json:"description,omitempty"`
+
+	// Token Exchange (RFC 8693)
+	TokenExchange *TokenExchangePolicy `json:"token_exchange,omitempty"`
+
+	// Autenticación en el token endpoint (RFC 7591 §2)
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty"`
+	JWKSURI                 string          `json:"jwks_uri,omitempty"`
+
+	// Mutual-TLS client authentication (RFC 8705 §2)
+	TLSClientAuthSubjectDN  string `json:"tls_client_auth_subject_dn,omitempty"`
+	TLSClientCertThumbprint string `json:"tls_client_auth_cert_thumbprint,omitempty"` // x5t#S256
+
+	// Pushed Authorization Requests (RFC 9126)
+	RequirePAR bool `json:"require_pushed_authorization_requests,omitempty"`
+
+	// ID Token signing algorithm (OIDC Registration §2); empty = tenant default
+	IDTokenSignedResponseAlg string `json:"id_token_signed_response_alg,omitempty"`
+
+	// First-party clients may skip the consent screen (tenant consent policy)
+	FirstParty bool `json:"first_party,omitempty"`
+
+	// OIDC Back-Channel / Front-Channel Logout 1.0
+	BackchannelLogoutURI              string `json:"backchannel_logout_uri,omitempty"`
+	BackchannelLogoutSessionRequired  bool   `json:"backchannel_logout_session_required,omitempty"`
+	FrontchannelLogoutURI             string `json:"frontchannel_logout_uri,omitempty"`
+	FrontchannelLogoutSessionRequired bool   `json:"frontchannel_logout_session_required,omitempty"`
+
+	// Subject identifiers: "public" (default) o "pairwise" (OIDC Core §8)
+	SubjectType         string `json:"subject_type,omitempty"`
+	SectorIdentifierURI string `json:"sector_identifier_uri,omitempty"`
+
+	// JWT-secured authorization requests (RFC 9101) y respuestas JARM
+	RequestObjectSigningAlg        string `json:"request_object_signing_alg,omitempty"`
+	RequireSignedRequestObject     bool   `json:"require_signed_request_object,omitempty"`
+	AuthorizationSignedResponseAlg string `json:"authorization_signed_response_alg,omitempty"`
+
+	// Respuestas cifradas (JWE) y userinfo firmado
+	IDTokenEncryptedResponseAlg  string `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEnc  string `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoSignedResponseAlg    string `json:"userinfo_signed_response_alg,omitempty"`
+	UserinfoEncryptedResponseAlg string `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc string `json:"userinfo_encrypted_response_enc,omitempty"`
 }
 
 // ClientResponse representa un client en la respuesta.
@@ -49,6 +94,57 @@ type ClientResponse struct {
 	IDTokenTTL      int      `json:"id_token_ttl,omitempty"`
 	PostLogoutURIs  []string `json:"post_logout_uris,omitempty"`
 	Description     string   `json:"description,omitempty"`
+
+	// Token Exchange (RFC 8693)
+	TokenExchange *TokenExchangePolicy `json:"token_exchange,omitempty"`
+
+	// Autenticación en el token endpoint (RFC 7591 §2)
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty"`
+	JWKSURI                 string          `json:"jwks_uri,omitempty"`
+
+	// Mutual-TLS client authentication (RFC 8705 §2)
+	TLSClientAuthSubjectDN  string `json:"tls_client_auth_subject_dn,omitempty"`
+	TLSClientCertThumbprint string `json:"tls_client_auth_cert_thumbprint,omitempty"` // x5t#S256
+
+	// Pushed Authorization Requests (RFC 9126)
+	RequirePAR bool `json:"require_pushed_authorization_requests,omitempty"`
+
+	// ID Token signing algorithm (OIDC Registration §2); empty = tenant default
+	IDTokenSignedResponseAlg string `json:"id_token_signed_response_alg,omitempty"`
+
+	// First-party clients may skip the consent screen (tenant consent policy)
+	FirstParty bool `json:"first_party,omitempty"`
+
+	// OIDC Back-Channel / Front-Channel Logout 1.0
+	BackchannelLogoutURI              string `json:"backchannel_logout_uri,omitempty"`
+	BackchannelLogoutSessionRequired  bool   `json:"backchannel_logout_session_required,omitempty"`
+	FrontchannelLogoutURI             string `json:"frontchannel_logout_uri,omitempty"`
+	FrontchannelLogoutSessionRequired bool   `json:"frontchannel_logout_session_required,omitempty"`
+
+	// Subject identifiers: "public" (default) o "pairwise" (OIDC Core §8)
+	SubjectType         string `json:"subject_type,omitempty"`
+	SectorIdentifierURI string `json:"sector_identifier_uri,omitempty"`
+
+	// JWT-secured authorization requests (RFC 9101) y respuestas JARM
+	RequestObjectSigningAlg        string `json:"request_object_signing_alg,omitempty"`
+	RequireSignedRequestObject     bool   `json:"require_signed_request_object,omitempty"`
+	AuthorizationSignedResponseAlg string `json:"authorization_signed_response_alg,omitempty"`
+
+	// Respuestas cifradas (JWE) y userinfo firmado
+	IDTokenEncryptedResponseAlg  string `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEnc  string `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoSignedResponseAlg    string `json:"userinfo_signed_response_alg,omitempty"`
+	UserinfoEncryptedResponseAlg string `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc string `json:"userinfo_encrypted_response_enc,omitempty"`
+}
+
+// TokenExchangePolicy restringe qué audiencias/scopes puede pedir un client vía token-exchange.
+type TokenExchangePolicy struct {
+	AllowedAudiences   []string `json:"allowed_audiences,omitempty"`
+	AllowedScopes      []string `json:"allowed_scopes,omitempty"`
+	AllowImpersonation bool     `json:"allow_impersonation,omitempty"`
+	AllowDelegation    bool     `json:"allow_delegation,omitempty"`
 }
 
 // StatusResponse es una respuesta genérica de estado.
@@ -0,0 +1,28 @@
+package admin
+
+// APIResourceRequest representa la entrada para crear/actualizar un API resource (RFC 8707).
+type APIResourceRequest struct {
+	Identifier     string   `json:"identifier"`
+	Name           string   `json:"name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
+	AccessTokenTTL int      `json:"access_token_ttl,omitempty"` // Segundos; 0 = TTL del client
+	SigningAlg     string   `json:"signing_alg,omitempty"`      // Vacío = alg del tenant
+
+	AccessTokenProfile string `json:"access_token_profile,omitempty"` // "rfc9068"; vacío = perfil del tenant
+}
+
+// APIResourceResponse representa un API resource en la respuesta.
+type APIResourceResponse struct {
+	ID             string   `json:"id"`
+	Identifier     string   `json:"identifier"`
+	Name           string   `json:"name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Scopes         []string `json:"scopes"`
+	AccessTokenTTL int      `json:"access_token_ttl,omitempty"`
+	SigningAlg     string   `json:"signing_alg,omitempty"`
+	CreatedAt      string   `json:"created_at,omitempty"`
+	UpdatedAt      string   `json:"updated_at,omitempty"`
+
+	AccessTokenProfile string `json:"access_token_profile,omitempty"`
+}
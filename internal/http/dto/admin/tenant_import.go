@@ -44,7 +44,8 @@ type ScopeImportData struct {
 }
 
 // UserImportData datos de usuario para import.
-// NOTA: No se importan passwords encriptados por seguridad.
+// NOTA: El export nunca incluye hashes de password; el import acepta hashes de
+// otros sistemas para migrar usuarios sin forzar un reset.
 type UserImportData struct {
 	Email         string                 `json:"email"`
 	Username      string                 `json:"username,omitempty"`
@@ -54,6 +55,13 @@ type UserImportData struct {
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	// SetPasswordOnImport: si true, se genera password temporal
 	SetPasswordOnImport bool `json:"set_password_on_import,omitempty"`
+
+	// Hash de password de otro sistema (bcrypt, PBKDF2, scrypt, SHA-crypt, argon2id).
+	// Se rehashea a argon2id en el primer login exitoso.
+	PasswordHash          string `json:"password_hash,omitempty"`
+	PasswordHashAlgorithm string `json:"password_hash_algorithm,omitempty"` // hint: "bcrypt" | "pbkdf2-sha256" | "scrypt" | "sha512-crypt" | ...
+	PasswordSalt          string `json:"password_salt,omitempty"`           // Keycloak: secretData.salt (base64)
+	PasswordIterations    int    `json:"password_iterations,omitempty"`     // Keycloak: credentialData.hashIterations
 }
 
 // RoleImportData datos de rol para import.
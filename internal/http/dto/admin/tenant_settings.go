@@ -5,12 +5,16 @@ package admin
 // Uses camelCase for consistency with the domain model and frontend.
 type TenantSettingsResponse struct {
 	// Core Settings
-	IssuerMode     string  `json:"issuerMode"`               // "path" | "subdomain" | "global"
-	IssuerOverride *string `json:"issuerOverride,omitempty"` // Custom issuer URL
+	IssuerMode       string  `json:"issuerMode"`                 // "path" | "subdomain" | "global"
+	IssuerOverride   *string `json:"issuerOverride,omitempty"`   // Custom issuer URL
+	SigningAlgorithm *string `json:"signingAlgorithm,omitempty"` // "EdDSA" (default) | "RS256" | "ES256"
+
+	AccessTokenProfile *string `json:"accessTokenProfile,omitempty"` // "" (default) | "rfc9068"
 
 	// Session Configuration
 	SessionLifetimeSeconds      int `json:"sessionLifetimeSeconds,omitempty"`
 	RefreshTokenLifetimeSeconds int `json:"refreshTokenLifetimeSeconds,omitempty"`
+	RefreshReuseGraceSeconds    int `json:"refreshReuseGraceSeconds,omitempty"`
 
 	// Feature Flags
 	MFAEnabled         bool `json:"mfaEnabled"`
@@ -35,8 +39,20 @@ type TenantSettingsResponse struct {
 	// Consent Policy
 	ConsentPolicy *ConsentPolicyDTO `json:"consentPolicy,omitempty"`
 
+	// Dynamic Client Registration (RFC 7591)
+	ClientRegistration *ClientRegistrationDTO `json:"clientRegistration,omitempty"`
+
 	// Custom User Fields
 	UserFields []UserFieldDefinition `json:"userFields,omitempty"`
+
+	// Rich Authorization Requests (RFC 9396)
+	AuthorizationDetailsTypes []AuthorizationDetailsTypeDTO `json:"authorizationDetailsTypes,omitempty"`
+
+	// Passwordless email login (magic link / OTP)
+	Passwordless *PasswordlessDTO `json:"passwordless,omitempty"`
+
+	// Phone verification and SMS/voice OTP factor
+	Phone *PhoneDTO `json:"phone,omitempty"`
 }
 
 // UserDBSettings configures the tenant's user database.
@@ -122,12 +138,16 @@ type EmailTemplateDTO struct {
 // Uses camelCase for consistency with the domain model and frontend.
 type UpdateTenantSettingsRequest struct {
 	// Core Settings
-	IssuerMode     *string `json:"issuerMode,omitempty"`
-	IssuerOverride *string `json:"issuerOverride,omitempty"`
+	IssuerMode       *string `json:"issuerMode,omitempty"`
+	IssuerOverride   *string `json:"issuerOverride,omitempty"`
+	SigningAlgorithm *string `json:"signingAlgorithm,omitempty"`
+
+	AccessTokenProfile *string `json:"accessTokenProfile,omitempty"`
 
 	// Session Configuration
 	SessionLifetimeSeconds      *int `json:"sessionLifetimeSeconds,omitempty"`
 	RefreshTokenLifetimeSeconds *int `json:"refreshTokenLifetimeSeconds,omitempty"`
+	RefreshReuseGraceSeconds    *int `json:"refreshReuseGraceSeconds,omitempty"`
 
 	// Feature Flags
 	MFAEnabled         *bool `json:"mfaEnabled,omitempty"`
@@ -152,8 +172,54 @@ type UpdateTenantSettingsRequest struct {
 	// Consent Policy
 	ConsentPolicy *ConsentPolicyDTO `json:"consentPolicy,omitempty"`
 
+	// Dynamic Client Registration (RFC 7591)
+	ClientRegistration *ClientRegistrationDTO `json:"clientRegistration,omitempty"`
+
 	// Custom User Fields
 	UserFields []UserFieldDefinition `json:"userFields,omitempty"`
+
+	// Rich Authorization Requests (RFC 9396)
+	AuthorizationDetailsTypes []AuthorizationDetailsTypeDTO `json:"authorizationDetailsTypes,omitempty"`
+
+	// Passwordless email login (magic link / OTP)
+	Passwordless *PasswordlessDTO `json:"passwordless,omitempty"`
+
+	// Phone verification and SMS/voice OTP factor
+	Phone *PhoneDTO `json:"phone,omitempty"`
+}
+
+// AuthorizationDetailsTypeDTO registers an authorization_details type and the
+// JSON Schema its objects must satisfy.
+type AuthorizationDetailsTypeDTO struct {
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"`
+}
+
+// PasswordlessDTO configures email-only login (magic link / one-time code).
+type PasswordlessDTO struct {
+	MagicLinkEnabled bool `json:"magicLinkEnabled"`
+	EmailOTPEnabled  bool `json:"emailOtpEnabled"`
+	AllowSignup      bool `json:"allowSignup"`           // create accounts for unknown emails
+	TTLSeconds       int  `json:"ttlSeconds,omitempty"`  // 0 = 10 minutes
+	MaxAttempts      int  `json:"maxAttempts,omitempty"` // 0 = 5
+}
+
+// PhoneDTO configures phone verification and the SMS/voice MFA factor.
+type PhoneDTO struct {
+	MFAEnabled      bool `json:"mfaEnabled"`
+	VoiceEnabled    bool `json:"voiceEnabled"`
+	MaxSendsPerHour int  `json:"maxSendsPerHour,omitempty"` // per number; 0 = 5
+
+	// Templates holds message texts per language: templates[lang]["sms_code" | "voice_code"].
+	Templates map[string]map[string]string `json:"templates,omitempty"`
+}
+
+// ClientRegistrationDTO configures the /oauth2/register endpoint.
+type ClientRegistrationDTO struct {
+	Mode                     string   `json:"mode"`                               // "disabled" | "open" | "initial_access_token"
+	InitialAccessTokens      []string `json:"initialAccessTokens,omitempty"`      // Plain tokens to add (only in requests)
+	InitialAccessTokenHashes []string `json:"initialAccessTokenHashes,omitempty"` // SHA-256 hashes of the accepted tokens
 }
 
 // ConsentPolicyDTO represents consent policy configuration.
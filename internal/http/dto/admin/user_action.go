@@ -1,6 +1,8 @@
 // Package admin contiene DTOs para endpoints administrativos.
 package admin
 
+import "time"
+
 // UserActionRequest representa la entrada para acciones admin sobre usuarios.
 type UserActionRequest struct {
 	UserID   string `json:"user_id"`
@@ -14,6 +16,21 @@ type UserActionResponse struct {
 	Status string `json:"status"`
 }
 
+// LockedAccountResponse representa una cuenta bloqueada por intentos fallidos.
+// UserID vacío = bloqueo por identificador (email sin cuenta asociada).
+type LockedAccountResponse struct {
+	UserID      string    `json:"user_id,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// ListLockedAccountsResponse para GET /v2/admin/tenants/{id}/users/locked
+type ListLockedAccountsResponse struct {
+	Accounts []LockedAccountResponse `json:"accounts"`
+	Total    int                     `json:"total"`
+}
+
 // SetEmailVerifiedRequest para marcar email como verificado manualmente.
 type SetEmailVerifiedRequest struct {
 	Verified bool `json:"verified"`
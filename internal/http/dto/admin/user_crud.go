@@ -45,6 +45,9 @@ type UserResponse struct {
 	DisabledReason *string        `json:"disabled_reason,omitempty"`
 	DisabledBy     *string        `json:"disabled_by,omitempty"`
 	CustomFields   map[string]any `json:"custom_fields,omitempty"`
+
+	PhoneNumber         string `json:"phone_number,omitempty"`
+	PhoneNumberVerified bool   `json:"phone_number_verified,omitempty"`
 }
 
 // ListUsersResponse para GET /v2/admin/tenants/{id}/users
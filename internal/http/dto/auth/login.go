@@ -23,6 +23,8 @@ type MFARequiredResponse struct {
 	MFARequired bool     `json:"mfa_required"`
 	MFAToken    string   `json:"mfa_token"`
 	AMR         []string `json:"amr"`
+
+	MFAMethods []string `json:"mfa_methods,omitempty"` // "totp", "webauthn"
 }
 
 // LoginResult es el resultado interno del service (tokens o MFA).
@@ -37,4 +39,5 @@ type LoginResult struct {
 	MFARequired bool
 	MFAToken    string
 	AMR         []string
+	MFAMethods  []string
 }
@@ -0,0 +1,39 @@
+// Package auth contains DTOs for the SMS / voice OTP factor endpoints.
+package auth
+
+// SMSEnrollRequest is the request for POST /v2/mfa/sms/enroll
+type SMSEnrollRequest struct {
+	PhoneNumber string `json:"phone_number"`      // E.164 (separators are ignored)
+	Channel     string `json:"channel,omitempty"` // "sms" (default) | "voice"
+}
+
+// SMSVerifyRequest is the request for POST /v2/mfa/sms/verify
+type SMSVerifyRequest struct {
+	Code string `json:"code"`
+}
+
+// SMSVerifyResponse is the response for POST /v2/mfa/sms/verify
+type SMSVerifyResponse struct {
+	PhoneNumber         string `json:"phone_number"`
+	PhoneNumberVerified bool   `json:"phone_number_verified"`
+}
+
+// SMSChallengeBeginRequest is the request for POST /v2/mfa/sms/challenge/begin
+type SMSChallengeBeginRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Channel  string `json:"channel,omitempty"` // "sms" (default) | "voice"
+}
+
+// SMSChallengeRequest is the request for POST /v2/mfa/sms/challenge
+type SMSChallengeRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// SMSCodeSentResponse reports where a code was sent (number masked).
+type SMSCodeSentResponse struct {
+	Sent      bool   `json:"sent"`
+	Channel   string `json:"channel"`
+	To        string `json:"to"`
+	ExpiresIn int    `json:"expires_in"`
+}
@@ -0,0 +1,33 @@
+// Package auth contains DTOs for passwordless (email) login endpoints.
+package auth
+
+// PasswordlessStartRequest is the request for POST /v2/auth/passwordless/start
+type PasswordlessStartRequest struct {
+	TenantID string `json:"tenant_id"`
+	ClientID string `json:"client_id"`
+	Email    string `json:"email"`
+	Method   string `json:"method"` // "otp" | "link"
+
+	// RedirectURI is where the magic link points (required for "link").
+	// The page there posts the token back together with the flow_id.
+	RedirectURI string `json:"redirect_uri,omitempty"`
+
+	ClientIP string `json:"-"` // From the request, for rate limiting
+}
+
+// PasswordlessStartResponse is the response for POST /v2/auth/passwordless/start.
+// The flow_id must stay on the device that started the login.
+type PasswordlessStartResponse struct {
+	FlowID    string `json:"flow_id"`
+	Method    string `json:"method"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// PasswordlessVerifyRequest is the request for POST /v2/auth/passwordless/verify
+type PasswordlessVerifyRequest struct {
+	TenantID string `json:"tenant_id"`
+	ClientID string `json:"client_id"`
+	FlowID   string `json:"flow_id"`
+	Code     string `json:"code,omitempty"`  // "otp"
+	Token    string `json:"token,omitempty"` // "link"
+}
@@ -0,0 +1,147 @@
+// Package auth contains DTOs for WebAuthn / passkey endpoints.
+package auth
+
+import "time"
+
+// Binary WebAuthn fields travel as base64url strings (PublicKeyCredential.toJSON()).
+
+// WebAuthnRP identifies the relying party.
+type WebAuthnRP struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebAuthnUser is the user entity bound to a new credential.
+type WebAuthnUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// WebAuthnCredentialParam is an accepted credential algorithm.
+type WebAuthnCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// WebAuthnCredentialDescriptor references an existing credential.
+type WebAuthnCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// WebAuthnAuthenticatorSelection constrains the authenticator used for registration.
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// WebAuthnCreationOptions maps to PublicKeyCredentialCreationOptions.
+type WebAuthnCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     WebAuthnRP                     `json:"rp"`
+	User                   WebAuthnUser                   `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+// WebAuthnRequestOptions maps to PublicKeyCredentialRequestOptions.
+type WebAuthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	Timeout          int64                          `json:"timeout"`
+	RPID             string                         `json:"rpId"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+// WebAuthnCreationOptionsResponse is the response for POST /v2/mfa/webauthn/register/begin
+type WebAuthnCreationOptionsResponse struct {
+	PublicKey WebAuthnCreationOptions `json:"publicKey"`
+}
+
+// WebAuthnRequestOptionsResponse is the response for the passkey login and MFA begin endpoints.
+type WebAuthnRequestOptionsResponse struct {
+	PublicKey WebAuthnRequestOptions `json:"publicKey"`
+}
+
+// WebAuthnAttestationCredential is the registration result of navigator.credentials.create().
+type WebAuthnAttestationCredential struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports,omitempty"`
+	} `json:"response"`
+}
+
+// WebAuthnAssertionCredential is the authentication result of navigator.credentials.get().
+type WebAuthnAssertionCredential struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// WebAuthnRegisterFinishRequest is the request for POST /v2/mfa/webauthn/register/finish
+type WebAuthnRegisterFinishRequest struct {
+	Name       string                        `json:"name,omitempty"`
+	Credential WebAuthnAttestationCredential `json:"credential"`
+}
+
+// WebAuthnMFABeginRequest is the request for POST /v2/mfa/webauthn/challenge/begin
+type WebAuthnMFABeginRequest struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+// WebAuthnMFAFinishRequest is the request for POST /v2/mfa/webauthn/challenge
+type WebAuthnMFAFinishRequest struct {
+	MFAToken   string                      `json:"mfa_token"`
+	Credential WebAuthnAssertionCredential `json:"credential"`
+}
+
+// PasskeyLoginBeginRequest is the request for POST /v2/auth/passkey/begin
+type PasskeyLoginBeginRequest struct {
+	TenantID string `json:"tenant_id"`
+	ClientID string `json:"client_id"`
+}
+
+// PasskeyLoginFinishRequest is the request for POST /v2/auth/passkey/finish
+type PasskeyLoginFinishRequest struct {
+	TenantID   string                      `json:"tenant_id"`
+	ClientID   string                      `json:"client_id"`
+	Credential WebAuthnAssertionCredential `json:"credential"`
+}
+
+// WebAuthnCredentialResponse describes a registered credential (no key material).
+type WebAuthnCredentialResponse struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	AAGUID         string     `json:"aaguid,omitempty"`
+	Transports     []string   `json:"transports,omitempty"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+}
+
+// WebAuthnCredentialsResponse is the response for GET /v2/mfa/webauthn/credentials
+type WebAuthnCredentialsResponse struct {
+	Credentials []WebAuthnCredentialResponse `json:"credentials"`
+}
+
+// WebAuthnRenameRequest is the request for PATCH /v2/mfa/webauthn/credentials/{id}
+type WebAuthnRenameRequest struct {
+	Name string `json:"name"`
+}
@@ -36,6 +36,18 @@ type ResetPasswordRequest struct {
 	NewPassword string `json:"new_password"`
 }
 
+// UnlockRequestRequest is the request for POST /v2/auth/unlock/request.
+type UnlockRequestRequest struct {
+	TenantID string `json:"tenant_id"`
+	Email    string `json:"email"`
+}
+
+// UnlockConfirmRequest is parsed from query params for GET /v2/auth/unlock.
+type UnlockConfirmRequest struct {
+	Token    string
+	TenantID string
+}
+
 // VerifyEmailResult contains the result of email verification.
 type VerifyEmailResult struct {
 	UserID   uuid.UUID
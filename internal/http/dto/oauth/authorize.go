@@ -13,7 +13,31 @@ type AuthorizeRequest struct {
 	Nonce               string `json:"nonce"`
 	CodeChallenge       string `json:"code_challenge"`
 	CodeChallengeMethod string `json:"code_challenge_method"`
-	Prompt              string `json:"prompt"` // e.g. "none"
+	Prompt              string `json:"prompt"`                // none | login | consent | select_account (space-separated)
+	RequestURI          string `json:"request_uri,omitempty"` // PAR reference (RFC 9126)
+
+	// OIDC Core §3.1.2.1 optional params
+	MaxAge      string `json:"max_age,omitempty"`       // seconds; 0 = same as prompt=login
+	LoginHint   string `json:"login_hint,omitempty"`    // prefills the login form
+	IDTokenHint string `json:"id_token_hint,omitempty"` // previously issued ID token of the expected user
+	UILocales   string `json:"ui_locales,omitempty"`    // space-separated BCP47 tags
+	ACRValues   string `json:"acr_values,omitempty"`    // space-separated, in order of preference
+
+	// Resource indicators of the APIs the tokens are for (RFC 8707)
+	Resource []string `json:"resource,omitempty"`
+
+	// AuthorizationDetails is the raw JSON array of fine-grained authorization
+	// requests (RFC 9396).
+	AuthorizationDetails string `json:"authorization_details,omitempty"`
+
+	// ResponseMode: query (default), fragment, form_post or their JARM variants
+	// query.jwt, fragment.jwt, form_post.jwt and jwt.
+	ResponseMode string `json:"response_mode,omitempty"`
+
+	// JWT-secured authorization request (RFC 9101): Request is the raw request object,
+	// RequestObject marks params that came from a verified one.
+	Request       string `json:"request,omitempty"`
+	RequestObject bool   `json:"request_object,omitempty"`
 }
 
 // AuthCodePayload is stored in cache when an auth code is issued.
@@ -28,7 +52,13 @@ type AuthCodePayload struct {
 	CodeChallenge   string    `json:"code_challenge"`
 	ChallengeMethod string    `json:"code_challenge_method"`
 	AMR             []string  `json:"amr"`
+	AuthTime        int64     `json:"auth_time,omitempty"` // unix time of the user's authentication
+	SessionID       string    `json:"sid,omitempty"`       // browser session (hash of the session cookie)
 	ExpiresAt       time.Time `json:"expires_at"`
+
+	Resource []string `json:"resource,omitempty"` // resource indicators granted (RFC 8707)
+
+	AuthorizationDetails []map[string]any `json:"authorization_details,omitempty"` // granted details (RFC 9396)
 }
 
 // MFAChallenge is stored in cache when MFA step-up is required.
@@ -52,6 +82,9 @@ type SessionPayload struct {
 	UserID   string    `json:"user_id"`
 	TenantID string    `json:"tenant_id"`
 	Expires  time.Time `json:"expires"`
+	AuthTime time.Time `json:"auth_time,omitempty"` // zero for sessions created before auth_time was tracked
+
+	AMR []string `json:"amr,omitempty"` // how the session was authenticated; empty means ["pwd"]
 }
 
 // AuthResultType indicates the outcome of the authorization request.
@@ -66,6 +99,8 @@ const (
 	AuthResultMFARequired
 	// AuthResultError - redirect with error params
 	AuthResultError
+	// AuthResultNeedConsent - redirect to consent UI
+	AuthResultNeedConsent
 )
 
 // AuthResult is the outcome from AuthorizeService.Authorize.
@@ -82,10 +117,18 @@ type AuthResult struct {
 	// For MFARequired
 	MFAToken string
 
+	// For NeedConsent
+	ConsentURL string
+
 	// For Error
 	ErrorCode        string
 	ErrorDescription string
 
 	// Common
 	RedirectURI string
+
+	// ResponseMode is how the response reaches the client: query, fragment or form_post.
+	// Response, when set, is the JARM JWT that replaces the code/state/error params.
+	ResponseMode string
+	Response     string
 }
@@ -6,6 +6,10 @@ import "time"
 type ConsentAcceptRequest struct {
 	Token   string `json:"consent_token"`
 	Approve bool   `json:"approve"`
+
+	// GrantedScopes is the subset the user accepted when the tenant consent mode
+	// is per_scope. Empty = all requested scopes. Ignored in single mode.
+	GrantedScopes []string `json:"granted_scopes,omitempty"`
 }
 
 // ConsentChallenge mimics the structure cached by Authorize handler in V1.
@@ -21,12 +25,37 @@ type ConsentChallenge struct {
 	CodeChallenge       string    `json:"code_challenge"`
 	CodeChallengeMethod string    `json:"code_challenge_method"`
 	AMR                 []string  `json:"amr"`
+	AuthTime            int64     `json:"auth_time,omitempty"`
+	SessionID           string    `json:"sid,omitempty"` // browser session the tokens are issued under
 	ExpiresAt           time.Time `json:"expires_at"`
+
+	// DeviceCodeHash is set when the consent comes from the device flow (RFC 8628).
+	// Approving marks the device authorization instead of issuing an auth code.
+	DeviceCodeHash string `json:"device_code_hash,omitempty"`
+
+	// DeniedScopes are the remembered denials used to pre-fill a per_scope consent screen.
+	DeniedScopes []string `json:"denied_scopes,omitempty"`
+
+	// Resource are the resource indicators of the authorization request (RFC 8707).
+	Resource []string `json:"resource,omitempty"`
+
+	// AuthorizationDetails of the authorization request (RFC 9396); approving
+	// the consent grants all of them.
+	AuthorizationDetails []map[string]any `json:"authorization_details,omitempty"`
+
+	// ResponseMode of the authorization request; the code is delivered the same way.
+	ResponseMode string `json:"response_mode,omitempty"`
 }
 
 // AuthCodeRedirect contains the result location for the client.
+// With a response mode other than query, the controller delivers Params to
+// RedirectURI itself (fragment redirect or form post) instead of using URL.
 type AuthCodeRedirect struct {
 	URL string
+
+	RedirectURI  string
+	ResponseMode string
+	Params       map[string]string
 }
 
 // ScopeDetail contains friendly scope information for consent screen display.
@@ -35,6 +64,8 @@ type ScopeDetail struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"display_name"`
 	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"` // cannot be denied (openid)
+	Denied      bool   `json:"denied,omitempty"`   // previously denied by the user
 }
 
 // ConsentInfoRequest is the input for GET /auth/consent/info.
@@ -48,4 +79,15 @@ type ConsentInfoResponse struct {
 	ClientName  string        `json:"client_name,omitempty"`
 	Scopes      []ScopeDetail `json:"scopes"`
 	RedirectURI string        `json:"redirect_uri"`
+	ConsentMode string        `json:"consent_mode,omitempty"` // "per_scope" lets the user pick scopes
+
+	AuthorizationDetails []AuthorizationDetailInfo `json:"authorization_details,omitempty"`
+}
+
+// AuthorizationDetailInfo is a requested authorization detail (RFC 9396) with
+// the description of its type for the consent screen.
+type AuthorizationDetailInfo struct {
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Detail      map[string]any `json:"detail"`
 }
@@ -0,0 +1,32 @@
+package oauth
+
+import "crypto/x509"
+
+// DeviceAuthorizationRequest is the input for POST /oauth2/device_authorization (RFC 8628 §3.1).
+type DeviceAuthorizationRequest struct {
+	ClientID            string `json:"client_id"`
+	ClientSecret        string `json:"client_secret,omitempty"`
+	ClientAssertionType string `json:"client_assertion_type,omitempty"`
+	ClientAssertion     string `json:"client_assertion,omitempty"`
+	Scope               string `json:"scope"`
+	TenantSlug          string `json:"-"`
+
+	ClientCert *x509.Certificate `json:"-"` // mTLS client certificate (RFC 8705), if presented
+}
+
+// DeviceAuthorizationResponse is the device authorization response (RFC 8628 §3.2).
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceVerifyResult is the outcome of GET /oauth2/device (user verification).
+// The browser is always redirected: to the code entry page, to login,
+// or to the consent screen.
+type DeviceVerifyResult struct {
+	RedirectURL string
+}
@@ -0,0 +1,33 @@
+package oauth
+
+// EndSessionRequest contains the parsed params for GET/POST /oauth2/logout
+// (OpenID Connect RP-Initiated Logout 1.0).
+type EndSessionRequest struct {
+	IDTokenHint           string `json:"id_token_hint"`
+	ClientID              string `json:"client_id"`
+	PostLogoutRedirectURI string `json:"post_logout_redirect_uri"`
+	State                 string `json:"state"`
+
+	// Confirm is the token from the logout confirmation page (no id_token_hint).
+	Confirm string `json:"logout_confirm"`
+}
+
+// EndSessionResult is the outcome from EndSessionService.EndSession.
+type EndSessionResult struct {
+	// RedirectURI is the validated post_logout_redirect_uri.
+	// Empty means the OP renders its own logged-out page.
+	RedirectURI string
+	State       string
+
+	// ClearCookie is the name of the session cookie to expire in the browser.
+	// Empty when the request carried no session cookie.
+	ClearCookie string
+
+	// ConfirmToken is set when the user must confirm the logout first (request
+	// without id_token_hint); nothing has been ended yet.
+	ConfirmToken string
+
+	// FrontchannelLogoutURIs are loaded in hidden iframes by the logged-out page
+	// (OIDC Front-Channel Logout 1.0) before redirecting to RedirectURI.
+	FrontchannelLogoutURIs []string
+}
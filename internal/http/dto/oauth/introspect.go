@@ -22,6 +22,10 @@ type IntrospectResponse struct {
 	Amr       any    `json:"amr,omitempty"` // []string or nil
 	Roles     any    `json:"roles,omitempty"`
 	Perms     any    `json:"perms,omitempty"`
+	Cnf       any    `json:"cnf,omitempty"` // {"jkt"} DPoP (RFC 9449 §6.2), {"x5t#S256"} mTLS (RFC 8705 §3.2)
+	Aud       any    `json:"aud,omitempty"` // string or []string (resource indicators, RFC 8707)
+
+	AuthorizationDetails []map[string]any `json:"authorization_details,omitempty"` // RFC 9396 §9.2
 }
 
 // IntrospectResult is the internal result from IntrospectService.
@@ -40,4 +44,9 @@ type IntrospectResult struct {
 	Amr       []string
 	Roles     []string
 	Perms     []string
+	DPoPJKT   string
+	X5TS256   string // certificate thumbprint of mTLS-bound tokens
+	Aud       []string
+
+	AuthorizationDetails []map[string]any
 }
@@ -0,0 +1,30 @@
+package oauth
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// PushedAuthorizationRequest is the input for POST /oauth2/par (RFC 9126 §2.1).
+type PushedAuthorizationRequest struct {
+	ClientID            string
+	ClientSecret        string
+	ClientAssertionType string
+	ClientAssertion     string
+	TenantSlug          string
+	ClientCert          *x509.Certificate // mTLS client certificate (RFC 8705), if presented
+	Authorize           AuthorizeRequest  // pushed authorization params
+}
+
+// PushedAuthorizationResponse is the PAR response (RFC 9126 §2.2).
+type PushedAuthorizationResponse struct {
+	RequestURI string `json:"request_uri"`
+	ExpiresIn  int64  `json:"expires_in"`
+}
+
+// PushedAuthorizationPayload is cached under the request_uri until it is used or expires.
+type PushedAuthorizationPayload struct {
+	Request   AuthorizeRequest `json:"request"`
+	TenantID  string           `json:"tenant_id"` // tenant slug
+	ExpiresAt time.Time        `json:"expires_at"`
+}
@@ -0,0 +1,67 @@
+package oauth
+
+import "encoding/json"
+
+// ClientMetadata is the client metadata accepted by /oauth2/register (RFC 7591 §2).
+// Fields the server doesn't store (client_uri, logo_uri, contacts, ...) are ignored.
+type ClientMetadata struct {
+	RedirectURIs             []string        `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod  string          `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes               []string        `json:"grant_types,omitempty"`
+	ResponseTypes            []string        `json:"response_types,omitempty"`
+	ClientName               string          `json:"client_name,omitempty"`
+	Scope                    string          `json:"scope,omitempty"`
+	JWKSURI                  string          `json:"jwks_uri,omitempty"`
+	JWKS                     json.RawMessage `json:"jwks,omitempty"`
+	PostLogoutRedirectURIs   []string        `json:"post_logout_redirect_uris,omitempty"`
+	TLSClientAuthSubjectDN   string          `json:"tls_client_auth_subject_dn,omitempty"`
+	RequirePAR               bool            `json:"require_pushed_authorization_requests,omitempty"`
+	IDTokenSignedResponseAlg string          `json:"id_token_signed_response_alg,omitempty"`
+
+	// OIDC Back-Channel / Front-Channel Logout 1.0 §2.2 / §2
+	BackchannelLogoutURI              string `json:"backchannel_logout_uri,omitempty"`
+	BackchannelLogoutSessionRequired  bool   `json:"backchannel_logout_session_required,omitempty"`
+	FrontchannelLogoutURI             string `json:"frontchannel_logout_uri,omitempty"`
+	FrontchannelLogoutSessionRequired bool   `json:"frontchannel_logout_session_required,omitempty"`
+
+	// Pairwise subject identifiers (OIDC Core §8, Registration §2)
+	SubjectType         string `json:"subject_type,omitempty"`
+	SectorIdentifierURI string `json:"sector_identifier_uri,omitempty"`
+
+	// JWT-secured authorization requests (RFC 9101) and JARM responses
+	RequestObjectSigningAlg        string `json:"request_object_signing_alg,omitempty"`
+	RequireSignedRequestObject     bool   `json:"require_signed_request_object,omitempty"`
+	AuthorizationSignedResponseAlg string `json:"authorization_signed_response_alg,omitempty"`
+
+	// Encrypted responses (JWE) and signed userinfo
+	IDTokenEncryptedResponseAlg  string `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEnc  string `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoSignedResponseAlg    string `json:"userinfo_signed_response_alg,omitempty"`
+	UserinfoEncryptedResponseAlg string `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc string `json:"userinfo_encrypted_response_enc,omitempty"`
+}
+
+// ClientUpdateRequest is the body of PUT /oauth2/register/{client_id} (RFC 7592 §2.2).
+type ClientUpdateRequest struct {
+	ClientID string `json:"client_id"`
+	ClientMetadata
+}
+
+// ClientRegistrationRequest is the service input for register, read, update and delete.
+type ClientRegistrationRequest struct {
+	TenantSlug  string
+	ClientID    string // from the registration_client_uri path (read/update/delete)
+	BearerToken string // initial access token (register) or registration access token (manage)
+	Metadata    ClientMetadata
+}
+
+// ClientRegistrationResponse is the client information response (RFC 7591 §3.2.1, RFC 7592 §3).
+type ClientRegistrationResponse struct {
+	ClientID                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty"` // 0 = never; only with client_secret
+	RegistrationAccessToken string `json:"registration_access_token"`
+	RegistrationClientURI   string `json:"registration_client_uri"`
+	ClientMetadata
+}
@@ -12,4 +12,12 @@ type UserInfoResponse struct {
 	Email         string         `json:"email,omitempty"`
 	EmailVerified bool           `json:"email_verified,omitempty"`
 	CustomFields  map[string]any `json:"custom_fields"`
+
+	// Scope "phone"
+	PhoneNumber         string `json:"phone_number,omitempty"`
+	PhoneNumberVerified *bool  `json:"phone_number_verified,omitempty"`
+
+	// JWT es la respuesta firmada y/o cifrada (application/jwt) cuando el client
+	// registró userinfo_signed_response_alg o userinfo_encrypted_response_alg.
+	JWT string `json:"-"`
 }
@@ -15,6 +15,9 @@ type SessionPayload struct {
 	UserID   string    `json:"user_id"`
 	TenantID string    `json:"tenant_id"`
 	Expires  time.Time `json:"expires"`
+	AuthTime time.Time `json:"auth_time"` // when the user authenticated (OIDC auth_time, max_age)
+
+	AMR []string `json:"amr,omitempty"` // authentication methods (empty = ["pwd"])
 }
 
 // LoginConfig contains configuration for session login.
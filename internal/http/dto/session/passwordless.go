@@ -0,0 +1,30 @@
+package session
+
+// PasswordlessStartRequest is the request for POST /v2/session/passwordless/start.
+// The login page sends it while handling /oauth2/authorize; the flow ID goes
+// into a cookie instead of the response.
+type PasswordlessStartRequest struct {
+	TenantID string `json:"tenant_id"`
+	ClientID string `json:"client_id"`
+	Email    string `json:"email"`
+	Method   string `json:"method"`    // "otp" | "link"
+	ReturnTo string `json:"return_to"` // /oauth2/authorize URL to resume after the link
+	ClientIP string `json:"-"`         // From the request, for rate limiting
+}
+
+// PasswordlessStartResponse is the response for POST /v2/session/passwordless/start.
+type PasswordlessStartResponse struct {
+	Method    string `json:"method"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// PasswordlessVerifyRequest is the request for POST /v2/session/passwordless/verify.
+type PasswordlessVerifyRequest struct {
+	TenantID string `json:"tenant_id"`
+	Code     string `json:"code"`
+}
+
+// PasswordlessVerifyResponse is the response for POST /v2/session/passwordless/verify.
+type PasswordlessVerifyResponse struct {
+	ReturnTo string `json:"return_to,omitempty"`
+}
@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ClientCertificate retorna el certificado del client (mutual TLS, RFC 8705).
+// Con TLS local usa sólo el cert de la conexión. Sin TLS local y si el request
+// viene de un proxy de confianza (FromTrustedProxy), lo toma del header que
+// agrega el proxy que termina TLS (PEM URL-encoded, PEM o DER en base64).
+// El proxy DEBE eliminar ese header de los requests entrantes.
+func ClientCertificate(r *http.Request, header string) *x509.Certificate {
+	if r.TLS != nil {
+		if len(r.TLS.PeerCertificates) > 0 {
+			return r.TLS.PeerCertificates[0]
+		}
+		return nil
+	}
+	if header == "" || !FromTrustedProxy(r) {
+		return nil
+	}
+	raw := strings.TrimSpace(r.Header.Get(header))
+	if raw == "" {
+		return nil
+	}
+	return parseForwardedCert(raw)
+}
+
+// parseForwardedCert acepta los formatos habituales de proxies
+// (nginx $ssl_client_escaped_cert, AWS ALB, Envoy, etc).
+func parseForwardedCert(raw string) *x509.Certificate {
+	if strings.Contains(raw, "%") {
+		if dec, err := url.QueryUnescape(raw); err == nil {
+			raw = dec
+		}
+	}
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil
+		}
+		return cert
+	}
+	der, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const certHeader = "X-Client-Cert"
+
+func testCert(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func certPEM(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func certRequest(remoteAddr, header string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "http://internal:8080/oauth2/token", nil)
+	r.RemoteAddr = remoteAddr
+	if header != "" {
+		r.Header.Set(certHeader, header)
+	}
+	return r
+}
+
+func TestClientCertificateFromTrustedProxy(t *testing.T) {
+	withTrustedProxies(t, "10.0.0.0/8")
+	cert := testCert(t, "client-a")
+
+	formats := map[string]string{
+		"escaped PEM": url.QueryEscape(certPEM(cert)),
+		"PEM":         certPEM(cert),
+		"base64 DER":  base64.StdEncoding.EncodeToString(cert.Raw),
+	}
+	for name, v := range formats {
+		got := ClientCertificate(certRequest("10.1.2.3:4000", v), certHeader)
+		if got == nil || !got.Equal(cert) {
+			t.Errorf("%s: certificate not parsed", name)
+		}
+	}
+
+	if got := ClientCertificate(certRequest("10.1.2.3:4000", "not a certificate"), certHeader); got != nil {
+		t.Error("garbage header parsed as a certificate")
+	}
+	if got := ClientCertificate(certRequest("10.1.2.3:4000", certPEM(cert)), ""); got != nil {
+		t.Error("certificate read with no header configured")
+	}
+}
+
+func TestClientCertificateIgnoresUntrustedHeader(t *testing.T) {
+	withTrustedProxies(t, "10.0.0.0/8")
+	cert := testCert(t, "client-a")
+
+	if got := ClientCertificate(certRequest("203.0.113.7:4000", certPEM(cert)), certHeader); got != nil {
+		t.Error("header from an untrusted peer accepted")
+	}
+
+	withTrustedProxies(t)
+	if got := ClientCertificate(certRequest("10.1.2.3:4000", certPEM(cert)), certHeader); got != nil {
+		t.Error("header accepted with no trusted proxies configured")
+	}
+}
+
+func TestClientCertificateDirectTLS(t *testing.T) {
+	withTrustedProxies(t, "10.0.0.0/8")
+	peer := testCert(t, "client-a")
+	forged := testCert(t, "client-b")
+
+	r := certRequest("10.1.2.3:4000", certPEM(forged))
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{peer}}
+	if got := ClientCertificate(r, certHeader); got == nil || !got.Equal(peer) {
+		t.Error("peer certificate not used")
+	}
+
+	// A TLS connection without a client certificate never falls back to the header
+	r.TLS = &tls.ConnectionState{}
+	if got := ClientCertificate(r, certHeader); got != nil {
+		t.Error("header used on a direct TLS connection")
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	got, err := ParseTrustedProxies(" 10.0.0.1, 192.168.0.0/16 ,::ffff:172.16.0.1,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.1/32", "192.168.0.0/16", "172.16.0.1/32"}
+	if len(got) != len(want) {
+		t.Fatalf("prefixes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("prefix %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"10.0.0", "10.0.0.0/33", "proxy.local"} {
+		if _, err := ParseTrustedProxies(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
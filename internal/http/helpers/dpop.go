@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DPoPHeader es el header que transporta el DPoP proof (RFC 9449 §4.1).
+const DPoPHeader = "DPoP"
+
+// GetDPoPProof retorna el único DPoP proof del request.
+// ok=false si viene más de uno (el request es inválido).
+func GetDPoPProof(r *http.Request) (proof string, ok bool) {
+	vals := r.Header.Values(DPoPHeader)
+	switch len(vals) {
+	case 0:
+		return "", true
+	case 1:
+		return strings.TrimSpace(vals[0]), true
+	default:
+		return "", false
+	}
+}
+
+// RequestURL reconstruye la URL absoluta del request sin query ni fragment,
+// tal como la ve el cliente (htu de DPoP). Respeta X-Forwarded-Proto/Host
+// sólo si el request viene de un proxy de confianza (FromTrustedProxy).
+func RequestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if FromTrustedProxy(r) {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+		}
+		if fh := r.Header.Get("X-Forwarded-Host"); fh != "" {
+			host = strings.TrimSpace(strings.Split(fh, ",")[0])
+		}
+	}
+	return scheme + "://" + host + r.URL.EscapedPath()
+}
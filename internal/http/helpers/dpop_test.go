@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+// withTrustedProxies sets TrustedProxies for the test.
+func withTrustedProxies(t *testing.T, cidrs ...string) {
+	t.Helper()
+	prev := TrustedProxies
+	TrustedProxies = nil
+	for _, c := range cidrs {
+		TrustedProxies = append(TrustedProxies, netip.MustParsePrefix(c))
+	}
+	t.Cleanup(func() { TrustedProxies = prev })
+}
+
+func forwardedRequest(remoteAddr string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "http://internal:8080/oauth2/token?x=1", nil)
+	r.RemoteAddr = remoteAddr
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "auth.example.com")
+	return r
+}
+
+func TestRequestURLHonorsTrustedProxy(t *testing.T) {
+	withTrustedProxies(t, "10.0.0.0/8")
+
+	if got := RequestURL(forwardedRequest("10.1.2.3:5000")); got != "https://auth.example.com/oauth2/token" {
+		t.Errorf("RequestURL via trusted proxy = %q", got)
+	}
+}
+
+func TestRequestURLIgnoresUntrustedForwardedHeaders(t *testing.T) {
+	withTrustedProxies(t, "10.0.0.0/8")
+	if got := RequestURL(forwardedRequest("203.0.113.7:5000")); got != "http://internal:8080/oauth2/token" {
+		t.Errorf("RequestURL from untrusted peer = %q", got)
+	}
+
+	withTrustedProxies(t)
+	if got := RequestURL(forwardedRequest("10.1.2.3:5000")); got != "http://internal:8080/oauth2/token" {
+		t.Errorf("RequestURL without trusted proxies = %q", got)
+	}
+}
+
+func TestRequestURLDirectTLS(t *testing.T) {
+	withTrustedProxies(t, "0.0.0.0/0")
+	r := httptest.NewRequest(http.MethodGet, "https://auth.example.com/userinfo", nil)
+	r.Header.Set("X-Forwarded-Host", "evil.example.com")
+	if got := RequestURL(r); got != "https://auth.example.com/userinfo" {
+		t.Errorf("RequestURL over direct TLS = %q", got)
+	}
+}
+
+func TestGetDPoPProof(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/oauth2/token", nil)
+	if p, ok := GetDPoPProof(r); !ok || p != "" {
+		t.Errorf("no header: %q, %v", p, ok)
+	}
+	r.Header.Set(DPoPHeader, " proof ")
+	if p, ok := GetDPoPProof(r); !ok || p != "proof" {
+		t.Errorf("one header: %q, %v", p, ok)
+	}
+	r.Header.Add(DPoPHeader, "other")
+	if _, ok := GetDPoPProof(r); ok {
+		t.Error("two DPoP headers accepted")
+	}
+}
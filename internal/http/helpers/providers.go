@@ -31,3 +31,21 @@ func IsPasswordProviderAllowed(providers []string) bool {
 func IsGoogleProviderAllowed(providers []string) bool {
 	return IsProviderAllowed(providers, "google")
 }
+
+// Providers de login sin password (ver TenantSettings.Passwordless).
+const (
+	ProviderEmailLink = "email_link"
+	ProviderEmailOTP  = "email_otp"
+)
+
+// IsProviderEnabled verifica si el client habilitó explícitamente un provider.
+// A diferencia de IsProviderAllowed, una lista vacía no habilita nada: se usa
+// para métodos opt-in como el login sin password.
+func IsProviderEnabled(providers []string, provider string) bool {
+	for _, p := range providers {
+		if strings.EqualFold(p, provider) {
+			return true
+		}
+	}
+	return false
+}
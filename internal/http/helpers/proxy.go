@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies son las redes de los proxies que terminan TLS delante del
+// server. Sólo a requests que llegan desde ellas se les creen los headers que
+// agrega el proxy (X-Forwarded-Proto/Host, cert del client). Vacío = ninguno.
+// Se configura al arrancar.
+var TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parsea una lista separada por comas de IPs o CIDRs.
+func ParseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.Contains(part, "/") {
+			p, err := netip.ParsePrefix(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", part, err)
+			}
+			out = append(out, p.Masked())
+			continue
+		}
+		ip, err := netip.ParseAddr(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", part, err)
+		}
+		ip = ip.Unmap()
+		out = append(out, netip.PrefixFrom(ip, ip.BitLen()))
+	}
+	return out, nil
+}
+
+// FromTrustedProxy indica si el request llegó por un proxy de confianza: sin
+// TLS local y desde una dirección de TrustedProxies.
+func FromTrustedProxy(r *http.Request) bool {
+	if r.TLS != nil || len(TrustedProxies) == 0 {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range TrustedProxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP devuelve la IP del cliente. X-Forwarded-For sólo se cree si el
+// request viene de un proxy de confianza; si no, se usa RemoteAddr.
+func ClientIP(r *http.Request) string {
+	if FromTrustedProxy(r) {
+		if xf := r.Header.Get("X-Forwarded-For"); xf != "" {
+			parts := strings.Split(xf, ",")
+			return strings.TrimSpace(parts[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	withTrustedProxies(t, "10.0.0.0/8", "::1/128")
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		tls        bool
+		want       string
+	}{
+		{"direct", "203.0.113.7:5000", "", false, "203.0.113.7"},
+		{"trusted proxy", "10.1.2.3:5000", "198.51.100.9, 10.1.2.3", false, "198.51.100.9"},
+		{"trusted IPv6 proxy", "[::1]:5000", "198.51.100.9", false, "198.51.100.9"},
+		{"trusted proxy without header", "10.1.2.3:5000", "", false, "10.1.2.3"},
+		{"untrusted peer spoofs the header", "203.0.113.7:5000", "198.51.100.9", false, "203.0.113.7"},
+		{"local TLS ignores the header", "10.1.2.3:5000", "198.51.100.9", true, "10.1.2.3"},
+		{"no port", "203.0.113.7", "", false, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := forwardedRequest(tt.remoteAddr)
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			if got := ClientIP(r); got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	withTrustedProxies(t)
+
+	r := forwardedRequest("10.1.2.3:5000")
+	r.Header.Set("X-Forwarded-For", "198.51.100.9")
+	if got := ClientIP(r); got != "10.1.2.3" {
+		t.Fatalf("ClientIP = %q, want RemoteAddr", got)
+	}
+}
@@ -0,0 +1,94 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/audit"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/store"
+	"go.uber.org/zap"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// REFRESH TOKEN REUSE DETECTION (OAuth 2.0 Security BCP §4.14)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// RefreshReuseReason es el motivo registrado en sesiones revocadas por reuso.
+const RefreshReuseReason = "refresh_token_reuse"
+
+// InRefreshReuseGrace indica si un refresh token revocado cae dentro de la
+// ventana de gracia del tenant (refresh concurrentes legítimos).
+func InRefreshReuseGrace(settings *repository.TenantSettings, rt *repository.RefreshToken, now time.Time) bool {
+	if settings == nil || settings.RefreshReuseGraceSeconds <= 0 || rt.RevokedAt == nil {
+		return false
+	}
+	grace := time.Duration(settings.RefreshReuseGraceSeconds) * time.Second
+	return now.Sub(*rt.RevokedAt) < grace
+}
+
+// HandleRefreshTokenReuse procesa la presentación de un refresh token ya revocado.
+// Sólo un token reemplazado por rotación (con sucesor en la familia) y fuera de
+// la ventana de gracia cuenta como reuso: revoca toda la familia de rotación,
+// termina la sesión ligada a ella y emite un evento de auditoría. Los tokens
+// revocados por logout o por un admin no tienen sucesor y sólo se rechazan.
+// Retorna true si se detectó reuso.
+func HandleRefreshTokenReuse(ctx context.Context, tda store.TenantDataAccess, rt *repository.RefreshToken) bool {
+	if rt == nil || rt.RevokedAt == nil {
+		return false
+	}
+	log := logger.From(ctx).With(
+		logger.Component("refresh.reuse"),
+		logger.TenantSlug(tda.Slug()),
+		logger.UserID(rt.UserID),
+	)
+
+	if InRefreshReuseGrace(tda.Settings(), rt, time.Now()) {
+		log.Debug("revoked refresh token presented within grace window")
+		return false
+	}
+
+	rotated, err := tda.Tokens().HasSuccessor(ctx, rt.ID)
+	if err != nil {
+		log.Warn("failed to check refresh token rotation", logger.Err(err))
+		return false
+	}
+	if !rotated {
+		return false
+	}
+
+	revoked, err := tda.Tokens().RevokeFamily(ctx, rt.ID)
+	if err != nil {
+		log.Error("failed to revoke refresh token family", logger.Err(err))
+	}
+
+	sessions := 0
+	if rt.SessionID != "" && tda.Sessions() != nil {
+		// revoked_by es UUID en PG: se registra el titular de la cuenta
+		if err := tda.Sessions().Revoke(ctx, rt.SessionID, rt.UserID, RefreshReuseReason); err != nil {
+			if !repository.IsNotFound(err) {
+				log.Warn("failed to revoke session", logger.Err(err))
+			}
+		} else {
+			sessions = 1
+		}
+	}
+
+	log.Warn("refresh token reuse detected",
+		zap.String("token_id", rt.ID),
+		zap.Int("family_revoked", revoked),
+		zap.Int("sessions_revoked", sessions),
+	)
+
+	audit.Log(ctx, "refresh_token_reuse", map[string]any{
+		"tenant":           tda.Slug(),
+		"tenant_id":        tda.ID(),
+		"client_id":        rt.ClientID,
+		"user_id":          rt.UserID,
+		"token_id":         rt.ID,
+		"family_revoked":   revoked,
+		"sessions_revoked": sessions,
+	})
+	return true
+}
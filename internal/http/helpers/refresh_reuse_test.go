@@ -0,0 +1,146 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// familyTokens es un TokenRepository en memoria con las relaciones rotated_from.
+type familyTokens struct {
+	repository.TokenRepository
+	tokens map[string]*repository.RefreshToken
+}
+
+func (f *familyTokens) HasSuccessor(_ context.Context, id string) (bool, error) {
+	for _, t := range f.tokens {
+		if t.RotatedFrom != nil && *t.RotatedFrom == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *familyTokens) RevokeFamily(_ context.Context, id string) (int, error) {
+	root := id
+	for f.tokens[root].RotatedFrom != nil {
+		root = *f.tokens[root].RotatedFrom
+	}
+	now := time.Now()
+	n := 0
+	var revoke func(string)
+	revoke = func(id string) {
+		if t := f.tokens[id]; t.RevokedAt == nil {
+			t.RevokedAt = &now
+			n++
+		}
+		for cid, c := range f.tokens {
+			if c.RotatedFrom != nil && *c.RotatedFrom == id {
+				revoke(cid)
+			}
+		}
+	}
+	revoke(root)
+	return n, nil
+}
+
+type revokedSessions struct {
+	repository.SessionRepository
+	revoked map[string]string // session id hash -> reason
+}
+
+func (s *revokedSessions) Revoke(_ context.Context, id, _, reason string) error {
+	s.revoked[id] = reason
+	return nil
+}
+
+type reuseTDA struct {
+	store.TenantDataAccess
+	settings repository.TenantSettings
+	tokens   *familyTokens
+	sessions *revokedSessions
+}
+
+func (t *reuseTDA) Slug() string                           { return "acme" }
+func (t *reuseTDA) ID() string                             { return "tenant-1" }
+func (t *reuseTDA) Settings() *repository.TenantSettings   { return &t.settings }
+func (t *reuseTDA) Tokens() repository.TokenRepository     { return t.tokens }
+func (t *reuseTDA) Sessions() repository.SessionRepository { return t.sessions }
+
+// newRotatedFamily arma rt1 -> rt2 -> rt3, con rt1 y rt2 ya rotados y otra
+// familia (other) del mismo usuario que no debe tocarse.
+func newRotatedFamily(revokedAgo time.Duration) *reuseTDA {
+	revokedAt := time.Now().Add(-revokedAgo)
+	ptr := func(s string) *string { return &s }
+	tokens := map[string]*repository.RefreshToken{
+		"rt1":   {ID: "rt1", UserID: "user-1", SessionID: "sess-1", RevokedAt: &revokedAt},
+		"rt2":   {ID: "rt2", UserID: "user-1", SessionID: "sess-1", RotatedFrom: ptr("rt1"), RevokedAt: &revokedAt},
+		"rt3":   {ID: "rt3", UserID: "user-1", SessionID: "sess-1", RotatedFrom: ptr("rt2")},
+		"other": {ID: "other", UserID: "user-1", SessionID: "sess-2"},
+	}
+	return &reuseTDA{
+		tokens:   &familyTokens{tokens: tokens},
+		sessions: &revokedSessions{revoked: map[string]string{}},
+	}
+}
+
+func TestHandleRefreshTokenReuseRevokesFamily(t *testing.T) {
+	tda := newRotatedFamily(time.Minute)
+
+	if !HandleRefreshTokenReuse(context.Background(), tda, tda.tokens.tokens["rt1"]) {
+		t.Fatal("reuse of a rotated token not detected")
+	}
+	if tda.tokens.tokens["rt3"].RevokedAt == nil {
+		t.Error("active descendant not revoked")
+	}
+	if tda.tokens.tokens["other"].RevokedAt != nil {
+		t.Error("token of another family revoked")
+	}
+	if tda.sessions.revoked["sess-1"] != RefreshReuseReason {
+		t.Errorf("session of the family not revoked: %v", tda.sessions.revoked)
+	}
+	if _, ok := tda.sessions.revoked["sess-2"]; ok {
+		t.Error("unrelated session revoked")
+	}
+}
+
+func TestHandleRefreshTokenReuseIgnoresLogoutRevocation(t *testing.T) {
+	tda := newRotatedFamily(time.Minute)
+	// rt3 fue revocado por logout: no tiene sucesor
+	revokedAt := time.Now().Add(-time.Minute)
+	tda.tokens.tokens["rt3"].RevokedAt = &revokedAt
+
+	if HandleRefreshTokenReuse(context.Background(), tda, tda.tokens.tokens["rt3"]) {
+		t.Fatal("token without successor treated as reuse")
+	}
+	if len(tda.sessions.revoked) != 0 {
+		t.Errorf("sessions revoked: %v", tda.sessions.revoked)
+	}
+}
+
+func TestHandleRefreshTokenReuseGrace(t *testing.T) {
+	tda := newRotatedFamily(2 * time.Second)
+	tda.settings.RefreshReuseGraceSeconds = 10
+
+	if HandleRefreshTokenReuse(context.Background(), tda, tda.tokens.tokens["rt2"]) {
+		t.Fatal("concurrent refresh within the grace window treated as reuse")
+	}
+	if tda.tokens.tokens["rt3"].RevokedAt != nil {
+		t.Error("family revoked within the grace window")
+	}
+
+	tda.settings.RefreshReuseGraceSeconds = 1
+	if !HandleRefreshTokenReuse(context.Background(), tda, tda.tokens.tokens["rt2"]) {
+		t.Fatal("reuse after the grace window not detected")
+	}
+}
+
+func TestHandleRefreshTokenReuseActiveToken(t *testing.T) {
+	tda := newRotatedFamily(time.Minute)
+	if HandleRefreshTokenReuse(context.Background(), tda, tda.tokens.tokens["rt3"]) || HandleRefreshTokenReuse(context.Background(), tda, nil) {
+		t.Fatal("active or missing token treated as reuse")
+	}
+}
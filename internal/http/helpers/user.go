@@ -1,9 +1,12 @@
 package helpers
 
 import (
+	"context"
 	"time"
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/password"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -40,3 +43,27 @@ func IsUserEmailVerified(user *repository.User) bool {
 	}
 	return user.EmailVerified
 }
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PASSWORD HASH HELPERS
+// ─────────────────────────────────────────────────────────────────────────────
+
+// UpgradePasswordHash reemplaza por argon2id un hash importado de otro sistema
+// (bcrypt, PBKDF2, scrypt, SHA-crypt) después de un login exitoso con plain.
+// Best-effort: si falla se loguea y el login sigue con el hash viejo.
+func UpgradePasswordHash(ctx context.Context, users repository.UserRepository, userID string, hash *string, plain string) {
+	if hash == nil || !password.NeedsRehash(*hash) {
+		return
+	}
+	log := logger.From(ctx).With(logger.UserID(userID), logger.String("from", password.Identify(*hash)))
+
+	newHash, err := password.Hash(password.Default, plain)
+	if err == nil {
+		err = users.UpdatePasswordHash(ctx, userID, newHash)
+	}
+	if err != nil {
+		log.Warn("password rehash failed", logger.Err(err))
+		return
+	}
+	log.Info("password hash upgraded to argon2id")
+}
@@ -1,10 +1,13 @@
 package middlewares
 
 import (
+	"crypto/subtle"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/dropDatabas3/hellojohn/internal/http/errors"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 )
 
@@ -12,22 +15,41 @@ import (
 // AUTHENTICATION MIDDLEWARES
 // =================================================================================
 
-// RequireAuth valida Authorization: Bearer <JWT> y guarda las claims en el contexto.
+// RequireAuth valida Authorization: Bearer <JWT> (o DPoP <JWT>) y guarda las claims en el contexto.
+// Los tokens ligados a una clave DPoP (cnf.jkt) exigen el esquema DPoP y un proof válido;
+// los ligados a un certificado (cnf.x5t#S256) exigen el mismo cert de client (mTLS, RFC 8705).
 // Si el token es inválido o no está presente, responde 401.
 func RequireAuth(issuer *jwtx.Issuer) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ah := strings.TrimSpace(r.Header.Get("Authorization"))
-			if ah == "" || !strings.HasPrefix(strings.ToLower(ah), "bearer ") {
+			scheme, raw := authorizationToken(r)
+			if raw == "" {
 				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="missing bearer token"`)
 				errors.WriteError(w, errors.ErrTokenMissing)
 				return
 			}
-			raw := strings.TrimSpace(ah[len("Bearer "):])
 
 			claims, err := jwtx.ParseEdDSA(raw, issuer.Keys, issuer.Iss)
 			if err != nil {
-				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="`+err.Error()+`"`)
+				w.Header().Set("WWW-Authenticate", scheme+` realm="api", error="invalid_token", error_description="`+err.Error()+`"`)
+				errors.WriteError(w, errors.ErrTokenInvalid.WithDetail(err.Error()))
+				return
+			}
+
+			if issuer.IsRevoked(r.Context(), claims) {
+				w.Header().Set("WWW-Authenticate", scheme+` realm="api", error="invalid_token", error_description="token revoked"`)
+				errors.WriteError(w, errors.ErrTokenInvalid.WithDetail("token revoked"))
+				return
+			}
+
+			if err := verifyDPoPBinding(r, issuer, claims, scheme, raw); err != nil {
+				w.Header().Set("WWW-Authenticate", `DPoP realm="api", error="invalid_token", error_description="`+err.Error()+`", algs="`+strings.Join(jwtx.DPoPAlgs, " ")+`"`)
+				errors.WriteError(w, errors.ErrTokenInvalid.WithDetail(err.Error()))
+				return
+			}
+
+			if err := verifyCertificateBinding(r, claims); err != nil {
+				w.Header().Set("WWW-Authenticate", scheme+` realm="api", error="invalid_token", error_description="`+err.Error()+`"`)
 				errors.WriteError(w, errors.ErrTokenInvalid.WithDetail(err.Error()))
 				return
 			}
@@ -50,16 +72,21 @@ func RequireAuth(issuer *jwtx.Issuer) Middleware {
 func OptionalAuth(issuer *jwtx.Issuer) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ah := strings.TrimSpace(r.Header.Get("Authorization"))
-			if ah == "" || !strings.HasPrefix(strings.ToLower(ah), "bearer ") {
+			scheme, raw := authorizationToken(r)
+			if raw == "" {
 				// No hay token, continuar sin claims
 				next.ServeHTTP(w, r)
 				return
 			}
-			raw := strings.TrimSpace(ah[len("Bearer "):])
 
 			claims, err := jwtx.ParseEdDSA(raw, issuer.Keys, issuer.Iss)
-			if err != nil {
+			if err == nil {
+				err = verifyDPoPBinding(r, issuer, claims, scheme, raw)
+			}
+			if err == nil {
+				err = verifyCertificateBinding(r, claims)
+			}
+			if err != nil || issuer.IsRevoked(r.Context(), claims) {
 				// Token inválido pero opcional, continuar sin claims
 				next.ServeHTTP(w, r)
 				return
@@ -89,3 +116,90 @@ func RequireUser() Middleware {
 		})
 	}
 }
+
+// =================================================================================
+// DPoP (RFC 9449)
+// =================================================================================
+
+// dpopReplay recuerda los jti de DPoP proofs cuando el issuer no resuelve el
+// cache del tenant (sin él, cada instancia tendría su propia memoria).
+var dpopReplay = jwtx.NewMemoryDPoPReplay()
+
+// authorizationToken extrae el token de Authorization: Bearer|DPoP <token>.
+// Retorna el esquema normalizado ("Bearer" o "DPoP") y el token ("" si no hay).
+func authorizationToken(r *http.Request) (scheme, token string) {
+	ah := strings.TrimSpace(r.Header.Get("Authorization"))
+	lower := strings.ToLower(ah)
+	switch {
+	case strings.HasPrefix(lower, "bearer "):
+		return "Bearer", strings.TrimSpace(ah[len("Bearer "):])
+	case strings.HasPrefix(lower, "dpop "):
+		return "DPoP", strings.TrimSpace(ah[len("DPoP "):])
+	}
+	return "Bearer", ""
+}
+
+// verifyDPoPBinding exige, para tokens con cnf.jkt, el esquema DPoP y un proof
+// firmado con la misma clave, ligado a este request y al token (ath).
+// Los jti de los proofs se recuerdan en el cache del tenant del token.
+func verifyDPoPBinding(r *http.Request, issuer *jwtx.Issuer, claims map[string]any, scheme, token string) error {
+	jkt := jwtx.ConfirmationJKT(claims)
+	if jkt == "" {
+		if scheme == "DPoP" {
+			return fmt.Errorf("token is not DPoP-bound")
+		}
+		return nil
+	}
+	if scheme != "DPoP" {
+		return fmt.Errorf("DPoP-bound token requires DPoP authorization scheme")
+	}
+
+	proof, single := helpers.GetDPoPProof(r)
+	if !single || proof == "" {
+		return fmt.Errorf("missing DPoP proof")
+	}
+	var replay jwtx.DPoPReplayCache = dpopReplay
+	if tid, _ := claims["tid"].(string); tid != "" {
+		if c := issuer.DPoPReplayFor(r.Context(), tid); c != nil {
+			replay = c
+		}
+	}
+	p, err := jwtx.VerifyDPoPProof(proof, jwtx.DPoPVerifyOptions{
+		Method:      r.Method,
+		URL:         helpers.RequestURL(r),
+		AccessToken: token,
+		Replay:      replay,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid DPoP proof")
+	}
+	if p.JKT != jkt {
+		return fmt.Errorf("DPoP key mismatch")
+	}
+	return nil
+}
+
+// =================================================================================
+// MUTUAL TLS (RFC 8705)
+// =================================================================================
+
+// ClientCertHeader es el header con el cert del client que reenvía un proxy
+// que termina TLS ("" = ninguno). Se configura al arrancar.
+var ClientCertHeader string
+
+// verifyCertificateBinding exige, para tokens con cnf."x5t#S256", que el request
+// presente el certificado al que se ligó el token (RFC 8705 §3).
+func verifyCertificateBinding(r *http.Request, claims map[string]any) error {
+	x5t := jwtx.ConfirmationX5T(claims)
+	if x5t == "" {
+		return nil
+	}
+	cert := helpers.ClientCertificate(r, ClientCertHeader)
+	if cert == nil {
+		return fmt.Errorf("certificate-bound token requires the client certificate")
+	}
+	if subtle.ConstantTimeCompare([]byte(x5t), []byte(jwtx.CertificateThumbprint(cert))) != 1 {
+		return fmt.Errorf("client certificate mismatch")
+	}
+	return nil
+}
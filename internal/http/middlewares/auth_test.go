@@ -0,0 +1,171 @@
+package middlewares
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+const testResource = "https://api.example.com/v2/me"
+
+// dpopKey returns a proof key and its JWK thumbprint (the token's cnf.jkt).
+func dpopKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jkt, err := publicJWK(key).Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, jkt
+}
+
+func publicJWK(key *ecdsa.PrivateKey) *jwtx.JWK {
+	return &jwtx.JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+// dpopRequest builds GET testResource with a proof for token signed by key.
+func dpopRequest(t *testing.T, key *ecdsa.PrivateKey, scheme, token string) *http.Request {
+	t.Helper()
+	sum := sha256.Sum256([]byte(token))
+	jwk := publicJWK(key)
+	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodES256, jwtv5.MapClaims{
+		"jti": jwtx.NewJTI(),
+		"htm": http.MethodGet,
+		"htu": testResource,
+		"iat": time.Now().Unix(),
+		"ath": base64.RawURLEncoding.EncodeToString(sum[:]),
+	})
+	tk.Header["typ"] = jwtx.DPoPProofType
+	tk.Header["jwk"] = map[string]any{"kty": jwk.Kty, "crv": jwk.Crv, "x": jwk.X, "y": jwk.Y}
+	proof, err := tk.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, testResource, nil)
+	r.Header.Set("Authorization", scheme+" "+token)
+	r.Header.Set("DPoP", proof)
+	return r
+}
+
+func TestVerifyDPoPBinding(t *testing.T) {
+	key, jkt := dpopKey(t)
+	claims := map[string]any{"sub": "user-1", "cnf": map[string]any{"jkt": jkt}}
+
+	r := dpopRequest(t, key, "DPoP", "at-1")
+	scheme, token := authorizationToken(r)
+	if err := verifyDPoPBinding(r, nil, claims, scheme, token); err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+
+	// The same proof can't be presented twice
+	if err := verifyDPoPBinding(r, nil, claims, scheme, token); err == nil {
+		t.Fatal("replayed proof accepted")
+	}
+}
+
+func TestVerifyDPoPBindingRejects(t *testing.T) {
+	key, jkt := dpopKey(t)
+	otherKey, _ := dpopKey(t)
+	bound := map[string]any{"sub": "user-1", "cnf": map[string]any{"jkt": jkt}}
+
+	tests := []struct {
+		name   string
+		r      *http.Request
+		claims map[string]any
+	}{
+		{"bound token as bearer", dpopRequest(t, key, "Bearer", "at-1"), bound},
+		{"proof signed by another key", dpopRequest(t, otherKey, "DPoP", "at-1"), bound},
+		{"DPoP scheme for an unbound token", dpopRequest(t, key, "DPoP", "at-1"), map[string]any{"sub": "user-1"}},
+	}
+	for _, tt := range tests {
+		scheme, token := authorizationToken(tt.r)
+		if err := verifyDPoPBinding(tt.r, nil, tt.claims, scheme, token); err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+
+	// Proof for another access token (ath mismatch)
+	r := dpopRequest(t, key, "DPoP", "at-1")
+	if err := verifyDPoPBinding(r, nil, bound, "DPoP", "at-2"); err == nil {
+		t.Error("proof for another token accepted")
+	}
+
+	// No proof at all
+	r = httptest.NewRequest(http.MethodGet, testResource, nil)
+	if err := verifyDPoPBinding(r, nil, bound, "DPoP", "at-1"); err == nil {
+		t.Error("missing proof accepted")
+	}
+}
+
+func TestVerifyDPoPBindingUnboundBearer(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, testResource, nil)
+	if err := verifyDPoPBinding(r, nil, map[string]any{"sub": "user-1"}, "Bearer", "at-1"); err != nil {
+		t.Fatalf("plain bearer token rejected: %v", err)
+	}
+}
+
+func clientCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "client-a"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func TestVerifyCertificateBinding(t *testing.T) {
+	cert, other := clientCert(t), clientCert(t)
+	bound := map[string]any{"cnf": map[string]any{"x5t#S256": jwtx.CertificateThumbprint(cert)}}
+
+	withCert := func(c *x509.Certificate) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, testResource, nil)
+		r.TLS = &tls.ConnectionState{}
+		if c != nil {
+			r.TLS.PeerCertificates = []*x509.Certificate{c}
+		}
+		return r
+	}
+
+	if err := verifyCertificateBinding(withCert(cert), bound); err != nil {
+		t.Errorf("matching certificate rejected: %v", err)
+	}
+	if err := verifyCertificateBinding(withCert(other), bound); err == nil {
+		t.Error("certificate mismatch accepted")
+	}
+	if err := verifyCertificateBinding(withCert(nil), bound); err == nil {
+		t.Error("bound token accepted without a certificate")
+	}
+	if err := verifyCertificateBinding(withCert(nil), map[string]any{"sub": "user-1"}); err != nil {
+		t.Errorf("unbound token rejected: %v", err)
+	}
+}
@@ -48,6 +48,8 @@ func RegisterAdminRoutes(mux *http.ServeMux, deps AdminRouterDeps) {
 	mux.Handle("GET /v2/admin/tenants/{tenant_id}/users/{userId}", userHandler)
 	mux.Handle("PUT /v2/admin/tenants/{tenant_id}/users/{userId}", userHandler)
 	mux.Handle("DELETE /v2/admin/tenants/{tenant_id}/users/{userId}", userHandler)
+	mux.Handle("GET /v2/admin/tenants/{tenant_id}/users/locked", userHandler)
+	mux.Handle("POST /v2/admin/tenants/{tenant_id}/users/{userId}/unlock", userHandler)
 
 	// Token Management (Data Plane - requiere DB)
 	tokenHandler := adminTokensHandler(dal, issuer, limiter, c.Tokens, true)
@@ -84,6 +86,14 @@ func RegisterAdminRoutes(mux *http.ServeMux, deps AdminRouterDeps) {
 	mux.Handle("PUT /v2/admin/tenants/{tenant_id}/scopes/{scopeId}", scopesHandler)
 	mux.Handle("DELETE /v2/admin/tenants/{tenant_id}/scopes/{scopeId}", scopesHandler)
 
+	// API Resources Management - RFC 8707 (Control Plane - no requiere DB)
+	resourcesHandler := adminResourcesHandler(dal, issuer, limiter, c.Resources, false)
+	mux.Handle("GET /v2/admin/tenants/{tenant_id}/resources", resourcesHandler)
+	mux.Handle("GET /v2/admin/tenants/{tenant_id}/resources/{resourceId}", resourcesHandler)
+	mux.Handle("POST /v2/admin/tenants/{tenant_id}/resources", resourcesHandler)
+	mux.Handle("PUT /v2/admin/tenants/{tenant_id}/resources/{resourceId}", resourcesHandler)
+	mux.Handle("DELETE /v2/admin/tenants/{tenant_id}/resources/{resourceId}", resourcesHandler)
+
 	// Claims Management (Control Plane - no requiere DB)
 	claimsHandler := adminClaimsHandler(dal, issuer, limiter, c.Claims, false)
 	mux.Handle("GET /v2/admin/tenants/{tenant_id}/claims", claimsHandler)
@@ -290,6 +300,43 @@ func adminScopesHandler(dal store.DataAccessLayer, issuer *jwtx.Issuer, limiter
 	return mw.Chain(handler, adminBaseChain(dal, issuer, limiter, requireDB)...)
 }
 
+// ─── Admin API Resources ───
+
+func adminResourcesHandler(dal store.DataAccessLayer, issuer *jwtx.Issuer, limiter mw.RateLimiter, c *ctrl.ResourcesController, requireDB bool) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+
+		switch {
+		case strings.Contains(path, "/tenants/") && strings.HasSuffix(path, "/resources"):
+			switch r.Method {
+			case http.MethodGet:
+				c.ListResources(w, r)
+			case http.MethodPost:
+				c.UpsertResource(w, r)
+			default:
+				httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+			}
+
+		case strings.Contains(path, "/tenants/") && strings.Contains(path, "/resources/"):
+			switch r.Method {
+			case http.MethodGet:
+				c.GetResource(w, r)
+			case http.MethodPut:
+				c.UpsertResource(w, r)
+			case http.MethodDelete:
+				c.DeleteResource(w, r)
+			default:
+				httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+			}
+
+		default:
+			httperrors.WriteError(w, httperrors.ErrNotFound)
+		}
+	})
+
+	return mw.Chain(handler, adminBaseChain(dal, issuer, limiter, requireDB)...)
+}
+
 // ─── Admin Claims ───
 
 func adminClaimsHandler(dal store.DataAccessLayer, issuer *jwtx.Issuer, limiter mw.RateLimiter, c *ctrl.ClaimsController, requireDB bool) http.Handler {
@@ -442,6 +489,22 @@ func adminUserCRUDHandler(dal store.DataAccessLayer, issuer *jwtx.Issuer, limite
 			}
 			c.SetPassword(w, r)
 
+		// POST /v2/admin/tenants/{tenant_id}/users/{userId}/unlock
+		case strings.Contains(path, "/tenants/") && strings.HasSuffix(path, "/unlock"):
+			if r.Method != http.MethodPost {
+				httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+				return
+			}
+			c.UnlockUser(w, r)
+
+		// GET /v2/admin/tenants/{tenant_id}/users/locked
+		case strings.Contains(path, "/tenants/") && strings.HasSuffix(path, "/users/locked"):
+			if r.Method != http.MethodGet {
+				httperrors.WriteError(w, httperrors.ErrMethodNotAllowed)
+				return
+			}
+			c.ListLockedUsers(w, r)
+
 		// POST/GET /v2/admin/tenants/{tenant_id}/users - Create user or List users
 		case strings.Contains(path, "/tenants/") && strings.HasSuffix(path, "/users"):
 			if r.Method == http.MethodPost {
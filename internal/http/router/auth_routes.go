@@ -52,6 +52,18 @@ func RegisterAuthRoutes(mux *http.ServeMux, deps AuthRouterDeps) {
 	// POST /v2/auth/logout-all
 	mux.Handle("/v2/auth/logout-all", authHandler(deps.RateLimiter, http.HandlerFunc(c.Logout.LogoutAll)))
 
+	// POST /v2/auth/passkey/begin + /finish - Login sin password con passkeys (tenant en body)
+	if c.WebAuthn != nil {
+		mux.Handle("/v2/auth/passkey/begin", authHandler(deps.RateLimiter, http.HandlerFunc(c.WebAuthn.PasskeyBegin)))
+		mux.Handle("/v2/auth/passkey/finish", authHandler(deps.RateLimiter, http.HandlerFunc(c.WebAuthn.PasskeyFinish)))
+	}
+
+	// POST /v2/auth/passwordless/start + /verify - Login por email: código OTP o magic link (tenant en body)
+	if c.Passwordless != nil {
+		mux.Handle("/v2/auth/passwordless/start", authHandler(deps.RateLimiter, http.HandlerFunc(c.Passwordless.Start)))
+		mux.Handle("/v2/auth/passwordless/verify", authHandler(deps.RateLimiter, http.HandlerFunc(c.Passwordless.Verify)))
+	}
+
 	// Social routes are registered in social_routes.go to avoid duplication
 }
 
@@ -30,6 +30,12 @@ func RegisterEmailRoutes(mux *http.ServeMux, deps EmailRouterDeps) {
 
 	// POST /v2/auth/reset - Complete password reset
 	mux.Handle("/v2/auth/reset", emailHandler(deps, http.HandlerFunc(c.Flows.ResetPassword)))
+
+	// POST /v2/auth/unlock/request - Re-send the unlock link of a locked account
+	mux.Handle("/v2/auth/unlock/request", emailHandler(deps, http.HandlerFunc(c.Flows.UnlockRequest)))
+
+	// GET /v2/auth/unlock - Unlock a locked account (link from the lockout email)
+	mux.Handle("/v2/auth/unlock", emailHandler(deps, http.HandlerFunc(c.Flows.UnlockConfirm)))
 }
 
 // emailHandler crea el middleware chain para endpoints de email flows.
@@ -11,6 +11,8 @@ import (
 // MFARouterDeps contiene las dependencias para el router MFA.
 type MFARouterDeps struct {
 	MFATOTPController *authctrl.MFATOTPController
+	WebAuthn          *authctrl.WebAuthnController
+	SMS               *authctrl.MFASMSController
 	DAL               storev2.DataAccessLayer // Required for tenant resolution
 	RateLimiter       mw.RateLimiter          // Rate limiter opcional
 	AuthMiddleware    mw.Middleware           // RequireAuth middleware (valida JWT)
@@ -38,6 +40,53 @@ func RegisterMFARoutes(mux *http.ServeMux, deps MFARouterDeps) {
 
 	// POST /v2/mfa/recovery/rotate - Rotate recovery codes (requires password + 2FA)
 	mux.Handle("/v2/mfa/recovery/rotate", mfaHandler(deps, http.HandlerFunc(c.RotateRecovery), true))
+
+	registerWebAuthnMFARoutes(mux, deps)
+	registerSMSMFARoutes(mux, deps)
+}
+
+// registerWebAuthnMFARoutes registra registro/gestión de credenciales WebAuthn y su challenge MFA.
+func registerWebAuthnMFARoutes(mux *http.ServeMux, deps MFARouterDeps) {
+	c := deps.WebAuthn
+	if c == nil {
+		return
+	}
+
+	// POST /v2/mfa/webauthn/register/begin - Creation options para una nueva credencial
+	mux.Handle("/v2/mfa/webauthn/register/begin", mfaHandler(deps, http.HandlerFunc(c.RegisterBegin), true))
+
+	// POST /v2/mfa/webauthn/register/finish - Verifica la attestation y guarda la credencial
+	mux.Handle("/v2/mfa/webauthn/register/finish", mfaHandler(deps, http.HandlerFunc(c.RegisterFinish), true))
+
+	// GET/PATCH/DELETE /v2/mfa/webauthn/credentials[/{id}] - Gestión de credenciales propias
+	mux.Handle("GET /v2/mfa/webauthn/credentials", mfaHandler(deps, http.HandlerFunc(c.ListCredentials), true))
+	mux.Handle("PATCH /v2/mfa/webauthn/credentials/{id}", mfaHandler(deps, http.HandlerFunc(c.RenameCredential), true))
+	mux.Handle("DELETE /v2/mfa/webauthn/credentials/{id}", mfaHandler(deps, http.HandlerFunc(c.DeleteCredential), true))
+
+	// POST /v2/mfa/webauthn/challenge/begin + /challenge - Segundo factor (no JWT auth, mfa_token driven)
+	mux.Handle("/v2/mfa/webauthn/challenge/begin", mfaHandler(deps, http.HandlerFunc(c.MFABegin), false))
+	mux.Handle("/v2/mfa/webauthn/challenge", mfaHandler(deps, http.HandlerFunc(c.MFAChallenge), false))
+}
+
+// registerSMSMFARoutes registra la verificación de teléfono y su challenge MFA por SMS/voz.
+func registerSMSMFARoutes(mux *http.ServeMux, deps MFARouterDeps) {
+	c := deps.SMS
+	if c == nil {
+		return
+	}
+
+	// POST /v2/mfa/sms/enroll - Envía un código al teléfono nuevo
+	mux.Handle("/v2/mfa/sms/enroll", mfaHandler(deps, http.HandlerFunc(c.Enroll), true))
+
+	// POST /v2/mfa/sms/verify - Confirma el teléfono con el código recibido
+	mux.Handle("/v2/mfa/sms/verify", mfaHandler(deps, http.HandlerFunc(c.Verify), true))
+
+	// DELETE /v2/mfa/sms - Borra el teléfono (y el factor SMS)
+	mux.Handle("DELETE /v2/mfa/sms", mfaHandler(deps, http.HandlerFunc(c.Remove), true))
+
+	// POST /v2/mfa/sms/challenge/begin + /challenge - Segundo factor (no JWT auth, mfa_token driven)
+	mux.Handle("/v2/mfa/sms/challenge/begin", mfaHandler(deps, http.HandlerFunc(c.ChallengeBegin), false))
+	mux.Handle("/v2/mfa/sms/challenge", mfaHandler(deps, http.HandlerFunc(c.Challenge), false))
 }
 
 // mfaHandler crea el middleware chain para endpoints MFA.
@@ -20,6 +20,9 @@ func RegisterOAuthRoutes(mux *http.ServeMux, deps OAuthRouterDeps) {
 	// GET /oauth2/authorize - Authorization endpoint (OAuth 2.1 / OIDC)
 	mux.Handle("/oauth2/authorize", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Authorize.Authorize)))
 
+	// POST /oauth2/par - Pushed Authorization Requests (RFC 9126)
+	mux.Handle("/oauth2/par", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.PAR.Push)))
+
 	// POST /oauth2/token - Token endpoint (RFC 6749)
 	mux.Handle("/oauth2/token", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Token.Token)))
 
@@ -29,6 +32,21 @@ func RegisterOAuthRoutes(mux *http.ServeMux, deps OAuthRouterDeps) {
 	// POST /oauth2/introspect - Token introspection (RFC 7662)
 	mux.Handle("/oauth2/introspect", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Introspect.Introspect)))
 
+	// GET|POST /oauth2/logout - End session (OIDC RP-Initiated Logout 1.0)
+	mux.Handle("/oauth2/logout", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.EndSession.EndSession)))
+
+	// POST /oauth2/device_authorization - Device Authorization (RFC 8628)
+	mux.Handle("/oauth2/device_authorization", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Device.DeviceAuthorization)))
+
+	// GET /oauth2/device - Device user verification (verification_uri)
+	mux.Handle("/oauth2/device", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Device.Verify)))
+
+	// POST /oauth2/register - Dynamic Client Registration (RFC 7591)
+	mux.Handle("/oauth2/register", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Register.Register)))
+
+	// GET|PUT|DELETE /oauth2/register/{client_id} - Client configuration endpoint (RFC 7592)
+	mux.Handle("/oauth2/register/", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Register.Manage)))
+
 	// GET /v2/auth/consent/info - Get consent info with scope DisplayNames (ISS-05-03)
 	mux.Handle("/v2/auth/consent/info", oauthHandler(deps.RateLimiter, http.HandlerFunc(c.Consent.GetInfo)))
 
@@ -61,6 +61,8 @@ func RegisterV2Routes(deps V2RouterDeps) {
 	if deps.AuthControllers != nil && deps.AuthControllers.MFATOTP != nil {
 		RegisterMFARoutes(mux, MFARouterDeps{
 			MFATOTPController: deps.AuthControllers.MFATOTP,
+			WebAuthn:          deps.AuthControllers.WebAuthn,
+			SMS:               deps.AuthControllers.MFASMS,
 			DAL:               deps.DAL,
 			RateLimiter:       deps.RateLimiter,
 			AuthMiddleware:    deps.AuthMiddleware,
@@ -24,6 +24,13 @@ func RegisterSessionRoutes(mux *http.ServeMux, deps SessionRouterDeps) {
 
 	// POST /v2/session/login - Session cookie login (requires tenant resolution)
 	mux.Handle("/v2/session/login", sessionLoginHandler(deps, http.HandlerFunc(c.Login.Login)))
+
+	// POST /v2/session/passwordless/start + /verify, GET /link - Login por email para /oauth2/authorize (tenant en body/query)
+	if c.Passwordless != nil {
+		mux.Handle("/v2/session/passwordless/start", sessionHandler(deps.RateLimiter, http.HandlerFunc(c.Passwordless.Start)))
+		mux.Handle("/v2/session/passwordless/verify", sessionHandler(deps.RateLimiter, http.HandlerFunc(c.Passwordless.Verify)))
+		mux.Handle("/v2/session/passwordless/link", sessionHandler(deps.RateLimiter, http.HandlerFunc(c.Passwordless.Link)))
+	}
 }
 
 // sessionHandler crea el middleware chain para endpoints de session.
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfigFromEnv construye la config TLS del listener a partir del entorno.
+// Retorna nil si TLS_CERT_FILE/TLS_KEY_FILE no están definidos (HTTP plano,
+// por ejemplo detrás de un proxy que termina TLS).
+//
+// Con MTLS_ENABLE=true el server pide certificado al client (RFC 8705) sin
+// exigirlo: la verificación la hace el token endpoint según el método
+// registrado por cada client (tls_client_auth / self_signed_tls_client_auth).
+// Como Go no valida la cadena de un cert pedido así, MTLS_ENABLE exige
+// MTLS_CLIENT_CA_FILE: sin CAs cualquier cert autofirmado con el DN de un
+// client tls_client_auth lo autenticaría.
+func TLSConfigFromEnv() (*tls.Config, error) {
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS key pair: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if getenvBool("MTLS_ENABLE", false) {
+		if os.Getenv("MTLS_CLIENT_CA_FILE") == "" {
+			return nil, fmt.Errorf("MTLS_ENABLE requires MTLS_CLIENT_CA_FILE")
+		}
+		// Self-signed certs no validan contra una CA: se piden sin verificar
+		cfg.ClientAuth = tls.RequestClientCert
+	}
+	return cfg, nil
+}
+
+// loadClientCAs carga las CAs (PEM) que emiten certificados para tls_client_auth.
+// Retorna nil si path está vacío.
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	pemData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read MTLS_CLIENT_CA_FILE: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("MTLS_CLIENT_CA_FILE contains no certificates")
+	}
+	return pool, nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyPair deja un cert autofirmado y su key en dir y retorna los paths.
+func writeKeyPair(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certFile, keyFile
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTLSConfigFromEnv(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "")
+	t.Setenv("TLS_KEY_FILE", "")
+	t.Setenv("MTLS_ENABLE", "")
+	t.Setenv("MTLS_CLIENT_CA_FILE", "")
+
+	if cfg, err := TLSConfigFromEnv(); cfg != nil || err != nil {
+		t.Fatalf("no TLS env: cfg=%v err=%v", cfg, err)
+	}
+
+	certFile, keyFile := writeKeyPair(t, t.TempDir())
+	t.Setenv("TLS_CERT_FILE", certFile)
+	if _, err := TLSConfigFromEnv(); err == nil {
+		t.Error("cert without key accepted")
+	}
+
+	t.Setenv("TLS_KEY_FILE", keyFile)
+	cfg, err := TLSConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ClientAuth != tls.NoClientCert {
+		t.Errorf("ClientAuth = %v without MTLS_ENABLE", cfg.ClientAuth)
+	}
+
+	// mTLS sin CAs dejaría pasar cualquier cert autofirmado
+	t.Setenv("MTLS_ENABLE", "true")
+	if _, err := TLSConfigFromEnv(); err == nil {
+		t.Error("MTLS_ENABLE accepted without MTLS_CLIENT_CA_FILE")
+	}
+
+	t.Setenv("MTLS_CLIENT_CA_FILE", certFile)
+	cfg, err = TLSConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ClientAuth != tls.RequestClientCert {
+		t.Errorf("ClientAuth = %v, want RequestClientCert", cfg.ClientAuth)
+	}
+}
+
+func TestLoadClientCAs(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _ := writeKeyPair(t, dir)
+
+	if pool, err := loadClientCAs(""); pool != nil || err != nil {
+		t.Errorf("empty path: pool=%v err=%v", pool, err)
+	}
+	if pool, err := loadClientCAs(certFile); pool == nil || err != nil {
+		t.Errorf("valid CA file: pool=%v err=%v", pool, err)
+	}
+
+	junk := filepath.Join(dir, "junk.pem")
+	writeFile(t, junk, []byte("not a certificate"))
+	if _, err := loadClientCAs(junk); err == nil {
+		t.Error("file without certificates accepted")
+	}
+	if _, err := loadClientCAs(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("missing file accepted")
+	}
+}
@@ -20,9 +20,15 @@ import (
 	cp "github.com/dropDatabas3/hellojohn/internal/controlplane"
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
 	oauth "github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
 	socialsvc "github.com/dropDatabas3/hellojohn/internal/http/services/social"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/netguard"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 	migrations "github.com/dropDatabas3/hellojohn/migrations/postgres"
 )
@@ -90,6 +96,14 @@ func buildV2HandlerInternal() (http.Handler, func() error, store.DataAccessLayer
 		_ = cleanup()
 		return nil, nil, nil, fmt.Errorf("keystore bootstrap failed: %w", err)
 	}
+	// Tenants creados antes de publicar la clave de cifrado en el JWKS
+	if tenants, err := manager.ConfigAccess().Tenants().List(ctx); err == nil {
+		for _, t := range tenants {
+			if err := persistentKS.EnsureEncryptionKey(t.Slug); err != nil {
+				logger.From(ctx).Warn("tenant encryption key bootstrap failed", logger.TenantSlug(t.Slug), logger.Err(err))
+			}
+		}
+	}
 
 	issuer := jwtx.NewIssuer(v2BaseURL, persistentKS)
 
@@ -124,6 +138,56 @@ func buildV2HandlerInternal() (http.Handler, func() error, store.DataAccessLayer
 	// 4. Control Plane Service
 	cpService := cp.NewService(manager)
 
+	// Algoritmo de firma por tenant (settings.signingAlgorithm)
+	issuer.WithSigningAlgResolver(func(ctx context.Context, slug string) string {
+		t, err := cpService.GetTenant(ctx, slug)
+		if err != nil || t == nil {
+			return ""
+		}
+		return t.Settings.SigningAlgorithm
+	})
+
+	// Denylist de access tokens (jti/usuario/client/sesión/tenant) en el cache del tenant
+	issuer.WithRevocationChecker(func(ctx context.Context, claims map[string]any) bool {
+		tid, _ := claims["tid"].(string)
+		if tid == "" || strings.EqualFold(tid, "global") {
+			return false
+		}
+		tda, err := manager.ForTenant(ctx, tid)
+		if err != nil {
+			return false
+		}
+		// Las marcas por usuario usan el ID local; los tokens de clients pairwise no
+		if local, err := pairwise.LocalSubjectFromClaims(claims, func(clientID string) *repository.Client {
+			c, _ := cpService.GetClient(ctx, tda.Slug(), clientID)
+			return c
+		}); err == nil && local != claims["sub"] {
+			withLocal := make(map[string]any, len(claims))
+			for k, v := range claims {
+				withLocal[k] = v
+			}
+			withLocal["sub"] = local
+			claims = withLocal
+		}
+		revoked, err := revocation.New(tda.Cache()).IsRevoked(ctx, claims)
+		if err != nil {
+			logger.From(ctx).Warn("revocation check failed", logger.TenantSlug(tid), logger.Err(err))
+		}
+		return revoked
+	})
+
+	// jti de DPoP proofs presentados a los resource servers (RequireAuth), en el cache del tenant
+	issuer.WithDPoPReplayResolver(func(ctx context.Context, tid string) jwtx.DPoPReplayCache {
+		if strings.EqualFold(tid, "global") {
+			return nil
+		}
+		tda, err := manager.ForTenant(ctx, tid)
+		if err != nil || tda.Cache() == nil {
+			return nil
+		}
+		return jwtx.NewCacheDPoPReplay(tda.Cache())
+	})
+
 	// 5. Email Service (V2)
 	// Use separate key for encryption/decryption (not signing key)
 	emailKey := os.Getenv("SECRETBOX_MASTER_KEY")
@@ -153,6 +217,44 @@ func buildV2HandlerInternal() (http.Handler, func() error, store.DataAccessLayer
 	// Keeping NoOp for safety.
 	socialCache := &NoOpSocialCache{}
 
+	// 6.4. Descargas a URLs de clients (jwks_uri, sector_identifier_uri, backchannel_logout_uri):
+	// solo IPs públicas salvo que los relying parties vivan en la red interna
+	netguard.AllowPrivate = getenvBool("OUTBOUND_ALLOW_PRIVATE_NETWORKS", false)
+
+	// 6.5. Mutual TLS (RFC 8705): CAs para tls_client_auth
+	mtlsClientCAs, err := loadClientCAs(os.Getenv("MTLS_CLIENT_CA_FILE"))
+	if err != nil {
+		_ = cleanup()
+		return nil, nil, nil, err
+	}
+
+	// 6.5.1. Proxies que terminan TLS: sólo a ellos se les cree el cert del
+	// client reenviado (MTLS_CLIENT_CERT_HEADER) y X-Forwarded-Proto/Host
+	trustedProxies, err := helpers.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		_ = cleanup()
+		return nil, nil, nil, err
+	}
+	helpers.TrustedProxies = trustedProxies
+	if os.Getenv("MTLS_CLIENT_CERT_HEADER") != "" && len(trustedProxies) == 0 {
+		logger.From(ctx).Warn("MTLS_CLIENT_CERT_HEADER is ignored without TRUSTED_PROXIES")
+	}
+
+	// 6.6. SMS / voz: verificación de teléfono y MFA por SMS (SMS_PROVIDER vacío = deshabilitado)
+	smsSender, err := sms.NewSender(sms.Config{
+		Provider:         os.Getenv("SMS_PROVIDER"),
+		TwilioAccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		TwilioFrom:       os.Getenv("TWILIO_FROM"),
+		TwilioBaseURL:    os.Getenv("TWILIO_API_BASE"),
+		WebhookURL:       os.Getenv("SMS_WEBHOOK_URL"),
+		WebhookSecret:    os.Getenv("SMS_WEBHOOK_SECRET"),
+	})
+	if err != nil {
+		_ = cleanup()
+		return nil, nil, nil, err
+	}
+
 	// 7. Dependencies Struct
 	deps := appv2.Deps{
 		DAL:          manager,
@@ -185,6 +287,12 @@ func buildV2HandlerInternal() (http.Handler, func() error, store.DataAccessLayer
 		OAuthCache:       oauth.NewCacheAdapter(cache.NewMemory("oauth")),
 		OAuthCookieName:  "sid", // Default
 		OAuthAllowBearer: true,  // Default V1 behavior
+		DPoPRequireNonce: getenvBool("DPOP_REQUIRE_NONCE", false),
+		// Mutual TLS
+		MTLSClientCAs:        mtlsClientCAs,
+		MTLSClientCertHeader: os.Getenv("MTLS_CLIENT_CERT_HEADER"),
+		// Phone / SMS
+		SMS: smsSender,
 	}
 
 	// 8. Build App (Router, Controllers)
@@ -251,6 +359,15 @@ func (s *NoOpEmailService) SendPasswordResetEmail(ctx context.Context, req email
 func (s *NoOpEmailService) SendNotificationEmail(ctx context.Context, req emailv2.SendNotificationRequest) error {
 	return nil
 }
+func (s *NoOpEmailService) SendBlockedEmail(ctx context.Context, req emailv2.SendBlockedRequest) error {
+	return nil
+}
+func (s *NoOpEmailService) SendUnblockedEmail(ctx context.Context, req emailv2.SendUnblockedRequest) error {
+	return nil
+}
+func (s *NoOpEmailService) SendPasswordlessEmail(ctx context.Context, req emailv2.SendPasswordlessRequest) error {
+	return nil
+}
 func (s *NoOpEmailService) TestSMTP(ctx context.Context, tenantSlugOrID, recipientEmail string, override *emailv2.SMTPConfig) error {
 	return nil
 }
@@ -402,6 +519,8 @@ func (m *MockTDA) EmailTokens() repository.EmailTokenRepository
 func (m *MockTDA) Identities() repository.IdentityRepository                       { return nil }
 func (m *MockTDA) Scopes() repository.ScopeRepository                              { return nil }
 func (m *MockTDA) Sessions() repository.SessionRepository                          { return nil }
+func (m *MockTDA) LoginAttempts() repository.LoginAttemptRepository                { return nil }
+func (m *MockTDA) WebAuthn() repository.WebAuthnRepository                         { return nil }
 func (m *MockTDA) Cache() cache.Client                                             { return nil }
 func (m *MockTDA) CacheRepo() repository.CacheRepository                           { return nil }
 func (m *MockTDA) Mailer() store.MailSender                                        { return nil }
@@ -437,6 +556,9 @@ func (m *MockUserRepo) SetEmailVerified(ctx context.Context, userID string, veri
 func (m *MockUserRepo) UpdatePasswordHash(ctx context.Context, userID, newHash string) error {
 	return repository.ErrNotImplemented
 }
+func (m *MockUserRepo) SetPhoneNumber(ctx context.Context, userID, phone string, verified bool) error {
+	return repository.ErrNotImplemented
+}
 func (m *MockUserRepo) List(ctx context.Context, tenantID string, filter repository.ListUsersFilter) ([]repository.User, error) {
 	return nil, repository.ErrNotImplemented
 }
@@ -458,6 +580,12 @@ func (m *MockTokenRepo) RevokeAllByUser(ctx context.Context, userID, clientID st
 	return 0, nil
 }
 func (m *MockTokenRepo) RevokeAllByClient(ctx context.Context, clientID string) error { return nil }
+func (m *MockTokenRepo) RevokeFamily(ctx context.Context, tokenID string) (int, error) {
+	return 0, nil
+}
+func (m *MockTokenRepo) HasSuccessor(ctx context.Context, tokenID string) (bool, error) {
+	return false, nil
+}
 func (m *MockTokenRepo) GetByID(ctx context.Context, tokenID string) (*repository.RefreshToken, error) {
 	return nil, repository.ErrNotFound
 }
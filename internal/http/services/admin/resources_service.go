@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+// APIResourceService define las operaciones del registro de APIs (RFC 8707) para el admin API.
+type APIResourceService interface {
+	List(ctx context.Context, tenantSlug string) ([]repository.APIResource, error)
+	Get(ctx context.Context, tenantSlug, resourceID string) (*repository.APIResource, error)
+	Upsert(ctx context.Context, tenantSlug string, input repository.APIResourceInput) (*repository.APIResource, error)
+	Delete(ctx context.Context, tenantSlug, resourceID string) error
+}
+
+// apiResourceService implementa APIResourceService usando controlplane.Service.
+type apiResourceService struct {
+	cp controlplane.Service
+}
+
+// NewAPIResourceService crea un nuevo servicio de API resources.
+func NewAPIResourceService(cp controlplane.Service) APIResourceService {
+	return &apiResourceService{cp: cp}
+}
+
+const componentResources = "admin.resources"
+
+func (s *apiResourceService) List(ctx context.Context, tenantSlug string) ([]repository.APIResource, error) {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Component(componentResources),
+		logger.Op("List"),
+		logger.TenantSlug(tenantSlug),
+	)
+
+	resources, err := s.cp.ListAPIResources(ctx, tenantSlug)
+	if err != nil {
+		log.Error("failed to list api resources", logger.Err(err))
+		return nil, err
+	}
+
+	log.Debug("api resources listed", logger.Int("count", len(resources)))
+	return resources, nil
+}
+
+// Get busca por ID o, si no coincide, por identifier.
+func (s *apiResourceService) Get(ctx context.Context, tenantSlug, resourceID string) (*repository.APIResource, error) {
+	resources, err := s.List(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resources {
+		if resources[i].ID == resourceID || resources[i].Identifier == resourceID {
+			return &resources[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (s *apiResourceService) Upsert(ctx context.Context, tenantSlug string, input repository.APIResourceInput) (*repository.APIResource, error) {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Component(componentResources),
+		logger.Op("Upsert"),
+		logger.TenantSlug(tenantSlug),
+		logger.String("resource", input.Identifier),
+	)
+
+	if input.Identifier == "" {
+		return nil, fmt.Errorf("resource identifier is required")
+	}
+
+	res, err := s.cp.UpsertAPIResource(ctx, tenantSlug, input)
+	if err != nil {
+		log.Error("failed to upsert api resource", logger.Err(err))
+		return nil, err
+	}
+
+	log.Info("api resource upserted")
+	return res, nil
+}
+
+func (s *apiResourceService) Delete(ctx context.Context, tenantSlug, resourceID string) error {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Component(componentResources),
+		logger.Op("Delete"),
+		logger.TenantSlug(tenantSlug),
+		logger.String("resource_id", resourceID),
+	)
+
+	res, err := s.Get(ctx, tenantSlug, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cp.DeleteAPIResource(ctx, tenantSlug, res.Identifier); err != nil {
+		log.Error("failed to delete api resource", logger.Err(err))
+		return err
+	}
+
+	log.Info("api resource deleted", logger.String("resource", res.Identifier))
+	return nil
+}
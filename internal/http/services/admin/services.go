@@ -6,6 +6,8 @@ import (
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
 	"github.com/dropDatabas3/hellojohn/internal/jwt"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
@@ -18,6 +20,10 @@ type Deps struct {
 	MasterKey    string
 	Issuer       *jwt.Issuer
 	RefreshTTL   time.Duration // TTL para admin refresh tokens
+
+	Logout logout.Notifier // Back-channel logout hacia los RPs (nil = deshabilitado)
+
+	Lockout lockout.Guard // Bloqueo por intentos fallidos (nil = deshabilitado)
 }
 
 // Services agrupa todos los services del dominio admin.
@@ -28,6 +34,7 @@ type Services struct {
 	Users         UserActionService
 	UserCRUD      UserCRUDService
 	Scopes        ScopeService
+	Resources     APIResourceService
 	Claims        ClaimsService
 	RBAC          RBACService
 	Tenants       TenantsService
@@ -48,16 +55,18 @@ func NewServices(d Deps) Services {
 		Clients: NewClientService(d.ControlPlane),
 		Scopes:  NewScopeService(d.ControlPlane),
 		Claims:  NewClaimsService(d.ControlPlane),
-		Users:   NewUserActionService(d.Email),
+		Users:   NewUserActionService(d.Email, d.Logout, d.Lockout),
 		UserCRUD: NewUserCRUDService(UserCRUDDeps{
-			DAL: d.DAL,
+			DAL:    d.DAL,
+			Logout: d.Logout,
 		}),
 		Consents:      NewConsentService(),
 		RBAC:          NewRBACService(),
 		Tenants:       NewTenantsService(d.DAL, d.MasterKey, d.Issuer, d.Email),
 		TokensAdmin:   NewTokensAdminService(TokensAdminDeps{DAL: d.DAL}),
-		SessionsAdmin: NewSessionsService(d.DAL),
+		SessionsAdmin: NewSessionsService(d.DAL, d.Logout),
 		Keys:          NewKeysService(d.DAL),
+		Resources:     NewAPIResourceService(d.ControlPlane),
 		Cluster:       NewClusterService(ClusterDeps{DAL: d.DAL}),
 	}
 }
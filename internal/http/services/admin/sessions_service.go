@@ -6,17 +6,21 @@ import (
 	"time"
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
 	"github.com/dropDatabas3/hellojohn/internal/store"
 )
 
 // SessionsService provee operaciones administrativas sobre sesiones.
 type SessionsService struct {
-	dal store.DataAccessLayer
+	dal    store.DataAccessLayer
+	logout logout.Notifier
 }
 
 // NewSessionsService crea un nuevo servicio de sesiones.
-func NewSessionsService(dal store.DataAccessLayer) *SessionsService {
-	return &SessionsService{dal: dal}
+// notifier puede ser nil (sin back-channel logout).
+func NewSessionsService(dal store.DataAccessLayer, notifier logout.Notifier) *SessionsService {
+	return &SessionsService{dal: dal, logout: notifier}
 }
 
 // ListSessionsInput contiene los parámetros para listar sesiones.
@@ -164,7 +168,22 @@ func (s *SessionsService) RevokeSession(ctx context.Context, input RevokeSession
 		return store.ErrNoDBForTenant
 	}
 
-	return tda.Sessions().Revoke(ctx, input.SessionIDHash, input.AdminID, input.Reason)
+	// El dueño de la sesión va en el logout_token junto al sid
+	userID := ""
+	if sess, err := tda.Sessions().Get(ctx, input.SessionIDHash); err == nil && sess != nil {
+		userID = sess.UserID
+	}
+
+	if err := tda.Sessions().Revoke(ctx, input.SessionIDHash, input.AdminID, input.Reason); err != nil {
+		return err
+	}
+
+	// Access tokens emitidos bajo la sesión (claim sid); best-effort
+	_ = revocation.New(tda.Cache()).RevokeSession(ctx, input.SessionIDHash)
+	if s.logout != nil {
+		s.logout.Backchannel(ctx, tda.Slug(), userID, input.SessionIDHash)
+	}
+	return nil
 }
 
 // RevokeUserSessionsInput contiene los parámetros para revocar sesiones de un usuario.
@@ -196,6 +215,12 @@ func (s *SessionsService) RevokeUserSessions(ctx context.Context, input RevokeUs
 		return nil, err
 	}
 
+	// Access tokens vigentes del usuario; best-effort
+	_ = revocation.New(tda.Cache()).RevokeSubject(ctx, input.UserID)
+	if s.logout != nil {
+		s.logout.Backchannel(ctx, tda.Slug(), input.UserID, "")
+	}
+
 	return &RevokeUserSessionsOutput{RevokedCount: count}, nil
 }
 
@@ -10,12 +10,16 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/dropDatabas3/hellojohn/internal/claims"
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/admin"
 	httperrors "github.com/dropDatabas3/hellojohn/internal/http/errors"
 	"github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/password"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 	"github.com/google/uuid"
 )
@@ -137,6 +141,13 @@ func (s *tenantsService) Create(ctx context.Context, req dto.CreateTenantRequest
 
 	resp := mapTenantToResponse(t)
 
+	// Encryption key published in the tenant JWKS (clients encrypt request objects with it)
+	if s.issuer != nil && s.issuer.Keys != nil {
+		if err := s.issuer.Keys.EnsureEncryptionKey(t.Slug); err != nil {
+			log.Warn("tenant encryption key generation failed", logger.Err(err), logger.String("slug", t.Slug))
+		}
+	}
+
 	// Bootstrap DB if tenant has database configured
 	if t.Settings.UserDB != nil && (t.Settings.UserDB.DSN != "" || t.Settings.UserDB.DSNEnc != "") {
 		log.Info("bootstrapping tenant DB", logger.String("slug", t.Slug))
@@ -352,6 +363,33 @@ func (s *tenantsService) UpdateSettings(ctx context.Context, slugOrID string, se
 	if settings.IssuerMode != "" && settings.IssuerMode != "global" && settings.IssuerMode != "path" && settings.IssuerMode != "domain" {
 		return "", fmt.Errorf("%w: invalid issuer_mode", repository.ErrInvalidInput)
 	}
+	if settings.SigningAlgorithm != "" && !jwt.IsSigningAlgSupported(settings.SigningAlgorithm) {
+		return "", fmt.Errorf("%w: invalid signing_algorithm", repository.ErrInvalidInput)
+	}
+	if settings.AccessTokenProfile != repository.AccessTokenProfileDefault && settings.AccessTokenProfile != repository.AccessTokenProfileRFC9068 {
+		return "", fmt.Errorf("%w: invalid access_token_profile", repository.ErrInvalidInput)
+	}
+	if settings.RefreshReuseGraceSeconds < 0 {
+		return "", fmt.Errorf("%w: invalid refresh_reuse_grace_seconds", repository.ErrInvalidInput)
+	}
+	if cp := settings.ConsentPolicy; cp != nil && cp.ConsentMode != "" &&
+		cp.ConsentMode != repository.ConsentModeSingle && cp.ConsentMode != repository.ConsentModePerScope {
+		return "", fmt.Errorf("%w: invalid consent_mode", repository.ErrInvalidInput)
+	}
+	if err := validateAuthorizationDetailsTypes(settings.AuthorizationDetailsTypes); err != nil {
+		return "", fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+	if pl := settings.Passwordless; pl != nil && (pl.TTLSeconds < 0 || pl.MaxAttempts < 0) {
+		return "", fmt.Errorf("%w: invalid passwordless settings", repository.ErrInvalidInput)
+	}
+	if ph := settings.Phone; ph != nil && ph.MaxSendsPerHour < 0 {
+		return "", fmt.Errorf("%w: invalid phone settings", repository.ErrInvalidInput)
+	}
+	if ph := settings.Phone; ph != nil {
+		if err := sms.ValidateTemplates(ph.Templates); err != nil {
+			return "", fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+		}
+	}
 
 	if err := encryptTenantSecrets(&settings, s.masterKey); err != nil {
 		return "", fmt.Errorf("failed to encrypt secrets: %w", err)
@@ -438,6 +476,59 @@ func dbSettingsChanged(old, new *repository.UserDBSettings) bool {
 	return false
 }
 
+// validateAuthorizationDetailsTypes checks the RAR type registry: unique,
+// non-empty type names and schemas within the supported JSON Schema subset.
+func validateAuthorizationDetailsTypes(types []repository.AuthorizationDetailsType) error {
+	seen := make(map[string]bool, len(types))
+	for _, t := range types {
+		if t.Type == "" {
+			return errors.New("authorization_details type is required")
+		}
+		if seen[t.Type] {
+			return fmt.Errorf("duplicated authorization_details type %q", t.Type)
+		}
+		seen[t.Type] = true
+		if err := claims.CheckSchema(t.Schema); err != nil {
+			return fmt.Errorf("invalid schema for authorization_details type %q: %v", t.Type, err)
+		}
+	}
+	return nil
+}
+
+// authorizationDetailsTypesFromDTO maps the RAR type registry of a request.
+func authorizationDetailsTypesFromDTO(in []dto.AuthorizationDetailsTypeDTO) []repository.AuthorizationDetailsType {
+	out := make([]repository.AuthorizationDetailsType, len(in))
+	for i, t := range in {
+		out[i] = repository.AuthorizationDetailsType{
+			Type:        t.Type,
+			Description: t.Description,
+			Schema:      t.Schema,
+		}
+	}
+	return out
+}
+
+// passwordlessFromDTO maps the passwordless login settings of a request.
+func passwordlessFromDTO(in *dto.PasswordlessDTO) *repository.PasswordlessSettings {
+	return &repository.PasswordlessSettings{
+		MagicLinkEnabled: in.MagicLinkEnabled,
+		EmailOTPEnabled:  in.EmailOTPEnabled,
+		AllowSignup:      in.AllowSignup,
+		TTLSeconds:       in.TTLSeconds,
+		MaxAttempts:      in.MaxAttempts,
+	}
+}
+
+// phoneFromDTO maps the phone verification / SMS MFA settings of a request.
+func phoneFromDTO(in *dto.PhoneDTO) *repository.PhoneSettings {
+	return &repository.PhoneSettings{
+		MFAEnabled:      in.MFAEnabled,
+		VoiceEnabled:    in.VoiceEnabled,
+		MaxSendsPerHour: in.MaxSendsPerHour,
+		Templates:       in.Templates,
+	}
+}
+
 // userFieldsChanged compares UserFields slices.
 func userFieldsChanged(old, new []repository.UserFieldDefinition) bool {
 	if len(old) != len(new) {
@@ -686,6 +777,7 @@ func mapTenantSettingsToDTO(s *repository.TenantSettings) *dto.TenantSettingsRes
 		IssuerMode:                  s.IssuerMode,
 		SessionLifetimeSeconds:      s.SessionLifetimeSeconds,
 		RefreshTokenLifetimeSeconds: s.RefreshTokenLifetimeSeconds,
+		RefreshReuseGraceSeconds:    s.RefreshReuseGraceSeconds,
 		MFAEnabled:                  s.MFAEnabled,
 		SocialLoginEnabled:          s.SocialLoginEnabled,
 		LogoURL:                     s.LogoURL,
@@ -697,6 +789,12 @@ func mapTenantSettingsToDTO(s *repository.TenantSettings) *dto.TenantSettingsRes
 	if s.IssuerOverride != "" {
 		resp.IssuerOverride = &s.IssuerOverride
 	}
+	if s.SigningAlgorithm != "" {
+		resp.SigningAlgorithm = &s.SigningAlgorithm
+	}
+	if s.AccessTokenProfile != "" {
+		resp.AccessTokenProfile = &s.AccessTokenProfile
+	}
 
 	if s.UserDB != nil {
 		resp.UserDB = &dto.UserDBSettings{
@@ -763,6 +861,40 @@ func mapTenantSettingsToDTO(s *repository.TenantSettings) *dto.TenantSettingsRes
 		}
 	}
 
+	if s.ClientRegistration != nil {
+		resp.ClientRegistration = &dto.ClientRegistrationDTO{
+			Mode:                     s.ClientRegistration.Mode,
+			InitialAccessTokenHashes: s.ClientRegistration.InitialAccessTokenHashes,
+		}
+	}
+
+	if s.Passwordless != nil {
+		resp.Passwordless = &dto.PasswordlessDTO{
+			MagicLinkEnabled: s.Passwordless.MagicLinkEnabled,
+			EmailOTPEnabled:  s.Passwordless.EmailOTPEnabled,
+			AllowSignup:      s.Passwordless.AllowSignup,
+			TTLSeconds:       s.Passwordless.TTLSeconds,
+			MaxAttempts:      s.Passwordless.MaxAttempts,
+		}
+	}
+
+	if s.Phone != nil {
+		resp.Phone = &dto.PhoneDTO{
+			MFAEnabled:      s.Phone.MFAEnabled,
+			VoiceEnabled:    s.Phone.VoiceEnabled,
+			MaxSendsPerHour: s.Phone.MaxSendsPerHour,
+			Templates:       s.Phone.Templates,
+		}
+	}
+
+	for _, t := range s.AuthorizationDetailsTypes {
+		resp.AuthorizationDetailsTypes = append(resp.AuthorizationDetailsTypes, dto.AuthorizationDetailsTypeDTO{
+			Type:        t.Type,
+			Description: t.Description,
+			Schema:      t.Schema,
+		})
+	}
+
 	if len(s.UserFields) > 0 {
 		resp.UserFields = make([]dto.UserFieldDefinition, len(s.UserFields))
 		for i, uf := range s.UserFields {
@@ -819,12 +951,21 @@ func mapDTOToTenantSettings(req *dto.UpdateTenantSettingsRequest, existing *repo
 	if req.IssuerOverride != nil {
 		result.IssuerOverride = *req.IssuerOverride
 	}
+	if req.SigningAlgorithm != nil {
+		result.SigningAlgorithm = *req.SigningAlgorithm
+	}
+	if req.AccessTokenProfile != nil {
+		result.AccessTokenProfile = *req.AccessTokenProfile
+	}
 	if req.SessionLifetimeSeconds != nil {
 		result.SessionLifetimeSeconds = *req.SessionLifetimeSeconds
 	}
 	if req.RefreshTokenLifetimeSeconds != nil {
 		result.RefreshTokenLifetimeSeconds = *req.RefreshTokenLifetimeSeconds
 	}
+	if req.RefreshReuseGraceSeconds != nil {
+		result.RefreshReuseGraceSeconds = *req.RefreshReuseGraceSeconds
+	}
 	if req.MFAEnabled != nil {
 		result.MFAEnabled = *req.MFAEnabled
 	}
@@ -974,6 +1115,32 @@ func mapDTOToTenantSettings(req *dto.UpdateTenantSettingsRequest, existing *repo
 		}
 	}
 
+	// Client Registration: the hash list is replaced as sent; plain tokens are hashed and added
+	if req.ClientRegistration != nil {
+		cr := &repository.ClientRegistrationSettings{
+			Mode:                     req.ClientRegistration.Mode,
+			InitialAccessTokenHashes: req.ClientRegistration.InitialAccessTokenHashes,
+		}
+		for _, t := range req.ClientRegistration.InitialAccessTokens {
+			if t != "" {
+				cr.InitialAccessTokenHashes = append(cr.InitialAccessTokenHashes, tokens.SHA256Base64URL(t))
+			}
+		}
+		result.ClientRegistration = cr
+	}
+
+	if req.AuthorizationDetailsTypes != nil {
+		result.AuthorizationDetailsTypes = authorizationDetailsTypesFromDTO(req.AuthorizationDetailsTypes)
+	}
+
+	if req.Passwordless != nil {
+		result.Passwordless = passwordlessFromDTO(req.Passwordless)
+	}
+
+	if req.Phone != nil {
+		result.Phone = phoneFromDTO(req.Phone)
+	}
+
 	if req.UserFields != nil {
 		result.UserFields = make([]repository.UserFieldDefinition, len(req.UserFields))
 		for i, uf := range req.UserFields {
@@ -1062,6 +1229,14 @@ func (s *tenantsService) ValidateImport(ctx context.Context, slugOrID string, re
 		result.Warnings = append(result.Warnings, fmt.Sprintf("Versión de export (%s) difiere de la actual (%s)", req.Version, importVersion))
 	}
 
+	// Validar hashes de password importados
+	for _, u := range req.Users {
+		if _, err := importedPasswordHash(u); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("Usuario %s: %v", u.Email, err))
+		}
+	}
+
 	// Obtener tenant existente
 	tenant, err := s.resolveTenant(ctx, slugOrID)
 	if err != nil {
@@ -1310,6 +1485,21 @@ func (s *tenantsService) importSettings(ctx context.Context, tenant *repository.
 	if settings.IssuerOverride != nil {
 		existing.IssuerOverride = *settings.IssuerOverride
 	}
+	if settings.SigningAlgorithm != nil {
+		existing.SigningAlgorithm = *settings.SigningAlgorithm
+	}
+	if settings.AccessTokenProfile != nil {
+		existing.AccessTokenProfile = *settings.AccessTokenProfile
+	}
+	if len(settings.AuthorizationDetailsTypes) > 0 {
+		existing.AuthorizationDetailsTypes = authorizationDetailsTypesFromDTO(settings.AuthorizationDetailsTypes)
+	}
+	if settings.Passwordless != nil {
+		existing.Passwordless = passwordlessFromDTO(settings.Passwordless)
+	}
+	if settings.Phone != nil {
+		existing.Phone = phoneFromDTO(settings.Phone)
+	}
 	if settings.SessionLifetimeSeconds > 0 {
 		existing.SessionLifetimeSeconds = settings.SessionLifetimeSeconds
 	}
@@ -1462,29 +1652,43 @@ func (s *tenantsService) importUser(ctx context.Context, tda store.TenantDataAcc
 		return false, fmt.Errorf("users repository no disponible")
 	}
 
+	pwdHash, err := importedPasswordHash(u)
+	if err != nil {
+		return false, err
+	}
+
 	tenantID := tda.ID()
 
 	// Verificar si existe
 	existing, _, _ := usersRepo.GetByEmail(ctx, tenantID, u.Email)
 	if existing != nil {
 		if mode == "replace" {
-			// Actualizar usuario existente (sin cambiar password)
+			// Actualizar usuario existente (el password sólo si viene un hash)
 			updateInput := repository.UpdateUserInput{
 				Name:         ptrString(u.Username),
 				CustomFields: u.Metadata,
 			}
-			return false, usersRepo.Update(ctx, existing.ID, updateInput)
+			if err := usersRepo.Update(ctx, existing.ID, updateInput); err != nil {
+				return false, err
+			}
+			if pwdHash != "" {
+				return false, usersRepo.UpdatePasswordHash(ctx, existing.ID, pwdHash)
+			}
+			return false, nil
 		}
 		// merge: skip usuarios existentes
 		return false, nil
 	}
 
-	// Crear nuevo usuario con password temporal
-	tempPwd := uuid.New().String()[:12]
+	// Crear nuevo usuario con el hash importado o un password temporal
+	needsPwd := pwdHash == ""
+	if needsPwd {
+		pwdHash = uuid.New().String()[:12] // Se encriptará en Create
+	}
 	createInput := repository.CreateUserInput{
 		TenantID:     tenantID,
 		Email:        u.Email,
-		PasswordHash: tempPwd, // Se encriptará en Create
+		PasswordHash: pwdHash,
 		Name:         u.Username,
 		CustomFields: u.Metadata,
 	}
@@ -1504,8 +1708,26 @@ func (s *tenantsService) importUser(ctx context.Context, tda store.TenantDataAcc
 		}
 	}
 
-	// Siempre necesita reset password al importar
-	return true, nil
+	// Sin hash importado necesita reset password
+	return needsPwd, nil
+}
+
+// importedPasswordHash valida el hash de password de un usuario importado y lo
+// devuelve en el formato que entiende password.Verify. "" si no trae hash.
+func importedPasswordHash(u dto.UserImportData) (string, error) {
+	if u.PasswordHash == "" {
+		return "", nil
+	}
+	h, err := password.Import(password.Imported{
+		Algorithm:  u.PasswordHashAlgorithm,
+		Hash:       u.PasswordHash,
+		Salt:       u.PasswordSalt,
+		Iterations: u.PasswordIterations,
+	})
+	if err != nil {
+		return "", fmt.Errorf("password_hash inválido: %w", err)
+	}
+	return h, nil
 }
 
 // ptrString retorna un puntero a un string.
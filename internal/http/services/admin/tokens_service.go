@@ -8,6 +8,7 @@ import (
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/admin"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -228,6 +229,11 @@ func (s *tokensAdminService) RevokeByUser(ctx context.Context, tenantID, userID
 		return nil, fmt.Errorf("failed to revoke tokens: %w", err)
 	}
 
+	// 4. Cortar también los access tokens vigentes (denylist)
+	if err := revocation.New(tda.Cache()).RevokeSubject(ctx, userID); err != nil {
+		log.Warn("failed to denylist access tokens", logger.Err(err))
+	}
+
 	log.Info("tokens revoked by user", logger.Int("count", revokedCount))
 
 	return &dto.RevokeResponse{
@@ -275,6 +281,11 @@ func (s *tokensAdminService) RevokeByClient(ctx context.Context, tenantID, clien
 		return nil, fmt.Errorf("failed to revoke tokens: %w", err)
 	}
 
+	// 5. Cortar también los access tokens vigentes (denylist)
+	if err := revocation.New(tda.Cache()).RevokeClient(ctx, clientID); err != nil {
+		log.Warn("failed to denylist access tokens", logger.Err(err))
+	}
+
 	log.Info("tokens revoked by client", logger.Int("count", countBefore))
 
 	return &dto.RevokeResponse{
@@ -311,6 +322,11 @@ func (s *tokensAdminService) RevokeAll(ctx context.Context, tenantID string) (*d
 		return nil, fmt.Errorf("failed to revoke all tokens: %w", err)
 	}
 
+	// 4. Cortar también los access tokens vigentes (denylist)
+	if err := revocation.New(tda.Cache()).RevokeTenant(ctx); err != nil {
+		log.Warn("failed to denylist access tokens", logger.Err(err))
+	}
+
 	log.Warn("all tokens revoked", logger.Int("count", revokedCount))
 
 	return &dto.RevokeResponse{
@@ -7,8 +7,10 @@ import (
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/admin"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	"github.com/dropDatabas3/hellojohn/internal/security/password"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -23,7 +25,8 @@ type UserCRUDService interface {
 
 // UserCRUDDeps contiene las dependencias del service.
 type UserCRUDDeps struct {
-	DAL store.DataAccessLayer
+	DAL    store.DataAccessLayer
+	Logout logout.Notifier // opcional: back-channel logout al borrar
 }
 
 type userCRUDService struct {
@@ -294,6 +297,14 @@ func (s *userCRUDService) Delete(ctx context.Context, tenantID, userID string) e
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
+	// 5. Invalidar access tokens vigentes del usuario (best-effort)
+	if err := revocation.New(tda.Cache()).RevokeSubject(ctx, userID); err != nil {
+		log.Warn("access token denylist failed", logger.Err(err), logger.UserID(userID))
+	}
+	if s.deps.Logout != nil {
+		s.deps.Logout.Backchannel(ctx, tda.Slug(), userID, "")
+	}
+
 	log.Info("user deleted", logger.UserID(userID))
 
 	return nil
@@ -318,5 +329,8 @@ func mapUserToResponse(user *repository.User) *dto.UserResponse {
 		DisabledReason: user.DisabledReason,
 		DisabledBy:     nil, // TODO: Agregar DisabledBy al repository.User si no existe
 		CustomFields:   user.CustomFields,
+
+		PhoneNumber:         user.PhoneNumber,
+		PhoneNumberVerified: user.PhoneNumberVerified,
 	}
 }
@@ -11,8 +11,11 @@ import (
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	"github.com/dropDatabas3/hellojohn/internal/security/password"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -23,16 +26,23 @@ type UserActionService interface {
 	ResendVerification(ctx context.Context, tda store.TenantDataAccess, userID, actor string) error
 	SetEmailVerified(ctx context.Context, tda store.TenantDataAccess, userID string, verified bool, actor string) error
 	SetPassword(ctx context.Context, tda store.TenantDataAccess, userID, newPassword, actor string) error
+
+	// Bloqueos por intentos fallidos de login (SecurityPolicy)
+	ListLocked(ctx context.Context, tda store.TenantDataAccess) ([]lockout.LockedAccount, error)
+	Unlock(ctx context.Context, tda store.TenantDataAccess, userID, actor string) error
 }
 
 // userActionService implementa UserActionService.
 type userActionService struct {
 	emailSvc emailv2.Service
+	logout   logout.Notifier
+	lockout  lockout.Guard
 }
 
 // NewUserActionService crea un nuevo service de acciones de usuarios.
-func NewUserActionService(emailSvc emailv2.Service) UserActionService {
-	return &userActionService{emailSvc: emailSvc}
+// notifier puede ser nil (sin back-channel logout); guard puede ser nil (sin lockout).
+func NewUserActionService(emailSvc emailv2.Service, notifier logout.Notifier, guard lockout.Guard) UserActionService {
+	return &userActionService{emailSvc: emailSvc, logout: notifier, lockout: guard}
 }
 
 const (
@@ -76,6 +86,14 @@ func (s *userActionService) Disable(ctx context.Context, tda store.TenantDataAcc
 			log.Warn("best-effort token revocation failed", logger.Err(err))
 		}
 	}
+	// Los access tokens vigentes dejan de valer de inmediato (denylist)
+	if err := revocation.New(tda.Cache()).RevokeSubject(ctx, userID); err != nil {
+		log.Warn("best-effort access token denylist failed", logger.Err(err))
+	}
+	// Avisar a los RPs con back-channel logout
+	if s.logout != nil {
+		s.logout.Backchannel(ctx, tda.Slug(), userID, "")
+	}
 
 	// Enviar email notificación (best-effort)
 	go s.sendBlockNotification(ctx, tda, userID, reason, until)
@@ -113,6 +131,43 @@ func (s *userActionService) Enable(ctx context.Context, tda store.TenantDataAcce
 	return nil
 }
 
+func (s *userActionService) ListLocked(ctx context.Context, tda store.TenantDataAccess) ([]lockout.LockedAccount, error) {
+	if s.lockout == nil {
+		return []lockout.LockedAccount{}, nil
+	}
+	return s.lockout.ListLocked(ctx, tda)
+}
+
+func (s *userActionService) Unlock(ctx context.Context, tda store.TenantDataAccess, userID, actor string) error {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Component(componentUserAction),
+		logger.Op("Unlock"),
+		logger.UserID(userID),
+	)
+
+	if err := tda.RequireDB(); err != nil {
+		return err
+	}
+	if tda.Users() == nil {
+		return fmt.Errorf(errUsersRepoNil)
+	}
+	if s.lockout == nil {
+		return nil
+	}
+
+	if err := s.lockout.Unlock(ctx, tda, userID); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrUserNotFound
+		}
+		log.Error("unlock failed", logger.Err(err))
+		return err
+	}
+
+	log.Info("user unlocked", logger.String("actor", actor))
+	return nil
+}
+
 func (s *userActionService) ResendVerification(ctx context.Context, tda store.TenantDataAccess, userID, actor string) error {
 	log := logger.From(ctx).With(
 		logger.Layer("service"),
@@ -268,6 +323,9 @@ func (s *userActionService) SetPassword(ctx context.Context, tda store.TenantDat
 			log.Warn("best-effort token revocation failed", logger.Err(err))
 		}
 	}
+	if err := revocation.New(tda.Cache()).RevokeSubject(ctx, userID); err != nil {
+		log.Warn("best-effort access token denylist failed", logger.Err(err))
+	}
 
 	// Enviar notificación por email (best-effort)
 	go s.sendPasswordChangedNotification(ctx, tda, user.Email)
@@ -323,18 +381,13 @@ func (s *userActionService) sendBlockNotification(ctx context.Context, tda store
 		return
 	}
 
-	var untilStr string
-	if until != nil {
-		untilStr = until.Format("2006-01-02 15:04")
-	}
-
-	req := emailv2.SendNotificationRequest{
+	req := emailv2.SendBlockedRequest{
 		TenantSlugOrID: tda.ID(),
 		Email:          user.Email,
-		TemplateID:     "user_blocked",
-		TemplateVars:   map[string]any{"Reason": reason, "Until": untilStr},
+		Reason:         reason,
+		Until:          until,
 	}
-	if err := s.emailSvc.SendNotificationEmail(ctx, req); err != nil {
+	if err := s.emailSvc.SendBlockedEmail(ctx, req); err != nil {
 		log.Warn("notification email failed", logger.Err(err))
 	}
 }
@@ -360,12 +413,11 @@ func (s *userActionService) sendUnblockNotification(ctx context.Context, tda sto
 		return
 	}
 
-	req := emailv2.SendNotificationRequest{
+	req := emailv2.SendUnblockedRequest{
 		TenantSlugOrID: tda.ID(),
 		Email:          user.Email,
-		TemplateID:     "user_unblocked",
 	}
-	if err := s.emailSvc.SendNotificationEmail(ctx, req); err != nil {
+	if err := s.emailSvc.SendUnblockedEmail(ctx, req); err != nil {
 		log.Warn("notification email failed", logger.Err(err))
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
 // LoginService define las operaciones de login.
@@ -12,6 +13,22 @@ type LoginService interface {
 	// LoginPassword autentica un usuario con email/password.
 	// Devuelve tokens o indica que MFA es requerido.
 	LoginPassword(ctx context.Context, in dto.LoginRequest) (*dto.LoginResult, error)
+
+	// CompleteLogin emite tokens para un usuario ya autenticado por otro medio
+	// (passkey, segundo factor o login por email), por el mismo camino que el login por password.
+	CompleteLogin(ctx context.Context, tda store.TenantDataAccess, in AuthenticatedLogin) (*dto.LoginResult, error)
+}
+
+// AuthenticatedLogin describe un login ya verificado, listo para emitir tokens.
+type AuthenticatedLogin struct {
+	ClientID string
+	UserID   string
+	AMR      []string
+	ACR      string
+
+	// RequireMFA marca un primer factor (login por email): si el usuario tiene
+	// MFA se devuelve el challenge en lugar de los tokens.
+	RequireMFA bool
 }
 
 // ClaimsHook permite extender claims del token (CEL/webhook/etc).
@@ -11,9 +11,11 @@ import (
 	"github.com/dropDatabas3/hellojohn/internal/domain/types"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
 	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 	jwtv5 "github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
@@ -24,7 +26,9 @@ type LoginDeps struct {
 	DAL        store.DataAccessLayer
 	Issuer     *jwtx.Issuer
 	RefreshTTL time.Duration
-	ClaimsHook ClaimsHook // nil = NoOp
+	ClaimsHook ClaimsHook    // nil = NoOp
+	Lockout    lockout.Guard // nil = sin account lockout
+	SMS        sms.Sender    // nil = sin factor SMS/voz
 }
 
 type loginService struct {
@@ -46,6 +50,7 @@ var (
 	ErrPasswordNotAllowed = fmt.Errorf("password login not allowed for this client")
 	ErrInvalidCredentials = fmt.Errorf("invalid credentials")
 	ErrUserDisabled       = fmt.Errorf("user disabled")
+	ErrUserLocked         = lockout.ErrLocked
 	ErrEmailNotVerified   = fmt.Errorf("email not verified")
 	ErrNoDatabase         = fmt.Errorf("no database for tenant")
 	ErrTokenIssueFailed   = fmt.Errorf("failed to issue token")
@@ -110,12 +115,22 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 
 	user, identity, err := tda.Users().GetByEmail(ctx, tenantID, in.Email)
 	if err != nil {
+		// El email también cuenta intentos: el lockout no revela si la cuenta existe
 		log.Debug("user not found")
-		return nil, ErrInvalidCredentials
+		if err := lockout.CheckLogin(ctx, s.deps.Lockout, tda, "", in.Email); err != nil {
+			return nil, err
+		}
+		return nil, lockout.LoginFailed(ctx, s.deps.Lockout, tda, "", in.Email, ErrInvalidCredentials)
 	}
 
 	log = log.With(logger.UserID(user.ID))
 
+	// Account lockout (SecurityPolicy): antes de verificar el password
+	if err := lockout.CheckLogin(ctx, s.deps.Lockout, tda, user.ID, in.Email); err != nil {
+		log.Info("account locked")
+		return nil, err
+	}
+
 	// Verificar estado del usuario
 	if helpers.IsUserDisabled(user) {
 		log.Info("user disabled")
@@ -125,13 +140,19 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 	// Verificar password
 	if identity == nil || identity.PasswordHash == nil || *identity.PasswordHash == "" {
 		log.Debug("no password identity")
-		return nil, ErrInvalidCredentials
+		return nil, lockout.LoginFailed(ctx, s.deps.Lockout, tda, user.ID, in.Email, ErrInvalidCredentials)
 	}
 
 	if !tda.Users().CheckPassword(identity.PasswordHash, in.Password) {
 		log.Debug("password check failed")
-		return nil, ErrInvalidCredentials
+		return nil, lockout.LoginFailed(ctx, s.deps.Lockout, tda, user.ID, in.Email, ErrInvalidCredentials)
 	}
+	// Hashes importados (bcrypt, PBKDF2, ...) pasan a argon2id
+	helpers.UpgradePasswordHash(ctx, tda.Users(), user.ID, identity.PasswordHash, in.Password)
+
+	// El email quedó probado; los intentos del usuario se limpian recién
+	// pasado el MFA para que el segundo factor no se pueda adivinar sin límite.
+	lockout.LoginSucceeded(ctx, s.deps.Lockout, tda, "", in.Email)
 
 	// Paso 5: Email verification gating
 	if client.RequireEmailVerification && !user.EmailVerified {
@@ -139,59 +160,111 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 		return nil, ErrEmailNotVerified
 	}
 
-	// Paso 6: MFA gate
+	// Paso 6: MFA gate (TOTP confirmado, credenciales WebAuthn y/o teléfono verificado)
+	if methods := s.mfaMethodsFor(ctx, tda, user); len(methods) > 0 {
+		// MFA Enabled - Check trusted device
+		// For now, minimal check: if token provided, assume trust (parity TODO: validate against DB hash)
+		isTrusted := in.TrustedDeviceToken != ""
 
-	if mfaRepo := tda.MFA(); mfaRepo != nil {
-		mfaCfg, err := mfaRepo.GetTOTP(ctx, user.ID)
-		if err == nil && mfaCfg != nil && mfaCfg.ConfirmedAt != nil {
-			// MFA Enabled - Check trusted device
-			// For now, minimal check: if token provided, assume trust (parity TODO: validate against DB hash)
-			isTrusted := in.TrustedDeviceToken != ""
-
-			if !isTrusted {
-				// Create Challenge
-				mfaToken, err := tokens.GenerateOpaqueToken(32)
-				if err != nil {
-					log.Error("failed to generate mfa token", logger.Err(err))
-					return nil, ErrTokenIssueFailed
-				}
-
-				// Cache payload
-				challenge := map[string]any{
-					"uid": user.ID,
-					"tid": tenantID,
-					"cid": in.ClientID,
-					"amr": []string{"pwd"},
-					"scp": client.Scopes, // Grant all requestable scopes or just configured?
-				}
-				challengeJSON, _ := json.Marshal(challenge)
-
-				// Cache: mfa:token:<token>
-				cacheKey := "mfa:token:" + mfaToken
-				// TTL: 5 min
-				if err := tda.Cache().Set(ctx, cacheKey, string(challengeJSON), 5*time.Minute); err != nil {
-					log.Error("failed to cache mfa challenge", logger.Err(err))
-					// Fail safe
-					return nil, ErrTokenIssueFailed
-				}
-
-				return &dto.LoginResult{
-					MFARequired: true,
-					MFAToken:    mfaToken,
-					AMR:         []string{"pwd"},
-				}, nil
-			}
-
-			// Trusted device -> Upgrade trust
-			amr = append(amr, "mfa")
-			acr = "urn:hellojohn:loa:2"
+		if !isTrusted {
+			return s.mfaChallenge(ctx, log, tda, in.ClientID, client.Scopes, user.ID, amr, methods)
 		}
+
+		// Trusted device -> Upgrade trust
+		amr = append(amr, "mfa")
+		acr = "urn:hellojohn:loa:2"
 	}
 
-	// Paso 7: Claims base
-	// Paso 7: Claims base
-	grantedScopes := client.Scopes
+	lockout.LoginSucceeded(ctx, s.deps.Lockout, tda, user.ID, "")
+
+	return s.issueTokens(ctx, log, tda, in.ClientID, client.Scopes, user.ID, amr, acr)
+}
+
+// CompleteLogin emite tokens para un usuario ya autenticado por otro medio
+// (passkey, segundo factor WebAuthn). Aplica los mismos gates que el login por password.
+func (s *loginService) CompleteLogin(ctx context.Context, tda store.TenantDataAccess, in AuthenticatedLogin) (*dto.LoginResult, error) {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Component("auth.login"),
+		logger.Op("CompleteLogin"),
+		logger.TenantSlug(tda.Slug()),
+		logger.UserID(in.UserID),
+	)
+
+	client, err := tda.Clients().Get(ctx, tda.Slug(), in.ClientID)
+	if err != nil {
+		log.Debug("client not found", logger.Err(err))
+		return nil, ErrInvalidClient
+	}
+	if err := tda.RequireDB(); err != nil {
+		return nil, ErrNoDatabase
+	}
+
+	user, err := tda.Users().GetByID(ctx, in.UserID)
+	if err != nil {
+		log.Debug("user not found", logger.Err(err))
+		return nil, ErrInvalidCredentials
+	}
+	if err := lockout.CheckLogin(ctx, s.deps.Lockout, tda, user.ID, ""); err != nil {
+		log.Info("account locked")
+		return nil, err
+	}
+	if helpers.IsUserDisabled(user) {
+		log.Info("user disabled")
+		return nil, ErrUserDisabled
+	}
+	if client.RequireEmailVerification && !user.EmailVerified {
+		log.Info("email not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	if in.RequireMFA {
+		if methods := s.mfaMethodsFor(ctx, tda, user); len(methods) > 0 {
+			return s.mfaChallenge(ctx, log, tda, in.ClientID, client.Scopes, user.ID, in.AMR, methods)
+		}
+	}
+
+	lockout.LoginSucceeded(ctx, s.deps.Lockout, tda, user.ID, "")
+	return s.issueTokens(ctx, log, tda, in.ClientID, client.Scopes, user.ID, in.AMR, in.ACR)
+}
 
+// mfaChallenge cachea el challenge de segundo factor (mfa:token:<token>, 5 min)
+// para un usuario que ya pasó el primer factor amr.
+func (s *loginService) mfaChallenge(ctx context.Context, log *zap.Logger, tda store.TenantDataAccess, clientID string, scopes []string, userID string, amr, methods []string) (*dto.LoginResult, error) {
+	mfaToken, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		log.Error("failed to generate mfa token", logger.Err(err))
+		return nil, ErrTokenIssueFailed
+	}
+
+	challenge := map[string]any{
+		"uid": userID,
+		"tid": tda.ID(),
+		"cid": clientID,
+		"amr": amr,
+		"scp": scopes, // Grant all requestable scopes or just configured?
+	}
+	challengeJSON, _ := json.Marshal(challenge)
+
+	if err := tda.Cache().Set(ctx, "mfa:token:"+mfaToken, string(challengeJSON), 5*time.Minute); err != nil {
+		log.Error("failed to cache mfa challenge", logger.Err(err))
+		// Fail safe
+		return nil, ErrTokenIssueFailed
+	}
+
+	return &dto.LoginResult{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+		MFAMethods:  methods,
+		AMR:         amr,
+	}, nil
+}
+
+// issueTokens emite access token y refresh token persistente (pasos 7-9 del login).
+func (s *loginService) issueTokens(ctx context.Context, log *zap.Logger, tda store.TenantDataAccess, clientID string, grantedScopes []string, userID string, amr []string, acr string) (*dto.LoginResult, error) {
+	tenantID := tda.ID()
+
+	// Paso 7: Claims base
 	std := map[string]any{
 		"tid": tenantID,
 		"amr": amr,
@@ -203,13 +276,13 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 	// RBAC (TODO en iteración 2): roles/perms si disponibles
 
 	// Claims hook (extensible)
-	std, custom = s.deps.ClaimsHook.ApplyAccess(ctx, tenantID, in.ClientID, user.ID, grantedScopes, amr, std, custom)
+	std, custom = s.deps.ClaimsHook.ApplyAccess(ctx, tenantID, clientID, userID, grantedScopes, amr, std, custom)
 
 	// Paso 8: Resolver issuer efectivo y emitir Access Token
 	effIss := jwtx.ResolveIssuer(
 		s.deps.Issuer.Iss,
 		string(tda.Settings().IssuerMode),
-		tenantSlug,
+		tda.Slug(),
 		tda.Settings().IssuerOverride,
 	)
 
@@ -217,7 +290,7 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 	exp := now.Add(s.deps.Issuer.AccessTTL)
 
 	// Seleccionar key según modo
-	kid, priv, _, err := s.selectSigningKey(tda)
+	kid, method, priv, err := s.selectSigningKey(tda)
 	if err != nil {
 		log.Error("failed to get signing key", logger.Err(err))
 		return nil, ErrTokenIssueFailed
@@ -225,11 +298,12 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 
 	claims := jwtv5.MapClaims{
 		"iss": effIss,
-		"sub": user.ID,
-		"aud": in.ClientID,
+		"sub": userID,
+		"aud": clientID,
 		"iat": now.Unix(),
 		"nbf": now.Unix(),
 		"exp": exp.Unix(),
+		"jti": jwtx.NewJTI(),
 	}
 	for k, v := range std {
 		claims[k] = v
@@ -238,7 +312,7 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 		claims["custom"] = custom
 	}
 
-	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims)
+	tk := jwtv5.NewWithClaims(method, claims)
 	tk.Header["kid"] = kid
 	tk.Header["typ"] = "JWT"
 
@@ -261,8 +335,8 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 
 	tokenInput := repository.CreateRefreshTokenInput{
 		TenantID:   tenantID,
-		ClientID:   in.ClientID,
-		UserID:     user.ID,
+		ClientID:   clientID,
+		UserID:     userID,
 		TokenHash:  refreshHash,
 		TTLSeconds: ttlSeconds,
 	}
@@ -285,12 +359,37 @@ func (s *loginService) LoginPassword(ctx context.Context, in dto.LoginRequest) (
 // ─── Internal Helpers ───
 // Nota: helpers comunes están en internal/http/v2/helpers/
 
-func (s *loginService) selectSigningKey(tda store.TenantDataAccess) (kid string, priv any, pub any, err error) {
+// mfaMethodsFor lista los segundos factores habilitados del usuario ("totp", "webauthn", "sms").
+func (s *loginService) mfaMethodsFor(ctx context.Context, tda store.TenantDataAccess, user *repository.User) []string {
+	var methods []string
+	if mfaRepo := tda.MFA(); mfaRepo != nil {
+		if cfg, err := mfaRepo.GetTOTP(ctx, user.ID); err == nil && cfg != nil && cfg.ConfirmedAt != nil {
+			methods = append(methods, "totp")
+		}
+	}
+	if waRepo := tda.WebAuthn(); waRepo != nil {
+		if creds, err := waRepo.ListByUser(ctx, user.ID); err == nil && len(creds) > 0 {
+			methods = append(methods, "webauthn")
+		}
+	}
+	if smsFactorEnabled(tda, s.deps.SMS) && user.PhoneNumber != "" && user.PhoneNumberVerified {
+		methods = append(methods, "sms")
+	}
+	return methods
+}
+
+// selectSigningKey devuelve la clave y el método según el modo de issuer y el algoritmo del tenant.
+func (s *loginService) selectSigningKey(tda store.TenantDataAccess) (kid string, method jwtv5.SigningMethod, priv any, err error) {
 	settings := tda.Settings()
+	keyring := "" // global
 	if types.IssuerMode(settings.IssuerMode) == types.IssuerModePath {
-		return s.deps.Issuer.Keys.ActiveForTenant(tda.Slug())
+		keyring = tda.Slug()
+	}
+	if method, err = jwtx.SigningMethodFor(settings.SigningAlgorithm); err != nil {
+		return "", nil, nil, err
 	}
-	return s.deps.Issuer.Keys.Active()
+	kid, priv, err = s.deps.Issuer.Keys.ActiveSignerForTenant(keyring, settings.SigningAlgorithm)
+	return kid, method, priv, err
 }
 
 // loginAsAdmin maneja el login de administradores globales del sistema.
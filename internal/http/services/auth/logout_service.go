@@ -7,6 +7,7 @@ import (
 
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
@@ -162,6 +163,13 @@ func (s *logoutService) LogoutAll(ctx context.Context, in dto.LogoutAllRequest,
 		return ErrLogoutFailed
 	}
 
+	// Without a client filter every access token of the user goes too
+	if in.ClientID == "" {
+		if err := revocation.New(tda.Cache()).RevokeSubject(ctx, in.UserID); err != nil {
+			log.Warn("access token denylist failed", logger.Err(err))
+		}
+	}
+
 	log.Info("logout-all successful", logger.Int("revoked_count", int(count)))
 	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/dropDatabas3/hellojohn/internal/cache"
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	"github.com/dropDatabas3/hellojohn/internal/domain/types"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
@@ -105,9 +106,22 @@ func (s *mfaTOTPService) Challenge(ctx context.Context, tenantSlug string, req C
 	}
 
 	userID := ch.UserID
+
+	// Account lockout: los códigos fallidos cuentan como intentos de login del usuario
+	if s.lockout != nil && s.lockout.Check(ctx, tda, userID, "") != nil {
+		_ = s.cache.Delete(ctx, key)
+		return nil, ErrMFALocked
+	}
 	if err := s.validate2FA(ctx, mfaRepo, userID, req.Code, req.Recovery); err != nil {
+		if err == ErrMFAInvalidCode && s.lockout != nil && s.lockout.RecordFailure(ctx, tda, userID, "") != nil {
+			_ = s.cache.Delete(ctx, key)
+			return nil, ErrMFALocked
+		}
 		return nil, err
 	}
+	if s.lockout != nil {
+		s.lockout.RecordSuccess(ctx, tda, userID, "")
+	}
 
 	// 5. Remember device (optional)
 	if req.RememberDevice {
@@ -154,14 +168,15 @@ func (s *mfaTOTPService) Challenge(ctx context.Context, tenantSlug string, req C
 		settings.IssuerOverride,
 	)
 
+	keyring := "" // global
+	if types.IssuerMode(settings.IssuerMode) == types.IssuerModePath {
+		keyring = tda.Slug()
+	}
+	method, errKey := jwtx.SigningMethodFor(settings.SigningAlgorithm)
 	var kid string
 	var priv any
-	var errKey error
-
-	if types.IssuerMode(settings.IssuerMode) == types.IssuerModePath {
-		kid, priv, _, errKey = s.issuer.Keys.ActiveForTenant(tda.Slug())
-	} else {
-		kid, priv, _, errKey = s.issuer.Keys.Active()
+	if errKey == nil {
+		kid, priv, errKey = s.issuer.Keys.ActiveSignerForTenant(keyring, settings.SigningAlgorithm)
 	}
 
 	if errKey != nil {
@@ -179,6 +194,7 @@ func (s *mfaTOTPService) Challenge(ctx context.Context, tenantSlug string, req C
 		"iat": now.Unix(),
 		"nbf": now.Unix(),
 		"exp": exp.Unix(),
+		"jti": jwtx.NewJTI(),
 	}
 	for k, v := range std {
 		claims[k] = v
@@ -187,7 +203,7 @@ func (s *mfaTOTPService) Challenge(ctx context.Context, tenantSlug string, req C
 		claims["custom"] = custom
 	}
 
-	token := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims)
+	token := jwtv5.NewWithClaims(method, claims)
 	token.Header["kid"] = kid
 	token.Header["typ"] = "JWT"
 	signedAccessToken, err := token.SignedString(priv)
@@ -291,6 +307,7 @@ var (
 	ErrMFATokenNotFound   = errors.New("mfa token not found or expired")
 	ErrMFATokenInvalid    = errors.New("mfa token invalid")
 	ErrMFATenantMismatch  = errors.New("tenant mismatch")
+	ErrMFALocked          = errors.New("account temporarily locked")
 )
 
 // MFATOTPDeps contains dependencies for MFATOTPService.
@@ -301,6 +318,7 @@ type MFATOTPDeps struct {
 	RefreshTTL time.Duration
 	ClaimsHook ClaimsHook
 	MasterKey  string
+	Lockout    lockout.Guard // nil = sin account lockout
 }
 
 // mfaTOTPService implements MFATOTPService.
@@ -311,6 +329,7 @@ type mfaTOTPService struct {
 	refreshTTL time.Duration
 	claimsHook ClaimsHook
 	masterKey  string
+	lockout    lockout.Guard
 }
 
 // NewMFATOTPService creates a new MFATOTPService.
@@ -322,6 +341,7 @@ func NewMFATOTPService(d MFATOTPDeps) MFATOTPService {
 		refreshTTL: d.RefreshTTL,
 		claimsHook: d.ClaimsHook,
 		masterKey:  d.MasterKey,
+		lockout:    d.Lockout,
 	}
 }
 
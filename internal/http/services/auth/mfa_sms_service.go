@@ -0,0 +1,374 @@
+// Package auth contains the SMS / voice OTP factor service.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// MFASMSService handles phone number verification and the SMS / voice OTP
+// second factor. A verified phone counts as a factor when the tenant enables
+// it (TenantSettings.Phone.MFAEnabled).
+type MFASMSService interface {
+	// Enroll sends a verification code to a new phone number of an authenticated user.
+	Enroll(ctx context.Context, tenantSlug, userID string, req dto.SMSEnrollRequest) (*dto.SMSCodeSentResponse, error)
+
+	// Verify confirms the pending phone number with the received code.
+	Verify(ctx context.Context, tenantSlug, userID, code string) (*dto.SMSVerifyResponse, error)
+
+	// Remove deletes the user's phone number (and with it the SMS factor).
+	Remove(ctx context.Context, tenantSlug, userID string) error
+
+	// BeginChallenge sends a code to the verified phone for a pending MFA challenge.
+	BeginChallenge(ctx context.Context, tenantSlug string, req dto.SMSChallengeBeginRequest) (*dto.SMSCodeSentResponse, error)
+
+	// Challenge verifies the code of a pending MFA challenge and issues tokens.
+	Challenge(ctx context.Context, tenantSlug string, req dto.SMSChallengeRequest) (*dto.LoginResult, error)
+}
+
+// SMS factor errors
+var (
+	ErrSMSNotEnabled        = errors.New("phone verification not enabled for tenant")
+	ErrSMSInvalidPhone      = errors.New("invalid phone number")
+	ErrSMSChannelNotAllowed = errors.New("channel not allowed")
+	ErrSMSRateLimited       = errors.New("too many codes sent to this number")
+	ErrSMSSendFailed        = errors.New("failed to send code")
+	ErrSMSCodeNotFound      = errors.New("verification code not found or expired")
+	ErrSMSTooManyAttempts   = errors.New("too many failed attempts")
+	ErrSMSPhoneNotVerified  = errors.New("no verified phone number")
+	ErrSMSFactorNotEnabled  = errors.New("sms factor not enabled for tenant")
+)
+
+const (
+	// smsCodeTTL is the validity of a sent code.
+	smsCodeTTL = 5 * time.Minute
+	// smsMaxAttempts is the number of wrong codes accepted per sent code.
+	smsMaxAttempts = 5
+
+	smsEnrollPrefix    = "mfa:sms:enroll:"
+	smsChallengePrefix = "mfa:sms:challenge:"
+	smsRateLimitPrefix = "mfa:sms:rl:"
+	smsAttemptsSuffix  = ":attempts"
+)
+
+// smsCodeState is the cached state of a sent code.
+type smsCodeState struct {
+	CodeHash  string    `json:"h"`
+	Phone     string    `json:"ph"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// MFASMSDeps contains dependencies for MFASMSService.
+type MFASMSDeps struct {
+	DAL     store.DataAccessLayer
+	Sender  sms.Sender
+	Login   LoginService  // token issuance shared with password login
+	Lockout lockout.Guard // nil = no account lockout
+}
+
+type mfaSMSService struct {
+	deps MFASMSDeps
+}
+
+// NewMFASMSService creates a new MFASMSService.
+func NewMFASMSService(d MFASMSDeps) MFASMSService {
+	return &mfaSMSService{deps: d}
+}
+
+// smsEnabled reports whether phone verification is available for the tenant.
+func smsEnabled(tda store.TenantDataAccess, sender sms.Sender) bool {
+	return sender != nil && tda.Settings() != nil && tda.Settings().Phone != nil
+}
+
+// smsFactorEnabled reports whether a verified phone counts as a second factor.
+func smsFactorEnabled(tda store.TenantDataAccess, sender sms.Sender) bool {
+	return smsEnabled(tda, sender) && tda.Settings().Phone.MFAEnabled
+}
+
+// ─── Phone verification ───
+
+func (s *mfaSMSService) Enroll(ctx context.Context, tenantSlug, userID string, req dto.SMSEnrollRequest) (*dto.SMSCodeSentResponse, error) {
+	tda, err := s.tenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	phone, err := sms.NormalizeE164(req.PhoneNumber)
+	if err != nil {
+		return nil, ErrSMSInvalidPhone
+	}
+	user, err := tda.Users().GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrMFAUserNotFound
+	}
+
+	return s.sendCode(ctx, tda, user, smsEnrollPrefix+userID, phone, req.Channel)
+}
+
+func (s *mfaSMSService) Verify(ctx context.Context, tenantSlug, userID, code string) (*dto.SMSVerifyResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("mfa.sms.verify"))
+
+	tda, err := s.tenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.checkCode(ctx, tda, smsEnrollPrefix+userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tda.Users().SetPhoneNumber(ctx, userID, state.Phone, true); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrMFAUserNotFound
+		}
+		log.Error("failed to store phone number", logger.Err(err))
+		return nil, ErrMFAStoreFailed
+	}
+	return &dto.SMSVerifyResponse{PhoneNumber: state.Phone, PhoneNumberVerified: true}, nil
+}
+
+func (s *mfaSMSService) Remove(ctx context.Context, tenantSlug, userID string) error {
+	tda, err := s.tenant(ctx, tenantSlug)
+	if err != nil {
+		return err
+	}
+	if err := tda.Users().SetPhoneNumber(ctx, userID, "", false); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrMFAUserNotFound
+		}
+		return ErrMFAStoreFailed
+	}
+	_ = tda.Cache().Delete(ctx, smsEnrollPrefix+userID)
+	return nil
+}
+
+// ─── Second factor ───
+
+func (s *mfaSMSService) BeginChallenge(ctx context.Context, tenantSlug string, req dto.SMSChallengeBeginRequest) (*dto.SMSCodeSentResponse, error) {
+	tda, err := s.tenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	if !smsFactorEnabled(tda, s.deps.Sender) {
+		return nil, ErrSMSFactorNotEnabled
+	}
+	ch, err := pendingMFA(ctx, tda, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	if s.deps.Lockout != nil && s.deps.Lockout.Check(ctx, tda, ch.UserID, "") != nil {
+		return nil, ErrMFALocked
+	}
+
+	user, err := tda.Users().GetByID(ctx, ch.UserID)
+	if err != nil {
+		return nil, ErrMFAUserNotFound
+	}
+	if user.PhoneNumber == "" || !user.PhoneNumberVerified {
+		return nil, ErrSMSPhoneNotVerified
+	}
+
+	return s.sendCode(ctx, tda, user, challengeKey(req.MFAToken), user.PhoneNumber, req.Channel)
+}
+
+func (s *mfaSMSService) Challenge(ctx context.Context, tenantSlug string, req dto.SMSChallengeRequest) (*dto.LoginResult, error) {
+	tda, err := s.tenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	if !smsFactorEnabled(tda, s.deps.Sender) {
+		return nil, ErrSMSFactorNotEnabled
+	}
+	ch, err := pendingMFA(ctx, tda, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	mfaKey := "mfa:token:" + strings.TrimSpace(req.MFAToken)
+
+	// Account lockout: wrong codes count as failed login attempts of the user
+	if s.deps.Lockout != nil && s.deps.Lockout.Check(ctx, tda, ch.UserID, "") != nil {
+		_ = tda.Cache().Delete(ctx, mfaKey)
+		return nil, ErrMFALocked
+	}
+
+	state, err := s.checkCode(ctx, tda, challengeKey(req.MFAToken), req.Code)
+	if err != nil {
+		if (err == ErrMFAInvalidCode || err == ErrSMSTooManyAttempts) &&
+			s.deps.Lockout != nil && s.deps.Lockout.RecordFailure(ctx, tda, ch.UserID, "") != nil {
+			_ = tda.Cache().Delete(ctx, mfaKey)
+			return nil, ErrMFALocked
+		}
+		return nil, err
+	}
+
+	// The number may have changed since the code was sent
+	user, err := tda.Users().GetByID(ctx, ch.UserID)
+	if err != nil || !user.PhoneNumberVerified || user.PhoneNumber != state.Phone {
+		return nil, ErrSMSPhoneNotVerified
+	}
+
+	_ = tda.Cache().Delete(ctx, mfaKey)
+
+	amr := append(append([]string{}, ch.AMRBase...), "sms", "mfa")
+	return s.deps.Login.CompleteLogin(ctx, tda, AuthenticatedLogin{
+		ClientID: ch.ClientID,
+		UserID:   ch.UserID,
+		AMR:      amr,
+		ACR:      "urn:hellojohn:loa:2",
+	})
+}
+
+// ─── Helpers ───
+
+// sendCode generates a code, sends it to phone in the user's language and caches
+// its state under key. A new code replaces the previous one.
+func (s *mfaSMSService) sendCode(ctx context.Context, tda store.TenantDataAccess, user *repository.User, key, phone, channel string) (*dto.SMSCodeSentResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("mfa.sms.send"), logger.TenantSlug(tda.Slug()))
+
+	cfg := tda.Settings().Phone
+	switch channel {
+	case "", sms.ChannelSMS:
+		channel = sms.ChannelSMS
+	case sms.ChannelVoice:
+		if !cfg.VoiceEnabled {
+			return nil, ErrSMSChannelNotAllowed
+		}
+	default:
+		return nil, ErrSMSChannelNotAllowed
+	}
+
+	limiter := sms.Limiter{Cache: tda.Cache(), MaxPerHour: cfg.MaxSendsPerHour, Prefix: smsRateLimitPrefix}
+	if _, err := limiter.Allow(ctx, phone); err != nil {
+		if errors.Is(err, sms.ErrRateLimited) {
+			return nil, ErrSMSRateLimited
+		}
+		log.Error("sms rate limiter failed", logger.Err(err))
+		return nil, ErrMFAStoreFailed
+	}
+
+	code, err := newSMSCode()
+	if err != nil {
+		return nil, ErrMFACryptoFailed
+	}
+	payload, _ := json.Marshal(smsCodeState{
+		CodeHash:  tokens.SHA256Base64URL(code),
+		Phone:     phone,
+		ExpiresAt: time.Now().Add(smsCodeTTL),
+	})
+	if err := tda.Cache().Set(ctx, key, string(payload), smsCodeTTL); err != nil {
+		log.Error("failed to store sms code", logger.Err(err))
+		return nil, ErrMFAStoreFailed
+	}
+	// A new code gets a fresh attempt counter
+	_ = tda.Cache().Delete(ctx, key+smsAttemptsSuffix)
+
+	body := sms.RenderCode(cfg.Templates, s.languages(ctx, tda, user), channel, code, smsCodeTTL)
+	msg := sms.Message{To: phone, Channel: channel, Body: body}
+	if err := s.deps.Sender.Send(ctx, msg); err != nil {
+		_ = tda.Cache().Delete(ctx, key)
+		log.Warn("failed to send code", logger.String("to", sms.Mask(phone)), logger.Err(err))
+		if errors.Is(err, sms.ErrChannelNotSupported) {
+			return nil, ErrSMSChannelNotAllowed
+		}
+		return nil, ErrSMSSendFailed
+	}
+
+	return &dto.SMSCodeSentResponse{
+		Sent:      true,
+		Channel:   channel,
+		To:        sms.Mask(phone),
+		ExpiresIn: int(smsCodeTTL.Seconds()),
+	}, nil
+}
+
+// checkCode verifies code against the state under key. The state is consumed on
+// success and after smsMaxAttempts wrong codes, counted atomically next to it.
+func (s *mfaSMSService) checkCode(ctx context.Context, tda store.TenantDataAccess, key, code string) (*smsCodeState, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrMFAMissingFields
+	}
+	raw, err := tda.Cache().Get(ctx, key)
+	if err != nil {
+		if cache.IsNotFound(err) {
+			return nil, ErrSMSCodeNotFound
+		}
+		return nil, ErrMFAStoreFailed
+	}
+	var state smsCodeState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil || time.Now().After(state.ExpiresAt) {
+		_ = tda.Cache().Delete(ctx, key)
+		return nil, ErrSMSCodeNotFound
+	}
+
+	if subtle.ConstantTimeCompare([]byte(tokens.SHA256Base64URL(code)), []byte(state.CodeHash)) != 1 {
+		// Atomic counter: concurrent wrong guesses each use up an attempt
+		attempts, err := tda.Cache().Incr(ctx, key+smsAttemptsSuffix, time.Until(state.ExpiresAt))
+		if err != nil || attempts >= smsMaxAttempts {
+			_ = tda.Cache().Delete(ctx, key)
+			_ = tda.Cache().Delete(ctx, key+smsAttemptsSuffix)
+			if err != nil {
+				return nil, ErrMFAStoreFailed
+			}
+			return nil, ErrSMSTooManyAttempts
+		}
+		return nil, ErrMFAInvalidCode
+	}
+
+	_ = tda.Cache().Delete(ctx, key) // one-time use
+	_ = tda.Cache().Delete(ctx, key+smsAttemptsSuffix)
+	return &state, nil
+}
+
+func (s *mfaSMSService) tenant(ctx context.Context, tenantSlug string) (store.TenantDataAccess, error) {
+	tda, err := s.deps.DAL.ForTenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, ErrSMSNotEnabled
+	}
+	if err := tda.RequireDB(); err != nil {
+		return nil, ErrSMSNotEnabled
+	}
+	if !smsEnabled(tda, s.deps.Sender) {
+		return nil, ErrSMSNotEnabled
+	}
+	return tda, nil
+}
+
+// challengeKey keys the code of an MFA challenge by the hash of its mfa_token.
+func challengeKey(mfaToken string) string {
+	return smsChallengePrefix + tokens.SHA256Base64URL(strings.TrimSpace(mfaToken))
+}
+
+// newSMSCode returns a uniformly random 6-digit code.
+func newSMSCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// languages returns the languages to try for the message text: the user's
+// preferred one, then the tenant's default.
+func (s *mfaSMSService) languages(ctx context.Context, tda store.TenantDataAccess, user *repository.User) []string {
+	langs := []string{user.Language}
+	if tenant, err := s.deps.DAL.ConfigAccess().Tenants().GetBySlug(ctx, tda.Slug()); err == nil && tenant != nil {
+		langs = append(langs, tenant.Language)
+	}
+	return langs
+}
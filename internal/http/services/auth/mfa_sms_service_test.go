@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// phoneInbox is an sms.Sender that keeps the sent messages.
+type phoneInbox struct {
+	mu   sync.Mutex
+	msgs []sms.Message
+}
+
+func (p *phoneInbox) Send(_ context.Context, msg sms.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+var smsCodePattern = regexp.MustCompile(`\d{6}`)
+
+func (p *phoneInbox) lastCode(t *testing.T) string {
+	t.Helper()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.msgs) == 0 {
+		t.Fatal("no message sent")
+	}
+	code := smsCodePattern.FindString(p.msgs[len(p.msgs)-1].Body)
+	if code == "" {
+		t.Fatalf("no code in %q", p.msgs[len(p.msgs)-1].Body)
+	}
+	return code
+}
+
+type phoneUsers struct {
+	repository.UserRepository
+	user *repository.User
+}
+
+func (u *phoneUsers) GetByID(_ context.Context, id string) (*repository.User, error) {
+	if id != u.user.ID {
+		return nil, repository.ErrNotFound
+	}
+	return u.user, nil
+}
+
+func (u *phoneUsers) SetPhoneNumber(_ context.Context, id, phone string, verified bool) error {
+	if id != u.user.ID {
+		return repository.ErrNotFound
+	}
+	u.user.PhoneNumber, u.user.PhoneNumberVerified = phone, verified
+	return nil
+}
+
+type phoneTDA struct {
+	store.TenantDataAccess
+	settings *repository.TenantSettings
+	users    *phoneUsers
+	cache    cache.Client
+}
+
+func (f *phoneTDA) Slug() string                         { return "acme" }
+func (f *phoneTDA) Settings() *repository.TenantSettings { return f.settings }
+func (f *phoneTDA) Users() repository.UserRepository     { return f.users }
+func (f *phoneTDA) Cache() cache.Client                  { return f.cache }
+func (f *phoneTDA) RequireDB() error                     { return nil }
+
+type noTenants struct{ repository.TenantRepository }
+
+func (noTenants) GetBySlug(context.Context, string) (*repository.Tenant, error) {
+	return nil, repository.ErrNotFound
+}
+
+type phoneConfig struct{ store.ConfigAccess }
+
+func (phoneConfig) Tenants() repository.TenantRepository { return noTenants{} }
+
+type phoneDAL struct {
+	store.DataAccessLayer
+	tda *phoneTDA
+}
+
+func (d phoneDAL) ForTenant(context.Context, string) (store.TenantDataAccess, error) {
+	return d.tda, nil
+}
+
+func (d phoneDAL) ConfigAccess() store.ConfigAccess { return phoneConfig{} }
+
+func newTestSMSService(phone *repository.PhoneSettings) (MFASMSService, *phoneTDA, *phoneInbox) {
+	tda := &phoneTDA{
+		settings: &repository.TenantSettings{Phone: phone},
+		users:    &phoneUsers{user: &repository.User{ID: "u1", Email: "ana@example.com"}},
+		cache:    cache.NewMemory("test"),
+	}
+	inbox := &phoneInbox{}
+	return NewMFASMSService(MFASMSDeps{DAL: phoneDAL{tda: tda}, Sender: inbox}), tda, inbox
+}
+
+func wrongSMSCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestMFASMS_EnrollAndVerify(t *testing.T) {
+	ctx := context.Background()
+	svc, tda, inbox := newTestSMSService(&repository.PhoneSettings{})
+
+	sent, err := svc.Enroll(ctx, "acme", "u1", dto.SMSEnrollRequest{PhoneNumber: "+54 9 11 2233-4455"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sent.Sent || sent.Channel != sms.ChannelSMS || sent.To != "+54*******4455" {
+		t.Fatalf("response = %+v", sent)
+	}
+	if inbox.msgs[0].To != "+5491122334455" {
+		t.Fatalf("sent to %q, want the normalized number", inbox.msgs[0].To)
+	}
+	code := inbox.lastCode(t)
+
+	res, err := svc.Verify(ctx, "acme", "u1", code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PhoneNumber != "+5491122334455" || !tda.users.user.PhoneNumberVerified {
+		t.Fatalf("Verify = %+v, user = %+v", res, tda.users.user)
+	}
+
+	// The code is single use
+	if _, err := svc.Verify(ctx, "acme", "u1", code); !errors.Is(err, ErrSMSCodeNotFound) {
+		t.Fatalf("replay: err = %v, want ErrSMSCodeNotFound", err)
+	}
+}
+
+func TestMFASMS_WrongCodeLimit(t *testing.T) {
+	ctx := context.Background()
+	svc, tda, inbox := newTestSMSService(&repository.PhoneSettings{})
+
+	if _, err := svc.Enroll(ctx, "acme", "u1", dto.SMSEnrollRequest{PhoneNumber: "+5491122334455"}); err != nil {
+		t.Fatal(err)
+	}
+	code := inbox.lastCode(t)
+
+	for i := 1; i < smsMaxAttempts; i++ {
+		if _, err := svc.Verify(ctx, "acme", "u1", wrongSMSCode(code)); !errors.Is(err, ErrMFAInvalidCode) {
+			t.Fatalf("wrong code %d: err = %v, want ErrMFAInvalidCode", i, err)
+		}
+	}
+	if _, err := svc.Verify(ctx, "acme", "u1", wrongSMSCode(code)); !errors.Is(err, ErrSMSTooManyAttempts) {
+		t.Fatalf("wrong code %d: err = %v, want ErrSMSTooManyAttempts", smsMaxAttempts, err)
+	}
+	// The code is dropped: the right one no longer works
+	if _, err := svc.Verify(ctx, "acme", "u1", code); !errors.Is(err, ErrSMSCodeNotFound) {
+		t.Fatalf("after the limit: err = %v, want ErrSMSCodeNotFound", err)
+	}
+	if tda.users.user.PhoneNumberVerified {
+		t.Fatal("phone verified after too many attempts")
+	}
+}
+
+// Concurrent wrong guesses each use up an attempt.
+func TestMFASMS_ConcurrentWrongCodes(t *testing.T) {
+	ctx := context.Background()
+	svc, _, inbox := newTestSMSService(&repository.PhoneSettings{})
+
+	if _, err := svc.Enroll(ctx, "acme", "u1", dto.SMSEnrollRequest{PhoneNumber: "+5491122334455"}); err != nil {
+		t.Fatal(err)
+	}
+	code := inbox.lastCode(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2*smsMaxAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = svc.Verify(ctx, "acme", "u1", wrongSMSCode(code))
+		}()
+	}
+	wg.Wait()
+
+	if _, err := svc.Verify(ctx, "acme", "u1", code); !errors.Is(err, ErrSMSCodeNotFound) {
+		t.Fatalf("err = %v, want ErrSMSCodeNotFound", err)
+	}
+}
+
+func TestMFASMS_SendRules(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not enabled", func(t *testing.T) {
+		svc, _, _ := newTestSMSService(nil)
+		if _, err := svc.Enroll(ctx, "acme", "u1", dto.SMSEnrollRequest{PhoneNumber: "+5491122334455"}); !errors.Is(err, ErrSMSNotEnabled) {
+			t.Fatalf("err = %v, want ErrSMSNotEnabled", err)
+		}
+	})
+
+	t.Run("invalid phone", func(t *testing.T) {
+		svc, _, _ := newTestSMSService(&repository.PhoneSettings{})
+		if _, err := svc.Enroll(ctx, "acme", "u1", dto.SMSEnrollRequest{PhoneNumber: "1122334455"}); !errors.Is(err, ErrSMSInvalidPhone) {
+			t.Fatalf("err = %v, want ErrSMSInvalidPhone", err)
+		}
+	})
+
+	t.Run("voice not allowed", func(t *testing.T) {
+		svc, _, _ := newTestSMSService(&repository.PhoneSettings{})
+		req := dto.SMSEnrollRequest{PhoneNumber: "+5491122334455", Channel: sms.ChannelVoice}
+		if _, err := svc.Enroll(ctx, "acme", "u1", req); !errors.Is(err, ErrSMSChannelNotAllowed) {
+			t.Fatalf("err = %v, want ErrSMSChannelNotAllowed", err)
+		}
+	})
+
+	t.Run("voice in the user's language", func(t *testing.T) {
+		svc, tda, inbox := newTestSMSService(&repository.PhoneSettings{VoiceEnabled: true})
+		tda.users.user.Language = "en"
+		req := dto.SMSEnrollRequest{PhoneNumber: "+5491122334455", Channel: sms.ChannelVoice}
+		if _, err := svc.Enroll(ctx, "acme", "u1", req); err != nil {
+			t.Fatal(err)
+		}
+		msg := inbox.msgs[0]
+		if msg.Channel != sms.ChannelVoice || !regexp.MustCompile(`^Your verification code is: (\d, ){5}\d\.$`).MatchString(msg.Body) {
+			t.Fatalf("message = %+v", msg)
+		}
+	})
+
+	t.Run("resend cooldown", func(t *testing.T) {
+		svc, _, _ := newTestSMSService(&repository.PhoneSettings{})
+		req := dto.SMSEnrollRequest{PhoneNumber: "+5491122334455"}
+		if _, err := svc.Enroll(ctx, "acme", "u1", req); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.Enroll(ctx, "acme", "u1", req); !errors.Is(err, ErrSMSRateLimited) {
+			t.Fatalf("err = %v, want ErrSMSRateLimited", err)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// PasswordlessService defines passwordless email login (OTP code or magic link)
+// for the JSON API. Browser logins for /oauth2/authorize go through the session
+// endpoints instead.
+type PasswordlessService interface {
+	// Start sends the code or link and returns the flow ID.
+	Start(ctx context.Context, in dto.PasswordlessStartRequest) (*dto.PasswordlessStartResponse, error)
+
+	// Verify completes the flow and issues tokens (or the MFA challenge).
+	Verify(ctx context.Context, in dto.PasswordlessVerifyRequest) (*dto.LoginResult, error)
+}
+
+// PasswordlessDeps contains dependencies for the passwordless service.
+type PasswordlessDeps struct {
+	DAL   store.DataAccessLayer
+	Flow  passwordless.Flow
+	Login LoginService
+}
+
+type passwordlessService struct {
+	deps PasswordlessDeps
+}
+
+// NewPasswordlessService creates a new PasswordlessService.
+func NewPasswordlessService(deps PasswordlessDeps) PasswordlessService {
+	return &passwordlessService{deps: deps}
+}
+
+// Start implements PasswordlessService.
+func (s *passwordlessService) Start(ctx context.Context, in dto.PasswordlessStartRequest) (*dto.PasswordlessStartResponse, error) {
+	tda, err := s.tenant(ctx, in.TenantID, in.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	started, err := s.deps.Flow.Start(ctx, tda, passwordless.StartInput{
+		ClientID:    strings.TrimSpace(in.ClientID),
+		Email:       in.Email,
+		Method:      in.Method,
+		RedirectURI: in.RedirectURI,
+		ClientIP:    in.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasswordlessStartResponse{
+		FlowID:    started.FlowID,
+		Method:    started.Method,
+		ExpiresIn: started.ExpiresIn,
+	}, nil
+}
+
+// Verify implements PasswordlessService.
+func (s *passwordlessService) Verify(ctx context.Context, in dto.PasswordlessVerifyRequest) (*dto.LoginResult, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("passwordless.Verify"))
+
+	tda, err := s.tenant(ctx, in.TenantID, in.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.deps.Flow.Verify(ctx, tda, passwordless.VerifyInput{
+		FlowID: in.FlowID,
+		Code:   in.Code,
+		Token:  in.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.ClientID != strings.TrimSpace(in.ClientID) {
+		log.Debug("flow started by another client")
+		return nil, ErrInvalidClient
+	}
+
+	// Email is a single factor: users with MFA get the second-factor challenge
+	return s.deps.Login.CompleteLogin(ctx, tda, AuthenticatedLogin{
+		ClientID:   res.ClientID,
+		UserID:     res.UserID,
+		AMR:        res.AMR,
+		ACR:        "urn:hellojohn:loa:1",
+		RequireMFA: true,
+	})
+}
+
+func (s *passwordlessService) tenant(ctx context.Context, tenantID, clientID string) (store.TenantDataAccess, error) {
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" || strings.TrimSpace(clientID) == "" {
+		return nil, ErrMissingFields
+	}
+	tda, err := s.deps.DAL.ForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+	return tda, nil
+}
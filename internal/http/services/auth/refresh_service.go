@@ -49,6 +49,7 @@ var (
 	ErrMissingRefreshFields = errors.New("missing required fields")
 	ErrInvalidRefreshToken  = errors.New("invalid or expired refresh token")
 	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
 	ErrClientMismatch       = errors.New("client_id mismatch")
 	ErrRefreshUserDisabled  = errors.New("user disabled")
 	ErrRefreshIssueFailed   = errors.New("failed to issue tokens")
@@ -178,6 +179,7 @@ func (s *refreshService) refreshAdminJWT(ctx context.Context, tokenStr string, l
 		"iat": now.Unix(),
 		"nbf": now.Unix(),
 		"exp": exp.Unix(),
+		"jti": jwtx.NewJTI(),
 	}
 	for k, v := range std {
 		atClaims[k] = v
@@ -251,8 +253,15 @@ func (s *refreshService) refreshFromDB(ctx context.Context, in dto.RefreshReques
 		return nil, ErrInvalidRefreshToken
 	}
 
-	// Check if revoked or expired
+	// A revoked token presented again is a replay: revoke the whole family
 	now := time.Now()
+	if rt.RevokedAt != nil && strings.EqualFold(in.ClientID, rt.ClientID) {
+		if helpers.HandleRefreshTokenReuse(ctx, tda, rt) {
+			return nil, ErrRefreshTokenReused
+		}
+	}
+
+	// Check if revoked or expired
 	if rt.RevokedAt != nil || !now.Before(rt.ExpiresAt) {
 		log.Debug("refresh token revoked or expired")
 		return nil, ErrRefreshTokenRevoked
@@ -319,7 +328,7 @@ func (s *refreshService) refreshFromDB(ctx context.Context, in dto.RefreshReques
 	custom = helpers.PutSystemClaimsV2(custom, effIss, user.Metadata, nil, nil)
 
 	// Select signing key
-	kid, priv, _, err := s.selectSigningKey(tda)
+	kid, method, priv, err := s.selectSigningKey(tda)
 	if err != nil {
 		log.Error("failed to get signing key", logger.Err(err))
 		return nil, ErrRefreshIssueFailed
@@ -335,6 +344,7 @@ func (s *refreshService) refreshFromDB(ctx context.Context, in dto.RefreshReques
 		"iat": nowUTC.Unix(),
 		"nbf": nowUTC.Unix(),
 		"exp": exp.Unix(),
+		"jti": jwtx.NewJTI(),
 	}
 	for k, v := range std {
 		claims[k] = v
@@ -343,7 +353,7 @@ func (s *refreshService) refreshFromDB(ctx context.Context, in dto.RefreshReques
 		claims["custom"] = custom
 	}
 
-	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims)
+	tk := jwtv5.NewWithClaims(method, claims)
 	tk.Header["kid"] = kid
 	tk.Header["typ"] = "JWT"
 
@@ -364,11 +374,13 @@ func (s *refreshService) refreshFromDB(ctx context.Context, in dto.RefreshReques
 	ttlSeconds := int(s.deps.RefreshTTL.Seconds())
 
 	tokenInput := repository.CreateRefreshTokenInput{
-		TenantID:   tenantID,
-		ClientID:   in.ClientID,
-		UserID:     user.ID,
-		TokenHash:  newHash,
-		TTLSeconds: ttlSeconds,
+		TenantID:    tenantID,
+		ClientID:    in.ClientID,
+		UserID:      user.ID,
+		TokenHash:   newHash,
+		TTLSeconds:  ttlSeconds,
+		RotatedFrom: rt.ID,
+		SessionID:   rt.SessionID,
 	}
 
 	if _, err := tda.Tokens().Create(ctx, tokenInput); err != nil {
@@ -390,10 +402,16 @@ func (s *refreshService) refreshFromDB(ctx context.Context, in dto.RefreshReques
 	}, nil
 }
 
-func (s *refreshService) selectSigningKey(tda store.TenantDataAccess) (kid string, priv any, pub any, err error) {
+// selectSigningKey devuelve la clave y el método según el modo de issuer y el algoritmo del tenant.
+func (s *refreshService) selectSigningKey(tda store.TenantDataAccess) (kid string, method jwtv5.SigningMethod, priv any, err error) {
 	settings := tda.Settings()
+	keyring := "" // global
 	if types.IssuerMode(settings.IssuerMode) == types.IssuerModePath {
-		return s.deps.Issuer.Keys.ActiveForTenant(tda.Slug())
+		keyring = tda.Slug()
+	}
+	if method, err = jwtx.SigningMethodFor(settings.SigningAlgorithm); err != nil {
+		return "", nil, nil, err
 	}
-	return s.deps.Issuer.Keys.Active()
+	kid, priv, err = s.deps.Issuer.Keys.ActiveSignerForTenant(keyring, settings.SigningAlgorithm)
+	return kid, method, priv, err
 }
@@ -234,7 +234,7 @@ func (s *registerService) issueTokens(ctx context.Context, tda store.TenantDataA
 	custom = helpers.PutSystemClaimsV2(custom, effIss, nil, nil, nil)
 
 	// Select signing key
-	kid, priv, _, err := s.selectSigningKey(tda)
+	kid, method, priv, err := s.selectSigningKey(tda)
 	if err != nil {
 		log.Error("failed to get signing key", logger.Err(err))
 		return nil, ErrRegisterTokenFailed
@@ -250,6 +250,7 @@ func (s *registerService) issueTokens(ctx context.Context, tda store.TenantDataA
 		"iat": now.Unix(),
 		"nbf": now.Unix(),
 		"exp": exp.Unix(),
+		"jti": jwtx.NewJTI(),
 	}
 	for k, v := range std {
 		claims[k] = v
@@ -258,7 +259,7 @@ func (s *registerService) issueTokens(ctx context.Context, tda store.TenantDataA
 		claims["custom"] = custom
 	}
 
-	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims)
+	tk := jwtv5.NewWithClaims(method, claims)
 	tk.Header["kid"] = kid
 	tk.Header["typ"] = "JWT"
 
@@ -372,10 +373,16 @@ func (s *registerService) checkPasswordPolicy(ctx context.Context, pwd string, p
 	return nil
 }
 
-func (s *registerService) selectSigningKey(tda store.TenantDataAccess) (kid string, priv any, pub any, err error) {
+// selectSigningKey devuelve la clave y el método según el modo de issuer y el algoritmo del tenant.
+func (s *registerService) selectSigningKey(tda store.TenantDataAccess) (kid string, method jwtv5.SigningMethod, priv any, err error) {
 	settings := tda.Settings()
+	keyring := "" // global
 	if types.IssuerMode(settings.IssuerMode) == types.IssuerModePath {
-		return s.deps.Issuer.Keys.ActiveForTenant(tda.Slug())
+		keyring = tda.Slug()
 	}
-	return s.deps.Issuer.Keys.Active()
+	if method, err = jwtx.SigningMethodFor(settings.SigningAlgorithm); err != nil {
+		return "", nil, nil, err
+	}
+	kid, priv, err = s.deps.Issuer.Keys.ActiveSignerForTenant(keyring, settings.SigningAlgorithm)
+	return kid, method, priv, err
 }
@@ -6,8 +6,11 @@ import (
 
 	"github.com/dropDatabas3/hellojohn/internal/cache"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
 	socialsvc "github.com/dropDatabas3/hellojohn/internal/http/services/social"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -25,6 +28,10 @@ type Deps struct {
 	Providers      ProviderConfig  // Global provider configuration
 	Email          emailv2.Service // Email service for verification
 	Social         socialsvc.Services
+	Lockout        lockout.Guard // Account lockout (nil = deshabilitado)
+
+	Passwordless passwordless.Flow // Login por email (nil = deshabilitado)
+	SMS          sms.Sender        // Verificación de teléfono + MFA SMS/voz (nil = deshabilitado)
 }
 
 // Services agrupa todos los services del dominio auth.
@@ -38,18 +45,26 @@ type Services struct {
 	CompleteProfile CompleteProfileService
 	Profile         ProfileService
 	MFATOTP         MFATOTPService
+	WebAuthn        WebAuthnService
+	MFASMS          MFASMSService
+	Passwordless    PasswordlessService
 	Social          socialsvc.Services
 }
 
 // NewServices crea el agregador de services auth.
 func NewServices(d Deps) Services {
+	// Login se comparte: passkeys, WebAuthn MFA y MFA SMS emiten tokens por el mismo camino
+	login := NewLoginService(LoginDeps{
+		DAL:        d.DAL,
+		Issuer:     d.Issuer,
+		RefreshTTL: d.RefreshTTL,
+		ClaimsHook: d.ClaimsHook,
+		Lockout:    d.Lockout,
+		SMS:        d.SMS,
+	})
+
 	return Services{
-		Login: NewLoginService(LoginDeps{
-			DAL:        d.DAL,
-			Issuer:     d.Issuer,
-			RefreshTTL: d.RefreshTTL,
-			ClaimsHook: d.ClaimsHook,
-		}),
+		Login: login,
 		Refresh: NewRefreshService(RefreshDeps{
 			DAL:        d.DAL,
 			Issuer:     d.Issuer,
@@ -89,7 +104,39 @@ func NewServices(d Deps) Services {
 			Cache:      d.Cache,
 			RefreshTTL: d.RefreshTTL,
 			ClaimsHook: d.ClaimsHook,
+			Lockout:    d.Lockout,
 		}),
-		Social: d.Social,
+		WebAuthn: NewWebAuthnService(WebAuthnDeps{
+			DAL:     d.DAL,
+			Issuer:  d.Issuer,
+			Login:   login,
+			Lockout: d.Lockout,
+		}),
+		MFASMS:       newMFASMS(d, login),
+		Passwordless: newPasswordless(d, login),
+		Social:       d.Social,
+	}
+}
+
+func newMFASMS(d Deps, login LoginService) MFASMSService {
+	if d.SMS == nil {
+		return nil
+	}
+	return NewMFASMSService(MFASMSDeps{
+		DAL:     d.DAL,
+		Sender:  d.SMS,
+		Login:   login,
+		Lockout: d.Lockout,
+	})
+}
+
+func newPasswordless(d Deps, login LoginService) PasswordlessService {
+	if d.Passwordless == nil {
+		return nil
 	}
+	return NewPasswordlessService(PasswordlessDeps{
+		DAL:   d.DAL,
+		Flow:  d.Passwordless,
+		Login: login,
+	})
 }
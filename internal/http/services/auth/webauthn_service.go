@@ -0,0 +1,616 @@
+// Package auth contains the WebAuthn / passkey service.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/auth"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/webauthn"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// WebAuthnService handles FIDO2/WebAuthn credentials: registration, use as a
+// second factor after password login, and passwordless (passkey) login.
+type WebAuthnService interface {
+	// BeginRegistration returns creation options for a new credential of an authenticated user.
+	BeginRegistration(ctx context.Context, tenantSlug, userID, clientID string) (*dto.WebAuthnCreationOptions, error)
+
+	// FinishRegistration verifies the attestation and stores the credential.
+	FinishRegistration(ctx context.Context, tenantSlug, userID, clientID string, req dto.WebAuthnRegisterFinishRequest) (*dto.WebAuthnCredentialResponse, error)
+
+	// ListCredentials lists the user's credentials.
+	ListCredentials(ctx context.Context, tenantSlug, userID string) ([]dto.WebAuthnCredentialResponse, error)
+
+	// RenameCredential changes the display name of one of the user's credentials.
+	RenameCredential(ctx context.Context, tenantSlug, userID, credentialID, name string) error
+
+	// DeleteCredential removes one of the user's credentials.
+	DeleteCredential(ctx context.Context, tenantSlug, userID, credentialID string) error
+
+	// BeginMFA returns request options for a pending MFA challenge (mfa_token from login).
+	BeginMFA(ctx context.Context, tenantSlug, mfaToken string) (*dto.WebAuthnRequestOptions, error)
+
+	// FinishMFA verifies the assertion for a pending MFA challenge and issues tokens.
+	FinishMFA(ctx context.Context, tenantSlug string, req dto.WebAuthnMFAFinishRequest) (*dto.LoginResult, error)
+
+	// BeginLogin returns request options for discoverable-credential (passkey) login.
+	BeginLogin(ctx context.Context, in dto.PasskeyLoginBeginRequest) (*dto.WebAuthnRequestOptions, error)
+
+	// FinishLogin verifies a passkey assertion and issues tokens like password login.
+	FinishLogin(ctx context.Context, in dto.PasskeyLoginFinishRequest) (*dto.LoginResult, error)
+}
+
+// WebAuthn errors
+var (
+	ErrWebAuthnNotSupported       = errors.New("webauthn not supported for tenant")
+	ErrWebAuthnInvalidRequest     = errors.New("invalid webauthn credential payload")
+	ErrWebAuthnCeremonyNotFound   = errors.New("webauthn challenge not found or expired")
+	ErrWebAuthnVerificationFailed = errors.New("webauthn verification failed")
+	ErrWebAuthnCredentialExists   = errors.New("webauthn credential already registered")
+	ErrWebAuthnCredentialNotFound = errors.New("webauthn credential not found")
+)
+
+const (
+	// webAuthnTimeout is both the browser timeout and the ceremony TTL.
+	webAuthnTimeout = 5 * time.Minute
+
+	webAuthnCeremonyPrefix = "webauthn:ceremony:"
+	webAuthnMaxNameLen     = 64
+
+	ceremonyRegister = "register"
+	ceremonyMFA      = "mfa"
+	ceremonyLogin    = "login"
+)
+
+// webAuthnCeremony is the cached state of a begun ceremony, keyed by its challenge.
+type webAuthnCeremony struct {
+	Purpose  string `json:"p"`
+	UserID   string `json:"uid,omitempty"`
+	ClientID string `json:"cid,omitempty"`
+	MFAToken string `json:"mfa,omitempty"`
+}
+
+// WebAuthnDeps contains dependencies for WebAuthnService.
+type WebAuthnDeps struct {
+	DAL     store.DataAccessLayer
+	Issuer  *jwtx.Issuer
+	Login   LoginService  // token issuance shared with password login
+	Lockout lockout.Guard // nil = no account lockout
+}
+
+type webAuthnService struct {
+	deps WebAuthnDeps
+}
+
+// NewWebAuthnService creates a new WebAuthnService.
+func NewWebAuthnService(d WebAuthnDeps) WebAuthnService {
+	return &webAuthnService{deps: d}
+}
+
+// ─── Registration ───
+
+func (s *webAuthnService) BeginRegistration(ctx context.Context, tenantSlug, userID, clientID string) (*dto.WebAuthnCreationOptions, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("webauthn.register.begin"))
+
+	tda, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	user, err := tda.Users().GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrMFAUserNotFound
+	}
+	existing, err := repo.ListByUser(ctx, userID)
+	if err != nil {
+		log.Error("failed to list credentials", logger.Err(err))
+		return nil, ErrMFAStoreFailed
+	}
+
+	challenge, err := s.beginCeremony(ctx, tda, webAuthnCeremony{Purpose: ceremonyRegister, UserID: userID, ClientID: clientID})
+	if err != nil {
+		log.Error("failed to store webauthn ceremony", logger.Err(err))
+		return nil, ErrMFAStoreFailed
+	}
+
+	cfg := s.rpConfig(ctx, tda, clientID)
+	displayName := user.Name
+	if displayName == "" {
+		displayName = user.Email
+	}
+	opts := &dto.WebAuthnCreationOptions{
+		Challenge: challenge,
+		RP:        dto.WebAuthnRP{ID: cfg.RPID, Name: cfg.RPName},
+		User: dto.WebAuthnUser{
+			ID:          webauthn.EncodeBase64URL([]byte(userID)),
+			Name:        user.Email,
+			DisplayName: displayName,
+		},
+		Timeout:                webAuthnTimeout.Milliseconds(),
+		ExcludeCredentials:     descriptors(existing),
+		AuthenticatorSelection: dto.WebAuthnAuthenticatorSelection{ResidentKey: "preferred", UserVerification: "preferred"},
+		Attestation:            "none",
+	}
+	for _, alg := range webauthn.SupportedAlgorithms {
+		opts.PubKeyCredParams = append(opts.PubKeyCredParams, dto.WebAuthnCredentialParam{Type: "public-key", Alg: alg})
+	}
+	return opts, nil
+}
+
+func (s *webAuthnService) FinishRegistration(ctx context.Context, tenantSlug, userID, clientID string, req dto.WebAuthnRegisterFinishRequest) (*dto.WebAuthnCredentialResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("webauthn.register.finish"), logger.UserID(userID))
+
+	tda, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	clientData, err := webauthn.DecodeBase64URL(req.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrWebAuthnInvalidRequest
+	}
+	attObj, err := webauthn.DecodeBase64URL(req.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, ErrWebAuthnInvalidRequest
+	}
+
+	challenge, cer, err := s.consumeCeremony(ctx, tda, clientData)
+	if err != nil {
+		return nil, err
+	}
+	if cer.Purpose != ceremonyRegister || cer.UserID != userID {
+		return nil, ErrWebAuthnCeremonyNotFound
+	}
+
+	cred, err := webauthn.VerifyRegistration(s.rpConfig(ctx, tda, cer.ClientID), challenge, clientData, attObj, false)
+	if err != nil {
+		log.Warn("attestation verification failed", logger.Err(err))
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	credentialID := webauthn.EncodeBase64URL(cred.ID)
+	if _, err := repo.GetByCredentialID(ctx, credentialID); err == nil {
+		return nil, ErrWebAuthnCredentialExists
+	}
+
+	name := normalizeCredentialName(req.Name)
+	if name == "" {
+		name = "Security key"
+		if cred.BackupEligible {
+			name = "Passkey"
+		}
+	}
+	aaguid := ""
+	if id, err := uuid.FromBytes(cred.AAGUID); err == nil {
+		aaguid = id.String()
+	}
+
+	rec := &repository.WebAuthnCredential{
+		UserID:            userID,
+		CredentialID:      credentialID,
+		PublicKey:         cred.PublicKey,
+		SignCount:         cred.SignCount,
+		AAGUID:            aaguid,
+		Transports:        req.Credential.Response.Transports,
+		AttestationFormat: cred.AttestationFormat,
+		Name:              name,
+		BackupEligible:    cred.BackupEligible,
+		BackupState:       cred.BackupState,
+	}
+	if err := repo.Create(ctx, rec); err != nil {
+		log.Error("failed to store credential", logger.Err(err))
+		return nil, ErrMFAStoreFailed
+	}
+
+	log.Info("webauthn credential registered", logger.String("format", cred.AttestationFormat))
+	resp := toCredentialResponse(*rec)
+	return &resp, nil
+}
+
+// ─── Credential management ───
+
+func (s *webAuthnService) ListCredentials(ctx context.Context, tenantSlug, userID string) ([]dto.WebAuthnCredentialResponse, error) {
+	_, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, ErrMFAStoreFailed
+	}
+	out := make([]dto.WebAuthnCredentialResponse, 0, len(creds))
+	for _, c := range creds {
+		out = append(out, toCredentialResponse(c))
+	}
+	return out, nil
+}
+
+func (s *webAuthnService) RenameCredential(ctx context.Context, tenantSlug, userID, credentialID, name string) error {
+	name = normalizeCredentialName(name)
+	if name == "" {
+		return ErrMFAMissingFields
+	}
+	_, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return err
+	}
+	return s.mapCredentialErr(repo.Rename(ctx, userID, credentialID, name))
+}
+
+func (s *webAuthnService) DeleteCredential(ctx context.Context, tenantSlug, userID, credentialID string) error {
+	_, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return err
+	}
+	if err := s.mapCredentialErr(repo.Delete(ctx, userID, credentialID)); err != nil {
+		return err
+	}
+	logger.From(ctx).Info("webauthn credential deleted", logger.UserID(userID))
+	return nil
+}
+
+// ─── Second factor ───
+
+func (s *webAuthnService) BeginMFA(ctx context.Context, tenantSlug, mfaToken string) (*dto.WebAuthnRequestOptions, error) {
+	tda, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := pendingMFA(ctx, tda, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := repo.ListByUser(ctx, ch.UserID)
+	if err != nil {
+		return nil, ErrMFAStoreFailed
+	}
+	if len(creds) == 0 {
+		return nil, ErrMFANotInitialized
+	}
+
+	challenge, err := s.beginCeremony(ctx, tda, webAuthnCeremony{Purpose: ceremonyMFA, UserID: ch.UserID, ClientID: ch.ClientID, MFAToken: mfaToken})
+	if err != nil {
+		return nil, ErrMFAStoreFailed
+	}
+	return &dto.WebAuthnRequestOptions{
+		Challenge:        challenge,
+		Timeout:          webAuthnTimeout.Milliseconds(),
+		RPID:             s.rpConfig(ctx, tda, ch.ClientID).RPID,
+		AllowCredentials: descriptors(creds),
+		UserVerification: "preferred",
+	}, nil
+}
+
+func (s *webAuthnService) FinishMFA(ctx context.Context, tenantSlug string, req dto.WebAuthnMFAFinishRequest) (*dto.LoginResult, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("webauthn.mfa.finish"))
+
+	tda, repo, err := s.tenantRepo(ctx, tenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := pendingMFA(ctx, tda, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	mfaKey := "mfa:token:" + strings.TrimSpace(req.MFAToken)
+
+	// Account lockout: failed assertions count as failed login attempts of the user
+	if s.deps.Lockout != nil && s.deps.Lockout.Check(ctx, tda, ch.UserID, "") != nil {
+		_ = tda.Cache().Delete(ctx, mfaKey)
+		return nil, ErrMFALocked
+	}
+
+	if _, err := s.verifyAssertion(ctx, tda, repo, req.Credential, ceremonyMFA, ch.UserID, func(cer webAuthnCeremony) bool {
+		return cer.UserID == ch.UserID && cer.MFAToken == strings.TrimSpace(req.MFAToken)
+	}, false); err != nil {
+		log.Warn("webauthn assertion failed", logger.Err(err))
+		if err == ErrWebAuthnVerificationFailed && s.deps.Lockout != nil && s.deps.Lockout.RecordFailure(ctx, tda, ch.UserID, "") != nil {
+			_ = tda.Cache().Delete(ctx, mfaKey)
+			return nil, ErrMFALocked
+		}
+		return nil, err
+	}
+
+	_ = tda.Cache().Delete(ctx, mfaKey)
+
+	amr := append(append([]string{}, ch.AMRBase...), "hwk", "mfa")
+	return s.deps.Login.CompleteLogin(ctx, tda, AuthenticatedLogin{
+		ClientID: ch.ClientID,
+		UserID:   ch.UserID,
+		AMR:      amr,
+		ACR:      "urn:hellojohn:loa:2",
+	})
+}
+
+// ─── Passkey login ───
+
+func (s *webAuthnService) BeginLogin(ctx context.Context, in dto.PasskeyLoginBeginRequest) (*dto.WebAuthnRequestOptions, error) {
+	in.TenantID = strings.TrimSpace(in.TenantID)
+	in.ClientID = strings.TrimSpace(in.ClientID)
+	if in.TenantID == "" || in.ClientID == "" {
+		return nil, ErrMissingFields
+	}
+
+	tda, err := s.deps.DAL.ForTenant(ctx, in.TenantID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+	if _, err := tda.Clients().Get(ctx, tda.Slug(), in.ClientID); err != nil {
+		return nil, ErrInvalidClient
+	}
+	if err := tda.RequireDB(); err != nil {
+		return nil, ErrNoDatabase
+	}
+	if tda.WebAuthn() == nil {
+		return nil, ErrWebAuthnNotSupported
+	}
+
+	challenge, err := s.beginCeremony(ctx, tda, webAuthnCeremony{Purpose: ceremonyLogin, ClientID: in.ClientID})
+	if err != nil {
+		return nil, ErrTokenIssueFailed
+	}
+	// Discoverable credentials: no allowCredentials, the authenticator picks the account
+	return &dto.WebAuthnRequestOptions{
+		Challenge:        challenge,
+		Timeout:          webAuthnTimeout.Milliseconds(),
+		RPID:             s.rpConfig(ctx, tda, in.ClientID).RPID,
+		UserVerification: "required",
+	}, nil
+}
+
+func (s *webAuthnService) FinishLogin(ctx context.Context, in dto.PasskeyLoginFinishRequest) (*dto.LoginResult, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("webauthn.login.finish"))
+
+	in.TenantID = strings.TrimSpace(in.TenantID)
+	in.ClientID = strings.TrimSpace(in.ClientID)
+	if in.TenantID == "" || in.ClientID == "" {
+		return nil, ErrMissingFields
+	}
+	tda, err := s.deps.DAL.ForTenant(ctx, in.TenantID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+	if err := tda.RequireDB(); err != nil {
+		return nil, ErrNoDatabase
+	}
+	repo := tda.WebAuthn()
+	if repo == nil {
+		return nil, ErrWebAuthnNotSupported
+	}
+
+	// Passwordless: user verification is mandatory
+	cred, err := s.verifyAssertion(ctx, tda, repo, in.Credential, ceremonyLogin, "", func(cer webAuthnCeremony) bool {
+		return cer.ClientID == in.ClientID
+	}, true)
+	if err != nil {
+		log.Warn("passkey assertion failed", logger.Err(err))
+		if err == ErrWebAuthnCredentialNotFound || err == ErrWebAuthnVerificationFailed {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	log.Info("passkey verified", logger.UserID(cred.UserID))
+	return s.deps.Login.CompleteLogin(ctx, tda, AuthenticatedLogin{
+		ClientID: in.ClientID,
+		UserID:   cred.UserID,
+		AMR:      []string{"hwk", "mfa"},
+		ACR:      "urn:hellojohn:loa:2",
+	})
+}
+
+// ─── Helpers ───
+
+// verifyAssertion consumes the ceremony, verifies the assertion against the stored
+// credential and records its usage. owner is the user the credential must belong to
+// ("" = any, discoverable login); match checks the ceremony belongs to this flow.
+func (s *webAuthnService) verifyAssertion(ctx context.Context, tda store.TenantDataAccess, repo repository.WebAuthnRepository, in dto.WebAuthnAssertionCredential, purpose, owner string, match func(webAuthnCeremony) bool, requireUV bool) (*repository.WebAuthnCredential, error) {
+	clientData, err1 := webauthn.DecodeBase64URL(in.Response.ClientDataJSON)
+	authData, err2 := webauthn.DecodeBase64URL(in.Response.AuthenticatorData)
+	sig, err3 := webauthn.DecodeBase64URL(in.Response.Signature)
+	rawID, err4 := webauthn.DecodeBase64URL(in.RawID)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || len(rawID) == 0 {
+		return nil, ErrWebAuthnInvalidRequest
+	}
+
+	challenge, cer, err := s.consumeCeremony(ctx, tda, clientData)
+	if err != nil {
+		return nil, err
+	}
+	if cer.Purpose != purpose || !match(cer) {
+		return nil, ErrWebAuthnCeremonyNotFound
+	}
+
+	cred, err := repo.GetByCredentialID(ctx, webauthn.EncodeBase64URL(rawID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrWebAuthnCredentialNotFound
+		}
+		return nil, ErrMFAStoreFailed
+	}
+	// Another user's credential: reject before verifying or touching its usage
+	if owner != "" && cred.UserID != owner {
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	// userHandle (discoverable credentials) must match the credential owner
+	if in.Response.UserHandle != "" {
+		if handle, err := webauthn.DecodeBase64URL(in.Response.UserHandle); err != nil || string(handle) != cred.UserID {
+			return nil, ErrWebAuthnVerificationFailed
+		}
+	}
+
+	res, err := webauthn.VerifyAssertion(s.rpConfig(ctx, tda, cer.ClientID), challenge, clientData, authData, sig, cred.PublicKey, cred.SignCount, requireUV)
+	if err != nil {
+		if err == webauthn.ErrSignCounter {
+			logger.From(ctx).Warn("webauthn sign counter regression, possible cloned authenticator", logger.UserID(cred.UserID))
+		}
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	if err := repo.UpdateUsage(ctx, cred.ID, res.SignCount, res.BackupState); err != nil {
+		logger.From(ctx).Warn("failed to update credential usage", logger.Err(err))
+	}
+	return cred, nil
+}
+
+// beginCeremony generates a challenge and caches the ceremony state under it.
+func (s *webAuthnService) beginCeremony(ctx context.Context, tda store.TenantDataAccess, cer webAuthnCeremony) (string, error) {
+	raw, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+	challenge := webauthn.EncodeBase64URL(raw)
+	payload, _ := json.Marshal(cer)
+	if err := tda.Cache().Set(ctx, webAuthnCeremonyPrefix+challenge, string(payload), webAuthnTimeout); err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+// consumeCeremony loads (single use) the ceremony referenced by clientDataJSON.
+func (s *webAuthnService) consumeCeremony(ctx context.Context, tda store.TenantDataAccess, clientData []byte) ([]byte, webAuthnCeremony, error) {
+	var cer webAuthnCeremony
+	challenge, err := webauthn.ClientDataChallenge(clientData)
+	if err != nil {
+		return nil, cer, ErrWebAuthnInvalidRequest
+	}
+	key := webAuthnCeremonyPrefix + challenge
+	payload, err := tda.Cache().Get(ctx, key)
+	if err != nil {
+		if cache.IsNotFound(err) {
+			return nil, cer, ErrWebAuthnCeremonyNotFound
+		}
+		return nil, cer, ErrMFAStoreFailed
+	}
+	_ = tda.Cache().Delete(ctx, key)
+
+	if err := json.Unmarshal([]byte(payload), &cer); err != nil {
+		return nil, cer, ErrWebAuthnCeremonyNotFound
+	}
+	raw, err := webauthn.DecodeBase64URL(challenge)
+	if err != nil {
+		return nil, cer, ErrWebAuthnInvalidRequest
+	}
+	return raw, cer, nil
+}
+
+// pendingMFA reads the MFA challenge created by the first factor.
+func pendingMFA(ctx context.Context, tda store.TenantDataAccess, mfaToken string) (*mfaChallenge, error) {
+	mfaToken = strings.TrimSpace(mfaToken)
+	if mfaToken == "" {
+		return nil, ErrMFAMissingFields
+	}
+	payload, err := tda.Cache().Get(ctx, "mfa:token:"+mfaToken)
+	if err != nil {
+		if cache.IsNotFound(err) {
+			return nil, ErrMFATokenNotFound
+		}
+		return nil, ErrMFAStoreFailed
+	}
+	var ch mfaChallenge
+	if err := json.Unmarshal([]byte(payload), &ch); err != nil {
+		return nil, ErrMFATokenInvalid
+	}
+	if ch.TenantID != tda.ID() {
+		return nil, ErrMFATenantMismatch
+	}
+	return &ch, nil
+}
+
+func (s *webAuthnService) tenantRepo(ctx context.Context, tenantSlug string) (store.TenantDataAccess, repository.WebAuthnRepository, error) {
+	tda, err := s.deps.DAL.ForTenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, nil, ErrWebAuthnNotSupported
+	}
+	if err := tda.RequireDB(); err != nil {
+		return nil, nil, ErrWebAuthnNotSupported
+	}
+	repo := tda.WebAuthn()
+	if repo == nil {
+		return nil, nil, ErrWebAuthnNotSupported
+	}
+	return tda, repo, nil
+}
+
+func (s *webAuthnService) mapCredentialErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case repository.IsNotFound(err):
+		return ErrWebAuthnCredentialNotFound
+	default:
+		return ErrMFAStoreFailed
+	}
+}
+
+// rpConfig resolves the relying party for a tenant.
+// RP ID: WEBAUTHN_RP_ID or the issuer host. Origins: WEBAUTHN_ORIGINS, the
+// issuer origin and, when a client is involved, its allowed origins.
+func (s *webAuthnService) rpConfig(ctx context.Context, tda store.TenantDataAccess, clientID string) webauthn.Config {
+	cfg := webauthn.Config{
+		RPID:   strings.TrimSpace(os.Getenv("WEBAUTHN_RP_ID")),
+		RPName: strings.TrimSpace(os.Getenv("WEBAUTHN_RP_NAME")),
+	}
+	if cfg.RPName == "" {
+		cfg.RPName = mfaConfigIssuer()
+	}
+	for _, o := range strings.Split(os.Getenv("WEBAUTHN_ORIGINS"), ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			cfg.Origins = append(cfg.Origins, o)
+		}
+	}
+	if s.deps.Issuer != nil {
+		if u, err := url.Parse(s.deps.Issuer.Iss); err == nil && u.Host != "" {
+			if cfg.RPID == "" {
+				cfg.RPID = u.Hostname()
+			}
+			cfg.Origins = append(cfg.Origins, u.Scheme+"://"+u.Host)
+		}
+	}
+	if clientID != "" {
+		if client, err := tda.Clients().Get(ctx, tda.Slug(), clientID); err == nil && client != nil {
+			cfg.Origins = append(cfg.Origins, client.AllowedOrigins...)
+		}
+	}
+	return cfg
+}
+
+func descriptors(creds []repository.WebAuthnCredential) []dto.WebAuthnCredentialDescriptor {
+	out := make([]dto.WebAuthnCredentialDescriptor, 0, len(creds))
+	for _, c := range creds {
+		out = append(out, dto.WebAuthnCredentialDescriptor{Type: "public-key", ID: c.CredentialID, Transports: c.Transports})
+	}
+	return out
+}
+
+func toCredentialResponse(c repository.WebAuthnCredential) dto.WebAuthnCredentialResponse {
+	return dto.WebAuthnCredentialResponse{
+		ID:             c.ID,
+		Name:           c.Name,
+		AAGUID:         c.AAGUID,
+		Transports:     c.Transports,
+		BackupEligible: c.BackupEligible,
+		BackupState:    c.BackupState,
+		CreatedAt:      c.CreatedAt,
+		LastUsedAt:     c.LastUsedAt,
+	}
+}
+
+func normalizeCredentialName(name string) string {
+	name = strings.TrimSpace(name)
+	if r := []rune(name); len(r) > webAuthnMaxNameLen {
+		name = string(r[:webAuthnMaxNameLen])
+	}
+	return name
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	"github.com/dropDatabas3/hellojohn/internal/security/password"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
@@ -27,6 +28,8 @@ type FlowsService interface {
 	VerifyEmailConfirm(ctx context.Context, tda store.TenantDataAccess, req dto.VerifyEmailConfirmRequest) (*dto.VerifyEmailResult, error)
 	ForgotPassword(ctx context.Context, tda store.TenantDataAccess, req dto.ForgotPasswordRequest) error
 	ResetPassword(ctx context.Context, tda store.TenantDataAccess, req dto.ResetPasswordRequest) (*dto.ResetPasswordResult, error)
+	UnlockRequest(ctx context.Context, tda store.TenantDataAccess, req dto.UnlockRequestRequest) error
+	UnlockConfirm(ctx context.Context, tda store.TenantDataAccess, req dto.UnlockConfirmRequest) error
 }
 
 // TokenIssuer interface for issuing tokens (auto-login support)
@@ -43,6 +46,7 @@ type FlowsDeps struct {
 	AutoLoginReset bool
 	Policy         *password.Policy
 	Issuer         TokenIssuer
+	Lockout        lockout.Guard // nil = unlock flows disabled
 }
 
 type flowsService struct {
@@ -53,6 +57,7 @@ type flowsService struct {
 	autoLogin bool
 	policy    *password.Policy
 	issuer    TokenIssuer
+	lockout   lockout.Guard
 }
 
 // NewFlowsService creates a new FlowsService.
@@ -73,6 +78,7 @@ func NewFlowsService(deps FlowsDeps) FlowsService {
 		autoLogin: deps.AutoLoginReset,
 		policy:    deps.Policy,
 		issuer:    deps.Issuer,
+		lockout:   deps.Lockout,
 	}
 }
 
@@ -464,3 +470,51 @@ func (s *flowsService) ResetPassword(ctx context.Context, tda store.TenantDataAc
 	log.Info("password reset completed", zap.String("user_id", tok.UserID))
 	return res, nil
 }
+
+func (s *flowsService) UnlockRequest(ctx context.Context, tda store.TenantDataAccess, req dto.UnlockRequestRequest) error {
+	log := logger.From(ctx).With(logger.Op("UnlockRequest"))
+
+	if req.Email == "" {
+		return ErrFlowsMissingEmail
+	}
+	if err := tda.RequireDB(); err != nil {
+		return ErrFlowsNoDatabase
+	}
+	if req.TenantID != "" && req.TenantID != tda.ID() && req.TenantID != tda.Slug() {
+		return ErrFlowsTenantMismatch
+	}
+	if s.lockout == nil {
+		return nil
+	}
+
+	// anti-enum: nunca indica si la cuenta existe o está bloqueada
+	if err := s.lockout.RequestUnlock(ctx, tda, req.Email); err != nil {
+		log.Warn("unlock request failed (soft-fail)", logger.Err(err))
+	}
+	return nil
+}
+
+func (s *flowsService) UnlockConfirm(ctx context.Context, tda store.TenantDataAccess, req dto.UnlockConfirmRequest) error {
+	log := logger.From(ctx).With(logger.Op("UnlockConfirm"))
+
+	if req.Token == "" {
+		return ErrFlowsMissingToken
+	}
+	if err := tda.RequireDB(); err != nil {
+		return ErrFlowsNoDatabase
+	}
+	if req.TenantID != "" && req.TenantID != tda.ID() && req.TenantID != tda.Slug() {
+		return ErrFlowsTenantMismatch
+	}
+	if s.lockout == nil {
+		return ErrFlowsInvalidToken
+	}
+
+	if err := s.lockout.UnlockWithToken(ctx, tda, req.Token); err != nil {
+		log.Debug("unlock token rejected", logger.Err(err))
+		return ErrFlowsInvalidToken
+	}
+
+	log.Info("account unlocked via email link")
+	return nil
+}
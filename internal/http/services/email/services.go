@@ -5,6 +5,7 @@ import (
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
 	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
 	"github.com/dropDatabas3/hellojohn/internal/security/password"
 )
 
@@ -17,6 +18,7 @@ type Deps struct {
 	AutoLoginReset bool
 	Policy         *password.Policy
 	Issuer         TokenIssuer
+	Lockout        lockout.Guard
 }
 
 // Services agrupa todos los services del dominio email.
@@ -35,6 +37,7 @@ func NewServices(d Deps) Services {
 			AutoLoginReset: d.AutoLoginReset,
 			Policy:         d.Policy,
 			Issuer:         d.Issuer,
+			Lockout:        d.Lockout,
 		}),
 	}
 }
@@ -0,0 +1,395 @@
+package lockout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// Guard enforces the tenant's account lockout policy. Failed attempts are
+// counted per user and per identifier (the email typed at login), so stuffing
+// attacks against unknown emails are throttled the same way as real accounts
+// and a lockout doesn't reveal whether an account exists.
+// Every method is a no-op when the tenant has no MaxLoginAttempts.
+type Guard interface {
+	// Check returns ErrLocked if the user or the identifier is locked.
+	// userID or identifier may be empty.
+	Check(ctx context.Context, tda store.TenantDataAccess, userID, identifier string) error
+
+	// RecordFailure counts a failed attempt. It returns ErrLocked when the
+	// attempt reaches MaxLoginAttempts and the lockout starts; the user is then
+	// emailed an unlock link.
+	RecordFailure(ctx context.Context, tda store.TenantDataAccess, userID, identifier string) error
+
+	// RecordSuccess clears the failed attempts after a successful login.
+	RecordSuccess(ctx context.Context, tda store.TenantDataAccess, userID, identifier string)
+
+	// RequestUnlock emails a new unlock link if the account of email is locked.
+	// It returns nil whether or not the account exists (anti-enumeration), and
+	// sms.ErrRateLimited when email exceeds the unlock request limit.
+	RequestUnlock(ctx context.Context, tda store.TenantDataAccess, email string) error
+
+	// UnlockWithToken lifts the lockout referenced by an unlock link token.
+	UnlockWithToken(ctx context.Context, tda store.TenantDataAccess, token string) error
+
+	// Unlock lifts a user's lockout (admin). The user is notified if it was locked.
+	Unlock(ctx context.Context, tda store.TenantDataAccess, userID string) error
+
+	// ListLocked lists the tenant's locked accounts and identifiers.
+	ListLocked(ctx context.Context, tda store.TenantDataAccess) ([]LockedAccount, error)
+}
+
+// LockedAccount is a lockout in effect.
+type LockedAccount struct {
+	UserID      string // empty for identifiers with no known user
+	Email       string
+	Failures    int
+	LockedUntil time.Time
+}
+
+// Errors
+var (
+	ErrLocked             = errors.New("account temporarily locked")
+	ErrInvalidUnlockToken = errors.New("unlock token invalid or expired")
+)
+
+const (
+	// DefaultLockoutDuration applies when the policy sets MaxLoginAttempts only.
+	DefaultLockoutDuration = 15 * time.Minute
+
+	lockoutReason     = "too many failed sign-in attempts"
+	unlockTokenPrefix = "lockout:unlock:"
+	userKeyPrefix     = "user:"
+	identifierPrefix  = "identifier:"
+
+	// Unlock emails per address, so the endpoint can't be used to flood a mailbox
+	unlockRatePrefix  = "lockout:unlock:rl:"
+	unlockMaxPerHour  = 3
+	unlockRetryPeriod = time.Minute
+)
+
+// UserKey is the attempt key of a user.
+func UserKey(userID string) string { return userKeyPrefix + userID }
+
+// IdentifierKey is the attempt key of a login identifier.
+func IdentifierKey(identifier string) string {
+	return identifierPrefix + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// GuardDeps contains dependencies for the Guard.
+type GuardDeps struct {
+	Email emailv2.Service // nil = no lock/unlock emails
+}
+
+type guard struct {
+	email emailv2.Service
+}
+
+// NewGuard creates a new Guard.
+func NewGuard(d GuardDeps) Guard {
+	return &guard{email: d.Email}
+}
+
+// unlockClaim is the payload of an unlock link token.
+type unlockClaim struct {
+	UserID string `json:"uid"`
+	Email  string `json:"email"`
+}
+
+// policy returns the tenant's lockout threshold and duration (ok=false if disabled).
+func policy(tda store.TenantDataAccess) (int, time.Duration, bool) {
+	settings := tda.Settings()
+	if settings == nil || settings.Security == nil || settings.Security.MaxLoginAttempts <= 0 {
+		return 0, 0, false
+	}
+	d := time.Duration(settings.Security.LockoutDurationMinutes) * time.Minute
+	if d <= 0 {
+		d = DefaultLockoutDuration
+	}
+	return settings.Security.MaxLoginAttempts, d, true
+}
+
+func attemptKeys(userID, identifier string) []string {
+	var keys []string
+	if userID != "" {
+		keys = append(keys, UserKey(userID))
+	}
+	if strings.TrimSpace(identifier) != "" {
+		keys = append(keys, IdentifierKey(identifier))
+	}
+	return keys
+}
+
+// Check implements Guard. Storage errors fail open: login keeps working.
+func (g *guard) Check(ctx context.Context, tda store.TenantDataAccess, userID, identifier string) error {
+	if _, _, ok := policy(tda); !ok {
+		return nil
+	}
+	repo := tda.LoginAttempts()
+	if repo == nil {
+		return nil
+	}
+	now := time.Now()
+	for _, key := range attemptKeys(userID, identifier) {
+		a, err := repo.Get(ctx, key)
+		if err != nil {
+			if !repository.IsNotFound(err) {
+				logger.From(ctx).Warn("lockout check failed", logger.Err(err))
+			}
+			continue
+		}
+		if a.IsLocked(now) {
+			return ErrLocked
+		}
+	}
+	return nil
+}
+
+// RecordFailure implements Guard.
+func (g *guard) RecordFailure(ctx context.Context, tda store.TenantDataAccess, userID, identifier string) error {
+	limit, duration, ok := policy(tda)
+	if !ok {
+		return nil
+	}
+	repo := tda.LoginAttempts()
+	if repo == nil {
+		return nil
+	}
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("lockout.RecordFailure"))
+
+	keys := attemptKeys(userID, identifier)
+	reached := false
+	for _, key := range keys {
+		a, err := repo.RegisterFailure(ctx, key, duration)
+		if err != nil {
+			log.Warn("failed to record login failure", logger.Err(err))
+			continue
+		}
+		if a.Failures >= limit {
+			reached = true
+		}
+	}
+	if !reached {
+		return nil
+	}
+
+	until := time.Now().Add(duration)
+	for _, key := range keys {
+		if err := repo.Lock(ctx, key, until); err != nil {
+			log.Warn("failed to lock", logger.Err(err))
+		}
+	}
+	log.Info("login locked", logger.UserID(userID))
+
+	if userID != "" {
+		if user, err := tda.Users().GetByID(ctx, userID); err == nil && user.Email != "" {
+			g.notifyLocked(ctx, tda, user.ID, user.Email, until)
+		}
+	}
+	return ErrLocked
+}
+
+// RecordSuccess implements Guard.
+func (g *guard) RecordSuccess(ctx context.Context, tda store.TenantDataAccess, userID, identifier string) {
+	if _, _, ok := policy(tda); !ok {
+		return
+	}
+	g.reset(ctx, tda, attemptKeys(userID, identifier))
+}
+
+// RequestUnlock implements Guard.
+func (g *guard) RequestUnlock(ctx context.Context, tda store.TenantDataAccess, email string) error {
+	if _, _, ok := policy(tda); !ok {
+		return nil
+	}
+	repo := tda.LoginAttempts()
+	if repo == nil {
+		return nil
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	// Throttled before the lookup, so the limit doesn't reveal whether the account exists
+	limiter := sms.Limiter{Cache: tda.Cache(), MaxPerHour: unlockMaxPerHour, Cooldown: unlockRetryPeriod, Prefix: unlockRatePrefix}
+	if _, err := limiter.Allow(ctx, email); err != nil {
+		return err
+	}
+
+	user, _, err := tda.Users().GetByEmail(ctx, tda.ID(), email)
+	if err != nil || user.Email == "" {
+		return nil
+	}
+
+	now := time.Now()
+	var until *time.Time
+	for _, key := range attemptKeys(user.ID, user.Email) {
+		if a, err := repo.Get(ctx, key); err == nil && a.IsLocked(now) {
+			if until == nil || a.LockedUntil.After(*until) {
+				until = a.LockedUntil
+			}
+		}
+	}
+	if until == nil {
+		return nil
+	}
+	g.notifyLocked(ctx, tda, user.ID, user.Email, *until)
+	return nil
+}
+
+// UnlockWithToken implements Guard.
+func (g *guard) UnlockWithToken(ctx context.Context, tda store.TenantDataAccess, token string) error {
+	c := tda.Cache()
+	if c == nil || token == "" {
+		return ErrInvalidUnlockToken
+	}
+	key := unlockTokenPrefix + tokens.SHA256Base64URL(token)
+	raw, err := c.Get(ctx, key)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+	_ = c.Delete(ctx, key) // one-time use
+
+	var claim unlockClaim
+	if err := json.Unmarshal([]byte(raw), &claim); err != nil || claim.UserID == "" {
+		return ErrInvalidUnlockToken
+	}
+	g.reset(ctx, tda, attemptKeys(claim.UserID, claim.Email))
+	g.notifyUnlocked(ctx, tda, claim.Email)
+	return nil
+}
+
+// Unlock implements Guard.
+func (g *guard) Unlock(ctx context.Context, tda store.TenantDataAccess, userID string) error {
+	user, err := tda.Users().GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	repo := tda.LoginAttempts()
+	if repo == nil {
+		return nil
+	}
+
+	keys := attemptKeys(user.ID, user.Email)
+	locked := false
+	now := time.Now()
+	for _, key := range keys {
+		if a, err := repo.Get(ctx, key); err == nil && a.IsLocked(now) {
+			locked = true
+		}
+	}
+	g.reset(ctx, tda, keys)
+	if locked {
+		g.notifyUnlocked(ctx, tda, user.Email)
+	}
+	return nil
+}
+
+// ListLocked implements Guard.
+func (g *guard) ListLocked(ctx context.Context, tda store.TenantDataAccess) ([]LockedAccount, error) {
+	repo := tda.LoginAttempts()
+	if repo == nil {
+		return nil, nil
+	}
+	attempts, err := repo.ListLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]LockedAccount, 0, len(attempts))
+	for _, a := range attempts {
+		acc := LockedAccount{Failures: a.Failures, LockedUntil: *a.LockedUntil}
+		switch {
+		case strings.HasPrefix(a.Key, userKeyPrefix):
+			acc.UserID = strings.TrimPrefix(a.Key, userKeyPrefix)
+			if user, err := tda.Users().GetByID(ctx, acc.UserID); err == nil {
+				acc.Email = user.Email
+			}
+		case strings.HasPrefix(a.Key, identifierPrefix):
+			acc.Email = strings.TrimPrefix(a.Key, identifierPrefix)
+		default:
+			continue
+		}
+		out = append(out, acc)
+	}
+	return out, nil
+}
+
+func (g *guard) reset(ctx context.Context, tda store.TenantDataAccess, keys []string) {
+	repo := tda.LoginAttempts()
+	if repo == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := repo.Reset(ctx, key); err != nil {
+			logger.From(ctx).Warn("failed to reset login attempts", logger.Err(err))
+		}
+	}
+}
+
+// notifyLocked issues an unlock link token valid while the lockout lasts and
+// emails it to the user. Delivery is best-effort and runs in the background.
+func (g *guard) notifyLocked(ctx context.Context, tda store.TenantDataAccess, userID, email string, until time.Time) {
+	if g.email == nil {
+		return
+	}
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("lockout.notifyLocked"))
+
+	unlockToken, err := issueUnlockToken(ctx, tda, userID, email, until)
+	if err != nil {
+		// The email still goes out, without the self-service link
+		log.Warn("failed to issue unlock token", logger.Err(err))
+	}
+
+	req := emailv2.SendBlockedRequest{
+		TenantSlugOrID: tda.ID(),
+		Email:          email,
+		Reason:         lockoutReason,
+		Until:          &until,
+		UnlockToken:    unlockToken,
+	}
+	go func(ctx context.Context) {
+		if err := g.email.SendBlockedEmail(ctx, req); err != nil {
+			log.Warn("lockout email failed", logger.Err(err))
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+// issueUnlockToken stores a one-time unlock token in the tenant cache until the
+// lockout ends.
+func issueUnlockToken(ctx context.Context, tda store.TenantDataAccess, userID, email string, until time.Time) (string, error) {
+	c := tda.Cache()
+	if c == nil {
+		return "", errors.New("cache not available")
+	}
+	raw, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+	payload, _ := json.Marshal(unlockClaim{UserID: userID, Email: email})
+	if err := c.Set(ctx, unlockTokenPrefix+tokens.SHA256Base64URL(raw), string(payload), time.Until(until)); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (g *guard) notifyUnlocked(ctx context.Context, tda store.TenantDataAccess, email string) {
+	if g.email == nil || email == "" {
+		return
+	}
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("lockout.notifyUnlocked"))
+
+	req := emailv2.SendUnblockedRequest{TenantSlugOrID: tda.ID(), Email: email}
+	go func(ctx context.Context) {
+		if err := g.email.SendUnblockedEmail(ctx, req); err != nil {
+			log.Warn("unlock email failed", logger.Err(err))
+		}
+	}(context.WithoutCancel(ctx))
+}
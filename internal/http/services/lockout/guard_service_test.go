@@ -0,0 +1,390 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// memAttempts is an in-memory LoginAttemptRepository.
+type memAttempts struct {
+	mu       sync.Mutex
+	attempts map[string]*repository.LoginAttempt
+}
+
+func (m *memAttempts) Get(_ context.Context, key string) (*repository.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.attempts[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (m *memAttempts) RegisterFailure(_ context.Context, key string, _ time.Duration) (*repository.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.attempts[key]
+	if !ok {
+		a = &repository.LoginAttempt{Key: key}
+		m.attempts[key] = a
+	}
+	a.Failures++
+	a.LastFailureAt = time.Now()
+	cp := *a
+	return &cp, nil
+}
+
+func (m *memAttempts) Lock(_ context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.attempts[key]
+	if !ok {
+		a = &repository.LoginAttempt{Key: key}
+		m.attempts[key] = a
+	}
+	a.LockedUntil = &until
+	return nil
+}
+
+func (m *memAttempts) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+func (m *memAttempts) ListLocked(_ context.Context) ([]repository.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []repository.LoginAttempt
+	for _, a := range m.attempts {
+		if a.IsLocked(time.Now()) {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+type users struct {
+	repository.UserRepository
+	byID map[string]*repository.User
+}
+
+func (u users) GetByID(_ context.Context, id string) (*repository.User, error) {
+	if user, ok := u.byID[id]; ok {
+		return user, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (u users) GetByEmail(_ context.Context, _, email string) (*repository.User, *repository.Identity, error) {
+	for _, user := range u.byID {
+		if user.Email == email {
+			return user, nil, nil
+		}
+	}
+	return nil, nil, repository.ErrNotFound
+}
+
+type lockoutTDA struct {
+	store.TenantDataAccess
+	settings *repository.TenantSettings
+	attempts *memAttempts
+	users    users
+	cache    cache.Client
+}
+
+func (f *lockoutTDA) ID() string                                       { return "t1" }
+func (f *lockoutTDA) Settings() *repository.TenantSettings             { return f.settings }
+func (f *lockoutTDA) LoginAttempts() repository.LoginAttemptRepository { return f.attempts }
+func (f *lockoutTDA) Users() repository.UserRepository                 { return f.users }
+func (f *lockoutTDA) Cache() cache.Client                              { return f.cache }
+
+func newLockoutTDA(maxAttempts int) *lockoutTDA {
+	return &lockoutTDA{
+		settings: &repository.TenantSettings{Security: &repository.SecurityPolicy{MaxLoginAttempts: maxAttempts}},
+		attempts: &memAttempts{attempts: map[string]*repository.LoginAttempt{}},
+		users:    users{byID: map[string]*repository.User{"u1": {ID: "u1", Email: "ana@example.com"}}},
+		cache:    cache.NewMemory("test"),
+	}
+}
+
+// mailbox records the lockout emails sent in the background.
+type mailbox struct {
+	emailv2.Service
+	blocked   chan emailv2.SendBlockedRequest
+	unblocked chan emailv2.SendUnblockedRequest
+}
+
+func newMailbox() *mailbox {
+	return &mailbox{
+		blocked:   make(chan emailv2.SendBlockedRequest, 4),
+		unblocked: make(chan emailv2.SendUnblockedRequest, 4),
+	}
+}
+
+func (m *mailbox) SendBlockedEmail(_ context.Context, req emailv2.SendBlockedRequest) error {
+	m.blocked <- req
+	return nil
+}
+
+func (m *mailbox) SendUnblockedEmail(_ context.Context, req emailv2.SendUnblockedRequest) error {
+	m.unblocked <- req
+	return nil
+}
+
+func (m *mailbox) nextBlocked(t *testing.T) emailv2.SendBlockedRequest {
+	t.Helper()
+	select {
+	case req := <-m.blocked:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("no lockout email sent")
+		return emailv2.SendBlockedRequest{}
+	}
+}
+
+func TestRecordFailure_LocksAtThreshold(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(3)
+	g := NewGuard(GuardDeps{})
+
+	for i := 1; i < 3; i++ {
+		if err := g.RecordFailure(ctx, tda, "u1", "ana@example.com"); err != nil {
+			t.Fatalf("failure %d: err = %v, want nil below the threshold", i, err)
+		}
+		if err := g.Check(ctx, tda, "u1", "ana@example.com"); err != nil {
+			t.Fatalf("failure %d: Check = %v, want unlocked", i, err)
+		}
+	}
+	if err := g.RecordFailure(ctx, tda, "u1", "ana@example.com"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("failure 3: err = %v, want ErrLocked", err)
+	}
+
+	// Both keys are locked; the identifier key ignores case and spaces
+	if err := g.Check(ctx, tda, "u1", ""); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Check(user) = %v, want ErrLocked", err)
+	}
+	if err := g.Check(ctx, tda, "", "  ANA@example.com "); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Check(identifier) = %v, want ErrLocked", err)
+	}
+
+	a, err := tda.attempts.Get(ctx, UserKey("u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(*a.LockedUntil); d <= 14*time.Minute || d > DefaultLockoutDuration {
+		t.Fatalf("lockout lasts %v, want the default %v", d, DefaultLockoutDuration)
+	}
+}
+
+// Failures against an unknown email are throttled like a real account.
+func TestRecordFailure_UnknownIdentifier(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(2)
+	g := NewGuard(GuardDeps{})
+
+	_ = g.RecordFailure(ctx, tda, "", "ghost@example.com")
+	if err := g.RecordFailure(ctx, tda, "", "ghost@example.com"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("err = %v, want ErrLocked", err)
+	}
+	if err := g.Check(ctx, tda, "", "ghost@example.com"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Check = %v, want ErrLocked", err)
+	}
+	if err := g.Check(ctx, tda, "u1", "ana@example.com"); err != nil {
+		t.Fatalf("other account: Check = %v, want unlocked", err)
+	}
+}
+
+func TestRecordSuccess_ClearsFailures(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(3)
+	g := NewGuard(GuardDeps{})
+
+	_ = g.RecordFailure(ctx, tda, "u1", "ana@example.com")
+	_ = g.RecordFailure(ctx, tda, "u1", "ana@example.com")
+	g.RecordSuccess(ctx, tda, "u1", "ana@example.com")
+
+	// The count starts over: two more failures stay below the threshold
+	for i := 0; i < 2; i++ {
+		if err := g.RecordFailure(ctx, tda, "u1", "ana@example.com"); err != nil {
+			t.Fatalf("err = %v, want nil after a successful login", err)
+		}
+	}
+}
+
+func TestGuard_DisabledPolicy(t *testing.T) {
+	ctx := context.Background()
+	g := NewGuard(GuardDeps{})
+
+	for name, settings := range map[string]*repository.TenantSettings{
+		"no settings":      nil,
+		"no security":      {},
+		"zero max attempt": {Security: &repository.SecurityPolicy{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tda := newLockoutTDA(0)
+			tda.settings = settings
+			for i := 0; i < 10; i++ {
+				if err := g.RecordFailure(ctx, tda, "u1", "ana@example.com"); err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+			}
+			if err := g.Check(ctx, tda, "u1", "ana@example.com"); err != nil {
+				t.Fatalf("Check = %v, want nil", err)
+			}
+			if len(tda.attempts.attempts) != 0 {
+				t.Fatalf("attempts recorded with lockout disabled: %v", tda.attempts.attempts)
+			}
+		})
+	}
+}
+
+func TestUnlock_Admin(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(1)
+	mail := newMailbox()
+	g := NewGuard(GuardDeps{Email: mail})
+
+	if err := g.RecordFailure(ctx, tda, "u1", "ana@example.com"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("err = %v, want ErrLocked", err)
+	}
+	mail.nextBlocked(t)
+
+	locked, err := g.ListLocked(ctx, tda)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) != 2 {
+		t.Fatalf("ListLocked = %+v, want the user and the identifier", locked)
+	}
+	for _, acc := range locked {
+		if acc.Email != "ana@example.com" {
+			t.Fatalf("locked account %+v, want email ana@example.com", acc)
+		}
+	}
+
+	if err := g.Unlock(ctx, tda, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check(ctx, tda, "u1", "ana@example.com"); err != nil {
+		t.Fatalf("Check after Unlock = %v", err)
+	}
+	select {
+	case req := <-mail.unblocked:
+		if req.Email != "ana@example.com" {
+			t.Fatalf("unlock email to %q", req.Email)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no unlock email sent")
+	}
+
+	if err := g.Unlock(ctx, tda, "missing"); !repository.IsNotFound(err) {
+		t.Fatalf("unknown user: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUnlockWithToken_SingleUse(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(1)
+	mail := newMailbox()
+	g := NewGuard(GuardDeps{Email: mail})
+
+	_ = g.RecordFailure(ctx, tda, "u1", "ana@example.com")
+	req := mail.nextBlocked(t)
+	if req.UnlockToken == "" || req.Until == nil {
+		t.Fatalf("lockout email %+v, want an unlock token and until", req)
+	}
+
+	if err := g.UnlockWithToken(ctx, tda, req.UnlockToken); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check(ctx, tda, "u1", "ana@example.com"); err != nil {
+		t.Fatalf("Check after unlock = %v", err)
+	}
+
+	// The link can't be replayed
+	if err := g.UnlockWithToken(ctx, tda, req.UnlockToken); !errors.Is(err, ErrInvalidUnlockToken) {
+		t.Fatalf("replay: err = %v, want ErrInvalidUnlockToken", err)
+	}
+	for _, token := range []string{"", "forged"} {
+		if err := g.UnlockWithToken(ctx, tda, token); !errors.Is(err, ErrInvalidUnlockToken) {
+			t.Fatalf("token %q: err = %v, want ErrInvalidUnlockToken", token, err)
+		}
+	}
+}
+
+func TestRequestUnlock(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(1)
+	mail := newMailbox()
+	g := NewGuard(GuardDeps{Email: mail})
+
+	_ = g.RecordFailure(ctx, tda, "u1", "ana@example.com")
+	first := mail.nextBlocked(t)
+
+	if err := g.RequestUnlock(ctx, tda, " Ana@Example.com "); err != nil {
+		t.Fatal(err)
+	}
+	again := mail.nextBlocked(t)
+	if again.UnlockToken == "" || again.UnlockToken == first.UnlockToken {
+		t.Fatal("RequestUnlock should email a new unlock token")
+	}
+
+	// Within the cooldown the request is throttled
+	if err := g.RequestUnlock(ctx, tda, "ana@example.com"); !errors.Is(err, sms.ErrRateLimited) {
+		t.Fatalf("err = %v, want sms.ErrRateLimited", err)
+	}
+
+	// Unknown accounts get the same answer and no email
+	if err := g.RequestUnlock(ctx, tda, "ghost@example.com"); err != nil {
+		t.Fatalf("unknown email: err = %v, want nil", err)
+	}
+	select {
+	case req := <-mail.blocked:
+		t.Fatalf("unexpected email to %q", req.Email)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestLoginHelpers_NilGuard(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(1)
+	failure := errors.New("invalid credentials")
+
+	if err := CheckLogin(ctx, nil, tda, "u1", "ana@example.com"); err != nil {
+		t.Fatalf("CheckLogin = %v", err)
+	}
+	if err := LoginFailed(ctx, nil, tda, "u1", "ana@example.com", failure); err != failure {
+		t.Fatalf("LoginFailed = %v, want the failure", err)
+	}
+	LoginSucceeded(ctx, nil, tda, "u1", "ana@example.com")
+}
+
+func TestLoginHelpers(t *testing.T) {
+	ctx := context.Background()
+	tda := newLockoutTDA(2)
+	g := NewGuard(GuardDeps{})
+	failure := errors.New("invalid credentials")
+
+	if err := LoginFailed(ctx, g, tda, "u1", "ana@example.com", failure); err != failure {
+		t.Fatalf("first failure = %v, want the failure", err)
+	}
+	if err := LoginFailed(ctx, g, tda, "u1", "ana@example.com", failure); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second failure = %v, want ErrLocked", err)
+	}
+	if err := CheckLogin(ctx, g, tda, "u1", "ana@example.com"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("CheckLogin = %v, want ErrLocked", err)
+	}
+}
@@ -0,0 +1,33 @@
+package lockout
+
+import (
+	"context"
+
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// Helpers for the password login services. A nil Guard means no account lockout.
+
+// CheckLogin returns ErrLocked if the user or the identifier is locked.
+func CheckLogin(ctx context.Context, g Guard, tda store.TenantDataAccess, userID, identifier string) error {
+	if g != nil && g.Check(ctx, tda, userID, identifier) != nil {
+		return ErrLocked
+	}
+	return nil
+}
+
+// LoginFailed counts a failed login and returns the error to report:
+// ErrLocked when this attempt started the lockout, failure otherwise.
+func LoginFailed(ctx context.Context, g Guard, tda store.TenantDataAccess, userID, identifier string, failure error) error {
+	if g != nil && g.RecordFailure(ctx, tda, userID, identifier) != nil {
+		return ErrLocked
+	}
+	return failure
+}
+
+// LoginSucceeded clears the failed attempts after a successful login.
+func LoginSucceeded(ctx context.Context, g Guard, tda store.TenantDataAccess, userID, identifier string) {
+	if g != nil {
+		g.RecordSuccess(ctx, tda, userID, identifier)
+	}
+}
@@ -0,0 +1,26 @@
+// Package lockout aplica el bloqueo de cuentas por intentos fallidos de login
+// (SecurityPolicy.MaxLoginAttempts y LockoutDurationMinutes del tenant).
+package lockout
+
+import (
+	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+)
+
+// Deps contiene las dependencias para crear los services lockout.
+type Deps struct {
+	Email emailv2.Service
+}
+
+// Services agrupa todos los services del dominio lockout.
+type Services struct {
+	Guard Guard
+}
+
+// NewServices crea el agregador de services lockout.
+func NewServices(d Deps) Services {
+	return Services{
+		Guard: NewGuard(GuardDeps{
+			Email: d.Email,
+		}),
+	}
+}
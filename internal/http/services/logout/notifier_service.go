@@ -0,0 +1,214 @@
+package logout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/netguard"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
+)
+
+// Notifier tells relying parties that a user session has ended
+// (OIDC Back-Channel Logout 1.0 and Front-Channel Logout 1.0).
+type Notifier interface {
+	// Backchannel POSTs a logout_token to every client of the tenant that registered
+	// a backchannel_logout_uri. An empty sid means all sessions of the user.
+	// Delivery runs in the background; failures are retried and logged.
+	Backchannel(ctx context.Context, tenantSlug, userID, sid string)
+
+	// FrontchannelURIs returns the frontchannel_logout_uri of every client of the
+	// tenant, with iss and sid appended when the client requires them.
+	FrontchannelURIs(ctx context.Context, tenantSlug, sid string) []string
+}
+
+// NotifierDeps contains dependencies for the Notifier.
+type NotifierDeps struct {
+	ControlPlane controlplane.Service
+	Issuer       *jwtx.Issuer
+	HTTPClient   *http.Client  // default: 5s timeout, no redirects
+	MaxAttempts  int           // default 3
+	RetryBackoff time.Duration // first retry delay, doubled on each attempt (default 1s)
+}
+
+type notifier struct {
+	cp          controlplane.Service
+	issuer      *jwtx.Issuer
+	http        *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewNotifier creates a new Notifier.
+func NewNotifier(d NotifierDeps) Notifier {
+	hc := d.HTTPClient
+	if hc == nil {
+		// backchannel_logout_uri is client-supplied: only public addresses (SSRF)
+		hc = netguard.NewClient(5 * time.Second)
+		// Back-Channel Logout §2.5: the OP must not follow redirects
+		hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	}
+	attempts := d.MaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	backoff := d.RetryBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	return &notifier{
+		cp:          d.ControlPlane,
+		issuer:      d.Issuer,
+		http:        hc,
+		maxAttempts: attempts,
+		backoff:     backoff,
+	}
+}
+
+// Backchannel implements Notifier.
+func (n *notifier) Backchannel(ctx context.Context, tenantSlug, userID, sid string) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("logout.Backchannel"))
+
+	if n.cp == nil || n.issuer == nil || tenantSlug == "" || (userID == "" && sid == "") {
+		return
+	}
+	tenant, clients, err := n.tenantClients(ctx, tenantSlug)
+	if err != nil {
+		log.Debug("backchannel logout skipped: tenant not resolved", logger.TenantSlug(tenantSlug), logger.Err(err))
+		return
+	}
+	iss := jwtx.ResolveIssuer(n.issuer.Iss, string(tenant.Settings.IssuerMode), tenant.Slug, tenant.Settings.IssuerOverride)
+
+	// The request context ends with the response; delivery must outlive it
+	bg := context.WithoutCancel(ctx)
+	for _, c := range clients {
+		if c.BackchannelLogoutURI == "" {
+			continue
+		}
+		if sid == "" && c.BackchannelLogoutSessionRequired {
+			// §2.4: sid is mandatory for these clients and a user-wide logout has none
+			log.Debug("backchannel logout skipped: client requires sid", logger.ClientID(c.ClientID))
+			continue
+		}
+		sub, err := pairwise.SubjectFor(&c, userID)
+		if err != nil {
+			log.Error("failed to derive pairwise subject", logger.ClientID(c.ClientID), logger.Err(err))
+			continue
+		}
+		token, err := n.issuer.IssueLogoutToken(tenant.Slug, iss, c.ClientID, sub, sid, c.IDTokenSignedResponseAlg)
+		if err != nil {
+			log.Error("failed to issue logout_token", logger.ClientID(c.ClientID), logger.Err(err))
+			continue
+		}
+		go n.deliver(bg, tenant.Slug, c.ClientID, c.BackchannelLogoutURI, token)
+	}
+}
+
+// deliver POSTs the logout_token, retrying with exponential backoff.
+func (n *notifier) deliver(ctx context.Context, tenantSlug, clientID, uri, token string) {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Op("logout.deliver"),
+		logger.TenantSlug(tenantSlug),
+		logger.ClientID(clientID),
+	)
+
+	body := url.Values{"logout_token": {token}}.Encode()
+	delay := n.backoff
+	var lastErr error
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if lastErr = n.post(ctx, uri, body); lastErr == nil {
+			log.Debug("backchannel logout delivered", logger.Int("attempt", attempt))
+			return
+		}
+		log.Debug("backchannel logout attempt failed", logger.Int("attempt", attempt), logger.Err(lastErr))
+	}
+	log.Warn("backchannel logout delivery failed",
+		logger.String("uri", uri),
+		logger.Int("attempts", n.maxAttempts),
+		logger.Err(lastErr),
+	)
+}
+
+func (n *notifier) post(ctx context.Context, uri, body string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cache-Control", "no-cache, no-store")
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// §2.8: 200 (or 204) means the RP processed it; anything else is a failure
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// FrontchannelURIs implements Notifier.
+func (n *notifier) FrontchannelURIs(ctx context.Context, tenantSlug, sid string) []string {
+	if n.cp == nil || tenantSlug == "" {
+		return nil
+	}
+	tenant, clients, err := n.tenantClients(ctx, tenantSlug)
+	if err != nil {
+		return nil
+	}
+	iss := ""
+	if n.issuer != nil {
+		iss = jwtx.ResolveIssuer(n.issuer.Iss, string(tenant.Settings.IssuerMode), tenant.Slug, tenant.Settings.IssuerOverride)
+	}
+
+	var out []string
+	for _, c := range clients {
+		if c.FrontchannelLogoutURI == "" {
+			continue
+		}
+		u, err := url.Parse(c.FrontchannelLogoutURI)
+		if err != nil {
+			continue
+		}
+		if c.FrontchannelLogoutSessionRequired && sid != "" {
+			q := u.Query()
+			q.Set("iss", iss)
+			q.Set("sid", sid)
+			u.RawQuery = q.Encode()
+		}
+		out = append(out, u.String())
+	}
+	return out
+}
+
+// tenantClients resolves the tenant (by slug or ID, sessions may carry either) and its clients.
+func (n *notifier) tenantClients(ctx context.Context, tenantSlug string) (*repository.Tenant, []repository.Client, error) {
+	tenant, err := n.cp.GetTenant(ctx, tenantSlug)
+	if err != nil {
+		if tenant, err = n.cp.GetTenantByID(ctx, tenantSlug); err != nil {
+			return nil, nil, err
+		}
+	}
+	if tenant == nil {
+		return nil, nil, repository.ErrNotFound
+	}
+	clients, err := n.cp.ListClients(ctx, tenant.Slug)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tenant, clients, nil
+}
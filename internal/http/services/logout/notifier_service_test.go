@@ -0,0 +1,190 @@
+package logout
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// staticKeys is a KeyRepository with a single ES256 key.
+type staticKeys struct {
+	repository.KeyRepository
+	key *repository.SigningKey
+}
+
+func (s *staticKeys) GetActiveByAlgorithm(context.Context, string, string) (*repository.SigningKey, error) {
+	return s.key, nil
+}
+
+type fakeControlPlane struct {
+	controlplane.Service
+	tenant  *repository.Tenant
+	clients []repository.Client
+}
+
+func (f *fakeControlPlane) GetTenant(_ context.Context, slug string) (*repository.Tenant, error) {
+	if slug != f.tenant.Slug {
+		return nil, controlplane.ErrTenantNotFound
+	}
+	return f.tenant, nil
+}
+
+func (f *fakeControlPlane) GetTenantByID(_ context.Context, id string) (*repository.Tenant, error) {
+	if id != f.tenant.ID {
+		return nil, controlplane.ErrTenantNotFound
+	}
+	return f.tenant, nil
+}
+
+func (f *fakeControlPlane) ListClients(context.Context, string) ([]repository.Client, error) {
+	return f.clients, nil
+}
+
+// received collects the logout_tokens posted to the test server.
+type received struct {
+	srv    *httptest.Server
+	tokens chan string
+	calls  atomic.Int32
+	fail   int32 // first n calls answer 500
+}
+
+func newReceiver(t *testing.T, fail int32) *received {
+	rc := &received{tokens: make(chan string, 10), fail: fail}
+	rc.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rc.calls.Add(1) <= rc.fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = r.ParseForm()
+		rc.tokens <- r.PostForm.Get("logout_token")
+	}))
+	t.Cleanup(rc.srv.Close)
+	return rc
+}
+
+func (rc *received) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case tok := <-rc.tokens:
+		return tok
+	case <-time.After(2 * time.Second):
+		t.Fatal("no logout_token delivered")
+		return ""
+	}
+}
+
+func newTestNotifier(t *testing.T, clients []repository.Client, hc *http.Client) (Notifier, *ecdsa.PrivateKey) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := &staticKeys{key: &repository.SigningKey{ID: "k1", Algorithm: "ES256", PrivateKey: priv, PublicKey: &priv.PublicKey}}
+	cp := &fakeControlPlane{tenant: &repository.Tenant{ID: "tenant-1", Slug: "acme"}, clients: clients}
+	return NewNotifier(NotifierDeps{
+		ControlPlane: cp,
+		Issuer:       jwtx.NewIssuer("https://auth.example.com", jwtx.NewPersistentKeystore(keys)),
+		HTTPClient:   hc,
+		RetryBackoff: time.Millisecond,
+	}), priv
+}
+
+func TestBackchannelLogoutToken(t *testing.T) {
+	rc := newReceiver(t, 0)
+	clients := []repository.Client{
+		{ClientID: "client-a", BackchannelLogoutURI: rc.srv.URL, IDTokenSignedResponseAlg: "ES256"},
+		{ClientID: "client-b"},
+	}
+	n, priv := newTestNotifier(t, clients, rc.srv.Client())
+
+	n.Backchannel(context.Background(), "acme", "user-1", "sid-1")
+
+	claims := jwtv5.MapClaims{}
+	tk, err := jwtv5.ParseWithClaims(rc.next(t), claims, func(*jwtv5.Token) (any, error) { return &priv.PublicKey, nil })
+	if err != nil {
+		t.Fatalf("logout_token: %v", err)
+	}
+	if tk.Header["typ"] != "logout+jwt" {
+		t.Errorf("typ = %v", tk.Header["typ"])
+	}
+	if claims["aud"] != "client-a" || claims["sub"] != "user-1" || claims["sid"] != "sid-1" || claims["iss"] != "https://auth.example.com" {
+		t.Errorf("claims = %v", claims)
+	}
+	events, _ := claims["events"].(map[string]any)
+	if _, ok := events[jwtx.BackchannelLogoutEvent]; !ok {
+		t.Errorf("events = %v", claims["events"])
+	}
+	if _, ok := claims["nonce"]; ok {
+		t.Error("logout_token carries a nonce")
+	}
+	if claims["jti"] == nil {
+		t.Error("logout_token without jti")
+	}
+}
+
+func TestBackchannelSkipsClientsRequiringSid(t *testing.T) {
+	rc := newReceiver(t, 0)
+	clients := []repository.Client{
+		{ClientID: "client-a", BackchannelLogoutURI: rc.srv.URL + "/a", BackchannelLogoutSessionRequired: true, IDTokenSignedResponseAlg: "ES256"},
+		{ClientID: "client-b", BackchannelLogoutURI: rc.srv.URL + "/b", IDTokenSignedResponseAlg: "ES256"},
+	}
+	n, _ := newTestNotifier(t, clients, rc.srv.Client())
+
+	// A user-wide logout (no sid) only reaches client-b
+	n.Backchannel(context.Background(), "tenant-1", "user-1", "")
+	rc.next(t)
+	select {
+	case <-rc.tokens:
+		t.Fatal("client requiring sid notified of a user-wide logout")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBackchannelRetries(t *testing.T) {
+	rc := newReceiver(t, 2)
+	clients := []repository.Client{{ClientID: "client-a", BackchannelLogoutURI: rc.srv.URL, IDTokenSignedResponseAlg: "ES256"}}
+	n, _ := newTestNotifier(t, clients, rc.srv.Client())
+
+	n.Backchannel(context.Background(), "acme", "user-1", "sid-1")
+	rc.next(t)
+	if calls := rc.calls.Load(); calls != 3 {
+		t.Errorf("attempts = %d, want 3", calls)
+	}
+}
+
+func TestFrontchannelURIs(t *testing.T) {
+	clients := []repository.Client{
+		{ClientID: "client-a", FrontchannelLogoutURI: "https://a.example.com/logout?x=1", FrontchannelLogoutSessionRequired: true},
+		{ClientID: "client-b", FrontchannelLogoutURI: "https://b.example.com/logout"},
+		{ClientID: "client-c"},
+	}
+	n, _ := newTestNotifier(t, clients, nil)
+
+	uris := n.FrontchannelURIs(context.Background(), "acme", "sid-1")
+	if len(uris) != 2 {
+		t.Fatalf("uris = %v", uris)
+	}
+	a, _ := url.Parse(uris[0])
+	if q := a.Query(); q.Get("iss") != "https://auth.example.com" || q.Get("sid") != "sid-1" || q.Get("x") != "1" {
+		t.Errorf("client-a uri = %s", uris[0])
+	}
+	if uris[1] != "https://b.example.com/logout" {
+		t.Errorf("client-b uri = %s", uris[1])
+	}
+
+	if uris := n.FrontchannelURIs(context.Background(), "unknown", "sid-1"); uris != nil {
+		t.Errorf("unknown tenant: %v", uris)
+	}
+}
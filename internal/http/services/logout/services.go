@@ -0,0 +1,29 @@
+// Package logout contiene el fan-out de logout hacia los relying parties
+// (OIDC Back-Channel y Front-Channel Logout).
+package logout
+
+import (
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+)
+
+// Deps contiene las dependencias para crear los services logout.
+type Deps struct {
+	ControlPlane controlplane.Service
+	Issuer       *jwtx.Issuer
+}
+
+// Services agrupa todos los services del dominio logout.
+type Services struct {
+	Notifier Notifier
+}
+
+// NewServices crea el agregador de services logout.
+func NewServices(d Deps) Services {
+	return Services{
+		Notifier: NewNotifier(NotifierDeps{
+			ControlPlane: d.ControlPlane,
+			Issuer:       d.Issuer,
+		}),
+	}
+}
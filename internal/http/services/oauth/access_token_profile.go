@@ -0,0 +1,69 @@
+package oauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// tenantAccessTokenProfile returns the tenant's default access token profile.
+func (s *tokenService) tenantAccessTokenProfile(ctx context.Context, tenantSlug string) string {
+	if s.cp == nil {
+		return repository.AccessTokenProfileDefault
+	}
+	ten, err := s.cp.GetTenant(ctx, tenantSlug)
+	if err != nil || ten == nil {
+		return repository.AccessTokenProfileDefault
+	}
+	return ten.Settings.AccessTokenProfile
+}
+
+// issueAccessToken issues the access token for target in its profile. The
+// RFC 9068 profile always carries client_id, drops the non-standard scp claim
+// and, for user tokens, adds the user's roles and entitlements (RFC 9068 §2.2.3.1,
+// RFC 7643 §4.1.2). userID is empty for client tokens. Granted authorization
+// details are carried in every profile (RFC 9396 §9.1).
+func (s *tokenService) issueAccessToken(ctx context.Context, tenantSlug, iss, sub, userID string, client *repository.Client, target *resourceTarget, std, custom map[string]any) (string, time.Time, error) {
+	if len(target.Details) > 0 {
+		std[authorizationDetailsClaim] = target.Details
+	}
+	if target.Profile != repository.AccessTokenProfileRFC9068 {
+		return s.issuer.IssueAccessForAudience(tenantSlug, iss, sub, target.Audience, std, custom, target.TTL, target.Alg)
+	}
+
+	std["client_id"] = client.ClientID
+	delete(std, "scp")
+	if scope, _ := std["scope"].(string); scope == "" {
+		delete(std, "scope")
+	}
+	if userID != "" {
+		roles, entitlements := s.userAuthorization(ctx, tenantSlug, userID)
+		if len(roles) > 0 {
+			std["roles"] = roles
+		}
+		if len(entitlements) > 0 {
+			std["entitlements"] = entitlements
+		}
+	}
+	return s.issuer.IssueAccessJWT(tenantSlug, iss, sub, target.Audience, std, custom, target.TTL, target.Alg)
+}
+
+// userAuthorization loads the user's RBAC roles and permissions (empty when the
+// tenant has no RBAC store).
+func (s *tokenService) userAuthorization(ctx context.Context, tenantSlug, userID string) ([]string, []string) {
+	if s.dal == nil {
+		return nil, nil
+	}
+	tda, err := s.dal.ForTenant(ctx, tenantSlug)
+	if err != nil {
+		return nil, nil
+	}
+	rbac := tda.RBAC()
+	if rbac == nil {
+		return nil, nil
+	}
+	roles, _ := rbac.GetUserRoles(ctx, userID)
+	perms, _ := rbac.GetUserPermissions(ctx, userID)
+	return roles, perms
+}
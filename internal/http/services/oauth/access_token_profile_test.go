@@ -0,0 +1,123 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// staticKeys is a KeyRepository with a single ES256 signing key.
+type staticKeys struct {
+	repository.KeyRepository
+	key *repository.SigningKey
+}
+
+func (s *staticKeys) GetActiveByAlgorithm(context.Context, string, string) (*repository.SigningKey, error) {
+	return s.key, nil
+}
+
+// signingIssuer returns an issuer that signs with a fresh ES256 key.
+func signingIssuer(t *testing.T) (*jwtx.Issuer, *ecdsa.PrivateKey) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := &staticKeys{key: &repository.SigningKey{ID: "k1", Algorithm: "ES256", PrivateKey: priv, PublicKey: &priv.PublicKey}}
+	return jwtx.NewIssuer(testIssuer, jwtx.NewPersistentKeystore(keys)), priv
+}
+
+func parseSigned(t *testing.T, raw string, pub *ecdsa.PublicKey) (map[string]any, jwtv5.MapClaims) {
+	t.Helper()
+	claims := jwtv5.MapClaims{}
+	tk, err := jwtv5.ParseWithClaims(raw, claims, func(*jwtv5.Token) (any, error) { return pub, nil })
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return tk.Header, claims
+}
+
+func (f *fakeControlPlane) ListTenants(context.Context) ([]repository.Tenant, error) {
+	return []repository.Tenant{*f.tenant}, nil
+}
+
+func TestIssueAccessTokenProfiles(t *testing.T) {
+	issuer, priv := signingIssuer(t)
+	s := &tokenService{issuer: issuer}
+	client := &repository.Client{ClientID: "client-a"}
+
+	std := func() map[string]any { return map[string]any{"scope": "orders:read", "scp": []string{"orders:read"}} }
+	target := &resourceTarget{Audience: []string{"https://api.example.com"}, Alg: "ES256", Profile: repository.AccessTokenProfileRFC9068}
+
+	raw, _, err := s.issueAccessToken(context.Background(), "acme", testIssuer, "user-1", "", client, target, std(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, claims := parseSigned(t, raw, &priv.PublicKey)
+	if header["typ"] != jwtx.AccessTokenJWTType {
+		t.Errorf("typ = %v, want at+jwt", header["typ"])
+	}
+	if claims["client_id"] != "client-a" || claims["scope"] != "orders:read" || claims["jti"] == nil {
+		t.Errorf("claims = %v", claims)
+	}
+	if _, ok := claims["scp"]; ok {
+		t.Error("RFC 9068 token carries scp")
+	}
+
+	target.Profile = repository.AccessTokenProfileDefault
+	raw, _, err = s.issueAccessToken(context.Background(), "acme", testIssuer, "user-1", "", client, target, std(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, claims = parseSigned(t, raw, &priv.PublicKey)
+	if header["typ"] != "JWT" || claims["scp"] == nil {
+		t.Errorf("default profile: typ=%v claims=%v", header["typ"], claims)
+	}
+}
+
+func TestIssueIntrospectionResponse(t *testing.T) {
+	issuer, priv := signingIssuer(t)
+	raw, err := issuer.IssueIntrospectionResponse("acme", testIssuer, "client-a", map[string]any{"active": true, "sub": "user-1"}, "ES256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, claims := parseSigned(t, raw, &priv.PublicKey)
+	if header["typ"] != jwtx.IntrospectionResponseType || claims["aud"] != "client-a" {
+		t.Errorf("header=%v claims=%v", header, claims)
+	}
+	result, _ := claims["token_introspection"].(map[string]any)
+	if result["active"] != true || result["sub"] != "user-1" {
+		t.Errorf("token_introspection = %v", claims["token_introspection"])
+	}
+}
+
+func TestIntrospectAuthenticateClient(t *testing.T) {
+	ctx := context.Background()
+	cp := newFakeControlPlane(nil)
+	cp.clients["confidential"] = &repository.Client{ClientID: "confidential", Type: repository.ClientTypeConfidential}
+	cp.secrets["confidential"] = "s3cret"
+	cp.clients["spa"] = &repository.Client{ClientID: "spa", Type: repository.ClientTypePublic}
+	s := NewIntrospectService(IntrospectDeps{ControlPlane: cp})
+
+	if id, err := s.AuthenticateClient(ctx, "acme", "confidential", "s3cret", ClientAssertion{}, nil); err != nil || id != "confidential" {
+		t.Fatalf("valid secret: %q, %v", id, err)
+	}
+	tests := []struct{ name, clientID, secret string }{
+		{"wrong secret", "confidential", "nope"},
+		{"public client", "spa", ""},
+		{"unknown client", "ghost", "s3cret"},
+		{"no client_id", "", ""},
+	}
+	for _, tt := range tests {
+		if _, err := s.AuthenticateClient(ctx, "acme", tt.clientID, tt.secret, ClientAssertion{}, nil); !errors.Is(err, ErrIntrospectInvalidClient) {
+			t.Errorf("%s: got %v, want ErrIntrospectInvalidClient", tt.name, err)
+		}
+	}
+}
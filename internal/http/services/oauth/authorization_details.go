@@ -0,0 +1,141 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dropDatabas3/hellojohn/internal/claims"
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// ErrInvalidAuthorizationDetails is returned for malformed authorization_details,
+// unknown types or details that don't match their type's schema (RFC 9396 §5).
+var ErrInvalidAuthorizationDetails = errors.New("invalid authorization_details")
+
+// authorizationDetailsClaim carries the granted details in access tokens and
+// introspection responses (RFC 9396 §7, §9).
+const authorizationDetailsClaim = "authorization_details"
+
+// parseAuthorizationDetails decodes the authorization_details parameter: a JSON
+// array of objects, each with a string "type" (RFC 9396 §2). Empty means none.
+func parseAuthorizationDetails(raw string) ([]map[string]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var details []map[string]any
+	if err := json.Unmarshal([]byte(raw), &details); err != nil {
+		return nil, fmt.Errorf("%w: must be a JSON array of objects", ErrInvalidAuthorizationDetails)
+	}
+	for i, d := range details {
+		if d == nil {
+			return nil, fmt.Errorf("%w: entry %d is not an object", ErrInvalidAuthorizationDetails, i)
+		}
+		if t, _ := d["type"].(string); t == "" {
+			return nil, fmt.Errorf("%w: entry %d has no type", ErrInvalidAuthorizationDetails, i)
+		}
+	}
+	return details, nil
+}
+
+// checkAuthorizationDetails validates the common fields (RFC 9396 §2.2) and each
+// detail against the schema of its type registered in the tenant settings.
+func checkAuthorizationDetails(settings *repository.TenantSettings, details []map[string]any) error {
+	for _, d := range details {
+		name, _ := d["type"].(string)
+		typ := settings.FindAuthorizationDetailsType(name)
+		if typ == nil {
+			return fmt.Errorf("%w: unknown type %q", ErrInvalidAuthorizationDetails, name)
+		}
+		for _, field := range []string{"locations", "actions", "datatypes", "privileges"} {
+			if v, ok := d[field]; ok && !isStringArray(v) {
+				return fmt.Errorf("%w: %s must be an array of strings", ErrInvalidAuthorizationDetails, field)
+			}
+		}
+		if v, ok := d["identifier"]; ok {
+			if _, isString := v.(string); !isString {
+				return fmt.Errorf("%w: identifier must be a string", ErrInvalidAuthorizationDetails)
+			}
+		}
+		if len(typ.Schema) > 0 {
+			if err := claims.ValidateSchema(typ.Schema, map[string]any(d)); err != nil {
+				return fmt.Errorf("%w: type %q: %v", ErrInvalidAuthorizationDetails, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveAuthorizationDetails parses raw and checks it against the tenant's
+// registered authorization details types.
+func resolveAuthorizationDetails(ctx context.Context, cp controlplane.Service, tenantSlug, raw string) ([]map[string]any, error) {
+	details, err := parseAuthorizationDetails(raw)
+	if err != nil || len(details) == 0 {
+		return details, err
+	}
+	var settings *repository.TenantSettings
+	if cp != nil {
+		if ten, err := cp.GetTenant(ctx, tenantSlug); err == nil && ten != nil {
+			settings = &ten.Settings
+		}
+	}
+	return details, checkAuthorizationDetails(settings, details)
+}
+
+// narrowAuthorizationDetails applies the authorization_details of a token request
+// to a grant: every requested detail must be one of the granted ones (RFC 9396 §6.1).
+// Without a request the whole grant applies.
+func narrowAuthorizationDetails(granted []map[string]any, raw string) ([]map[string]any, error) {
+	requested, err := parseAuthorizationDetails(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(requested) == 0 {
+		return granted, nil
+	}
+	for _, d := range requested {
+		if !containsAuthorizationDetail(granted, d) {
+			return nil, fmt.Errorf("%w: %q was not granted", ErrInvalidAuthorizationDetails, d["type"])
+		}
+	}
+	return requested, nil
+}
+
+// authorizationDetailsFromClaim reads the authorization_details claim of a parsed token.
+func authorizationDetailsFromClaim(v any) []map[string]any {
+	list, _ := v.([]any)
+	var out []map[string]any
+	for _, x := range list {
+		if d, ok := x.(map[string]any); ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func containsAuthorizationDetail(list []map[string]any, d map[string]any) bool {
+	for _, g := range list {
+		if reflect.DeepEqual(g, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func isStringArray(v any) bool {
+	list, ok := v.([]any)
+	if !ok {
+		return false
+	}
+	for _, x := range list {
+		if _, ok := x.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+func paymentDetailsSettings() repository.TenantSettings {
+	return repository.TenantSettings{AuthorizationDetailsTypes: []repository.AuthorizationDetailsType{
+		{Type: "account_information"},
+		{Type: "payment_initiation", Schema: map[string]any{
+			"type":     "object",
+			"required": []any{"instructedAmount"},
+			"properties": map[string]any{
+				"instructedAmount": map[string]any{"type": "object", "required": []any{"currency", "amount"}},
+			},
+		}},
+	}}
+}
+
+func TestParseAuthorizationDetails(t *testing.T) {
+	details, err := parseAuthorizationDetails(`[{"type":"account_information","actions":["list_accounts"]}]`)
+	if err != nil || len(details) != 1 || details[0]["type"] != "account_information" {
+		t.Fatalf("details = %v, %v", details, err)
+	}
+	if details, err := parseAuthorizationDetails("  "); details != nil || err != nil {
+		t.Errorf("empty: %v, %v", details, err)
+	}
+	for _, bad := range []string{`{"type":"x"}`, `[1]`, `[null]`, `[{"actions":[]}]`, `[{"type":""}]`, `not json`} {
+		if _, err := parseAuthorizationDetails(bad); !errors.Is(err, ErrInvalidAuthorizationDetails) {
+			t.Errorf("%s: got %v", bad, err)
+		}
+	}
+}
+
+func TestResolveAuthorizationDetails(t *testing.T) {
+	ctx := context.Background()
+	cp := newFakeControlPlane(nil)
+	cp.tenant.Settings = paymentDetailsSettings()
+
+	valid := `[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"123.50"}},{"type":"account_information","locations":["https://bank.example.com"]}]`
+	if details, err := resolveAuthorizationDetails(ctx, cp, "acme", valid); err != nil || len(details) != 2 {
+		t.Fatalf("valid details: %v, %v", details, err)
+	}
+
+	tests := []struct{ name, raw string }{
+		{"unknown type", `[{"type":"crypto_transfer"}]`},
+		{"schema violation", `[{"type":"payment_initiation"}]`},
+		{"locations not strings", `[{"type":"account_information","locations":[1]}]`},
+		{"actions not an array", `[{"type":"account_information","actions":"read"}]`},
+		{"identifier not a string", `[{"type":"account_information","identifier":7}]`},
+	}
+	for _, tt := range tests {
+		if _, err := resolveAuthorizationDetails(ctx, cp, "acme", tt.raw); !errors.Is(err, ErrInvalidAuthorizationDetails) {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+	}
+
+	// Tenants without registered types don't accept any
+	cp.tenant.Settings = repository.TenantSettings{}
+	if _, err := resolveAuthorizationDetails(ctx, cp, "acme", `[{"type":"account_information"}]`); !errors.Is(err, ErrInvalidAuthorizationDetails) {
+		t.Errorf("no registered types: got %v", err)
+	}
+}
+
+func TestNarrowAuthorizationDetails(t *testing.T) {
+	granted, _ := parseAuthorizationDetails(`[{"type":"account_information","actions":["list_accounts"]},{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"10"}}]`)
+
+	if got, err := narrowAuthorizationDetails(granted, ""); err != nil || len(got) != 2 {
+		t.Errorf("no request: %v, %v", got, err)
+	}
+	got, err := narrowAuthorizationDetails(granted, `[{"type":"account_information","actions":["list_accounts"]}]`)
+	if err != nil || len(got) != 1 || got[0]["type"] != "account_information" {
+		t.Errorf("subset: %v, %v", got, err)
+	}
+	// A detail differing from the grant (more actions, another amount) is not part of it
+	for _, raw := range []string{
+		`[{"type":"account_information","actions":["list_accounts","read_balances"]}]`,
+		`[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"1000"}}]`,
+	} {
+		if _, err := narrowAuthorizationDetails(granted, raw); !errors.Is(err, ErrInvalidAuthorizationDetails) {
+			t.Errorf("%s: got %v", raw, err)
+		}
+	}
+}
+
+func TestAuthorizationDetailsFromClaim(t *testing.T) {
+	got := authorizationDetailsFromClaim([]any{map[string]any{"type": "a"}, "junk", map[string]any{"type": "b"}})
+	if len(got) != 2 || got[1]["type"] != "b" {
+		t.Errorf("got %v", got)
+	}
+	if authorizationDetailsFromClaim(nil) != nil {
+		t.Error("details from a missing claim")
+	}
+}
@@ -0,0 +1,193 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// OIDC prompt values (Core §3.1.2.1).
+const (
+	PromptNone          = "none"
+	PromptLogin         = "login"
+	PromptConsent       = "consent"
+	PromptSelectAccount = "select_account"
+)
+
+// Errors for OIDC authentication request params
+var (
+	ErrInvalidPrompt      = errors.New("invalid prompt")
+	ErrInvalidMaxAge      = errors.New("invalid max_age")
+	ErrInvalidIDTokenHint = errors.New("invalid id_token_hint")
+)
+
+const (
+	// cacheKeyPrefixInteraction records when we sent the user to log in / pick an account,
+	// so the return trip to /authorize isn't bounced back again (prompt=login, max_age=0).
+	cacheKeyPrefixInteraction = "authz:interaction:"
+	interactionTTL            = 15 * time.Minute
+
+	// interactionParam carries the interaction id in return_to.
+	interactionParam = "interaction"
+
+	consentChallengeTTL = 10 * time.Minute
+)
+
+// oidcParams holds the parsed prompt / max_age / acr_values of an authorize request.
+type oidcParams struct {
+	prompt map[string]bool
+	maxAge int // -1 = not requested
+	acr    []string
+}
+
+// parseOIDCParams validates prompt and max_age.
+// prompt=none can't be combined with other values (Core §3.1.2.1).
+func parseOIDCParams(req dto.AuthorizeRequest) (oidcParams, error) {
+	p := oidcParams{prompt: map[string]bool{}, maxAge: -1, acr: strings.Fields(req.ACRValues)}
+	for _, v := range strings.Fields(req.Prompt) {
+		switch v {
+		case PromptNone, PromptLogin, PromptConsent, PromptSelectAccount:
+			p.prompt[v] = true
+		default:
+			return p, ErrInvalidPrompt
+		}
+	}
+	if p.prompt[PromptNone] && len(p.prompt) > 1 {
+		return p, ErrInvalidPrompt
+	}
+	if req.MaxAge != "" {
+		n, err := strconv.Atoi(req.MaxAge)
+		if err != nil || n < 0 {
+			return p, ErrInvalidMaxAge
+		}
+		p.maxAge = n
+	}
+	if req.ResponseMode != "" && !containsString(ResponseModes, req.ResponseMode) {
+		return p, ErrInvalidResponseMode
+	}
+	return p, nil
+}
+
+// forcesLogin reports whether the request demands a fresh authentication.
+func (p oidcParams) forcesLogin() bool {
+	return p.prompt[PromptLogin] || p.maxAge == 0
+}
+
+// authTimeExpired reports whether the authentication is older than max_age.
+// An unknown auth_time never satisfies max_age.
+func (p oidcParams) authTimeExpired(authTime time.Time) bool {
+	if p.maxAge < 0 {
+		return false
+	}
+	return authTime.IsZero() || time.Since(authTime) > time.Duration(p.maxAge)*time.Second
+}
+
+// startInteraction records that the user is being sent to the login UI and returns its id.
+func (s *authorizeService) startInteraction() string {
+	id, err := tokens.GenerateOpaqueToken(16)
+	if err != nil {
+		return ""
+	}
+	s.cache.Set(cacheKeyPrefixInteraction+id, []byte(strconv.FormatInt(time.Now().Unix(), 10)), interactionTTL)
+	return id
+}
+
+// interactionTime returns when the interaction referenced by return_to started.
+func (s *authorizeService) interactionTime(r *http.Request) (time.Time, bool) {
+	id := r.URL.Query().Get(interactionParam)
+	if id == "" {
+		return time.Time{}, false
+	}
+	b, ok := s.cache.Get(cacheKeyPrefixInteraction + id)
+	if !ok {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
+}
+
+// buildLoginURL constructs the login UI URL. The UI gets the hints it needs
+// (login_hint prefill, ui_locales, acr_values, prompt) and return_to points back here.
+func (s *authorizeService) buildLoginURL(r *http.Request, req dto.AuthorizeRequest, interaction string) string {
+	returnTo := absoluteRequestURL(r)
+	if interaction != "" {
+		if u, err := url.Parse(returnTo); err == nil {
+			q := u.Query()
+			q.Set(interactionParam, interaction)
+			u.RawQuery = q.Encode()
+			returnTo = u.String()
+		}
+	}
+
+	q := url.Values{}
+	q.Set("return_to", returnTo)
+	for k, v := range map[string]string{
+		"login_hint": req.LoginHint,
+		"ui_locales": req.UILocales,
+		"acr_values": req.ACRValues,
+		"prompt":     req.Prompt,
+	} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	return s.uiBaseURL + "/login?" + q.Encode()
+}
+
+// startConsent caches a consent challenge and returns the consent UI URL.
+// ConsentService.Accept issues the auth code once the user approves.
+func (s *authorizeService) startConsent(req dto.AuthorizeRequest, userID, tenantID, sid string, amr []string, authTime int64, consent consentDecision, details []map[string]any) (string, error) {
+	consentToken, err := tokens.GenerateOpaqueToken(24)
+	if err != nil {
+		return "", err
+	}
+	challenge := dto.ConsentChallenge{
+		UserID:              userID,
+		ClientID:            req.ClientID,
+		TenantID:            tenantID,
+		RedirectURI:         req.RedirectURI,
+		RequestedScopes:     strings.Fields(req.Scope),
+		State:               req.State,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		AMR:                 amr,
+		AuthTime:            authTime,
+		SessionID:           sid,
+		ExpiresAt:           time.Now().Add(consentChallengeTTL),
+		DeniedScopes:        consent.denied,
+		Resource:            req.Resource,
+		ResponseMode:        req.ResponseMode,
+
+		AuthorizationDetails: details,
+	}
+	challengeBytes, _ := json.Marshal(challenge)
+	s.cache.Set("consent:token:"+consentToken, challengeBytes, consentChallengeTTL)
+
+	u := s.uiBaseURL + "/consent?consent_token=" + url.QueryEscape(consentToken)
+	if req.UILocales != "" {
+		u += "&ui_locales=" + url.QueryEscape(req.UILocales)
+	}
+	return u, nil
+}
+
+// authError builds an error result that is returned to the client's redirect_uri.
+func authError(req dto.AuthorizeRequest, code, description string) dto.AuthResult {
+	return dto.AuthResult{
+		Type:             dto.AuthResultError,
+		RedirectURI:      req.RedirectURI,
+		State:            req.State,
+		ErrorCode:        code,
+		ErrorDescription: description,
+	}
+}
@@ -0,0 +1,214 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+func (f *fakeControlPlane) IsScopeAllowed(client *repository.Client, scope string) bool {
+	return containsString(client.Scopes, scope)
+}
+
+func (f *fakeControlPlane) ListAPIResources(context.Context, string) ([]repository.APIResource, error) {
+	return f.resources, nil
+}
+
+// noTenantDAL has no tenant data: consent falls back to prompt=consent only.
+type noTenantDAL struct{ store.DataAccessLayer }
+
+func (noTenantDAL) ForTenant(context.Context, string) (store.TenantDataAccess, error) {
+	return nil, errors.New("no tenant data")
+}
+
+func TestParseOIDCParams(t *testing.T) {
+	p, err := parseOIDCParams(dto.AuthorizeRequest{Prompt: "login consent", MaxAge: "300", ACRValues: "urn:mfa pwd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.prompt[PromptLogin] || !p.prompt[PromptConsent] || p.maxAge != 300 || len(p.acr) != 2 {
+		t.Errorf("params = %+v", p)
+	}
+	if p, _ := parseOIDCParams(dto.AuthorizeRequest{}); p.maxAge != -1 || p.forcesLogin() {
+		t.Errorf("defaults = %+v", p)
+	}
+
+	tests := []struct {
+		name string
+		req  dto.AuthorizeRequest
+		want error
+	}{
+		{"unknown prompt", dto.AuthorizeRequest{Prompt: "create"}, ErrInvalidPrompt},
+		{"none with login", dto.AuthorizeRequest{Prompt: "none login"}, ErrInvalidPrompt},
+		{"negative max_age", dto.AuthorizeRequest{MaxAge: "-1"}, ErrInvalidMaxAge},
+		{"non-numeric max_age", dto.AuthorizeRequest{MaxAge: "1h"}, ErrInvalidMaxAge},
+		{"unknown response_mode", dto.AuthorizeRequest{ResponseMode: "web_message"}, ErrInvalidResponseMode},
+	}
+	for _, tt := range tests {
+		if _, err := parseOIDCParams(tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestAuthTimeExpired(t *testing.T) {
+	p := oidcParams{maxAge: 60}
+	if p.authTimeExpired(time.Now().Add(-30 * time.Second)) {
+		t.Error("recent login treated as expired")
+	}
+	if !p.authTimeExpired(time.Now().Add(-2 * time.Minute)) {
+		t.Error("old login accepted")
+	}
+	if !p.authTimeExpired(time.Time{}) {
+		t.Error("unknown auth_time satisfies max_age")
+	}
+	if (oidcParams{maxAge: -1}).authTimeExpired(time.Time{}) {
+		t.Error("expired without max_age")
+	}
+	if !(oidcParams{maxAge: 0}).forcesLogin() {
+		t.Error("max_age=0 doesn't force login")
+	}
+}
+
+func TestBuildLoginURL(t *testing.T) {
+	s := &authorizeService{uiBaseURL: "https://ui.example.com"}
+	r := httptest.NewRequest(http.MethodGet, "https://auth.example.com/oauth2/authorize?client_id=c", nil)
+	req := dto.AuthorizeRequest{LoginHint: "ana@example.com", UILocales: "es en", Prompt: "login"}
+
+	u, err := url.Parse(s.buildLoginURL(r, req, "int-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("login_hint") != "ana@example.com" || q.Get("ui_locales") != "es en" || q.Get("prompt") != "login" {
+		t.Errorf("hints not forwarded: %v", q)
+	}
+	if q.Has("acr_values") {
+		t.Error("empty acr_values forwarded")
+	}
+	returnTo, _ := url.Parse(q.Get("return_to"))
+	if returnTo.Query().Get(interactionParam) != "int-1" || returnTo.Query().Get("client_id") != "c" {
+		t.Errorf("return_to = %s", returnTo)
+	}
+}
+
+// authorizeTest drives authorizeClient up to the login decision.
+type authorizeTest struct {
+	s      *authorizeService
+	client *repository.Client
+}
+
+func newAuthorizeTest() *authorizeTest {
+	return &authorizeTest{
+		s: &authorizeService{
+			dal:        noTenantDAL{},
+			cp:         newFakeControlPlane(nil),
+			cache:      NewCacheAdapter(cache.NewMemory("test")),
+			cookieName: "sid",
+			uiBaseURL:  "https://ui.example.com",
+		},
+		client: &repository.Client{ClientID: "client-a"},
+	}
+}
+
+// login caches a browser session authenticated at authTime and returns its cookie.
+func (a *authorizeTest) login(authTime time.Time) *http.Cookie {
+	raw, _ := tokens.GenerateOpaqueToken(16)
+	sp, _ := json.Marshal(dto.SessionPayload{
+		UserID: "user-1", TenantID: "acme", Expires: time.Now().Add(time.Hour), AuthTime: authTime, AMR: []string{"pwd", "mfa"},
+	})
+	a.s.cache.Set(cacheKeyPrefixSID+tokens.SHA256Base64URL(raw), sp, time.Hour)
+	return &http.Cookie{Name: "sid", Value: raw}
+}
+
+func (a *authorizeTest) run(t *testing.T, target string, cookie *http.Cookie, req dto.AuthorizeRequest) dto.AuthResult {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	req.ClientID, req.RedirectURI, req.State = "client-a", "https://app.example.com/cb", "st"
+	res, err := a.s.authorizeClient(context.Background(), r, req, a.client, "acme", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+const authorizeURL = "https://auth.example.com/oauth2/authorize?client_id=client-a"
+
+func TestAuthorizePromptNoneWithoutSession(t *testing.T) {
+	a := newAuthorizeTest()
+	res := a.run(t, authorizeURL, nil, dto.AuthorizeRequest{Prompt: PromptNone})
+	if res.Type != dto.AuthResultError || res.ErrorCode != "login_required" || res.State != "st" {
+		t.Fatalf("result = %+v, want login_required", res)
+	}
+
+	res = a.run(t, authorizeURL, nil, dto.AuthorizeRequest{Prompt: "bogus"})
+	if res.Type != dto.AuthResultError || res.ErrorCode != "invalid_request" {
+		t.Fatalf("invalid prompt: result = %+v", res)
+	}
+}
+
+func TestAuthorizeMaxAge(t *testing.T) {
+	a := newAuthorizeTest()
+	stale := a.login(time.Now().Add(-time.Hour))
+
+	res := a.run(t, authorizeURL, stale, dto.AuthorizeRequest{MaxAge: "600"})
+	if res.Type != dto.AuthResultNeedLogin {
+		t.Fatalf("stale session: result = %+v, want login", res)
+	}
+	res = a.run(t, authorizeURL, stale, dto.AuthorizeRequest{MaxAge: "600", Prompt: PromptNone})
+	if res.ErrorCode != "login_required" {
+		t.Fatalf("stale session with prompt=none: result = %+v", res)
+	}
+}
+
+func TestAuthorizePromptLoginInteraction(t *testing.T) {
+	a := newAuthorizeTest()
+	cookie := a.login(time.Now().Add(-time.Minute))
+
+	res := a.run(t, authorizeURL, cookie, dto.AuthorizeRequest{Prompt: PromptLogin})
+	if res.Type != dto.AuthResultNeedLogin {
+		t.Fatalf("result = %+v, want login", res)
+	}
+	u, _ := url.Parse(res.LoginURL)
+	returnTo, _ := url.Parse(u.Query().Get("return_to"))
+	interaction := returnTo.Query().Get(interactionParam)
+	if interaction == "" {
+		t.Fatalf("no interaction in return_to %s", returnTo)
+	}
+
+	// Coming back with the old session: still has to log in
+	res = a.run(t, returnTo.String(), cookie, dto.AuthorizeRequest{Prompt: PromptLogin})
+	if res.Type != dto.AuthResultNeedLogin {
+		t.Fatalf("old session after interaction: result = %+v", res)
+	}
+
+	// A login newer than the interaction satisfies prompt=login and moves on to consent
+	fresh := a.login(time.Now().Add(time.Second))
+	res = a.run(t, returnTo.String(), fresh, dto.AuthorizeRequest{Prompt: "login consent"})
+	if res.Type != dto.AuthResultNeedConsent {
+		t.Fatalf("fresh login: result = %+v, want consent", res)
+	}
+}
+
+func TestAuthorizeRejectsForgedIDTokenHint(t *testing.T) {
+	a := newAuthorizeTest()
+	res := a.run(t, authorizeURL, nil, dto.AuthorizeRequest{IDTokenHint: "a.b.c"})
+	if res.ErrorCode != "invalid_request" || !strings.Contains(res.ErrorDescription, "id_token_hint") {
+		t.Fatalf("result = %+v", res)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -56,6 +55,9 @@ type CacheClient interface {
 	Get(key string) ([]byte, bool)
 	Set(key string, value []byte, ttl time.Duration)
 	Delete(key string) // Added for token endpoint's one-shot code consumption
+	// SetNX stores the value only if key is absent, atomically; false if it already
+	// existed or the write failed (callers treat both as "seen").
+	SetNX(key string, value []byte, ttl time.Duration) bool
 }
 
 // AuthorizeDeps contains dependencies for AuthorizeService.
@@ -67,6 +69,9 @@ type AuthorizeDeps struct {
 	CookieName   string
 	AllowBearer  bool
 	UIBaseURL    string // Default: "http://localhost:3000"
+
+	// ClientAuth verifies request objects (JAR) against the client's keys.
+	ClientAuth ClientAuthService
 }
 
 type authorizeService struct {
@@ -77,6 +82,7 @@ type authorizeService struct {
 	cookieName  string
 	allowBearer bool
 	uiBaseURL   string
+	clientAuth  ClientAuthService
 }
 
 // NewAuthorizeService creates a new AuthorizeService.
@@ -96,6 +102,7 @@ func NewAuthorizeService(d AuthorizeDeps) AuthorizeService {
 		cookieName:  d.CookieName,
 		allowBearer: d.AllowBearer,
 		uiBaseURL:   uiBase,
+		clientAuth:  d.ClientAuth,
 	}
 }
 
@@ -103,8 +110,32 @@ func NewAuthorizeService(d AuthorizeDeps) AuthorizeService {
 func (s *authorizeService) Authorize(ctx context.Context, r *http.Request, req dto.AuthorizeRequest) (dto.AuthResult, error) {
 	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("AuthorizeService.Authorize"))
 
+	// 0. Pushed authorization request: the pushed params replace the front-channel ones (RFC 9126 §4)
+	parKey := ""
+	if req.RequestURI != "" {
+		pushed, key, err := loadPushedRequest(s.cache, req.ClientID, req.RequestURI)
+		if err != nil {
+			log.Debug("request_uri resolution failed", logger.Err(err), logger.ClientID(req.ClientID))
+			return dto.AuthResult{}, err
+		}
+		req, parKey = pushed, key
+	}
+
+	// 0b. JWT-secured authorization request: the signed params replace the query ones (RFC 9101 §6.3)
+	if req.Request != "" {
+		client, tenantSlug, err := s.resolveClient(ctx, req.ClientID)
+		if err != nil {
+			log.Debug("client resolution failed", logger.Err(err), logger.ClientID(req.ClientID))
+			return dto.AuthResult{}, ErrInvalidClient
+		}
+		if req, err = resolveRequestObject(ctx, s.clientAuth, tenantSlug, client, req); err != nil {
+			log.Debug("request object rejected", logger.Err(err), logger.ClientID(client.ClientID))
+			return dto.AuthResult{}, ErrInvalidRequestObject
+		}
+	}
+
 	// 1. Validate request
-	if err := s.validateRequest(req); err != nil {
+	if err := validateAuthorizeRequest(req); err != nil {
 		return dto.AuthResult{}, err
 	}
 
@@ -115,50 +146,115 @@ func (s *authorizeService) Authorize(ctx context.Context, r *http.Request, req d
 		return dto.AuthResult{}, ErrInvalidClient
 	}
 
+	if client.RequirePAR && parKey == "" {
+		return dto.AuthResult{}, ErrPARRequired
+	}
+	if client.RequireSignedRequestObject && !req.RequestObject {
+		return dto.AuthResult{}, ErrRequestObjectRequired
+	}
+
 	if err := s.validateRedirectURI(client, req.RedirectURI); err != nil {
 		log.Debug("redirect validation failed", logger.Err(err))
 		return dto.AuthResult{}, ErrInvalidRedirect
 	}
 
+	result, err := s.authorizeClient(ctx, r, req, client, tenantSlug, parKey)
+	if err != nil {
+		return dto.AuthResult{}, err
+	}
+	return s.encodeResponse(ctx, client, tenantSlug, req.ResponseMode, result)
+}
+
+// authorizeClient runs the flow once the client and its redirect_uri are trusted:
+// from here on errors are returned as redirect results.
+func (s *authorizeService) authorizeClient(ctx context.Context, r *http.Request, req dto.AuthorizeRequest, client *repository.Client, tenantSlug, parKey string) (dto.AuthResult, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("AuthorizeService.Authorize"))
+
 	if err := s.validateScopes(client, req.Scope); err != nil {
 		log.Debug("scope validation failed", logger.Err(err))
-		return dto.AuthResult{
-			Type:             dto.AuthResultError,
-			RedirectURI:      req.RedirectURI,
-			ErrorCode:        "invalid_scope",
-			ErrorDescription: "scope not allowed",
-		}, nil
+		return authError(req, "invalid_scope", "scope not allowed"), nil
+	}
+
+	// Resource indicators must name registered APIs the client may target (RFC 8707 §2)
+	if err := checkResources(s.cp, client, listResources(ctx, s.cp, tenantSlug), req.Resource); err != nil {
+		log.Debug("resource validation failed", logger.Err(err))
+		return authError(req, "invalid_target", "resource not allowed"), nil
+	}
+
+	// authorization_details must use types registered for the tenant (RFC 9396 §5)
+	details, err := resolveAuthorizationDetails(ctx, s.cp, tenantSlug, req.AuthorizationDetails)
+	if err != nil {
+		log.Debug("authorization_details validation failed", logger.Err(err))
+		return authError(req, "invalid_authorization_details", err.Error()), nil
+	}
+
+	// From here on errors go back to the client's redirect_uri
+	params, err := parseOIDCParams(req)
+	if err != nil {
+		log.Debug("oidc params validation failed", logger.Err(err))
+		return authError(req, "invalid_request", err.Error()), nil
+	}
+
+	// id_token_hint names the user the client expects to be logged in
+	hintSub := ""
+	if req.IDTokenHint != "" {
+		hint, err := verifyIDTokenHint(ctx, s.issuer, s.cp, req.IDTokenHint)
+		if err != nil || !containsString(hint.Audience, client.ClientID) ||
+			(hint.TenantID != "" && !strings.EqualFold(hint.TenantID, tenantSlug)) {
+			log.Debug("id_token_hint rejected", logger.Err(err))
+			return authError(req, "invalid_request", ErrInvalidIDTokenHint.Error()), nil
+		}
+		hintSub = hint.Sub
 	}
 
 	// 3. Authenticate user (session cookie or bearer)
-	sub, tid, amr, authenticated := s.authenticate(ctx, r, tenantSlug)
+	sub, tid, sid, amr, authTime, authenticated := s.authenticate(ctx, r, tenantSlug)
 	log.Debug("auth result", logger.Bool("authenticated", authenticated), logger.UserID(sub), logger.TenantSlug(tid))
-
-	// 4. Not authenticated or tenant mismatch
 	if !authenticated || !strings.EqualFold(tid, tenantSlug) {
-		if strings.Contains(req.Prompt, "none") {
-			return dto.AuthResult{
-				Type:             dto.AuthResultError,
-				RedirectURI:      req.RedirectURI,
-				ErrorCode:        "login_required",
-				ErrorDescription: "login required",
-			}, nil
-		}
+		authenticated = false
+	}
 
-		loginURL := s.buildLoginURL(r)
+	// 4. Decide whether the user has to (re-)authenticate.
+	// After a forced interaction the user comes back with an interaction id;
+	// prompt=login / max_age=0 are satisfied by a login newer than that.
+	interactedAt, interacted := s.interactionTime(r)
+	needLogin, forced := !authenticated, false
+	switch {
+	case needLogin:
+	case hintSub != "" && hintSub != sub:
+		needLogin = true
+	case params.forcesLogin():
+		needLogin, forced = !interacted || authTime.Before(interactedAt), true
+	case params.authTimeExpired(authTime):
+		needLogin = true
+	case params.prompt[PromptSelectAccount] && !interacted:
+		needLogin, forced = true, true
+	}
+
+	if needLogin {
+		if params.prompt[PromptNone] {
+			return authError(req, "login_required", "login required"), nil
+		}
+		interaction := ""
+		if forced {
+			interaction = s.startInteraction()
+		}
 		return dto.AuthResult{
 			Type:     dto.AuthResultNeedLogin,
-			LoginURL: loginURL,
+			LoginURL: s.buildLoginURL(r, req, interaction),
 		}, nil
 	}
 
 	// 5. MFA step-up check
-	if len(amr) == 1 && amr[0] == "pwd" {
-		needMFA, mfaToken, err := s.checkMFAStepUp(ctx, r, sub, tid, req.ClientID, req.Scope)
+	if len(amr) == 1 && isFirstFactor(amr[0]) {
+		needMFA, mfaToken, err := s.checkMFAStepUp(ctx, r, sub, tid, req.ClientID, req.Scope, amr)
 		if err != nil {
 			log.Debug("MFA check failed", logger.Err(err))
 		}
 		if needMFA {
+			if params.prompt[PromptNone] {
+				return authError(req, "interaction_required", "mfa required"), nil
+			}
 			return dto.AuthResult{
 				Type:     dto.AuthResultMFARequired,
 				MFAToken: mfaToken,
@@ -170,7 +266,31 @@ func (s *authorizeService) Authorize(ctx context.Context, r *http.Request, req d
 		}
 	}
 
-	// 6. Generate auth code and store in cache
+	var authTimeUnix int64
+	if !authTime.IsZero() {
+		authTimeUnix = authTime.Unix()
+	}
+
+	// 6. Consent (prompt=consent or the tenant ConsentPolicy). Authorization
+	// details describe a single transaction, so the user always approves them.
+	consent := s.evaluateConsent(ctx, client, tenantSlug, sub, strings.Fields(req.Scope), params.prompt[PromptConsent] || len(details) > 0)
+	if consent.required {
+		if params.prompt[PromptNone] {
+			return authError(req, "consent_required", "consent required"), nil
+		}
+		consentURL, err := s.startConsent(req, sub, tid, sid, amr, authTimeUnix, consent, details)
+		if err != nil {
+			log.Error("consent challenge failed", logger.Err(err))
+			return dto.AuthResult{}, ErrCodeGenFailed
+		}
+		// the consent screen issues the code, so the request_uri is spent here
+		if parKey != "" {
+			s.cache.Delete(parKey)
+		}
+		return dto.AuthResult{Type: dto.AuthResultNeedConsent, ConsentURL: consentURL}, nil
+	}
+
+	// 7. Generate auth code and store in cache
 	code, err := tokens.GenerateOpaqueToken(32)
 	if err != nil {
 		log.Error("code generation failed", logger.Err(err))
@@ -182,18 +302,28 @@ func (s *authorizeService) Authorize(ctx context.Context, r *http.Request, req d
 		TenantID:        tid,
 		ClientID:        req.ClientID,
 		RedirectURI:     req.RedirectURI,
-		Scope:           req.Scope,
+		Scope:           strings.Join(consent.scopes, " "),
 		Nonce:           req.Nonce,
 		CodeChallenge:   req.CodeChallenge,
 		ChallengeMethod: req.CodeChallengeMethod,
 		AMR:             amr,
+		AuthTime:        authTimeUnix,
+		SessionID:       sid,
 		ExpiresAt:       time.Now().Add(authCodeTTL),
+		Resource:        req.Resource,
+
+		AuthorizationDetails: details,
 	}
 	payloadBytes, _ := json.Marshal(payload)
 	// Store hashed code in cache (hardening)
 	codeHash := tokens.SHA256Base64URL(code)
 	s.cache.Set(cacheKeyPrefixCode+codeHash, payloadBytes, authCodeTTL)
 
+	// request_uri is one-time use; kept until here so it survives the login round-trip
+	if parKey != "" {
+		s.cache.Delete(parKey)
+	}
+
 	log.Info("auth code issued", logger.UserID(sub), logger.TenantSlug(tid), logger.ClientID(req.ClientID))
 
 	return dto.AuthResult{
@@ -204,8 +334,29 @@ func (s *authorizeService) Authorize(ctx context.Context, r *http.Request, req d
 	}, nil
 }
 
-// validateRequest checks required params for authorize.
-func (s *authorizeService) validateRequest(req dto.AuthorizeRequest) error {
+// encodeResponse applies the requested response_mode to code and error results;
+// JARM modes carry them as a single signed "response" param.
+func (s *authorizeService) encodeResponse(ctx context.Context, client *repository.Client, tenantSlug, mode string, result dto.AuthResult) (dto.AuthResult, error) {
+	var params map[string]string
+	switch result.Type {
+	case dto.AuthResultSuccess:
+		params = map[string]string{"code": result.Code, "state": result.State}
+	case dto.AuthResultError:
+		params = map[string]string{"error": result.ErrorCode, "error_description": result.ErrorDescription, "state": result.State}
+	default:
+		return result, nil
+	}
+	delivery, response, err := signAuthorizationResponse(ctx, s.issuer, s.cp, client, tenantSlug, mode, params)
+	if err != nil {
+		logger.From(ctx).Error("authorization response signing failed", logger.Err(err))
+		return dto.AuthResult{}, ErrCodeGenFailed
+	}
+	result.ResponseMode, result.Response = delivery, response
+	return result, nil
+}
+
+// validateAuthorizeRequest checks required params for authorize (also used by PAR).
+func validateAuthorizeRequest(req dto.AuthorizeRequest) error {
 	if req.ResponseType != "code" || req.ClientID == "" || req.RedirectURI == "" || req.Scope == "" {
 		return ErrMissingParams
 	}
@@ -264,15 +415,21 @@ func (s *authorizeService) validateScopes(client *repository.Client, scopeStr st
 }
 
 // authenticate tries cookie session first, then bearer token.
-func (s *authorizeService) authenticate(ctx context.Context, r *http.Request, expectedTenant string) (sub, tid string, amr []string, ok bool) {
+// authTime is zero when unknown (sessions created before it was tracked).
+// sid identifies the browser session (hash of the cookie) the tokens are issued under.
+func (s *authorizeService) authenticate(ctx context.Context, r *http.Request, expectedTenant string) (sub, tid, sid string, amr []string, authTime time.Time, ok bool) {
 	// 1. Try session cookie
 	if ck, err := r.Cookie(s.cookieName); err == nil && ck != nil && strings.TrimSpace(ck.Value) != "" {
-		key := cacheKeyPrefixSID + tokens.SHA256Base64URL(ck.Value)
-		if b, found := s.cache.Get(key); found {
+		sidHash := tokens.SHA256Base64URL(ck.Value)
+		if b, found := s.cache.Get(cacheKeyPrefixSID + sidHash); found {
 			var sp dto.SessionPayload
 			if json.Unmarshal(b, &sp) == nil {
 				if time.Now().Before(sp.Expires) && strings.EqualFold(sp.TenantID, expectedTenant) {
-					return sp.UserID, sp.TenantID, []string{"pwd"}, true
+					amr := sp.AMR
+					if len(amr) == 0 {
+						amr = []string{"pwd"}
+					}
+					return sp.UserID, sp.TenantID, sidHash, amr, sp.AuthTime, true
 				}
 			}
 		}
@@ -284,12 +441,16 @@ func (s *authorizeService) authenticate(ctx context.Context, r *http.Request, ex
 		if strings.HasPrefix(strings.ToLower(ah), "bearer ") {
 			raw := strings.TrimSpace(ah[len("Bearer "):])
 			tk, err := jwtv5.Parse(raw, s.issuer.Keyfunc(),
-				jwtv5.WithValidMethods([]string{"EdDSA"}),
+				jwtv5.WithValidMethods(jwtx.SigningAlgs),
 				jwtv5.WithIssuer(s.issuer.Iss))
 			if err == nil && tk.Valid {
 				if claims, ok := tk.Claims.(jwtv5.MapClaims); ok {
-					sub, _ = claims["sub"].(string)
 					tid, _ = claims["tid"].(string)
+					sid, _ = claims["sid"].(string)
+					// A token minted for a pairwise client carries a per-sector sub
+					if local, err := localSubject(ctx, s.cp, tid, claims); err == nil {
+						sub = local
+					}
 					if v, ok := claims["amr"].([]any); ok {
 						for _, i := range v {
 							if s, ok := i.(string); ok {
@@ -297,20 +458,34 @@ func (s *authorizeService) authenticate(ctx context.Context, r *http.Request, ex
 							}
 						}
 					}
+					// iat is not an authentication time; without auth_time it stays unknown
+					if v, ok := claims["auth_time"].(float64); ok {
+						authTime = time.Unix(int64(v), 0)
+					}
 					if sub != "" && tid != "" {
-						return sub, tid, amr, true
+						return sub, tid, sid, amr, authTime, true
 					}
 				}
 			}
 		}
 	}
 
-	return "", "", nil, false
+	return "", "", "", nil, time.Time{}, false
+}
+
+// isFirstFactor reports whether a lone AMR value still needs the MFA step-up:
+// password or a passwordless email code/link.
+func isFirstFactor(amr string) bool {
+	switch amr {
+	case "pwd", "otp", "email":
+		return true
+	}
+	return false
 }
 
 // checkMFAStepUp checks if user needs MFA verification.
 // Returns (needMFA, mfaToken, error). If trusted device, needMFA=false and mfaToken="".
-func (s *authorizeService) checkMFAStepUp(ctx context.Context, r *http.Request, userID, tenantID, clientID, scope string) (bool, string, error) {
+func (s *authorizeService) checkMFAStepUp(ctx context.Context, r *http.Request, userID, tenantID, clientID, scope string, amr []string) (bool, string, error) {
 	// Get tenant data access
 	tda, err := s.dal.ForTenant(ctx, tenantID)
 	if err != nil {
@@ -342,7 +517,7 @@ func (s *authorizeService) checkMFAStepUp(ctx context.Context, r *http.Request,
 		UserID:   userID,
 		TenantID: tenantID,
 		ClientID: clientID,
-		AMRBase:  []string{"pwd"},
+		AMRBase:  amr,
 		Scope:    strings.Fields(scope),
 	}
 	challengeBytes, _ := json.Marshal(challenge)
@@ -357,9 +532,8 @@ func (s *authorizeService) checkMFAStepUp(ctx context.Context, r *http.Request,
 	return true, mid, nil
 }
 
-// buildLoginURL constructs the URL to redirect for login.
-func (s *authorizeService) buildLoginURL(r *http.Request) string {
-	// Build return_to from current request
+// absoluteRequestURL rebuilds the absolute URL of the current request (used as return_to).
+func absoluteRequestURL(r *http.Request) string {
 	returnTo := r.URL.String()
 	if !r.URL.IsAbs() {
 		scheme := "http"
@@ -372,6 +546,5 @@ func (s *authorizeService) buildLoginURL(r *http.Request) string {
 		}
 		returnTo = fmt.Sprintf("%s://%s%s", scheme, host, r.URL.RequestURI())
 	}
-
-	return fmt.Sprintf("%s/login?return_to=%s", s.uiBaseURL, url.QueryEscape(returnTo))
+	return returnTo
 }
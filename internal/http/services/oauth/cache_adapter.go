@@ -28,6 +28,11 @@ func (a *CacheAdapter) Set(key string, value []byte, ttl time.Duration) {
 	_ = a.Client.Set(context.Background(), key, string(value), ttl)
 }
 
+func (a *CacheAdapter) SetNX(key string, value []byte, ttl time.Duration) bool {
+	ok, err := a.Client.SetNX(context.Background(), key, string(value), ttl)
+	return err == nil && ok
+}
+
 func (a *CacheAdapter) Delete(key string) {
 	_ = a.Client.Delete(context.Background(), key)
 }
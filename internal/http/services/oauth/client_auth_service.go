@@ -0,0 +1,273 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// ClientAssertionTypeJWTBearer is the client_assertion_type for JWT client authentication (RFC 7523 §2.2).
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+const (
+	cacheKeyPrefixAssertionJTI = "client_assertion:jti:"
+	assertionLeeway            = 30 * time.Second
+	assertionMaxLifetime       = time.Hour
+)
+
+// Signing algorithms accepted for client assertions.
+var (
+	PrivateKeyJWTAlgs   = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+	ClientSecretJWTAlgs = []string{"HS256", "HS384", "HS512"}
+)
+
+// assertionAudiencePaths are the endpoints a client assertion may be addressed to.
+var assertionAudiencePaths = []string{"/oauth2/token", "/oauth2/introspect", "/oauth2/revoke", "/oauth2/par", "/oauth2/device_authorization"}
+
+// ClientAssertion carries the client_assertion parameters of a request.
+type ClientAssertion struct {
+	Type  string
+	Value string
+}
+
+// Present reports whether the request carried a client assertion.
+func (a ClientAssertion) Present() bool {
+	return a.Type != "" || a.Value != ""
+}
+
+// ClientAuthService verifies RFC 7523 client assertions (private_key_jwt, client_secret_jwt).
+type ClientAuthService interface {
+	// Authenticate resolves the client and verifies its assertion.
+	// clientID may be empty, in which case it is taken from the assertion "sub".
+	// Returns the client and the slug of the tenant that owns it.
+	Authenticate(ctx context.Context, tenantSlug, clientID string, assertion ClientAssertion) (*repository.Client, string, error)
+
+	// Verify checks an assertion for an already resolved client.
+	Verify(ctx context.Context, tenantSlug string, client *repository.Client, assertion ClientAssertion) error
+
+	// VerifyRequestObject verifies a request object (RFC 9101) signed with the client's
+	// keys, decrypting it first with the tenant key if it is a JWE. Returns its claims.
+	VerifyRequestObject(ctx context.Context, tenantSlug string, client *repository.Client, raw string) (map[string]any, error)
+
+	// EncryptionKey returns the client's public key for encrypting responses with alg
+	// (a use=enc key from its jwks or jwks_uri).
+	EncryptionKey(ctx context.Context, client *repository.Client, alg string) (*jwtx.JWK, error)
+}
+
+// ClientAuthDeps contains dependencies for ClientAuthService.
+type ClientAuthDeps struct {
+	ControlPlane controlplane.Service
+	Cache        CacheClient
+	Issuer       *jwtx.Issuer
+	JWKS         *jwtx.RemoteJWKSCache // jwks_uri cache (optional, created if nil)
+}
+
+type clientAuthService struct {
+	cp     controlplane.Service
+	cache  CacheClient
+	issuer *jwtx.Issuer
+	jwks   *jwtx.RemoteJWKSCache
+}
+
+// Client assertion errors.
+var (
+	ErrClientAssertionInvalid  = errors.New("invalid client assertion")
+	ErrClientAssertionReplayed = errors.New("client assertion replayed")
+)
+
+// NewClientAuthService creates a new ClientAuthService.
+func NewClientAuthService(d ClientAuthDeps) ClientAuthService {
+	jwks := d.JWKS
+	if jwks == nil {
+		jwks = jwtx.NewRemoteJWKSCache(0)
+	}
+	return &clientAuthService{
+		cp:     d.ControlPlane,
+		cache:  d.Cache,
+		issuer: d.Issuer,
+		jwks:   jwks,
+	}
+}
+
+func (s *clientAuthService) Authenticate(ctx context.Context, tenantSlug, clientID string, assertion ClientAssertion) (*repository.Client, string, error) {
+	sub := ClientIDFromAssertion(assertion.Value)
+	if clientID == "" {
+		clientID = sub
+	}
+	if clientID == "" || sub != clientID {
+		return nil, "", fmt.Errorf("%w: client_id mismatch", ErrClientAssertionInvalid)
+	}
+
+	client, slug, err := findClient(ctx, s.cp, tenantSlug, clientID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.Verify(ctx, slug, client, assertion); err != nil {
+		return nil, "", err
+	}
+	return client, slug, nil
+}
+
+func (s *clientAuthService) Verify(ctx context.Context, tenantSlug string, client *repository.Client, assertion ClientAssertion) error {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.client_auth.verify"))
+
+	if assertion.Type != ClientAssertionTypeJWTBearer || assertion.Value == "" {
+		return fmt.Errorf("%w: unsupported client_assertion_type", ErrClientAssertionInvalid)
+	}
+
+	var keyfunc jwtv5.Keyfunc
+	var algs []string
+	switch client.TokenEndpointAuthMethod {
+	case repository.AuthMethodPrivateKeyJWT:
+		algs = PrivateKeyJWTAlgs
+		keyfunc = func(t *jwtv5.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return s.clientKey(ctx, client, kid)
+		}
+	case repository.AuthMethodClientSecretJWT:
+		secret, err := s.cp.DecryptClientSecret(ctx, tenantSlug, client.ClientID)
+		if err != nil || secret == "" {
+			log.Warn("client secret not available", logger.String("client_id", client.ClientID))
+			return fmt.Errorf("%w: client secret not available", ErrClientAssertionInvalid)
+		}
+		algs = ClientSecretJWTAlgs
+		keyfunc = func(*jwtv5.Token) (any, error) { return []byte(secret), nil }
+	default:
+		return fmt.Errorf("%w: client not registered for jwt authentication", ErrClientAssertionInvalid)
+	}
+
+	tk, err := jwtv5.Parse(assertion.Value, keyfunc,
+		jwtv5.WithValidMethods(algs),
+		jwtv5.WithIssuer(client.ClientID),
+		jwtv5.WithSubject(client.ClientID),
+		jwtv5.WithExpirationRequired(),
+		jwtv5.WithLeeway(assertionLeeway))
+	if err != nil || !tk.Valid {
+		return fmt.Errorf("%w: %v", ErrClientAssertionInvalid, err)
+	}
+	claims, ok := tk.Claims.(jwtv5.MapClaims)
+	if !ok {
+		return fmt.Errorf("%w: unexpected claims type", ErrClientAssertionInvalid)
+	}
+
+	// aud must identify this authorization server (RFC 7523 §3)
+	aud, _ := claims.GetAudience()
+	if !s.audienceAllowed(ctx, tenantSlug, aud) {
+		return fmt.Errorf("%w: invalid aud", ErrClientAssertionInvalid)
+	}
+
+	exp, _ := claims.GetExpirationTime()
+	ttl := time.Until(exp.Time) + assertionLeeway
+	if ttl > assertionMaxLifetime {
+		return fmt.Errorf("%w: exp too far in the future", ErrClientAssertionInvalid)
+	}
+
+	// Single use: remember jti until the assertion expires
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return fmt.Errorf("%w: jti required", ErrClientAssertionInvalid)
+	}
+	key := cacheKeyPrefixAssertionJTI + tenantSlug + ":" + client.ClientID + ":" + jti
+	if !s.cache.SetNX(key, []byte("1"), ttl) {
+		log.Warn("client assertion replayed", logger.String("client_id", client.ClientID))
+		return ErrClientAssertionReplayed
+	}
+
+	return nil
+}
+
+// clientKey returns the client's public key for kid from its inline JWKS or jwks_uri.
+func (s *clientAuthService) clientKey(ctx context.Context, client *repository.Client, kid string) (any, error) {
+	if client.JWKS != "" {
+		set, err := jwtx.ParseJWKS([]byte(client.JWKS))
+		if err != nil {
+			return nil, err
+		}
+		k, err := set.Find(kid)
+		if err != nil {
+			return nil, err
+		}
+		return k.PublicKey()
+	}
+
+	if client.JWKSURI == "" {
+		return nil, fmt.Errorf("client has no jwks")
+	}
+	set, err := s.jwks.Get(ctx, client.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	k, err := set.Find(kid)
+	if errors.Is(err, jwtx.ErrJWKNotFound) {
+		// Unknown kid: the client may have rotated keys. Refresh refetches at
+		// most once per RemoteJWKSMinRefresh, so unknown kids can't force a
+		// download on every request.
+		if set, err = s.jwks.Refresh(ctx, client.JWKSURI); err != nil {
+			return nil, err
+		}
+		k, err = set.Find(kid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return k.PublicKey()
+}
+
+func (s *clientAuthService) EncryptionKey(ctx context.Context, client *repository.Client, alg string) (*jwtx.JWK, error) {
+	return s.jwks.EncryptionKey(ctx, client.JWKS, client.JWKSURI, alg)
+}
+
+// audienceAllowed accepts the issuer (base or tenant-effective) or one of our endpoint URLs.
+func (s *clientAuthService) audienceAllowed(ctx context.Context, tenantSlug string, aud []string) bool {
+	if s.issuer == nil {
+		return false
+	}
+	base := strings.TrimRight(s.issuer.Iss, "/")
+	allowed := []string{base}
+	for _, p := range assertionAudiencePaths {
+		allowed = append(allowed, base+p)
+	}
+	if s.cp != nil {
+		if ten, err := s.cp.GetTenant(ctx, tenantSlug); err == nil && ten != nil {
+			allowed = append(allowed, jwtx.ResolveIssuer(s.issuer.Iss, string(ten.Settings.IssuerMode), ten.Slug, ten.Settings.IssuerOverride))
+		}
+	}
+
+	for _, a := range aud {
+		if containsString(allowed, strings.TrimRight(a, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIDFromAssertion returns the unverified "sub" of a client assertion,
+// used to identify the client when client_id is omitted (RFC 7523 §3).
+func ClientIDFromAssertion(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	var claims jwtv5.MapClaims
+	if _, _, err := jwtv5.NewParser().ParseUnverified(raw, &claims); err != nil {
+		return ""
+	}
+	sub, _ := claims["sub"].(string)
+	return sub
+}
+
+// requiresAssertion reports whether the client must authenticate with a JWT.
+func requiresAssertion(client *repository.Client) bool {
+	switch client.TokenEndpointAuthMethod {
+	case repository.AuthMethodPrivateKeyJWT, repository.AuthMethodClientSecretJWT:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+const testIssuer = "https://auth.example.com"
+
+// privateKeyJWTClient returns a private_key_jwt client with an inline JWKS
+// holding the public half of the returned key.
+func privateKeyJWTClient(t *testing.T) (*repository.Client, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(jwtx.JWKSet{Keys: []jwtx.JWK{{
+		Kty: "EC",
+		Kid: "k1",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}}})
+	return &repository.Client{
+		ClientID:                "client-a",
+		TokenEndpointAuthMethod: repository.AuthMethodPrivateKeyJWT,
+		JWKS:                    string(jwks),
+	}, key
+}
+
+func signAssertion(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwtv5.MapClaims) ClientAssertion {
+	t.Helper()
+	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodES256, claims)
+	tk.Header["kid"] = kid
+	raw, err := tk.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ClientAssertion{Type: ClientAssertionTypeJWTBearer, Value: raw}
+}
+
+func assertionClaims(jti string) jwtv5.MapClaims {
+	return jwtv5.MapClaims{
+		"iss": "client-a",
+		"sub": "client-a",
+		"aud": testIssuer + "/oauth2/token",
+		"exp": time.Now().Add(2 * time.Minute).Unix(),
+		"jti": jti,
+	}
+}
+
+func newTestClientAuth() *clientAuthService {
+	return NewClientAuthService(ClientAuthDeps{
+		Cache:  NewCacheAdapter(cache.NewMemory("test")),
+		Issuer: &jwtx.Issuer{Iss: testIssuer},
+	}).(*clientAuthService)
+}
+
+func TestVerifyPrivateKeyJWT(t *testing.T) {
+	ctx := context.Background()
+	s := newTestClientAuth()
+	client, key := privateKeyJWTClient(t)
+
+	if err := s.Verify(ctx, "acme", client, signAssertion(t, key, "k1", assertionClaims("jti-1"))); err != nil {
+		t.Fatalf("valid assertion rejected: %v", err)
+	}
+}
+
+func TestVerifyRejectsReplayedAssertion(t *testing.T) {
+	ctx := context.Background()
+	s := newTestClientAuth()
+	client, key := privateKeyJWTClient(t)
+
+	assertion := signAssertion(t, key, "k1", assertionClaims("jti-replay"))
+	if err := s.Verify(ctx, "acme", client, assertion); err != nil {
+		t.Fatalf("first use rejected: %v", err)
+	}
+	if err := s.Verify(ctx, "acme", client, assertion); !errors.Is(err, ErrClientAssertionReplayed) {
+		t.Fatalf("replay: got %v, want ErrClientAssertionReplayed", err)
+	}
+}
+
+func TestVerifyRejectsInvalidAssertions(t *testing.T) {
+	ctx := context.Background()
+	s := newTestClientAuth()
+	client, key := privateKeyJWTClient(t)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name  string
+		key   *ecdsa.PrivateKey
+		kid   string
+		claim func(jwtv5.MapClaims)
+	}{
+		{"wrong key", otherKey, "k1", nil},
+		{"unknown kid", key, "k2", nil},
+		{"wrong audience", key, "k1", func(c jwtv5.MapClaims) { c["aud"] = "https://other.example.com" }},
+		{"sub is another client", key, "k1", func(c jwtv5.MapClaims) { c["sub"] = "client-b" }},
+		{"expired", key, "k1", func(c jwtv5.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"exp too far", key, "k1", func(c jwtv5.MapClaims) { c["exp"] = time.Now().Add(2 * time.Hour).Unix() }},
+		{"no jti", key, "k1", func(c jwtv5.MapClaims) { delete(c, "jti") }},
+	}
+	for i, tt := range tests {
+		claims := assertionClaims("jti-invalid-" + string(rune('a'+i)))
+		if tt.claim != nil {
+			tt.claim(claims)
+		}
+		err := s.Verify(ctx, "acme", client, signAssertion(t, tt.key, tt.kid, claims))
+		if !errors.Is(err, ErrClientAssertionInvalid) {
+			t.Errorf("%s: got %v, want ErrClientAssertionInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestVerifyRejectsWrongAssertionType(t *testing.T) {
+	s := newTestClientAuth()
+	client, key := privateKeyJWTClient(t)
+
+	assertion := signAssertion(t, key, "k1", assertionClaims("jti-type"))
+	assertion.Type = "urn:ietf:params:oauth:client-assertion-type:saml2-bearer"
+	if err := s.Verify(context.Background(), "acme", client, assertion); !errors.Is(err, ErrClientAssertionInvalid) {
+		t.Fatalf("got %v, want ErrClientAssertionInvalid", err)
+	}
+}
+
+func TestClientIDFromAssertion(t *testing.T) {
+	_, key := privateKeyJWTClient(t)
+	assertion := signAssertion(t, key, "k1", assertionClaims("jti-sub"))
+	if got := ClientIDFromAssertion(assertion.Value); got != "client-a" {
+		t.Errorf("ClientIDFromAssertion = %q, want client-a", got)
+	}
+	if got := ClientIDFromAssertion("not-a-jwt"); got != "" {
+		t.Errorf("ClientIDFromAssertion(garbage) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// findClient looks up a client in the given tenant first and then across all tenants.
+// Returns the client and the slug of the tenant that owns it.
+func findClient(ctx context.Context, cp controlplane.Service, tenantSlug, clientID string) (*repository.Client, string, error) {
+	if cp == nil {
+		return nil, "", fmt.Errorf("control plane not initialized")
+	}
+
+	// Try the provided tenant slug first
+	if tenantSlug != "" {
+		c, err := cp.GetClient(ctx, tenantSlug, clientID)
+		if err == nil && c != nil {
+			return c, tenantSlug, nil
+		}
+	}
+
+	// Search across all tenants
+	tenants, err := cp.ListTenants(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, t := range tenants {
+		if t.Slug == tenantSlug {
+			continue
+		}
+		c, err := cp.GetClient(ctx, t.Slug, clientID)
+		if err == nil && c != nil {
+			return c, t.Slug, nil
+		}
+	}
+	return nil, "", fmt.Errorf("client not found")
+}
+
+// checkClientSecret validates the secret of a confidential client.
+// Public clients have no secret and always pass.
+func checkClientSecret(ctx context.Context, cp controlplane.Service, tenantSlug string, client *repository.Client, providedSecret string) error {
+	if client.Type != repository.ClientTypeConfidential {
+		return nil // only confidential clients have secrets
+	}
+	dec, err := cp.DecryptClientSecret(ctx, tenantSlug, client.ClientID)
+	if err != nil {
+		return err
+	}
+	if dec == "" || !subtleEq(dec, providedSecret) {
+		return fmt.Errorf("invalid secret")
+	}
+	return nil
+}
+
+// authenticateRequestClient resolves and authenticates the client of a back-channel
+// request (PAR, device_authorization) with its registered method, as the token
+// endpoint does: mutual TLS, JWT assertion (private_key_jwt, client_secret_jwt) or
+// client secret. Public clients are identified by client_id only. roots are the
+// CAs for tls_client_auth (nil = trust the TLS terminator). Returns the client
+// and its tenant slug.
+func authenticateRequestClient(ctx context.Context, cp controlplane.Service, clientAuth ClientAuthService, roots *x509.CertPool, tenantSlug, clientID, secret string, assertion ClientAssertion, cert *x509.Certificate) (*repository.Client, string, error) {
+	if assertion.Present() {
+		if clientAuth == nil {
+			return nil, "", ErrClientAssertionInvalid
+		}
+		return clientAuth.Authenticate(ctx, tenantSlug, clientID, assertion)
+	}
+
+	if clientID == "" {
+		return nil, "", ErrTokenInvalidClient
+	}
+	client, slug, err := findClient(ctx, cp, tenantSlug, clientID)
+	if err != nil {
+		return nil, "", err
+	}
+	if requiresTLSClientAuth(client) {
+		if err := verifyClientCertificate(client, cert, roots); err != nil {
+			return nil, "", err
+		}
+		return client, slug, nil
+	}
+	if requiresAssertion(client) {
+		return nil, "", ErrClientAssertionInvalid
+	}
+	if err := checkClientSecret(ctx, cp, slug, client, secret); err != nil {
+		return nil, "", err
+	}
+	return client, slug, nil
+}
@@ -0,0 +1,126 @@
+package oauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// consentDecision is the outcome of applying the tenant ConsentPolicy to an authorize request.
+type consentDecision struct {
+	required bool
+	scopes   []string // scopes granted without prompting (only when !required)
+	denied   []string // remembered denials, used to pre-fill the consent screen
+}
+
+// evaluateConsent decides whether the user has to go through the consent screen.
+// Without a policy (or with show_consent_screen off) only prompt=consent asks.
+func (s *authorizeService) evaluateConsent(ctx context.Context, client *repository.Client, tenantSlug, userID string, requested []string, explicit bool) consentDecision {
+	d := consentDecision{scopes: requested}
+
+	tda, err := s.dal.ForTenant(ctx, tenantSlug)
+	if err != nil {
+		d.required = explicit
+		return d
+	}
+	policy := tda.Settings().ConsentPolicy
+	if policy == nil || !policy.ShowConsentScreen {
+		d.required = explicit
+		return d
+	}
+	mode := consentMode(policy)
+
+	if !explicit && policy.AllowSkipConsentForFirstParty && client.FirstParty {
+		return d
+	}
+
+	prior := activeConsent(ctx, tda, policy, tenantSlug, userID, client.ClientID, time.Now())
+	if prior != nil && mode == repository.ConsentModePerScope && policy.RememberScopeDecisions {
+		d.denied = prior.DeniedScopes
+	}
+	if explicit || prior == nil || repromptDue(policy, prior, time.Now()) {
+		d.required = true
+		return d
+	}
+
+	granted, covered := coveredScopes(policy, mode, prior, requested)
+	if !covered {
+		d.required = true
+		return d
+	}
+	d.scopes = granted
+	return d
+}
+
+// activeConsent returns the stored consent if it is still valid under the policy.
+// Revoked or expired (expiration_days) consents count as missing.
+func activeConsent(ctx context.Context, tda store.TenantDataAccess, policy *repository.ConsentPolicySettings, tenantSlug, userID, clientID string, now time.Time) *repository.Consent {
+	if tda.Consents() == nil {
+		return nil
+	}
+	c, err := tda.Consents().Get(ctx, tenantSlug, userID, clientID)
+	if err != nil || c == nil || c.RevokedAt != nil {
+		return nil
+	}
+	if policy.ExpirationDays != nil && *policy.ExpirationDays > 0 &&
+		!now.Before(c.GrantedAt.AddDate(0, 0, *policy.ExpirationDays)) {
+		return nil
+	}
+	return c
+}
+
+// repromptDue reports whether the user must re-confirm a still valid consent (reprompt_days).
+func repromptDue(policy *repository.ConsentPolicySettings, c *repository.Consent, now time.Time) bool {
+	if policy.RepromptDays == nil || *policy.RepromptDays <= 0 {
+		return false
+	}
+	last := c.UpdatedAt
+	if last.IsZero() {
+		last = c.GrantedAt
+	}
+	return !now.Before(last.AddDate(0, 0, *policy.RepromptDays))
+}
+
+// coveredScopes checks whether the stored decision answers every requested scope.
+// In per_scope mode with remembered decisions a denied scope counts as answered
+// and is dropped from the grant; otherwise every scope must have been granted.
+func coveredScopes(policy *repository.ConsentPolicySettings, mode string, c *repository.Consent, requested []string) ([]string, bool) {
+	rememberDenials := mode == repository.ConsentModePerScope && policy.RememberScopeDecisions
+	granted := make([]string, 0, len(requested))
+	for _, sc := range requested {
+		switch {
+		case containsString(c.Scopes, sc):
+			granted = append(granted, sc)
+		case rememberDenials && sc != "openid" && containsString(c.DeniedScopes, sc):
+		default:
+			return nil, false
+		}
+	}
+	return granted, true
+}
+
+// consentMode normalizes the policy mode; anything but per_scope is single.
+func consentMode(policy *repository.ConsentPolicySettings) string {
+	if policy != nil && policy.ConsentMode == repository.ConsentModePerScope {
+		return repository.ConsentModePerScope
+	}
+	return repository.ConsentModeSingle
+}
+
+// splitScopeDecision splits the requested scopes into granted and denied according
+// to the user's selection. openid can't be denied; an empty selection grants all.
+func splitScopeDecision(requested, selected []string) (granted, denied []string) {
+	if len(selected) == 0 {
+		return requested, nil
+	}
+	for _, sc := range requested {
+		if sc == "openid" || containsString(selected, sc) {
+			granted = append(granted, sc)
+		} else {
+			denied = append(denied, sc)
+		}
+	}
+	return granted, denied
+}
@@ -0,0 +1,116 @@
+package oauth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+type storedConsents struct {
+	repository.ConsentRepository
+	consent *repository.Consent
+}
+
+func (s *storedConsents) Get(context.Context, string, string, string) (*repository.Consent, error) {
+	if s.consent == nil {
+		return nil, repository.ErrNotFound
+	}
+	return s.consent, nil
+}
+
+type consentTDA struct {
+	store.TenantDataAccess
+	settings repository.TenantSettings
+	consents *storedConsents
+}
+
+func (t *consentTDA) Settings() *repository.TenantSettings   { return &t.settings }
+func (t *consentTDA) Consents() repository.ConsentRepository { return t.consents }
+
+type tenantDAL struct {
+	store.DataAccessLayer
+	tda store.TenantDataAccess
+}
+
+func (d tenantDAL) ForTenant(context.Context, string) (store.TenantDataAccess, error) {
+	return d.tda, nil
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestEvaluateConsent(t *testing.T) {
+	now := time.Now()
+	requested := []string{"openid", "profile", "email"}
+	policy := func(edit func(*repository.ConsentPolicySettings)) *repository.ConsentPolicySettings {
+		p := &repository.ConsentPolicySettings{ShowConsentScreen: true}
+		if edit != nil {
+			edit(p)
+		}
+		return p
+	}
+	granted := func(scopes ...string) *repository.Consent {
+		return &repository.Consent{Scopes: scopes, GrantedAt: now.Add(-24 * time.Hour)}
+	}
+
+	tests := []struct {
+		name       string
+		policy     *repository.ConsentPolicySettings
+		prior      *repository.Consent
+		firstParty bool
+		explicit   bool
+		want       consentDecision
+	}{
+		{"no policy", nil, nil, false, false, consentDecision{scopes: requested}},
+		{"no policy, prompt=consent", nil, nil, false, true, consentDecision{required: true, scopes: requested}},
+		{"screen off", policy(func(p *repository.ConsentPolicySettings) { p.ShowConsentScreen = false }), nil, false, false, consentDecision{scopes: requested}},
+		{"first party skip", policy(func(p *repository.ConsentPolicySettings) { p.AllowSkipConsentForFirstParty = true }), nil, true, false, consentDecision{scopes: requested}},
+		{"first party, prompt=consent", policy(func(p *repository.ConsentPolicySettings) { p.AllowSkipConsentForFirstParty = true }), nil, true, true, consentDecision{required: true, scopes: requested}},
+		{"no prior consent", policy(nil), nil, false, false, consentDecision{required: true, scopes: requested}},
+		{"covered", policy(nil), granted("openid", "profile", "email", "phone"), false, false, consentDecision{scopes: requested}},
+		{"new scope", policy(nil), granted("openid", "profile"), false, false, consentDecision{required: true, scopes: requested}},
+		{"revoked", policy(nil), &repository.Consent{Scopes: requested, GrantedAt: now, RevokedAt: &now}, false, false, consentDecision{required: true, scopes: requested}},
+		{"expired", policy(func(p *repository.ConsentPolicySettings) { p.ExpirationDays = intPtr(1) }),
+			&repository.Consent{Scopes: requested, GrantedAt: now.AddDate(0, 0, -2)}, false, false, consentDecision{required: true, scopes: requested}},
+		{"reprompt due", policy(func(p *repository.ConsentPolicySettings) { p.RepromptDays = intPtr(7) }),
+			&repository.Consent{Scopes: requested, GrantedAt: now.AddDate(0, 0, -30), UpdatedAt: now.AddDate(0, 0, -8)}, false, false, consentDecision{required: true, scopes: requested}},
+		{"reprompt not due", policy(func(p *repository.ConsentPolicySettings) { p.RepromptDays = intPtr(7) }),
+			&repository.Consent{Scopes: requested, GrantedAt: now.AddDate(0, 0, -30), UpdatedAt: now.AddDate(0, 0, -1)}, false, false, consentDecision{scopes: requested}},
+		{"remembered denial", policy(func(p *repository.ConsentPolicySettings) {
+			p.ConsentMode = repository.ConsentModePerScope
+			p.RememberScopeDecisions = true
+		}), &repository.Consent{Scopes: []string{"openid", "profile"}, DeniedScopes: []string{"email"}, GrantedAt: now},
+			false, false, consentDecision{scopes: []string{"openid", "profile"}, denied: []string{"email"}}},
+		{"denial not remembered in single mode", policy(func(p *repository.ConsentPolicySettings) { p.RememberScopeDecisions = true }),
+			&repository.Consent{Scopes: []string{"openid", "profile"}, DeniedScopes: []string{"email"}, GrantedAt: now},
+			false, false, consentDecision{required: true, scopes: requested}},
+	}
+	for _, tt := range tests {
+		tda := &consentTDA{
+			settings: repository.TenantSettings{ConsentPolicy: tt.policy},
+			consents: &storedConsents{consent: tt.prior},
+		}
+		s := &authorizeService{dal: tenantDAL{tda: tda}}
+		client := &repository.Client{ClientID: "client-a", FirstParty: tt.firstParty}
+
+		got := s.evaluateConsent(context.Background(), client, "acme", "user-1", requested, tt.explicit)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitScopeDecision(t *testing.T) {
+	requested := []string{"openid", "profile", "email"}
+
+	granted, denied := splitScopeDecision(requested, []string{"email"})
+	if !reflect.DeepEqual(granted, []string{"openid", "email"}) || !reflect.DeepEqual(denied, []string{"profile"}) {
+		t.Errorf("granted=%v denied=%v", granted, denied)
+	}
+	if granted, denied := splitScopeDecision(requested, nil); !reflect.DeepEqual(granted, requested) || denied != nil {
+		t.Errorf("empty selection: granted=%v denied=%v", granted, denied)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
@@ -36,19 +38,22 @@ type ConsentDeps struct {
 	DAL          store.DataAccessLayer
 	Cache        CacheClient
 	ControlPlane controlplane.Service
+	Issuer       *jwtx.Issuer // signs JARM responses
 }
 
 type consentService struct {
-	dal   store.DataAccessLayer
-	cache CacheClient
-	cp    controlplane.Service
+	dal    store.DataAccessLayer
+	cache  CacheClient
+	cp     controlplane.Service
+	issuer *jwtx.Issuer
 }
 
 func NewConsentService(d ConsentDeps) ConsentService {
 	return &consentService{
-		dal:   d.DAL,
-		cache: d.Cache,
-		cp:    d.ControlPlane,
+		dal:    d.DAL,
+		cache:  d.Cache,
+		cp:     d.ControlPlane,
+		issuer: d.Issuer,
 	}
 }
 
@@ -80,13 +85,17 @@ func (s *consentService) Accept(ctx context.Context, req dto.ConsentAcceptReques
 		return nil, ErrConsentNotFound
 	}
 
+	// Device flow (RFC 8628): the decision is recorded on the device authorization
+	if payload.DeviceCodeHash != "" {
+		return s.acceptDevice(ctx, payload, req.Approve, req.GrantedScopes)
+	}
+
 	// 3. Handle Rejection
 	if !req.Approve {
-		loc := buildRedirect(payload.RedirectURI, map[string]string{
+		return s.respond(ctx, payload, map[string]string{
 			"error": "access_denied",
 			"state": payload.State,
 		})
-		return &dto.AuthCodeRedirect{URL: loc}, nil
 	}
 
 	// 4. Handle Approval
@@ -98,8 +107,8 @@ func (s *consentService) Accept(ctx context.Context, req dto.ConsentAcceptReques
 		return nil, ErrConsentStoreFailed
 	}
 
-	// Persist Consent using repository
-	_, err = tda.Consents().Upsert(ctx, payload.TenantID, payload.UserID, payload.ClientID, payload.RequestedScopes)
+	// Persist the decision (per-scope when the tenant policy allows it)
+	granted, err := s.recordDecision(ctx, tda, payload, req.GrantedScopes)
 	if err != nil {
 		log.Error("failed to upsert consent", logger.Err(err))
 		return nil, ErrConsentStoreFailed
@@ -117,12 +126,17 @@ func (s *consentService) Accept(ctx context.Context, req dto.ConsentAcceptReques
 		ClientID:        payload.ClientID,
 		TenantID:        payload.TenantID,
 		RedirectURI:     payload.RedirectURI,
-		Scope:           strings.Join(payload.RequestedScopes, " "),
+		Scope:           strings.Join(granted, " "),
 		Nonce:           payload.Nonce,
 		CodeChallenge:   payload.CodeChallenge,
 		ChallengeMethod: payload.CodeChallengeMethod,
 		AMR:             payload.AMR,
+		AuthTime:        payload.AuthTime,
+		SessionID:       payload.SessionID,
 		ExpiresAt:       time.Now().Add(10 * time.Minute), // Match V2 TTL
+		Resource:        payload.Resource,
+
+		AuthorizationDetails: payload.AuthorizationDetails,
 	}
 
 	authBytes, _ := json.Marshal(authPayload)
@@ -133,14 +147,98 @@ func (s *consentService) Accept(ctx context.Context, req dto.ConsentAcceptReques
 	s.cache.Set("code:"+codeHash, authBytes, 10*time.Minute)
 
 	// 6. Return Redirect
-	loc := buildRedirect(payload.RedirectURI, map[string]string{
+	return s.respond(ctx, payload, map[string]string{
 		"code":  code,
 		"state": payload.State,
 	})
+}
+
+// respond builds the authorization response in the response_mode of the original
+// request. Plain query responses keep the ready-made URL.
+func (s *consentService) respond(ctx context.Context, payload dto.ConsentChallenge, params map[string]string) (*dto.AuthCodeRedirect, error) {
+	if payload.ResponseMode == "" || payload.ResponseMode == ResponseModeQuery {
+		return &dto.AuthCodeRedirect{URL: buildRedirect(payload.RedirectURI, params)}, nil
+	}
 
+	var client *repository.Client
+	if s.cp != nil {
+		client, _ = s.cp.GetClient(ctx, payload.TenantID, payload.ClientID)
+	}
+	delivery, response, err := signAuthorizationResponse(ctx, s.issuer, s.cp, client, payload.TenantID, payload.ResponseMode, params)
+	if err != nil {
+		logger.From(ctx).Error("authorization response signing failed", logger.Err(err))
+		return nil, ErrConsentCodeFailed
+	}
+	if response != "" {
+		params = map[string]string{"response": response}
+	}
+
+	res := &dto.AuthCodeRedirect{RedirectURI: payload.RedirectURI, ResponseMode: delivery, Params: params}
+	if delivery == ResponseModeQuery {
+		res.URL = buildRedirect(payload.RedirectURI, params)
+	}
+	return res, nil
+}
+
+// acceptDevice records the user's decision for a pending device authorization.
+// The device picks up the result on its next poll of the token endpoint.
+func (s *consentService) acceptDevice(ctx context.Context, payload dto.ConsentChallenge, approve bool, selected []string) (*dto.AuthCodeRedirect, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.consent.acceptDevice"))
+
+	dev, ok := loadDeviceCode(s.cache, payload.DeviceCodeHash)
+	if !ok || dev.Status != deviceStatusPending || dev.ClientID != payload.ClientID {
+		return nil, ErrConsentNotFound
+	}
+
+	if !approve {
+		dev.Status = deviceStatusDenied
+		storeDeviceCode(s.cache, payload.DeviceCodeHash, dev)
+		loc := buildRedirect(payload.RedirectURI, map[string]string{"error": "access_denied"})
+		return &dto.AuthCodeRedirect{URL: loc}, nil
+	}
+
+	tda, err := s.dal.ForTenant(ctx, payload.TenantID)
+	if err != nil {
+		log.Error("failed to resolve tenant for consent", logger.Err(err), logger.String("tid", payload.TenantID))
+		return nil, ErrConsentStoreFailed
+	}
+	granted, err := s.recordDecision(ctx, tda, payload, selected)
+	if err != nil {
+		log.Error("failed to upsert consent", logger.Err(err))
+		return nil, ErrConsentStoreFailed
+	}
+
+	dev.Status = deviceStatusApproved
+	dev.Scope = strings.Join(granted, " ")
+	dev.UserID = payload.UserID
+	dev.AMR = payload.AMR
+	dev.AuthTime = payload.AuthTime
+	storeDeviceCode(s.cache, payload.DeviceCodeHash, dev)
+
+	log.Info("device authorization approved", logger.UserID(payload.UserID), logger.ClientID(payload.ClientID))
+
+	loc := buildRedirect(payload.RedirectURI, map[string]string{"status": "approved"})
 	return &dto.AuthCodeRedirect{URL: loc}, nil
 }
 
+// recordDecision stores the user's consent and returns the granted scopes.
+// In per_scope mode the user may deselect scopes (never openid); the denied
+// ones are remembered only when the policy has remember_scope_decisions.
+func (s *consentService) recordDecision(ctx context.Context, tda store.TenantDataAccess, payload dto.ConsentChallenge, selected []string) ([]string, error) {
+	policy := tda.Settings().ConsentPolicy
+	granted, denied := payload.RequestedScopes, []string(nil)
+	if consentMode(policy) == repository.ConsentModePerScope {
+		granted, denied = splitScopeDecision(payload.RequestedScopes, selected)
+		if !policy.RememberScopeDecisions {
+			denied = nil
+		}
+	}
+	if _, err := tda.Consents().UpsertDecision(ctx, payload.TenantID, payload.UserID, payload.ClientID, granted, denied, payload.AuthorizationDetails); err != nil {
+		return nil, err
+	}
+	return granted, nil
+}
+
 // GetInfo retrieves consent info with scope DisplayNames for consent screen.
 // ISS-05-03: DisplayName in Consent Screen
 func (s *consentService) GetInfo(ctx context.Context, token string) (*dto.ConsentInfoResponse, error) {
@@ -185,6 +283,8 @@ func (s *consentService) GetInfo(ctx context.Context, token string) (*dto.Consen
 			scopeDetails = append(scopeDetails, dto.ScopeDetail{
 				Name:        scopeName,
 				DisplayName: scopeName,
+				Required:    scopeName == "openid",
+				Denied:      containsString(payload.DeniedScopes, scopeName),
 			})
 			continue
 		}
@@ -193,6 +293,8 @@ func (s *consentService) GetInfo(ctx context.Context, token string) (*dto.Consen
 			Name:        scope.Name,
 			DisplayName: scope.DisplayName,
 			Description: scope.Description,
+			Required:    scope.Name == "openid",
+			Denied:      containsString(payload.DeniedScopes, scope.Name),
 		}
 
 		// Fallback: if no display_name, use name
@@ -212,11 +314,26 @@ func (s *consentService) GetInfo(ctx context.Context, token string) (*dto.Consen
 		}
 	}
 
+	// 6. Authorization details (RFC 9396) with the description of their type
+	settings := tda.Settings()
+	var details []dto.AuthorizationDetailInfo
+	for _, d := range payload.AuthorizationDetails {
+		info := dto.AuthorizationDetailInfo{Detail: d}
+		info.Type, _ = d["type"].(string)
+		if typ := settings.FindAuthorizationDetailsType(info.Type); typ != nil {
+			info.Description = typ.Description
+		}
+		details = append(details, info)
+	}
+
 	return &dto.ConsentInfoResponse{
 		ClientID:    payload.ClientID,
 		ClientName:  clientName,
 		Scopes:      scopeDetails,
 		RedirectURI: payload.RedirectURI,
+		ConsentMode: consentMode(settings.ConsentPolicy),
+
+		AuthorizationDetails: details,
 	}, nil
 }
 
@@ -0,0 +1,356 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// GrantTypeDeviceCode is the grant_type for device code polling (RFC 8628 §3.4).
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Cache key prefixes for the device flow
+const (
+	cacheKeyPrefixDeviceCode = "device:code:" // + sha256(device_code) → DeviceCodePayload
+	cacheKeyPrefixDeviceUser = "device:user:" // + normalized user_code → sha256(device_code)
+)
+
+// Device flow settings
+const (
+	deviceCodeTTL        = 10 * time.Minute
+	devicePollInterval   = 5 // seconds
+	deviceSlowDownStep   = 5 // seconds added on each slow_down
+	deviceConsentTTL     = 5 * time.Minute
+	userCodeAlphabet     = "BCDFGHJKLMNPQRSTVWXZ" // no vowels: avoids words, no 0/O or 1/I confusion
+	userCodeLength       = 8
+	deviceStatusPending  = "pending"
+	deviceStatusApproved = "approved"
+	deviceStatusDenied   = "denied"
+)
+
+// DeviceCodePayload is the cached state of a device authorization.
+// Shared by the device_authorization endpoint, the verification flow (via consent)
+// and the token endpoint polling.
+type DeviceCodePayload struct {
+	ClientID     string    `json:"client_id"`
+	TenantID     string    `json:"tenant_id"` // tenant slug
+	Scope        string    `json:"scope"`
+	UserCode     string    `json:"user_code"`
+	Status       string    `json:"status"` // pending | approved | denied
+	UserID       string    `json:"user_id,omitempty"`
+	AMR          []string  `json:"amr,omitempty"`
+	AuthTime     int64     `json:"auth_time,omitempty"` // unix time the approving session authenticated
+	Interval     int       `json:"interval"`            // seconds
+	LastPolledAt time.Time `json:"last_polled_at,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// DeviceService handles the Device Authorization Grant (RFC 8628).
+type DeviceService interface {
+	// Authorize handles POST /oauth2/device_authorization.
+	Authorize(ctx context.Context, req dto.DeviceAuthorizationRequest) (*dto.DeviceAuthorizationResponse, error)
+	// Verify handles GET /oauth2/device (browser side, where the user enters the user_code).
+	Verify(ctx context.Context, r *http.Request, userCode string) (dto.DeviceVerifyResult, error)
+}
+
+// DeviceDeps contains dependencies for DeviceService.
+type DeviceDeps struct {
+	ControlPlane controlplane.Service
+	Cache        CacheClient
+	Issuer       *jwtx.Issuer
+	CookieName   string
+	UIBaseURL    string // Default: UI_BASE_URL or "http://localhost:3000"
+	ClientAuth   ClientAuthService
+	ClientCAs    *x509.CertPool // roots for tls_client_auth (nil = trust the TLS terminator)
+}
+
+type deviceService struct {
+	cp         controlplane.Service
+	cache      CacheClient
+	issuer     *jwtx.Issuer
+	cookieName string
+	uiBaseURL  string
+	clientAuth ClientAuthService
+	clientCAs  *x509.CertPool
+}
+
+// NewDeviceService creates a new DeviceService.
+func NewDeviceService(d DeviceDeps) DeviceService {
+	uiBase := d.UIBaseURL
+	if uiBase == "" {
+		uiBase = os.Getenv("UI_BASE_URL")
+		if uiBase == "" {
+			uiBase = "http://localhost:3000"
+		}
+	}
+	cookieName := d.CookieName
+	if cookieName == "" {
+		cookieName = "sid"
+	}
+	return &deviceService{
+		cp:         d.ControlPlane,
+		cache:      d.Cache,
+		issuer:     d.Issuer,
+		cookieName: cookieName,
+		uiBaseURL:  strings.TrimRight(uiBase, "/"),
+		clientAuth: d.ClientAuth,
+		clientCAs:  d.ClientCAs,
+	}
+}
+
+// Authorize issues a device_code/user_code pair for the client.
+func (s *deviceService) Authorize(ctx context.Context, req dto.DeviceAuthorizationRequest) (*dto.DeviceAuthorizationResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.device.authorize"))
+
+	assertion := ClientAssertion{Type: req.ClientAssertionType, Value: req.ClientAssertion}
+	if req.ClientID == "" && !assertion.Present() {
+		return nil, ErrTokenInvalidRequest
+	}
+
+	// Same client authentication methods as the token endpoint
+	client, tenantSlug, err := authenticateRequestClient(ctx, s.cp, s.clientAuth, s.clientCAs, req.TenantSlug, req.ClientID, req.ClientSecret, assertion, req.ClientCert)
+	if err != nil {
+		log.Warn("client authentication failed", logger.String("client_id", req.ClientID), logger.Err(err))
+		return nil, ErrTokenInvalidClient
+	}
+
+	// Device flow must be explicitly enabled for the client
+	if !hasGrantType(client, GrantTypeDeviceCode) {
+		log.Warn("grant_type not allowed for client", logger.String("grant_type", GrantTypeDeviceCode))
+		return nil, ErrTokenUnauthorizedClient
+	}
+
+	scope := strings.Join(strings.Fields(req.Scope), " ")
+	if scope == "" {
+		scope = strings.Join(client.Scopes, " ")
+	}
+	for _, sc := range strings.Fields(scope) {
+		if !s.cp.IsScopeAllowed(client, sc) {
+			log.Warn("scope not allowed", logger.String("scope", sc))
+			return nil, ErrTokenInvalidScope
+		}
+	}
+
+	deviceCode, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		log.Error("device_code generation failed", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		log.Error("user_code generation failed", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+
+	payload := DeviceCodePayload{
+		ClientID:  client.ClientID,
+		TenantID:  tenantSlug,
+		Scope:     scope,
+		UserCode:  userCode,
+		Status:    deviceStatusPending,
+		Interval:  devicePollInterval,
+		ExpiresAt: time.Now().Add(deviceCodeTTL),
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	deviceHash := tokens.SHA256Base64URL(deviceCode)
+	s.cache.Set(cacheKeyPrefixDeviceCode+deviceHash, payloadBytes, deviceCodeTTL)
+	s.cache.Set(cacheKeyPrefixDeviceUser+normalizeUserCode(userCode), []byte(deviceHash), deviceCodeTTL)
+
+	verificationURI := s.verificationURI()
+
+	log.Info("device authorization issued", logger.TenantSlug(tenantSlug), logger.ClientID(client.ClientID))
+
+	return &dto.DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(userCode),
+		ExpiresIn:               int64(deviceCodeTTL.Seconds()),
+		Interval:                devicePollInterval,
+	}, nil
+}
+
+// Verify resolves the user_code entered in the browser. It reuses the session
+// cookie for authentication and hands off to the consent screen.
+func (s *deviceService) Verify(ctx context.Context, r *http.Request, userCode string) (dto.DeviceVerifyResult, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.device.verify"))
+
+	code := normalizeUserCode(userCode)
+	if code == "" {
+		return dto.DeviceVerifyResult{RedirectURL: s.uiBaseURL + "/device"}, nil
+	}
+
+	invalid := dto.DeviceVerifyResult{
+		RedirectURL: buildRedirect(s.uiBaseURL+"/device", map[string]string{"error": "invalid_user_code"}),
+	}
+
+	hashBytes, ok := s.cache.Get(cacheKeyPrefixDeviceUser + code)
+	if !ok {
+		log.Debug("user_code not found")
+		return invalid, nil
+	}
+	deviceHash := string(hashBytes)
+
+	payload, ok := loadDeviceCode(s.cache, deviceHash)
+	if !ok || payload.Status != deviceStatusPending || !time.Now().Before(payload.ExpiresAt) {
+		log.Debug("device authorization expired or already decided")
+		return invalid, nil
+	}
+
+	// Authenticate via session cookie (must belong to the client's tenant)
+	sess, ok := s.sessionUser(r, payload.TenantID)
+	if !ok {
+		loginURL := s.uiBaseURL + "/login?return_to=" + url.QueryEscape(absoluteRequestURL(r))
+		return dto.DeviceVerifyResult{RedirectURL: loginURL}, nil
+	}
+
+	// Hand off to the consent screen; ConsentService.Accept resolves the device code
+	consentToken, err := tokens.GenerateOpaqueToken(24)
+	if err != nil {
+		log.Error("consent token generation failed", logger.Err(err))
+		return dto.DeviceVerifyResult{}, ErrCodeGenFailed
+	}
+
+	expiresAt := time.Now().Add(deviceConsentTTL)
+	if payload.ExpiresAt.Before(expiresAt) {
+		expiresAt = payload.ExpiresAt
+	}
+	// The device tokens reflect how the approving browser session authenticated (amr → acr)
+	amr := sess.AMR
+	if len(amr) == 0 {
+		amr = []string{"pwd"}
+	}
+	var authTime int64
+	if !sess.AuthTime.IsZero() {
+		authTime = sess.AuthTime.Unix()
+	}
+	challenge := dto.ConsentChallenge{
+		UserID:          sess.UserID,
+		ClientID:        payload.ClientID,
+		TenantID:        payload.TenantID,
+		RedirectURI:     s.uiBaseURL + "/device",
+		RequestedScopes: strings.Fields(payload.Scope),
+		AMR:             amr,
+		AuthTime:        authTime,
+		ExpiresAt:       expiresAt,
+		DeviceCodeHash:  deviceHash,
+	}
+	challengeBytes, _ := json.Marshal(challenge)
+	s.cache.Set("consent:token:"+consentToken, challengeBytes, time.Until(expiresAt))
+
+	log.Debug("device verification handed to consent", logger.UserID(sess.UserID), logger.ClientID(payload.ClientID))
+
+	return dto.DeviceVerifyResult{
+		RedirectURL: s.uiBaseURL + "/consent?consent_token=" + url.QueryEscape(consentToken),
+	}, nil
+}
+
+// sessionUser returns the session behind the cookie if it belongs to tenantSlug.
+func (s *deviceService) sessionUser(r *http.Request, tenantSlug string) (dto.SessionPayload, bool) {
+	var sp dto.SessionPayload
+	ck, err := r.Cookie(s.cookieName)
+	if err != nil || ck == nil || strings.TrimSpace(ck.Value) == "" {
+		return sp, false
+	}
+	b, ok := s.cache.Get(cacheKeyPrefixSID + tokens.SHA256Base64URL(ck.Value))
+	if !ok {
+		return sp, false
+	}
+	if json.Unmarshal(b, &sp) != nil {
+		return sp, false
+	}
+	if !time.Now().Before(sp.Expires) || !strings.EqualFold(sp.TenantID, tenantSlug) || sp.UserID == "" {
+		return sp, false
+	}
+	return sp, true
+}
+
+func (s *deviceService) verificationURI() string {
+	base := ""
+	if s.issuer != nil {
+		base = strings.TrimRight(s.issuer.Iss, "/")
+	}
+	return base + "/oauth2/device"
+}
+
+// loadDeviceCode reads the device authorization state by device_code hash.
+func loadDeviceCode(c CacheClient, deviceHash string) (*DeviceCodePayload, bool) {
+	raw, ok := c.Get(cacheKeyPrefixDeviceCode + deviceHash)
+	if !ok {
+		return nil, false
+	}
+	var p DeviceCodePayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, false
+	}
+	return &p, true
+}
+
+// storeDeviceCode writes back the device authorization state keeping its original expiry.
+func storeDeviceCode(c CacheClient, deviceHash string, p *DeviceCodePayload) {
+	ttl := time.Until(p.ExpiresAt)
+	if ttl <= 0 {
+		c.Delete(cacheKeyPrefixDeviceCode + deviceHash)
+		return
+	}
+	b, _ := json.Marshal(p)
+	c.Set(cacheKeyPrefixDeviceCode+deviceHash, b, ttl)
+}
+
+// generateUserCode returns a user_code formatted as XXXX-XXXX.
+func generateUserCode() (string, error) {
+	// Rejection sampling keeps the distribution uniform over the alphabet
+	limit := byte(256 - 256%len(userCodeAlphabet))
+	out := make([]byte, 0, userCodeLength+1)
+	buf := make([]byte, 1)
+	for n := 0; n < userCodeLength; {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		if buf[0] >= limit {
+			continue
+		}
+		if n == userCodeLength/2 {
+			out = append(out, '-')
+		}
+		out = append(out, userCodeAlphabet[int(buf[0])%len(userCodeAlphabet)])
+		n++
+	}
+	return string(out), nil
+}
+
+// normalizeUserCode uppercases and strips separators so "bcdf-ghjk" matches "BCDFGHJK".
+func normalizeUserCode(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// hasGrantType reports whether the grant is explicitly listed in the client's grant_types.
+// Unlike isGrantTypeAllowed, an empty list does not enable the grant.
+func hasGrantType(client *repository.Client, grantType string) bool {
+	for _, g := range client.GrantTypes {
+		if strings.EqualFold(g, grantType) {
+			return true
+		}
+	}
+	return false
+}
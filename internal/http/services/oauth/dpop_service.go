@@ -0,0 +1,98 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// TokenTypeDPoP is the token_type of sender-constrained access tokens (RFC 9449 §5).
+const TokenTypeDPoP = "DPoP"
+
+const (
+	cacheKeyPrefixDPoPNonce = "dpop:nonce:"
+	dpopNonceTTL            = 5 * time.Minute
+)
+
+// DPoP errors (RFC 9449 §12.2)
+var (
+	ErrTokenInvalidDPoPProof = errors.New("invalid_dpop_proof")
+	ErrTokenUseDPoPNonce     = errors.New("use_dpop_nonce")
+)
+
+// DPoPService validates DPoP proofs presented at the token endpoint (RFC 9449).
+type DPoPService interface {
+	// VerifyProof validates the DPoP header for the given request method and URL
+	// and returns the JWK thumbprint the issued tokens must be bound to.
+	VerifyProof(ctx context.Context, proof, method, url string) (string, error)
+
+	// Nonce returns a fresh server nonce for the DPoP-Nonce response header.
+	Nonce() string
+}
+
+// DPoPDeps contains dependencies for DPoPService.
+type DPoPDeps struct {
+	Cache        CacheClient
+	RequireNonce bool // require a server-issued nonce in every proof
+}
+
+type dpopService struct {
+	cache        CacheClient
+	requireNonce bool
+}
+
+// NewDPoPService creates a new DPoPService.
+func NewDPoPService(d DPoPDeps) DPoPService {
+	return &dpopService{
+		cache:        d.Cache,
+		requireNonce: d.RequireNonce,
+	}
+}
+
+func (s *dpopService) VerifyProof(ctx context.Context, proof, method, url string) (string, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.dpop.verify"))
+
+	p, err := jwtx.VerifyDPoPProof(proof, jwtx.DPoPVerifyOptions{
+		Method: method,
+		URL:    url,
+		Replay: s.cache,
+	})
+	if err != nil {
+		log.Debug("invalid dpop proof", logger.Err(err))
+		return "", ErrTokenInvalidDPoPProof
+	}
+
+	// A nonce, when sent, must be one we issued; it's mandatory only if configured
+	if p.Nonce == "" {
+		if s.requireNonce {
+			return "", ErrTokenUseDPoPNonce
+		}
+		return p.JKT, nil
+	}
+	if _, ok := s.cache.Get(cacheKeyPrefixDPoPNonce + p.Nonce); !ok {
+		return "", ErrTokenUseDPoPNonce
+	}
+	return p.JKT, nil
+}
+
+func (s *dpopService) Nonce() string {
+	n, err := tokens.GenerateOpaqueToken(16)
+	if err != nil {
+		return ""
+	}
+	s.cache.Set(cacheKeyPrefixDPoPNonce+n, []byte("1"), dpopNonceTTL)
+	return n
+}
+
+// bindDPoP adds the cnf.jkt confirmation claim (RFC 9449 §6.1) and returns the token_type.
+func bindDPoP(std map[string]any, jkt string) string {
+	if jkt == "" {
+		return "Bearer"
+	}
+	addConfirmation(std, "jkt", jkt)
+	return TokenTypeDPoP
+}
@@ -0,0 +1,377 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// Errors for end_session flow
+var (
+	ErrEndSessionInvalidHint     = errors.New("invalid id_token_hint")
+	ErrEndSessionClientMismatch  = errors.New("client_id does not match id_token_hint")
+	ErrEndSessionClientRequired  = errors.New("client_id or id_token_hint required with post_logout_redirect_uri")
+	ErrEndSessionInvalidRedirect = errors.New("post_logout_redirect_uri not registered")
+	ErrEndSessionInvalidConfirm  = errors.New("invalid or expired logout confirmation")
+)
+
+const (
+	// cacheKeyPrefixLogoutConfirm maps a confirmation token to the session it may end.
+	cacheKeyPrefixLogoutConfirm = "logout:confirm:"
+	logoutConfirmTTL            = 5 * time.Minute
+)
+
+// EndSessionService handles OIDC RP-Initiated Logout.
+type EndSessionService interface {
+	EndSession(ctx context.Context, r *http.Request, req dto.EndSessionRequest) (dto.EndSessionResult, error)
+}
+
+// EndSessionDeps contains dependencies for EndSessionService.
+type EndSessionDeps struct {
+	DAL          store.DataAccessLayer
+	ControlPlane controlplane.Service
+	Cache        CacheClient
+	Issuer       *jwtx.Issuer
+	CookieName   string
+	Logout       logout.Notifier // optional: back/front-channel logout
+}
+
+type endSessionService struct {
+	dal        store.DataAccessLayer
+	cp         controlplane.Service
+	cache      CacheClient
+	issuer     *jwtx.Issuer
+	cookieName string
+	logout     logout.Notifier
+}
+
+// NewEndSessionService creates a new EndSessionService.
+func NewEndSessionService(d EndSessionDeps) EndSessionService {
+	cookieName := d.CookieName
+	if cookieName == "" {
+		cookieName = "sid"
+	}
+	return &endSessionService{
+		dal:        d.DAL,
+		cp:         d.ControlPlane,
+		cache:      d.Cache,
+		issuer:     d.Issuer,
+		cookieName: cookieName,
+		logout:     d.Logout,
+	}
+}
+
+// idTokenHint holds the claims we use from a validated id_token_hint.
+type idTokenHint struct {
+	Sub      string
+	TenantID string
+	Audience []string
+}
+
+// EndSession validates the logout request, terminates the browser session and
+// revokes the refresh tokens the user holds for the client.
+func (s *endSessionService) EndSession(ctx context.Context, r *http.Request, req dto.EndSessionRequest) (dto.EndSessionResult, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("EndSessionService.EndSession"))
+
+	// 1. Validate id_token_hint (signature + issuer, expiry is ignored per spec)
+	var hint *idTokenHint
+	if req.IDTokenHint != "" {
+		h, err := verifyIDTokenHint(ctx, s.issuer, s.cp, req.IDTokenHint)
+		if err != nil {
+			log.Debug("id_token_hint rejected", logger.Err(err))
+			return dto.EndSessionResult{}, ErrEndSessionInvalidHint
+		}
+		hint = h
+	}
+
+	// 2. Resolve the client (client_id param wins, must be an audience of the hint)
+	clientID := req.ClientID
+	if hint != nil {
+		if clientID == "" && len(hint.Audience) == 1 {
+			clientID = hint.Audience[0]
+		}
+		if clientID != "" && !containsString(hint.Audience, clientID) {
+			return dto.EndSessionResult{}, ErrEndSessionClientMismatch
+		}
+	}
+
+	tenantSlug := ""
+	if hint != nil {
+		tenantSlug = hint.TenantID
+	}
+
+	var client *repository.Client
+	if clientID != "" {
+		c, slug, err := s.lookupClient(ctx, tenantSlug, clientID)
+		if err != nil {
+			log.Debug("client not found", logger.ClientID(clientID), logger.Err(err))
+		} else {
+			client, tenantSlug = c, slug
+		}
+	}
+
+	// 3. Validate post_logout_redirect_uri against the client's registration
+	redirectURI := ""
+	if req.PostLogoutRedirectURI != "" {
+		if client == nil {
+			return dto.EndSessionResult{}, ErrEndSessionClientRequired
+		}
+		if !containsString(client.PostLogoutURIs, req.PostLogoutRedirectURI) {
+			log.Debug("post_logout_redirect_uri not allowed", logger.ClientID(client.ClientID))
+			return dto.EndSessionResult{}, ErrEndSessionInvalidRedirect
+		}
+		redirectURI = req.PostLogoutRedirectURI
+	}
+
+	result := dto.EndSessionResult{RedirectURI: redirectURI}
+	if redirectURI != "" {
+		result.State = req.State
+	}
+
+	// 4. Without id_token_hint the cookie alone is not proof the user wants to log out
+	// (any site can link here): ask the user to confirm first (RP-Initiated Logout §2).
+	ck, err := r.Cookie(s.cookieName)
+	hasSession := err == nil && ck != nil && strings.TrimSpace(ck.Value) != ""
+	if hint == nil && hasSession {
+		sidHash := tokens.SHA256Base64URL(ck.Value)
+		if req.Confirm == "" {
+			token, err := s.newLogoutConfirmation(sidHash)
+			if err != nil {
+				return dto.EndSessionResult{}, err
+			}
+			result.ConfirmToken = token
+			return result, nil
+		}
+		if r.Method != http.MethodPost || !s.consumeLogoutConfirmation(req.Confirm, sidHash) {
+			return dto.EndSessionResult{}, ErrEndSessionInvalidConfirm
+		}
+	}
+
+	// 5. Terminate browser session (only if it belongs to the hinted user)
+	userID := ""
+	if hint != nil {
+		userID = hint.Sub
+	}
+	if hasSession {
+		sessUser, sessTenant, sid, ended := s.endBrowserSession(ctx, ck.Value, userID)
+		if ended {
+			result.ClearCookie = s.cookieName
+		}
+		if userID == "" {
+			userID = sessUser
+		}
+		if tenantSlug == "" {
+			tenantSlug = sessTenant
+		}
+
+		// Tell the other relying parties of the session (back + front channel)
+		if s.logout != nil && sid != "" {
+			s.logout.Backchannel(ctx, sessTenant, sessUser, sid)
+			result.FrontchannelLogoutURIs = s.logout.FrontchannelURIs(ctx, sessTenant, sid)
+		}
+	}
+
+	// 6. Revoke refresh tokens issued to this client for the user
+	if client != nil && userID != "" {
+		s.revokeClientTokens(ctx, tenantSlug, userID, client.ClientID)
+	}
+
+	log.Info("rp-initiated logout completed",
+		logger.UserID(userID),
+		logger.TenantSlug(tenantSlug),
+		logger.ClientID(clientID),
+		logger.Bool("redirect", redirectURI != ""),
+	)
+
+	return result, nil
+}
+
+// newLogoutConfirmation stores a single-use token bound to the browser session.
+func (s *endSessionService) newLogoutConfirmation(sidHash string) (string, error) {
+	token, err := tokens.GenerateOpaqueToken(24)
+	if err != nil {
+		return "", err
+	}
+	s.cache.Set(cacheKeyPrefixLogoutConfirm+tokens.SHA256Base64URL(token), []byte(sidHash), logoutConfirmTTL)
+	return token, nil
+}
+
+// consumeLogoutConfirmation reports whether token was issued for this session, deleting it.
+func (s *endSessionService) consumeLogoutConfirmation(token, sidHash string) bool {
+	key := cacheKeyPrefixLogoutConfirm + tokens.SHA256Base64URL(token)
+	b, ok := s.cache.Get(key)
+	if !ok {
+		return false
+	}
+	s.cache.Delete(key)
+	return subtle.ConstantTimeCompare(b, []byte(sidHash)) == 1
+}
+
+// verifyIDTokenHint verifies the signature and issuer of a previously issued ID token.
+// Expired tokens are accepted: the hint only identifies the user (end_session, /authorize).
+// Other tokens signed by the issuer (access tokens, logout tokens, signed userinfo...)
+// are rejected.
+func verifyIDTokenHint(ctx context.Context, issuer *jwtx.Issuer, cp controlplane.Service, raw string) (*idTokenHint, error) {
+	if issuer == nil {
+		return nil, errors.New("issuer not configured")
+	}
+
+	parser := jwtv5.NewParser(
+		jwtv5.WithValidMethods(jwtx.SigningAlgs),
+		jwtv5.WithoutClaimsValidation(),
+	)
+	tk, err := parser.Parse(raw, issuer.KeyfuncFromTokenClaims())
+	if err != nil || !tk.Valid {
+		return nil, ErrEndSessionInvalidHint
+	}
+	claims, ok := tk.Claims.(jwtv5.MapClaims)
+	if !ok || !isIDToken(tk.Header, claims) {
+		return nil, ErrEndSessionInvalidHint
+	}
+
+	h := &idTokenHint{}
+	h.Sub, _ = claims["sub"].(string)
+	h.TenantID, _ = claims["tid"].(string)
+	switch aud := claims["aud"].(type) {
+	case string:
+		h.Audience = []string{aud}
+	case []any:
+		for _, v := range aud {
+			if s, ok := v.(string); ok {
+				h.Audience = append(h.Audience, s)
+			}
+		}
+	}
+	if h.Sub == "" || len(h.Audience) == 0 {
+		return nil, ErrEndSessionInvalidHint
+	}
+
+	// Pairwise clients received a per-sector sub: map it back to the local user
+	local, err := localSubject(ctx, cp, h.TenantID, claims)
+	if err != nil {
+		return nil, ErrEndSessionInvalidHint
+	}
+	h.Sub = local
+
+	// Validate issuer against the tenant's effective issuer
+	iss, _ := claims["iss"].(string)
+	if h.TenantID != "" && cp != nil {
+		if ten, err := cp.GetTenant(ctx, h.TenantID); err == nil && ten != nil {
+			expected := jwtx.ResolveIssuer(issuer.Iss, string(ten.Settings.IssuerMode), ten.Slug, ten.Settings.IssuerOverride)
+			if iss != expected {
+				return nil, ErrEndSessionInvalidHint
+			}
+		}
+	}
+
+	return h, nil
+}
+
+// isIDToken reports whether a token signed by the issuer is an ID token. Every
+// ID token carries azp and at_hash and, unlike access and logout tokens, no jti;
+// the typ header is plain "JWT" (access tokens may use "at+jwt").
+func isIDToken(header map[string]any, claims jwtv5.MapClaims) bool {
+	if typ, _ := header["typ"].(string); typ != "" && !strings.EqualFold(typ, "JWT") {
+		return false
+	}
+	if _, ok := claims["jti"]; ok {
+		return false
+	}
+	azp, _ := claims["azp"].(string)
+	atHash, _ := claims["at_hash"].(string)
+	return azp != "" && atHash != ""
+}
+
+// endBrowserSession deletes the cached session behind the cookie and marks the
+// persisted session as revoked. Returns the session's user, tenant and sid (the
+// hash carried in the ID token sid claim); sid is empty if nothing was ended.
+// If expectedUser is set and the session belongs to someone else, it is left
+// intact and ended is false (the cookie must stay too).
+func (s *endSessionService) endBrowserSession(ctx context.Context, cookieValue, expectedUser string) (userID, tenantSlug, sid string, ended bool) {
+	log := logger.From(ctx)
+
+	sidHash := tokens.SHA256Base64URL(cookieValue)
+	key := cacheKeyPrefixSID + sidHash
+
+	if b, ok := s.cache.Get(key); ok {
+		var sp dto.SessionPayload
+		if json.Unmarshal(b, &sp) == nil {
+			userID, tenantSlug = sp.UserID, sp.TenantID
+		}
+	}
+
+	if expectedUser != "" && userID != "" && userID != expectedUser {
+		log.Warn("id_token_hint subject does not own the browser session, keeping session")
+		return "", "", "", false
+	}
+
+	s.cache.Delete(key)
+
+	// Best-effort: mark persisted session as revoked and cut the access tokens issued under it
+	if tenantSlug != "" && s.dal != nil {
+		if tda, err := s.dal.ForTenant(ctx, tenantSlug); err == nil {
+			if tda.RequireDB() == nil && tda.Sessions() != nil {
+				if err := tda.Sessions().Revoke(ctx, sidHash, "user", "rp_logout"); err != nil && !errors.Is(err, repository.ErrNotFound) {
+					log.Debug("failed to revoke persisted session", logger.Err(err))
+				}
+			}
+			if err := revocation.New(tda.Cache()).RevokeSession(ctx, sidHash); err != nil {
+				log.Debug("failed to denylist session access tokens", logger.Err(err))
+			}
+		}
+	}
+
+	if userID == "" {
+		return "", tenantSlug, "", true // unknown or expired session
+	}
+	return userID, tenantSlug, sidHash, true
+}
+
+// revokeClientTokens revokes all refresh tokens of the user for the given client.
+func (s *endSessionService) revokeClientTokens(ctx context.Context, tenantSlug, userID, clientID string) {
+	log := logger.From(ctx)
+
+	if tenantSlug == "" || s.dal == nil {
+		return
+	}
+	tda, err := s.dal.ForTenant(ctx, tenantSlug)
+	if err != nil || tda.RequireDB() != nil {
+		return
+	}
+	n, err := tda.Tokens().RevokeAllByUser(ctx, userID, clientID)
+	if err != nil {
+		log.Warn("failed to revoke refresh tokens on logout", logger.Err(err))
+		return
+	}
+	log.Debug("refresh tokens revoked on logout", logger.Count(n), logger.ClientID(clientID))
+}
+
+// lookupClient finds the client in the given tenant or, if not found, across all tenants.
+func (s *endSessionService) lookupClient(ctx context.Context, tenantSlug, clientID string) (*repository.Client, string, error) {
+	return findClient(ctx, s.cp, tenantSlug, clientID)
+}
+
+// containsString reports whether v is in list (exact match).
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -2,13 +2,17 @@ package oauth
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"strings"
 	"time"
 
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 	jwtv5 "github.com/golang-jwt/jwt/v5"
@@ -18,12 +22,21 @@ import (
 // IntrospectService defines operations for token introspection.
 type IntrospectService interface {
 	Introspect(ctx context.Context, token string, includeSys bool) (*dto.IntrospectResult, error)
+	// AuthenticateClient authenticates the requesting client with its registered
+	// method and returns its client_id. A signed response is addressed to that
+	// client (RFC 9701 §5), so public clients are rejected.
+	AuthenticateClient(ctx context.Context, tenantSlug, clientID, secret string, assertion ClientAssertion, cert *x509.Certificate) (string, error)
+	// SignResponse signs an introspection response for the requesting client (RFC 9701).
+	SignResponse(ctx context.Context, tenantSlug, audience string, resp map[string]any) (string, error)
 }
 
 // IntrospectDeps contains dependencies for the introspect service.
 type IntrospectDeps struct {
-	DAL    store.DataAccessLayer
-	Issuer *jwtx.Issuer
+	DAL          store.DataAccessLayer
+	Issuer       *jwtx.Issuer
+	ControlPlane controlplane.Service
+	ClientAuth   ClientAuthService
+	ClientCAs    *x509.CertPool // roots for tls_client_auth (nil = trust the TLS terminator)
 }
 
 type introspectService struct {
@@ -37,7 +50,8 @@ func NewIntrospectService(deps IntrospectDeps) IntrospectService {
 
 // Service errors
 var (
-	ErrIntrospectTokenEmpty = fmt.Errorf("token is empty")
+	ErrIntrospectTokenEmpty    = fmt.Errorf("token is empty")
+	ErrIntrospectInvalidClient = fmt.Errorf("client authentication failed")
 )
 
 // Introspect analyzes a token and returns its status and claims.
@@ -65,6 +79,27 @@ func (s *introspectService) Introspect(ctx context.Context, token string, includ
 	return s.introspectJWT(ctx, token, includeSys, log)
 }
 
+func (s *introspectService) AuthenticateClient(ctx context.Context, tenantSlug, clientID, secret string, assertion ClientAssertion, cert *x509.Certificate) (string, error) {
+	client, _, err := authenticateRequestClient(ctx, s.deps.ControlPlane, s.deps.ClientAuth, s.deps.ClientCAs, tenantSlug, clientID, secret, assertion, cert)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIntrospectInvalidClient, err)
+	}
+	if client.Type != repository.ClientTypeConfidential {
+		return "", fmt.Errorf("%w: public client", ErrIntrospectInvalidClient)
+	}
+	return client.ClientID, nil
+}
+
+// SignResponse wraps resp in the token_introspection claim of a JWT issued by
+// the tenant and addressed to the requesting client (RFC 9701 §5).
+func (s *introspectService) SignResponse(ctx context.Context, tenantSlug, audience string, resp map[string]any) (string, error) {
+	iss := s.deps.Issuer.Iss
+	if tenant, err := s.deps.DAL.ConfigAccess().Tenants().GetBySlug(ctx, tenantSlug); err == nil && tenant != nil {
+		iss = jwtx.ResolveIssuer(s.deps.Issuer.Iss, string(tenant.Settings.IssuerMode), tenant.Slug, tenant.Settings.IssuerOverride)
+	}
+	return s.deps.Issuer.IssueIntrospectionResponse(tenantSlug, iss, audience, resp, "")
+}
+
 // introspectRefreshToken handles opaque refresh token introspection.
 func (s *introspectService) introspectRefreshToken(ctx context.Context, token string, log *zap.Logger) (*dto.IntrospectResult, error) {
 	hash := tokens.SHA256Base64URL(token)
@@ -99,13 +134,27 @@ func (s *introspectService) introspectRefreshToken(ctx context.Context, token st
 			zap.String("user_id", rt.UserID),
 		)
 
+		// Same sub the client sees in its ID/access tokens (pairwise or public)
+		sub := rt.UserID
+		if rt.ClientID != "" {
+			if c, err := s.deps.DAL.ConfigAccess().Clients(t.Slug).Get(ctx, t.Slug, rt.ClientID); err == nil {
+				if sub, err = pairwise.SubjectFor(c, rt.UserID); err != nil {
+					log.Error("failed to derive pairwise subject", logger.Err(err))
+					return &dto.IntrospectResult{Active: false}, nil
+				}
+			}
+		}
+
 		return &dto.IntrospectResult{
 			Active:    active,
 			TokenType: "refresh_token",
-			Sub:       rt.UserID,
+			Sub:       sub,
 			ClientID:  rt.ClientID,
 			Exp:       rt.ExpiresAt.Unix(),
 			Iat:       rt.IssuedAt.Unix(),
+			DPoPJKT:   rt.DPoPJKT,
+
+			AuthorizationDetails: rt.AuthorizationDetails,
 		}, nil
 	}
 
@@ -117,7 +166,7 @@ func (s *introspectService) introspectRefreshToken(ctx context.Context, token st
 // introspectJWT handles JWT access token introspection.
 func (s *introspectService) introspectJWT(ctx context.Context, token string, includeSys bool, log *zap.Logger) (*dto.IntrospectResult, error) {
 	// Parse and validate JWT
-	parsed, err := jwtv5.Parse(token, s.deps.Issuer.KeyfuncFromTokenClaims(), jwtv5.WithValidMethods([]string{"EdDSA"}))
+	parsed, err := jwtv5.Parse(token, s.deps.Issuer.KeyfuncFromTokenClaims(), jwtv5.WithValidMethods(jwtx.SigningAlgs))
 	if err != nil || !parsed.Valid {
 		log.Debug("jwt parse failed", logger.Err(err))
 		return &dto.IntrospectResult{Active: false}, nil
@@ -132,7 +181,12 @@ func (s *introspectService) introspectJWT(ctx context.Context, token string, inc
 	expF, _ := claims["exp"].(float64)
 	iatF, _ := claims["iat"].(float64)
 	sub, _ := claims["sub"].(string)
-	clientID, _ := claims["aud"].(string)
+	// Tokens issued for API resources carry the client in client_id; aud names the APIs
+	aud := audienceList(claims["aud"])
+	clientID, _ := claims["client_id"].(string)
+	if clientID == "" && len(aud) == 1 {
+		clientID = aud[0]
+	}
 	tid, _ := claims["tid"].(string)
 	acr, _ := claims["acr"].(string)
 	iss, _ := claims["iss"].(string)
@@ -180,6 +234,12 @@ func (s *introspectService) introspectJWT(ctx context.Context, token string, inc
 		}
 	}
 
+	// Revoked through the denylist (jti, user, client, session or tenant)
+	if active && s.deps.Issuer.IsRevoked(ctx, claims) {
+		log.Debug("jwt revoked", zap.String("jti", jti))
+		active = false
+	}
+
 	result := &dto.IntrospectResult{
 		Active:    active,
 		TokenType: "access_token",
@@ -193,12 +253,22 @@ func (s *introspectService) introspectJWT(ctx context.Context, token string, inc
 		Tid:       tid,
 		Acr:       acr,
 		Amr:       amrVals,
+		DPoPJKT:   jwtx.ConfirmationJKT(claims),
+		X5TS256:   jwtx.ConfirmationX5T(claims),
+		Aud:       aud,
+
+		AuthorizationDetails: authorizationDetailsFromClaim(claims[authorizationDetailsClaim]),
 	}
 
 	// Extract system roles/perms if requested and token is active
 	if active && includeSys {
 		result.Roles, result.Perms = s.extractSystemClaims(claims, log)
 	}
+	// RFC 9068 tokens carry the user's roles and entitlements as top-level claims
+	if active && len(result.Roles) == 0 && len(result.Perms) == 0 {
+		result.Roles = audienceList(claims["roles"])
+		result.Perms = audienceList(claims["entitlements"])
+	}
 
 	log.Debug("jwt introspected",
 		zap.Bool("active", active),
@@ -208,6 +278,26 @@ func (s *introspectService) introspectJWT(ctx context.Context, token string, inc
 	return result, nil
 }
 
+// audienceList normalizes the aud claim (string or array); also used for
+// other string-or-array claims.
+func audienceList(v any) []string {
+	switch a := v.(type) {
+	case string:
+		if a != "" {
+			return []string{a}
+		}
+	case []any:
+		out := make([]string, 0, len(a))
+		for _, x := range a {
+			if s, ok := x.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
 // extractSystemClaims extracts roles and perms from the custom namespace.
 func (s *introspectService) extractSystemClaims(claims jwtv5.MapClaims, log *zap.Logger) ([]string, []string) {
 	custom, ok := claims["custom"].(map[string]any)
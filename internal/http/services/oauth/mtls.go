@@ -0,0 +1,84 @@
+package oauth
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"strings"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+)
+
+// ErrClientCertificateInvalid is returned when mutual-TLS client authentication fails.
+var ErrClientCertificateInvalid = errors.New("invalid client certificate")
+
+// requiresTLSClientAuth reports whether the client authenticates with mutual TLS (RFC 8705 §2).
+func requiresTLSClientAuth(client *repository.Client) bool {
+	switch client.TokenEndpointAuthMethod {
+	case repository.AuthMethodTLSClientAuth, repository.AuthMethodSelfSignedTLS:
+		return true
+	}
+	return false
+}
+
+// verifyClientCertificate checks the presented certificate against the client's registration.
+// tls_client_auth: the cert must chain to roots (when configured) and match the subject DN.
+// self_signed_tls_client_auth: the cert must match the registered thumbprint.
+// roots is nil only when TLS is terminated by a trusted proxy (the server refuses
+// MTLS_ENABLE without CAs), which is then trusted to have validated the chain.
+func verifyClientCertificate(client *repository.Client, cert *x509.Certificate, roots *x509.CertPool) error {
+	if cert == nil {
+		return ErrClientCertificateInvalid
+	}
+	switch client.TokenEndpointAuthMethod {
+	case repository.AuthMethodTLSClientAuth:
+		if roots != nil {
+			if _, err := cert.Verify(x509.VerifyOptions{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}); err != nil {
+				return ErrClientCertificateInvalid
+			}
+		}
+		if normalizeDN(cert.Subject.String()) != normalizeDN(client.TLSClientAuthSubjectDN) {
+			return ErrClientCertificateInvalid
+		}
+		return nil
+	case repository.AuthMethodSelfSignedTLS:
+		got := jwtx.CertificateThumbprint(cert)
+		if client.TLSClientCertThumbprint == "" || subtle.ConstantTimeCompare([]byte(got), []byte(client.TLSClientCertThumbprint)) != 1 {
+			return ErrClientCertificateInvalid
+		}
+		return nil
+	}
+	return ErrClientCertificateInvalid
+}
+
+// normalizeDN makes RFC 4514 DNs comparable: case-insensitive, no spaces around separators.
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, p := range parts {
+		k, v, _ := strings.Cut(p, "=")
+		parts[i] = strings.TrimSpace(k) + "=" + strings.TrimSpace(v)
+	}
+	return strings.ToLower(strings.Join(parts, ","))
+}
+
+// bindCertificate adds the cnf."x5t#S256" confirmation claim (RFC 8705 §3.1).
+func bindCertificate(std map[string]any, cert *x509.Certificate) {
+	if cert == nil {
+		return
+	}
+	addConfirmation(std, "x5t#S256", jwtx.CertificateThumbprint(cert))
+}
+
+// addConfirmation merges a confirmation method into the "cnf" claim.
+func addConfirmation(std map[string]any, method, value string) {
+	cnf, _ := std["cnf"].(map[string]any)
+	if cnf == nil {
+		cnf = map[string]any{}
+		std["cnf"] = cnf
+	}
+	cnf[method] = value
+}
@@ -0,0 +1,151 @@
+package oauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+)
+
+// testCA issues client certificates for tls_client_auth tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	p := x509.NewCertPool()
+	p.AddCert(ca.cert)
+	return p
+}
+
+func (ca *testCA) issue(t *testing.T, subject pkix.Name) *x509.Certificate {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func TestVerifyClientCertificateTLSClientAuth(t *testing.T) {
+	ca := newTestCA(t)
+	client := &repository.Client{
+		ClientID:                "client-a",
+		TokenEndpointAuthMethod: repository.AuthMethodTLSClientAuth,
+		TLSClientAuthSubjectDN:  "CN=client-a, O=Acme",
+	}
+	cert := ca.issue(t, pkix.Name{CommonName: "client-a", Organization: []string{"Acme"}})
+
+	if err := verifyClientCertificate(client, cert, ca.pool()); err != nil {
+		t.Fatalf("valid certificate rejected: %v", err)
+	}
+
+	// Same DN, but not issued by a trusted CA
+	selfSigned := selfSignedCert(t, "client-a")
+	client.TLSClientAuthSubjectDN = selfSigned.Subject.String()
+	if err := verifyClientCertificate(client, selfSigned, ca.pool()); !errors.Is(err, ErrClientCertificateInvalid) {
+		t.Errorf("untrusted chain: got %v", err)
+	}
+
+	// Trusted CA, another subject
+	client.TLSClientAuthSubjectDN = "CN=client-a,O=Acme"
+	other := ca.issue(t, pkix.Name{CommonName: "client-b", Organization: []string{"Acme"}})
+	if err := verifyClientCertificate(client, other, ca.pool()); !errors.Is(err, ErrClientCertificateInvalid) {
+		t.Errorf("subject mismatch: got %v", err)
+	}
+
+	if err := verifyClientCertificate(client, nil, ca.pool()); !errors.Is(err, ErrClientCertificateInvalid) {
+		t.Errorf("no certificate: got %v", err)
+	}
+}
+
+func TestVerifyClientCertificateSelfSigned(t *testing.T) {
+	cert := selfSignedCert(t, "client-a")
+	client := &repository.Client{
+		ClientID:                "client-a",
+		TokenEndpointAuthMethod: repository.AuthMethodSelfSignedTLS,
+		TLSClientCertThumbprint: jwtx.CertificateThumbprint(cert),
+	}
+	if err := verifyClientCertificate(client, cert, nil); err != nil {
+		t.Fatalf("registered certificate rejected: %v", err)
+	}
+	if err := verifyClientCertificate(client, selfSignedCert(t, "client-a"), nil); !errors.Is(err, ErrClientCertificateInvalid) {
+		t.Errorf("other certificate: got %v", err)
+	}
+	client.TLSClientCertThumbprint = ""
+	if err := verifyClientCertificate(client, cert, nil); !errors.Is(err, ErrClientCertificateInvalid) {
+		t.Errorf("no registered thumbprint: got %v", err)
+	}
+}
+
+func TestVerifyClientCertificateOtherMethods(t *testing.T) {
+	client := &repository.Client{ClientID: "client-a", TokenEndpointAuthMethod: "client_secret_basic"}
+	if err := verifyClientCertificate(client, selfSignedCert(t, "client-a"), nil); !errors.Is(err, ErrClientCertificateInvalid) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestNormalizeDN(t *testing.T) {
+	if normalizeDN("CN=Client A, O=Acme") != normalizeDN("cn=client a,o=acme") {
+		t.Error("equivalent DNs differ")
+	}
+	if normalizeDN("CN=a,O=Acme") == normalizeDN("CN=b,O=Acme") {
+		t.Error("different DNs match")
+	}
+}
+
+func TestBindCertificateKeepsDPoP(t *testing.T) {
+	cert := selfSignedCert(t, "client-a")
+	std := map[string]any{}
+	bindDPoP(std, "jkt-1")
+	bindCertificate(std, cert)
+
+	cnf, _ := std["cnf"].(map[string]any)
+	if cnf["jkt"] != "jkt-1" || cnf["x5t#S256"] != jwtx.CertificateThumbprint(cert) {
+		t.Fatalf("cnf = %v", cnf)
+	}
+
+	unbound := map[string]any{}
+	bindCertificate(unbound, nil)
+	if _, ok := unbound["cnf"]; ok {
+		t.Error("cnf added without a certificate")
+	}
+}
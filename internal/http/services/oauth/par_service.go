@@ -0,0 +1,181 @@
+package oauth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// RequestURIPrefix is the URN prefix of request_uri values issued by the PAR endpoint (RFC 9126 §2.2).
+const RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+const (
+	cacheKeyPrefixPAR = "par:" // + sha256(reference) → PushedAuthorizationPayload
+	parTTL            = 90 * time.Second
+)
+
+// Errors for pushed authorization requests
+var (
+	ErrPARRequired       = errors.New("pushed authorization request required")
+	ErrInvalidRequestURI = errors.New("invalid request_uri")
+)
+
+// PARService handles Pushed Authorization Requests (RFC 9126).
+type PARService interface {
+	// Push validates and stores an authorization request, returning its request_uri.
+	Push(ctx context.Context, req dto.PushedAuthorizationRequest) (*dto.PushedAuthorizationResponse, error)
+}
+
+// PARDeps contains dependencies for PARService.
+type PARDeps struct {
+	ControlPlane controlplane.Service
+	Cache        CacheClient
+	ClientAuth   ClientAuthService
+	ClientCAs    *x509.CertPool // roots for tls_client_auth (nil = trust the TLS terminator)
+}
+
+type parService struct {
+	cp         controlplane.Service
+	cache      CacheClient
+	clientAuth ClientAuthService
+	clientCAs  *x509.CertPool
+}
+
+// NewPARService creates a new PARService.
+func NewPARService(d PARDeps) PARService {
+	return &parService{
+		cp:         d.ControlPlane,
+		cache:      d.Cache,
+		clientAuth: d.ClientAuth,
+		clientCAs:  d.ClientCAs,
+	}
+}
+
+// Push handles POST /oauth2/par.
+// Errors are ErrToken* so the controller can reuse the token endpoint error mapping.
+func (s *parService) Push(ctx context.Context, req dto.PushedAuthorizationRequest) (*dto.PushedAuthorizationResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.par.push"))
+
+	// request_uri can't be pushed itself (RFC 9126 §2.1)
+	if req.Authorize.RequestURI != "" {
+		return nil, ErrTokenInvalidRequest
+	}
+
+	client, tenantSlug, err := s.authenticateClient(ctx, req)
+	if err != nil {
+		log.Warn("client authentication failed", logger.String("client_id", req.ClientID), logger.Err(err))
+		return nil, ErrTokenInvalidClient
+	}
+
+	// client_id in the pushed params must match the authenticated client
+	authReq := req.Authorize
+	if authReq.ClientID == "" {
+		authReq.ClientID = client.ClientID
+	}
+	if authReq.ClientID != client.ClientID {
+		return nil, ErrTokenInvalidRequest
+	}
+
+	// A pushed request object is verified now; only its params are stored (RFC 9126 §3)
+	if authReq.Request != "" {
+		if authReq, err = resolveRequestObject(ctx, s.clientAuth, tenantSlug, client, authReq); err != nil {
+			log.Debug("request object rejected", logger.Err(err))
+			return nil, ErrTokenInvalidRequestObject
+		}
+	}
+	if client.RequireSignedRequestObject && !authReq.RequestObject {
+		return nil, ErrTokenInvalidRequestObject
+	}
+
+	// Validate up-front, the same checks /authorize applies
+	if err := validateAuthorizeRequest(authReq); err != nil {
+		log.Debug("invalid authorization params", logger.Err(err))
+		if err == ErrInvalidScope {
+			return nil, ErrTokenInvalidScope
+		}
+		return nil, ErrTokenInvalidRequest
+	}
+	if _, err := parseOIDCParams(authReq); err != nil {
+		log.Debug("invalid oidc params", logger.Err(err))
+		return nil, ErrTokenInvalidRequest
+	}
+	if !containsString(client.RedirectURIs, authReq.RedirectURI) {
+		log.Debug("redirect_uri not allowed")
+		return nil, ErrTokenInvalidRequest
+	}
+	for _, sc := range strings.Fields(authReq.Scope) {
+		if !s.cp.IsScopeAllowed(client, sc) {
+			return nil, ErrTokenInvalidScope
+		}
+	}
+	if err := checkResources(s.cp, client, listResources(ctx, s.cp, tenantSlug), authReq.Resource); err != nil {
+		log.Debug("invalid resource", logger.Err(err))
+		return nil, ErrTokenInvalidTarget
+	}
+	if _, err := resolveAuthorizationDetails(ctx, s.cp, tenantSlug, authReq.AuthorizationDetails); err != nil {
+		log.Debug("invalid authorization_details", logger.Err(err))
+		return nil, ErrTokenInvalidAuthorizationDetails
+	}
+
+	ref, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		log.Error("request_uri generation failed", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+	payload, _ := json.Marshal(dto.PushedAuthorizationPayload{
+		Request:   authReq,
+		TenantID:  tenantSlug,
+		ExpiresAt: time.Now().Add(parTTL),
+	})
+	s.cache.Set(cacheKeyPrefixPAR+tokens.SHA256Base64URL(ref), payload, parTTL)
+
+	log.Info("authorization request pushed", logger.TenantID(tenantSlug), logger.String("client_id", client.ClientID))
+
+	return &dto.PushedAuthorizationResponse{
+		RequestURI: RequestURIPrefix + ref,
+		ExpiresIn:  int64(parTTL.Seconds()),
+	}, nil
+}
+
+// authenticateClient authenticates the pushing client with its registered method.
+// Public clients are identified by client_id only (PKCE protects the code).
+func (s *parService) authenticateClient(ctx context.Context, req dto.PushedAuthorizationRequest) (*repository.Client, string, error) {
+	assertion := ClientAssertion{Type: req.ClientAssertionType, Value: req.ClientAssertion}
+	return authenticateRequestClient(ctx, s.cp, s.clientAuth, s.clientCAs, req.TenantSlug, req.ClientID, req.ClientSecret, assertion, req.ClientCert)
+}
+
+// loadPushedRequest resolves a request_uri issued by the PAR endpoint.
+// Returns the pushed request and its cache key; the caller deletes it once the code is issued.
+func loadPushedRequest(cache CacheClient, clientID, requestURI string) (dto.AuthorizeRequest, string, error) {
+	ref, ok := strings.CutPrefix(requestURI, RequestURIPrefix)
+	if !ok || ref == "" {
+		return dto.AuthorizeRequest{}, "", ErrInvalidRequestURI
+	}
+	key := cacheKeyPrefixPAR + tokens.SHA256Base64URL(ref)
+	data, found := cache.Get(key)
+	if !found {
+		return dto.AuthorizeRequest{}, "", ErrInvalidRequestURI
+	}
+	var p dto.PushedAuthorizationPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return dto.AuthorizeRequest{}, "", ErrInvalidRequestURI
+	}
+	if !time.Now().Before(p.ExpiresAt) {
+		cache.Delete(key)
+		return dto.AuthorizeRequest{}, "", ErrInvalidRequestURI
+	}
+	// client_id is the only front-channel param still required (RFC 9126 §4)
+	if clientID != p.Request.ClientID {
+		return dto.AuthorizeRequest{}, "", ErrInvalidRequestURI
+	}
+	return p.Request, key, nil
+}
@@ -0,0 +1,86 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// pushRequest stores a pushed request the way Push does and returns its request_uri.
+func pushRequest(t *testing.T, c CacheClient, req dto.AuthorizeRequest, expiresAt time.Time) string {
+	t.Helper()
+	ref, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := json.Marshal(dto.PushedAuthorizationPayload{Request: req, TenantID: "acme", ExpiresAt: expiresAt})
+	c.Set(cacheKeyPrefixPAR+tokens.SHA256Base64URL(ref), payload, parTTL)
+	return RequestURIPrefix + ref
+}
+
+func TestLoadPushedRequest(t *testing.T) {
+	c := NewCacheAdapter(cache.NewMemory("test"))
+	pushed := dto.AuthorizeRequest{ClientID: "client-a", RedirectURI: "https://app.example.com/cb", Scope: "openid"}
+	uri := pushRequest(t, c, pushed, time.Now().Add(parTTL))
+
+	got, key, err := loadPushedRequest(c, "client-a", uri)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got.RedirectURI != pushed.RedirectURI || got.Scope != pushed.Scope {
+		t.Errorf("loaded request = %+v", got)
+	}
+
+	// The caller consumes it once the code is issued: it can't be used again
+	c.Delete(key)
+	if _, _, err := loadPushedRequest(c, "client-a", uri); !errors.Is(err, ErrInvalidRequestURI) {
+		t.Errorf("reused request_uri: got %v, want ErrInvalidRequestURI", err)
+	}
+}
+
+func TestLoadPushedRequestRejects(t *testing.T) {
+	c := NewCacheAdapter(cache.NewMemory("test"))
+	pushed := dto.AuthorizeRequest{ClientID: "client-a"}
+	valid := pushRequest(t, c, pushed, time.Now().Add(parTTL))
+	expired := pushRequest(t, c, pushed, time.Now().Add(-time.Second))
+
+	tests := []struct {
+		name     string
+		clientID string
+		uri      string
+	}{
+		{"other client", "client-b", valid},
+		{"expired", "client-a", expired},
+		{"unknown reference", "client-a", RequestURIPrefix + "nope"},
+		{"foreign uri", "client-a", "https://evil.example.com/request.jwt"},
+		{"empty reference", "client-a", RequestURIPrefix},
+	}
+	for _, tt := range tests {
+		if _, _, err := loadPushedRequest(c, tt.clientID, tt.uri); !errors.Is(err, ErrInvalidRequestURI) {
+			t.Errorf("%s: got %v, want ErrInvalidRequestURI", tt.name, err)
+		}
+	}
+
+	// Expired requests are dropped when found
+	ref := expired[len(RequestURIPrefix):]
+	if _, ok := c.Get(cacheKeyPrefixPAR + tokens.SHA256Base64URL(ref)); ok {
+		t.Error("expired pushed request still cached")
+	}
+}
+
+func TestPushRejectsNestedRequestURI(t *testing.T) {
+	s := NewPARService(PARDeps{Cache: NewCacheAdapter(cache.NewMemory("test"))})
+	req := dto.PushedAuthorizationRequest{
+		ClientID:  "client-a",
+		Authorize: dto.AuthorizeRequest{ClientID: "client-a", RequestURI: RequestURIPrefix + "x"},
+	}
+	if _, err := s.Push(context.Background(), req); !errors.Is(err, ErrTokenInvalidRequest) {
+		t.Fatalf("got %v, want ErrTokenInvalidRequest", err)
+	}
+}
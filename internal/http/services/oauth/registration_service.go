@@ -0,0 +1,472 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// Dynamic client registration errors (RFC 7591 §3.2.2, RFC 7592 §2)
+var (
+	ErrRegistrationDisabled     = errors.New("client registration not allowed")
+	ErrRegistrationInvalidToken = errors.New("invalid registration token")
+	ErrInvalidRedirectURI       = errors.New("invalid_redirect_uri")
+	ErrInvalidClientMetadata    = errors.New("invalid_client_metadata")
+)
+
+// registrationGrantTypes are the grants a client may request for itself.
+// token-exchange is left out: its policy is admin-only.
+var registrationGrantTypes = []string{"authorization_code", "refresh_token", "client_credentials", GrantTypeDeviceCode}
+
+// defaultRegistrationScopes are assigned when the registration omits "scope".
+var defaultRegistrationScopes = []string{"openid", "profile", "email"}
+
+// RegistrationService implements Dynamic Client Registration (RFC 7591)
+// and the client configuration endpoint (RFC 7592).
+type RegistrationService interface {
+	// Register creates a client from the submitted metadata.
+	Register(ctx context.Context, req dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error)
+
+	// Read returns the current registration of a client.
+	Read(ctx context.Context, req dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error)
+
+	// Update replaces the client metadata.
+	Update(ctx context.Context, req dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error)
+
+	// Delete deregisters the client.
+	Delete(ctx context.Context, req dto.ClientRegistrationRequest) error
+}
+
+// RegistrationDeps contains dependencies for RegistrationService.
+type RegistrationDeps struct {
+	ControlPlane controlplane.Service
+	Issuer       *jwtx.Issuer
+}
+
+type registrationService struct {
+	cp     controlplane.Service
+	issuer *jwtx.Issuer
+}
+
+// NewRegistrationService creates a new RegistrationService.
+// Clients are written through the control plane, the same path admin-created clients use.
+func NewRegistrationService(d RegistrationDeps) RegistrationService {
+	return &registrationService{
+		cp:     d.ControlPlane,
+		issuer: d.Issuer,
+	}
+}
+
+func (s *registrationService) Register(ctx context.Context, req dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.register"), logger.TenantSlug(req.TenantSlug))
+
+	if err := s.checkPolicy(ctx, req.TenantSlug, req.BearerToken); err != nil {
+		log.Warn("registration rejected", logger.Err(err))
+		return nil, err
+	}
+
+	input := controlplane.ClientInput{ClientID: uuid.NewString()}
+	if err := s.applyMetadata(ctx, req.TenantSlug, &input, req.Metadata); err != nil {
+		return nil, err
+	}
+
+	var secret string
+	if usesClientSecret(input.TokenEndpointAuthMethod) {
+		generated, err := tokens.GenerateOpaqueToken(32)
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+		input.Secret = secret
+	}
+
+	rat, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	input.RegistrationAccessTokenHash = tokens.SHA256Base64URL(rat)
+
+	client, err := s.cp.CreateClient(ctx, req.TenantSlug, input)
+	if err != nil {
+		log.Warn("client registration failed", logger.Err(err))
+		return nil, metadataError(err)
+	}
+
+	log.Info("client registered", logger.ClientID(client.ClientID))
+
+	resp := s.response(req.TenantSlug, client, secret, rat)
+	resp.ClientIDIssuedAt = time.Now().Unix()
+	return resp, nil
+}
+
+func (s *registrationService) Read(ctx context.Context, req dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error) {
+	client, err := s.authorizeManagement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.clientSecret(ctx, req.TenantSlug, client)
+	if err != nil {
+		return nil, err
+	}
+	return s.response(req.TenantSlug, client, secret, req.BearerToken), nil
+}
+
+func (s *registrationService) Update(ctx context.Context, req dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.register.update"), logger.TenantSlug(req.TenantSlug))
+
+	existing, err := s.authorizeManagement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admin-only settings are kept; the registration metadata is replaced
+	input := clientInputFrom(existing)
+	if err := s.applyMetadata(ctx, req.TenantSlug, &input, req.Metadata); err != nil {
+		return nil, err
+	}
+
+	// A client switching to a secret-based method gets its first secret now
+	var secret string
+	if usesClientSecret(input.TokenEndpointAuthMethod) && existing.SecretEnc == "" {
+		generated, err := tokens.GenerateOpaqueToken(32)
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+		input.Secret = secret
+	}
+
+	client, err := s.cp.UpdateClient(ctx, req.TenantSlug, input)
+	if err != nil {
+		log.Warn("client update failed", logger.Err(err))
+		return nil, metadataError(err)
+	}
+
+	if secret == "" {
+		if secret, err = s.clientSecret(ctx, req.TenantSlug, client); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("registered client updated", logger.ClientID(client.ClientID))
+	return s.response(req.TenantSlug, client, secret, req.BearerToken), nil
+}
+
+func (s *registrationService) Delete(ctx context.Context, req dto.ClientRegistrationRequest) error {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.register.delete"), logger.TenantSlug(req.TenantSlug))
+
+	client, err := s.authorizeManagement(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := s.cp.DeleteClient(ctx, req.TenantSlug, client.ClientID); err != nil {
+		log.Error("client delete failed", logger.Err(err))
+		return err
+	}
+
+	log.Info("registered client deleted", logger.ClientID(client.ClientID))
+	return nil
+}
+
+// checkPolicy enforces the tenant's registration mode.
+func (s *registrationService) checkPolicy(ctx context.Context, tenantSlug, initialAccessToken string) error {
+	tenant, err := s.cp.GetTenant(ctx, tenantSlug)
+	if err != nil {
+		return ErrRegistrationDisabled
+	}
+	policy := tenant.Settings.ClientRegistration
+	if policy == nil {
+		return ErrRegistrationDisabled
+	}
+
+	switch policy.Mode {
+	case repository.ClientRegistrationOpen:
+		return nil
+	case repository.ClientRegistrationInitialAccessToken:
+		if initialAccessToken == "" {
+			return ErrRegistrationInvalidToken
+		}
+		hash := tokens.SHA256Base64URL(initialAccessToken)
+		for _, h := range policy.InitialAccessTokenHashes {
+			if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+				return nil
+			}
+		}
+		return ErrRegistrationInvalidToken
+	}
+	return ErrRegistrationDisabled
+}
+
+// authorizeManagement checks the registration access token for the addressed client.
+// Unknown clients get the same error so client_ids can't be probed (RFC 7592 §2.1).
+func (s *registrationService) authorizeManagement(ctx context.Context, req dto.ClientRegistrationRequest) (*repository.Client, error) {
+	if req.BearerToken == "" || req.ClientID == "" {
+		return nil, ErrRegistrationInvalidToken
+	}
+	client, err := s.cp.GetClient(ctx, req.TenantSlug, req.ClientID)
+	if err != nil {
+		return nil, ErrRegistrationInvalidToken
+	}
+	hash := tokens.SHA256Base64URL(req.BearerToken)
+	if client.RegistrationAccessTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(client.RegistrationAccessTokenHash), []byte(hash)) != 1 {
+		return nil, ErrRegistrationInvalidToken
+	}
+	return client, nil
+}
+
+// applyMetadata validates RFC 7591 metadata, applies defaults (§2) and maps it onto the client input.
+func (s *registrationService) applyMetadata(ctx context.Context, tenantSlug string, in *controlplane.ClientInput, md dto.ClientMetadata) error {
+	grants := md.GrantTypes
+	if len(grants) == 0 {
+		grants = []string{"authorization_code"}
+	}
+	for _, g := range grants {
+		if !containsString(registrationGrantTypes, g) {
+			return ErrInvalidClientMetadata
+		}
+	}
+
+	// response_types must agree with grant_types (§2.1); only "code" is supported
+	usesCode := containsString(grants, "authorization_code")
+	responseTypes := md.ResponseTypes
+	if len(responseTypes) == 0 && usesCode {
+		responseTypes = []string{"code"}
+	}
+	for _, rt := range responseTypes {
+		if rt != "code" {
+			return ErrInvalidClientMetadata
+		}
+	}
+	if usesCode != (len(responseTypes) > 0) {
+		return ErrInvalidClientMetadata
+	}
+
+	if usesCode && len(md.RedirectURIs) == 0 {
+		return ErrInvalidRedirectURI
+	}
+	for _, uri := range append(append([]string{}, md.RedirectURIs...), md.PostLogoutRedirectURIs...) {
+		if u, err := url.Parse(uri); err != nil || u.Fragment != "" || !s.cp.ValidateRedirectURI(uri) {
+			return ErrInvalidRedirectURI
+		}
+	}
+
+	method := md.TokenEndpointAuthMethod
+	if method == "" {
+		method = repository.AuthMethodClientSecretBasic
+	}
+	clientType := repository.ClientTypeConfidential
+	if method == repository.AuthMethodNone {
+		clientType = repository.ClientTypePublic
+		if containsString(grants, "client_credentials") {
+			return ErrInvalidClientMetadata
+		}
+	}
+
+	scopes := defaultRegistrationScopes
+	if md.Scope != "" {
+		scopes = strings.Fields(md.Scope)
+		if err := s.checkScopes(ctx, tenantSlug, scopes); err != nil {
+			return err
+		}
+	}
+
+	name := strings.TrimSpace(md.ClientName)
+	if name == "" {
+		name = in.ClientID
+	}
+
+	in.Name = name
+	in.Type = clientType
+	in.RedirectURIs = md.RedirectURIs
+	in.PostLogoutURIs = md.PostLogoutRedirectURIs
+	in.GrantTypes = grants
+	in.Scopes = scopes
+	in.TokenEndpointAuthMethod = method
+	in.JWKS = string(md.JWKS)
+	in.JWKSURI = md.JWKSURI
+	in.TLSClientAuthSubjectDN = md.TLSClientAuthSubjectDN
+	in.RequirePAR = md.RequirePAR
+	in.IDTokenSignedResponseAlg = md.IDTokenSignedResponseAlg
+	in.BackchannelLogoutURI = md.BackchannelLogoutURI
+	in.BackchannelLogoutSessionRequired = md.BackchannelLogoutSessionRequired
+	in.FrontchannelLogoutURI = md.FrontchannelLogoutURI
+	in.FrontchannelLogoutSessionRequired = md.FrontchannelLogoutSessionRequired
+	in.SubjectType = md.SubjectType
+	in.SectorIdentifierURI = md.SectorIdentifierURI
+	in.RequestObjectSigningAlg = md.RequestObjectSigningAlg
+	in.RequireSignedRequestObject = md.RequireSignedRequestObject
+	in.AuthorizationSignedResponseAlg = md.AuthorizationSignedResponseAlg
+	in.IDTokenEncryptedResponseAlg = md.IDTokenEncryptedResponseAlg
+	in.IDTokenEncryptedResponseEnc = md.IDTokenEncryptedResponseEnc
+	in.UserinfoSignedResponseAlg = md.UserinfoSignedResponseAlg
+	in.UserinfoEncryptedResponseAlg = md.UserinfoEncryptedResponseAlg
+	in.UserinfoEncryptedResponseEnc = md.UserinfoEncryptedResponseEnc
+	return nil
+}
+
+// checkScopes rejects scopes the tenant doesn't define.
+func (s *registrationService) checkScopes(ctx context.Context, tenantSlug string, requested []string) error {
+	defined, err := s.cp.ListScopes(ctx, tenantSlug)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(defined))
+	for _, sc := range defined {
+		names = append(names, sc.Name)
+	}
+	for _, sc := range requested {
+		if !containsString(names, sc) {
+			return ErrInvalidClientMetadata
+		}
+	}
+	return nil
+}
+
+// clientSecret returns the plain secret of clients that authenticate with it, "" otherwise.
+func (s *registrationService) clientSecret(ctx context.Context, tenantSlug string, client *repository.Client) (string, error) {
+	if !usesClientSecret(client.TokenEndpointAuthMethod) {
+		return "", nil
+	}
+	return s.cp.DecryptClientSecret(ctx, tenantSlug, client.ClientID)
+}
+
+// response builds the client information response (RFC 7591 §3.2.1).
+func (s *registrationService) response(tenantSlug string, client *repository.Client, secret, registrationToken string) *dto.ClientRegistrationResponse {
+	resp := &dto.ClientRegistrationResponse{
+		ClientID:                client.ClientID,
+		RegistrationAccessToken: registrationToken,
+		RegistrationClientURI:   s.registrationClientURI(tenantSlug, client.ClientID),
+		ClientMetadata: dto.ClientMetadata{
+			RedirectURIs:             client.RedirectURIs,
+			TokenEndpointAuthMethod:  client.TokenEndpointAuthMethod,
+			GrantTypes:               client.GrantTypes,
+			ClientName:               client.Name,
+			Scope:                    strings.Join(client.Scopes, " "),
+			JWKSURI:                  client.JWKSURI,
+			PostLogoutRedirectURIs:   client.PostLogoutURIs,
+			TLSClientAuthSubjectDN:   client.TLSClientAuthSubjectDN,
+			RequirePAR:               client.RequirePAR,
+			IDTokenSignedResponseAlg: client.IDTokenSignedResponseAlg,
+
+			BackchannelLogoutURI:              client.BackchannelLogoutURI,
+			BackchannelLogoutSessionRequired:  client.BackchannelLogoutSessionRequired,
+			FrontchannelLogoutURI:             client.FrontchannelLogoutURI,
+			FrontchannelLogoutSessionRequired: client.FrontchannelLogoutSessionRequired,
+
+			SubjectType:         client.SubjectType,
+			SectorIdentifierURI: client.SectorIdentifierURI,
+
+			RequestObjectSigningAlg:        client.RequestObjectSigningAlg,
+			RequireSignedRequestObject:     client.RequireSignedRequestObject,
+			AuthorizationSignedResponseAlg: client.AuthorizationSignedResponseAlg,
+
+			IDTokenEncryptedResponseAlg:  client.IDTokenEncryptedResponseAlg,
+			IDTokenEncryptedResponseEnc:  client.IDTokenEncryptedResponseEnc,
+			UserinfoSignedResponseAlg:    client.UserinfoSignedResponseAlg,
+			UserinfoEncryptedResponseAlg: client.UserinfoEncryptedResponseAlg,
+			UserinfoEncryptedResponseEnc: client.UserinfoEncryptedResponseEnc,
+		},
+	}
+	if client.JWKS != "" {
+		resp.JWKS = []byte(client.JWKS)
+	}
+	if containsString(client.GrantTypes, "authorization_code") {
+		resp.ResponseTypes = []string{"code"}
+	}
+	if secret != "" {
+		var never int64
+		resp.ClientSecret = secret
+		resp.ClientSecretExpiresAt = &never
+	}
+	return resp
+}
+
+// registrationClientURI is the client configuration endpoint of a client (RFC 7592 §1).
+func (s *registrationService) registrationClientURI(tenantSlug, clientID string) string {
+	base := ""
+	if s.issuer != nil {
+		base = strings.TrimRight(s.issuer.Iss, "/")
+	}
+	return base + "/oauth2/register/" + url.PathEscape(clientID) + "?tenant=" + url.QueryEscape(tenantSlug)
+}
+
+// usesClientSecret reports whether the auth method needs a client_secret.
+func usesClientSecret(method string) bool {
+	switch method {
+	case repository.AuthMethodClientSecretBasic, repository.AuthMethodClientSecretPost, repository.AuthMethodClientSecretJWT:
+		return true
+	}
+	return false
+}
+
+// metadataError maps control plane validation errors to invalid_client_metadata.
+func metadataError(err error) error {
+	if errors.Is(err, controlplane.ErrBadInput) {
+		return ErrInvalidClientMetadata
+	}
+	return err
+}
+
+// clientInputFrom copies a stored client into a control plane input for updates.
+func clientInputFrom(c *repository.Client) controlplane.ClientInput {
+	return controlplane.ClientInput{
+		Name:                     c.Name,
+		ClientID:                 c.ClientID,
+		Type:                     c.Type,
+		RedirectURIs:             c.RedirectURIs,
+		AllowedOrigins:           c.AllowedOrigins,
+		Providers:                c.Providers,
+		Scopes:                   c.Scopes,
+		RequireEmailVerification: c.RequireEmailVerification,
+		ResetPasswordURL:         c.ResetPasswordURL,
+		VerifyEmailURL:           c.VerifyEmailURL,
+		ClaimSchema:              c.ClaimSchema,
+		ClaimMapping:             c.ClaimMapping,
+		GrantTypes:               c.GrantTypes,
+		AccessTokenTTL:           c.AccessTokenTTL,
+		RefreshTokenTTL:          c.RefreshTokenTTL,
+		IDTokenTTL:               c.IDTokenTTL,
+		PostLogoutURIs:           c.PostLogoutURIs,
+		Description:              c.Description,
+		TokenExchange:            c.TokenExchange,
+		TokenEndpointAuthMethod:  c.TokenEndpointAuthMethod,
+		JWKS:                     c.JWKS,
+		JWKSURI:                  c.JWKSURI,
+		TLSClientAuthSubjectDN:   c.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  c.TLSClientCertThumbprint,
+		RequirePAR:               c.RequirePAR,
+		IDTokenSignedResponseAlg: c.IDTokenSignedResponseAlg,
+		FirstParty:               c.FirstParty,
+
+		BackchannelLogoutURI:              c.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  c.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             c.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: c.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         c.SubjectType,
+		SectorIdentifierURI: c.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        c.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     c.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: c.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  c.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  c.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    c.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: c.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: c.UserinfoEncryptedResponseEnc,
+	}
+}
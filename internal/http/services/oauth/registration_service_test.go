@@ -0,0 +1,243 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+)
+
+// fakeControlPlane keeps clients in memory; other methods panic if called.
+type fakeControlPlane struct {
+	controlplane.Service
+	tenant    *repository.Tenant
+	scopes    []repository.Scope
+	resources []repository.APIResource
+	clients   map[string]*repository.Client
+	secrets   map[string]string
+}
+
+func newFakeControlPlane(policy *repository.ClientRegistrationSettings) *fakeControlPlane {
+	return &fakeControlPlane{
+		tenant:  &repository.Tenant{Slug: "acme", Settings: repository.TenantSettings{ClientRegistration: policy}},
+		scopes:  []repository.Scope{{Name: "openid"}, {Name: "profile"}, {Name: "email"}, {Name: "orders:read"}},
+		clients: map[string]*repository.Client{},
+		secrets: map[string]string{},
+	}
+}
+
+func (f *fakeControlPlane) GetTenant(_ context.Context, slug string) (*repository.Tenant, error) {
+	if slug != f.tenant.Slug {
+		return nil, controlplane.ErrTenantNotFound
+	}
+	return f.tenant, nil
+}
+
+func (f *fakeControlPlane) ListScopes(context.Context, string) ([]repository.Scope, error) {
+	return f.scopes, nil
+}
+
+func (f *fakeControlPlane) ValidateRedirectURI(uri string) bool {
+	return strings.HasPrefix(uri, "https://")
+}
+
+func (f *fakeControlPlane) GetClient(_ context.Context, _, clientID string) (*repository.Client, error) {
+	c, ok := f.clients[clientID]
+	if !ok {
+		return nil, controlplane.ErrClientNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeControlPlane) CreateClient(_ context.Context, _ string, in controlplane.ClientInput) (*repository.Client, error) {
+	return f.store(in), nil
+}
+
+func (f *fakeControlPlane) UpdateClient(_ context.Context, _ string, in controlplane.ClientInput) (*repository.Client, error) {
+	if in.RegistrationAccessTokenHash == "" {
+		in.RegistrationAccessTokenHash = f.clients[in.ClientID].RegistrationAccessTokenHash
+	}
+	return f.store(in), nil
+}
+
+func (f *fakeControlPlane) DeleteClient(_ context.Context, _, clientID string) error {
+	delete(f.clients, clientID)
+	return nil
+}
+
+func (f *fakeControlPlane) DecryptClientSecret(_ context.Context, _, clientID string) (string, error) {
+	return f.secrets[clientID], nil
+}
+
+func (f *fakeControlPlane) store(in controlplane.ClientInput) *repository.Client {
+	c := &repository.Client{
+		ClientID:                    in.ClientID,
+		Name:                        in.Name,
+		Type:                        in.Type,
+		RedirectURIs:                in.RedirectURIs,
+		GrantTypes:                  in.GrantTypes,
+		Scopes:                      in.Scopes,
+		TokenEndpointAuthMethod:     in.TokenEndpointAuthMethod,
+		RegistrationAccessTokenHash: in.RegistrationAccessTokenHash,
+	}
+	if in.Secret != "" {
+		f.secrets[in.ClientID] = in.Secret
+	}
+	if f.secrets[in.ClientID] != "" {
+		c.SecretEnc = "enc"
+	}
+	f.clients[in.ClientID] = c
+	return c
+}
+
+func newTestRegistration(cp *fakeControlPlane) RegistrationService {
+	return NewRegistrationService(RegistrationDeps{ControlPlane: cp, Issuer: &jwtx.Issuer{Iss: testIssuer}})
+}
+
+func webClientMetadata() dto.ClientMetadata {
+	return dto.ClientMetadata{ClientName: "Shop", RedirectURIs: []string{"https://shop.example.com/cb"}}
+}
+
+func TestRegisterOpen(t *testing.T) {
+	ctx := context.Background()
+	cp := newFakeControlPlane(&repository.ClientRegistrationSettings{Mode: repository.ClientRegistrationOpen})
+	s := newTestRegistration(cp)
+
+	resp, err := s.Register(ctx, dto.ClientRegistrationRequest{TenantSlug: "acme", Metadata: webClientMetadata()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ClientSecret == "" || resp.RegistrationAccessToken == "" {
+		t.Fatalf("missing credentials: %+v", resp)
+	}
+	if !strings.HasPrefix(resp.RegistrationClientURI, testIssuer+"/oauth2/register/"+resp.ClientID) {
+		t.Errorf("registration_client_uri = %s", resp.RegistrationClientURI)
+	}
+	if resp.TokenEndpointAuthMethod != repository.AuthMethodClientSecretBasic || strings.Join(resp.ResponseTypes, " ") != "code" {
+		t.Errorf("defaults not applied: %+v", resp.ClientMetadata)
+	}
+
+	// Only the hash of the registration access token is stored
+	stored := cp.clients[resp.ClientID]
+	if stored.RegistrationAccessTokenHash != tokens.SHA256Base64URL(resp.RegistrationAccessToken) {
+		t.Error("registration access token hash not stored")
+	}
+}
+
+func TestRegisterPolicy(t *testing.T) {
+	ctx := context.Background()
+	iat := "initial-token"
+	tests := []struct {
+		name   string
+		policy *repository.ClientRegistrationSettings
+		token  string
+		want   error
+	}{
+		{"not configured", nil, "", ErrRegistrationDisabled},
+		{"disabled", &repository.ClientRegistrationSettings{Mode: repository.ClientRegistrationDisabled}, "", ErrRegistrationDisabled},
+		{"initial token missing", &repository.ClientRegistrationSettings{
+			Mode: repository.ClientRegistrationInitialAccessToken, InitialAccessTokenHashes: []string{tokens.SHA256Base64URL(iat)},
+		}, "", ErrRegistrationInvalidToken},
+		{"initial token wrong", &repository.ClientRegistrationSettings{
+			Mode: repository.ClientRegistrationInitialAccessToken, InitialAccessTokenHashes: []string{tokens.SHA256Base64URL(iat)},
+		}, "other", ErrRegistrationInvalidToken},
+		{"initial token valid", &repository.ClientRegistrationSettings{
+			Mode: repository.ClientRegistrationInitialAccessToken, InitialAccessTokenHashes: []string{tokens.SHA256Base64URL(iat)},
+		}, iat, nil},
+	}
+	for _, tt := range tests {
+		s := newTestRegistration(newFakeControlPlane(tt.policy))
+		_, err := s.Register(ctx, dto.ClientRegistrationRequest{TenantSlug: "acme", BearerToken: tt.token, Metadata: webClientMetadata()})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRegisterRejectsInvalidMetadata(t *testing.T) {
+	ctx := context.Background()
+	s := newTestRegistration(newFakeControlPlane(&repository.ClientRegistrationSettings{Mode: repository.ClientRegistrationOpen}))
+
+	tests := []struct {
+		name string
+		edit func(*dto.ClientMetadata)
+		want error
+	}{
+		{"no redirect_uris", func(m *dto.ClientMetadata) { m.RedirectURIs = nil }, ErrInvalidRedirectURI},
+		{"fragment in redirect_uri", func(m *dto.ClientMetadata) { m.RedirectURIs = []string{"https://shop.example.com/cb#x"} }, ErrInvalidRedirectURI},
+		{"insecure redirect_uri", func(m *dto.ClientMetadata) { m.RedirectURIs = []string{"http://shop.example.com/cb"} }, ErrInvalidRedirectURI},
+		{"token-exchange grant", func(m *dto.ClientMetadata) { m.GrantTypes = []string{GrantTypeTokenExchange} }, ErrInvalidClientMetadata},
+		{"implicit response_type", func(m *dto.ClientMetadata) { m.ResponseTypes = []string{"token"} }, ErrInvalidClientMetadata},
+		{"response_types without code grant", func(m *dto.ClientMetadata) {
+			m.GrantTypes = []string{"client_credentials"}
+			m.ResponseTypes = []string{"code"}
+		}, ErrInvalidClientMetadata},
+		{"public client_credentials", func(m *dto.ClientMetadata) {
+			m.TokenEndpointAuthMethod = repository.AuthMethodNone
+			m.GrantTypes = []string{"authorization_code", "client_credentials"}
+		}, ErrInvalidClientMetadata},
+		{"unknown scope", func(m *dto.ClientMetadata) { m.Scope = "openid admin" }, ErrInvalidClientMetadata},
+	}
+	for _, tt := range tests {
+		md := webClientMetadata()
+		tt.edit(&md)
+		if _, err := s.Register(ctx, dto.ClientRegistrationRequest{TenantSlug: "acme", Metadata: md}); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRegistrationManagement(t *testing.T) {
+	ctx := context.Background()
+	cp := newFakeControlPlane(&repository.ClientRegistrationSettings{Mode: repository.ClientRegistrationOpen})
+	s := newTestRegistration(cp)
+
+	md := webClientMetadata()
+	md.TokenEndpointAuthMethod = repository.AuthMethodNone
+	reg, err := s.Register(ctx, dto.ClientRegistrationRequest{TenantSlug: "acme", Metadata: md})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.ClientSecret != "" {
+		t.Error("public client got a secret")
+	}
+	manage := dto.ClientRegistrationRequest{TenantSlug: "acme", ClientID: reg.ClientID, BearerToken: reg.RegistrationAccessToken}
+
+	if _, err := s.Read(ctx, manage); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	// A wrong token and an unknown client look the same
+	for _, req := range []dto.ClientRegistrationRequest{
+		{TenantSlug: "acme", ClientID: reg.ClientID, BearerToken: "wrong"},
+		{TenantSlug: "acme", ClientID: "unknown", BearerToken: reg.RegistrationAccessToken},
+		{TenantSlug: "acme", ClientID: reg.ClientID},
+	} {
+		if _, err := s.Read(ctx, req); !errors.Is(err, ErrRegistrationInvalidToken) {
+			t.Errorf("read %+v: got %v, want ErrRegistrationInvalidToken", req, err)
+		}
+	}
+
+	// Switching to a secret-based method issues the first secret
+	manage.Metadata = webClientMetadata()
+	upd, err := s.Update(ctx, manage)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if upd.ClientSecret == "" {
+		t.Error("no secret after switching to client_secret_basic")
+	}
+
+	if err := s.Delete(ctx, manage); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.Read(ctx, manage); !errors.Is(err, ErrRegistrationInvalidToken) {
+		t.Errorf("read after delete: got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// Errors for JWT-secured authorization requests (RFC 9101)
+var (
+	ErrInvalidRequestObject  = errors.New("invalid request object")
+	ErrRequestObjectRequired = errors.New("signed request object required")
+)
+
+// VerifyRequestObject implements ClientAuthService.
+func (s *clientAuthService) VerifyRequestObject(ctx context.Context, tenantSlug string, client *repository.Client, raw string) (map[string]any, error) {
+	// Signed then encrypted (RFC 9101 §6.1): the JWE payload is the signed JWT
+	if jwtx.IsJWE(raw) {
+		if s.issuer == nil {
+			return nil, fmt.Errorf("%w: encryption not supported", ErrInvalidRequestObject)
+		}
+		plain, _, err := s.issuer.DecryptForTenant(tenantSlug, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRequestObject, err)
+		}
+		raw = string(plain)
+	}
+
+	algs := jwtx.RequestObjectSigningAlgs
+	if client.RequestObjectSigningAlg != "" {
+		algs = []string{client.RequestObjectSigningAlg}
+	}
+	tk, err := jwtv5.Parse(raw,
+		func(t *jwtv5.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return s.clientKey(ctx, client, kid)
+		},
+		jwtv5.WithValidMethods(algs),
+		jwtv5.WithIssuer(client.ClientID),
+		jwtv5.WithLeeway(assertionLeeway))
+	if err != nil || !tk.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequestObject, err)
+	}
+	claims, ok := tk.Claims.(jwtv5.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected claims type", ErrInvalidRequestObject)
+	}
+
+	// aud must identify this authorization server (RFC 9101 §4)
+	aud, _ := claims.GetAudience()
+	if !s.audienceAllowed(ctx, tenantSlug, aud) {
+		return nil, fmt.Errorf("%w: invalid aud", ErrInvalidRequestObject)
+	}
+	if cid, ok := claims["client_id"]; ok && cid != client.ClientID {
+		return nil, fmt.Errorf("%w: client_id mismatch", ErrInvalidRequestObject)
+	}
+	return claims, nil
+}
+
+// resolveRequestObject verifies the request object of req and merges its claims over
+// the query parameters: signed values take precedence (RFC 9101 §6.3).
+func resolveRequestObject(ctx context.Context, auth ClientAuthService, tenantSlug string, client *repository.Client, req dto.AuthorizeRequest) (dto.AuthorizeRequest, error) {
+	if auth == nil {
+		return req, ErrInvalidRequestObject
+	}
+	claims, err := auth.VerifyRequestObject(ctx, tenantSlug, client, req.Request)
+	if err != nil {
+		return req, err
+	}
+	// A request object can't point to another one (RFC 9101 §4)
+	if _, ok := claims["request"]; ok {
+		return req, fmt.Errorf("%w: nested request", ErrInvalidRequestObject)
+	}
+	if _, ok := claims["request_uri"]; ok {
+		return req, fmt.Errorf("%w: nested request_uri", ErrInvalidRequestObject)
+	}
+
+	fields := map[string]*string{
+		"response_type":         &req.ResponseType,
+		"client_id":             &req.ClientID,
+		"redirect_uri":          &req.RedirectURI,
+		"scope":                 &req.Scope,
+		"state":                 &req.State,
+		"nonce":                 &req.Nonce,
+		"code_challenge":        &req.CodeChallenge,
+		"code_challenge_method": &req.CodeChallengeMethod,
+		"prompt":                &req.Prompt,
+		"max_age":               &req.MaxAge,
+		"login_hint":            &req.LoginHint,
+		"id_token_hint":         &req.IDTokenHint,
+		"ui_locales":            &req.UILocales,
+		"acr_values":            &req.ACRValues,
+		"response_mode":         &req.ResponseMode,
+	}
+	for name, dst := range fields {
+		v, ok := claims[name]
+		if !ok {
+			continue
+		}
+		switch t := v.(type) {
+		case string:
+			*dst = strings.TrimSpace(t)
+		case float64: // max_age may be a JSON number
+			*dst = strconv.FormatInt(int64(t), 10)
+		default:
+			return req, fmt.Errorf("%w: invalid %s", ErrInvalidRequestObject, name)
+		}
+	}
+	switch r := claims["resource"].(type) {
+	case nil:
+	case string:
+		req.Resource = []string{r}
+	case []any:
+		req.Resource = nil
+		for _, v := range r {
+			s, ok := v.(string)
+			if !ok {
+				return req, fmt.Errorf("%w: invalid resource", ErrInvalidRequestObject)
+			}
+			req.Resource = append(req.Resource, s)
+		}
+	default:
+		return req, fmt.Errorf("%w: invalid resource", ErrInvalidRequestObject)
+	}
+	// authorization_details is a JSON array in the request object (RFC 9396 §3)
+	if ad, ok := claims["authorization_details"]; ok {
+		list, isList := ad.([]any)
+		if !isList {
+			return req, fmt.Errorf("%w: invalid authorization_details", ErrInvalidRequestObject)
+		}
+		b, _ := json.Marshal(list)
+		req.AuthorizationDetails = string(b)
+	}
+
+	if req.ClientID != client.ClientID {
+		return req, fmt.Errorf("%w: client_id mismatch", ErrInvalidRequestObject)
+	}
+	req.Request = ""
+	req.RequestObject = true
+	return req, nil
+}
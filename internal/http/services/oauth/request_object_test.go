@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oauth"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+func requestObjectClaims() jwtv5.MapClaims {
+	return jwtv5.MapClaims{
+		"iss":           "client-a",
+		"aud":           testIssuer,
+		"exp":           time.Now().Add(time.Minute).Unix(),
+		"client_id":     "client-a",
+		"response_type": "code",
+		"redirect_uri":  "https://app.example.com/signed",
+		"scope":         "openid profile",
+		"max_age":       float64(300),
+		"resource":      []any{"https://api.example.com/orders"},
+	}
+}
+
+func TestResolveRequestObject(t *testing.T) {
+	ctx := context.Background()
+	s := newTestClientAuth()
+	client, key := privateKeyJWTClient(t)
+
+	req := dto.AuthorizeRequest{
+		ClientID:    "client-a",
+		RedirectURI: "https://app.example.com/query",
+		Scope:       "openid",
+		State:       "from-query",
+		Request:     signAssertion(t, key, "k1", requestObjectClaims()).Value,
+	}
+	got, err := resolveRequestObject(ctx, s, "acme", client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Signed values win; parameters absent from the object are kept
+	if got.RedirectURI != "https://app.example.com/signed" || got.Scope != "openid profile" || got.State != "from-query" {
+		t.Errorf("merged request = %+v", got)
+	}
+	if got.MaxAge != "300" || !reflect.DeepEqual(got.Resource, []string{"https://api.example.com/orders"}) {
+		t.Errorf("max_age=%q resource=%v", got.MaxAge, got.Resource)
+	}
+	if !got.RequestObject || got.Request != "" {
+		t.Error("request object not marked as verified")
+	}
+}
+
+func TestResolveRequestObjectRejects(t *testing.T) {
+	ctx := context.Background()
+	s := newTestClientAuth()
+	client, key := privateKeyJWTClient(t)
+
+	tests := []struct {
+		name string
+		edit func(jwtv5.MapClaims)
+	}{
+		{"nested request", func(c jwtv5.MapClaims) { c["request"] = "x.y.z" }},
+		{"nested request_uri", func(c jwtv5.MapClaims) { c["request_uri"] = RequestURIPrefix + "x" }},
+		{"other client_id", func(c jwtv5.MapClaims) { c["client_id"] = "client-b" }},
+		{"other issuer", func(c jwtv5.MapClaims) { c["iss"] = "client-b" }},
+		{"wrong audience", func(c jwtv5.MapClaims) { c["aud"] = "https://other.example.com" }},
+		{"expired", func(c jwtv5.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"non-string scope", func(c jwtv5.MapClaims) { c["scope"] = []any{"openid"} }},
+		{"authorization_details not a list", func(c jwtv5.MapClaims) { c["authorization_details"] = "x" }},
+	}
+	for _, tt := range tests {
+		claims := requestObjectClaims()
+		tt.edit(claims)
+		req := dto.AuthorizeRequest{ClientID: "client-a", Request: signAssertion(t, key, "k1", claims).Value}
+		if _, err := resolveRequestObject(ctx, s, "acme", client, req); !errors.Is(err, ErrInvalidRequestObject) {
+			t.Errorf("%s: got %v, want ErrInvalidRequestObject", tt.name, err)
+		}
+	}
+
+	// The client pinned another algorithm
+	client.RequestObjectSigningAlg = "RS256"
+	req := dto.AuthorizeRequest{ClientID: "client-a", Request: signAssertion(t, key, "k1", requestObjectClaims()).Value}
+	if _, err := resolveRequestObject(ctx, s, "acme", client, req); !errors.Is(err, ErrInvalidRequestObject) {
+		t.Errorf("alg other than request_object_signing_alg: got %v", err)
+	}
+}
+
+func TestSplitResponseMode(t *testing.T) {
+	tests := []struct {
+		mode     string
+		delivery string
+		jarm     bool
+	}{
+		{"", ResponseModeQuery, false},
+		{ResponseModeQuery, ResponseModeQuery, false},
+		{ResponseModeFragment, ResponseModeFragment, false},
+		{ResponseModeFormPost, ResponseModeFormPost, false},
+		{ResponseModeJWT, ResponseModeQuery, true},
+		{ResponseModeQueryJWT, ResponseModeQuery, true},
+		{ResponseModeFragmentJWT, ResponseModeFragment, true},
+		{ResponseModeFormPostJWT, ResponseModeFormPost, true},
+	}
+	for _, tt := range tests {
+		if delivery, jarm := splitResponseMode(tt.mode); delivery != tt.delivery || jarm != tt.jarm {
+			t.Errorf("splitResponseMode(%q) = %s, %v; want %s, %v", tt.mode, delivery, jarm, tt.delivery, tt.jarm)
+		}
+	}
+}
+
+func TestSignAuthorizationResponsePlain(t *testing.T) {
+	delivery, response, err := signAuthorizationResponse(context.Background(), nil, nil, nil, "acme", ResponseModeFormPost, map[string]string{"code": "c"})
+	if err != nil || delivery != ResponseModeFormPost || response != "" {
+		t.Errorf("got %s, %q, %v", delivery, response, err)
+	}
+	if _, _, err := signAuthorizationResponse(context.Background(), nil, nil, nil, "acme", ResponseModeJWT, nil); err == nil {
+		t.Error("JARM without an issuer accepted")
+	}
+}
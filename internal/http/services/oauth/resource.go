@@ -0,0 +1,181 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// resourceIndicators are the resource parameters of a grant (RFC 8707): Granted
+// at /authorize (kept with the code and the refresh token) and Requested at /token.
+type resourceIndicators struct {
+	Granted   []string
+	Requested []string
+}
+
+// resourceTarget is the audience an access token is issued for.
+type resourceTarget struct {
+	Audience []string // resource identifiers, or the client ID when no resource was requested
+	Scopes   []string // granted scopes minus those qualified for APIs outside the audience
+	TTL      int      // seconds; 0 = issuer default
+	Alg      string   // "" = tenant algorithm
+	Profile  string   // repository.AccessTokenProfile*; resources override the tenant's
+	Client   bool     // true when the audience is the client itself
+	Granted  []string // resources of the grant, kept with the refresh token
+
+	// Authorization details (RFC 9396): Details go into the access token,
+	// GrantedDetails are those of the grant and are kept with the refresh token.
+	Details        []map[string]any
+	GrantedDetails []map[string]any
+}
+
+// validResourceIndicator reports whether v is an absolute URI without fragment (RFC 8707 §2).
+func validResourceIndicator(v string) bool {
+	u, err := url.Parse(v)
+	return err == nil && u.IsAbs() && u.Fragment == "" && u.RawFragment == ""
+}
+
+// findResource returns the registered API resource with the given identifier.
+func findResource(all []repository.APIResource, identifier string) *repository.APIResource {
+	for i := range all {
+		if all[i].Identifier == identifier {
+			return &all[i]
+		}
+	}
+	return nil
+}
+
+// listResources loads the tenant's API resource registry (empty if unavailable).
+func listResources(ctx context.Context, cp controlplane.Service, tenantSlug string) []repository.APIResource {
+	if cp == nil {
+		return nil
+	}
+	all, err := cp.ListAPIResources(ctx, tenantSlug)
+	if err != nil {
+		return nil
+	}
+	return all
+}
+
+// clientMayTarget reports whether the client is allowed at least one scope of the
+// resource (or all of them with "<identifier>/*").
+func clientMayTarget(cp controlplane.Service, client *repository.Client, res *repository.APIResource) bool {
+	if containsString(client.Scopes, res.QualifiedScope("*")) {
+		return true
+	}
+	for _, sc := range res.Scopes {
+		if cp.IsScopeAllowed(client, res.QualifiedScope(sc)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkResources validates resource indicators for the client: each must be a
+// registered API resource (from all) the client may target.
+func checkResources(cp controlplane.Service, client *repository.Client, all []repository.APIResource, identifiers []string) error {
+	for _, id := range identifiers {
+		if !validResourceIndicator(id) {
+			return fmt.Errorf("invalid resource indicator %q", id)
+		}
+		res := findResource(all, id)
+		if res == nil {
+			return fmt.Errorf("unknown resource %q", id)
+		}
+		if !clientMayTarget(cp, client, res) {
+			return fmt.Errorf("resource %q not allowed for client", id)
+		}
+	}
+	return nil
+}
+
+// target returns the resources the access token is for. Requested resources must
+// be part of the grant; a grant made without resource indicators is not
+// audience-restricted, so any resource the client may target is accepted.
+func (r resourceIndicators) target() ([]string, error) {
+	if len(r.Requested) == 0 {
+		return r.Granted, nil
+	}
+	if len(r.Granted) == 0 {
+		return r.Requested, nil
+	}
+	for _, id := range r.Requested {
+		if !containsString(r.Granted, id) {
+			return nil, fmt.Errorf("resource %q was not granted", id)
+		}
+	}
+	return r.Requested, nil
+}
+
+// resolveResourceTarget builds the audience, scopes, TTL, signing algorithm and
+// profile of an access token for the resource indicators. Without resources the
+// token is for the client.
+func (s *tokenService) resolveResourceTarget(ctx context.Context, client *repository.Client, tenantSlug string, r resourceIndicators, scopes []string) (*resourceTarget, error) {
+	identifiers, err := r.target()
+	if err != nil {
+		return nil, err
+	}
+	if len(identifiers) == 0 {
+		return &resourceTarget{Audience: []string{client.ClientID}, Scopes: scopes, TTL: client.AccessTokenTTL, Profile: s.tenantAccessTokenProfile(ctx, tenantSlug), Client: true}, nil
+	}
+	all := listResources(ctx, s.cp, tenantSlug)
+	if err := checkResources(s.cp, client, all, identifiers); err != nil {
+		return nil, err
+	}
+
+	t := &resourceTarget{Audience: uniqueResources(identifiers), Scopes: []string{}, Granted: r.Granted}
+	for _, id := range t.Audience {
+		res := findResource(all, id)
+		// The shortest resource TTL wins: the token is valid at every resource in aud
+		if res.AccessTokenTTL > 0 && (t.TTL <= 0 || res.AccessTokenTTL < t.TTL) {
+			t.TTL = res.AccessTokenTTL
+		}
+		if res.SigningAlg != "" {
+			if t.Alg != "" && t.Alg != res.SigningAlg {
+				return nil, fmt.Errorf("resources require different signing algorithms")
+			}
+			t.Alg = res.SigningAlg
+		}
+		if res.AccessTokenProfile != "" {
+			if t.Profile != "" && t.Profile != res.AccessTokenProfile {
+				return nil, fmt.Errorf("resources require different access token profiles")
+			}
+			t.Profile = res.AccessTokenProfile
+		}
+	}
+
+	if t.TTL <= 0 {
+		t.TTL = client.AccessTokenTTL
+	}
+	if t.Profile == "" {
+		t.Profile = s.tenantAccessTokenProfile(ctx, tenantSlug)
+	}
+
+	// Down-scope: drop scopes that belong to an API outside the audience
+	for _, sc := range scopes {
+		owner := ""
+		for i := range all {
+			if _, ok := all[i].SplitQualifiedScope(sc); ok && len(all[i].Identifier) > len(owner) {
+				owner = all[i].Identifier
+			}
+		}
+		if owner == "" || containsString(t.Audience, owner) {
+			t.Scopes = append(t.Scopes, sc)
+		}
+	}
+	return t, nil
+}
+
+// uniqueResources removes duplicated resource indicators keeping their order.
+func uniqueResources(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !containsString(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package oauth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+const (
+	ordersAPI  = "https://api.example.com/orders"
+	billingAPI = "https://api.example.com/billing"
+)
+
+func newResourceTokenService() *tokenService {
+	cp := newFakeControlPlane(nil)
+	cp.resources = []repository.APIResource{
+		{Identifier: ordersAPI, Scopes: []string{"read", "write"}, AccessTokenTTL: 600, SigningAlg: "ES256"},
+		{Identifier: billingAPI, Scopes: []string{"read"}, AccessTokenTTL: 300},
+	}
+	return &tokenService{cp: cp}
+}
+
+func resourceClient() *repository.Client {
+	return &repository.Client{
+		ClientID:       "client-a",
+		AccessTokenTTL: 900,
+		Scopes:         []string{"openid", ordersAPI + "/read", billingAPI + "/*"},
+	}
+}
+
+func TestResourceIndicatorsTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       resourceIndicators
+		want    []string
+		wantErr bool
+	}{
+		{"none", resourceIndicators{}, nil, false},
+		{"granted only", resourceIndicators{Granted: []string{ordersAPI}}, []string{ordersAPI}, false},
+		{"unrestricted grant", resourceIndicators{Requested: []string{billingAPI}}, []string{billingAPI}, false},
+		{"subset of grant", resourceIndicators{Granted: []string{ordersAPI, billingAPI}, Requested: []string{billingAPI}}, []string{billingAPI}, false},
+		{"outside grant", resourceIndicators{Granted: []string{ordersAPI}, Requested: []string{billingAPI}}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.r.target()
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckResources(t *testing.T) {
+	s := newResourceTokenService()
+	all := s.cp.(*fakeControlPlane).resources
+	client := resourceClient()
+
+	if err := checkResources(s.cp, client, all, []string{ordersAPI, billingAPI}); err != nil {
+		t.Fatalf("allowed resources rejected: %v", err)
+	}
+	for _, bad := range []string{
+		"api-orders",                      // not absolute
+		ordersAPI + "#frag",               // fragment
+		"https://api.example.com/unknown", // not registered
+	} {
+		if checkResources(s.cp, client, all, []string{bad}) == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+
+	noScopes := &repository.Client{ClientID: "client-b", Scopes: []string{"openid"}}
+	if checkResources(s.cp, noScopes, all, []string{ordersAPI}) == nil {
+		t.Error("resource accepted for a client without any of its scopes")
+	}
+}
+
+func TestResolveResourceTarget(t *testing.T) {
+	ctx := context.Background()
+	s := newResourceTokenService()
+	client := resourceClient()
+	scopes := []string{"openid", ordersAPI + "/read", billingAPI + "/read"}
+
+	// Without resources the token is for the client
+	tgt, err := s.resolveResourceTarget(ctx, client, "acme", resourceIndicators{}, scopes)
+	if err != nil || !tgt.Client || !reflect.DeepEqual(tgt.Audience, []string{"client-a"}) || tgt.TTL != 900 {
+		t.Fatalf("client target = %+v, %v", tgt, err)
+	}
+
+	// One resource: its TTL and alg, scopes of other APIs dropped
+	tgt, err = s.resolveResourceTarget(ctx, client, "acme", resourceIndicators{Granted: []string{ordersAPI}}, scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tgt.Audience, []string{ordersAPI}) || tgt.TTL != 600 || tgt.Alg != "ES256" {
+		t.Errorf("orders target = %+v", tgt)
+	}
+	if !reflect.DeepEqual(tgt.Scopes, []string{"openid", ordersAPI + "/read"}) {
+		t.Errorf("scopes = %v", tgt.Scopes)
+	}
+
+	// Several resources: the shortest TTL wins
+	tgt, err = s.resolveResourceTarget(ctx, client, "acme", resourceIndicators{Granted: []string{ordersAPI, billingAPI, ordersAPI}}, scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tgt.Audience) != 2 || tgt.TTL != 300 || len(tgt.Scopes) != 3 {
+		t.Errorf("multi target = %+v", tgt)
+	}
+
+	if _, err := s.resolveResourceTarget(ctx, client, "acme", resourceIndicators{Granted: []string{ordersAPI}, Requested: []string{billingAPI}}, scopes); err == nil {
+		t.Error("resource outside the grant accepted")
+	}
+}
+
+func TestResolveResourceTargetConflictingAlgs(t *testing.T) {
+	s := newResourceTokenService()
+	cp := s.cp.(*fakeControlPlane)
+	cp.resources[1].SigningAlg = "RS256"
+
+	_, err := s.resolveResourceTarget(context.Background(), resourceClient(), "acme", resourceIndicators{Granted: []string{ordersAPI, billingAPI}}, nil)
+	if err == nil {
+		t.Fatal("resources with different signing algorithms accepted")
+	}
+}
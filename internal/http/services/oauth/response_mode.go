@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+)
+
+// Response modes for the authorization response (OAuth 2.0 Multiple Response
+// Types, Form Post Response Mode and JARM).
+const (
+	ResponseModeQuery       = "query"
+	ResponseModeFragment    = "fragment"
+	ResponseModeFormPost    = "form_post"
+	ResponseModeJWT         = "jwt"
+	ResponseModeQueryJWT    = "query.jwt"
+	ResponseModeFragmentJWT = "fragment.jwt"
+	ResponseModeFormPostJWT = "form_post.jwt"
+)
+
+// ResponseModes lists the supported response_mode values (published in discovery).
+var ResponseModes = []string{
+	ResponseModeQuery, ResponseModeFragment, ResponseModeFormPost,
+	ResponseModeJWT, ResponseModeQueryJWT, ResponseModeFragmentJWT, ResponseModeFormPostJWT,
+}
+
+// ErrInvalidResponseMode is returned for an unsupported response_mode.
+var ErrInvalidResponseMode = errors.New("unsupported response_mode")
+
+// splitResponseMode returns how the response is delivered (query, fragment or
+// form_post) and whether it is wrapped in a signed JWT. For the code flow "jwt"
+// means query.jwt (JARM §2.3.4).
+func splitResponseMode(mode string) (string, bool) {
+	switch mode {
+	case ResponseModeFragment, ResponseModeFormPost:
+		return mode, false
+	case ResponseModeJWT, ResponseModeQueryJWT:
+		return ResponseModeQuery, true
+	case ResponseModeFragmentJWT:
+		return ResponseModeFragment, true
+	case ResponseModeFormPostJWT:
+		return ResponseModeFormPost, true
+	default:
+		return ResponseModeQuery, false
+	}
+}
+
+// signAuthorizationResponse resolves the delivery of an authorization response.
+// For JARM modes it also returns the params signed as the "response" JWT,
+// addressed to the client and signed with its authorization_signed_response_alg.
+func signAuthorizationResponse(ctx context.Context, issuer *jwtx.Issuer, cp controlplane.Service, client *repository.Client, tenantSlug, mode string, params map[string]string) (string, string, error) {
+	delivery, jarm := splitResponseMode(mode)
+	if !jarm {
+		return delivery, "", nil
+	}
+	if issuer == nil || client == nil {
+		return "", "", errors.New("authorization response signing not configured")
+	}
+
+	iss := issuer.Iss
+	if cp != nil {
+		if ten, err := cp.GetTenant(ctx, tenantSlug); err == nil && ten != nil {
+			iss = jwtx.ResolveIssuer(issuer.Iss, string(ten.Settings.IssuerMode), ten.Slug, ten.Settings.IssuerOverride)
+		}
+	}
+
+	response, err := issuer.IssueAuthorizationResponse(tenantSlug, iss, client.ClientID, params, client.AuthorizationSignedResponseAlg)
+	if err != nil {
+		return "", "", err
+	}
+	return delivery, response, nil
+}
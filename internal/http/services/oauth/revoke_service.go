@@ -3,11 +3,15 @@ package oauth
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/revocation"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 )
 
@@ -18,7 +22,8 @@ type RevokeService interface {
 
 // RevokeDeps contains dependencies for the revoke service.
 type RevokeDeps struct {
-	DAL store.DataAccessLayer
+	DAL    store.DataAccessLayer
+	Issuer *jwtx.Issuer
 }
 
 type revokeService struct {
@@ -35,7 +40,7 @@ var (
 	ErrRevokeTokenEmpty = fmt.Errorf("token is empty")
 )
 
-// Revoke revokes a refresh token if it exists.
+// Revoke revokes a refresh token if it exists, or denylists a JWT access token by jti.
 // This operation is idempotent: always returns success even if token doesn't exist.
 func (s *revokeService) Revoke(ctx context.Context, token string) error {
 	log := logger.From(ctx).With(
@@ -48,6 +53,12 @@ func (s *revokeService) Revoke(ctx context.Context, token string) error {
 		return ErrRevokeTokenEmpty
 	}
 
+	// JWT access token: goes to the tenant denylist until it expires
+	if strings.Count(token, ".") == 2 {
+		s.revokeAccessToken(ctx, token, log)
+		return nil
+	}
+
 	// Calculate hash (same format as how refresh tokens are stored)
 	hash := tokens.SHA256Base64URL(token)
 
@@ -110,3 +121,41 @@ func (s *revokeService) tryRevokeInTenant(ctx context.Context, tda store.TenantD
 	log.Info("token revoked", zap.String("token_id", rt.ID))
 	return true
 }
+
+// revokeAccessToken denylists a signed access token by its jti.
+// Invalid or foreign tokens are ignored (RFC 7009 §2.2).
+func (s *revokeService) revokeAccessToken(ctx context.Context, token string, log *zap.Logger) {
+	if s.deps.Issuer == nil {
+		return
+	}
+	parsed, err := jwtv5.Parse(token, s.deps.Issuer.KeyfuncFromTokenClaims(), jwtv5.WithValidMethods(jwtx.SigningAlgs))
+	if err != nil || !parsed.Valid {
+		log.Debug("access token not revocable", logger.Err(err))
+		return
+	}
+	claims, ok := parsed.Claims.(jwtv5.MapClaims)
+	if !ok {
+		return
+	}
+	jti, _ := claims["jti"].(string)
+	tid, _ := claims["tid"].(string)
+	if jti == "" || tid == "" {
+		log.Debug("access token without jti/tid, nothing to revoke")
+		return
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return
+	}
+
+	tda, err := s.deps.DAL.ForTenant(ctx, tid)
+	if err != nil {
+		log.Debug("tenant resolution failed", logger.Err(err))
+		return
+	}
+	if err := revocation.New(tda.Cache()).RevokeToken(ctx, jti, exp.Time); err != nil {
+		log.Warn("failed to denylist access token", logger.Err(err))
+		return
+	}
+	log.Info("access token revoked", zap.String("jti", jti))
+}
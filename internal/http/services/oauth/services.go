@@ -2,9 +2,11 @@
 package oauth
 
 import (
+	"crypto/x509"
 	"time"
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
@@ -18,6 +20,14 @@ type Deps struct {
 	CookieName   string
 	AllowBearer  bool
 	RefreshTTL   time.Duration // TTL for refresh tokens (default 30 days)
+
+	DPoPRequireNonce bool // require server nonces in DPoP proofs (RFC 9449 §8)
+
+	ClientCAs *x509.CertPool // roots for tls_client_auth (RFC 8705); nil = trust the TLS terminator
+
+	Logout logout.Notifier // back/front-channel logout fan-out (nil = disabled)
+
+	ClientJWKS *jwtx.RemoteJWKSCache // clients' jwks_uri cache, shared with userinfo (nil = own cache)
 }
 
 // Services agrupa todos los services del dominio OAuth.
@@ -27,18 +37,35 @@ type Services struct {
 	Authorize  AuthorizeService
 	Token      TokenService
 	Consent    ConsentService
+	EndSession EndSessionService
+	Device     DeviceService
+	ClientAuth ClientAuthService
+	PAR        PARService
+	DPoP       DPoPService
+	Register   RegistrationService
 }
 
 // NewServices crea el agregador de services OAuth.
 func NewServices(d Deps) Services {
+	clientAuth := NewClientAuthService(ClientAuthDeps{
+		ControlPlane: d.ControlPlane,
+		Cache:        d.Cache,
+		Issuer:       d.Issuer,
+		JWKS:         d.ClientJWKS,
+	})
+
 	return Services{
 		Revoke: NewRevokeService(RevokeDeps{
-			DAL: d.DAL,
-		}),
-		Introspect: NewIntrospectService(IntrospectDeps{
 			DAL:    d.DAL,
 			Issuer: d.Issuer,
 		}),
+		Introspect: NewIntrospectService(IntrospectDeps{
+			DAL:          d.DAL,
+			Issuer:       d.Issuer,
+			ControlPlane: d.ControlPlane,
+			ClientAuth:   clientAuth,
+			ClientCAs:    d.ClientCAs,
+		}),
 		Authorize: NewAuthorizeService(AuthorizeDeps{
 			DAL:          d.DAL,
 			ControlPlane: d.ControlPlane,
@@ -46,18 +73,53 @@ func NewServices(d Deps) Services {
 			Issuer:       d.Issuer,
 			CookieName:   d.CookieName,
 			AllowBearer:  d.AllowBearer,
+			ClientAuth:   clientAuth,
 		}),
 		Token: NewTokenService(TokenDeps{
 			DAL:          d.DAL,
 			Issuer:       d.Issuer,
 			Cache:        d.Cache,
 			ControlPlane: d.ControlPlane,
+			ClientAuth:   clientAuth,
+			ClientCAs:    d.ClientCAs,
 			RefreshTTL:   d.RefreshTTL,
 		}),
 		Consent: NewConsentService(ConsentDeps{
 			DAL:          d.DAL,
 			Cache:        d.Cache,
 			ControlPlane: d.ControlPlane,
+			Issuer:       d.Issuer,
+		}),
+		EndSession: NewEndSessionService(EndSessionDeps{
+			DAL:          d.DAL,
+			ControlPlane: d.ControlPlane,
+			Cache:        d.Cache,
+			Issuer:       d.Issuer,
+			CookieName:   d.CookieName,
+			Logout:       d.Logout,
+		}),
+		Device: NewDeviceService(DeviceDeps{
+			ControlPlane: d.ControlPlane,
+			Cache:        d.Cache,
+			Issuer:       d.Issuer,
+			CookieName:   d.CookieName,
+			ClientAuth:   clientAuth,
+			ClientCAs:    d.ClientCAs,
+		}),
+		ClientAuth: clientAuth,
+		PAR: NewPARService(PARDeps{
+			ControlPlane: d.ControlPlane,
+			Cache:        d.Cache,
+			ClientAuth:   clientAuth,
+			ClientCAs:    d.ClientCAs,
+		}),
+		DPoP: NewDPoPService(DPoPDeps{
+			Cache:        d.Cache,
+			RequireNonce: d.DPoPRequireNonce,
+		}),
+		Register: NewRegistrationService(RegistrationDeps{
+			ControlPlane: d.ControlPlane,
+			Issuer:       d.Issuer,
 		}),
 	}
 }
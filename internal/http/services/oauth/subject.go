@@ -0,0 +1,25 @@
+package oauth
+
+import (
+	"context"
+
+	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
+)
+
+// localSubject maps the sub of a token issued by this server (ID token hint,
+// token-exchange subject/actor) back to the local user ID, undoing the pairwise
+// mapping of the client it was issued to.
+func localSubject(ctx context.Context, cp controlplane.Service, tenantSlug string, claims map[string]any) (string, error) {
+	return pairwise.LocalSubjectFromClaims(claims, func(clientID string) *repository.Client {
+		if cp == nil {
+			return nil
+		}
+		c, _, err := findClient(ctx, cp, tenantSlug, clientID)
+		if err != nil {
+			return nil
+		}
+		return c
+	})
+}
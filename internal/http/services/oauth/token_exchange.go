@@ -0,0 +1,360 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// GrantTypeTokenExchange is the grant_type for Token Exchange (RFC 8693 §2.1).
+const GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Token type identifiers (RFC 8693 §3).
+const (
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeIDToken     = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// TokenExchangeRequest contains parameters for the token-exchange grant.
+type TokenExchangeRequest struct {
+	ClientID           string
+	ClientSecret       string
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	Audience           []string // audience + resource params
+	Scope              string
+	RequestedTokenType string
+	TenantSlug         string
+	Assertion          ClientAssertion
+	DPoPJKT            string
+	ClientCert         *x509.Certificate
+}
+
+// ExchangeToken handles grant_type=urn:ietf:params:oauth:grant-type:token-exchange (RFC 8693).
+// Without actor_token the new token impersonates the subject; with actor_token
+// it is a delegation and carries an "act" claim identifying the actor.
+func (s *tokenService) ExchangeToken(ctx context.Context, req TokenExchangeRequest) (*TokenResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.token.exchange"))
+
+	if req.ClientID == "" || req.SubjectToken == "" || req.SubjectTokenType == "" {
+		return nil, ErrTokenInvalidRequest
+	}
+	if req.ActorToken == "" && req.ActorTokenType != "" || req.ActorToken != "" && req.ActorTokenType == "" {
+		return nil, ErrTokenInvalidRequest
+	}
+	if req.RequestedTokenType != "" && req.RequestedTokenType != TokenTypeAccessToken {
+		return nil, ErrTokenInvalidRequest
+	}
+
+	// Lookup client
+	client, tenantSlug, err := s.lookupClient(ctx, req.TenantSlug, req.ClientID)
+	if err != nil {
+		log.Warn("client not found", logger.String("client_id", req.ClientID))
+		return nil, ErrTokenInvalidClient
+	}
+
+	// Token exchange must be explicitly enabled and configured for the client
+	if !hasGrantType(client, GrantTypeTokenExchange) || client.TokenExchange == nil {
+		log.Warn("grant_type not allowed for client", logger.String("grant_type", GrantTypeTokenExchange))
+		return nil, ErrTokenUnauthorizedClient
+	}
+	if client.Type != repository.ClientTypeConfidential {
+		log.Warn("token exchange requires confidential client")
+		return nil, ErrTokenUnauthorizedClient
+	}
+	if err := s.authenticateClient(ctx, tenantSlug, client, req.ClientSecret, req.Assertion, req.ClientCert); err != nil {
+		log.Warn("invalid client credentials")
+		return nil, ErrTokenInvalidClient
+	}
+	policy := client.TokenExchange
+
+	effIss := s.resolveEffectiveIssuer(ctx, tenantSlug)
+
+	// Validate subject_token
+	subj, err := s.parseExchangeToken(ctx, tenantSlug, effIss, req.SubjectToken, req.SubjectTokenType)
+	if err != nil {
+		log.Warn("invalid subject_token", logger.Err(err))
+		return nil, ErrTokenInvalidGrant
+	}
+	if err := checkExchangeBinding(subj, req.DPoPJKT, req.ClientCert); err != nil {
+		log.Warn("subject_token binding not proven", logger.Err(err))
+		return nil, ErrTokenInvalidGrant
+	}
+	// Work with the local user ID; the subject_token may carry a pairwise sub
+	subjectSub, err := localSubject(ctx, s.cp, tenantSlug, subj)
+	if err != nil || subjectSub == "" {
+		return nil, ErrTokenInvalidGrant
+	}
+
+	// Validate actor_token (delegation) or fall back to impersonation
+	var actorSub string
+	if req.ActorToken != "" {
+		if !policy.AllowDelegation {
+			log.Warn("delegation not allowed for client")
+			return nil, ErrTokenUnauthorizedClient
+		}
+		actor, err := s.parseExchangeToken(ctx, tenantSlug, effIss, req.ActorToken, req.ActorTokenType)
+		if err != nil {
+			log.Warn("invalid actor_token", logger.Err(err))
+			return nil, ErrTokenInvalidGrant
+		}
+		if err := checkExchangeBinding(actor, req.DPoPJKT, req.ClientCert); err != nil {
+			log.Warn("actor_token binding not proven", logger.Err(err))
+			return nil, ErrTokenInvalidGrant
+		}
+		actorSub, err = localSubject(ctx, s.cp, tenantSlug, actor)
+		if err != nil || actorSub == "" {
+			return nil, ErrTokenInvalidGrant
+		}
+	} else if !policy.AllowImpersonation {
+		log.Warn("impersonation not allowed for client")
+		return nil, ErrTokenUnauthorizedClient
+	}
+
+	// may_act in the subject_token restricts who may act on its behalf (RFC 8693 §4.4)
+	if mayAct, ok := subj["may_act"].(map[string]any); ok {
+		if !mayActAllows(mayAct, actorSub, client.ClientID) {
+			log.Warn("actor not authorized by may_act")
+			return nil, ErrTokenInvalidGrant
+		}
+	}
+
+	// Resolve target audience
+	aud, err := resolveExchangeAudience(policy, client.ClientID, req.Audience)
+	if err != nil {
+		log.Warn("audience not allowed", logger.Err(err))
+		return nil, ErrTokenInvalidTarget
+	}
+
+	// Down-scope: requested scopes must be held by the subject and allowed by policy
+	scopes, err := resolveExchangeScopes(policy, claimScopes(subj), strings.Fields(req.Scope))
+	if err != nil {
+		log.Warn("scope not allowed", logger.Err(err))
+		return nil, ErrTokenInvalidScope
+	}
+	scopeOut := strings.Join(scopes, " ")
+
+	// The new token's subjects follow the subject type of its audience (the
+	// requesting client when the audience is not a registered client)
+	subClient := client
+	if aud != client.ClientID {
+		if c, _, err := s.lookupClient(ctx, tenantSlug, aud); err == nil {
+			subClient = c
+		}
+	}
+	outSub, err := pairwise.SubjectFor(subClient, subjectSub)
+	if err != nil {
+		log.Error("failed to derive pairwise subject", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+	outActor, err := pairwise.SubjectFor(subClient, actorSub)
+	if err != nil {
+		log.Error("failed to derive pairwise subject", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+
+	// Build claims (keep subject's authentication context)
+	std := map[string]any{
+		"tid":       tenantSlug,
+		"amr":       subj["amr"],
+		"acr":       subj["acr"],
+		"scope":     scopeOut,
+		"scp":       scopes,
+		"client_id": client.ClientID,
+	}
+	if std["acr"] == nil {
+		std["acr"] = "urn:hellojohn:loa:1"
+	}
+	bindCertificate(std, req.ClientCert)
+	tokenType := bindDPoP(std, req.DPoPJKT)
+	prevAct, _ := subj["act"].(map[string]any)
+	if actorSub != "" {
+		act := map[string]any{"sub": outActor}
+		if prevAct != nil {
+			act["act"] = prevAct // keep the delegation chain
+		}
+		std["act"] = act
+	} else if prevAct != nil {
+		std["act"] = prevAct
+	}
+
+	var custom map[string]any
+	if c, ok := subj["custom"].(map[string]any); ok {
+		custom = c
+	}
+
+	// The exchanged token never outlives the subject_token
+	ttl := client.AccessTokenTTL
+	if ttl <= 0 {
+		ttl = int(s.issuer.AccessTTL.Seconds())
+	}
+	if exp, err := subj.GetExpirationTime(); err == nil && exp != nil {
+		if remaining := int(time.Until(exp.Time).Seconds()); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	if ttl <= 0 {
+		return nil, ErrTokenInvalidGrant
+	}
+
+	target := &resourceTarget{Audience: []string{aud}, TTL: ttl, Profile: s.tenantAccessTokenProfile(ctx, tenantSlug)}
+	access, exp, err := s.issueAccessToken(ctx, tenantSlug, effIss, outSub, "", client, target, std, custom)
+	if err != nil {
+		log.Error("failed to issue access token", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+
+	log.Info("token exchanged",
+		logger.TenantID(tenantSlug),
+		logger.String("client_id", req.ClientID),
+		logger.String("aud", aud),
+		logger.Bool("delegation", actorSub != ""),
+	)
+
+	return &TokenResponse{
+		AccessToken:     access,
+		TokenType:       tokenType,
+		ExpiresIn:       int64(time.Until(exp).Seconds()),
+		Scope:           scopeOut,
+		IssuedTokenType: TokenTypeAccessToken,
+	}, nil
+}
+
+// parseExchangeToken validates a subject/actor token issued by this tenant and
+// not revoked.
+func (s *tokenService) parseExchangeToken(ctx context.Context, tenantSlug, expectedIss, raw, tokenType string) (jwtv5.MapClaims, error) {
+	switch tokenType {
+	case TokenTypeAccessToken, TokenTypeIDToken, TokenTypeJWT:
+	default:
+		return nil, fmt.Errorf("unsupported token type %q", tokenType)
+	}
+
+	tk, err := jwtv5.Parse(raw, s.issuer.KeyfuncForTenant(tenantSlug),
+		jwtv5.WithValidMethods(jwtx.SigningAlgs),
+		jwtv5.WithIssuer(expectedIss),
+		jwtv5.WithExpirationRequired())
+	if err != nil || !tk.Valid {
+		return nil, fmt.Errorf("parse token: %w", err)
+	}
+	claims, ok := tk.Claims.(jwtv5.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("unexpected claims type")
+	}
+	if tid, _ := claims["tid"].(string); tid != "" && !strings.EqualFold(tid, tenantSlug) {
+		return nil, fmt.Errorf("token belongs to another tenant")
+	}
+	if s.issuer.IsRevoked(ctx, claims) {
+		return nil, fmt.Errorf("token revoked")
+	}
+	return claims, nil
+}
+
+// checkExchangeBinding requires the caller to hold the key a sender-constrained token
+// is bound to: a DPoP proof with the same JWK (cnf.jkt) and/or the same client
+// certificate (cnf.x5t#S256). The exchanged token is bound to the presented keys,
+// so an exchange never strips the binding.
+func checkExchangeBinding(claims jwtv5.MapClaims, dpopJKT string, cert *x509.Certificate) error {
+	if jkt := jwtx.ConfirmationJKT(claims); jkt != "" && subtle.ConstantTimeCompare([]byte(jkt), []byte(dpopJKT)) != 1 {
+		return fmt.Errorf("DPoP proof key does not match cnf.jkt")
+	}
+	if x5t := jwtx.ConfirmationX5T(claims); x5t != "" && subtle.ConstantTimeCompare([]byte(x5t), []byte(jwtx.CertificateThumbprint(cert))) != 1 {
+		return fmt.Errorf("client certificate does not match cnf.x5t#S256")
+	}
+	return nil
+}
+
+// mayActAllows checks the actor against a may_act claim ({"sub": ...} and/or {"client_id": ...}).
+// For impersonation (no actor_token) the requesting client is the actor.
+func mayActAllows(mayAct map[string]any, actorSub, clientID string) bool {
+	if sub, ok := mayAct["sub"].(string); ok && sub != "" {
+		if actorSub != "" && sub == actorSub {
+			return true
+		}
+		if actorSub == "" && sub == clientID {
+			return true
+		}
+	}
+	if cid, ok := mayAct["client_id"].(string); ok && cid != "" && cid == clientID {
+		return true
+	}
+	return false
+}
+
+// resolveExchangeAudience picks the single target audience allowed by the policy.
+// Defaults to the requesting client when no audience/resource was requested.
+func resolveExchangeAudience(policy *repository.TokenExchangePolicy, clientID string, requested []string) (string, error) {
+	switch len(requested) {
+	case 0:
+		return clientID, nil
+	case 1:
+	default:
+		return "", fmt.Errorf("only one audience per exchange is supported")
+	}
+	aud := requested[0]
+	for _, a := range policy.AllowedAudiences {
+		if a == "*" || a == aud {
+			return aud, nil
+		}
+	}
+	return "", fmt.Errorf("audience %q not allowed", aud)
+}
+
+// resolveExchangeScopes returns the scopes for the exchanged token.
+// Empty request = all subject scopes permitted by the policy.
+func resolveExchangeScopes(policy *repository.TokenExchangePolicy, subjectScopes, requested []string) ([]string, error) {
+	allowed := func(sc string) bool {
+		return len(policy.AllowedScopes) == 0 || containsString(policy.AllowedScopes, sc)
+	}
+
+	if len(requested) == 0 {
+		out := []string{}
+		for _, sc := range subjectScopes {
+			if allowed(sc) {
+				out = append(out, sc)
+			}
+		}
+		return out, nil
+	}
+
+	for _, sc := range requested {
+		if !containsString(subjectScopes, sc) {
+			return nil, fmt.Errorf("scope %q not held by subject_token", sc)
+		}
+		if !allowed(sc) {
+			return nil, fmt.Errorf("scope %q not allowed by policy", sc)
+		}
+	}
+	return requested, nil
+}
+
+// claimScopes extracts scopes from "scp" (array) or "scope" (space-separated).
+func claimScopes(claims jwtv5.MapClaims) []string {
+	if arr, ok := claims["scp"].([]any); ok && len(arr) > 0 {
+		out := make([]string, 0, len(arr))
+		for _, v := range arr {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	if s, ok := claims["scp"].(string); ok && s != "" {
+		return strings.Fields(s)
+	}
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// selfSignedCert returns a throwaway client certificate.
+func selfSignedCert(t *testing.T, cn string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestCheckExchangeBinding(t *testing.T) {
+	cert := selfSignedCert(t, "client-a")
+	other := selfSignedCert(t, "client-b")
+
+	unbound := jwtv5.MapClaims{"sub": "user-1"}
+	if err := checkExchangeBinding(unbound, "", nil); err != nil {
+		t.Errorf("unbound token: %v", err)
+	}
+
+	dpopBound := jwtv5.MapClaims{"cnf": map[string]any{"jkt": "thumb-1"}}
+	if err := checkExchangeBinding(dpopBound, "thumb-1", nil); err != nil {
+		t.Errorf("matching DPoP key: %v", err)
+	}
+	if checkExchangeBinding(dpopBound, "thumb-2", nil) == nil {
+		t.Error("DPoP key mismatch accepted")
+	}
+	if checkExchangeBinding(dpopBound, "", nil) == nil {
+		t.Error("DPoP-bound token accepted without a proof")
+	}
+
+	certBound := jwtv5.MapClaims{"cnf": map[string]any{"x5t#S256": jwtx.CertificateThumbprint(cert)}}
+	if err := checkExchangeBinding(certBound, "", cert); err != nil {
+		t.Errorf("matching certificate: %v", err)
+	}
+	if checkExchangeBinding(certBound, "", other) == nil {
+		t.Error("certificate mismatch accepted")
+	}
+	if checkExchangeBinding(certBound, "", nil) == nil {
+		t.Error("certificate-bound token accepted without a certificate")
+	}
+}
+
+func TestMayActAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		mayAct   map[string]any
+		actor    string
+		clientID string
+		want     bool
+	}{
+		{"actor sub matches", map[string]any{"sub": "svc-1"}, "svc-1", "client-a", true},
+		{"actor sub differs", map[string]any{"sub": "svc-1"}, "svc-2", "client-a", false},
+		{"impersonation by named client", map[string]any{"sub": "client-a"}, "", "client-a", true},
+		{"impersonation by other client", map[string]any{"sub": "client-a"}, "", "client-b", false},
+		{"client_id matches", map[string]any{"client_id": "client-a"}, "svc-2", "client-a", true},
+		{"empty may_act", map[string]any{}, "svc-1", "client-a", false},
+	}
+	for _, tt := range tests {
+		if got := mayActAllows(tt.mayAct, tt.actor, tt.clientID); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveExchangeAudience(t *testing.T) {
+	policy := &repository.TokenExchangePolicy{AllowedAudiences: []string{"api-orders"}}
+
+	if aud, err := resolveExchangeAudience(policy, "client-a", nil); err != nil || aud != "client-a" {
+		t.Errorf("default audience = %q, %v; want client-a", aud, err)
+	}
+	if aud, err := resolveExchangeAudience(policy, "client-a", []string{"api-orders"}); err != nil || aud != "api-orders" {
+		t.Errorf("allowed audience = %q, %v", aud, err)
+	}
+	if _, err := resolveExchangeAudience(policy, "client-a", []string{"api-billing"}); err == nil {
+		t.Error("audience outside the policy accepted")
+	}
+	if _, err := resolveExchangeAudience(policy, "client-a", []string{"api-orders", "api-orders"}); err == nil {
+		t.Error("several audiences accepted")
+	}
+
+	wildcard := &repository.TokenExchangePolicy{AllowedAudiences: []string{"*"}}
+	if aud, err := resolveExchangeAudience(wildcard, "client-a", []string{"anything"}); err != nil || aud != "anything" {
+		t.Errorf("wildcard audience = %q, %v", aud, err)
+	}
+}
+
+func TestResolveExchangeScopes(t *testing.T) {
+	policy := &repository.TokenExchangePolicy{AllowedScopes: []string{"orders:read", "profile"}}
+	subject := []string{"openid", "profile", "orders:read", "orders:write"}
+
+	got, err := resolveExchangeScopes(policy, subject, nil)
+	if err != nil || !reflect.DeepEqual(got, []string{"profile", "orders:read"}) {
+		t.Errorf("default scopes = %v, %v", got, err)
+	}
+	got, err = resolveExchangeScopes(policy, subject, []string{"orders:read"})
+	if err != nil || !reflect.DeepEqual(got, []string{"orders:read"}) {
+		t.Errorf("down-scoped = %v, %v", got, err)
+	}
+	if _, err := resolveExchangeScopes(policy, subject, []string{"orders:write"}); err == nil {
+		t.Error("scope outside the policy accepted")
+	}
+	if _, err := resolveExchangeScopes(policy, subject, []string{"admin"}); err == nil {
+		t.Error("scope not held by the subject accepted")
+	}
+
+	open := &repository.TokenExchangePolicy{}
+	if got, err := resolveExchangeScopes(open, subject, []string{"orders:write"}); err != nil || len(got) != 1 {
+		t.Errorf("no scope policy = %v, %v", got, err)
+	}
+}
+
+func TestClaimScopes(t *testing.T) {
+	tests := []struct {
+		claims jwtv5.MapClaims
+		want   []string
+	}{
+		{jwtv5.MapClaims{"scp": []any{"a", "b"}}, []string{"a", "b"}},
+		{jwtv5.MapClaims{"scp": "a b"}, []string{"a", "b"}},
+		{jwtv5.MapClaims{"scope": "a b c"}, []string{"a", "b", "c"}},
+		{jwtv5.MapClaims{}, nil},
+	}
+	for _, tt := range tests {
+		if got := claimScopes(tt.claims); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("claimScopes(%v) = %v, want %v", tt.claims, got, tt.want)
+		}
+	}
+}
+
+func TestParseExchangeTokenRejectsUnsupportedType(t *testing.T) {
+	s := &tokenService{}
+	if _, err := s.parseExchangeToken(context.Background(), "acme", "https://issuer", "x.y.z", "urn:ietf:params:oauth:token-type:refresh_token"); err == nil {
+		t.Error("refresh_token type accepted")
+	}
+}
@@ -3,6 +3,7 @@ package oauth
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"time"
 )
@@ -17,6 +18,12 @@ type TokenService interface {
 
 	// ExchangeClientCredentials handles grant_type=client_credentials (M2M)
 	ExchangeClientCredentials(ctx context.Context, req ClientCredentialsRequest) (*TokenResponse, error)
+
+	// ExchangeDeviceCode handles grant_type=urn:ietf:params:oauth:grant-type:device_code (RFC 8628)
+	ExchangeDeviceCode(ctx context.Context, req DeviceCodeRequest) (*TokenResponse, error)
+
+	// ExchangeToken handles grant_type=urn:ietf:params:oauth:grant-type:token-exchange (RFC 8693)
+	ExchangeToken(ctx context.Context, req TokenExchangeRequest) (*TokenResponse, error)
 }
 
 // AuthCodeRequest contains parameters for authorization_code grant.
@@ -26,6 +33,12 @@ type AuthCodeRequest struct {
 	ClientID     string
 	CodeVerifier string
 	TenantSlug   string // Resolved from request headers/query
+	Assertion    ClientAssertion
+	DPoPJKT      string            // thumbprint of a verified DPoP proof (RFC 9449)
+	ClientCert   *x509.Certificate // mutual-TLS client certificate (RFC 8705)
+	Resource     []string          // resource indicators (RFC 8707)
+
+	AuthorizationDetails string // JSON array narrowing or requesting authorization details (RFC 9396)
 }
 
 // RefreshTokenRequest contains parameters for refresh_token grant.
@@ -33,6 +46,12 @@ type RefreshTokenRequest struct {
 	ClientID     string
 	RefreshToken string
 	TenantSlug   string
+	Assertion    ClientAssertion
+	DPoPJKT      string            // thumbprint of a verified DPoP proof (RFC 9449)
+	ClientCert   *x509.Certificate // mutual-TLS client certificate (RFC 8705)
+	Resource     []string          // resource indicators (RFC 8707)
+
+	AuthorizationDetails string // JSON array narrowing or requesting authorization details (RFC 9396)
 }
 
 // ClientCredentialsRequest contains parameters for client_credentials grant.
@@ -41,6 +60,23 @@ type ClientCredentialsRequest struct {
 	ClientSecret string
 	Scope        string
 	TenantSlug   string
+	Assertion    ClientAssertion
+	DPoPJKT      string            // thumbprint of a verified DPoP proof (RFC 9449)
+	ClientCert   *x509.Certificate // mutual-TLS client certificate (RFC 8705)
+	Resource     []string          // resource indicators (RFC 8707)
+
+	AuthorizationDetails string // JSON array narrowing or requesting authorization details (RFC 9396)
+}
+
+// DeviceCodeRequest contains parameters for the device_code grant.
+type DeviceCodeRequest struct {
+	ClientID     string
+	ClientSecret string
+	DeviceCode   string
+	TenantSlug   string
+	Assertion    ClientAssertion
+	DPoPJKT      string            // thumbprint of a verified DPoP proof (RFC 9449)
+	ClientCert   *x509.Certificate // mutual-TLS client certificate (RFC 8705)
 }
 
 // TokenResponse is the standard OAuth2 token response.
@@ -51,6 +87,12 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	IDToken      string `json:"id_token,omitempty"`
 	Scope        string `json:"scope,omitempty"`
+
+	// IssuedTokenType is only set for token exchange (RFC 8693 §2.2.1)
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+
+	// AuthorizationDetails granted to the access token (RFC 9396 §7)
+	AuthorizationDetails []map[string]any `json:"authorization_details,omitempty"`
 }
 
 // Token endpoint errors (OAuth2 standard).
@@ -63,6 +105,16 @@ var (
 	ErrTokenInvalidScope         = errors.New("invalid_scope")
 	ErrTokenServerError          = errors.New("server_error")
 	ErrTokenDBNotConfigured      = errors.New("db_not_configured")
+	ErrTokenInvalidTarget        = errors.New("invalid_target")         // RFC 8693 §2.2.2
+	ErrTokenInvalidRequestObject = errors.New("invalid_request_object") // RFC 9101 §6.3
+
+	ErrTokenInvalidAuthorizationDetails = errors.New("invalid_authorization_details") // RFC 9396 §5
+
+	// Device flow polling errors (RFC 8628 §3.5)
+	ErrTokenAuthorizationPending = errors.New("authorization_pending")
+	ErrTokenSlowDown             = errors.New("slow_down")
+	ErrTokenAccessDenied         = errors.New("access_denied")
+	ErrTokenExpiredToken         = errors.New("expired_token")
 )
 
 // AuthCodePayload is the cached authorization code data.
@@ -74,7 +126,13 @@ type AuthCodePayload struct {
 	Scope           string    `json:"scope"`
 	Nonce           string    `json:"nonce,omitempty"`
 	CodeChallenge   string    `json:"code_challenge"`
-	ChallengeMethod string    `json:"challenge_method"` // "S256"
+	ChallengeMethod string    `json:"code_challenge_method"` // "S256"; same key as dto.AuthCodePayload
 	AMR             []string  `json:"amr,omitempty"`
+	AuthTime        int64     `json:"auth_time,omitempty"`
+	SessionID       string    `json:"sid,omitempty"`
 	ExpiresAt       time.Time `json:"expires_at"`
+
+	Resource []string `json:"resource,omitempty"` // resource indicators granted at authorize (RFC 8707)
+
+	AuthorizationDetails []map[string]any `json:"authorization_details,omitempty"` // consented at authorize (RFC 9396)
 }
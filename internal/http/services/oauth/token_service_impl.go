@@ -3,6 +3,7 @@ package oauth
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,8 +12,10 @@ import (
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
@@ -23,6 +26,8 @@ type TokenDeps struct {
 	Issuer       *jwtx.Issuer
 	Cache        CacheClient
 	ControlPlane controlplane.Service
+	ClientAuth   ClientAuthService
+	ClientCAs    *x509.CertPool // roots for tls_client_auth (nil = trust the TLS terminator)
 	RefreshTTL   time.Duration
 }
 
@@ -32,6 +37,8 @@ type tokenService struct {
 	issuer     *jwtx.Issuer
 	cache      CacheClient
 	cp         controlplane.Service
+	clientAuth ClientAuthService
+	clientCAs  *x509.CertPool
 	refreshTTL time.Duration
 }
 
@@ -46,6 +53,8 @@ func NewTokenService(d TokenDeps) TokenService {
 		issuer:     d.Issuer,
 		cache:      d.Cache,
 		cp:         d.ControlPlane,
+		clientAuth: d.ClientAuth,
+		clientCAs:  d.ClientCAs,
 		refreshTTL: ttl,
 	}
 }
@@ -72,6 +81,18 @@ func (s *tokenService) ExchangeAuthorizationCode(ctx context.Context, req AuthCo
 		return nil, ErrTokenUnauthorizedClient
 	}
 
+	// Clients registered for JWT authentication must present an assertion
+	if err := s.verifyClientAssertion(ctx, tenantSlug, client, req.Assertion); err != nil {
+		log.Warn("invalid client assertion", logger.Err(err))
+		return nil, ErrTokenInvalidClient
+	}
+
+	// Clients registered for mutual TLS must present their certificate
+	if err := s.verifyTLSClientAuth(client, req.ClientCert); err != nil {
+		log.Warn("invalid client certificate", logger.Err(err))
+		return nil, ErrTokenInvalidClient
+	}
+
 	// Consume authorization code from cache (one-shot)
 	// Hardening: check for hashed code first
 	codeHash := tokens.SHA256Base64URL(req.Code)
@@ -115,10 +136,45 @@ func (s *tokenService) ExchangeAuthorizationCode(ctx context.Context, req AuthCo
 		return nil, ErrTokenInvalidGrant
 	}
 
+	// Resource indicators: the token targets resources granted at /authorize (RFC 8707 §2.2)
+	target, err := s.resolveResourceTarget(ctx, client, tenantSlug, resourceIndicators{Granted: ac.Resource, Requested: req.Resource}, strings.Fields(ac.Scope))
+	if err != nil {
+		log.Warn("invalid resource", logger.Err(err))
+		return nil, ErrTokenInvalidTarget
+	}
+
+	// Authorization details: the consented ones or the requested subset (RFC 9396 §6.1)
+	if target.Details, err = narrowAuthorizationDetails(ac.AuthorizationDetails, req.AuthorizationDetails); err != nil {
+		log.Warn("invalid authorization_details", logger.Err(err))
+		return nil, ErrTokenInvalidAuthorizationDetails
+	}
+	target.GrantedDetails = ac.AuthorizationDetails
+
+	resp, err := s.issueUserTokens(ctx, client, tenantSlug, ac.UserID, ac.Scope, ac.Nonce, ac.SessionID, ac.AMR, ac.AuthTime, tokenBinding{DPoPJKT: req.DPoPJKT, Cert: req.ClientCert}, target)
+	if err != nil {
+		log.Error("failed to issue tokens", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+
+	log.Info("authorization_code exchanged",
+		logger.TenantID(tenantSlug),
+		logger.String("client_id", req.ClientID),
+	)
+
+	return resp, nil
+}
+
+// issueUserTokens issues access, refresh and ID tokens for a user-bound grant
+// (authorization_code, device_code). The access token is bound to the DPoP key
+// and/or client certificate in binding; for public clients the refresh token is
+// bound to the DPoP key too. authTime (unix, 0 = unknown) becomes the auth_time claim
+// and sid (browser session, may be empty) lets the session's logout revoke the access token.
+// The access token is issued for target (resource indicators); the ID token always for the client.
+func (s *tokenService) issueUserTokens(ctx context.Context, client *repository.Client, tenantSlug, userID, scope, nonce, sid string, amr []string, authTime int64, binding tokenBinding, target *resourceTarget) (*TokenResponse, error) {
 	// Build access token claims
-	reqScopes := strings.Fields(ac.Scope)
+	reqScopes := strings.Fields(scope)
 	acrVal := "urn:hellojohn:loa:1"
-	for _, v := range ac.AMR {
+	for _, v := range amr {
 		if v == "mfa" {
 			acrVal = "urn:hellojohn:loa:2"
 			break
@@ -127,68 +183,94 @@ func (s *tokenService) ExchangeAuthorizationCode(ctx context.Context, req AuthCo
 
 	std := map[string]any{
 		"tid":   tenantSlug,
-		"amr":   ac.AMR,
+		"amr":   amr,
 		"acr":   acrVal,
-		"scope": strings.Join(reqScopes, " "),
-		"scp":   reqScopes,
+		"scope": strings.Join(target.Scopes, " "),
+		"scp":   target.Scopes,
+	}
+	if !target.Client {
+		// aud names the APIs; client_id keeps the client identifiable
+		std["client_id"] = client.ClientID
 	}
+	if authTime > 0 {
+		std["auth_time"] = authTime
+	}
+	if sid != "" {
+		std["sid"] = sid
+	}
+	bindCertificate(std, binding.Cert)
+	tokenType := bindDPoP(std, binding.DPoPJKT)
 	custom := map[string]any{}
 
 	// Resolve effective issuer for tenant
 	effIss := s.resolveEffectiveIssuer(ctx, tenantSlug)
 
-	// Issue access token with client-specific TTL (if configured)
-	access, exp, err := s.issuer.IssueAccessForTenantWithTTL(tenantSlug, effIss, ac.UserID, req.ClientID, std, custom, client.AccessTokenTTL)
+	// Pairwise clients see a per-sector sub; the refresh token keeps the local user ID
+	sub, err := pairwise.SubjectFor(client, userID)
 	if err != nil {
-		log.Error("failed to issue access token", logger.Err(err))
-		return nil, ErrTokenServerError
+		return nil, fmt.Errorf("pairwise subject: %w", err)
+	}
+
+	// Issue access token for the target audience with its TTL, signing algorithm and profile
+	access, exp, err := s.issueAccessToken(ctx, tenantSlug, effIss, sub, userID, client, target, std, custom)
+	if err != nil {
+		return nil, fmt.Errorf("issue access token: %w", err)
 	}
 
 	// Create refresh token with client-specific TTL
-	rawRT, err := s.createRefreshTokenWithTTL(ctx, tenantSlug, client.ClientID, ac.UserID, client.RefreshTokenTTL)
+	rawRT, err := s.createRefreshTokenWithTTL(ctx, tenantSlug, client.ClientID, userID, client.RefreshTokenTTL, refreshBinding(client, binding.DPoPJKT), "", sid, target.Granted, target.GrantedDetails)
 	if err != nil {
-		log.Error("failed to create refresh token", logger.Err(err))
-		return nil, ErrTokenServerError
+		return nil, fmt.Errorf("create refresh token: %w", err)
 	}
 
 	// Issue ID token with client-specific TTL (if configured)
 	idStd := map[string]any{
 		"tid":     tenantSlug,
 		"at_hash": atHash(access),
-		"azp":     req.ClientID,
+		"azp":     client.ClientID,
 		"acr":     acrVal,
-		"amr":     ac.AMR,
+		"amr":     amr,
+	}
+	// auth_time is required when max_age was requested (OIDC Core §2); always sent when known
+	if authTime > 0 {
+		idStd["auth_time"] = authTime
+	}
+	// sid ties the ID token to the browser session for back/front-channel logout
+	if sid != "" {
+		idStd["sid"] = sid
 	}
 	idExtra := map[string]any{}
-	if ac.Nonce != "" {
-		idExtra["nonce"] = ac.Nonce
+	if nonce != "" {
+		idExtra["nonce"] = nonce
 	}
 
 	// Enrich ID token with claims based on granted scopes
 	if tenantData, err := s.dal.ForTenant(ctx, tenantSlug); err == nil {
-		if user, err := tenantData.Users().GetByID(ctx, ac.UserID); err == nil {
+		if user, err := tenantData.Users().GetByID(ctx, userID); err == nil {
 			s.enrichClaimsFromScopes(ctx, idExtra, tenantSlug, user, reqScopes)
 		}
 	}
+	delete(idExtra, "sub") // a scope-mapped "sub" must not override the (pairwise) subject
 
-	idToken, _, err := s.issuer.IssueIDTokenForTenantWithTTL(tenantSlug, effIss, ac.UserID, req.ClientID, idStd, idExtra, client.IDTokenTTL)
+	idToken, _, err := s.issuer.IssueIDTokenForTenantWithAlg(tenantSlug, effIss, sub, client.ClientID, idStd, idExtra, client.IDTokenTTL, client.IDTokenSignedResponseAlg)
 	if err != nil {
-		log.Error("failed to issue id_token", logger.Err(err))
-		return nil, ErrTokenServerError
+		return nil, fmt.Errorf("issue id_token: %w", err)
+	}
+	if client.IDTokenEncryptedResponseAlg != "" {
+		if idToken, err = s.encryptIDToken(ctx, client, idToken); err != nil {
+			return nil, fmt.Errorf("encrypt id_token: %w", err)
+		}
 	}
-
-	log.Info("authorization_code exchanged",
-		logger.TenantID(tenantSlug),
-		logger.String("client_id", req.ClientID),
-	)
 
 	return &TokenResponse{
 		AccessToken:  access,
-		TokenType:    "Bearer",
+		TokenType:    tokenType,
 		ExpiresIn:    int64(time.Until(exp).Seconds()),
 		RefreshToken: rawRT,
 		IDToken:      idToken,
-		Scope:        ac.Scope,
+		Scope:        strings.Join(target.Scopes, " "),
+
+		AuthorizationDetails: target.Details,
 	}, nil
 }
 
@@ -213,6 +295,18 @@ func (s *tokenService) ExchangeRefreshToken(ctx context.Context, req RefreshToke
 		return nil, ErrTokenUnauthorizedClient
 	}
 
+	// Clients registered for JWT authentication must present an assertion
+	if err := s.verifyClientAssertion(ctx, tenantSlug, client, req.Assertion); err != nil {
+		log.Warn("invalid client assertion", logger.Err(err))
+		return nil, ErrTokenInvalidClient
+	}
+
+	// Clients registered for mutual TLS must present their certificate
+	if err := s.verifyTLSClientAuth(client, req.ClientCert); err != nil {
+		log.Warn("invalid client certificate", logger.Err(err))
+		return nil, ErrTokenInvalidClient
+	}
+
 	// Get tenant data access
 	tenantData, err := s.dal.ForTenant(ctx, tenantSlug)
 	if err != nil {
@@ -228,6 +322,13 @@ func (s *tokenService) ExchangeRefreshToken(ctx context.Context, req RefreshToke
 		return nil, ErrTokenInvalidGrant
 	}
 
+	// A revoked token presented again is a replay: revoke the whole family
+	if rt.RevokedAt != nil && (rt.ClientID == "" || rt.ClientID == client.ClientID) {
+		if helpers.HandleRefreshTokenReuse(ctx, tenantData, rt) {
+			return nil, ErrTokenInvalidGrant
+		}
+	}
+
 	// Validate refresh token
 	now := time.Now()
 	// NOTE: Checking clientID match if stored token has one
@@ -236,6 +337,23 @@ func (s *tokenService) ExchangeRefreshToken(ctx context.Context, req RefreshToke
 		return nil, ErrTokenInvalidGrant
 	}
 
+	// DPoP-bound refresh tokens require a proof with the same key (RFC 9449 §5)
+	if rt.DPoPJKT != "" && rt.DPoPJKT != req.DPoPJKT {
+		log.Warn("refresh token dpop key mismatch")
+		return nil, ErrTokenInvalidGrant
+	}
+
+	// The refreshed access token can only target resources of the original grant
+	target, err := s.resolveResourceTarget(ctx, client, tenantSlug, resourceIndicators{Granted: rt.Resources, Requested: req.Resource}, nil)
+	if err != nil {
+		log.Warn("invalid resource", logger.Err(err))
+		return nil, ErrTokenInvalidTarget
+	}
+	if target.Details, err = narrowAuthorizationDetails(rt.AuthorizationDetails, req.AuthorizationDetails); err != nil {
+		log.Warn("invalid authorization_details", logger.Err(err))
+		return nil, ErrTokenInvalidAuthorizationDetails
+	}
+
 	// Build access token claims
 	std := map[string]any{
 		"tid": tenantSlug,
@@ -243,13 +361,24 @@ func (s *tokenService) ExchangeRefreshToken(ctx context.Context, req RefreshToke
 		"acr": "urn:hellojohn:loa:1",
 		"scp": []string{},
 	}
+	if !target.Client {
+		std["client_id"] = client.ClientID
+	}
+	bindCertificate(std, req.ClientCert)
+	tokenType := bindDPoP(std, req.DPoPJKT)
 	custom := map[string]any{}
 
 	// Resolve effective issuer
 	effIss := s.resolveEffectiveIssuer(ctx, tenantSlug)
 
-	// Issue new access token with client-specific TTL
-	access, exp, err := s.issuer.IssueAccessForTenantWithTTL(tenantSlug, effIss, rt.UserID, req.ClientID, std, custom, client.AccessTokenTTL)
+	sub, err := pairwise.SubjectFor(client, rt.UserID)
+	if err != nil {
+		log.Error("failed to derive pairwise subject", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+
+	// Issue new access token for the target audience
+	access, exp, err := s.issueAccessToken(ctx, tenantSlug, effIss, sub, rt.UserID, client, target, std, custom)
 	if err != nil {
 		log.Error("failed to issue access token", logger.Err(err))
 		return nil, ErrTokenServerError
@@ -258,7 +387,8 @@ func (s *tokenService) ExchangeRefreshToken(ctx context.Context, req RefreshToke
 	// Rotate refresh token: revoke old, create new with client-specific TTL
 	_ = tenantData.Tokens().Revoke(ctx, rt.ID)
 
-	newRT, err := s.createRefreshTokenWithTTL(ctx, tenantSlug, client.ClientID, rt.UserID, client.RefreshTokenTTL)
+	// The rotated token keeps the original binding, session, resources and authorization details
+	newRT, err := s.createRefreshTokenWithTTL(ctx, tenantSlug, client.ClientID, rt.UserID, client.RefreshTokenTTL, rt.DPoPJKT, rt.ID, rt.SessionID, rt.Resources, rt.AuthorizationDetails)
 	if err != nil {
 		log.Error("failed to create new refresh token", logger.Err(err))
 		return nil, ErrTokenServerError
@@ -271,9 +401,11 @@ func (s *tokenService) ExchangeRefreshToken(ctx context.Context, req RefreshToke
 
 	return &TokenResponse{
 		AccessToken:  access,
-		TokenType:    "Bearer",
+		TokenType:    tokenType,
 		ExpiresIn:    int64(time.Until(exp).Seconds()),
 		RefreshToken: newRT,
+
+		AuthorizationDetails: target.Details,
 	}, nil
 }
 
@@ -304,8 +436,8 @@ func (s *tokenService) ExchangeClientCredentials(ctx context.Context, req Client
 		return nil, ErrTokenUnauthorizedClient
 	}
 
-	// Validate client secret
-	if err := s.validateClientSecret(ctx, tenantSlug, client, req.ClientSecret); err != nil {
+	// Authenticate client (secret or assertion)
+	if err := s.authenticateClient(ctx, tenantSlug, client, req.ClientSecret, req.Assertion, req.ClientCert); err != nil {
 		log.Warn("invalid client credentials")
 		return nil, ErrTokenInvalidClient
 	}
@@ -324,12 +456,21 @@ func (s *tokenService) ExchangeClientCredentials(ctx context.Context, req Client
 	}
 
 	// Determine scope output
-	var scopeOut string
-	if len(reqScopes) > 0 {
-		scopeOut = strings.Join(reqScopes, " ")
-	} else {
-		scopeOut = strings.Join(client.Scopes, " ")
+	if len(reqScopes) == 0 {
+		reqScopes = client.Scopes
+	}
+
+	// Resource indicators restrict aud and down-scope to the requested APIs (RFC 8707)
+	target, err := s.resolveResourceTarget(ctx, client, tenantSlug, resourceIndicators{Requested: req.Resource}, reqScopes)
+	if err != nil {
+		log.Warn("invalid resource", logger.Err(err))
+		return nil, ErrTokenInvalidTarget
+	}
+	if target.Details, err = resolveAuthorizationDetails(ctx, s.cp, tenantSlug, req.AuthorizationDetails); err != nil {
+		log.Warn("invalid authorization_details", logger.Err(err))
+		return nil, ErrTokenInvalidAuthorizationDetails
 	}
+	scopeOut := strings.Join(target.Scopes, " ")
 
 	// Build claims
 	std := map[string]any{
@@ -339,13 +480,18 @@ func (s *tokenService) ExchangeClientCredentials(ctx context.Context, req Client
 		"scp":   scopeOut,
 		"scope": scopeOut,
 	}
+	if !target.Client {
+		std["client_id"] = client.ClientID
+	}
+	bindCertificate(std, req.ClientCert)
+	tokenType := bindDPoP(std, req.DPoPJKT)
 	custom := map[string]any{}
 
 	// Resolve effective issuer
 	effIss := s.resolveEffectiveIssuer(ctx, tenantSlug)
 
-	// Issue access token (sub = clientID for M2M) with client-specific TTL
-	access, exp, err := s.issuer.IssueAccessForTenantWithTTL(tenantSlug, effIss, req.ClientID, req.ClientID, std, custom, client.AccessTokenTTL)
+	// Issue access token (sub = clientID for M2M) for the target audience
+	access, exp, err := s.issueAccessToken(ctx, tenantSlug, effIss, req.ClientID, "", client, target, std, custom)
 	if err != nil {
 		log.Error("failed to issue access token", logger.Err(err))
 		return nil, ErrTokenServerError
@@ -358,12 +504,102 @@ func (s *tokenService) ExchangeClientCredentials(ctx context.Context, req Client
 
 	return &TokenResponse{
 		AccessToken: access,
-		TokenType:   "Bearer",
+		TokenType:   tokenType,
 		ExpiresIn:   int64(time.Until(exp).Seconds()),
 		Scope:       scopeOut,
+
+		AuthorizationDetails: target.Details,
 	}, nil
 }
 
+// ExchangeDeviceCode handles grant_type=urn:ietf:params:oauth:grant-type:device_code (RFC 8628).
+func (s *tokenService) ExchangeDeviceCode(ctx context.Context, req DeviceCodeRequest) (*TokenResponse, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("oauth.token.device"))
+
+	if req.ClientID == "" || req.DeviceCode == "" {
+		return nil, ErrTokenInvalidRequest
+	}
+
+	// Lookup client
+	client, tenantSlug, err := s.lookupClient(ctx, req.TenantSlug, req.ClientID)
+	if err != nil {
+		log.Warn("client not found", logger.String("client_id", req.ClientID))
+		return nil, ErrTokenInvalidClient
+	}
+
+	// Device flow must be explicitly enabled for the client
+	if !hasGrantType(client, GrantTypeDeviceCode) {
+		log.Warn("grant_type not allowed for client", logger.String("grant_type", GrantTypeDeviceCode))
+		return nil, ErrTokenUnauthorizedClient
+	}
+
+	if err := s.authenticateClient(ctx, tenantSlug, client, req.ClientSecret, req.Assertion, req.ClientCert); err != nil {
+		log.Warn("invalid client credentials")
+		return nil, ErrTokenInvalidClient
+	}
+
+	deviceHash := tokens.SHA256Base64URL(req.DeviceCode)
+	dev, ok := loadDeviceCode(s.cache, deviceHash)
+	if !ok {
+		// Unknown or evicted after expiry
+		return nil, ErrTokenExpiredToken
+	}
+	if dev.ClientID != client.ClientID {
+		log.Warn("device_code issued to another client")
+		return nil, ErrTokenInvalidGrant
+	}
+
+	now := time.Now()
+	if !now.Before(dev.ExpiresAt) {
+		s.cache.Delete(cacheKeyPrefixDeviceCode + deviceHash)
+		return nil, ErrTokenExpiredToken
+	}
+
+	switch dev.Status {
+	case deviceStatusDenied:
+		s.cache.Delete(cacheKeyPrefixDeviceCode + deviceHash)
+		return nil, ErrTokenAccessDenied
+
+	case deviceStatusPending:
+		// Enforce polling interval: polling too fast bumps it (RFC 8628 §3.5)
+		tooFast := !dev.LastPolledAt.IsZero() && now.Sub(dev.LastPolledAt) < time.Duration(dev.Interval)*time.Second
+		dev.LastPolledAt = now
+		if tooFast {
+			dev.Interval += deviceSlowDownStep
+			storeDeviceCode(s.cache, deviceHash, dev)
+			return nil, ErrTokenSlowDown
+		}
+		storeDeviceCode(s.cache, deviceHash, dev)
+		return nil, ErrTokenAuthorizationPending
+
+	case deviceStatusApproved:
+		// One-shot: consume before issuing
+		s.cache.Delete(cacheKeyPrefixDeviceCode + deviceHash)
+		s.cache.Delete(cacheKeyPrefixDeviceUser + normalizeUserCode(dev.UserCode))
+
+	default:
+		return nil, ErrTokenInvalidGrant
+	}
+
+	target, err := s.resolveResourceTarget(ctx, client, tenantSlug, resourceIndicators{}, strings.Fields(dev.Scope))
+	if err != nil {
+		return nil, ErrTokenInvalidTarget
+	}
+
+	resp, err := s.issueUserTokens(ctx, client, tenantSlug, dev.UserID, dev.Scope, "", "", dev.AMR, dev.AuthTime, tokenBinding{DPoPJKT: req.DPoPJKT, Cert: req.ClientCert}, target)
+	if err != nil {
+		log.Error("failed to issue tokens", logger.Err(err))
+		return nil, ErrTokenServerError
+	}
+
+	log.Info("device_code exchanged",
+		logger.TenantID(tenantSlug),
+		logger.String("client_id", req.ClientID),
+	)
+
+	return resp, nil
+}
+
 // isGrantTypeAllowed checks if the grant_type is allowed for the client
 func isGrantTypeAllowed(client *repository.Client, grantType string) bool {
 	// If no grant_types are configured, allow all (backwards compatibility)
@@ -381,33 +617,20 @@ func isGrantTypeAllowed(client *repository.Client, grantType string) bool {
 // --- Helper methods ---
 
 func (s *tokenService) lookupClient(ctx context.Context, tenantSlug, clientID string) (*repository.Client, string, error) {
-	if s.cp == nil {
-		return nil, "", fmt.Errorf("control plane not initialized")
-	}
+	return findClient(ctx, s.cp, tenantSlug, clientID)
+}
 
-	// Try the provided tenant slug first
-	if tenantSlug != "" {
-		c, err := s.cp.GetClient(ctx, tenantSlug, clientID)
-		if err == nil && c != nil {
-			return c, tenantSlug, nil
-		}
+// encryptIDToken nests the signed ID token in a JWE for the client's encryption key
+// (id_token_encrypted_response_alg/enc).
+func (s *tokenService) encryptIDToken(ctx context.Context, client *repository.Client, idToken string) (string, error) {
+	if s.clientAuth == nil {
+		return "", fmt.Errorf("client keys not configured")
 	}
-
-	// Search across all tenants
-	tenants, err := s.cp.ListTenants(ctx)
+	key, err := s.clientAuth.EncryptionKey(ctx, client, client.IDTokenEncryptedResponseAlg)
 	if err != nil {
-		return nil, "", err
-	}
-	for _, t := range tenants {
-		if t.Slug == tenantSlug {
-			continue
-		}
-		c, err := s.cp.GetClient(ctx, t.Slug, clientID)
-		if err == nil && c != nil {
-			return c, t.Slug, nil
-		}
+		return "", err
 	}
-	return nil, "", fmt.Errorf("client not found")
+	return jwtx.EncryptNested(idToken, key, client.IDTokenEncryptedResponseAlg, client.IDTokenEncryptedResponseEnc)
 }
 
 func (s *tokenService) resolveEffectiveIssuer(ctx context.Context, tenantSlug string) string {
@@ -425,26 +648,52 @@ func (s *tokenService) resolveEffectiveIssuer(ctx context.Context, tenantSlug st
 }
 
 func (s *tokenService) validateClientSecret(ctx context.Context, tenantSlug string, client *repository.Client, providedSecret string) error {
-	if client.Type != repository.ClientTypeConfidential {
-		return nil // only confidential clients have secrets
+	return checkClientSecret(ctx, s.cp, tenantSlug, client, providedSecret)
+}
+
+// authenticateClient authenticates the client with its registered method:
+// mutual TLS, JWT assertion (private_key_jwt, client_secret_jwt) or client secret.
+func (s *tokenService) authenticateClient(ctx context.Context, tenantSlug string, client *repository.Client, providedSecret string, assertion ClientAssertion, cert *x509.Certificate) error {
+	if requiresTLSClientAuth(client) {
+		return verifyClientCertificate(client, cert, s.clientCAs)
 	}
-	dec, err := s.cp.DecryptClientSecret(ctx, tenantSlug, client.ClientID)
-	if err != nil {
-		return err
+	if requiresAssertion(client) || assertion.Present() {
+		return s.verifyClientAssertion(ctx, tenantSlug, client, assertion)
 	}
-	if dec == "" || !subtleEq(dec, providedSecret) {
-		return fmt.Errorf("invalid secret")
+	return s.validateClientSecret(ctx, tenantSlug, client, providedSecret)
+}
+
+// verifyClientAssertion verifies the assertion when one was sent or the client requires it.
+// Grants that don't otherwise authenticate the client (authorization_code, refresh_token)
+// call it directly so JWT-registered clients can't skip authentication.
+func (s *tokenService) verifyClientAssertion(ctx context.Context, tenantSlug string, client *repository.Client, assertion ClientAssertion) error {
+	if !requiresAssertion(client) && !assertion.Present() {
+		return nil
 	}
-	return nil
+	if s.clientAuth == nil {
+		return fmt.Errorf("client assertion not supported")
+	}
+	return s.clientAuth.Verify(ctx, tenantSlug, client, assertion)
+}
+
+// verifyTLSClientAuth verifies the client certificate when the client is registered for mutual TLS.
+func (s *tokenService) verifyTLSClientAuth(client *repository.Client, cert *x509.Certificate) error {
+	if !requiresTLSClientAuth(client) {
+		return nil
+	}
+	return verifyClientCertificate(client, cert, s.clientCAs)
 }
 
 func (s *tokenService) createRefreshToken(ctx context.Context, tenantSlug, clientID, userID string) (string, error) {
-	return s.createRefreshTokenWithTTL(ctx, tenantSlug, clientID, userID, 0)
+	return s.createRefreshTokenWithTTL(ctx, tenantSlug, clientID, userID, 0, "", "", "", nil, nil)
 }
 
 // createRefreshTokenWithTTL creates a refresh token with optional client-specific TTL.
-// If ttlSeconds <= 0, uses the default service TTL.
-func (s *tokenService) createRefreshTokenWithTTL(ctx context.Context, tenantSlug, clientID, userID string, ttlSeconds int) (string, error) {
+// If ttlSeconds <= 0, uses the default service TTL. A non-empty dpopJKT binds it to a DPoP key
+// and resources records the resource indicators of the grant (RFC 8707); details are
+// its authorization details (RFC 9396). sessionID links the token family to the
+// browser session it was issued under.
+func (s *tokenService) createRefreshTokenWithTTL(ctx context.Context, tenantSlug, clientID, userID string, ttlSeconds int, dpopJKT, rotatedFrom, sessionID string, resources []string, details []map[string]any) (string, error) {
 	// Generate opaque token
 	rawRT, err := tokens.GenerateOpaqueToken(32)
 	if err != nil {
@@ -468,11 +717,17 @@ func (s *tokenService) createRefreshTokenWithTTL(ctx context.Context, tenantSlug
 
 	// Create refresh token in repo
 	_, err = tenantData.Tokens().Create(ctx, repository.CreateRefreshTokenInput{
-		TenantID:   tenantSlug,
-		ClientID:   clientID,
-		UserID:     userID,
-		TokenHash:  tokenHash,
-		TTLSeconds: effectiveTTL,
+		TenantID:    tenantSlug,
+		ClientID:    clientID,
+		UserID:      userID,
+		TokenHash:   tokenHash,
+		TTLSeconds:  effectiveTTL,
+		DPoPJKT:     dpopJKT,
+		RotatedFrom: rotatedFrom,
+		SessionID:   sessionID,
+		Resources:   resources,
+
+		AuthorizationDetails: details,
 	})
 	if err != nil {
 		return "", fmt.Errorf("store refresh token: %w", err)
@@ -481,6 +736,22 @@ func (s *tokenService) createRefreshTokenWithTTL(ctx context.Context, tenantSlug
 	return rawRT, nil
 }
 
+// tokenBinding holds the proof-of-possession keys an access token is bound to.
+type tokenBinding struct {
+	DPoPJKT string            // RFC 9449
+	Cert    *x509.Certificate // RFC 8705
+}
+
+// refreshBinding returns the DPoP key a new refresh token is bound to.
+// Only public clients get bound refresh tokens; confidential clients are
+// already sender-constrained by client authentication (RFC 9449 §5).
+func refreshBinding(client *repository.Client, dpopJKT string) string {
+	if client.Type == repository.ClientTypeConfidential {
+		return ""
+	}
+	return dpopJKT
+}
+
 // atHash computes at_hash = base64url(left-most 128 bits of SHA-256(access_token))
 func atHash(accessToken string) string {
 	sum := sha256.Sum256([]byte(accessToken))
@@ -555,6 +826,11 @@ func (s *tokenService) enrichClaimsFromScopes(ctx context.Context, claims map[st
 		case "email":
 			claims["email"] = user.Email
 			claims["email_verified"] = user.EmailVerified
+		case "phone":
+			if user.PhoneNumber != "" {
+				claims["phone_number"] = user.PhoneNumber
+				claims["phone_number_verified"] = user.PhoneNumberVerified
+			}
 		}
 	}
 }
@@ -580,6 +856,14 @@ func getUserClaimValue(user *repository.User, claimName string) any {
 		return user.Email
 	case "email_verified":
 		return user.EmailVerified
+	case "phone_number":
+		if user.PhoneNumber != "" {
+			return user.PhoneNumber
+		}
+	case "phone_number_verified":
+		if user.PhoneNumber != "" {
+			return user.PhoneNumberVerified
+		}
 	case "picture":
 		if user.Picture != "" {
 			return user.Picture
@@ -3,10 +3,12 @@ package oidc
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/oidc"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
@@ -46,18 +48,24 @@ var (
 // Metadata OIDC común
 var (
 	responseTypesSupported            = []string{"code"}
-	grantTypesSupported               = []string{"authorization_code", "refresh_token", "client_credentials"}
-	subjectTypesSupported             = []string{"public"}
-	idTokenSigningAlgValuesSupported  = []string{"EdDSA"}
-	tokenEndpointAuthMethodsSupported = []string{"none", "client_secret_post", "client_secret_basic"}
+	grantTypesSupported               = []string{"authorization_code", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:device_code", "urn:ietf:params:oauth:grant-type:token-exchange"}
+	subjectTypesSupported             = []string{"public", "pairwise"}
+	idTokenSigningAlgValuesSupported  = jwtx.SigningAlgs
+	tokenEndpointAuthMethodsSupported = []string{"none", "client_secret_post", "client_secret_basic", "client_secret_jwt", "private_key_jwt", "tls_client_auth", "self_signed_tls_client_auth"}
+	tokenEndpointAuthSigningAlgs      = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA", "HS256", "HS384", "HS512"}
 	codeChallengeMethodsSupported     = []string{"S256"}
-	scopesSupported                   = []string{"openid", "email", "profile", "offline_access"}
+	scopesSupported                   = []string{"openid", "email", "profile", "phone", "offline_access"}
 	claimsSupported                   = []string{
 		"iss", "sub", "aud", "exp", "iat", "nbf",
 		"nonce", "auth_time", "acr", "amr",
-		"at_hash", "tid",
+		"at_hash", "tid", "sid",
 		"email", "email_verified",
+		"phone_number", "phone_number_verified",
 	}
+	promptValuesSupported             = []string{"none", "login", "consent", "select_account"}
+	acrValuesSupported                = []string{"urn:hellojohn:loa:1", "urn:hellojohn:loa:2"} // loa:2 = MFA
+	uiLocalesSupported                = []string{"en", "es"}
+	responseModesSupported            = []string{"query", "fragment", "form_post", "jwt", "query.jwt", "fragment.jwt", "form_post.jwt"}
 )
 
 func (s *discoveryService) GetGlobalDiscovery(ctx context.Context) dto.OIDCMetadata {
@@ -68,19 +76,45 @@ func (s *discoveryService) GetGlobalDiscovery(ctx context.Context) dto.OIDCMetad
 		UserinfoEndpoint:                  s.baseIssuer + "/userinfo",
 		JWKSURI:                           s.baseIssuer + "/.well-known/jwks.json",
 
-		// Endpoints opcionales (RFC 7009, RFC 7662)
+		// Endpoints opcionales (RFC 7009, RFC 7662, RP-Initiated Logout)
 		RevocationEndpoint:                s.baseIssuer + "/oauth2/revoke",
 		IntrospectionEndpoint:             s.baseIssuer + "/oauth2/introspect",
-		// EndSessionEndpoint queda vacío (no implementado aún)
+		EndSessionEndpoint:                s.baseIssuer + "/oauth2/logout", // OIDC RP-Initiated Logout
+		DeviceAuthorizationEndpoint:       s.baseIssuer + "/oauth2/device_authorization",
+		PushedAuthorizationRequestEndpoint: s.baseIssuer + "/oauth2/par",
 
 		ResponseTypesSupported:            responseTypesSupported,
 		GrantTypesSupported:               grantTypesSupported,
 		SubjectTypesSupported:             subjectTypesSupported,
 		IDTokenSigningAlgValuesSupported:  idTokenSigningAlgValuesSupported,
 		TokenEndpointAuthMethodsSupported: tokenEndpointAuthMethodsSupported,
+		TokenEndpointAuthSigningAlgValuesSupported: tokenEndpointAuthSigningAlgs,
+		DPoPSigningAlgValuesSupported:     jwtx.DPoPAlgs,
+		TLSClientCertificateBoundAccessTokens: true,
 		CodeChallengeMethodsSupported:     codeChallengeMethodsSupported,
 		ScopesSupported:                   scopesSupported,
 		ClaimsSupported:                   claimsSupported,
+		PromptValuesSupported:             promptValuesSupported,
+		ACRValuesSupported:                acrValuesSupported,
+		UILocalesSupported:                uiLocalesSupported,
+
+		BackchannelLogoutSupported:        true,
+		BackchannelLogoutSessionSupported: true,
+		FrontchannelLogoutSupported:       true,
+		FrontchannelLogoutSessionSupported: true,
+
+		RequestParameterSupported:                 true,
+		RequestObjectSigningAlgValuesSupported:    jwtx.RequestObjectSigningAlgs,
+		RequestObjectEncryptionAlgValuesSupported: jwtx.KeyEncryptionAlgs,
+		RequestObjectEncryptionEncValuesSupported: jwtx.ContentEncryptionAlgs,
+		ResponseModesSupported:                    responseModesSupported,
+		AuthorizationSigningAlgValuesSupported:    jwtx.SigningAlgs,
+
+		IDTokenEncryptionAlgValuesSupported:  jwtx.ResponseEncryptionAlgs,
+		IDTokenEncryptionEncValuesSupported:  jwtx.ContentEncryptionAlgs,
+		UserinfoSigningAlgValuesSupported:    jwtx.SigningAlgs,
+		UserinfoEncryptionAlgValuesSupported: jwtx.ResponseEncryptionAlgs,
+		UserinfoEncryptionEncValuesSupported: jwtx.ContentEncryptionAlgs,
 	}
 }
 
@@ -112,6 +146,19 @@ func (s *discoveryService) GetTenantDiscovery(ctx context.Context, slug string)
 		tenant.Settings.IssuerOverride,
 	)
 
+	// Dynamic Client Registration solo se anuncia si el tenant lo permite
+	var registrationEndpoint string
+	if cr := tenant.Settings.ClientRegistration; cr != nil &&
+		(cr.Mode == repository.ClientRegistrationOpen || cr.Mode == repository.ClientRegistrationInitialAccessToken) {
+		registrationEndpoint = s.baseIssuer + "/oauth2/register?tenant=" + url.QueryEscape(slug)
+	}
+
+	// Tipos de authorization_details registrados en el tenant (RFC 9396)
+	var authorizationDetailsTypes []string
+	for _, t := range tenant.Settings.AuthorizationDetailsTypes {
+		authorizationDetailsTypes = append(authorizationDetailsTypes, t.Type)
+	}
+
 	// Endpoints globales para compat, solo issuer y jwks_uri son por tenant
 	return dto.OIDCMetadata{
 		Issuer:                            iss,
@@ -120,18 +167,47 @@ func (s *discoveryService) GetTenantDiscovery(ctx context.Context, slug string)
 		UserinfoEndpoint:                  s.baseIssuer + "/userinfo",
 		JWKSURI:                           s.baseIssuer + "/.well-known/jwks/" + slug + ".json",
 
-		// Endpoints opcionales (RFC 7009, RFC 7662)
+		// Endpoints opcionales (RFC 7009, RFC 7662, RP-Initiated Logout)
 		RevocationEndpoint:                s.baseIssuer + "/oauth2/revoke",
 		IntrospectionEndpoint:             s.baseIssuer + "/oauth2/introspect",
-		// EndSessionEndpoint queda vacío (no implementado aún)
+		EndSessionEndpoint:                s.baseIssuer + "/oauth2/logout", // OIDC RP-Initiated Logout
+		DeviceAuthorizationEndpoint:       s.baseIssuer + "/oauth2/device_authorization",
+		PushedAuthorizationRequestEndpoint: s.baseIssuer + "/oauth2/par",
+		RegistrationEndpoint:              registrationEndpoint,
 
 		ResponseTypesSupported:            responseTypesSupported,
 		GrantTypesSupported:               grantTypesSupported,
 		SubjectTypesSupported:             subjectTypesSupported,
 		IDTokenSigningAlgValuesSupported:  idTokenSigningAlgValuesSupported,
 		TokenEndpointAuthMethodsSupported: tokenEndpointAuthMethodsSupported,
+		TokenEndpointAuthSigningAlgValuesSupported: tokenEndpointAuthSigningAlgs,
+		DPoPSigningAlgValuesSupported:     jwtx.DPoPAlgs,
+		TLSClientCertificateBoundAccessTokens: true,
 		CodeChallengeMethodsSupported:     codeChallengeMethodsSupported,
 		ScopesSupported:                   scopesSupported,
 		ClaimsSupported:                   claimsSupported,
+		PromptValuesSupported:             promptValuesSupported,
+		ACRValuesSupported:                acrValuesSupported,
+		UILocalesSupported:                uiLocalesSupported,
+
+		BackchannelLogoutSupported:        true,
+		BackchannelLogoutSessionSupported: true,
+		FrontchannelLogoutSupported:       true,
+		FrontchannelLogoutSessionSupported: true,
+
+		RequestParameterSupported:                 true,
+		RequestObjectSigningAlgValuesSupported:    jwtx.RequestObjectSigningAlgs,
+		RequestObjectEncryptionAlgValuesSupported: jwtx.KeyEncryptionAlgs,
+		RequestObjectEncryptionEncValuesSupported: jwtx.ContentEncryptionAlgs,
+		ResponseModesSupported:                    responseModesSupported,
+		AuthorizationSigningAlgValuesSupported:    jwtx.SigningAlgs,
+
+		IDTokenEncryptionAlgValuesSupported:  jwtx.ResponseEncryptionAlgs,
+		IDTokenEncryptionEncValuesSupported:  jwtx.ContentEncryptionAlgs,
+		UserinfoSigningAlgValuesSupported:    jwtx.SigningAlgs,
+		UserinfoEncryptionAlgValuesSupported: jwtx.ResponseEncryptionAlgs,
+		UserinfoEncryptionEncValuesSupported: jwtx.ContentEncryptionAlgs,
+
+		AuthorizationDetailsTypesSupported: authorizationDetailsTypes,
 	}, nil
 }
@@ -14,6 +14,7 @@ type Deps struct {
 	ControlPlane controlplane.Service
 	Issuer       *jwtx.Issuer
 	DAL          store.DataAccessLayer
+	ClientJWKS   *jwtx.RemoteJWKSCache // jwks_uri de clients (cifrado de userinfo)
 }
 
 // Services agrupa todos los services del dominio OIDC.
@@ -32,6 +33,7 @@ func NewServices(d Deps) Services {
 			Issuer:       d.Issuer,
 			ControlPlane: d.ControlPlane,
 			DAL:          d.DAL,
+			ClientJWKS:   d.ClientJWKS,
 		}),
 	}
 }
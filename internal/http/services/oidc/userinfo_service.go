@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	"github.com/dropDatabas3/hellojohn/internal/security/pairwise"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 	jwtv5 "github.com/golang-jwt/jwt/v5"
 )
@@ -26,6 +28,7 @@ type UserInfoDeps struct {
 	Issuer       *jwtx.Issuer
 	ControlPlane controlplane.Service
 	DAL          store.DataAccessLayer
+	ClientJWKS   *jwtx.RemoteJWKSCache // jwks_uri de clients; nil = cache propio
 }
 
 type userInfoService struct {
@@ -34,6 +37,9 @@ type userInfoService struct {
 
 // NewUserInfoService crea un nuevo servicio UserInfo.
 func NewUserInfoService(deps UserInfoDeps) UserInfoService {
+	if deps.ClientJWKS == nil {
+		deps.ClientJWKS = jwtx.NewRemoteJWKSCache(0)
+	}
 	return &userInfoService{deps: deps}
 }
 
@@ -43,6 +49,7 @@ var (
 	ErrInvalidToken   = fmt.Errorf("invalid or expired token")
 	ErrIssuerMismatch = fmt.Errorf("issuer mismatch")
 	ErrMissingSub     = fmt.Errorf("missing sub claim")
+	ErrResponseSecure = fmt.Errorf("userinfo response signing/encryption failed")
 )
 
 func (s *userInfoService) GetUserInfo(ctx context.Context, bearerToken string) (*dto.UserInfoResponse, error) {
@@ -53,7 +60,7 @@ func (s *userInfoService) GetUserInfo(ctx context.Context, bearerToken string) (
 	)
 
 	// 1) Validar token JWT
-	tk, err := jwtv5.Parse(bearerToken, s.deps.Issuer.Keyfunc(), jwtv5.WithValidMethods([]string{"EdDSA"}))
+	tk, err := jwtv5.Parse(bearerToken, s.deps.Issuer.Keyfunc(), jwtv5.WithValidMethods(jwtx.SigningAlgs))
 	if err != nil || !tk.Valid {
 		log.Debug("invalid token", logger.Err(err))
 		return nil, ErrInvalidToken
@@ -63,6 +70,10 @@ func (s *userInfoService) GetUserInfo(ctx context.Context, bearerToken string) (
 	if !ok {
 		return nil, ErrInvalidToken
 	}
+	if s.deps.Issuer.IsRevoked(ctx, claims) {
+		log.Debug("token revoked")
+		return nil, ErrInvalidToken
+	}
 
 	// 2) Validar issuer per-tenant
 	issStr, _ := claims["iss"].(string)
@@ -105,17 +116,82 @@ func (s *userInfoService) GetUserInfo(ctx context.Context, bearerToken string) (
 	}
 
 	if tda != nil {
-		user, err := tda.Users().GetByID(ctx, sub)
+		// El sub de un client pairwise no es el ID local: revertirlo para buscar al usuario
+		userID, err := pairwise.LocalSubjectFromClaims(claims, s.clientLookup(ctx, tda.Slug()))
+		if err != nil {
+			log.Debug("pairwise subject not resolved", logger.Err(err))
+			return nil, ErrInvalidToken
+		}
+		user, err := tda.Users().GetByID(ctx, userID)
 		if err == nil && user != nil {
 			s.populateUserInfo(resp, user, scopes)
 		} else if err != nil {
 			log.Debug("user not found", logger.Err(err))
 		}
+
+		// 7) Respuesta firmada y/o cifrada si el client la registró (OIDC Core §5.3.2)
+		clientID, _ := claims["client_id"].(string)
+		if lookup := s.clientLookup(ctx, tda.Slug()); lookup != nil && clientID != "" {
+			if client := lookup(clientID); client != nil && (client.UserinfoSignedResponseAlg != "" || client.UserinfoEncryptedResponseAlg != "") {
+				if issStr == "" {
+					issStr = s.deps.Issuer.Iss
+				}
+				if resp.JWT, err = s.secureResponse(ctx, tda.Slug(), issStr, client, resp); err != nil {
+					log.Error("userinfo response not secured", logger.Err(err), logger.ClientID(clientID))
+					return nil, ErrResponseSecure
+				}
+			}
+		}
 	}
 
 	return resp, nil
 }
 
+// secureResponse devuelve la respuesta como JWT firmado (userinfo_signed_response_alg)
+// y/o cifrado para el client (userinfo_encrypted_response_alg/enc). Firmado y
+// cifrado es un JWS anidado en el JWE.
+func (s *userInfoService) secureResponse(ctx context.Context, tenantSlug, iss string, client *repository.Client, resp *dto.UserInfoResponse) (string, error) {
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	cty := ""
+	if client.UserinfoSignedResponseAlg != "" {
+		var claims map[string]any
+		if err := json.Unmarshal(raw, &claims); err != nil {
+			return "", err
+		}
+		signed, err := s.deps.Issuer.IssueUserInfo(tenantSlug, iss, client.ClientID, claims, client.UserinfoSignedResponseAlg)
+		if err != nil {
+			return "", err
+		}
+		raw, cty = []byte(signed), "JWT"
+	}
+	if client.UserinfoEncryptedResponseAlg == "" {
+		return string(raw), nil
+	}
+
+	key, err := s.deps.ClientJWKS.EncryptionKey(ctx, client.JWKS, client.JWKSURI, client.UserinfoEncryptedResponseAlg)
+	if err != nil {
+		return "", err
+	}
+	return jwtx.EncryptJWE(raw, key, client.UserinfoEncryptedResponseAlg, client.UserinfoEncryptedResponseEnc, cty)
+}
+
+// clientLookup busca clients del tenant en el control plane.
+func (s *userInfoService) clientLookup(ctx context.Context, tenantSlug string) pairwise.ClientLookup {
+	if s.deps.ControlPlane == nil {
+		return nil
+	}
+	return func(clientID string) *repository.Client {
+		c, err := s.deps.ControlPlane.GetClient(ctx, tenantSlug, clientID)
+		if err != nil {
+			return nil
+		}
+		return c
+	}
+}
+
 func (s *userInfoService) resolveTenantStore(ctx context.Context, tid string) store.TenantDataAccess {
 	if tid == "" || s.deps.DAL == nil {
 		return nil
@@ -161,6 +237,13 @@ func (s *userInfoService) populateUserInfo(resp *dto.UserInfoResponse, user *rep
 		resp.EmailVerified = user.EmailVerified
 	}
 
+	// Teléfono solo si scope "phone" presente y el usuario tiene uno
+	if helpers.HasScope(scopes, "phone") && user.PhoneNumber != "" {
+		verified := user.PhoneNumberVerified
+		resp.PhoneNumber = user.PhoneNumber
+		resp.PhoneNumberVerified = &verified
+	}
+
 	// Custom fields siempre (para CompleteProfile flow)
 	finalCF := make(map[string]any)
 
@@ -0,0 +1,516 @@
+package passwordless
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+// Flow runs passwordless email logins. Start emails a one-time code or magic
+// link and returns a flow ID that only the starting device knows; Verify needs
+// both, so a link forwarded to (or opened on) another device can't complete the
+// login. Flows live in the tenant cache, are single-use and accept a limited
+// number of wrong codes. Starts are rate limited per email and per client IP,
+// and wrong codes across all flows of an email are capped as well. Start never
+// reveals whether the email has an account.
+type Flow interface {
+	// Start creates a flow for in.Email and sends the code or link.
+	Start(ctx context.Context, tda store.TenantDataAccess, in StartInput) (*Started, error)
+
+	// Verify completes a flow. Accounts are created here when the tenant
+	// allows signup; the email counts as verified either way.
+	Verify(ctx context.Context, tda store.TenantDataAccess, in VerifyInput) (*Verified, error)
+}
+
+// Methods
+const (
+	MethodLink = "link" // magic link (amr "email")
+	MethodOTP  = "otp"  // 6-digit email code (amr "otp")
+)
+
+// StartInput is the input of Flow.Start.
+type StartInput struct {
+	ClientID    string
+	Email       string
+	Method      string // MethodLink | MethodOTP
+	RedirectURI string // link target for JSON flows; must be a client redirect URI
+	ReturnTo    string // browser flows: where the session link endpoint returns to
+	ClientIP    string // for the per-IP start limit; empty = not limited by IP
+}
+
+// Started describes a started flow.
+type Started struct {
+	FlowID    string
+	Method    string
+	ExpiresIn int
+}
+
+// VerifyInput is the input of Flow.Verify.
+type VerifyInput struct {
+	FlowID string
+	Code   string // MethodOTP
+	Token  string // MethodLink
+}
+
+// Verified is a completed flow.
+type Verified struct {
+	UserID   string
+	ClientID string
+	Email    string
+	AMR      []string
+	ReturnTo string
+	Created  bool // the account was created by this login
+}
+
+// Errors
+var (
+	ErrNotEnabled      = errors.New("passwordless login not enabled for this tenant or client")
+	ErrInvalidRequest  = errors.New("invalid passwordless request")
+	ErrInvalidClient   = errors.New("invalid client")
+	ErrInvalidCode     = errors.New("code or link invalid or expired")
+	ErrTooManyAttempts = errors.New("too many failed attempts")
+	ErrRateLimited     = errors.New("too many passwordless requests")
+	ErrUserDisabled    = errors.New("user disabled")
+	ErrNoDatabase      = errors.New("no database for tenant")
+	ErrUnavailable     = errors.New("passwordless flow storage unavailable")
+)
+
+const (
+	// DefaultTTL applies when the tenant sets no TTLSeconds.
+	DefaultTTL = 10 * time.Minute
+	// DefaultMaxAttempts applies when the tenant sets no MaxAttempts.
+	DefaultMaxAttempts = 5
+
+	codeDigits     = 6
+	flowKeyPrefix  = "passwordless:flow:"
+	attemptsSuffix = ":attempts"
+	usedSuffix     = ":used"
+	flowIDBytes    = 32
+	linkTokenBytes = 32
+
+	// Start limits, per email and per client IP
+	startEmailRatePrefix = "passwordless:rl:email:"
+	startEmailMaxPerHour = 5
+	startEmailCooldown   = 30 * time.Second
+	startIPRatePrefix    = "passwordless:rl:ip:"
+	startIPMaxPerHour    = 30
+	startIPCooldown      = time.Second
+
+	// Wrong codes per email across all of its flows, so new flows don't
+	// restart the guessing budget
+	emailFailuresPrefix = "passwordless:fails:"
+	emailMaxFailures    = 10
+	emailFailuresWindow = time.Hour
+)
+
+// FlowDeps contains dependencies for the Flow.
+type FlowDeps struct {
+	Email   emailv2.Service // nil = nothing is sent (flows can't complete)
+	Lockout lockout.Guard   // nil = no account lockout
+}
+
+type flow struct {
+	email   emailv2.Service
+	lockout lockout.Guard
+}
+
+// NewFlow creates a new Flow.
+func NewFlow(d FlowDeps) Flow {
+	return &flow{email: d.Email, lockout: d.Lockout}
+}
+
+// flowState is the cached state of a flow, keyed by the hash of its ID.
+type flowState struct {
+	TenantID    string    `json:"tid"`
+	ClientID    string    `json:"cid"`
+	Email       string    `json:"email"`
+	UserID      string    `json:"uid,omitempty"` // empty: no account (yet)
+	Signup      bool      `json:"signup,omitempty"`
+	Method      string    `json:"method"`
+	SecretHash  string    `json:"secret"` // SHA-256 of the code or link token
+	RedirectURI string    `json:"redirect_uri,omitempty"`
+	ReturnTo    string    `json:"return_to,omitempty"`
+	ExpiresAt   time.Time `json:"exp"`
+}
+
+// config returns the tenant settings, or nil if passwordless is disabled.
+func config(tda store.TenantDataAccess) *repository.PasswordlessSettings {
+	settings := tda.Settings()
+	if settings == nil {
+		return nil
+	}
+	return settings.Passwordless
+}
+
+func ttlOf(cfg *repository.PasswordlessSettings) time.Duration {
+	if cfg.TTLSeconds > 0 {
+		return time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	return DefaultTTL
+}
+
+func maxAttemptsOf(cfg *repository.PasswordlessSettings) int {
+	if cfg.MaxAttempts > 0 {
+		return cfg.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+// Start implements Flow.
+func (f *flow) Start(ctx context.Context, tda store.TenantDataAccess, in StartInput) (*Started, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("passwordless.Start"), logger.TenantSlug(tda.Slug()))
+
+	cfg := config(tda)
+	if cfg == nil {
+		return nil, ErrNotEnabled
+	}
+	var provider string
+	switch in.Method {
+	case MethodLink:
+		if !cfg.MagicLinkEnabled {
+			return nil, ErrNotEnabled
+		}
+		provider = helpers.ProviderEmailLink
+	case MethodOTP:
+		if !cfg.EmailOTPEnabled {
+			return nil, ErrNotEnabled
+		}
+		provider = helpers.ProviderEmailOTP
+	default:
+		return nil, ErrInvalidRequest
+	}
+
+	email := strings.ToLower(strings.TrimSpace(in.Email))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, ErrInvalidRequest
+	}
+
+	client, err := tda.Clients().Get(ctx, tda.Slug(), in.ClientID)
+	if err != nil {
+		log.Debug("client not found", logger.Err(err))
+		return nil, ErrInvalidClient
+	}
+	if !helpers.IsProviderEnabled(client.Providers, provider) {
+		return nil, ErrNotEnabled
+	}
+	if in.Method == MethodLink {
+		// The link has to land somewhere that can finish the flow with the flow ID
+		if in.RedirectURI == "" && in.ReturnTo == "" {
+			return nil, ErrInvalidRequest
+		}
+		if in.RedirectURI != "" && !containsString(client.RedirectURIs, in.RedirectURI) {
+			return nil, ErrInvalidRequest
+		}
+	}
+
+	if err := tda.RequireDB(); err != nil {
+		return nil, ErrNoDatabase
+	}
+	c := tda.Cache()
+	if c == nil {
+		return nil, ErrUnavailable
+	}
+	if err := f.limitStart(ctx, tda, email, in.ClientIP); err != nil {
+		if !errors.Is(err, ErrRateLimited) {
+			log.Error("rate limit check failed", logger.Err(err))
+			return nil, ErrUnavailable
+		}
+		log.Debug("passwordless start rate limited")
+		return nil, err
+	}
+
+	flowID, err := tokens.GenerateOpaqueToken(flowIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	var code, linkToken, secret string
+	if in.Method == MethodOTP {
+		if code, err = newCode(); err != nil {
+			return nil, err
+		}
+		secret = code
+	} else {
+		if linkToken, err = tokens.GenerateOpaqueToken(linkTokenBytes); err != nil {
+			return nil, err
+		}
+		secret = linkToken
+	}
+
+	ttl := ttlOf(cfg)
+	state := flowState{
+		TenantID:    tda.ID(),
+		ClientID:    client.ClientID,
+		Email:       email,
+		Method:      in.Method,
+		SecretHash:  tokens.SHA256Base64URL(secret),
+		RedirectURI: in.RedirectURI,
+		ReturnTo:    in.ReturnTo,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+
+	// The flow is stored and the response is the same whether or not an email
+	// goes out: only the mailbox owner learns if the account exists.
+	deliver := false
+	user, _, err := tda.Users().GetByEmail(ctx, tda.ID(), email)
+	switch {
+	case err == nil:
+		state.UserID = user.ID
+		deliver = !helpers.IsUserDisabled(user) && (f.lockout == nil || f.lockout.Check(ctx, tda, user.ID, email) == nil)
+	case repository.IsNotFound(err):
+		state.Signup = cfg.AllowSignup
+		deliver = cfg.AllowSignup
+	default:
+		log.Error("user lookup failed", logger.Err(err))
+		return nil, err
+	}
+
+	payload, _ := json.Marshal(state)
+	if err := c.Set(ctx, flowKeyPrefix+tokens.SHA256Base64URL(flowID), string(payload), ttl); err != nil {
+		log.Error("failed to store flow", logger.Err(err))
+		return nil, ErrUnavailable
+	}
+
+	if deliver && f.email != nil {
+		req := emailv2.SendPasswordlessRequest{
+			TenantSlugOrID: tda.ID(),
+			Email:          email,
+			Token:          linkToken,
+			Code:           code,
+			LinkURL:        in.RedirectURI,
+			TTL:            ttl,
+		}
+		// Sent in the background so response time doesn't reveal the account either
+		go func(ctx context.Context) {
+			if err := f.email.SendPasswordlessEmail(ctx, req); err != nil {
+				log.Warn("passwordless email failed", logger.Err(err))
+			}
+		}(context.WithoutCancel(ctx))
+	}
+
+	log.Debug("passwordless flow started", logger.String("method", in.Method))
+	return &Started{FlowID: flowID, Method: in.Method, ExpiresIn: int(ttl.Seconds())}, nil
+}
+
+// Verify implements Flow.
+func (f *flow) Verify(ctx context.Context, tda store.TenantDataAccess, in VerifyInput) (*Verified, error) {
+	log := logger.From(ctx).With(logger.Layer("service"), logger.Op("passwordless.Verify"), logger.TenantSlug(tda.Slug()))
+
+	cfg := config(tda)
+	if cfg == nil {
+		return nil, ErrNotEnabled
+	}
+	if in.FlowID == "" || (in.Code == "" && in.Token == "") {
+		return nil, ErrInvalidRequest
+	}
+	c := tda.Cache()
+	if c == nil {
+		return nil, ErrUnavailable
+	}
+
+	key := flowKeyPrefix + tokens.SHA256Base64URL(in.FlowID)
+	raw, err := c.Get(ctx, key)
+	if err != nil {
+		return nil, ErrInvalidCode
+	}
+	var state flowState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil || state.TenantID != tda.ID() || time.Now().After(state.ExpiresAt) {
+		_ = c.Delete(ctx, key)
+		return nil, ErrInvalidCode
+	}
+
+	if f.lockout != nil {
+		if err := f.lockout.Check(ctx, tda, state.UserID, state.Email); err != nil {
+			return nil, err
+		}
+	}
+	if emailFailures(ctx, tda, state.Email) >= emailMaxFailures {
+		_ = c.Delete(ctx, key)
+		_ = c.Delete(ctx, key+attemptsSuffix)
+		return nil, ErrTooManyAttempts
+	}
+
+	secret := in.Code
+	if state.Method == MethodLink {
+		secret = in.Token
+	}
+	if secret == "" || subtle.ConstantTimeCompare([]byte(tokens.SHA256Base64URL(strings.TrimSpace(secret))), []byte(state.SecretHash)) != 1 {
+		return nil, f.verifyFailed(ctx, tda, key, &state, maxAttemptsOf(cfg))
+	}
+
+	// Single use: only the request that sets the marker consumes the flow, so
+	// concurrent verifies of the same code can't both log in
+	consumed, err := c.SetNX(ctx, key+usedSuffix, "1", time.Until(state.ExpiresAt))
+	if err != nil || !consumed {
+		return nil, ErrInvalidCode
+	}
+	_ = c.Delete(ctx, key)
+	_ = c.Delete(ctx, key+attemptsSuffix)
+	_ = c.Delete(ctx, emailFailuresKey(state.Email))
+
+	if state.UserID == "" && !state.Signup {
+		// Nothing was sent for an unknown email; a match here is a guess
+		return nil, ErrInvalidCode
+	}
+	if err := tda.RequireDB(); err != nil {
+		return nil, ErrNoDatabase
+	}
+
+	users := tda.Users()
+	res := &Verified{ClientID: state.ClientID, Email: state.Email, ReturnTo: state.ReturnTo}
+	if state.Method == MethodOTP {
+		res.AMR = []string{"otp"}
+	} else {
+		res.AMR = []string{"email"}
+	}
+
+	var user *repository.User
+	if state.UserID != "" {
+		if user, err = users.GetByID(ctx, state.UserID); err != nil {
+			log.Debug("user not found", logger.Err(err))
+			return nil, ErrInvalidCode
+		}
+	} else {
+		// Just-in-time signup: the email is proven, the account has no password
+		user, _, err = users.Create(ctx, repository.CreateUserInput{
+			TenantID:       tda.ID(),
+			Email:          state.Email,
+			SourceClientID: state.ClientID,
+		})
+		if repository.IsConflict(err) {
+			// Signed up in the meantime
+			user, _, err = users.GetByEmail(ctx, tda.ID(), state.Email)
+		} else if err == nil {
+			res.Created = true
+		}
+		if err != nil {
+			log.Error("signup failed", logger.Err(err))
+			return nil, fmt.Errorf("passwordless signup: %w", err)
+		}
+	}
+	if helpers.IsUserDisabled(user) {
+		return nil, ErrUserDisabled
+	}
+	if !user.EmailVerified {
+		if err := users.SetEmailVerified(ctx, user.ID, true); err != nil {
+			log.Warn("failed to mark email verified", logger.Err(err))
+		}
+	}
+	if f.lockout != nil {
+		f.lockout.RecordSuccess(ctx, tda, user.ID, state.Email)
+	}
+
+	res.UserID = user.ID
+	log.Info("passwordless login verified", logger.UserID(user.ID), logger.Bool("created", res.Created))
+	return res, nil
+}
+
+// limitStart applies the per-email and per-IP start limits, and refuses emails
+// that used up their wrong codes for the window.
+func (f *flow) limitStart(ctx context.Context, tda store.TenantDataAccess, email, clientIP string) error {
+	c := tda.Cache()
+	if emailFailures(ctx, tda, email) >= emailMaxFailures {
+		return ErrRateLimited
+	}
+	if clientIP != "" {
+		limiter := sms.Limiter{Cache: c, MaxPerHour: startIPMaxPerHour, Cooldown: startIPCooldown, Prefix: startIPRatePrefix}
+		if _, err := limiter.Allow(ctx, clientIP); err != nil {
+			return rateLimitErr(err)
+		}
+	}
+	limiter := sms.Limiter{Cache: c, MaxPerHour: startEmailMaxPerHour, Cooldown: startEmailCooldown, Prefix: startEmailRatePrefix}
+	if _, err := limiter.Allow(ctx, email); err != nil {
+		return rateLimitErr(err)
+	}
+	return nil
+}
+
+func rateLimitErr(err error) error {
+	if errors.Is(err, sms.ErrRateLimited) {
+		return ErrRateLimited
+	}
+	return err
+}
+
+// emailFailuresKey keeps the email out of the cache in clear.
+func emailFailuresKey(email string) string {
+	return emailFailuresPrefix + tokens.SHA256Base64URL(email)
+}
+
+// emailFailures returns the wrong codes counted for email in the current window.
+func emailFailures(ctx context.Context, tda store.TenantDataAccess, email string) int {
+	raw, err := tda.Cache().Get(ctx, emailFailuresKey(email))
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(raw)
+	return n
+}
+
+// verifyFailed counts a wrong code with an atomic counter next to the flow, so
+// concurrent guesses can't share an attempt, and another one per email across
+// its flows. The flow is dropped once either runs out; the failure also counts
+// towards the account lockout.
+func (f *flow) verifyFailed(ctx context.Context, tda store.TenantDataAccess, key string, state *flowState, maxAttempts int) error {
+	c := tda.Cache()
+	attempts, err := c.Incr(ctx, key+attemptsSuffix, time.Until(state.ExpiresAt))
+	if err == nil {
+		var total int64
+		total, err = c.Incr(ctx, emailFailuresKey(state.Email), emailFailuresWindow)
+		if total >= emailMaxFailures {
+			attempts = int64(maxAttempts)
+		}
+	}
+
+	var lockErr error
+	if f.lockout != nil {
+		lockErr = f.lockout.RecordFailure(ctx, tda, state.UserID, state.Email)
+	}
+	// Without the counter the flow can't stay open
+	if err != nil || lockErr != nil || attempts >= int64(maxAttempts) {
+		_ = c.Delete(ctx, key)
+		_ = c.Delete(ctx, key+attemptsSuffix)
+		if lockErr != nil {
+			return lockErr
+		}
+		if err != nil {
+			return ErrInvalidCode
+		}
+		return ErrTooManyAttempts
+	}
+	return ErrInvalidCode
+}
+
+// newCode returns a uniformly random 6-digit code.
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,517 @@
+package passwordless
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+)
+
+type clients struct {
+	repository.ClientRepository
+	client *repository.Client
+}
+
+func (c clients) Get(_ context.Context, _, clientID string) (*repository.Client, error) {
+	if c.client == nil || clientID != c.client.ClientID {
+		return nil, repository.ErrNotFound
+	}
+	return c.client, nil
+}
+
+type users struct {
+	repository.UserRepository
+	mu      sync.Mutex
+	byEmail map[string]*repository.User
+}
+
+func (u *users) GetByEmail(_ context.Context, _, email string) (*repository.User, *repository.Identity, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if user, ok := u.byEmail[email]; ok {
+		return user, nil, nil
+	}
+	return nil, nil, repository.ErrNotFound
+}
+
+func (u *users) GetByID(_ context.Context, id string) (*repository.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, user := range u.byEmail {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (u *users) Create(_ context.Context, in repository.CreateUserInput) (*repository.User, *repository.Identity, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.byEmail[in.Email]; ok {
+		return nil, nil, repository.ErrConflict
+	}
+	user := &repository.User{ID: "new-" + in.Email, Email: in.Email}
+	u.byEmail[in.Email] = user
+	return user, nil, nil
+}
+
+func (u *users) SetEmailVerified(_ context.Context, id string, verified bool) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, user := range u.byEmail {
+		if user.ID == id {
+			user.EmailVerified = verified
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+type flowTDA struct {
+	store.TenantDataAccess
+	settings *repository.TenantSettings
+	clients  clients
+	users    *users
+	cache    cache.Client
+}
+
+func (f *flowTDA) ID() string                                       { return "t1" }
+func (f *flowTDA) Slug() string                                     { return "acme" }
+func (f *flowTDA) Settings() *repository.TenantSettings             { return f.settings }
+func (f *flowTDA) Clients() repository.ClientRepository             { return f.clients }
+func (f *flowTDA) Users() repository.UserRepository                 { return f.users }
+func (f *flowTDA) Cache() cache.Client                              { return f.cache }
+func (f *flowTDA) RequireDB() error                                 { return nil }
+func (f *flowTDA) LoginAttempts() repository.LoginAttemptRepository { return nil }
+
+func newFlowTDA(cfg *repository.PasswordlessSettings) *flowTDA {
+	return &flowTDA{
+		settings: &repository.TenantSettings{Passwordless: cfg},
+		clients: clients{client: &repository.Client{
+			ClientID:     "web",
+			Providers:    []string{helpers.ProviderEmailLink, helpers.ProviderEmailOTP},
+			RedirectURIs: []string{"https://app.example.com/cb"},
+		}},
+		users: &users{byEmail: map[string]*repository.User{
+			"ana@example.com": {ID: "u1", Email: "ana@example.com"},
+		}},
+		cache: cache.NewMemory("test"),
+	}
+}
+
+func enabled() *repository.PasswordlessSettings {
+	return &repository.PasswordlessSettings{MagicLinkEnabled: true, EmailOTPEnabled: true}
+}
+
+// outbox records the passwordless emails sent in the background.
+type outbox struct {
+	emailv2.Service
+	sent chan emailv2.SendPasswordlessRequest
+}
+
+func newOutbox() *outbox {
+	return &outbox{sent: make(chan emailv2.SendPasswordlessRequest, 16)}
+}
+
+func (o *outbox) SendPasswordlessEmail(_ context.Context, req emailv2.SendPasswordlessRequest) error {
+	o.sent <- req
+	return nil
+}
+
+func (o *outbox) next(t *testing.T) emailv2.SendPasswordlessRequest {
+	t.Helper()
+	select {
+	case req := <-o.sent:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("no passwordless email sent")
+		return emailv2.SendPasswordlessRequest{}
+	}
+}
+
+func (o *outbox) none(t *testing.T) {
+	t.Helper()
+	select {
+	case req := <-o.sent:
+		t.Fatalf("unexpected email to %q", req.Email)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func startOTP(t *testing.T, f Flow, tda store.TenantDataAccess, email string) *Started {
+	t.Helper()
+	started, err := f.Start(context.Background(), tda, StartInput{ClientID: "web", Email: email, Method: MethodOTP})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return started
+}
+
+// wrongCode returns a 6-digit code different from code.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestFlow_OTP(t *testing.T) {
+	ctx := context.Background()
+	tda := newFlowTDA(enabled())
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail})
+
+	started := startOTP(t, f, tda, " Ana@Example.com ")
+	if started.Method != MethodOTP || started.ExpiresIn != int(DefaultTTL.Seconds()) {
+		t.Fatalf("Started = %+v", started)
+	}
+	req := mail.next(t)
+	if req.Email != "ana@example.com" || len(req.Code) != codeDigits || req.Token != "" {
+		t.Fatalf("email = %+v, want a 6-digit code to the normalized address", req)
+	}
+
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: wrongCode(req.Code)}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("wrong code: err = %v, want ErrInvalidCode", err)
+	}
+	res, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: req.Code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.UserID != "u1" || res.ClientID != "web" || res.Created || len(res.AMR) != 1 || res.AMR[0] != "otp" {
+		t.Fatalf("Verified = %+v", res)
+	}
+	if !tda.users.byEmail["ana@example.com"].EmailVerified {
+		t.Fatal("the email should be marked verified")
+	}
+
+	// Single use: the same code can't log in twice
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: req.Code}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("replay: err = %v, want ErrInvalidCode", err)
+	}
+}
+
+// Concurrent verifies of the same code: exactly one logs in.
+func TestFlow_ConcurrentVerifySingleUse(t *testing.T) {
+	ctx := context.Background()
+	tda := newFlowTDA(enabled())
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail})
+
+	started := startOTP(t, f, tda, "ana@example.com")
+	code := mail.next(t).Code
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+		ok int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: code}); err == nil {
+				mu.Lock()
+				ok++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if ok != 1 {
+		t.Fatalf("%d verifies succeeded, want 1", ok)
+	}
+}
+
+// The link only completes with the flow ID of the device that started it.
+func TestFlow_MagicLinkDeviceBinding(t *testing.T) {
+	ctx := context.Background()
+	tda := newFlowTDA(enabled())
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail})
+
+	in := StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodLink, RedirectURI: "https://app.example.com/cb", ClientIP: "198.51.100.1"}
+	first, err := f.Start(ctx, tda, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := mail.next(t)
+	if link.Token == "" || link.Code != "" || link.LinkURL != in.RedirectURI {
+		t.Fatalf("email = %+v, want a link token to the redirect URI", link)
+	}
+
+	// Another device started its own flow (the cooldown is per email, so use another account)
+	tda.users.byEmail["bob@example.com"] = &repository.User{ID: "u2", Email: "bob@example.com"}
+	in.Email, in.ClientIP = "bob@example.com", "198.51.100.2"
+	other, err := f.Start(ctx, tda, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mail.next(t)
+
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: other.FlowID, Token: link.Token}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("link on another flow: err = %v, want ErrInvalidCode", err)
+	}
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: "guessed", Token: link.Token}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("unknown flow: err = %v, want ErrInvalidCode", err)
+	}
+	res, err := f.Verify(ctx, tda, VerifyInput{FlowID: first.FlowID, Token: link.Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.UserID != "u1" || len(res.AMR) != 1 || res.AMR[0] != "email" {
+		t.Fatalf("Verified = %+v", res)
+	}
+}
+
+func TestFlow_StartRejects(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		cfg  *repository.PasswordlessSettings
+		in   StartInput
+		want error
+	}{
+		{"tenant disabled", nil, StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodOTP}, ErrNotEnabled},
+		{"otp off", &repository.PasswordlessSettings{MagicLinkEnabled: true}, StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodOTP}, ErrNotEnabled},
+		{"link off", &repository.PasswordlessSettings{EmailOTPEnabled: true}, StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodLink, RedirectURI: "https://app.example.com/cb"}, ErrNotEnabled},
+		{"unknown method", enabled(), StartInput{ClientID: "web", Email: "ana@example.com", Method: "sms"}, ErrInvalidRequest},
+		{"invalid email", enabled(), StartInput{ClientID: "web", Email: "Ana <ana@example.com>", Method: MethodOTP}, ErrInvalidRequest},
+		{"unknown client", enabled(), StartInput{ClientID: "other", Email: "ana@example.com", Method: MethodOTP}, ErrInvalidClient},
+		{"link without target", enabled(), StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodLink}, ErrInvalidRequest},
+		{"unregistered redirect", enabled(), StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodLink, RedirectURI: "https://evil.example.com/cb"}, ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tda := newFlowTDA(tt.cfg)
+			if _, err := NewFlow(FlowDeps{}).Start(ctx, tda, tt.in); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("client without provider", func(t *testing.T) {
+		tda := newFlowTDA(enabled())
+		tda.clients.client.Providers = []string{helpers.ProviderEmailLink}
+		in := StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodOTP}
+		if _, err := NewFlow(FlowDeps{}).Start(ctx, tda, in); !errors.Is(err, ErrNotEnabled) {
+			t.Fatalf("err = %v, want ErrNotEnabled", err)
+		}
+	})
+}
+
+func TestFlow_StartRateLimits(t *testing.T) {
+	ctx := context.Background()
+	tda := newFlowTDA(enabled())
+	f := NewFlow(FlowDeps{})
+
+	in := StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodOTP, ClientIP: "198.51.100.1"}
+	if _, err := f.Start(ctx, tda, in); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same email from another IP: within the per-email cooldown
+	in.ClientIP = "198.51.100.2"
+	if _, err := f.Start(ctx, tda, in); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("same email: err = %v, want ErrRateLimited", err)
+	}
+
+	// Another email from the first IP: within the per-IP cooldown
+	in.Email, in.ClientIP = "bob@example.com", "198.51.100.1"
+	if _, err := f.Start(ctx, tda, in); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("same IP: err = %v, want ErrRateLimited", err)
+	}
+
+	// Another email from a fresh IP goes through
+	in.ClientIP = "198.51.100.3"
+	if _, err := f.Start(ctx, tda, in); err != nil {
+		t.Fatalf("fresh email and IP: err = %v", err)
+	}
+}
+
+func TestFlow_MaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	cfg := enabled()
+	cfg.MaxAttempts = 3
+	tda := newFlowTDA(cfg)
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail})
+
+	started := startOTP(t, f, tda, "ana@example.com")
+	code := mail.next(t).Code
+
+	for i := 1; i < 3; i++ {
+		if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: wrongCode(code)}); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("wrong code %d: err = %v, want ErrInvalidCode", i, err)
+		}
+	}
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: wrongCode(code)}); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("wrong code 3: err = %v, want ErrTooManyAttempts", err)
+	}
+	// The flow is gone: the right code no longer works
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: code}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("after the limit: err = %v, want ErrInvalidCode", err)
+	}
+}
+
+// Wrong codes are capped per email across flows, so new flows don't restart
+// the guessing budget.
+func TestFlow_EmailFailureCap(t *testing.T) {
+	ctx := context.Background()
+	cfg := enabled()
+	cfg.MaxAttempts = 2 * emailMaxFailures
+	tda := newFlowTDA(cfg)
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail})
+
+	started := startOTP(t, f, tda, "ana@example.com")
+	code := mail.next(t).Code
+
+	var err error
+	for i := 0; i < emailMaxFailures; i++ {
+		_, err = f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: wrongCode(code)})
+	}
+	if !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("wrong code %d: err = %v, want ErrTooManyAttempts", emailMaxFailures, err)
+	}
+
+	if _, err := f.Start(ctx, tda, StartInput{ClientID: "web", Email: "ana@example.com", Method: MethodOTP}); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("new flow: err = %v, want ErrRateLimited", err)
+	}
+}
+
+// Wrong codes count towards the account lockout.
+func TestFlow_Lockout(t *testing.T) {
+	ctx := context.Background()
+	tda := newFlowTDA(enabled())
+	tda.settings.Security = &repository.SecurityPolicy{MaxLoginAttempts: 2}
+	attempts := &memAttempts{attempts: map[string]*repository.LoginAttempt{}}
+	ltda := &lockoutFlowTDA{flowTDA: tda, attempts: attempts}
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail, Lockout: lockout.NewGuard(lockout.GuardDeps{})})
+
+	started := startOTP(t, f, ltda, "ana@example.com")
+	code := mail.next(t).Code
+
+	if _, err := f.Verify(ctx, ltda, VerifyInput{FlowID: started.FlowID, Code: wrongCode(code)}); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("first wrong code: err = %v", err)
+	}
+	if _, err := f.Verify(ctx, ltda, VerifyInput{FlowID: started.FlowID, Code: wrongCode(code)}); !errors.Is(err, lockout.ErrLocked) {
+		t.Fatalf("second wrong code: err = %v, want lockout.ErrLocked", err)
+	}
+}
+
+func TestFlow_UnknownEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no signup", func(t *testing.T) {
+		tda := newFlowTDA(enabled())
+		mail := newOutbox()
+		f := NewFlow(FlowDeps{Email: mail})
+
+		// Same answer as for an account, but nothing is sent
+		started := startOTP(t, f, tda, "ghost@example.com")
+		if started.FlowID == "" {
+			t.Fatal("Start should return a flow for unknown emails")
+		}
+		mail.none(t)
+	})
+
+	t.Run("signup", func(t *testing.T) {
+		cfg := enabled()
+		cfg.AllowSignup = true
+		tda := newFlowTDA(cfg)
+		mail := newOutbox()
+		f := NewFlow(FlowDeps{Email: mail})
+
+		started := startOTP(t, f, tda, "new@example.com")
+		res, err := f.Verify(ctx, tda, VerifyInput{FlowID: started.FlowID, Code: mail.next(t).Code})
+		if err != nil {
+			t.Fatal(err)
+		}
+		user := tda.users.byEmail["new@example.com"]
+		if !res.Created || user == nil || res.UserID != user.ID || !user.EmailVerified {
+			t.Fatalf("Verified = %+v, user = %+v, want a new verified account", res, user)
+		}
+	})
+}
+
+func TestFlow_DisabledUser(t *testing.T) {
+	ctx := context.Background()
+	tda := newFlowTDA(enabled())
+	now := time.Now()
+	tda.users.byEmail["ana@example.com"].DisabledAt = &now
+	mail := newOutbox()
+	f := NewFlow(FlowDeps{Email: mail})
+
+	startOTP(t, f, tda, "ana@example.com")
+	mail.none(t)
+	if _, err := f.Verify(ctx, tda, VerifyInput{FlowID: "x"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("no code: err = %v, want ErrInvalidRequest", err)
+	}
+}
+
+// memAttempts is an in-memory LoginAttemptRepository for the lockout tests.
+type memAttempts struct {
+	mu       sync.Mutex
+	attempts map[string]*repository.LoginAttempt
+}
+
+func (m *memAttempts) Get(_ context.Context, key string) (*repository.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.attempts[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (m *memAttempts) RegisterFailure(_ context.Context, key string, _ time.Duration) (*repository.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.attempts[key]
+	if !ok {
+		a = &repository.LoginAttempt{Key: key}
+		m.attempts[key] = a
+	}
+	a.Failures++
+	cp := *a
+	return &cp, nil
+}
+
+func (m *memAttempts) Lock(_ context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a, ok := m.attempts[key]; ok {
+		a.LockedUntil = &until
+	}
+	return nil
+}
+
+func (m *memAttempts) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+func (m *memAttempts) ListLocked(context.Context) ([]repository.LoginAttempt, error) {
+	return nil, nil
+}
+
+type lockoutFlowTDA struct {
+	*flowTDA
+	attempts *memAttempts
+}
+
+func (f *lockoutFlowTDA) LoginAttempts() repository.LoginAttemptRepository { return f.attempts }
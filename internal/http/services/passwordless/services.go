@@ -0,0 +1,31 @@
+// Package passwordless implementa el login sólo con email: magic link o código
+// OTP de 6 dígitos (TenantSettings.Passwordless + providers "email_link" /
+// "email_otp" del client). Lo usan el login JSON (/v2/auth) y el de sesión que
+// alimenta /oauth2/authorize.
+package passwordless
+
+import (
+	emailv2 "github.com/dropDatabas3/hellojohn/internal/email"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+)
+
+// Deps contiene las dependencias para crear los services passwordless.
+type Deps struct {
+	Email   emailv2.Service
+	Lockout lockout.Guard // Account lockout (nil = deshabilitado)
+}
+
+// Services agrupa todos los services del dominio passwordless.
+type Services struct {
+	Flow Flow
+}
+
+// NewServices crea el agregador de services passwordless.
+func NewServices(d Deps) Services {
+	return Services{
+		Flow: NewFlow(FlowDeps{
+			Email:   d.Email,
+			Lockout: d.Lockout,
+		}),
+	}
+}
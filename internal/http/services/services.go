@@ -47,6 +47,7 @@
 package services
 
 import (
+	"crypto/x509"
 	"time"
 
 	controlplane "github.com/dropDatabas3/hellojohn/internal/controlplane"
@@ -56,12 +57,16 @@ import (
 	"github.com/dropDatabas3/hellojohn/internal/http/services/auth"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/email"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/health"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/oauth"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/oidc"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/security"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/session"
 	"github.com/dropDatabas3/hellojohn/internal/http/services/social"
 	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+	"github.com/dropDatabas3/hellojohn/internal/sms"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -99,6 +104,11 @@ type Deps struct {
 	OAuthCache       oauth.CacheClient
 	OAuthCookieName  string
 	OAuthAllowBearer bool
+	DPoPRequireNonce bool
+	MTLSClientCAs    *x509.CertPool
+
+	// ─── Teléfono ───
+	SMS sms.Sender // Provider de SMS/voz (nil = sin verificación de teléfono ni MFA SMS)
 }
 
 // Services agrupa todos los sub-services por dominio.
@@ -113,12 +123,36 @@ type Services struct {
 	Security security.Services
 	Health   health.Services
 	Social   social.Services
+	Logout   logout.Services  // Back/front-channel logout hacia los RPs
+	Lockout  lockout.Services // Bloqueo de cuentas por intentos fallidos
 }
 
 // New crea el agregador de services con todas las dependencias inyectadas.
 // Este es el único lugar donde se instancian los services.
 func New(d Deps) *Services {
+	// Logout se comparte: lo disparan admin, oauth (end_session) y session
+	logoutSvcs := logout.NewServices(logout.Deps{
+		ControlPlane: d.ControlPlane,
+		Issuer:       d.Issuer,
+	})
+
+	// Lockout se comparte: lo aplican auth, session y mfa; admin y email flows desbloquean
+	lockoutSvcs := lockout.NewServices(lockout.Deps{
+		Email: d.Email,
+	})
+
+	// Passwordless se comparte: login por email en auth (JSON) y session (/oauth2/authorize)
+	passwordlessSvcs := passwordless.NewServices(passwordless.Deps{
+		Email:   d.Email,
+		Lockout: lockoutSvcs.Guard,
+	})
+
+	// JWKS de clients (jwks_uri): claves de client assertions, request objects y cifrado de respuestas
+	clientJWKS := jwtx.NewRemoteJWKSCache(0)
+
 	return &Services{
+		Logout:  logoutSvcs,
+		Lockout: lockoutSvcs,
 		Admin: admin.NewServices(admin.Deps{
 			DAL:          d.DAL,
 			ControlPlane: d.ControlPlane,
@@ -126,6 +160,8 @@ func New(d Deps) *Services {
 			MasterKey:    d.MasterKey,
 			Issuer:       d.Issuer,
 			RefreshTTL:   d.RefreshTTL,
+			Logout:       logoutSvcs.Notifier,
+			Lockout:      lockoutSvcs.Guard,
 		}),
 		Auth: auth.NewServices(auth.Deps{
 			DAL:            d.DAL,
@@ -136,6 +172,9 @@ func New(d Deps) *Services {
 			FSAdminEnabled: d.FSAdminEnabled,
 			Email:          d.Email,
 			Social:         d.Social,
+			Lockout:        lockoutSvcs.Guard,
+			Passwordless:   passwordlessSvcs.Flow,
+			SMS:            d.SMS,
 		}),
 		OIDC: oidc.NewServices(oidc.Deps{
 			JWKSCache:    d.JWKSCache,
@@ -143,6 +182,7 @@ func New(d Deps) *Services {
 			ControlPlane: d.ControlPlane,
 			Issuer:       d.Issuer,
 			DAL:          d.DAL,
+			ClientJWKS:   clientJWKS,
 		}),
 		Health: health.NewServices(d.HealthDeps),
 		Social: d.Social,
@@ -154,11 +194,20 @@ func New(d Deps) *Services {
 			ControlPlane: d.ControlPlane,
 			CookieName:   d.OAuthCookieName,
 			AllowBearer:  d.OAuthAllowBearer,
+
+			DPoPRequireNonce: d.DPoPRequireNonce,
+			ClientCAs:        d.MTLSClientCAs,
+			Logout:           logoutSvcs.Notifier,
+			ClientJWKS:       clientJWKS,
 		}),
 		Session: session.NewServices(session.Deps{
-			Cache:        nil,
+			Cache:        newSessionCache(d.OAuthCache),
 			LogoutConfig: dto.SessionLogoutConfig{},
 			LoginConfig:  dto.LoginConfig{},
+			Logout:       logoutSvcs.Notifier,
+			Lockout:      lockoutSvcs.Guard,
+			DAL:          d.DAL,
+			Passwordless: passwordlessSvcs.Flow,
 		}),
 		Email: email.NewServices(email.Deps{
 			Email:          d.Email,
@@ -168,9 +217,35 @@ func New(d Deps) *Services {
 			AutoLoginReset: d.AutoLogin,
 			Policy:         nil, // Generar policy real si configuración lo requiere
 			Issuer:         nil, // Implementar TokenIssuer adapter para soporte AutoLogin
+			Lockout:        lockoutSvcs.Guard,
 		}),
 		Security: security.NewServices(security.Deps{
 			// Add security deps if any
 		}),
 	}
 }
+
+// sessionCache adapta el cache de OAuth al session.Cache: las sesiones que crea
+// /v2/session/* tienen que quedar donde /oauth2/authorize las lee.
+type sessionCache struct {
+	c oauth.CacheClient
+}
+
+func newSessionCache(c oauth.CacheClient) session.Cache {
+	if c == nil {
+		return nil
+	}
+	return sessionCache{c: c}
+}
+
+func (s sessionCache) Get(key string) ([]byte, bool) { return s.c.Get(key) }
+
+func (s sessionCache) Set(key string, value []byte, ttl time.Duration) error {
+	s.c.Set(key, value, ttl)
+	return nil
+}
+
+func (s sessionCache) Delete(key string) error {
+	s.c.Delete(key)
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	"github.com/dropDatabas3/hellojohn/internal/http/helpers"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
@@ -31,13 +33,15 @@ type LoginResult struct {
 
 // LoginDeps contains dependencies for the login service.
 type LoginDeps struct {
-	Cache  Cache
-	Config dto.LoginConfig
+	Cache   Cache
+	Config  dto.LoginConfig
+	Lockout lockout.Guard // nil = no account lockout
 }
 
 type loginService struct {
-	cache  Cache
-	config dto.LoginConfig
+	cache   Cache
+	config  dto.LoginConfig
+	lockout lockout.Guard
 }
 
 // NewLoginService creates a new LoginService.
@@ -50,8 +54,9 @@ func NewLoginService(deps LoginDeps) LoginService {
 		cfg.TTL = 24 * time.Hour
 	}
 	return &loginService{
-		cache:  deps.Cache,
-		config: cfg,
+		cache:   deps.Cache,
+		config:  cfg,
+		lockout: deps.Lockout,
 	}
 }
 
@@ -61,6 +66,8 @@ var (
 	ErrLoginMissingEmail       = fmt.Errorf("email is required")
 	ErrLoginMissingPassword    = fmt.Errorf("password is required")
 	ErrLoginInvalidCredentials = fmt.Errorf("invalid credentials")
+	ErrLoginUserDisabled       = fmt.Errorf("user disabled")
+	ErrLoginLocked             = lockout.ErrLocked
 	ErrLoginNoDatabase         = fmt.Errorf("database not available")
 	ErrLoginUserNotFound       = fmt.Errorf("user not found")
 	ErrLoginSessionFailed      = fmt.Errorf("failed to create session")
@@ -102,26 +109,37 @@ func (s *loginService) Login(ctx context.Context, tda store.TenantDataAccess, re
 	}
 	user, identity, err := usersRepo.GetByEmail(ctx, tenantIDForLookup, email)
 	if err != nil {
+		// Unknown emails count attempts too: a lockout doesn't reveal whether the account exists
 		log.Debug("user lookup failed", logger.Err(err))
-		return nil, ErrLoginInvalidCredentials
+		if err := lockout.CheckLogin(ctx, s.lockout, tda, "", email); err != nil {
+			return nil, err
+		}
+		return nil, lockout.LoginFailed(ctx, s.lockout, tda, "", email, ErrLoginInvalidCredentials)
 	}
+
+	// Account lockout (SecurityPolicy), before the password is checked
+	if err := lockout.CheckLogin(ctx, s.lockout, tda, user.ID, email); err != nil {
+		log.Info("account locked")
+		return nil, err
+	}
+	if helpers.IsUserDisabled(user) {
+		log.Info("user disabled")
+		return nil, ErrLoginUserDisabled
+	}
+
 	if identity == nil || identity.PasswordHash == nil {
 		log.Debug("identity not found or no password")
-		return nil, ErrLoginInvalidCredentials
+		return nil, lockout.LoginFailed(ctx, s.lockout, tda, user.ID, email, ErrLoginInvalidCredentials)
 	}
 
 	// Verify password
 	if !usersRepo.CheckPassword(identity.PasswordHash, password) {
 		log.Debug("password mismatch")
-		return nil, ErrLoginInvalidCredentials
-	}
-
-	// Generate session ID
-	sessionID, err := tokens.GenerateOpaqueToken(32)
-	if err != nil {
-		log.Error("failed to generate session ID", logger.Err(err))
-		return nil, ErrLoginSessionFailed
+		return nil, lockout.LoginFailed(ctx, s.lockout, tda, user.ID, email, ErrLoginInvalidCredentials)
 	}
+	// Upgrade imported hashes (bcrypt, PBKDF2, ...) to argon2id
+	helpers.UpgradePasswordHash(ctx, usersRepo, user.ID, identity.PasswordHash, password)
+	lockout.LoginSucceeded(ctx, s.lockout, tda, user.ID, email)
 
 	// Create session payload (use user's tenant or request tenant)
 	tenantID := user.TenantID
@@ -129,29 +147,50 @@ func (s *loginService) Login(ctx context.Context, tda store.TenantDataAccess, re
 		tenantID = req.TenantID
 	}
 
-	expiresAt := time.Now().Add(s.config.TTL)
+	result, err := createSession(s.cache, s.config.TTL, user.ID, tenantID, []string{"pwd"})
+	if err != nil {
+		log.Error("failed to create session", logger.Err(err))
+		return nil, ErrLoginSessionFailed
+	}
+
+	log.Debug("session created",
+		zap.String("user_id", user.ID),
+		zap.String("tenant_id", tenantID),
+	)
+
+	return result, nil
+}
+
+// createSession stores a new session in the cache under "sid:" + hash(ID), where
+// /oauth2/authorize reads it, and returns the raw ID for the cookie.
+func createSession(c Cache, ttl time.Duration, userID, tenantID string, amr []string) (*LoginResult, error) {
+	if c == nil {
+		return nil, fmt.Errorf("no session cache configured")
+	}
+	sessionID, err := tokens.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate session ID: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
 	payload := dto.SessionPayload{
-		UserID:   user.ID,
+		UserID:   userID,
 		TenantID: tenantID,
 		Expires:  expiresAt,
+		AuthTime: now,
+		AMR:      amr,
 	}
 
-	// Store in cache
 	key := "sid:" + tokens.SHA256Base64URL(sessionID)
 	payloadBytes, _ := json.Marshal(payload)
-	if err := s.cache.Set(key, payloadBytes, s.config.TTL); err != nil {
-		log.Error("failed to store session in cache", logger.Err(err))
-		return nil, ErrLoginSessionFailed
+	if err := c.Set(key, payloadBytes, ttl); err != nil {
+		return nil, fmt.Errorf("store session: %w", err)
 	}
 
-	log.Debug("session created",
-		zap.String("user_id", user.ID),
-		zap.String("tenant_id", tenantID),
-	)
-
 	return &LoginResult{
 		SessionID: sessionID,
-		UserID:    user.ID,
+		UserID:    userID,
 		TenantID:  tenantID,
 		ExpiresAt: expiresAt,
 	}, nil
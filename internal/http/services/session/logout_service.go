@@ -2,10 +2,12 @@ package session
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"time"
 
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
 	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
 	tokens "github.com/dropDatabas3/hellojohn/internal/security/token"
 	"go.uber.org/zap"
@@ -28,6 +30,7 @@ type SessionLogoutService interface {
 type SessionLogoutDeps struct {
 	Cache  Cache
 	Config dto.SessionLogoutConfig
+	Logout logout.Notifier // optional: back-channel logout to relying parties
 }
 
 type sessionLogoutService struct {
@@ -47,13 +50,20 @@ func (s *sessionLogoutService) Logout(ctx context.Context, sessionID string) err
 		logger.Op("Logout"),
 	)
 
-	if sessionID == "" {
+	if sessionID == "" || s.deps.Cache == nil {
 		log.Debug("no session ID provided, skipping cache delete")
 		return nil
 	}
 
 	// Build cache key using same hash as session_login
-	key := "sid:" + tokens.SHA256Base64URL(sessionID)
+	sidHash := tokens.SHA256Base64URL(sessionID)
+	key := "sid:" + sidHash
+
+	// Read the owner before deleting so the relying parties can be notified
+	var payload dto.SessionPayload
+	if b, ok := s.deps.Cache.Get(key); ok {
+		_ = json.Unmarshal(b, &payload)
+	}
 
 	if err := s.deps.Cache.Delete(key); err != nil {
 		// Log but don't fail - logout should be best-effort
@@ -62,6 +72,10 @@ func (s *sessionLogoutService) Logout(ctx context.Context, sessionID string) err
 		log.Debug("session deleted from cache")
 	}
 
+	if s.deps.Logout != nil && payload.UserID != "" {
+		s.deps.Logout.Backchannel(ctx, payload.TenantID, payload.UserID, sidHash)
+	}
+
 	return nil
 }
 
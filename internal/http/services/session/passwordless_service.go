@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
+	"go.uber.org/zap"
+)
+
+// PasswordlessService defines passwordless email login for browser sessions:
+// the session it creates carries the email AMR, so /oauth2/authorize still asks
+// for the second factor when the user has one.
+type PasswordlessService interface {
+	// Start emails the code or link and returns the flow (its ID goes in a cookie).
+	Start(ctx context.Context, req dto.PasswordlessStartRequest) (*passwordless.Started, error)
+
+	// Verify completes the flow with a code (otp) or token (link) and creates the session.
+	Verify(ctx context.Context, tenantID string, in passwordless.VerifyInput) (*PasswordlessResult, error)
+}
+
+// PasswordlessResult is a completed passwordless session login.
+type PasswordlessResult struct {
+	LoginResult
+	ReturnTo string
+}
+
+// PasswordlessDeps contains dependencies for the passwordless session service.
+type PasswordlessDeps struct {
+	DAL    store.DataAccessLayer
+	Flow   passwordless.Flow
+	Cache  Cache
+	Config dto.LoginConfig
+}
+
+type passwordlessService struct {
+	deps PasswordlessDeps
+}
+
+// NewPasswordlessService creates a new PasswordlessService.
+func NewPasswordlessService(deps PasswordlessDeps) PasswordlessService {
+	if deps.Config.TTL <= 0 {
+		deps.Config.TTL = 24 * time.Hour
+	}
+	return &passwordlessService{deps: deps}
+}
+
+// Service errors
+var (
+	ErrPasswordlessMissingFields = fmt.Errorf("tenant_id and client_id are required")
+	ErrPasswordlessInvalidTenant = fmt.Errorf("invalid tenant")
+)
+
+// Start implements PasswordlessService.
+func (s *passwordlessService) Start(ctx context.Context, req dto.PasswordlessStartRequest) (*passwordless.Started, error) {
+	if strings.TrimSpace(req.ClientID) == "" {
+		return nil, ErrPasswordlessMissingFields
+	}
+	tda, err := s.tenant(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.deps.Flow.Start(ctx, tda, passwordless.StartInput{
+		ClientID: strings.TrimSpace(req.ClientID),
+		Email:    req.Email,
+		Method:   req.Method,
+		ReturnTo: req.ReturnTo,
+		ClientIP: req.ClientIP,
+	})
+}
+
+// Verify implements PasswordlessService.
+func (s *passwordlessService) Verify(ctx context.Context, tenantID string, in passwordless.VerifyInput) (*PasswordlessResult, error) {
+	log := logger.From(ctx).With(
+		logger.Layer("service"),
+		logger.Component("session.passwordless"),
+		logger.Op("Verify"),
+	)
+
+	tda, err := s.tenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.deps.Flow.Verify(ctx, tda, in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keyed by slug: it's what /oauth2/authorize matches the session against
+	session, err := createSession(s.deps.Cache, s.deps.Config.TTL, res.UserID, tda.Slug(), res.AMR)
+	if err != nil {
+		log.Error("failed to create session", logger.Err(err))
+		return nil, ErrLoginSessionFailed
+	}
+
+	log.Debug("session created",
+		zap.String("user_id", res.UserID),
+		zap.Strings("amr", res.AMR),
+	)
+	return &PasswordlessResult{LoginResult: *session, ReturnTo: res.ReturnTo}, nil
+}
+
+func (s *passwordlessService) tenant(ctx context.Context, tenantID string) (store.TenantDataAccess, error) {
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return nil, ErrPasswordlessMissingFields
+	}
+	tda, err := s.deps.DAL.ForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, ErrPasswordlessInvalidTenant
+	}
+	return tda, nil
+}
@@ -3,6 +3,10 @@ package session
 
 import (
 	dto "github.com/dropDatabas3/hellojohn/internal/http/dto/session"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/lockout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/logout"
+	"github.com/dropDatabas3/hellojohn/internal/http/services/passwordless"
+	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
 // Deps contiene las dependencias para crear los services session.
@@ -10,24 +14,45 @@ type Deps struct {
 	Cache        Cache
 	LogoutConfig dto.SessionLogoutConfig
 	LoginConfig  dto.LoginConfig
+	Logout       logout.Notifier
+	Lockout      lockout.Guard // Account lockout (nil = deshabilitado)
+
+	// Login por email para /oauth2/authorize (Passwordless nil = deshabilitado)
+	DAL          store.DataAccessLayer
+	Passwordless passwordless.Flow
 }
 
 // Services agrupa todos los services del dominio session.
 type Services struct {
 	Logout SessionLogoutService
 	Login  LoginService
+
+	Passwordless PasswordlessService // nil si no hay Flow
 }
 
 // NewServices crea el agregador de services session.
 func NewServices(d Deps) Services {
+	var pwl PasswordlessService
+	if d.Passwordless != nil {
+		pwl = NewPasswordlessService(PasswordlessDeps{
+			DAL:    d.DAL,
+			Flow:   d.Passwordless,
+			Cache:  d.Cache,
+			Config: d.LoginConfig,
+		})
+	}
+
 	return Services{
 		Logout: NewSessionLogoutService(SessionLogoutDeps{
 			Cache:  d.Cache,
 			Config: d.LogoutConfig,
+			Logout: d.Logout,
 		}),
 		Login: NewLoginService(LoginDeps{
-			Cache:  d.Cache,
-			Config: d.LoginConfig,
+			Cache:   d.Cache,
+			Config:  d.LoginConfig,
+			Lockout: d.Lockout,
 		}),
+		Passwordless: pwl,
 	}
 }
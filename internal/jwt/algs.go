@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"fmt"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritmos con los que el servidor firma tokens.
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+// SigningAlgs son los algoritmos soportados para firmar; también los que se
+// aceptan al verificar tokens propios. EdDSA es el default.
+var SigningAlgs = []string{AlgEdDSA, AlgRS256, AlgES256}
+
+// IsSigningAlgSupported indica si alg es un algoritmo de firma soportado.
+func IsSigningAlgSupported(alg string) bool {
+	for _, a := range SigningAlgs {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestObjectSigningAlgs son los algoritmos aceptados en request objects
+// firmados por clients (RFC 9101). Solo asimétricos: se verifican con su JWKS.
+var RequestObjectSigningAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// IsRequestObjectAlgSupported indica si alg se acepta para firmar request objects.
+func IsRequestObjectAlgSupported(alg string) bool {
+	for _, a := range RequestObjectSigningAlgs {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// SigningMethodFor devuelve el método jwtv5 para alg ("" = EdDSA).
+func SigningMethodFor(alg string) (jwtv5.SigningMethod, error) {
+	switch alg {
+	case "", AlgEdDSA:
+		return jwtv5.SigningMethodEdDSA, nil
+	case AlgRS256:
+		return jwtv5.SigningMethodRS256, nil
+	case AlgES256:
+		return jwtv5.SigningMethodES256, nil
+	}
+	return nil, fmt.Errorf("unsupported signing alg: %s", alg)
+}
+
+// Algoritmos JWE (RFC 7516/7518) para contenido cifrado hacia el servidor
+// (request objects de JAR). La clave es RSA y se publica en el JWKS con use=enc.
+const (
+	AlgRSAOAEP    = "RSA-OAEP"
+	AlgRSAOAEP256 = "RSA-OAEP-256"
+
+	EncA128GCM      = "A128GCM"
+	EncA256GCM      = "A256GCM"
+	EncA128CBCHS256 = "A128CBC-HS256"
+	EncA256CBCHS512 = "A256CBC-HS512"
+)
+
+// KeyEncryptionAlgs son los "alg" JWE aceptados al descifrar.
+var KeyEncryptionAlgs = []string{AlgRSAOAEP256, AlgRSAOAEP}
+
+// ContentEncryptionAlgs son los "enc" JWE soportados.
+var ContentEncryptionAlgs = []string{EncA128GCM, EncA256GCM, EncA128CBCHS256, EncA256CBCHS512}
+
+// AlgECDHES es el acuerdo de clave ECDH-ES directo (RFC 7518 §4.6), usado para
+// cifrar respuestas hacia clients con claves EC.
+const AlgECDHES = "ECDH-ES"
+
+// ResponseEncryptionAlgs son los "alg" JWE con que se cifran ID Tokens y
+// respuestas de userinfo hacia el client (con su clave pública use=enc).
+var ResponseEncryptionAlgs = []string{AlgRSAOAEP256, AlgRSAOAEP, AlgECDHES}
+
+// DefaultResponseEncryptionEnc es el "enc" cuando el client solo registra el alg
+// (OIDC Registration §2).
+const DefaultResponseEncryptionEnc = EncA128CBCHS256
+
+// IsResponseEncryptionSupported indica si alg/enc sirven para cifrar respuestas.
+// enc vacío equivale a DefaultResponseEncryptionEnc.
+func IsResponseEncryptionSupported(alg, enc string) bool {
+	if enc == "" {
+		enc = DefaultResponseEncryptionEnc
+	}
+	okAlg, okEnc := false, false
+	for _, a := range ResponseEncryptionAlgs {
+		okAlg = okAlg || a == alg
+	}
+	for _, e := range ContentEncryptionAlgs {
+		okEnc = okEnc || e == enc
+	}
+	return okAlg && okEnc
+}
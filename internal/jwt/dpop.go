@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// DPoPProofType es el "typ" requerido en el header de un DPoP proof (RFC 9449 §4.2).
+const DPoPProofType = "dpop+jwt"
+
+// DPoPAlgs son los algoritmos aceptados para DPoP proofs (solo asimétricos).
+var DPoPAlgs = []string{"ES256", "ES384", "ES512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "EdDSA"}
+
+const (
+	dpopDefaultMaxAge = 5 * time.Minute
+	dpopLeeway        = 30 * time.Second
+	dpopReplayPrefix  = "dpop:jti:"
+)
+
+// Errores DPoP.
+var (
+	ErrDPoPInvalid = errors.New("invalid_dpop_proof")
+	ErrDPoPReplay  = errors.New("dpop proof replayed")
+)
+
+// DPoPReplayCache registra los jti de proofs ya usados.
+// SetNX debe ser atómico: retorna false si la key ya existía.
+type DPoPReplayCache interface {
+	SetNX(key string, value []byte, ttl time.Duration) bool
+}
+
+// DPoPVerifyOptions define qué se espera del proof.
+type DPoPVerifyOptions struct {
+	Method      string          // htm esperado (ej. "POST")
+	URL         string          // htu esperado (sin query ni fragment)
+	AccessToken string          // si no es vacío, el proof debe llevar ath = b64url(sha256(token))
+	Replay      DPoPReplayCache // opcional: protección anti-replay por jti
+	MaxAge      time.Duration   // ventana de iat aceptada (default 5m)
+}
+
+// DPoPProof son los datos de un DPoP proof verificado.
+type DPoPProof struct {
+	JKT      string // JWK SHA-256 thumbprint de la clave pública del proof
+	JTI      string
+	IssuedAt time.Time
+	Nonce    string // nonce del server, si vino; lo valida el caller
+}
+
+// VerifyDPoPProof valida un DPoP proof (RFC 9449 §4.3): typ, alg asimétrico,
+// firma con el jwk del header, htm, htu, iat, ath y jti de un solo uso.
+func VerifyDPoPProof(proof string, opts DPoPVerifyOptions) (*DPoPProof, error) {
+	var jwk *JWK
+	keyfunc := func(t *jwtv5.Token) (any, error) {
+		if typ, _ := t.Header["typ"].(string); !strings.EqualFold(typ, DPoPProofType) {
+			return nil, errors.New("invalid typ")
+		}
+		raw, ok := t.Header["jwk"].(map[string]any)
+		if !ok {
+			return nil, errors.New("missing jwk")
+		}
+		if _, private := raw["d"]; private {
+			return nil, errors.New("jwk contains private key")
+		}
+		b, _ := json.Marshal(raw)
+		var k JWK
+		if err := json.Unmarshal(b, &k); err != nil {
+			return nil, err
+		}
+		jwk = &k
+		return k.PublicKey()
+	}
+
+	tk, err := jwtv5.Parse(proof, keyfunc, jwtv5.WithValidMethods(DPoPAlgs))
+	if err != nil || !tk.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrDPoPInvalid, err)
+	}
+	claims, ok := tk.Claims.(jwtv5.MapClaims)
+	if !ok {
+		return nil, ErrDPoPInvalid
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, fmt.Errorf("%w: missing jti", ErrDPoPInvalid)
+	}
+	if htm, _ := claims["htm"].(string); htm != opts.Method {
+		return nil, fmt.Errorf("%w: htm mismatch", ErrDPoPInvalid)
+	}
+	if htu, _ := claims["htu"].(string); !sameDPoPURL(htu, opts.URL) {
+		return nil, fmt.Errorf("%w: htu mismatch", ErrDPoPInvalid)
+	}
+
+	maxAge := opts.MaxAge
+	if maxAge <= 0 {
+		maxAge = dpopDefaultMaxAge
+	}
+	iat, err := claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		return nil, fmt.Errorf("%w: missing iat", ErrDPoPInvalid)
+	}
+	now := time.Now()
+	if iat.Time.After(now.Add(dpopLeeway)) || iat.Time.Before(now.Add(-maxAge)) {
+		return nil, fmt.Errorf("%w: iat out of range", ErrDPoPInvalid)
+	}
+
+	if opts.AccessToken != "" {
+		sum := sha256.Sum256([]byte(opts.AccessToken))
+		if ath, _ := claims["ath"].(string); ath != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			return nil, fmt.Errorf("%w: ath mismatch", ErrDPoPInvalid)
+		}
+	}
+
+	jkt, err := jwk.Thumbprint()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDPoPInvalid, err)
+	}
+
+	if opts.Replay != nil {
+		key := dpopReplayPrefix + jkt + ":" + jti
+		if !opts.Replay.SetNX(key, []byte("1"), maxAge+dpopLeeway) {
+			return nil, ErrDPoPReplay
+		}
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	return &DPoPProof{JKT: jkt, JTI: jti, IssuedAt: iat.Time, Nonce: nonce}, nil
+}
+
+// ConfirmationJKT retorna el thumbprint cnf.jkt de un token DPoP-bound ("" si no está ligado).
+func ConfirmationJKT(claims map[string]any) string {
+	cnf, ok := claims["cnf"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	jkt, _ := cnf["jkt"].(string)
+	return jkt
+}
+
+// sameDPoPURL compara htu ignorando query, fragment y mayúsculas en scheme/host (RFC 9449 §4.3).
+func sameDPoPURL(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(ua.Host, ub.Host) &&
+		ua.EscapedPath() == ub.EscapedPath()
+}
@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+)
+
+// MemoryDPoPReplay es un DPoPReplayCache en memoria, para cuando no hay un
+// cache compartido configurado (un solo proceso, desarrollo, tests).
+type MemoryDPoPReplay struct {
+	mu    sync.Mutex
+	items map[string]time.Time // key -> expiración
+	sweep time.Time
+}
+
+// NewMemoryDPoPReplay crea un replay cache en memoria.
+func NewMemoryDPoPReplay() *MemoryDPoPReplay {
+	return &MemoryDPoPReplay{items: make(map[string]time.Time)}
+}
+
+// SetNX registra la key con TTL si no existe o expiró. Limpia expirados como
+// mucho una vez por minuto.
+func (c *MemoryDPoPReplay) SetNX(key string, _ []byte, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if now.After(c.sweep) {
+		for k, exp := range c.items {
+			if now.After(exp) {
+				delete(c.items, k)
+			}
+		}
+		c.sweep = now.Add(time.Minute)
+	}
+	if exp, ok := c.items[key]; ok && !now.After(exp) {
+		return false
+	}
+	c.items[key] = now.Add(ttl)
+	return true
+}
+
+// CacheDPoPReplay es un DPoPReplayCache sobre el cache del tenant (memory o
+// Redis), compartido por todas las instancias.
+type CacheDPoPReplay struct {
+	c cache.Client
+}
+
+// NewCacheDPoPReplay crea un replay cache sobre c.
+func NewCacheDPoPReplay(c cache.Client) *CacheDPoPReplay {
+	return &CacheDPoPReplay{c: c}
+}
+
+// SetNX registra la key de forma atómica. Un error del cache cuenta como
+// replay: mejor rechazar el proof que aceptarlo dos veces.
+func (r *CacheDPoPReplay) SetNX(key string, value []byte, ttl time.Duration) bool {
+	ok, err := r.c.SetNX(context.Background(), key, string(value), ttl)
+	return err == nil && ok
+}
@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+const testHTU = "https://auth.example.com/oauth2/token"
+
+func newDPoPKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// headerJWK is the public key as it goes in the proof header.
+func headerJWK(pub *ecdsa.PublicKey) map[string]any {
+	k := ecJWK(pub)
+	return map[string]any{"kty": k.Kty, "crv": k.Crv, "x": k.X, "y": k.Y}
+}
+
+// dpopProof signs a proof for POST testHTU; edit changes claims or header first.
+func dpopProof(t *testing.T, key *ecdsa.PrivateKey, jti string, edit func(h map[string]any, c jwtv5.MapClaims)) string {
+	t.Helper()
+	claims := jwtv5.MapClaims{
+		"jti": jti,
+		"htm": "POST",
+		"htu": testHTU,
+		"iat": time.Now().Unix(),
+	}
+	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodES256, claims)
+	tk.Header["typ"] = DPoPProofType
+	tk.Header["jwk"] = headerJWK(&key.PublicKey)
+	if edit != nil {
+		edit(tk.Header, claims)
+	}
+	raw, err := tk.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestVerifyDPoPProof(t *testing.T) {
+	key := newDPoPKey(t)
+	p, err := VerifyDPoPProof(dpopProof(t, key, "jti-1", nil), DPoPVerifyOptions{Method: "POST", URL: testHTU + "?x=1"})
+	if err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+	want, _ := ecJWK(&key.PublicKey).Thumbprint()
+	if p.JKT != want || p.JTI != "jti-1" {
+		t.Errorf("proof = %+v, want jkt %s", p, want)
+	}
+}
+
+func TestVerifyDPoPProofReplay(t *testing.T) {
+	key := newDPoPKey(t)
+	replay := NewMemoryDPoPReplay()
+	proof := dpopProof(t, key, "jti-replay", nil)
+	opts := DPoPVerifyOptions{Method: "POST", URL: testHTU, Replay: replay}
+
+	if _, err := VerifyDPoPProof(proof, opts); err != nil {
+		t.Fatalf("first use rejected: %v", err)
+	}
+	if _, err := VerifyDPoPProof(proof, opts); !errors.Is(err, ErrDPoPReplay) {
+		t.Fatalf("replay: got %v, want ErrDPoPReplay", err)
+	}
+}
+
+func TestVerifyDPoPProofAccessTokenHash(t *testing.T) {
+	key := newDPoPKey(t)
+	sum := sha256.Sum256([]byte("access-token"))
+	ath := base64.RawURLEncoding.EncodeToString(sum[:])
+	proof := dpopProof(t, key, "jti-ath", func(_ map[string]any, c jwtv5.MapClaims) { c["ath"] = ath })
+
+	if _, err := VerifyDPoPProof(proof, DPoPVerifyOptions{Method: "POST", URL: testHTU, AccessToken: "access-token"}); err != nil {
+		t.Fatalf("matching ath rejected: %v", err)
+	}
+	if _, err := VerifyDPoPProof(proof, DPoPVerifyOptions{Method: "POST", URL: testHTU, AccessToken: "other-token"}); !errors.Is(err, ErrDPoPInvalid) {
+		t.Fatalf("ath for another token: got %v, want ErrDPoPInvalid", err)
+	}
+}
+
+func TestVerifyDPoPProofRejects(t *testing.T) {
+	key := newDPoPKey(t)
+	tests := []struct {
+		name string
+		edit func(h map[string]any, c jwtv5.MapClaims)
+	}{
+		{"wrong typ", func(h map[string]any, _ jwtv5.MapClaims) { h["typ"] = "JWT" }},
+		{"no jwk", func(h map[string]any, _ jwtv5.MapClaims) { delete(h, "jwk") }},
+		{"private jwk", func(h map[string]any, _ jwtv5.MapClaims) {
+			jwk := headerJWK(&key.PublicKey)
+			jwk["d"] = base64.RawURLEncoding.EncodeToString(key.D.Bytes())
+			h["jwk"] = jwk
+		}},
+		{"jwk of another key", func(h map[string]any, _ jwtv5.MapClaims) { h["jwk"] = headerJWK(&newDPoPKey(t).PublicKey) }},
+		{"wrong htm", func(_ map[string]any, c jwtv5.MapClaims) { c["htm"] = "GET" }},
+		{"wrong htu", func(_ map[string]any, c jwtv5.MapClaims) { c["htu"] = "https://auth.example.com/oauth2/revoke" }},
+		{"old iat", func(_ map[string]any, c jwtv5.MapClaims) { c["iat"] = time.Now().Add(-time.Hour).Unix() }},
+		{"future iat", func(_ map[string]any, c jwtv5.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{"no jti", func(_ map[string]any, c jwtv5.MapClaims) { delete(c, "jti") }},
+	}
+	for _, tt := range tests {
+		proof := dpopProof(t, key, "jti-"+tt.name, tt.edit)
+		if _, err := VerifyDPoPProof(proof, DPoPVerifyOptions{Method: "POST", URL: testHTU}); !errors.Is(err, ErrDPoPInvalid) {
+			t.Errorf("%s: got %v, want ErrDPoPInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestVerifyDPoPProofRejectsSymmetricAlg(t *testing.T) {
+	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{
+		"jti": "jti-hs", "htm": "POST", "htu": testHTU, "iat": time.Now().Unix(),
+	})
+	tk.Header["typ"] = DPoPProofType
+	tk.Header["jwk"] = map[string]any{"kty": "oct", "k": "c2VjcmV0"}
+	raw, _ := tk.SignedString([]byte("secret"))
+	if _, err := VerifyDPoPProof(raw, DPoPVerifyOptions{Method: "POST", URL: testHTU}); !errors.Is(err, ErrDPoPInvalid) {
+		t.Fatalf("got %v, want ErrDPoPInvalid", err)
+	}
+}
+
+func TestConfirmationJKT(t *testing.T) {
+	if got := ConfirmationJKT(map[string]any{"cnf": map[string]any{"jkt": "abc"}}); got != "abc" {
+		t.Errorf("ConfirmationJKT = %q, want abc", got)
+	}
+	if got := ConfirmationJKT(map[string]any{"sub": "u"}); got != "" {
+		t.Errorf("unbound token: ConfirmationJKT = %q", got)
+	}
+}
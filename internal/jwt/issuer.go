@@ -18,12 +18,24 @@ import (
 // Se inyecta opcionalmente para resolver "tid" claims a slugs.
 type TenantResolver func(ctx context.Context, tenantID string) (slug string, err error)
 
+// SigningAlgResolver devuelve el algoritmo de firma configurado para un tenant ("" = EdDSA).
+type SigningAlgResolver func(ctx context.Context, tenant string) string
+
+// RevocationChecker indica si un access token ya validado fue revocado (denylist).
+type RevocationChecker func(ctx context.Context, claims map[string]any) bool
+
+// DPoPReplayResolver devuelve el replay cache de DPoP proofs de un tenant (nil = ninguno).
+type DPoPReplayResolver func(ctx context.Context, tenant string) DPoPReplayCache
+
 // Issuer firma tokens usando la clave activa del keystore persistente.
 type Issuer struct {
-	Iss            string              // "iss" base
-	Keys           *PersistentKeystore // keystore persistente
-	AccessTTL      time.Duration       // TTL por defecto de Access/ID (ej: 15m)
-	TenantResolver TenantResolver      // opcional: para mapear tid→slug
+	Iss                string              // "iss" base
+	Keys               *PersistentKeystore // keystore persistente
+	AccessTTL          time.Duration       // TTL por defecto de Access/ID (ej: 15m)
+	TenantResolver     TenantResolver      // opcional: para mapear tid→slug
+	SigningAlgResolver SigningAlgResolver  // opcional: algoritmo por tenant (default EdDSA)
+	RevocationChecker  RevocationChecker   // opcional: denylist de access tokens
+	DPoPReplayResolver DPoPReplayResolver  // opcional: jti de DPoP proofs en el cache del tenant
 }
 
 func NewIssuer(iss string, ks *PersistentKeystore) *Issuer {
@@ -40,6 +52,81 @@ func (i *Issuer) WithTenantResolver(resolver TenantResolver) *Issuer {
 	return i
 }
 
+// WithSigningAlgResolver agrega el resolver del algoritmo de firma por tenant.
+func (i *Issuer) WithSigningAlgResolver(resolver SigningAlgResolver) *Issuer {
+	i.SigningAlgResolver = resolver
+	return i
+}
+
+// WithRevocationChecker agrega la consulta a la denylist de access tokens.
+func (i *Issuer) WithRevocationChecker(checker RevocationChecker) *Issuer {
+	i.RevocationChecker = checker
+	return i
+}
+
+// IsRevoked consulta la denylist para los claims de un access token.
+// Sin checker configurado ningún token se considera revocado.
+func (i *Issuer) IsRevoked(ctx context.Context, claims map[string]any) bool {
+	if i.RevocationChecker == nil {
+		return false
+	}
+	return i.RevocationChecker(ctx, claims)
+}
+
+// WithDPoPReplayResolver agrega el replay cache de DPoP proofs por tenant.
+func (i *Issuer) WithDPoPReplayResolver(resolver DPoPReplayResolver) *Issuer {
+	i.DPoPReplayResolver = resolver
+	return i
+}
+
+// DPoPReplayFor retorna el replay cache de DPoP proofs del tenant, o nil si no hay resolver.
+func (i *Issuer) DPoPReplayFor(ctx context.Context, tenant string) DPoPReplayCache {
+	if i.DPoPReplayResolver == nil || tenant == "" {
+		return nil
+	}
+	return i.DPoPReplayResolver(ctx, tenant)
+}
+
+// NewJTI genera el identificador único (claim jti) de un access token.
+func NewJTI() string {
+	return uuid.NewString()
+}
+
+// SigningAlgFor devuelve el algoritmo con el que se firman los tokens del tenant.
+func (i *Issuer) SigningAlgFor(tenant string) string {
+	if i.SigningAlgResolver != nil && tenant != "" {
+		if alg := i.SigningAlgResolver(context.Background(), tenant); IsSigningAlgSupported(alg) {
+			return alg
+		}
+	}
+	return AlgEdDSA
+}
+
+// signForTenant firma claims con la clave activa del tenant para alg y setea kid/typ.
+// alg vacío usa el algoritmo configurado para el tenant.
+func (i *Issuer) signForTenant(tenant, alg string, claims jwtv5.MapClaims) (string, error) {
+	return i.signForTenantTyp(tenant, alg, "JWT", claims)
+}
+
+// signForTenantTyp es signForTenant con un header typ explícito (ej: "logout+jwt").
+func (i *Issuer) signForTenantTyp(tenant, alg, typ string, claims jwtv5.MapClaims) (string, error) {
+	if alg == "" {
+		alg = i.SigningAlgFor(tenant)
+	}
+	method, err := SigningMethodFor(alg)
+	if err != nil {
+		return "", err
+	}
+	kid, priv, err := i.Keys.ActiveSignerForTenant(tenant, alg)
+	if err != nil {
+		return "", err
+	}
+	tk := jwtv5.NewWithClaims(method, claims)
+	tk.Header["kid"] = kid
+	tk.Header["typ"] = typ
+	return tk.SignedString(priv)
+}
+
 // GetIss returns the issuer string.
 func (i *Issuer) GetIss() string {
 	return i.Iss
@@ -56,7 +143,7 @@ func (i *Issuer) Keyfunc() jwtv5.Keyfunc {
 	return func(t *jwtv5.Token) (any, error) {
 		kid, _ := t.Header["kid"].(string)
 		if kid != "" {
-			return i.Keys.PublicKeyByKID(kid)
+			return i.Keys.VerificationKeyForTenant("", kid)
 		}
 		// Fallback: usar la activa
 		_, _, pub, err := i.Keys.Active()
@@ -76,11 +163,7 @@ func (i *Issuer) KeyfuncForTenant(tenant string) jwtv5.Keyfunc {
 			return nil, errors.New("kid_missing")
 		}
 		// Buscar pubkey en JWKS del tenant
-		pub, err := i.Keys.PublicKeyByKIDForTenant(tenant, kid)
-		if err != nil {
-			return nil, err
-		}
-		return ed25519.PublicKey(pub), nil
+		return i.Keys.VerificationKeyForTenant(tenant, kid)
 	}
 }
 
@@ -114,9 +197,10 @@ func (i *Issuer) IssueAccess(sub, aud string, std map[string]any, custom map[str
 		"iss": i.Iss,
 		"sub": sub,
 		"aud": aud,
-		"iat": now.Unix(),
+		"iat": accessIssuedAt(now),
 		"nbf": now.Unix(),
 		"exp": exp.Unix(),
+		"jti": NewJTI(),
 	}
 	for k, v := range std {
 		claims[k] = v
@@ -180,6 +264,40 @@ func (i *Issuer) IssueAccessForTenant(tenant, iss, sub, aud string, std map[stri
 // IssueAccessForTenantWithTTL emite un Access Token con TTL personalizado.
 // Si ttlSeconds <= 0, usa el TTL por defecto del issuer.
 func (i *Issuer) IssueAccessForTenantWithTTL(tenant, iss, sub, aud string, std map[string]any, custom map[string]any, ttlSeconds int) (string, time.Time, error) {
+	return i.IssueAccessForAudience(tenant, iss, sub, []string{aud}, std, custom, ttlSeconds, "")
+}
+
+// IssueAccessForAudience emite un Access Token para una o más audiencias
+// (resource indicators, RFC 8707) firmado con alg; alg vacío usa el del tenant.
+// Con una sola audiencia el claim aud es un string.
+func (i *Issuer) IssueAccessForAudience(tenant, iss, sub string, aud []string, std map[string]any, custom map[string]any, ttlSeconds int, alg string) (string, time.Time, error) {
+	return i.issueAccess(tenant, iss, sub, aud, std, custom, ttlSeconds, alg, "JWT")
+}
+
+// AccessTokenJWTType es el typ de los access tokens JWT de RFC 9068 §2.1.
+const AccessTokenJWTType = "at+jwt"
+
+// MaxAccessTTL es la vida máxima de un access token, aunque el client o el API
+// resource configuren un TTL mayor. Las marcas de revocación por usuario/client/
+// sesión/tenant duran esto: pasado ese tiempo ningún token anterior sigue vigente.
+const MaxAccessTTL = 24 * time.Hour
+
+// accessIssuedAt devuelve el iat de un access token con precisión de milisegundos
+// (NumericDate admite fracciones, RFC 7519 §2), para que una revocación masiva no
+// alcance a los tokens emitidos en el mismo segundo pero después de ella.
+func accessIssuedAt(now time.Time) float64 {
+	return float64(now.UnixMilli()) / 1000
+}
+
+// IssueAccessJWT emite un Access Token con el perfil JWT de RFC 9068: typ
+// "at+jwt" y los claims de std (client_id, scope, auth_time, roles, ...)
+// junto a iss, sub, aud, exp, iat y jti. alg vacío usa el del tenant.
+func (i *Issuer) IssueAccessJWT(tenant, iss, sub string, aud []string, std map[string]any, custom map[string]any, ttlSeconds int, alg string) (string, time.Time, error) {
+	return i.issueAccess(tenant, iss, sub, aud, std, custom, ttlSeconds, alg, AccessTokenJWTType)
+}
+
+// issueAccess arma y firma un access token con el typ de header dado.
+func (i *Issuer) issueAccess(tenant, iss, sub string, aud []string, std map[string]any, custom map[string]any, ttlSeconds int, alg, typ string) (string, time.Time, error) {
 	now := time.Now().UTC()
 
 	// Use custom TTL if provided, otherwise use default
@@ -187,20 +305,24 @@ func (i *Issuer) IssueAccessForTenantWithTTL(tenant, iss, sub, aud string, std m
 	if ttlSeconds > 0 {
 		ttl = time.Duration(ttlSeconds) * time.Second
 	}
+	if ttl > MaxAccessTTL {
+		ttl = MaxAccessTTL
+	}
 	exp := now.Add(ttl)
 
-	kid, priv, _, err := i.Keys.ActiveForTenant(tenant)
-	if err != nil {
-		return "", time.Time{}, err
+	var audClaim any = aud
+	if len(aud) == 1 {
+		audClaim = aud[0]
 	}
 
 	claims := jwtv5.MapClaims{
 		"iss": iss,
 		"sub": sub,
-		"aud": aud,
-		"iat": now.Unix(),
+		"aud": audClaim,
+		"iat": accessIssuedAt(now),
 		"nbf": now.Unix(),
 		"exp": exp.Unix(),
+		"jti": NewJTI(),
 	}
 	for k, v := range std {
 		claims[k] = v
@@ -208,11 +330,7 @@ func (i *Issuer) IssueAccessForTenantWithTTL(tenant, iss, sub, aud string, std m
 	if custom != nil {
 		claims["custom"] = custom
 	}
-	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims)
-	tk.Header["kid"] = kid
-	tk.Header["typ"] = "JWT"
-
-	signed, err := tk.SignedString(priv)
+	signed, err := i.signForTenantTyp(tenant, alg, typ, claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -227,6 +345,12 @@ func (i *Issuer) IssueIDTokenForTenant(tenant, iss, sub, aud string, std map[str
 // IssueIDTokenForTenantWithTTL emite un ID Token OIDC con TTL personalizado.
 // Si ttlSeconds <= 0, usa el TTL por defecto del issuer.
 func (i *Issuer) IssueIDTokenForTenantWithTTL(tenant, iss, sub, aud string, std map[string]any, extra map[string]any, ttlSeconds int) (string, time.Time, error) {
+	return i.IssueIDTokenForTenantWithAlg(tenant, iss, sub, aud, std, extra, ttlSeconds, "")
+}
+
+// IssueIDTokenForTenantWithAlg emite un ID Token firmado con alg
+// (id_token_signed_response_alg del client); alg vacío usa el del tenant.
+func (i *Issuer) IssueIDTokenForTenantWithAlg(tenant, iss, sub, aud string, std map[string]any, extra map[string]any, ttlSeconds int, alg string) (string, time.Time, error) {
 	now := time.Now().UTC()
 
 	// Use custom TTL if provided, otherwise use default
@@ -236,11 +360,6 @@ func (i *Issuer) IssueIDTokenForTenantWithTTL(tenant, iss, sub, aud string, std
 	}
 	exp := now.Add(ttl)
 
-	kid, priv, _, err := i.Keys.ActiveForTenant(tenant)
-	if err != nil {
-		return "", time.Time{}, err
-	}
-
 	claims := jwtv5.MapClaims{
 		"iss": iss,
 		"sub": sub,
@@ -255,17 +374,120 @@ func (i *Issuer) IssueIDTokenForTenantWithTTL(tenant, iss, sub, aud string, std
 	for k, v := range extra {
 		claims[k] = v
 	}
-	tk := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims)
-	tk.Header["kid"] = kid
-	tk.Header["typ"] = "JWT"
-
-	signed, err := tk.SignedString(priv)
+	signed, err := i.signForTenant(tenant, alg, claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 	return signed, exp, nil
 }
 
+// BackchannelLogoutEvent identifica el logout_token (OIDC Back-Channel Logout 1.0 §2.4).
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenTTL es la vida del logout_token; el RP lo consume al recibirlo.
+const LogoutTokenTTL = 2 * time.Minute
+
+// IssueLogoutToken emite el logout_token de back-channel logout para el client aud.
+// Lleva sub, sid o ambos (nunca nonce); alg vacío usa el algoritmo del tenant.
+func (i *Issuer) IssueLogoutToken(tenant, iss, aud, sub, sid, alg string) (string, error) {
+	if sub == "" && sid == "" {
+		return "", errors.New("logout token requires sub or sid")
+	}
+	now := time.Now().UTC()
+	claims := jwtv5.MapClaims{
+		"iss":    iss,
+		"aud":    aud,
+		"iat":    now.Unix(),
+		"exp":    now.Add(LogoutTokenTTL).Unix(),
+		"jti":    NewJTI(),
+		"events": map[string]any{BackchannelLogoutEvent: map[string]any{}},
+	}
+	if sub != "" {
+		claims["sub"] = sub
+	}
+	if sid != "" {
+		claims["sid"] = sid
+	}
+	return i.signForTenantTyp(tenant, alg, "logout+jwt", claims)
+}
+
+// AuthorizationResponseTTL es la vida de una respuesta de autorización JARM.
+const AuthorizationResponseTTL = 10 * time.Minute
+
+// IssueAuthorizationResponse firma los parámetros de una respuesta de /authorize
+// (code, state o error) como JWT para el client aud (JARM). alg vacío usa el del tenant.
+func (i *Issuer) IssueAuthorizationResponse(tenant, iss, aud string, params map[string]string, alg string) (string, error) {
+	now := time.Now().UTC()
+	claims := jwtv5.MapClaims{}
+	for k, v := range params {
+		if v != "" {
+			claims[k] = v
+		}
+	}
+	claims["iss"] = iss
+	claims["aud"] = aud
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(AuthorizationResponseTTL).Unix()
+	return i.signForTenant(tenant, alg, claims)
+}
+
+// UserInfoTTL es la vida de una respuesta de userinfo firmada.
+const UserInfoTTL = 5 * time.Minute
+
+// IssueUserInfo firma los claims de /userinfo como JWT para el client aud
+// (OIDC Core §5.3.2): agrega iss y aud. alg vacío usa el del tenant.
+func (i *Issuer) IssueUserInfo(tenant, iss, aud string, claims map[string]any, alg string) (string, error) {
+	now := time.Now().UTC()
+	mc := jwtv5.MapClaims{}
+	for k, v := range claims {
+		mc[k] = v
+	}
+	mc["iss"] = iss
+	mc["aud"] = aud
+	mc["iat"] = now.Unix()
+	mc["exp"] = now.Add(UserInfoTTL).Unix()
+	return i.signForTenant(tenant, alg, mc)
+}
+
+// IntrospectionResponseType es el typ y media type de una respuesta de
+// introspección firmada (RFC 9701 §5).
+const IntrospectionResponseType = "token-introspection+jwt"
+
+// IssueIntrospectionResponse firma el resultado de /introspect para el
+// solicitante aud (RFC 9701): lo anida en el claim token_introspection.
+// alg vacío usa el del tenant.
+func (i *Issuer) IssueIntrospectionResponse(tenant, iss, aud string, result map[string]any, alg string) (string, error) {
+	claims := jwtv5.MapClaims{
+		"iss":                 iss,
+		"iat":                 time.Now().UTC().Unix(),
+		"token_introspection": result,
+	}
+	if aud != "" {
+		claims["aud"] = aud
+	}
+	return i.signForTenantTyp(tenant, alg, IntrospectionResponseType, claims)
+}
+
+// EncryptNested cifra un JWT ya firmado para la clave del client: JWS anidado
+// en un JWE con cty "JWT" (OIDC Core §16.14). enc vacío usa A128CBC-HS256.
+func EncryptNested(signed string, key *JWK, alg, enc string) (string, error) {
+	return EncryptJWE([]byte(signed), key, alg, enc, "JWT")
+}
+
+// DecryptForTenant descifra un JWE dirigido al servidor con la clave de cifrado
+// del tenant indicada por su kid (RSA-OAEP / RSA-OAEP-256).
+func (i *Issuer) DecryptForTenant(tenant, token string) ([]byte, *JWEHeader, error) {
+	h, err := ParseJWEHeader(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	priv, err := i.Keys.DecryptionKeyForTenant(tenant, h.Kid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return DecryptJWE(token, priv)
+}
+
 // JWKSJSON expone el JWKS actual (active+retiring)
 func (i *Issuer) JWKSJSON() []byte {
 	j, _ := i.Keys.JWKSJSON()
@@ -358,19 +580,19 @@ func (i *Issuer) KeyfuncFromTokenClaims() jwtv5.Keyfunc {
 
 		// 3) Intentar buscar en el Tenant Keyring
 		if tenantSlug != "" {
-			pub, err := i.Keys.PublicKeyByKIDForTenant(tenantSlug, kid)
+			pub, err := i.Keys.VerificationKeyForTenant(tenantSlug, kid)
 			if err == nil {
-				return ed25519.PublicKey(pub), nil
+				return pub, nil
 			}
 			// Si falla, continuar con fallback global
 		}
 
 		// 4) Fallback: Buscar en el Global Keyring
-		pub, err := i.Keys.PublicKeyByKID(kid)
+		pub, err := i.Keys.VerificationKeyForTenant("", kid)
 		if err != nil {
 			return nil, fmt.Errorf("kid_not_found: %s (tenant=%s)", kid, tenantSlug)
 		}
-		return ed25519.PublicKey(pub), nil
+		return pub, nil
 	}
 }
 
@@ -395,6 +617,7 @@ func (i *Issuer) IssueAdminAccess(ctx context.Context, claims AdminAccessClaims)
 		"iat":        now.Unix(),
 		"nbf":        now.Unix(),
 		"exp":        exp.Unix(),
+		"jti":        NewJTI(),
 	}
 
 	// Solo incluir tenants si no está vacío (tenant admin)
@@ -0,0 +1,330 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// ErrInvalidJWE indica un JWE mal formado o que no se pudo descifrar.
+var ErrInvalidJWE = errors.New("invalid jwe")
+
+// JWEHeader es el protected header de un JWE compacto.
+type JWEHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	Zip string `json:"zip,omitempty"`
+	Epk *JWK   `json:"epk,omitempty"` // clave efímera de ECDH-ES
+}
+
+// IsJWE indica si token tiene la forma de un JWE compacto (5 partes).
+func IsJWE(token string) bool {
+	return strings.Count(token, ".") == 4
+}
+
+// ParseJWEHeader devuelve el protected header de un JWE compacto sin descifrarlo.
+func ParseJWEHeader(token string) (*JWEHeader, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, ErrInvalidJWE
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidJWE
+	}
+	var h JWEHeader
+	if err := json.Unmarshal(raw, &h); err != nil {
+		return nil, ErrInvalidJWE
+	}
+	return &h, nil
+}
+
+// DecryptJWE descifra un JWE compacto con la clave RSA privada del servidor.
+// Soporta alg RSA-OAEP / RSA-OAEP-256 y enc AES-GCM / AES-CBC-HMAC-SHA2.
+func DecryptJWE(token string, priv *rsa.PrivateKey) ([]byte, *JWEHeader, error) {
+	h, err := ParseJWEHeader(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.Zip != "" {
+		return nil, nil, fmt.Errorf("%w: compression not supported", ErrInvalidJWE)
+	}
+	parts := strings.Split(token, ".")
+	ek, err1 := base64.RawURLEncoding.DecodeString(parts[1])
+	iv, err2 := base64.RawURLEncoding.DecodeString(parts[2])
+	ct, err3 := base64.RawURLEncoding.DecodeString(parts[3])
+	tag, err4 := base64.RawURLEncoding.DecodeString(parts[4])
+	if err := errors.Join(err1, err2, err3, err4); err != nil {
+		return nil, nil, ErrInvalidJWE
+	}
+
+	var oaepHash hash.Hash
+	switch h.Alg {
+	case AlgRSAOAEP:
+		oaepHash = sha1.New()
+	case AlgRSAOAEP256:
+		oaepHash = sha256.New()
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidJWE, h.Alg)
+	}
+	cek, err := rsa.DecryptOAEP(oaepHash, rand.Reader, priv, ek, nil)
+	if err != nil {
+		return nil, nil, ErrInvalidJWE
+	}
+
+	// AAD = ASCII(BASE64URL(protected header)) (RFC 7516 §5.2)
+	plain, err := decryptContent(h.Enc, cek, iv, ct, tag, []byte(parts[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+	return plain, h, nil
+}
+
+// decryptContent aplica el algoritmo de cifrado de contenido "enc".
+func decryptContent(enc string, cek, iv, ct, tag, aad []byte) ([]byte, error) {
+	switch enc {
+	case EncA128GCM, EncA256GCM:
+		if (enc == EncA128GCM && len(cek) != 16) || (enc == EncA256GCM && len(cek) != 32) {
+			return nil, ErrInvalidJWE
+		}
+		block, err := aes.NewCipher(cek)
+		if err != nil {
+			return nil, ErrInvalidJWE
+		}
+		gcm, err := cipher.NewGCM(block)
+		if err != nil || len(iv) != gcm.NonceSize() {
+			return nil, ErrInvalidJWE
+		}
+		plain, err := gcm.Open(nil, iv, append(ct, tag...), aad)
+		if err != nil {
+			return nil, ErrInvalidJWE
+		}
+		return plain, nil
+
+	case EncA128CBCHS256, EncA256CBCHS512:
+		// RFC 7518 §5.2: CEK = MAC_KEY || ENC_KEY, tag = primera mitad del HMAC
+		newHash, size := sha256.New, 32
+		if enc == EncA256CBCHS512 {
+			newHash, size = sha512.New, 64
+		}
+		if len(cek) != size || len(iv) != aes.BlockSize {
+			return nil, ErrInvalidJWE
+		}
+		macKey, encKey := cek[:size/2], cek[size/2:]
+
+		if subtle.ConstantTimeCompare(cbcHMAC(newHash, macKey, aad, iv, ct)[:size/2], tag) != 1 {
+			return nil, ErrInvalidJWE
+		}
+
+		block, err := aes.NewCipher(encKey)
+		if err != nil || len(ct) == 0 || len(ct)%aes.BlockSize != 0 {
+			return nil, ErrInvalidJWE
+		}
+		plain := make([]byte, len(ct))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, ct)
+		// PKCS#7 (el HMAC ya autenticó el ciphertext)
+		pad := int(plain[len(plain)-1])
+		if pad == 0 || pad > aes.BlockSize {
+			return nil, ErrInvalidJWE
+		}
+		return plain[:len(plain)-pad], nil
+	}
+	return nil, fmt.Errorf("%w: unsupported enc %q", ErrInvalidJWE, enc)
+}
+
+// cbcHMAC calcula el HMAC de AES-CBC-HMAC-SHA2: AAD || IV || ciphertext || AL
+// (RFC 7518 §5.2.2.1); el tag es su primera mitad.
+func cbcHMAC(newHash func() hash.Hash, macKey, aad, iv, ct []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+	m := hmac.New(newHash, macKey)
+	m.Write(aad)
+	m.Write(iv)
+	m.Write(ct)
+	m.Write(al)
+	return m.Sum(nil)
+}
+
+// EncryptJWE cifra plaintext como JWE compacto para la clave pública key del
+// destinatario: RSA-OAEP / RSA-OAEP-256 (kty RSA) o ECDH-ES (kty EC).
+// enc vacío usa DefaultResponseEncryptionEnc; cty "JWT" marca un JWT anidado.
+func EncryptJWE(plaintext []byte, key *JWK, alg, enc, cty string) (string, error) {
+	if enc == "" {
+		enc = DefaultResponseEncryptionEnc
+	}
+	size, err := cekSize(enc)
+	if err != nil {
+		return "", err
+	}
+	pub, err := key.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	h := JWEHeader{Alg: alg, Enc: enc, Kid: key.Kid, Cty: cty}
+
+	var cek, ek []byte
+	switch alg {
+	case AlgRSAOAEP, AlgRSAOAEP256:
+		rsaPub, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return "", fmt.Errorf("%w: %s requires an RSA key", ErrInvalidJWE, alg)
+		}
+		cek = make([]byte, size)
+		if _, err := rand.Read(cek); err != nil {
+			return "", err
+		}
+		var oaepHash hash.Hash = sha1.New()
+		if alg == AlgRSAOAEP256 {
+			oaepHash = sha256.New()
+		}
+		if ek, err = rsa.EncryptOAEP(oaepHash, rand.Reader, rsaPub, cek, nil); err != nil {
+			return "", err
+		}
+	case AlgECDHES:
+		ecPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return "", fmt.Errorf("%w: %s requires an EC key", ErrInvalidJWE, alg)
+		}
+		// Acuerdo directo: la CEK es la clave derivada y no viaja cifrada
+		if cek, h.Epk, err = ecdhESKey(ecPub, enc, size); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: unsupported alg %q", ErrInvalidJWE, alg)
+	}
+
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(hb)
+	iv, ct, tag, err := encryptContent(enc, cek, plaintext, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	return strings.Join([]string{protected, b64(ek), b64(iv), b64(ct), b64(tag)}, "."), nil
+}
+
+// cekSize es el largo en bytes de la CEK para enc.
+func cekSize(enc string) (int, error) {
+	switch enc {
+	case EncA128GCM:
+		return 16, nil
+	case EncA256GCM, EncA128CBCHS256:
+		return 32, nil
+	case EncA256CBCHS512:
+		return 64, nil
+	}
+	return 0, fmt.Errorf("%w: unsupported enc %q", ErrInvalidJWE, enc)
+}
+
+// encryptContent aplica el algoritmo de cifrado de contenido "enc" (inverso de decryptContent).
+func encryptContent(enc string, cek, plain, aad []byte) (iv, ct, tag []byte, err error) {
+	switch enc {
+	case EncA128GCM, EncA256GCM:
+		block, err := aes.NewCipher(cek)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		iv = make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(iv); err != nil {
+			return nil, nil, nil, err
+		}
+		sealed := gcm.Seal(nil, iv, plain, aad)
+		n := len(sealed) - gcm.Overhead()
+		return iv, sealed[:n], sealed[n:], nil
+
+	case EncA128CBCHS256, EncA256CBCHS512:
+		newHash, size := sha256.New, 32
+		if enc == EncA256CBCHS512 {
+			newHash, size = sha512.New, 64
+		}
+		macKey, encKey := cek[:size/2], cek[size/2:]
+		block, err := aes.NewCipher(encKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		iv = make([]byte, aes.BlockSize)
+		if _, err := rand.Read(iv); err != nil {
+			return nil, nil, nil, err
+		}
+		pad := aes.BlockSize - len(plain)%aes.BlockSize
+		padded := append(append([]byte{}, plain...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+		ct = make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, padded)
+		return iv, ct, cbcHMAC(newHash, macKey, aad, iv, ct)[:size/2], nil
+	}
+	return nil, nil, nil, fmt.Errorf("%w: unsupported enc %q", ErrInvalidJWE, enc)
+}
+
+// ecdhESKey genera una clave efímera sobre la curva del destinatario y deriva
+// la CEK del secreto compartido con Concat KDF (RFC 7518 §4.6.2).
+func ecdhESKey(pub *ecdsa.PublicKey, enc string, size int) ([]byte, *JWK, error) {
+	recipient, err := pub.ECDH()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWE, err)
+	}
+	eph, err := recipient.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	z, err := eph.ECDH(recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Punto sin comprimir: 0x04 || X || Y
+	raw := eph.PublicKey().Bytes()
+	n := (len(raw) - 1) / 2
+	epk := &JWK{
+		Kty: "EC",
+		Crv: pub.Curve.Params().Name,
+		X:   base64.RawURLEncoding.EncodeToString(raw[1 : 1+n]),
+		Y:   base64.RawURLEncoding.EncodeToString(raw[1+n:]),
+	}
+	return concatKDF(z, enc, size), epk, nil
+}
+
+// concatKDF es el KDF de NIST SP 800-56A con SHA-256. OtherInfo lleva el enc
+// como AlgorithmID, apu/apv vacíos y el largo de la clave en bits.
+func concatKDF(z []byte, algID string, size int) []byte {
+	lenPrefixed := func(b []byte) []byte {
+		return append(binary.BigEndian.AppendUint32(nil, uint32(len(b))), b...)
+	}
+	other := lenPrefixed([]byte(algID))
+	other = append(other, lenPrefixed(nil)...) // PartyUInfo
+	other = append(other, lenPrefixed(nil)...) // PartyVInfo
+	other = binary.BigEndian.AppendUint32(other, uint32(size*8))
+
+	var out []byte
+	for counter := uint32(1); len(out) < size; counter++ {
+		h := sha256.New()
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+		h.Write(z)
+		h.Write(other)
+		out = h.Sum(out)
+	}
+	return out[:size]
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func rsaJWK(pub *rsa.PublicKey) *JWK {
+	return &JWK{
+		Kty: "RSA",
+		Kid: "enc-1",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+func TestJWERoundTripRSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := rsaJWK(&priv.PublicKey)
+	plain := []byte(`{"sub":"user-1","aud":"client-1"}`)
+
+	for _, alg := range KeyEncryptionAlgs {
+		for _, enc := range ContentEncryptionAlgs {
+			token, err := EncryptJWE(plain, key, alg, enc, "JWT")
+			if err != nil {
+				t.Fatalf("%s/%s: encrypt: %v", alg, enc, err)
+			}
+			if !IsJWE(token) {
+				t.Fatalf("%s/%s: not a compact JWE: %s", alg, enc, token)
+			}
+			got, h, err := DecryptJWE(token, priv)
+			if err != nil {
+				t.Fatalf("%s/%s: decrypt: %v", alg, enc, err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Errorf("%s/%s: plaintext mismatch: %s", alg, enc, got)
+			}
+			if h.Alg != alg || h.Enc != enc || h.Kid != "enc-1" || h.Cty != "JWT" {
+				t.Errorf("%s/%s: unexpected header: %+v", alg, enc, h)
+			}
+		}
+	}
+}
+
+func TestJWEDefaultEnc(t *testing.T) {
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	token, err := EncryptJWE([]byte("x"), rsaJWK(&priv.PublicKey), AlgRSAOAEP256, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := ParseJWEHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Enc != DefaultResponseEncryptionEnc {
+		t.Errorf("enc = %q, want %q", h.Enc, DefaultResponseEncryptionEnc)
+	}
+}
+
+func TestJWEDecryptRejectsTampering(t *testing.T) {
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	for _, enc := range ContentEncryptionAlgs {
+		token, err := EncryptJWE([]byte("secret payload"), rsaJWK(&priv.PublicKey), AlgRSAOAEP256, enc, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts := strings.Split(token, ".")
+
+		flip := func(i int) string {
+			raw, _ := base64.RawURLEncoding.DecodeString(parts[i])
+			raw[0] ^= 0x01
+			p := append([]string(nil), parts...)
+			p[i] = base64.RawURLEncoding.EncodeToString(raw)
+			return strings.Join(p, ".")
+		}
+
+		cases := map[string]string{
+			"ciphertext": flip(3),
+			"tag":        flip(4),
+			"iv":         flip(2),
+			"header":     strings.Join(append([]string{base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RSA-OAEP-256","enc":"` + enc + `","kid":"x"}`))}, parts[1:]...), "."),
+			"truncated":  strings.Join(parts[:4], "."),
+		}
+		for name, bad := range cases {
+			if _, _, err := DecryptJWE(bad, priv); err == nil {
+				t.Errorf("%s: tampered %s accepted", enc, name)
+			}
+		}
+		if _, _, err := DecryptJWE(token, other); err == nil {
+			t.Errorf("%s: decrypted with the wrong key", enc)
+		}
+	}
+}
+
+func TestJWEEncryptRejectsKeyMismatch(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	if _, err := EncryptJWE([]byte("x"), ecJWK(&ec.PublicKey), AlgRSAOAEP256, "", ""); err == nil {
+		t.Error("RSA-OAEP-256 accepted an EC key")
+	}
+	if _, err := EncryptJWE([]byte("x"), rsaJWK(&priv.PublicKey), AlgECDHES, "", ""); err == nil {
+		t.Error("ECDH-ES accepted an RSA key")
+	}
+	if _, err := EncryptJWE([]byte("x"), rsaJWK(&priv.PublicKey), "dir", "", ""); err == nil {
+		t.Error("unsupported alg accepted")
+	}
+	if _, err := EncryptJWE([]byte("x"), rsaJWK(&priv.PublicKey), AlgRSAOAEP256, "A192GCM", ""); err == nil {
+		t.Error("unsupported enc accepted")
+	}
+}
+
+func ecJWK(pub *ecdsa.PublicKey) *JWK {
+	x, y := make([]byte, 32), make([]byte, 32)
+	pub.X.FillBytes(x)
+	pub.Y.FillBytes(y)
+	return &JWK{Kty: "EC", Crv: "P-256", X: base64.RawURLEncoding.EncodeToString(x), Y: base64.RawURLEncoding.EncodeToString(y)}
+}
+
+// El servidor no descifra ECDH-ES (sólo lo usa para cifrar respuestas); el
+// receptor deriva la CEK de epk con su clave privada.
+func TestJWERoundTripECDHES(t *testing.T) {
+	recipient, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte(`{"sub":"user-1"}`)
+
+	for _, enc := range ContentEncryptionAlgs {
+		token, err := EncryptJWE(plain, ecJWK(&recipient.PublicKey), AlgECDHES, enc, "")
+		if err != nil {
+			t.Fatalf("%s: encrypt: %v", enc, err)
+		}
+		h, err := ParseJWEHeader(token)
+		if err != nil || h.Epk == nil {
+			t.Fatalf("%s: missing epk: %v", enc, err)
+		}
+		parts := strings.Split(token, ".")
+		if parts[1] != "" {
+			t.Fatalf("%s: ECDH-ES must not carry an encrypted key", enc)
+		}
+
+		epk, err := h.Epk.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ephPub, err := epk.(*ecdsa.PublicKey).ECDH()
+		if err != nil {
+			t.Fatal(err)
+		}
+		recipientECDH, err := recipient.ECDH()
+		if err != nil {
+			t.Fatal(err)
+		}
+		z, err := recipientECDH.ECDH(ephPub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, _ := cekSize(enc)
+		cek := concatKDF(z, enc, size)
+
+		dec := base64.RawURLEncoding.DecodeString
+		iv, _ := dec(parts[2])
+		ct, _ := dec(parts[3])
+		tag, _ := dec(parts[4])
+		got, err := decryptContent(enc, cek, iv, ct, tag, []byte(parts[0]))
+		if err != nil {
+			t.Fatalf("%s: decrypt: %v", enc, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("%s: plaintext mismatch: %s", enc, got)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK es una clave pública en formato JSON Web Key (RFC 7517).
+// Soporta RSA, EC (P-256/P-384/P-521) y OKP (Ed25519).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet es un documento JWKS {"keys":[...]}.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ErrJWKNotFound se retorna cuando no hay clave que coincida con el kid.
+var ErrJWKNotFound = errors.New("jwk not found")
+
+// ParseJWKS parsea un documento JWKS.
+func ParseJWKS(data []byte) (*JWKSet, error) {
+	var set JWKSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+	return &set, nil
+}
+
+// Find retorna la clave con el kid dado. Si kid es vacío y el set tiene una
+// única clave, retorna esa.
+func (s *JWKSet) Find(kid string) (*JWK, error) {
+	if s == nil {
+		return nil, ErrJWKNotFound
+	}
+	if kid == "" {
+		if len(s.Keys) == 1 {
+			return &s.Keys[0], nil
+		}
+		return nil, ErrJWKNotFound
+	}
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i], nil
+		}
+	}
+	return nil, ErrJWKNotFound
+}
+
+// FindEncryption retorna la primera clave de cifrado (use "enc" o sin use) cuyo
+// tipo sirve para el alg JWE dado: RSA para RSA-OAEP*, EC para ECDH-ES.
+func (s *JWKSet) FindEncryption(alg string) (*JWK, error) {
+	if s == nil {
+		return nil, ErrJWKNotFound
+	}
+	kty := "RSA"
+	if alg == AlgECDHES {
+		kty = "EC"
+	}
+	for i := range s.Keys {
+		k := &s.Keys[i]
+		if k.Kty != kty || k.Use == "sig" || (k.Alg != "" && k.Alg != alg) {
+			continue
+		}
+		return k, nil
+	}
+	return nil, ErrJWKNotFound
+}
+
+// PublicKey convierte el JWK a una clave pública de crypto.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		nb, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk: invalid n: %w", err)
+		}
+		eb, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwk: invalid e: %w", err)
+		}
+		e := 0
+		for _, b := range eb {
+			e = (e << 8) | int(b)
+		}
+		if e == 0 {
+			e = 65537
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: e}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwk: unsupported curve %q", k.Crv)
+		}
+		xb, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwk: invalid x: %w", err)
+		}
+		yb, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwk: invalid y: %w", err)
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xb), Y: new(big.Int).SetBytes(yb)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("jwk: point not on curve")
+		}
+		return pub, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwk: unsupported curve %q", k.Crv)
+		}
+		xb, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(xb) != ed25519.PublicKeySize {
+			return nil, errors.New("jwk: invalid ed25519 key")
+		}
+		return ed25519.PublicKey(xb), nil
+	}
+	return nil, fmt.Errorf("jwk: unsupported kty %q", k.Kty)
+}
+
+// Thumbprint calcula el JWK SHA-256 Thumbprint (RFC 7638) en base64url.
+func (k *JWK) Thumbprint() (string, error) {
+	// Miembros requeridos en orden lexicográfico, sin espacios
+	var canon string
+	switch k.Kty {
+	case "RSA":
+		canon = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	case "EC":
+		canon = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, k.Crv, k.X, k.Y)
+	case "OKP":
+		canon = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, k.Crv, k.X)
+	default:
+		return "", fmt.Errorf("jwk: unsupported kty %q", k.Kty)
+	}
+	sum := sha256.Sum256([]byte(canon))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -3,9 +3,12 @@ package jwt
 
 import (
 	"context"
+	"crypto"
 	"crypto/ed25519"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 
 var (
 	ErrNoActiveKey = errors.New("no_active_signing_key")
+	ErrKIDNotFound = errors.New("kid_not_found")
 )
 
 // PersistentKeystore wrappea repository.KeyRepository con caching local.
@@ -33,6 +37,9 @@ type PersistentKeystore struct {
 	lastJWKS  []byte
 	jwksUntil time.Time
 	jwksTTL   time.Duration
+
+	// genLocks serializa la generación de claves por tenant+alg
+	genLocks sync.Map // tenant + "|" + alg -> *sync.Mutex
 }
 
 // NewPersistentKeystore crea un nuevo keystore usando un KeyRepository.
@@ -44,18 +51,18 @@ func NewPersistentKeystore(repo repository.KeyRepository) *PersistentKeystore {
 	}
 }
 
-// EnsureBootstrap genera una clave si no existe ninguna.
+// EnsureBootstrap genera las claves globales que falten: la de firma (EdDSA) y
+// la de cifrado que se publica en el JWKS.
 func (k *PersistentKeystore) EnsureBootstrap(ctx context.Context) error {
 	_, err := k.repo.GetActive(ctx, "")
-	if err == nil {
-		return nil
+	if errors.Is(err, repository.ErrNotFound) {
+		// Generar clave inicial
+		_, err = k.repo.Generate(ctx, "", "EdDSA")
 	}
-	if !errors.Is(err, repository.ErrNotFound) {
+	if err != nil {
 		return err
 	}
-	// Generar clave inicial
-	_, err = k.repo.Generate(ctx, "", "EdDSA")
-	return err
+	return k.EnsureEncryptionKey("")
 }
 
 // Active devuelve la clave activa global (cacheada).
@@ -103,13 +110,67 @@ func (k *PersistentKeystore) ActiveForTenant(tenant string) (kid string, priv ed
 	return key.ID, priv, pub, nil
 }
 
-// PublicKeyByKID devuelve la pubkey por KID (global).
-func (k *PersistentKeystore) PublicKeyByKID(kid string) (ed25519.PublicKey, error) {
-	return k.PublicKeyByKIDForTenant("", kid)
+// ActiveSignerForTenant devuelve la clave activa del tenant (o global si tenant="")
+// para el algoritmo pedido ("" = EdDSA). Si el keyring no tiene clave de ese
+// algoritmo la genera (una sola vez, ver ensureKey), así elegir RS256/ES256 no
+// requiere un paso previo.
+func (k *PersistentKeystore) ActiveSignerForTenant(tenant, alg string) (kid string, priv crypto.Signer, err error) {
+	if alg == "" || alg == AlgEdDSA {
+		kid, edPriv, _, err := k.ActiveForTenant(tenant)
+		return kid, edPriv, err
+	}
+	if !IsSigningAlgSupported(alg) {
+		return "", nil, fmt.Errorf("unsupported signing alg: %s", alg)
+	}
+
+	ctx := context.Background()
+	key, err := k.repo.GetActiveByAlgorithm(ctx, tenant, alg)
+	if errors.Is(err, repository.ErrNotFound) {
+		key, err = k.ensureKey(ctx, tenant, alg)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	signer, ok := key.PrivateKey.(crypto.Signer)
+	if !ok || key.Algorithm != alg {
+		return "", nil, fmt.Errorf("invalid %s private key", alg)
+	}
+	return key.ID, signer, nil
 }
 
-// PublicKeyByKIDForTenant devuelve la pubkey por KID dentro del ámbito de un tenant.
-func (k *PersistentKeystore) PublicKeyByKIDForTenant(tenant, kid string) (ed25519.PublicKey, error) {
+// ensureKey devuelve la clave activa de alg del tenant, generándola si falta.
+// La generación se serializa por tenant+alg y se vuelve a buscar la clave con el
+// lock tomado: requests concurrentes no generan dos claves activas del mismo alg.
+func (k *PersistentKeystore) ensureKey(ctx context.Context, tenant, alg string) (*repository.SigningKey, error) {
+	l, _ := k.genLocks.LoadOrStore(tenant+"|"+alg, &sync.Mutex{})
+	mu := l.(*sync.Mutex)
+	mu.Lock()
+	defer mu.Unlock()
+
+	key, err := k.repo.GetActiveByAlgorithm(ctx, tenant, alg)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return key, err
+	}
+	key, err = k.repo.Generate(ctx, tenant, alg)
+	if err != nil {
+		return nil, err
+	}
+	k.invalidateJWKS()
+	return key, nil
+}
+
+// invalidateJWKS descarta el JWKS global cacheado (tras generar claves nuevas).
+func (k *PersistentKeystore) invalidateJWKS() {
+	k.mu.Lock()
+	k.jwksUntil = time.Time{}
+	k.mu.Unlock()
+}
+
+// VerificationKeyForTenant devuelve la clave pública (ed25519, RSA o ECDSA) por KID
+// dentro del JWKS del tenant ("" = global). Las claves de cifrado (use=enc) no
+// verifican firmas.
+func (k *PersistentKeystore) VerificationKeyForTenant(tenant, kid string) (crypto.PublicKey, error) {
 	if kid == "" {
 		return nil, errors.New("kid_missing")
 	}
@@ -124,25 +185,82 @@ func (k *PersistentKeystore) PublicKeyByKIDForTenant(tenant, kid string) (ed2551
 	}
 	k.mu.RUnlock()
 
-	// Buscar en JWKS del tenant
-	ctx := context.Background()
-	jwks, err := k.repo.GetJWKS(ctx, tenant)
+	jwks, err := k.repo.GetJWKS(context.Background(), tenant)
 	if err != nil {
 		return nil, err
 	}
-
 	for _, jk := range jwks.Keys {
-		if jk.KID == kid {
-			// Decodificar X (base64url) a ed25519.PublicKey
-			xBytes, err := DecodeBase64URL(jk.X)
-			if err != nil {
-				return nil, err
+		if jk.KID == kid && jk.Use != "enc" {
+			j := JWK{Kty: jk.Kty, Crv: jk.Crv, N: jk.N, E: jk.E, X: jk.X, Y: jk.Y}
+			return j.PublicKey()
+		}
+	}
+	return nil, ErrKIDNotFound
+}
+
+// EnsureEncryptionKey genera la clave de cifrado (RSA-OAEP-256) del keyring del
+// tenant ("" = global) si todavía no tiene una. Se llama al preparar el keyring
+// (bootstrap, alta de tenant, rotación), no al servir el JWKS.
+func (k *PersistentKeystore) EnsureEncryptionKey(tenant string) error {
+	_, err := k.ensureKey(context.Background(), tenant, AlgRSAOAEP256)
+	return err
+}
+
+// DecryptionKeyForTenant devuelve la clave privada de cifrado con ese kid, que debe
+// estar publicada en el JWKS del tenant. kid vacío = clave de cifrado activa.
+func (k *PersistentKeystore) DecryptionKeyForTenant(tenant, kid string) (*rsa.PrivateKey, error) {
+	ctx := context.Background()
+	var key *repository.SigningKey
+	if kid == "" {
+		active, err := k.repo.GetActiveByAlgorithm(ctx, tenant, AlgRSAOAEP256)
+		if err != nil {
+			return nil, err
+		}
+		key = active
+	} else {
+		jwks, err := k.repo.GetJWKS(ctx, tenant)
+		if err != nil {
+			return nil, err
+		}
+		published := false
+		for _, jk := range jwks.Keys {
+			if jk.KID == kid && jk.Use == "enc" {
+				published = true
+				break
 			}
-			return ed25519.PublicKey(xBytes), nil
+		}
+		if !published {
+			return nil, ErrKIDNotFound
+		}
+		if key, err = k.repo.GetByKID(ctx, kid); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil, errors.New("kid_not_found")
+	priv, ok := key.PrivateKey.(*rsa.PrivateKey)
+	if !ok || key.Algorithm != AlgRSAOAEP256 {
+		return nil, errors.New("invalid encryption key")
+	}
+	return priv, nil
+}
+
+// PublicKeyByKID devuelve la pubkey por KID (global).
+func (k *PersistentKeystore) PublicKeyByKID(kid string) (ed25519.PublicKey, error) {
+	return k.PublicKeyByKIDForTenant("", kid)
+}
+
+// PublicKeyByKIDForTenant devuelve la pubkey Ed25519 por KID dentro del ámbito de un tenant.
+// Para claves de cualquier algoritmo usar VerificationKeyForTenant.
+func (k *PersistentKeystore) PublicKeyByKIDForTenant(tenant, kid string) (ed25519.PublicKey, error) {
+	pub, err := k.VerificationKeyForTenant(tenant, kid)
+	if err != nil {
+		return nil, err
+	}
+	edPub, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("kid_not_ed25519")
+	}
+	return edPub, nil
 }
 
 // JWKSJSON construye el JWKS JSON global (cacheado).
@@ -184,9 +302,21 @@ func (k *PersistentKeystore) JWKSJSONForTenant(tenant string) ([]byte, error) {
 	return data, nil
 }
 
-// RotateFor rota las claves de un tenant.
+// RotateFor rota las claves de un tenant (todos los algoritmos del keyring). Si
+// el keyring todavía no tenía clave de cifrado, la genera.
 func (k *PersistentKeystore) RotateFor(tenant string, graceSeconds int64) (*repository.SigningKey, error) {
 	ctx := context.Background()
 	gracePeriod := time.Duration(graceSeconds) * time.Second
-	return k.repo.Rotate(ctx, tenant, gracePeriod)
+	key, err := k.repo.Rotate(ctx, tenant, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	k.mu.Lock()
+	k.cacheUntil = time.Time{}
+	k.jwksUntil = time.Time{}
+	k.mu.Unlock()
+	if err := k.EnsureEncryptionKey(tenant); err != nil {
+		return nil, err
+	}
+	return key, nil
 }
@@ -8,14 +8,15 @@ import (
 	jwtv5 "github.com/golang-jwt/jwt/v5"
 )
 
-// ParseEdDSA valida firma (EdDSA) usando el keystore (por kid o activa),
+// ParseEdDSA valida firma usando el keystore (por kid o activa),
 // chequea iss (si expectedIss != ""), y valida exp/nbf con una pequeña tolerancia.
+// Acepta todos los SigningAlgs (el nombre quedó de cuando solo había EdDSA).
 // Devuelve las claims como map[string]any.
 func ParseEdDSA(token string, ks *PersistentKeystore, expectedIss string) (map[string]any, error) {
 	keyfunc := func(t *jwtv5.Token) (any, error) {
 		kid, _ := t.Header["kid"].(string)
 		if kid != "" {
-			return ks.PublicKeyByKID(kid)
+			return ks.VerificationKeyForTenant("", kid)
 		}
 		_, _, pub, err := ks.Active()
 		if err != nil {
@@ -24,7 +25,7 @@ func ParseEdDSA(token string, ks *PersistentKeystore, expectedIss string) (map[s
 		return ed25519.PublicKey(pub), nil
 	}
 
-	tok, err := jwtv5.Parse(token, keyfunc, jwtv5.WithValidMethods([]string{"EdDSA"}))
+	tok, err := jwtv5.Parse(token, keyfunc, jwtv5.WithValidMethods(SigningAlgs))
 	if err != nil || !tok.Valid {
 		return nil, errors.New("invalid_jwt")
 	}
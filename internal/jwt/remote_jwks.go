@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/security/netguard"
+)
+
+const maxRemoteJWKSSize = 256 << 10 // 256KB
+
+// RemoteJWKSMinRefresh es el intervalo mínimo entre re-descargas forzadas de un
+// mismo jwks_uri (ver Refresh).
+const RemoteJWKSMinRefresh = time.Minute
+
+type remoteJWKSEntry struct {
+	set     *JWKSet
+	exp     time.Time
+	fetched time.Time // última descarga (o intento de Refresh)
+}
+
+// RemoteJWKSCache descarga y cachea JWKS remotos (ej. jwks_uri de clients).
+type RemoteJWKSCache struct {
+	mu    sync.RWMutex
+	ttl   time.Duration
+	http  *http.Client
+	items map[string]remoteJWKSEntry // uri -> entry
+}
+
+// NewRemoteJWKSCache crea un cache de JWKS remotos. ttl <= 0 usa 10 minutos.
+func NewRemoteJWKSCache(ttl time.Duration) *RemoteJWKSCache {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return &RemoteJWKSCache{
+		ttl:   ttl,
+		http:  netguard.NewClient(5 * time.Second), // jwks_uri lo elige el client: solo IPs públicas
+		items: make(map[string]remoteJWKSEntry),
+	}
+}
+
+// Get retorna el JWKS de uri, descargándolo si no está en cache o expiró.
+func (c *RemoteJWKSCache) Get(ctx context.Context, uri string) (*JWKSet, error) {
+	now := time.Now()
+
+	c.mu.RLock()
+	if e, ok := c.items[uri]; ok && now.Before(e.exp) {
+		c.mu.RUnlock()
+		return e.set, nil
+	}
+	c.mu.RUnlock()
+
+	set, err := c.fetch(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.items[uri] = remoteJWKSEntry{set: set, exp: now.Add(c.ttl), fetched: now}
+	c.mu.Unlock()
+	return set, nil
+}
+
+// Refresh re-descarga el JWKS de uri (ej. kid desconocido tras una rotación del
+// client), pero a lo sumo una vez cada RemoteJWKSMinRefresh: dentro de ese
+// intervalo devuelve lo cacheado, así un kid inventado no dispara una descarga
+// por request. Si la descarga falla se mantiene el JWKS anterior.
+func (c *RemoteJWKSCache) Refresh(ctx context.Context, uri string) (*JWKSet, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	e, ok := c.items[uri]
+	if !ok {
+		c.mu.Unlock()
+		return c.Get(ctx, uri)
+	}
+	if now.Sub(e.fetched) < RemoteJWKSMinRefresh {
+		c.mu.Unlock()
+		return e.set, nil
+	}
+	// Marcado antes de descargar: los requests concurrentes usan el JWKS actual
+	e.fetched = now
+	c.items[uri] = e
+	c.mu.Unlock()
+
+	set, err := c.fetch(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.items[uri] = remoteJWKSEntry{set: set, exp: now.Add(c.ttl), fetched: now}
+	c.mu.Unlock()
+	return set, nil
+}
+
+func (c *RemoteJWKSCache) fetch(ctx context.Context, uri string) (*JWKSet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("fetch jwks: http %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// EncryptionKey resuelve la clave de cifrado para alg del JWKS de un client:
+// el inline si lo tiene, si no el de jwks_uri (re-descargado una vez si ninguna
+// clave sirve, por si el client rotó; ver Refresh).
+func (c *RemoteJWKSCache) EncryptionKey(ctx context.Context, inline, uri, alg string) (*JWK, error) {
+	if inline != "" {
+		set, err := ParseJWKS([]byte(inline))
+		if err != nil {
+			return nil, err
+		}
+		return set.FindEncryption(alg)
+	}
+	if uri == "" {
+		return nil, ErrJWKNotFound
+	}
+	set, err := c.Get(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	k, err := set.FindEncryption(alg)
+	if errors.Is(err, ErrJWKNotFound) {
+		if set, err = c.Refresh(ctx, uri); err != nil {
+			return nil, err
+		}
+		k, err = set.FindEncryption(alg)
+	}
+	return k, err
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoteJWKSRefreshIsRateLimited(t *testing.T) {
+	var kid atomic.Value
+	kid.Store("k1")
+	var fetches atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_, _ = w.Write([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"` + kid.Load().(string) + `","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewRemoteJWKSCache(time.Hour)
+	c.http = srv.Client()
+	ctx := context.Background()
+
+	set, err := c.Get(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.Find("k2"); !errors.Is(err, ErrJWKNotFound) {
+		t.Fatalf("unexpected kid k2 before rotation: %v", err)
+	}
+
+	// Unknown kids right after a download don't trigger another one
+	for i := 0; i < 5; i++ {
+		if _, err := c.Refresh(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("downloads = %d, want 1", n)
+	}
+
+	// Once the interval has passed, Refresh downloads the rotated set
+	kid.Store("k2")
+	c.mu.Lock()
+	e := c.items[srv.URL]
+	e.fetched = time.Now().Add(-RemoteJWKSMinRefresh)
+	c.items[srv.URL] = e
+	c.mu.Unlock()
+
+	set, err = c.Refresh(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.Find("k2"); err != nil {
+		t.Fatalf("rotated key not found: %v", err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Fatalf("downloads = %d, want 2", n)
+	}
+}
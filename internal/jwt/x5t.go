@@ -0,0 +1,27 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+)
+
+// CertificateThumbprint retorna el x5t#S256 de un certificado:
+// base64url(SHA-256(DER)) (RFC 8705 §3.1).
+func CertificateThumbprint(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ConfirmationX5T retorna el thumbprint cnf."x5t#S256" de un token ligado a un certificado ("" si no está ligado).
+func ConfirmationX5T(claims map[string]any) string {
+	cnf, ok := claims["cnf"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	x5t, _ := cnf["x5t#S256"].(string)
+	return x5t
+}
@@ -6,8 +6,11 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -19,6 +22,16 @@ func GenerateEd25519() (ed25519.PublicKey, ed25519.PrivateKey, error) {
 	return pub, priv, err
 }
 
+// GenerateRSA genera una clave RSA de 2048 bits (RS256).
+func GenerateRSA() (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, 2048)
+}
+
+// GenerateP256 genera una clave ECDSA P-256 (ES256).
+func GenerateP256() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
 // EncryptPrivateKey cifra una clave privada usando AES-GCM con una master key en formato hex.
 // El resultado tiene el prefijo "GCMV1" para identificación del formato.
 func EncryptPrivateKey(privateKey []byte, masterKeyHex string) ([]byte, error) {
@@ -0,0 +1,83 @@
+// Package netguard protege las descargas server-side hacia URLs que registran
+// los clients (jwks_uri, sector_identifier_uri, backchannel_logout_uri) contra
+// SSRF.
+//
+// El chequeo se hace al conectar, sobre la IP ya resuelta: validar el host al
+// registrar el client no alcanza, porque el DNS puede cambiar después (DNS
+// rebinding). Se rechazan loopback, redes privadas, link-local (incluida la
+// metadata de los clouds en 169.254.169.254), CGNAT, multicast y no especificadas.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress indica que el destino resolvió a una IP no pública.
+var ErrForbiddenAddress = errors.New("netguard: destination address not allowed")
+
+// AllowPrivate desactiva el chequeo (solo desarrollo o despliegues donde los
+// relying parties viven en la red interna). Se configura al arrancar.
+var AllowPrivate bool
+
+// cgnat es el rango de Shared Address Space (RFC 6598), que netip no clasifica.
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublic indica si ip es una dirección pública a la que se puede conectar.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	switch {
+	case !ip.IsValid(),
+		ip.IsUnspecified(),
+		ip.IsLoopback(),
+		ip.IsPrivate(),
+		ip.IsLinkLocalUnicast(),
+		ip.IsLinkLocalMulticast(),
+		ip.IsInterfaceLocalMulticast(),
+		ip.IsMulticast(),
+		cgnat.Contains(ip),
+		ip.Is4() && ip.As4()[0] == 0: // 0.0.0.0/8 ("this network")
+		return false
+	}
+	return true
+}
+
+// control se ejecuta por cada conexión, después de resolver el host.
+func control(network, address string, _ syscall.RawConn) error {
+	if AllowPrivate {
+		return nil
+	}
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	if !IsPublic(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ap.Addr())
+	}
+	return nil
+}
+
+// NewClient crea un http.Client que solo conecta a direcciones públicas, también
+// al seguir redirects. No usa proxies del entorno: el chequeo tiene que ver el
+// destino real.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: control,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,230 @@
+// Package pairwise implementa los subject identifiers pairwise (OIDC Core §8.1).
+//
+// El sub pairwise es determinista por (sector, usuario) y no permite correlacionar
+// usuarios entre sectores. Se deriva con cifrado determinista (AES-GCM con nonce
+// = HMAC(sector, usuario)) sobre claves derivadas de SECRETBOX_MASTER_KEY, por lo
+// que también se puede revertir al ID local sin guardar una tabla de mapeo
+// (id_token_hint, token exchange, userinfo).
+package pairwise
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/security/netguard"
+	"github.com/dropDatabas3/hellojohn/internal/security/secretbox"
+)
+
+// Tipos de subject (OIDC Core §8, client metadata subject_type).
+const (
+	SubjectTypePublic   = "public"
+	SubjectTypePairwise = "pairwise"
+)
+
+const (
+	labelEnc   = "hellojohn/pairwise/enc"
+	labelNonce = "hellojohn/pairwise/nonce"
+	nonceSize  = 12
+)
+
+// ErrInvalidSubject indica un sub que no fue emitido para ese sector.
+var ErrInvalidSubject = errors.New("pairwise: invalid subject")
+
+// IsPairwise indica si el client recibe subs pairwise.
+func IsPairwise(c *repository.Client) bool {
+	return c != nil && c.SubjectType == SubjectTypePairwise
+}
+
+// Sector devuelve el sector identifier del client: el host de su
+// sector_identifier_uri o, si no tiene, el de su primera redirect_uri.
+// Clients sin redirect_uris (device, client_credentials) usan su client_id.
+func Sector(c *repository.Client) string {
+	if c.SectorIdentifierURI != "" {
+		if u, err := url.Parse(c.SectorIdentifierURI); err == nil && u.Host != "" {
+			return strings.ToLower(u.Host)
+		}
+	}
+	for _, r := range c.RedirectURIs {
+		if u, err := url.Parse(r); err == nil && u.Host != "" {
+			return strings.ToLower(u.Host)
+		}
+	}
+	return "client:" + c.ClientID
+}
+
+// SubjectFor devuelve el sub que ve el client para el usuario local userID.
+// Para clients public es el mismo userID.
+func SubjectFor(c *repository.Client, userID string) (string, error) {
+	if !IsPairwise(c) || userID == "" {
+		return userID, nil
+	}
+	return Subject(Sector(c), userID)
+}
+
+// LocalSubject revierte SubjectFor: devuelve el ID local del usuario detrás
+// de un sub emitido al client.
+func LocalSubject(c *repository.Client, sub string) (string, error) {
+	if !IsPairwise(c) || sub == "" {
+		return sub, nil
+	}
+	return Resolve(Sector(c), sub)
+}
+
+// ClientLookup busca un client del tenant por client_id (nil si no existe).
+type ClientLookup func(clientID string) *repository.Client
+
+// LocalSubjectFromClaims devuelve el ID local del usuario de un token emitido por
+// este servidor. El sub sigue al client del aud (si es único y es un client) o,
+// si no, a azp / client_id (token exchange hacia una API); sin client se asume public.
+func LocalSubjectFromClaims(claims map[string]any, lookup ClientLookup) (string, error) {
+	sub, _ := claims["sub"].(string)
+	if sub == "" || lookup == nil {
+		return sub, nil
+	}
+
+	var candidates []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		candidates = append(candidates, aud)
+	case []any:
+		if len(aud) == 1 {
+			if a, ok := aud[0].(string); ok {
+				candidates = append(candidates, a)
+			}
+		}
+	case []string:
+		if len(aud) == 1 {
+			candidates = append(candidates, aud[0])
+		}
+	}
+	for _, k := range []string{"azp", "client_id"} {
+		if v, ok := claims[k].(string); ok {
+			candidates = append(candidates, v)
+		}
+	}
+
+	for _, id := range candidates {
+		if id == "" {
+			continue
+		}
+		if c := lookup(id); c != nil {
+			return LocalSubject(c, sub)
+		}
+	}
+	return sub, nil
+}
+
+// Subject calcula el sub pairwise de userID en sector.
+func Subject(sector, userID string) (string, error) {
+	aead, nonceKey, err := keys()
+	if err != nil {
+		return "", err
+	}
+	nonce := deriveNonce(nonceKey, sector, userID)
+	ct := aead.Seal(nil, nonce, []byte(userID), []byte(sector))
+	return base64.RawURLEncoding.EncodeToString(append(nonce, ct...)), nil
+}
+
+// Resolve devuelve el userID detrás de un sub pairwise de sector.
+func Resolve(sector, sub string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(sub)
+	if err != nil || len(raw) <= nonceSize {
+		return "", ErrInvalidSubject
+	}
+	aead, nonceKey, err := keys()
+	if err != nil {
+		return "", err
+	}
+	nonce, ct := raw[:nonceSize], raw[nonceSize:]
+	pt, err := aead.Open(nil, nonce, ct, []byte(sector))
+	if err != nil {
+		return "", ErrInvalidSubject
+	}
+	userID := string(pt)
+	// Solo se acepta la forma canónica (la que emite Subject)
+	if !hmac.Equal(nonce, deriveNonce(nonceKey, sector, userID)) {
+		return "", ErrInvalidSubject
+	}
+	return userID, nil
+}
+
+func keys() (cipher.AEAD, []byte, error) {
+	encKey, err := secretbox.DeriveKey(labelEnc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pairwise: %w", err)
+	}
+	nonceKey, err := secretbox.DeriveKey(labelNonce)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pairwise: %w", err)
+	}
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, nonceKey, nil
+}
+
+func deriveNonce(key []byte, sector, userID string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(sector))
+	m.Write([]byte{0})
+	m.Write([]byte(userID))
+	return m.Sum(nil)[:nonceSize]
+}
+
+// sectorHTTPClient se usa para descargar los sector_identifier_uri (solo IPs públicas).
+var sectorHTTPClient = netguard.NewClient(5 * time.Second)
+
+// VerifySectorIdentifier descarga el sector_identifier_uri (JSON array de URIs)
+// y comprueba que incluya todas las redirect_uris del client (OIDC Registration §5).
+func VerifySectorIdentifier(ctx context.Context, sectorURI string, redirectURIs []string) error {
+	u, err := url.Parse(sectorURI)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("sector_identifier_uri must be an https URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sectorURI, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := sectorHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch sector_identifier_uri: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch sector_identifier_uri: status %d", resp.StatusCode)
+	}
+
+	var listed []string
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&listed); err != nil {
+		return fmt.Errorf("sector_identifier_uri must return a JSON array: %w", err)
+	}
+	set := make(map[string]bool, len(listed))
+	for _, r := range listed {
+		set[r] = true
+	}
+	for _, r := range redirectURIs {
+		if !set[r] {
+			return fmt.Errorf("redirect_uri %q not listed in sector_identifier_uri", r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package pairwise
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/dropDatabas3/hellojohn/internal/security/secretbox"
+)
+
+func setMasterKey(t *testing.T, seed byte) {
+	t.Helper()
+	t.Setenv("SECRETBOX_MASTER_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{seed}, 32)))
+	secretbox.UnsafeResetSecretBoxForTests()
+	t.Cleanup(secretbox.UnsafeResetSecretBoxForTests)
+}
+
+func TestSubjectDeterministicAndReversible(t *testing.T) {
+	setMasterKey(t, 1)
+
+	a, err := Subject("app.example.com", "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Subject("app.example.com", "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Fatalf("subject not deterministic: %s vs %s", a, b)
+	}
+	if a == "user-1" {
+		t.Fatal("pairwise subject leaks the local ID")
+	}
+
+	got, err := Resolve("app.example.com", a)
+	if err != nil || got != "user-1" {
+		t.Fatalf("Resolve = %q, %v", got, err)
+	}
+}
+
+func TestSubjectSeparatesSectorsAndUsers(t *testing.T) {
+	setMasterKey(t, 1)
+
+	s1, _ := Subject("app.example.com", "user-1")
+	s2, _ := Subject("other.example.org", "user-1")
+	u2, _ := Subject("app.example.com", "user-2")
+	if s1 == s2 {
+		t.Error("same subject in two sectors")
+	}
+	if s1 == u2 {
+		t.Error("same subject for two users")
+	}
+
+	// Un sub no se puede usar en otro sector
+	if _, err := Resolve("other.example.org", s1); !errors.Is(err, ErrInvalidSubject) {
+		t.Errorf("subject resolved in another sector: %v", err)
+	}
+}
+
+func TestSubjectDependsOnMasterKey(t *testing.T) {
+	setMasterKey(t, 1)
+	s1, _ := Subject("app.example.com", "user-1")
+
+	setMasterKey(t, 2)
+	s2, _ := Subject("app.example.com", "user-1")
+	if s1 == s2 {
+		t.Error("subject does not depend on the master key")
+	}
+	if _, err := Resolve("app.example.com", s1); !errors.Is(err, ErrInvalidSubject) {
+		t.Errorf("subject from another key resolved: %v", err)
+	}
+}
+
+func TestResolveRejectsMalformed(t *testing.T) {
+	setMasterKey(t, 1)
+	sub, _ := Subject("app.example.com", "user-1")
+	raw, _ := base64.RawURLEncoding.DecodeString(sub)
+	raw[len(raw)-1] ^= 0x01
+
+	cases := map[string]string{
+		"not base64": "%%%",
+		"too short":  base64.RawURLEncoding.EncodeToString(make([]byte, nonceSize)),
+		"tampered":   base64.RawURLEncoding.EncodeToString(raw),
+		"local id":   "user-1",
+	}
+	for name, s := range cases {
+		if _, err := Resolve("app.example.com", s); !errors.Is(err, ErrInvalidSubject) {
+			t.Errorf("%s: expected ErrInvalidSubject, got %v", name, err)
+		}
+	}
+}
+
+func TestSectorAndSubjectFor(t *testing.T) {
+	setMasterKey(t, 1)
+
+	withSector := &repository.Client{
+		ClientID:            "a",
+		SubjectType:         SubjectTypePairwise,
+		SectorIdentifierURI: "https://Sector.Example.com/uris.json",
+		RedirectURIs:        []string{"https://app1.example.com/cb"},
+	}
+	sameSector := &repository.Client{
+		ClientID:            "b",
+		SubjectType:         SubjectTypePairwise,
+		SectorIdentifierURI: "https://sector.example.com/other.json",
+		RedirectURIs:        []string{"https://app2.example.com/cb"},
+	}
+	byRedirect := &repository.Client{
+		ClientID:     "c",
+		SubjectType:  SubjectTypePairwise,
+		RedirectURIs: []string{"https://app1.example.com/cb"},
+	}
+	noRedirect := &repository.Client{ClientID: "d", SubjectType: SubjectTypePairwise}
+	public := &repository.Client{ClientID: "e", SubjectType: SubjectTypePublic}
+
+	if got := Sector(withSector); got != "sector.example.com" {
+		t.Errorf("Sector = %q", got)
+	}
+	if got := Sector(byRedirect); got != "app1.example.com" {
+		t.Errorf("Sector = %q", got)
+	}
+	if got := Sector(noRedirect); got != "client:d" {
+		t.Errorf("Sector = %q", got)
+	}
+
+	// Clients del mismo sector ven el mismo sub
+	a, _ := SubjectFor(withSector, "user-1")
+	b, _ := SubjectFor(sameSector, "user-1")
+	c, _ := SubjectFor(byRedirect, "user-1")
+	if a != b {
+		t.Error("clients sharing a sector_identifier_uri host got different subjects")
+	}
+	if a == c {
+		t.Error("clients in different sectors got the same subject")
+	}
+	if local, err := LocalSubject(byRedirect, c); err != nil || local != "user-1" {
+		t.Errorf("LocalSubject = %q, %v", local, err)
+	}
+
+	if sub, _ := SubjectFor(public, "user-1"); sub != "user-1" {
+		t.Errorf("public client got %q", sub)
+	}
+}
+
+func TestLocalSubjectFromClaims(t *testing.T) {
+	setMasterKey(t, 1)
+
+	client := &repository.Client{ClientID: "web", SubjectType: SubjectTypePairwise, RedirectURIs: []string{"https://app.example.com/cb"}}
+	lookup := func(id string) *repository.Client {
+		if id == "web" {
+			return client
+		}
+		return nil
+	}
+	sub, _ := SubjectFor(client, "user-1")
+
+	cases := map[string]map[string]any{
+		"aud string": {"sub": sub, "aud": "web"},
+		"aud list":   {"sub": sub, "aud": []any{"web"}},
+		"azp":        {"sub": sub, "aud": "https://api.example.com", "azp": "web"},
+		"client_id":  {"sub": sub, "aud": []any{"https://api.example.com"}, "client_id": "web"},
+	}
+	for name, claims := range cases {
+		if got, err := LocalSubjectFromClaims(claims, lookup); err != nil || got != "user-1" {
+			t.Errorf("%s: got %q, %v", name, got, err)
+		}
+	}
+
+	// Sin client conocido el sub se toma como public
+	if got, _ := LocalSubjectFromClaims(map[string]any{"sub": "user-2", "aud": "unknown"}, lookup); got != "user-2" {
+		t.Errorf("unknown client: got %q", got)
+	}
+}
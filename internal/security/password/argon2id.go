@@ -53,11 +53,8 @@ func decodeB64(s string) ([]byte, error) {
 	return base64.RawURLEncoding.DecodeString(s)
 }
 
-// Verify valida un PHC string generado por Hash contra la contraseña en claro.
-func Verify(plain, phc string) bool {
-	if plain == "" || phc == "" {
-		return false
-	}
+// verifyArgon2id valida un PHC string generado por Hash contra la contraseña en claro.
+func verifyArgon2id(plain, phc string) bool {
 	// Formato PHC esperado:
 	// $argon2id$v=19$m=65536,t=3,p=1$<saltB64>$<dkB64>
 	parts := strings.Split(phc, "$")
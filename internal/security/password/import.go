@@ -0,0 +1,112 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errores de import de hashes.
+var (
+	ErrUnsupportedHash       = errors.New("password: unsupported hash format")
+	ErrHashAlgorithmMismatch = errors.New("password: hash does not match algorithm hint")
+)
+
+// Imported es un hash exportado de otro sistema (Auth0, Keycloak, un legacy propio).
+type Imported struct {
+	// Algorithm es el hint del formato (AlgBcrypt, AlgPBKDF2SHA256, ...).
+	// Vacío = se detecta por el prefijo del hash.
+	Algorithm string
+	// Hash es el hash codificado ($2b$..., $6$..., $pbkdf2-sha256$...) o, en
+	// exports estilo Keycloak, sólo el digest en base64 (secretData.value).
+	Hash string
+	// Salt e Iterations completan un digest suelto (Keycloak: secretData.salt y
+	// credentialData.hashIterations). Sólo aplican a PBKDF2.
+	Salt       string
+	Iterations int
+}
+
+// Import valida un hash importado y lo devuelve en el formato que entiende
+// Verify. El hash queda tal cual hasta el primer login exitoso, donde se
+// reemplaza por argon2id (ver NeedsRehash).
+func Import(in Imported) (string, error) {
+	alg := normalizeAlgorithm(in.Algorithm)
+	hash := strings.TrimSpace(in.Hash)
+	if hash == "" {
+		return "", ErrUnsupportedHash
+	}
+
+	// Digest suelto + salt + iteraciones (Keycloak)
+	if in.Salt != "" || in.Iterations > 0 {
+		switch alg {
+		case AlgPBKDF2SHA1, AlgPBKDF2SHA256, AlgPBKDF2SHA512:
+		default:
+			return "", fmt.Errorf("%w: salt/iterations only apply to pbkdf2", ErrUnsupportedHash)
+		}
+		salt, err := decodeLegacyB64(strings.TrimSpace(in.Salt))
+		if err != nil || len(salt) == 0 {
+			return "", fmt.Errorf("%w: invalid salt", ErrUnsupportedHash)
+		}
+		dk, err := decodeLegacyB64(hash)
+		if err != nil || len(dk) == 0 || len(dk) > maxKeyLen {
+			return "", fmt.Errorf("%w: invalid digest", ErrUnsupportedHash)
+		}
+		if in.Iterations <= 0 || in.Iterations > maxPBKDF2Iterations {
+			return "", fmt.Errorf("%w: invalid iterations", ErrUnsupportedHash)
+		}
+		return encodePBKDF2(alg, in.Iterations, salt, dk), nil
+	}
+
+	detected := Identify(hash)
+	if detected == "" {
+		return "", ErrUnsupportedHash
+	}
+	if alg != "" && alg != detected {
+		return "", fmt.Errorf("%w: %s vs %s", ErrHashAlgorithmMismatch, alg, detected)
+	}
+	if !wellFormed(detected, hash) {
+		return "", fmt.Errorf("%w: malformed %s hash", ErrUnsupportedHash, detected)
+	}
+	return hash, nil
+}
+
+// wellFormed chequea que el hash se pueda parsear (sin verificar contraseña),
+// para rechazar en el import lo que nunca va a poder validar.
+func wellFormed(alg, hash string) bool {
+	switch alg {
+	case AlgPBKDF2SHA1, AlgPBKDF2SHA256, AlgPBKDF2SHA512:
+		_, ok := parsePBKDF2(hash)
+		return ok
+	case AlgBcrypt:
+		return len(hash) == 60
+	default:
+		// argon2id, scrypt y SHA-crypt se validan recién en Verify
+		return strings.Count(hash, "$") >= 3
+	}
+}
+
+// normalizeAlgorithm acepta las variantes habituales del nombre
+// ("PBKDF2_SHA256", "sha512crypt", "2b", ...).
+func normalizeAlgorithm(alg string) string {
+	alg = strings.ToLower(strings.TrimSpace(alg))
+	alg = strings.ReplaceAll(alg, "_", "-")
+	switch alg {
+	case "":
+		return ""
+	case "bcrypt", "2a", "2b", "2y":
+		return AlgBcrypt
+	case "pbkdf2", "pbkdf2-sha1":
+		// Keycloak llama "pbkdf2" a PBKDF2WithHmacSHA1
+		return AlgPBKDF2SHA1
+	case "pbkdf2-sha256", "pbkdf2-hmac-sha256":
+		return AlgPBKDF2SHA256
+	case "pbkdf2-sha512", "pbkdf2-hmac-sha512":
+		return AlgPBKDF2SHA512
+	case "sha256-crypt", "sha256crypt", "sha-256-crypt":
+		return AlgSHA256Crypt
+	case "sha512-crypt", "sha512crypt", "sha-512-crypt":
+		return AlgSHA512Crypt
+	default:
+		return alg // argon2id, scrypt o desconocido (falla en la comparación)
+	}
+}
@@ -0,0 +1,82 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestImportKeycloakCredential(t *testing.T) {
+	// secretData / credentialData de un export de Keycloak (pbkdf2-sha256, 64 bytes)
+	encoded, err := Import(Imported{
+		Algorithm:  "pbkdf2-sha256",
+		Hash:       "xa7RFWf5K9zLJoQuy4srf2humLsWMUXf57SHK47m/2LxSD6I7EV36N4EghZKr2hmLNzOCaSjyZzR+aO3ty8UnQ==",
+		Salt:       "a2V5Y2xvYWstc2FsdC0xNg==",
+		Iterations: 27500,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Identify(encoded) != AlgPBKDF2SHA256 {
+		t.Fatalf("unexpected format: %s", encoded)
+	}
+	if !Verify("S3cret!", encoded) || Verify("s3cret!", encoded) {
+		t.Fatalf("imported keycloak hash does not verify: %s", encoded)
+	}
+
+	// Keycloak "pbkdf2" es PBKDF2WithHmacSHA1
+	encoded, err = Import(Imported{
+		Algorithm:  "pbkdf2",
+		Hash:       "+tlvvilPkcXGAYqbISvM2gCGR0/Ph315G4BcfJiADr2Aw2CZUYLn01fb6flIkC54ycSyCr6Rr+/YdSPV78pitw==",
+		Salt:       "a2V5Y2xvYWstc2FsdC0xNg==",
+		Iterations: 27500,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Identify(encoded) != AlgPBKDF2SHA1 || !Verify("S3cret!", encoded) {
+		t.Fatalf("imported keycloak sha1 hash does not verify: %s", encoded)
+	}
+}
+
+func TestImportEncodedHashes(t *testing.T) {
+	cases := []Imported{
+		{Hash: "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+		{Algorithm: "sha512crypt", Hash: "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		{Algorithm: "SHA256_CRYPT", Hash: "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA"},
+		{Algorithm: "scrypt", Hash: "$scrypt$ln=10,r=8,p=1$c2NyeXB0LXNhbHQtMDAwMQ$0VIoOyjy9wOgdaOEq9ZMhGhPKY2f.0PVF59sTpze1DY"},
+		{Hash: "$pbkdf2$131000$MDEyMzQ1Njc4OWFiY2RlZg$psSehCxCX0ECqfbI2etYTXYpLDM"},
+	}
+	for _, in := range cases {
+		got, err := Import(in)
+		if err != nil {
+			t.Errorf("%s: %v", in.Hash, err)
+			continue
+		}
+		if got != in.Hash {
+			t.Errorf("encoded hash changed on import: %s -> %s", in.Hash, got)
+		}
+	}
+}
+
+func TestImportRejects(t *testing.T) {
+	cases := []struct {
+		name string
+		in   Imported
+		err  error
+	}{
+		{"empty", Imported{}, ErrUnsupportedHash},
+		{"unknown prefix", Imported{Hash: "$1$saltsalt$hash"}, ErrUnsupportedHash},
+		{"plain digest without salt", Imported{Algorithm: "pbkdf2-sha256", Hash: "abc"}, ErrUnsupportedHash},
+		{"algorithm mismatch", Imported{Algorithm: "bcrypt", Hash: "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"}, ErrHashAlgorithmMismatch},
+		{"truncated bcrypt", Imported{Hash: "$2b$10$abcdefghijklmnopqrstuv"}, ErrUnsupportedHash},
+		{"salt for scrypt", Imported{Algorithm: "scrypt", Hash: "abc", Salt: "c2FsdA==", Iterations: 1}, ErrUnsupportedHash},
+		{"missing iterations", Imported{Algorithm: "pbkdf2-sha256", Hash: "YWJj", Salt: "c2FsdA=="}, ErrUnsupportedHash},
+		{"too many iterations", Imported{Algorithm: "pbkdf2-sha256", Hash: "YWJj", Salt: "c2FsdA==", Iterations: 100_000_000}, ErrUnsupportedHash},
+		{"malformed pbkdf2", Imported{Hash: "$pbkdf2-sha256$i=abc$c2FsdA$YWJj"}, ErrUnsupportedHash},
+	}
+	for _, c := range cases {
+		if _, err := Import(c.in); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package password
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Límites de costo para hashes importados: un hash con parámetros absurdos no
+// debe poder usarse para tumbar el server en cada intento de login.
+const (
+	maxPBKDF2Iterations = 10_000_000
+	maxScryptLogN       = 20 // N = 2^20 (1 GiB con r=8)
+	maxScryptRP         = 1 << 10
+	maxKeyLen           = 128
+)
+
+// ─── bcrypt ───
+
+func verifyBcrypt(plain, encoded string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain)) == nil
+}
+
+// ─── PBKDF2 ───
+
+// pbkdf2Hash es un hash PBKDF2 decodificado. Formatos aceptados:
+//
+//	$pbkdf2-sha256$i=27500$<salt>$<hash>   (PHC, lo que genera Import)
+//	$pbkdf2-sha256$29000$<salt>$<hash>     (passlib; base64 con "." en vez de "+")
+//	$pbkdf2$29000$<salt>$<hash>            (passlib pbkdf2_sha1)
+type pbkdf2Hash struct {
+	alg        string
+	iterations int
+	salt, dk   []byte
+}
+
+func parsePBKDF2(encoded string) (*pbkdf2Hash, bool) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[0] != "" {
+		return nil, false
+	}
+	alg := Identify(encoded)
+	iter, err := strconv.Atoi(strings.TrimPrefix(parts[2], "i="))
+	if err != nil || iter <= 0 || iter > maxPBKDF2Iterations {
+		return nil, false
+	}
+	salt, err := decodeLegacyB64(parts[3])
+	if err != nil || len(salt) == 0 {
+		return nil, false
+	}
+	dk, err := decodeLegacyB64(parts[4])
+	if err != nil || len(dk) == 0 || len(dk) > maxKeyLen {
+		return nil, false
+	}
+	return &pbkdf2Hash{alg: alg, iterations: iter, salt: salt, dk: dk}, true
+}
+
+func verifyPBKDF2(plain, encoded string) bool {
+	h, ok := parsePBKDF2(encoded)
+	if !ok {
+		return false
+	}
+	dk := pbkdf2.Key([]byte(plain), h.salt, h.iterations, len(h.dk), pbkdf2Func(h.alg))
+	return subtle.ConstantTimeCompare(dk, h.dk) == 1
+}
+
+func pbkdf2Func(alg string) func() hash.Hash {
+	switch alg {
+	case AlgPBKDF2SHA1:
+		return sha1.New
+	case AlgPBKDF2SHA512:
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+// encodePBKDF2 arma el formato PHC de un hash PBKDF2.
+func encodePBKDF2(alg string, iterations int, salt, dk []byte) string {
+	return "$" + alg + "$i=" + strconv.Itoa(iterations) + "$" +
+		base64.RawStdEncoding.EncodeToString(salt) + "$" +
+		base64.RawStdEncoding.EncodeToString(dk)
+}
+
+// ─── scrypt ───
+
+// verifyScrypt valida hashes $scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<hash>
+// (passlib / PHC).
+func verifyScrypt(plain, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[0] != "" {
+		return false
+	}
+	var ln, r, p int
+	for _, kv := range strings.Split(parts[2], ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return false
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return false
+		}
+		switch k {
+		case "ln":
+			ln = n
+		case "r":
+			r = n
+		case "p":
+			p = n
+		}
+	}
+	if ln <= 0 || ln > maxScryptLogN || r <= 0 || p <= 0 || r*p > maxScryptRP {
+		return false
+	}
+	salt, err := decodeLegacyB64(parts[3])
+	if err != nil || len(salt) == 0 {
+		return false
+	}
+	dkStored, err := decodeLegacyB64(parts[4])
+	if err != nil || len(dkStored) == 0 || len(dkStored) > maxKeyLen {
+		return false
+	}
+	dk, err := scrypt.Key([]byte(plain), salt, 1<<ln, r, p, len(dkStored))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(dk, dkStored) == 1
+}
+
+// decodeLegacyB64 decodifica las variantes de base64 que usan los exports:
+// estándar con o sin padding, url-safe y la "adapted base64" de passlib ("." por "+").
+func decodeLegacyB64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if b, err := base64.RawStdEncoding.DecodeString(strings.ReplaceAll(s, ".", "+")); err == nil {
+		return b, nil
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,59 @@
+package password
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestVerifyLegacyFormats(t *testing.T) {
+	bc, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name, plain, encoded, alg string
+	}{
+		{"bcrypt", "password", string(bc), AlgBcrypt},
+		// passlib pbkdf2_sha1 / pbkdf2_sha512 (adapted base64, "." por "+")
+		{"passlib pbkdf2 sha1", "password", "$pbkdf2$131000$MDEyMzQ1Njc4OWFiY2RlZg$psSehCxCX0ECqfbI2etYTXYpLDM", AlgPBKDF2SHA1},
+		{"passlib pbkdf2 sha512", "password", "$pbkdf2-sha512$25000$MDEyMzQ1Njc4OWFiY2RlZg$uxdHU.JH8vyHyz/DeXpgS7H6Tjz9A.lBWgSoOvvbH2iwTOWkhoRip9yMdB0AZCvZtIdBg46qUM3yPQkKDTKUSg", AlgPBKDF2SHA512},
+		// PHC, el formato que arma Import para Keycloak
+		{"phc pbkdf2 sha256", "S3cret!", "$pbkdf2-sha256$i=27500$a2V5Y2xvYWstc2FsdC0xNg$xa7RFWf5K9zLJoQuy4srf2humLsWMUXf57SHK47m/2LxSD6I7EV36N4EghZKr2hmLNzOCaSjyZzR+aO3ty8UnQ", AlgPBKDF2SHA256},
+		// passlib scrypt
+		{"passlib scrypt", "password", "$scrypt$ln=10,r=8,p=1$c2NyeXB0LXNhbHQtMDAwMQ$0VIoOyjy9wOgdaOEq9ZMhGhPKY2f.0PVF59sTpze1DY", AlgScrypt},
+	}
+	for _, c := range cases {
+		if got := Identify(c.encoded); got != c.alg {
+			t.Errorf("%s: Identify = %q, want %q", c.name, got, c.alg)
+		}
+		if !Verify(c.plain, c.encoded) {
+			t.Errorf("%s: valid password rejected", c.name)
+		}
+		if Verify(c.plain+"x", c.encoded) {
+			t.Errorf("%s: wrong password accepted", c.name)
+		}
+		if !NeedsRehash(c.encoded) {
+			t.Errorf("%s: expected NeedsRehash", c.name)
+		}
+	}
+}
+
+func TestVerifyLegacyRejectsAbusiveParameters(t *testing.T) {
+	cases := []string{
+		"$pbkdf2-sha256$i=0$MDEyMzQ1Njc4OWFiY2RlZg$psSehCxCX0ECqfbI2etYTXYpLDM",
+		"$pbkdf2-sha256$i=99999999$MDEyMzQ1Njc4OWFiY2RlZg$psSehCxCX0ECqfbI2etYTXYpLDM",
+		"$pbkdf2-sha256$i=1000$$psSehCxCX0ECqfbI2etYTXYpLDM",
+		"$pbkdf2-sha256$i=1000$MDEyMzQ1Njc4OWFiY2RlZg",
+		"$scrypt$ln=30,r=8,p=1$c2NyeXB0LXNhbHQtMDAwMQ$0VIoOyjy9wOgdaOEq9ZMhGhPKY2f.0PVF59sTpze1DY",
+		"$scrypt$ln=10,r=1024,p=1024$c2NyeXB0LXNhbHQtMDAwMQ$0VIoOyjy9wOgdaOEq9ZMhGhPKY2f.0PVF59sTpze1DY",
+		"$scrypt$ln=10,r=8$c2NyeXB0LXNhbHQtMDAwMQ$0VIoOyjy9wOgdaOEq9ZMhGhPKY2f.0PVF59sTpze1DY",
+		"$scrypt$ln=x,r=8,p=1$c2NyeXB0LXNhbHQtMDAwMQ$0VIoOyjy9wOgdaOEq9ZMhGhPKY2f.0PVF59sTpze1DY",
+	}
+	for _, encoded := range cases {
+		if Verify("password", encoded) {
+			t.Errorf("hash accepted: %s", encoded)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// SHA-crypt ($5$ / $6$) según la especificación de Ulrich Drepper
+// (https://www.akkadia.org/drepper/SHA-crypt.txt), el formato de /etc/shadow.
+
+const (
+	shaCryptDefaultRounds = 5000
+	shaCryptMinRounds     = 1000
+	shaCryptMaxRounds     = 10_000_000 // la spec permite 999999999; limitamos el costo
+	shaCryptMaxSalt       = 16
+	shaCryptAlphabet      = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// Orden de bytes del digest en la codificación final (grupos de 3 bytes).
+var (
+	sha256CryptPerm = [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	}
+	sha512CryptPerm = [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41},
+	}
+)
+
+// verifySHACrypt valida $5$[rounds=N$]salt$hash y $6$[rounds=N$]salt$hash.
+func verifySHACrypt(plain, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) < 4 || parts[0] != "" {
+		return false
+	}
+	id := parts[1]
+	rest := parts[2:]
+
+	rounds, custom := shaCryptDefaultRounds, false
+	if strings.HasPrefix(rest[0], "rounds=") {
+		n, err := strconv.Atoi(strings.TrimPrefix(rest[0], "rounds="))
+		if err != nil || n > shaCryptMaxRounds {
+			return false
+		}
+		if n < shaCryptMinRounds {
+			n = shaCryptMinRounds
+		}
+		rounds, custom = n, true
+		rest = rest[1:]
+	}
+	if len(rest) != 2 {
+		return false
+	}
+	salt := rest[0]
+	if len(salt) > shaCryptMaxSalt {
+		salt = salt[:shaCryptMaxSalt]
+	}
+
+	var computed string
+	switch id {
+	case "5":
+		computed = shaCrypt(sha256.New, sha256CryptPerm, id, []byte(plain), salt, rounds, custom)
+	case "6":
+		computed = shaCrypt(sha512.New, sha512CryptPerm, id, []byte(plain), salt, rounds, custom)
+	default:
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(encoded)) == 1
+}
+
+// shaCrypt calcula el string completo ($id$[rounds=N$]salt$hash).
+func shaCrypt(newHash func() hash.Hash, perm [][3]int, id string, key []byte, salt string, rounds int, custom bool) string {
+	saltB := []byte(salt)
+
+	// Digest B = H(key salt key)
+	h := newHash()
+	h.Write(key)
+	h.Write(saltB)
+	h.Write(key)
+	b := h.Sum(nil)
+	size := len(b)
+
+	// Digest A = H(key salt B-repetido-hasta-len(key) ...)
+	h = newHash()
+	h.Write(key)
+	h.Write(saltB)
+	h.Write(repeatTo(b, len(key)))
+	for n := len(key); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(key)
+		}
+	}
+	a := h.Sum(nil)
+
+	// Secuencia P: H(key repetido len(key) veces), recortado a len(key)
+	h = newHash()
+	for i := 0; i < len(key); i++ {
+		h.Write(key)
+	}
+	p := repeatTo(h.Sum(nil), len(key))
+
+	// Secuencia S: H(salt repetido 16+A[0] veces), recortado a len(salt)
+	h = newHash()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(saltB)
+	}
+	s := repeatTo(h.Sum(nil), len(saltB))
+
+	c := a
+	for i := 0; i < rounds; i++ {
+		h = newHash()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(p)
+		}
+		c = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString("$" + id + "$")
+	if custom {
+		out.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	out.WriteString(salt)
+	out.WriteByte('$')
+	for _, g := range perm {
+		b64From24(&out, c[g[0]], c[g[1]], c[g[2]], 4)
+	}
+	if size == sha256.Size {
+		b64From24(&out, 0, c[31], c[30], 3)
+	} else {
+		b64From24(&out, 0, 0, c[63], 2)
+	}
+	return out.String()
+}
+
+func repeatTo(src []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		rem := n - len(out)
+		if rem > len(src) {
+			rem = len(src)
+		}
+		out = append(out, src[:rem]...)
+	}
+	return out
+}
+
+func b64From24(out *strings.Builder, b2, b1, b0 byte, n int) {
+	w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+	for i := 0; i < n; i++ {
+		out.WriteByte(shaCryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
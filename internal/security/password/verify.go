@@ -0,0 +1,69 @@
+package password
+
+import "strings"
+
+// Algoritmos de hash reconocidos. Todo lo que no es argon2id se acepta sólo
+// para usuarios migrados de otros sistemas y se rehashea en el próximo login.
+const (
+	AlgArgon2id     = "argon2id"
+	AlgBcrypt       = "bcrypt"        // $2a$ / $2b$ / $2y$ (Auth0, la mayoría de los frameworks)
+	AlgPBKDF2SHA1   = "pbkdf2-sha1"   // Keycloak "pbkdf2"
+	AlgPBKDF2SHA256 = "pbkdf2-sha256" // Keycloak "pbkdf2-sha256" (default hasta KC 24)
+	AlgPBKDF2SHA512 = "pbkdf2-sha512" // Keycloak "pbkdf2-sha512"
+	AlgScrypt       = "scrypt"        // $scrypt$ln=..,r=..,p=..$salt$hash (passlib/PHC)
+	AlgSHA256Crypt  = "sha256-crypt"  // $5$ (crypt(3), Drepper)
+	AlgSHA512Crypt  = "sha512-crypt"  // $6$ (crypt(3), Drepper)
+)
+
+// Verify valida la contraseña en claro contra un hash codificado en cualquiera
+// de los formatos soportados (ver Identify). Formato desconocido = false.
+func Verify(plain, encoded string) bool {
+	if plain == "" || encoded == "" {
+		return false
+	}
+	switch Identify(encoded) {
+	case AlgArgon2id:
+		return verifyArgon2id(plain, encoded)
+	case AlgBcrypt:
+		return verifyBcrypt(plain, encoded)
+	case AlgPBKDF2SHA1, AlgPBKDF2SHA256, AlgPBKDF2SHA512:
+		return verifyPBKDF2(plain, encoded)
+	case AlgScrypt:
+		return verifyScrypt(plain, encoded)
+	case AlgSHA256Crypt, AlgSHA512Crypt:
+		return verifySHACrypt(plain, encoded)
+	default:
+		return false
+	}
+}
+
+// Identify devuelve el algoritmo de un hash codificado según su prefijo,
+// o "" si no es un formato soportado.
+func Identify(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return AlgArgon2id
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return AlgBcrypt
+	case strings.HasPrefix(encoded, "$pbkdf2-sha1$"), strings.HasPrefix(encoded, "$pbkdf2$"):
+		return AlgPBKDF2SHA1
+	case strings.HasPrefix(encoded, "$pbkdf2-sha256$"):
+		return AlgPBKDF2SHA256
+	case strings.HasPrefix(encoded, "$pbkdf2-sha512$"):
+		return AlgPBKDF2SHA512
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		return AlgScrypt
+	case strings.HasPrefix(encoded, "$5$"):
+		return AlgSHA256Crypt
+	case strings.HasPrefix(encoded, "$6$"):
+		return AlgSHA512Crypt
+	default:
+		return ""
+	}
+}
+
+// NeedsRehash indica si el hash debe reemplazarse por uno argon2id (Hash)
+// después de un login exitoso.
+func NeedsRehash(encoded string) bool {
+	return Identify(encoded) != AlgArgon2id
+}
@@ -0,0 +1,161 @@
+// Package revocation implementa la denylist de access tokens JWT.
+//
+// Los access tokens son autocontenidos y siguen siendo válidos hasta su exp;
+// la denylist permite cortarlos antes. Se guarda en el cache del tenant
+// (memory o Redis) con dos tipos de entradas:
+//
+//   - revoked:jti:<jti>            un token puntual, con TTL hasta su exp
+//   - revoked:{sub|client|sid}:<id> y revoked:tenant
+//     marcas de tiempo en milisegundos: todo token con iat <= marca queda revocado
+package revocation
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	jwtx "github.com/dropDatabas3/hellojohn/internal/jwt"
+)
+
+const (
+	keyJTI     = "revoked:jti:"
+	keySubject = "revoked:sub:"
+	keyClient  = "revoked:client:"
+	keySession = "revoked:sid:"
+	keyTenant  = "revoked:tenant"
+)
+
+// MarkTTL es la vida de las marcas por usuario/client/sesión/tenant: el TTL de
+// access token más largo que emite el issuer. Pasado ese tiempo ningún token
+// emitido antes de la marca sigue vigente.
+var MarkTTL = jwtx.MaxAccessTTL
+
+// Denylist registra y consulta revocaciones de access tokens sobre un cache.
+// Con cache nil todas las operaciones son no-op.
+type Denylist struct {
+	c cache.Client
+}
+
+// New crea una denylist sobre el cache del tenant.
+func New(c cache.Client) *Denylist {
+	return &Denylist{c: c}
+}
+
+// RevokeToken revoca un access token por jti hasta su expiración.
+func (d *Denylist) RevokeToken(ctx context.Context, jti string, exp time.Time) error {
+	if d.c == nil || jti == "" {
+		return nil
+	}
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		return nil // ya expiró
+	}
+	return d.c.Set(ctx, keyJTI+jti, "1", ttl)
+}
+
+// RevokeSubject revoca todos los access tokens emitidos hasta ahora para un usuario.
+func (d *Denylist) RevokeSubject(ctx context.Context, userID string) error {
+	return d.mark(ctx, keySubject, userID)
+}
+
+// RevokeClient revoca todos los access tokens emitidos hasta ahora para un client.
+func (d *Denylist) RevokeClient(ctx context.Context, clientID string) error {
+	return d.mark(ctx, keyClient, clientID)
+}
+
+// RevokeSession revoca los access tokens emitidos bajo una sesión (claim sid).
+func (d *Denylist) RevokeSession(ctx context.Context, sid string) error {
+	return d.mark(ctx, keySession, sid)
+}
+
+// RevokeTenant revoca todos los access tokens emitidos hasta ahora en el tenant.
+func (d *Denylist) RevokeTenant(ctx context.Context) error {
+	if d.c == nil {
+		return nil
+	}
+	return d.c.Set(ctx, keyTenant, now(), MarkTTL)
+}
+
+func (d *Denylist) mark(ctx context.Context, prefix, id string) error {
+	if d.c == nil || id == "" {
+		return nil
+	}
+	return d.c.Set(ctx, prefix+id, now(), MarkTTL)
+}
+
+// IsRevoked indica si el access token con estos claims fue revocado.
+// Los errores del cache se devuelven junto con false; el caller decide.
+func (d *Denylist) IsRevoked(ctx context.Context, claims map[string]any) (bool, error) {
+	if d.c == nil {
+		return false, nil
+	}
+
+	if jti, _ := claims["jti"].(string); jti != "" {
+		ok, err := d.c.Exists(ctx, keyJTI+jti)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	iat, _ := claims["iat"].(float64)
+	keys := []string{keyTenant}
+	if sub, _ := claims["sub"].(string); sub != "" {
+		keys = append(keys, keySubject+sub)
+	}
+	for _, aud := range audiences(claims["aud"]) {
+		keys = append(keys, keyClient+aud)
+	}
+	// Tokens para API resources (RFC 8707) llevan el client en client_id/azp
+	for _, k := range []string{"client_id", "azp"} {
+		if c, _ := claims[k].(string); c != "" {
+			keys = append(keys, keyClient+c)
+		}
+	}
+	if sid, _ := claims["sid"].(string); sid != "" {
+		keys = append(keys, keySession+sid)
+	}
+
+	for _, k := range keys {
+		v, err := d.c.Get(ctx, k)
+		if err != nil {
+			if cache.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if mark, err := strconv.ParseInt(v, 10, 64); err == nil && int64(math.Round(iat*1000)) <= mark {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// audiences normaliza el claim aud (string o lista).
+func audiences(v any) []string {
+	switch a := v.(type) {
+	case string:
+		if a != "" {
+			return []string{a}
+		}
+	case []any:
+		out := make([]string, 0, len(a))
+		for _, x := range a {
+			if s, ok := x.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return a
+	}
+	return nil
+}
+
+// now devuelve la marca actual en milisegundos: el iat de los access tokens los
+// incluye, así que un token emitido después de la marca en el mismo segundo no
+// queda revocado.
+func now() string {
+	return strconv.FormatInt(time.Now().UnixMilli(), 10)
+}
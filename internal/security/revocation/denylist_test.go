@@ -0,0 +1,101 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+)
+
+// issuedAt devuelve un iat como lo emite el issuer (segundos con milisegundos).
+func issuedAt(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+func accessClaims(iat time.Time) map[string]any {
+	return map[string]any{
+		"jti":       "jti-1",
+		"sub":       "user-1",
+		"aud":       []any{"api-orders"},
+		"client_id": "client-a",
+		"sid":       "sid-1",
+		"iat":       issuedAt(iat),
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	d := New(cache.NewMemory("test"))
+	claims := accessClaims(time.Now())
+
+	if revoked, err := d.IsRevoked(ctx, claims); err != nil || revoked {
+		t.Fatalf("fresh token: revoked=%v err=%v", revoked, err)
+	}
+	if err := d.RevokeToken(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := d.IsRevoked(ctx, claims); !revoked {
+		t.Fatal("revoked jti accepted")
+	}
+
+	other := accessClaims(time.Now())
+	other["jti"] = "jti-2"
+	if revoked, _ := d.IsRevoked(ctx, other); revoked {
+		t.Error("other jti revoked")
+	}
+}
+
+func TestRevocationMarks(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		revoke func(*Denylist) error
+	}{
+		{"subject", func(d *Denylist) error { return d.RevokeSubject(ctx, "user-1") }},
+		{"audience", func(d *Denylist) error { return d.RevokeClient(ctx, "api-orders") }},
+		{"client_id", func(d *Denylist) error { return d.RevokeClient(ctx, "client-a") }},
+		{"session", func(d *Denylist) error { return d.RevokeSession(ctx, "sid-1") }},
+		{"tenant", func(d *Denylist) error { return d.RevokeTenant(ctx) }},
+	}
+	for _, tt := range tests {
+		d := New(cache.NewMemory("test"))
+		before := accessClaims(time.Now().Add(-time.Second))
+		before["jti"] = ""
+
+		if err := tt.revoke(d); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if revoked, err := d.IsRevoked(ctx, before); err != nil || !revoked {
+			t.Errorf("%s: token issued before the mark accepted (err=%v)", tt.name, err)
+		}
+
+		// Emitido después de la marca, aunque en el mismo segundo
+		time.Sleep(2 * time.Millisecond)
+		after := accessClaims(time.Now())
+		if revoked, _ := d.IsRevoked(ctx, after); revoked {
+			t.Errorf("%s: token issued after the mark revoked", tt.name)
+		}
+	}
+}
+
+func TestRevocationMarkScope(t *testing.T) {
+	ctx := context.Background()
+	d := New(cache.NewMemory("test"))
+	if err := d.RevokeSubject(ctx, "user-2"); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := d.IsRevoked(ctx, accessClaims(time.Now().Add(-time.Second))); revoked {
+		t.Error("mark for another user revoked the token")
+	}
+}
+
+func TestNilCacheDenylist(t *testing.T) {
+	d := New(nil)
+	if err := d.RevokeTenant(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := d.IsRevoked(context.Background(), accessClaims(time.Now())); revoked || err != nil {
+		t.Errorf("nil cache: revoked=%v err=%v", revoked, err)
+	}
+}
@@ -3,7 +3,9 @@ package secretbox
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
@@ -60,6 +62,19 @@ func IsSecretBoxReady() bool {
 	return len(masterKey) == requiredKeyLength
 }
 
+// DeriveKey deriva una subclave de 32 bytes para un uso concreto (HMAC-SHA256 de
+// label con la clave maestra). Así otros módulos no reutilizan la clave maestra.
+func DeriveKey(label string) ([]byte, error) {
+	if err := ensureLoaded(); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	m := hmac.New(sha256.New, masterKey)
+	m.Write([]byte(label))
+	return m.Sum(nil), nil
+}
+
 // Encrypt cifra plainText y devuelve base64(nonce)|base64(ciphertext).
 func Encrypt(plainText string) (string, error) {
 	if err := ensureLoaded(); err != nil {
@@ -0,0 +1,118 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Decoder CBOR (RFC 8949) mínimo: cubre lo que usan attestationObject y COSE_Key
+// (enteros, byte/text strings, arrays, maps, bool/null). No soporta largos
+// indefinidos, tags ni floats.
+
+var errCBOR = errors.New("webauthn: invalid cbor")
+
+// maxCBORDepth limita el anidamiento para no recursionar sobre input hostil.
+const maxCBORDepth = 16
+
+// cborDecode decodifica un único item y retorna los bytes restantes.
+// Los maps se devuelven como map[any]any con claves int64 o string.
+func cborDecode(b []byte) (any, []byte, error) {
+	return cborItem(b, 0)
+}
+
+func cborItem(b []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth || len(b) == 0 {
+		return nil, nil, errCBOR
+	}
+	major := b[0] >> 5
+	arg, rest, err := cborArg(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0: // unsigned int
+		if arg > math.MaxInt64 {
+			return nil, nil, errCBOR
+		}
+		return int64(arg), rest, nil
+	case 1: // negative int
+		if arg > math.MaxInt64 {
+			return nil, nil, errCBOR
+		}
+		return -1 - int64(arg), rest, nil
+	case 2, 3: // byte string, text string
+		if arg > uint64(len(rest)) {
+			return nil, nil, errCBOR
+		}
+		data := rest[:arg]
+		if major == 3 {
+			return string(data), rest[arg:], nil
+		}
+		return append([]byte(nil), data...), rest[arg:], nil
+	case 4: // array
+		if arg > uint64(len(rest)) {
+			return nil, nil, errCBOR
+		}
+		out := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var v any
+			if v, rest, err = cborItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			out = append(out, v)
+		}
+		return out, rest, nil
+	case 5: // map
+		if arg > uint64(len(rest)) {
+			return nil, nil, errCBOR
+		}
+		out := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			var k, v any
+			if k, rest, err = cborItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch k.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errCBOR
+			}
+			if v, rest, err = cborItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			out[k] = v
+		}
+		return out, rest, nil
+	case 7: // simple values
+		switch b[0] & 0x1f {
+		case 20:
+			return false, rest, nil
+		case 21:
+			return true, rest, nil
+		case 22, 23:
+			return nil, rest, nil
+		}
+	}
+	return nil, nil, errCBOR
+}
+
+// cborArg lee el argumento del header (additional info) de un item.
+func cborArg(b []byte) (uint64, []byte, error) {
+	info := b[0] & 0x1f
+	b = b[1:]
+	switch {
+	case info < 24:
+		return uint64(info), b, nil
+	case info == 24 && len(b) >= 1:
+		return uint64(b[0]), b[1:], nil
+	case info == 25 && len(b) >= 2:
+		return uint64(binary.BigEndian.Uint16(b)), b[2:], nil
+	case info == 26 && len(b) >= 4:
+		return uint64(binary.BigEndian.Uint32(b)), b[4:], nil
+	case info == 27 && len(b) >= 8:
+		return binary.BigEndian.Uint64(b), b[8:], nil
+	}
+	return 0, nil, errCBOR
+}
@@ -0,0 +1,77 @@
+package webauthn
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCBORDecodeValues(t *testing.T) {
+	// {"fmt": "none", 1: -7, "b": h'0102', "a": [true, null]}
+	in := []byte{
+		0xa4,
+		0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e',
+		0x01, 0x26,
+		0x61, 'b', 0x42, 0x01, 0x02,
+		0x61, 'a', 0x82, 0xf5, 0xf6,
+	}
+	v, rest, err := cborDecode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("unexpected trailing bytes: %x", rest)
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		t.Fatalf("expected map, got %T", v)
+	}
+	if m["fmt"] != "none" {
+		t.Errorf("fmt = %v", m["fmt"])
+	}
+	if m[int64(1)] != int64(-7) {
+		t.Errorf("1 = %v", m[int64(1)])
+	}
+	if b, _ := m["b"].([]byte); !bytes.Equal(b, []byte{1, 2}) {
+		t.Errorf("b = %v", m["b"])
+	}
+	if a, _ := m["a"].([]any); len(a) != 2 || a[0] != true || a[1] != nil {
+		t.Errorf("a = %v", m["a"])
+	}
+}
+
+func TestCBORDecodeReturnsRest(t *testing.T) {
+	v, rest, err := cborDecode([]byte{0x18, 0xff, 0xaa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != int64(255) || !bytes.Equal(rest, []byte{0xaa}) {
+		t.Fatalf("got %v, rest %x", v, rest)
+	}
+}
+
+func TestCBORDecodeMalformed(t *testing.T) {
+	deep := bytes.Repeat([]byte{0x81}, maxCBORDepth+2)
+	deep = append(deep, 0x00)
+
+	cases := map[string][]byte{
+		"empty":                {},
+		"truncated uint16":     {0x19, 0x01},
+		"truncated uint64":     {0x1b, 0, 0, 0, 0},
+		"truncated bytes":      {0x45, 0x01, 0x02},
+		"truncated text":       {0x63, 'a'},
+		"array longer than in": {0x83, 0x01},
+		"map missing value":    {0xa1, 0x01},
+		"huge length":          {0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"uint overflow":        {0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"indefinite length":    {0x5f, 0x41, 0x00, 0xff},
+		"tag":                  {0xc0, 0x00},
+		"float":                {0xf9, 0x3c, 0x00},
+		"array map key":        {0xa1, 0x80, 0x01},
+		"too deep":             deep,
+	}
+	for name, in := range cases {
+		if _, _, err := cborDecode(in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// Algoritmos COSE soportados (IANA COSE Algorithms).
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgPS256 int64 = -37
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms es el orden de preferencia anunciado en pubKeyCredParams.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256, AlgPS256}
+
+// Parámetros de COSE_Key (RFC 9053).
+const (
+	coseKty    int64 = 1
+	coseAlg    int64 = 3
+	coseCrv    int64 = -1
+	coseX      int64 = -2
+	coseY      int64 = -3
+	coseRSAN   int64 = -1
+	coseRSAE   int64 = -2
+	ktyOKP     int64 = 1
+	ktyEC2     int64 = 2
+	ktyRSA     int64 = 3
+	crvP256    int64 = 1
+	crvEd25519 int64 = 6
+)
+
+var (
+	ErrUnsupportedKey = errors.New("webauthn: unsupported credential public key")
+	ErrBadSignature   = errors.New("webauthn: signature verification failed")
+)
+
+// PublicKey es la clave pública de una credencial junto con su algoritmo COSE.
+type PublicKey struct {
+	Alg int64
+	Key crypto.PublicKey
+}
+
+// ParsePublicKey decodifica una COSE_Key (tal como se guarda en la credencial).
+func ParsePublicKey(cose []byte) (*PublicKey, error) {
+	v, _, err := cborDecode(cose)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+	kty, _ := m[coseKty].(int64)
+	alg, _ := m[coseAlg].(int64)
+
+	switch kty {
+	case ktyEC2:
+		crv, _ := m[coseCrv].(int64)
+		x, _ := m[coseX].([]byte)
+		y, _ := m[coseY].([]byte)
+		if alg != AlgES256 || crv != crvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return &PublicKey{Alg: alg, Key: pub}, nil
+
+	case ktyOKP:
+		crv, _ := m[coseCrv].(int64)
+		x, _ := m[coseX].([]byte)
+		if alg != AlgEdDSA || crv != crvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return &PublicKey{Alg: alg, Key: ed25519.PublicKey(x)}, nil
+
+	case ktyRSA:
+		n, _ := m[coseRSAN].([]byte)
+		e, _ := m[coseRSAE].([]byte)
+		if (alg != AlgRS256 && alg != AlgPS256) || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrUnsupportedKey
+		}
+		exp := 0
+		for _, b := range e {
+			exp = exp<<8 | int(b)
+		}
+		return &PublicKey{Alg: alg, Key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exp}}, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Verify valida sig sobre data con el algoritmo de la clave.
+func (k *PublicKey) Verify(data, sig []byte) error {
+	return verifySignature(k.Alg, k.Key, data, sig)
+}
+
+func verifySignature(alg int64, key crypto.PublicKey, data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	ok := false
+	switch alg {
+	case AlgES256:
+		if pub, isEC := key.(*ecdsa.PublicKey); isEC {
+			ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+		}
+	case AlgEdDSA:
+		if pub, isEd := key.(ed25519.PublicKey); isEd {
+			ok = ed25519.Verify(pub, data, sig)
+		}
+	case AlgRS256:
+		if pub, isRSA := key.(*rsa.PublicKey); isRSA {
+			ok = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+		}
+	case AlgPS256:
+		if pub, isRSA := key.(*rsa.PublicKey); isRSA {
+			ok = rsa.VerifyPSS(pub, crypto.SHA256, digest[:], sig, nil) == nil
+		}
+	}
+	if !ok {
+		return ErrBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+// Package webauthn implementa la verificación de ceremonias WebAuthn Level 2
+// (registro y autenticación) del lado del Relying Party.
+//
+// Attestation soportada: "none" y "packed" (self-attestation o x5c). Con x5c se
+// verifica la firma contra el certificado pero no se valida la cadena contra
+// metadata (FIDO MDS): el RP pide attestation "none" y no depende de ella.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Flags de authenticatorData (WebAuthn §6.1).
+const (
+	FlagUserPresent    byte = 0x01
+	FlagUserVerified   byte = 0x04
+	FlagBackupEligible byte = 0x08
+	FlagBackupState    byte = 0x10
+	FlagAttestedData   byte = 0x40
+	FlagExtensionData  byte = 0x80
+)
+
+// ChallengeSize es el largo en bytes de los challenges generados.
+const ChallengeSize = 32
+
+var (
+	ErrInvalidClientData      = errors.New("webauthn: invalid client data")
+	ErrChallengeMismatch      = errors.New("webauthn: challenge mismatch")
+	ErrOriginNotAllowed       = errors.New("webauthn: origin not allowed")
+	ErrInvalidAuthData        = errors.New("webauthn: invalid authenticator data")
+	ErrRPIDMismatch           = errors.New("webauthn: rp id hash mismatch")
+	ErrUserNotPresent         = errors.New("webauthn: user not present")
+	ErrUserNotVerified        = errors.New("webauthn: user not verified")
+	ErrUnsupportedAttestation = errors.New("webauthn: unsupported attestation")
+	ErrSignCounter            = errors.New("webauthn: sign counter did not increase (possible cloned authenticator)")
+)
+
+// Config identifica al Relying Party.
+type Config struct {
+	RPID    string   // dominio efectivo (ej: "example.com")
+	RPName  string   // nombre visible en el autenticador
+	Origins []string // orígenes aceptados en clientDataJSON (ej: "https://app.example.com")
+}
+
+// Credential es una credencial recién registrada.
+type Credential struct {
+	ID                []byte
+	PublicKey         []byte // COSE_Key tal como la entregó el autenticador
+	Alg               int64
+	SignCount         uint32
+	AAGUID            []byte
+	AttestationFormat string
+	UserVerified      bool
+	BackupEligible    bool
+	BackupState       bool
+}
+
+// AssertionResult es el resultado de una autenticación válida.
+type AssertionResult struct {
+	SignCount    uint32
+	UserVerified bool
+	BackupState  bool
+}
+
+// AuthenticatorData es el authenticatorData parseado (WebAuthn §6.1).
+type AuthenticatorData struct {
+	RPIDHash  []byte
+	Flags     byte
+	SignCount uint32
+
+	// Attested credential data (sólo en registro)
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte
+}
+
+// Has indica si el flag está presente.
+func (a *AuthenticatorData) Has(flag byte) bool { return a.Flags&flag != 0 }
+
+// clientData es CollectedClientData (WebAuthn §5.8.1).
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// NewChallenge genera un challenge aleatorio.
+func NewChallenge() ([]byte, error) {
+	b := make([]byte, ChallengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// EncodeBase64URL codifica como lo espera el navegador (base64url sin padding).
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL acepta base64url con o sin padding.
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// ClientDataChallenge extrae el challenge de clientDataJSON sin validarlo, para
+// ubicar el estado de la ceremonia antes de verificarla.
+func ClientDataChallenge(clientDataJSON []byte) (string, error) {
+	var cd clientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil || cd.Challenge == "" {
+		return "", ErrInvalidClientData
+	}
+	return cd.Challenge, nil
+}
+
+// VerifyRegistration valida la respuesta de navigator.credentials.create().
+func VerifyRegistration(cfg Config, challenge, clientDataJSON, attestationObject []byte, requireUV bool) (*Credential, error) {
+	if err := verifyClientData(cfg, "webauthn.create", challenge, clientDataJSON); err != nil {
+		return nil, err
+	}
+
+	v, _, err := cborDecode(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	att, ok := v.(map[any]any)
+	if !ok {
+		return nil, ErrUnsupportedAttestation
+	}
+	format, _ := att["fmt"].(string)
+	rawAuthData, _ := att["authData"].([]byte)
+	attStmt, _ := att["attStmt"].(map[any]any)
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAuthData(cfg, authData, requireUV); err != nil {
+		return nil, err
+	}
+	if !authData.Has(FlagAttestedData) || len(authData.CredentialID) == 0 {
+		return nil, ErrInvalidAuthData
+	}
+
+	pub, err := ParsePublicKey(authData.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	switch format {
+	case "none":
+		if len(attStmt) != 0 {
+			return nil, ErrUnsupportedAttestation
+		}
+	case "packed":
+		if err := verifyPacked(attStmt, pub, authData.AAGUID, signed); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedAttestation
+	}
+
+	return &Credential{
+		ID:                authData.CredentialID,
+		PublicKey:         authData.PublicKey,
+		Alg:               pub.Alg,
+		SignCount:         authData.SignCount,
+		AAGUID:            authData.AAGUID,
+		AttestationFormat: format,
+		UserVerified:      authData.Has(FlagUserVerified),
+		BackupEligible:    authData.Has(FlagBackupEligible),
+		BackupState:       authData.Has(FlagBackupState),
+	}, nil
+}
+
+// VerifyAssertion valida la respuesta de navigator.credentials.get() contra la
+// clave pública guardada. storedSignCount es el contador de la última autenticación.
+func VerifyAssertion(cfg Config, challenge, clientDataJSON, rawAuthData, signature, publicKey []byte, storedSignCount uint32, requireUV bool) (*AssertionResult, error) {
+	if err := verifyClientData(cfg, "webauthn.get", challenge, clientDataJSON); err != nil {
+		return nil, err
+	}
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAuthData(cfg, authData, requireUV); err != nil {
+		return nil, err
+	}
+
+	pub, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if err := pub.Verify(signed, signature); err != nil {
+		return nil, err
+	}
+
+	// Autenticadores sin contador (passkeys sincronizadas) reportan siempre 0
+	if (authData.SignCount != 0 || storedSignCount != 0) && authData.SignCount <= storedSignCount {
+		return nil, ErrSignCounter
+	}
+
+	return &AssertionResult{
+		SignCount:    authData.SignCount,
+		UserVerified: authData.Has(FlagUserVerified),
+		BackupState:  authData.Has(FlagBackupState),
+	}, nil
+}
+
+// ParseAuthenticatorData parsea authenticatorData (con attested credential data si está presente).
+func ParseAuthenticatorData(b []byte) (*AuthenticatorData, error) {
+	if len(b) < 37 {
+		return nil, ErrInvalidAuthData
+	}
+	a := &AuthenticatorData{
+		RPIDHash:  b[:32],
+		Flags:     b[32],
+		SignCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	rest := b[37:]
+
+	if a.Has(FlagAttestedData) {
+		if len(rest) < 18 {
+			return nil, ErrInvalidAuthData
+		}
+		a.AAGUID = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+			return nil, ErrInvalidAuthData
+		}
+		a.CredentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		// La COSE_Key es un item CBOR; lo que sigue son extensiones
+		_, after, err := cborDecode(rest)
+		if err != nil {
+			return nil, ErrInvalidAuthData
+		}
+		a.PublicKey = rest[:len(rest)-len(after)]
+		rest = after
+	}
+	if a.Has(FlagExtensionData) {
+		var err error
+		if _, rest, err = cborDecode(rest); err != nil {
+			return nil, ErrInvalidAuthData
+		}
+	}
+	if len(rest) != 0 {
+		return nil, ErrInvalidAuthData
+	}
+	return a, nil
+}
+
+func verifyClientData(cfg Config, typ string, challenge, raw []byte) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return ErrInvalidClientData
+	}
+	if cd.Type != typ || cd.CrossOrigin {
+		return ErrInvalidClientData
+	}
+	got, err := DecodeBase64URL(cd.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return ErrChallengeMismatch
+	}
+	for _, o := range cfg.Origins {
+		if strings.EqualFold(strings.TrimRight(o, "/"), cd.Origin) {
+			return nil
+		}
+	}
+	return ErrOriginNotAllowed
+}
+
+func verifyAuthData(cfg Config, a *AuthenticatorData, requireUV bool) error {
+	rpHash := sha256.Sum256([]byte(cfg.RPID))
+	if !bytes.Equal(a.RPIDHash, rpHash[:]) {
+		return ErrRPIDMismatch
+	}
+	if !a.Has(FlagUserPresent) {
+		return ErrUserNotPresent
+	}
+	if requireUV && !a.Has(FlagUserVerified) {
+		return ErrUserNotVerified
+	}
+	return nil
+}
+
+// oidFIDOAAGUID es la extensión id-fido-gen-ce-aaguid del certificado de attestation.
+var oidFIDOAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+// verifyPacked valida attestation "packed" (WebAuthn §8.2).
+func verifyPacked(stmt map[any]any, credKey *PublicKey, aaguid, signed []byte) error {
+	alg, _ := stmt["alg"].(int64)
+	sig, _ := stmt["sig"].([]byte)
+	if len(sig) == 0 {
+		return ErrUnsupportedAttestation
+	}
+
+	x5c, hasX5C := stmt["x5c"].([]any)
+	if !hasX5C {
+		// Self attestation: firmada con la propia credencial
+		if alg != credKey.Alg {
+			return ErrUnsupportedAttestation
+		}
+		return credKey.Verify(signed, sig)
+	}
+
+	if len(x5c) == 0 {
+		return ErrUnsupportedAttestation
+	}
+	der, _ := x5c[0].([]byte)
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return ErrUnsupportedAttestation
+	}
+	if cert.Version != 3 || cert.IsCA {
+		return ErrUnsupportedAttestation
+	}
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidFIDOAAGUID) {
+			continue
+		}
+		var certAAGUID []byte
+		if _, err := asn1.Unmarshal(ext.Value, &certAAGUID); err != nil || !bytes.Equal(certAAGUID, aaguid) {
+			return ErrUnsupportedAttestation
+		}
+	}
+	return verifySignature(alg, cert.PublicKey, signed, sig)
+}
@@ -0,0 +1,261 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var testRP = Config{RPID: "example.com", RPName: "Example", Origins: []string{"https://example.com"}}
+
+// cborHead codifica el header de un item CBOR (solo lo que usan los tests).
+func cborHead(major byte, n int) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 256:
+		return []byte{major<<5 | 24, byte(n)}
+	default:
+		return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+	}
+}
+
+func cborInt(v int64) []byte {
+	if v < 0 {
+		return cborHead(1, int(-1-v))
+	}
+	return cborHead(0, int(v))
+}
+
+func cborBytes(b []byte) []byte { return append(cborHead(2, len(b)), b...) }
+
+func cborText(s string) []byte { return append(cborHead(3, len(s)), s...) }
+
+func ec2Key(pub *ecdsa.PublicKey) []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	pub.X.FillBytes(x)
+	pub.Y.FillBytes(y)
+	out := cborHead(5, 5)
+	out = append(out, cborInt(coseKty)...)
+	out = append(out, cborInt(ktyEC2)...)
+	out = append(out, cborInt(coseAlg)...)
+	out = append(out, cborInt(AlgES256)...)
+	out = append(out, cborInt(coseCrv)...)
+	out = append(out, cborInt(crvP256)...)
+	out = append(out, cborInt(coseX)...)
+	out = append(out, cborBytes(x)...)
+	out = append(out, cborInt(coseY)...)
+	out = append(out, cborBytes(y)...)
+	return out
+}
+
+func okpKey(pub ed25519.PublicKey) []byte {
+	out := cborHead(5, 4)
+	out = append(out, cborInt(coseKty)...)
+	out = append(out, cborInt(ktyOKP)...)
+	out = append(out, cborInt(coseAlg)...)
+	out = append(out, cborInt(AlgEdDSA)...)
+	out = append(out, cborInt(coseCrv)...)
+	out = append(out, cborInt(crvEd25519)...)
+	out = append(out, cborInt(coseX)...)
+	out = append(out, cborBytes(pub)...)
+	return out
+}
+
+func authData(rpID string, flags byte, count uint32) []byte {
+	h := sha256.Sum256([]byte(rpID))
+	out := append([]byte(nil), h[:]...)
+	out = append(out, flags)
+	return binary.BigEndian.AppendUint32(out, count)
+}
+
+func clientDataJSON(t *testing.T, typ string, challenge []byte) []byte {
+	t.Helper()
+	raw, err := json.Marshal(clientData{Type: typ, Challenge: EncodeBase64URL(challenge), Origin: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func signAssertion(t *testing.T, priv *ecdsa.PrivateKey, ad, cd []byte) []byte {
+	t.Helper()
+	cdHash := sha256.Sum256(cd)
+	digest := sha256.Sum256(append(append([]byte(nil), ad...), cdHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestParsePublicKey(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	k, err := ParsePublicKey(ec2Key(&ec.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Alg != AlgES256 || !ec.PublicKey.Equal(k.Key) {
+		t.Errorf("unexpected ES256 key: %+v", k)
+	}
+
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	k, err = ParsePublicKey(okpKey(edPub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Alg != AlgEdDSA || !edPub.Equal(k.Key) {
+		t.Errorf("unexpected EdDSA key: %+v", k)
+	}
+}
+
+func TestParsePublicKeyRejects(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	good := ec2Key(&ec.PublicKey)
+
+	// Punto fuera de la curva: y alterada
+	offCurve := append([]byte(nil), good...)
+	offCurve[len(offCurve)-1] ^= 0x01
+
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	shortOKP := okpKey(edPub[:31])
+
+	cases := map[string][]byte{
+		"empty":     {},
+		"not a map": cborText("key"),
+		"truncated": good[:len(good)-5],
+		"off curve": offCurve,
+		"short okp": shortOKP,
+		"no kty":    append(cborHead(5, 1), append(cborInt(coseAlg), cborInt(AlgES256)...)...),
+	}
+	for name, in := range cases {
+		if _, err := ParsePublicKey(in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseAuthenticatorDataMalformed(t *testing.T) {
+	base := authData("example.com", FlagUserPresent, 1)
+	if _, err := ParseAuthenticatorData(base); err != nil {
+		t.Fatalf("valid authData rejected: %v", err)
+	}
+
+	attested := append(authData("example.com", FlagUserPresent|FlagAttestedData, 0), make([]byte, 16)...)
+	attested = append(attested, 0x00, 0x04, 1, 2, 3, 4)
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	withKey := append(append([]byte(nil), attested...), ec2Key(&ec.PublicKey)...)
+	if a, err := ParseAuthenticatorData(withKey); err != nil || len(a.CredentialID) != 4 || len(a.PublicKey) == 0 {
+		t.Fatalf("valid attested authData rejected: %v", err)
+	}
+
+	cases := map[string][]byte{
+		"short":               base[:36],
+		"trailing bytes":      append(append([]byte(nil), base...), 0x00),
+		"attested no header":  authData("example.com", FlagUserPresent|FlagAttestedData, 0),
+		"credential id short": attested[:len(attested)-2],
+		"missing cose key":    attested,
+		"truncated cose key":  withKey[:len(withKey)-3],
+		"missing extensions":  authData("example.com", FlagUserPresent|FlagExtensionData, 0),
+	}
+	for name, in := range cases {
+		if _, err := ParseAuthenticatorData(in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cose := ec2Key(&priv.PublicKey)
+	challenge, _ := NewChallenge()
+	cd := clientDataJSON(t, "webauthn.get", challenge)
+	ad := authData("example.com", FlagUserPresent|FlagUserVerified, 5)
+	sig := signAssertion(t, priv, ad, cd)
+
+	res, err := VerifyAssertion(testRP, challenge, cd, ad, sig, cose, 4, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SignCount != 5 || !res.UserVerified {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	// Contador que no avanza: posible autenticador clonado
+	if _, err := VerifyAssertion(testRP, challenge, cd, ad, sig, cose, 5, true); !errors.Is(err, ErrSignCounter) {
+		t.Errorf("expected ErrSignCounter, got %v", err)
+	}
+
+	// Firma de otro authenticatorData
+	other := authData("example.com", FlagUserPresent|FlagUserVerified, 6)
+	if _, err := VerifyAssertion(testRP, challenge, cd, other, sig, cose, 4, true); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature, got %v", err)
+	}
+
+	// Otro challenge
+	otherChallenge, _ := NewChallenge()
+	if _, err := VerifyAssertion(testRP, otherChallenge, cd, ad, sig, cose, 4, true); !errors.Is(err, ErrChallengeMismatch) {
+		t.Errorf("expected ErrChallengeMismatch, got %v", err)
+	}
+
+	// Tipo de ceremonia equivocado
+	cdCreate := clientDataJSON(t, "webauthn.create", challenge)
+	if _, err := VerifyAssertion(testRP, challenge, cdCreate, ad, signAssertion(t, priv, ad, cdCreate), cose, 4, true); !errors.Is(err, ErrInvalidClientData) {
+		t.Errorf("expected ErrInvalidClientData, got %v", err)
+	}
+
+	// Otro RP ID
+	foreign := authData("evil.example", FlagUserPresent|FlagUserVerified, 5)
+	if _, err := VerifyAssertion(testRP, challenge, cd, foreign, signAssertion(t, priv, foreign, cd), cose, 4, true); !errors.Is(err, ErrRPIDMismatch) {
+		t.Errorf("expected ErrRPIDMismatch, got %v", err)
+	}
+
+	// Sin user verification cuando se exige
+	noUV := authData("example.com", FlagUserPresent, 5)
+	if _, err := VerifyAssertion(testRP, challenge, cd, noUV, signAssertion(t, priv, noUV, cd), cose, 4, true); !errors.Is(err, ErrUserNotVerified) {
+		t.Errorf("expected ErrUserNotVerified, got %v", err)
+	}
+
+	// authenticatorData truncado
+	if _, err := VerifyAssertion(testRP, challenge, cd, ad[:20], sig, cose, 4, true); !errors.Is(err, ErrInvalidAuthData) {
+		t.Errorf("expected ErrInvalidAuthData, got %v", err)
+	}
+}
+
+func TestVerifyRegistrationNone(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	challenge, _ := NewChallenge()
+	cd := clientDataJSON(t, "webauthn.create", challenge)
+
+	credID := []byte{9, 8, 7, 6}
+	ad := append(authData("example.com", FlagUserPresent|FlagAttestedData, 0), make([]byte, 16)...)
+	ad = append(ad, 0x00, byte(len(credID)))
+	ad = append(ad, credID...)
+	ad = append(ad, ec2Key(&priv.PublicKey)...)
+
+	att := cborHead(5, 3)
+	att = append(att, cborText("fmt")...)
+	att = append(att, cborText("none")...)
+	att = append(att, cborText("attStmt")...)
+	att = append(att, cborHead(5, 0)...)
+	att = append(att, cborText("authData")...)
+	att = append(att, cborBytes(ad)...)
+
+	cred, err := VerifyRegistration(testRP, challenge, cd, att, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cred.ID) != string(credID) || cred.Alg != AlgES256 || cred.AttestationFormat != "none" {
+		t.Errorf("unexpected credential: %+v", cred)
+	}
+
+	if _, err := VerifyRegistration(testRP, challenge, cd, att[:len(att)-10], false); err == nil {
+		t.Error("expected error for truncated attestationObject")
+	}
+}
@@ -0,0 +1,83 @@
+package sms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+)
+
+// Límites por defecto de envíos a un mismo número.
+const (
+	DefaultMaxPerHour = 5
+	DefaultCooldown   = 30 * time.Second
+)
+
+// Limiter limita los envíos por número de teléfono (ventana fija de una hora +
+// cooldown entre reenvíos) sobre el cache del tenant. Protege contra el abuso
+// de SMS (toll fraud) además del rate limit por IP.
+//
+// Check-and-set no es atómico: con varias réplicas el límite es aproximado.
+type Limiter struct {
+	Cache      cache.Client
+	MaxPerHour int           // <= 0 = DefaultMaxPerHour
+	Cooldown   time.Duration // <= 0 = DefaultCooldown
+	Prefix     string        // prefijo de keys (ej. "sms:rl:"); vacío = "sms:rl:"
+}
+
+type limiterWindow struct {
+	Count int       `json:"n"`
+	Start time.Time `json:"s"`
+	Last  time.Time `json:"l"`
+}
+
+// Allow registra un envío a phone y retorna ErrRateLimited (con el tiempo de
+// espera sugerido) si excede el cooldown o el máximo por hora.
+// Sin cache no limita.
+func (l Limiter) Allow(ctx context.Context, phone string) (time.Duration, error) {
+	if l.Cache == nil {
+		return 0, nil
+	}
+	max := l.MaxPerHour
+	if max <= 0 {
+		max = DefaultMaxPerHour
+	}
+	cooldown := l.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	prefix := l.Prefix
+	if prefix == "" {
+		prefix = "sms:rl:"
+	}
+	// El número no queda en claro en el cache
+	sum := sha256.Sum256([]byte(phone))
+	key := prefix + hex.EncodeToString(sum[:16])
+
+	now := time.Now()
+	var w limiterWindow
+	if raw, err := l.Cache.Get(ctx, key); err == nil {
+		_ = json.Unmarshal([]byte(raw), &w)
+	}
+	if w.Start.IsZero() || now.Sub(w.Start) >= time.Hour {
+		w = limiterWindow{Start: now}
+	}
+
+	if !w.Last.IsZero() && now.Sub(w.Last) < cooldown {
+		return cooldown - now.Sub(w.Last), ErrRateLimited
+	}
+	if w.Count >= max {
+		return w.Start.Add(time.Hour).Sub(now), ErrRateLimited
+	}
+
+	w.Count++
+	w.Last = now
+	payload, _ := json.Marshal(w)
+	if err := l.Cache.Set(ctx, key, string(payload), w.Start.Add(time.Hour).Sub(now)); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+)
+
+func TestLimiter_MaxPerHour(t *testing.T) {
+	ctx := context.Background()
+	l := Limiter{Cache: cache.NewMemory("test"), MaxPerHour: 3, Cooldown: time.Nanosecond}
+
+	for i := 1; i <= 3; i++ {
+		time.Sleep(time.Millisecond) // pasa el cooldown
+		if _, err := l.Allow(ctx, "+5491122334455"); err != nil {
+			t.Fatalf("envío %d: err = %v", i, err)
+		}
+	}
+	time.Sleep(time.Millisecond)
+	wait, err := l.Allow(ctx, "+5491122334455")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("envío 4: err = %v, want ErrRateLimited", err)
+	}
+	if wait <= 59*time.Minute || wait > time.Hour {
+		t.Fatalf("espera = %v, want el resto de la hora", wait)
+	}
+
+	// El límite es por número
+	if _, err := l.Allow(ctx, "+5491100000000"); err != nil {
+		t.Fatalf("otro número: err = %v", err)
+	}
+}
+
+func TestLimiter_Cooldown(t *testing.T) {
+	ctx := context.Background()
+	l := Limiter{Cache: cache.NewMemory("test")}
+
+	if _, err := l.Allow(ctx, "+5491122334455"); err != nil {
+		t.Fatal(err)
+	}
+	wait, err := l.Allow(ctx, "+5491122334455")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("reenvío inmediato: err = %v, want ErrRateLimited", err)
+	}
+	if wait <= 0 || wait > DefaultCooldown {
+		t.Fatalf("espera = %v, want <= %v", wait, DefaultCooldown)
+	}
+}
+
+// Los prefijos separan límites distintos sobre el mismo cache.
+func TestLimiter_Prefix(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemory("test")
+
+	if _, err := (Limiter{Cache: c, Prefix: "a:"}).Allow(ctx, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Limiter{Cache: c, Prefix: "b:"}).Allow(ctx, "x"); err != nil {
+		t.Fatalf("otro prefijo: err = %v", err)
+	}
+	if _, err := (Limiter{Cache: c, Prefix: "a:"}).Allow(ctx, "x"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("mismo prefijo: err = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestLimiter_NilCache(t *testing.T) {
+	l := Limiter{}
+	for i := 0; i < 10; i++ {
+		if _, err := l.Allow(context.Background(), "+5491122334455"); err != nil {
+			t.Fatalf("sin cache no limita: err = %v", err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package sms
+
+import (
+	"context"
+
+	"github.com/dropDatabas3/hellojohn/internal/observability/logger"
+)
+
+// LogSender implementa Sender logueando el mensaje en vez de enviarlo.
+// Sólo para desarrollo: el código de verificación queda en los logs.
+type LogSender struct{}
+
+// Send implementa Sender.
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	if !IsE164(msg.To) {
+		return ErrInvalidPhone
+	}
+	logger.From(ctx).Info("sms (log provider)",
+		logger.String("to", msg.To),
+		logger.String("channel", channelOf(msg)),
+		logger.String("body", msg.Body),
+	)
+	return nil
+}
@@ -0,0 +1,39 @@
+package sms
+
+import (
+	"regexp"
+	"strings"
+)
+
+// e164 acepta "+" y de 8 a 15 dígitos sin cero inicial (ITU-T E.164).
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// NormalizeE164 limpia separadores habituales (espacios, guiones, puntos,
+// paréntesis) y valida que el resultado sea E.164. No agrega prefijos de país:
+// el número tiene que venir con "+".
+func NormalizeE164(raw string) (string, error) {
+	phone := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+	if !e164.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+	return phone, nil
+}
+
+// IsE164 indica si phone ya está en formato E.164.
+func IsE164(phone string) bool {
+	return e164.MatchString(phone)
+}
+
+// Mask oculta el número para mostrarlo o loguearlo: "+54*******4455".
+func Mask(phone string) string {
+	if len(phone) <= 6 {
+		return phone
+	}
+	return phone[:3] + strings.Repeat("*", len(phone)-7) + phone[len(phone)-4:]
+}
@@ -0,0 +1,45 @@
+package sms
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeE164(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"+5491122334455", "+5491122334455", false},
+		{" +54 9 (11) 2233-4455 ", "+5491122334455", false},
+		{"+1.415.555.2671", "+14155552671", false},
+		{"5491122334455", "", true},     // sin "+"
+		{"+0123456789", "", true},       // cero inicial
+		{"+1234567", "", true},          // muy corto
+		{"+1234567890123456", "", true}, // muy largo
+		{"+54911abc4455", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeE164(tt.raw)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidPhone) {
+				t.Errorf("NormalizeE164(%q) err = %v, want ErrInvalidPhone", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeE164(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	if got := Mask("+5491122334455"); got != "+54*******4455" {
+		t.Fatalf("Mask = %q", got)
+	}
+	if got := Mask("+1234"); got != "+1234" {
+		t.Fatalf("Mask corto = %q", got)
+	}
+}
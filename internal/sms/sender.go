@@ -0,0 +1,98 @@
+// Package sms envía códigos de verificación por SMS o llamada de voz.
+//
+// Sender es el análogo de emailv2.Sender para teléfonos; el provider se elige
+// por configuración (SMS_PROVIDER):
+//
+//   - "twilio":  API REST de Twilio (o compatible, ver TwilioSender.BaseURL)
+//   - "webhook": POST JSON firmado a una URL propia (gateway interno, otro provider)
+//   - "log":     sólo loguea el mensaje (desarrollo; el código queda en los logs)
+//
+// Los números viajan siempre en formato E.164 (ver NormalizeE164).
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Canales de envío.
+const (
+	ChannelSMS   = "sms"
+	ChannelVoice = "voice"
+)
+
+// Message es un mensaje a enviar a un teléfono.
+type Message struct {
+	To      string // E.164
+	Body    string // texto del SMS o lo que se dice en la llamada
+	Channel string // ChannelSMS (default) | ChannelVoice
+}
+
+// Sender es la interfaz para enviar mensajes a teléfonos.
+// Implementada por TwilioSender, WebhookSender y LogSender.
+type Sender interface {
+	// Send envía el mensaje por el canal indicado.
+	// Retorna ErrChannelNotSupported si el provider no soporta el canal.
+	Send(ctx context.Context, msg Message) error
+}
+
+// Errores
+var (
+	ErrInvalidPhone        = errors.New("sms: phone number must be E.164")
+	ErrChannelNotSupported = errors.New("sms: channel not supported by provider")
+	ErrNotConfigured       = errors.New("sms: provider not configured")
+	ErrRateLimited         = errors.New("sms: too many messages to this number")
+)
+
+// Config selecciona y configura el provider.
+type Config struct {
+	Provider string // "twilio" | "webhook" | "log" | "" (deshabilitado)
+
+	// Twilio
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string // número E.164 o Messaging Service SID ("MG...")
+	TwilioBaseURL    string // vacío = https://api.twilio.com
+
+	// Webhook
+	WebhookURL    string
+	WebhookSecret string // HMAC-SHA256 del body en X-HelloJohn-Signature (opcional)
+}
+
+// NewSender crea el Sender configurado. Provider vacío retorna (nil, nil):
+// sin provider no hay verificación de teléfono ni MFA por SMS.
+func NewSender(cfg Config) (Sender, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "":
+		return nil, nil
+	case "twilio":
+		if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" || cfg.TwilioFrom == "" {
+			return nil, fmt.Errorf("%w: twilio requires account SID, auth token and from", ErrNotConfigured)
+		}
+		return &TwilioSender{
+			AccountSID: cfg.TwilioAccountSID,
+			AuthToken:  cfg.TwilioAuthToken,
+			From:       cfg.TwilioFrom,
+			BaseURL:    cfg.TwilioBaseURL,
+		}, nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("%w: webhook requires URL", ErrNotConfigured)
+		}
+		return &WebhookSender{URL: cfg.WebhookURL, Secret: cfg.WebhookSecret}, nil
+	case "log":
+		return LogSender{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrNotConfigured, cfg.Provider)
+	}
+}
+
+// channelOf normaliza el canal (vacío = SMS).
+func channelOf(msg Message) string {
+	if msg.Channel == "" {
+		return ChannelSMS
+	}
+	return msg.Channel
+}
@@ -0,0 +1,150 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    string // tipo esperado; vacío = nil
+		wantErr bool
+	}{
+		{"deshabilitado", Config{}, "", false},
+		{"log", Config{Provider: " LOG "}, "log", false},
+		{"twilio", Config{Provider: "twilio", TwilioAccountSID: "AC1", TwilioAuthToken: "tok", TwilioFrom: "+15005550006"}, "twilio", false},
+		{"twilio incompleto", Config{Provider: "twilio", TwilioAccountSID: "AC1"}, "", true},
+		{"webhook", Config{Provider: "webhook", WebhookURL: "https://sms.example.com"}, "webhook", false},
+		{"webhook sin url", Config{Provider: "webhook"}, "", true},
+		{"desconocido", Config{Provider: "carrier-pigeon"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSender(tt.cfg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNotConfigured) {
+					t.Fatalf("err = %v, want ErrNotConfigured", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			switch s.(type) {
+			case LogSender:
+				got = "log"
+			case *TwilioSender:
+				got = "twilio"
+			case *WebhookSender:
+				got = "webhook"
+			}
+			if got != tt.want || (tt.want == "" && s != nil) {
+				t.Fatalf("NewSender = %T, want %s", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestTwilioSender(t *testing.T) {
+	var (
+		path string
+		form url.Values
+		user string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, _, _ = r.BasicAuth()
+		_ = r.ParseForm()
+		form = r.PostForm
+		if form.Get("To") == "+15005550001" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"code": 21211, "message": "Invalid 'To' Phone Number", "status": 400}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := &TwilioSender{AccountSID: "AC1", AuthToken: "tok", From: "+15005550006", BaseURL: srv.URL + "/", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	if err := s.Send(ctx, Message{To: "+5491122334455", Body: "code 1"}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/2010-04-01/Accounts/AC1/Messages.json" || user != "AC1" || form.Get("From") != "+15005550006" || form.Get("Body") != "code 1" {
+		t.Fatalf("SMS: path=%s user=%s form=%v", path, user, form)
+	}
+
+	if err := s.Send(ctx, Message{To: "+5491122334455", Body: "1, 2 & 3", Channel: ChannelVoice}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/2010-04-01/Accounts/AC1/Calls.json" || !strings.Contains(form.Get("Twiml"), `<Say language="es-ES">1, 2 &amp; 3</Say>`) {
+		t.Fatalf("voz: path=%s twiml=%s", path, form.Get("Twiml"))
+	}
+
+	err := s.Send(ctx, Message{To: "+15005550001", Body: "x"})
+	if err == nil || !strings.Contains(err.Error(), "21211") {
+		t.Fatalf("error de la API: err = %v", err)
+	}
+	if err := s.Send(ctx, Message{To: "1122334455", Body: "x"}); !errors.Is(err, ErrInvalidPhone) {
+		t.Fatalf("número inválido: err = %v", err)
+	}
+
+	// Un Messaging Service envía SMS pero no llama
+	ms := &TwilioSender{AccountSID: "AC1", AuthToken: "tok", From: "MG123", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	if err := ms.Send(ctx, Message{To: "+5491122334455", Body: "x"}); err != nil || form.Get("MessagingServiceSid") != "MG123" {
+		t.Fatalf("messaging service: err = %v form = %v", err, form)
+	}
+	if err := ms.Send(ctx, Message{To: "+5491122334455", Body: "x", Channel: ChannelVoice}); !errors.Is(err, ErrChannelNotSupported) {
+		t.Fatalf("voz con messaging service: err = %v", err)
+	}
+}
+
+func TestWebhookSender(t *testing.T) {
+	var (
+		body []byte
+		sig  string
+	)
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(SignatureHeader)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := &WebhookSender{URL: srv.URL, Secret: "s3cret", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	if err := s.Send(ctx, Message{To: "+5491122334455", Body: "code 1"}); err != nil {
+		t.Fatal(err)
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.To != "+5491122334455" || payload.Body != "code 1" || payload.Channel != ChannelSMS {
+		t.Fatalf("payload = %+v", payload)
+	}
+	if sig != Sign("s3cret", body) || !strings.HasPrefix(sig, "sha256=") {
+		t.Fatalf("firma = %q", sig)
+	}
+
+	status = http.StatusBadGateway
+	if err := s.Send(ctx, Message{To: "+5491122334455", Body: "x"}); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("status no 2xx: err = %v", err)
+	}
+	if err := s.Send(ctx, Message{To: "+54 11", Body: "x"}); !errors.Is(err, ErrInvalidPhone) {
+		t.Fatalf("número inválido: err = %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package sms
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// IDs de los templates de mensajes (PhoneSettings.Templates[lang][id]).
+const (
+	TemplateCode      = "sms_code"   // código enviado por SMS
+	TemplateVoiceCode = "voice_code" // código leído en una llamada
+)
+
+// DefaultLanguage es el idioma de los templates cuando ningún otro aplica.
+const DefaultLanguage = "es"
+
+// defaultTemplates son los textos por defecto, por idioma.
+var defaultTemplates = map[string]map[string]string{
+	"es": {
+		TemplateCode:      "Tu código de verificación es {{.Code}}. Vence en {{.Minutes}} minutos. No lo compartas con nadie.",
+		TemplateVoiceCode: "Tu código de verificación es: {{.Code}}.",
+	},
+	"en": {
+		TemplateCode:      "Your verification code is {{.Code}}. It expires in {{.Minutes}} minutes. Don't share it with anyone.",
+		TemplateVoiceCode: "Your verification code is: {{.Code}}.",
+	},
+}
+
+// CodeVars son las variables de los templates de código.
+type CodeVars struct {
+	Code    string // en llamadas, los dígitos separados por comas (se leen de a uno)
+	Minutes int    // validez del código
+}
+
+// RenderCode arma el texto del mensaje con code para channel. Recorre langs en
+// orden (ej. idioma del usuario, del tenant) buscando primero el template del
+// tenant (tenant[lang][id]) y después el default de ese idioma; si ninguno
+// aplica usa el default en DefaultLanguage. Un template del tenant que no
+// compila se ignora.
+func RenderCode(tenant map[string]map[string]string, langs []string, channel, code string, ttl time.Duration) string {
+	id := TemplateCode
+	vars := CodeVars{Code: code, Minutes: int(ttl.Minutes())}
+	if channel == ChannelVoice {
+		id = TemplateVoiceCode
+		vars.Code = strings.Join(strings.Split(code, ""), ", ")
+	}
+
+	for _, lang := range append(langs, DefaultLanguage) {
+		if lang == "" {
+			continue
+		}
+		if text, ok := tenant[lang][id]; ok && text != "" {
+			if body, err := render(text, vars); err == nil {
+				return body
+			}
+		}
+		if text, ok := defaultTemplates[lang][id]; ok {
+			if body, err := render(text, vars); err == nil {
+				return body
+			}
+		}
+	}
+	return ""
+}
+
+// ValidateTemplates chequea que los templates de un tenant usen IDs conocidos
+// y compilen.
+func ValidateTemplates(tenant map[string]map[string]string) error {
+	for lang, byID := range tenant {
+		for id, text := range byID {
+			if id != TemplateCode && id != TemplateVoiceCode {
+				return fmt.Errorf("unknown sms template %q", id)
+			}
+			if _, err := render(text, CodeVars{Code: "000000", Minutes: 5}); err != nil {
+				return fmt.Errorf("sms template %s/%s: %w", lang, id, err)
+			}
+		}
+	}
+	return nil
+}
+
+func render(text string, vars CodeVars) (string, error) {
+	t, err := template.New("sms").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,66 @@
+package sms
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderCode(t *testing.T) {
+	tenant := map[string]map[string]string{
+		"en": {TemplateCode: "Acme code: {{.Code}} ({{.Minutes}} min)"},
+		"pt": {TemplateCode: "{{.Missing}}"}, // no compila con missingkey=error: se ignora
+	}
+
+	tests := []struct {
+		name    string
+		tenant  map[string]map[string]string
+		langs   []string
+		channel string
+		want    string
+	}{
+		{"default es", nil, nil, ChannelSMS, "Tu código de verificación es 123456. Vence en 5 minutos. No lo compartas con nadie."},
+		{"default en", nil, []string{"en"}, ChannelSMS, "Your verification code is 123456. It expires in 5 minutes. Don't share it with anyone."},
+		{"template del tenant", tenant, []string{"en"}, ChannelSMS, "Acme code: 123456 (5 min)"},
+		{"idioma del usuario sin template cae al del tenant", tenant, []string{"", "fr", "en"}, ChannelSMS, "Acme code: 123456 (5 min)"},
+		{"idioma desconocido usa el default", nil, []string{"fr"}, ChannelSMS, "Tu código de verificación es 123456. Vence en 5 minutos. No lo compartas con nadie."},
+		{"template roto cae al default", tenant, []string{"pt"}, ChannelSMS, "Tu código de verificación es 123456. Vence en 5 minutos. No lo compartas con nadie."},
+		{"voz lee los dígitos de a uno", nil, []string{"en"}, ChannelVoice, "Your verification code is: 1, 2, 3, 4, 5, 6."},
+		{"voz sin template del tenant usa el default del idioma", tenant, []string{"en"}, ChannelVoice, "Your verification code is: 1, 2, 3, 4, 5, 6."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderCode(tt.tenant, tt.langs, tt.channel, "123456", 5*time.Minute); got != tt.want {
+				t.Fatalf("RenderCode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  map[string]map[string]string
+		wantErr string
+	}{
+		{"vacío", nil, ""},
+		{"válidos", map[string]map[string]string{"en": {TemplateCode: "{{.Code}}", TemplateVoiceCode: "{{.Code}} {{.Minutes}}"}}, ""},
+		{"id desconocido", map[string]map[string]string{"en": {"email_code": "{{.Code}}"}}, "unknown sms template"},
+		{"no parsea", map[string]map[string]string{"en": {TemplateCode: "{{.Code"}}, "sms template en/sms_code"},
+		{"variable inexistente", map[string]map[string]string{"es": {TemplateCode: "{{.Phone}}"}}, "sms template es/sms_code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplates(tt.tenant)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
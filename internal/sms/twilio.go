@@ -0,0 +1,111 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultTwilioBaseURL = "https://api.twilio.com"
+
+// TwilioSender implementa Sender con la API REST de Twilio (Messages para SMS,
+// Calls con TwiML <Say> para voz). BaseURL permite apuntar a providers con API
+// compatible.
+type TwilioSender struct {
+	AccountSID string
+	AuthToken  string
+	From       string // número E.164 o Messaging Service SID ("MG...", sólo SMS)
+	BaseURL    string // vacío = https://api.twilio.com
+	Language   string // idioma de la voz (TwiML language); vacío = "es-ES"
+
+	HTTPClient *http.Client // nil = cliente con timeout de 10s
+}
+
+var twilioHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Send implementa Sender.
+func (s *TwilioSender) Send(ctx context.Context, msg Message) error {
+	if !IsE164(msg.To) {
+		return ErrInvalidPhone
+	}
+
+	form := url.Values{}
+	form.Set("To", msg.To)
+	var resource string
+	switch channelOf(msg) {
+	case ChannelSMS:
+		resource = "Messages.json"
+		if strings.HasPrefix(s.From, "MG") {
+			form.Set("MessagingServiceSid", s.From)
+		} else {
+			form.Set("From", s.From)
+		}
+		form.Set("Body", msg.Body)
+	case ChannelVoice:
+		if strings.HasPrefix(s.From, "MG") {
+			return ErrChannelNotSupported
+		}
+		resource = "Calls.json"
+		form.Set("From", s.From)
+		form.Set("Twiml", s.twiml(msg.Body))
+	default:
+		return ErrChannelNotSupported
+	}
+
+	base := s.BaseURL
+	if base == "" {
+		base = defaultTwilioBaseURL
+	}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/%s", strings.TrimRight(base, "/"), url.PathEscape(s.AccountSID), resource)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("twilio: build request: %w", err)
+	}
+	req.SetBasicAuth(s.AccountSID, s.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	hc := s.HTTPClient
+	if hc == nil {
+		hc = twilioHTTPClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio: send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Errores de Twilio: {"code": 21211, "message": "...", "status": 400}
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio: status %d: %d %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("twilio: status %d", resp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	return nil
+}
+
+// twiml arma la llamada: el mensaje se repite dos veces.
+func (s *TwilioSender) twiml(body string) string {
+	lang := s.Language
+	if lang == "" {
+		lang = "es-ES"
+	}
+	var text strings.Builder
+	_ = xml.EscapeText(&text, []byte(body))
+	say := fmt.Sprintf(`<Say language="%s">%s</Say>`, lang, text.String())
+	return `<?xml version="1.0" encoding="UTF-8"?><Response>` + say + `<Pause length="1"/>` + say + `</Response>`
+}
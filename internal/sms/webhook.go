@@ -0,0 +1,78 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader lleva "sha256=<hex>" = HMAC-SHA256(secret, body) del webhook.
+const SignatureHeader = "X-HelloJohn-Signature"
+
+// WebhookSender implementa Sender haciendo POST de un JSON
+// {"to", "body", "channel"} a URL. Cualquier 2xx cuenta como enviado.
+type WebhookSender struct {
+	URL    string
+	Secret string // vacío = sin firma
+
+	HTTPClient *http.Client // nil = cliente con timeout de 10s
+}
+
+var webhookHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+type webhookPayload struct {
+	To      string `json:"to"`
+	Body    string `json:"body"`
+	Channel string `json:"channel"`
+}
+
+// Send implementa Sender.
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+	if !IsE164(msg.To) {
+		return ErrInvalidPhone
+	}
+	payload, err := json.Marshal(webhookPayload{To: msg.To, Body: msg.Body, Channel: channelOf(msg)})
+	if err != nil {
+		return fmt.Errorf("sms webhook: encode: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("sms webhook: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(s.Secret, payload))
+	}
+
+	hc := s.HTTPClient
+	if hc == nil {
+		hc = webhookHTTPClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms webhook: send: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sms webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign calcula el valor de SignatureHeader para body, para que el receptor
+// del webhook pueda verificarlo.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
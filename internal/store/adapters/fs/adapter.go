@@ -83,6 +83,9 @@ func (c *fsConnection) Admins() repository.AdminRepository { return newAdminRepo
 func (c *fsConnection) AdminRefreshTokens() repository.AdminRefreshTokenRepository {
 	return newAdminRefreshTokenRepo(c.root)
 }
+func (c *fsConnection) APIResources() repository.APIResourceRepository {
+	return &apiResourceRepo{conn: c}
+}
 
 // Data plane (NO soportado por FS)
 func (c *fsConnection) Users() repository.UserRepository             { return nil }
@@ -95,6 +98,12 @@ func (c *fsConnection) EmailTokens() repository.EmailTokenRepository { return ni
 func (c *fsConnection) Identities() repository.IdentityRepository    { return nil }
 func (c *fsConnection) Sessions() repository.SessionRepository       { return nil }
 
+// LoginAttempts usa el fallback en cache del tenant
+func (c *fsConnection) LoginAttempts() repository.LoginAttemptRepository { return nil }
+
+// WebAuthn requiere DB
+func (c *fsConnection) WebAuthn() repository.WebAuthnRepository { return nil }
+
 // ─── Helpers ───
 
 func (c *fsConnection) tenantPath(slug string) string {
@@ -317,7 +326,39 @@ func (r *clientRepo) Create(ctx context.Context, tenantID string, input reposito
 		AllowedOrigins:           input.AllowedOrigins,
 		Providers:                input.Providers,
 		Scopes:                   input.Scopes,
+		SecretEnc:                input.Secret,
 		RequireEmailVerification: input.RequireEmailVerification,
+		PostLogoutURIs:           input.PostLogoutURIs,
+		GrantTypes:               input.GrantTypes,
+		TokenExchange:            tokenExchangeToYAML(input.TokenExchange),
+		TokenEndpointAuthMethod:  input.TokenEndpointAuthMethod,
+		JWKS:                     input.JWKS,
+		JWKSURI:                  input.JWKSURI,
+		TLSClientAuthSubjectDN:   input.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  input.TLSClientCertThumbprint,
+		RequirePAR:               input.RequirePAR,
+		IDTokenSignedResponseAlg: input.IDTokenSignedResponseAlg,
+		FirstParty:               input.FirstParty,
+
+		BackchannelLogoutURI:              input.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  input.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             input.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: input.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         input.SubjectType,
+		SectorIdentifierURI: input.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        input.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     input.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: input.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  input.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  input.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    input.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: input.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: input.UserinfoEncryptedResponseEnc,
+
+		RegistrationAccessTokenHash: input.RegistrationAccessTokenHash,
 	}
 	clients = append(clients, newClient)
 
@@ -338,9 +379,18 @@ func (r *clientRepo) Update(ctx context.Context, tenantID string, input reposito
 		return nil, err
 	}
 
-	found := false
+	found := -1
 	for i, c := range clients {
 		if c.ClientID == input.ClientID {
+			// Secret y registration token vacíos = conservar los actuales
+			secretEnc := c.SecretEnc
+			if input.Secret != "" {
+				secretEnc = input.Secret
+			}
+			ratHash := c.RegistrationAccessTokenHash
+			if input.RegistrationAccessTokenHash != "" {
+				ratHash = input.RegistrationAccessTokenHash
+			}
 			clients[i] = clientYAML{
 				ClientID:                 input.ClientID,
 				Name:                     input.Name,
@@ -349,14 +399,46 @@ func (r *clientRepo) Update(ctx context.Context, tenantID string, input reposito
 				AllowedOrigins:           input.AllowedOrigins,
 				Providers:                input.Providers,
 				Scopes:                   input.Scopes,
+				SecretEnc:                secretEnc,
 				RequireEmailVerification: input.RequireEmailVerification,
+				PostLogoutURIs:           input.PostLogoutURIs,
+				GrantTypes:               input.GrantTypes,
+				TokenExchange:            tokenExchangeToYAML(input.TokenExchange),
+				TokenEndpointAuthMethod:  input.TokenEndpointAuthMethod,
+				JWKS:                     input.JWKS,
+				JWKSURI:                  input.JWKSURI,
+				TLSClientAuthSubjectDN:   input.TLSClientAuthSubjectDN,
+				TLSClientCertThumbprint:  input.TLSClientCertThumbprint,
+				RequirePAR:               input.RequirePAR,
+				IDTokenSignedResponseAlg: input.IDTokenSignedResponseAlg,
+				FirstParty:               input.FirstParty,
+
+				BackchannelLogoutURI:              input.BackchannelLogoutURI,
+				BackchannelLogoutSessionRequired:  input.BackchannelLogoutSessionRequired,
+				FrontchannelLogoutURI:             input.FrontchannelLogoutURI,
+				FrontchannelLogoutSessionRequired: input.FrontchannelLogoutSessionRequired,
+
+				SubjectType:         input.SubjectType,
+				SectorIdentifierURI: input.SectorIdentifierURI,
+
+				RequestObjectSigningAlg:        input.RequestObjectSigningAlg,
+				RequireSignedRequestObject:     input.RequireSignedRequestObject,
+				AuthorizationSignedResponseAlg: input.AuthorizationSignedResponseAlg,
+
+				IDTokenEncryptedResponseAlg:  input.IDTokenEncryptedResponseAlg,
+				IDTokenEncryptedResponseEnc:  input.IDTokenEncryptedResponseEnc,
+				UserinfoSignedResponseAlg:    input.UserinfoSignedResponseAlg,
+				UserinfoEncryptedResponseAlg: input.UserinfoEncryptedResponseAlg,
+				UserinfoEncryptedResponseEnc: input.UserinfoEncryptedResponseEnc,
+
+				RegistrationAccessTokenHash: ratHash,
 			}
-			found = true
+			found = i
 			break
 		}
 	}
 
-	if !found {
+	if found < 0 {
 		return nil, repository.ErrNotFound
 	}
 
@@ -364,7 +446,8 @@ func (r *clientRepo) Update(ctx context.Context, tenantID string, input reposito
 		return nil, err
 	}
 
-	return r.Get(ctx, tenantID, input.ClientID)
+	// No usar r.Get: tomaría el RLock con el Lock ya tomado (deadlock)
+	return clients[found].toRepository(tenantID), nil
 }
 
 func (r *clientRepo) Delete(ctx context.Context, tenantID, clientID string) error {
@@ -1132,10 +1215,13 @@ type tenantSettingsYAML struct {
 	BrandColor                  string `yaml:"brandColor,omitempty"`
 	SessionLifetimeSeconds      int    `yaml:"sessionLifetimeSeconds,omitempty"`
 	RefreshTokenLifetimeSeconds int    `yaml:"refreshTokenLifetimeSeconds,omitempty"`
+	RefreshReuseGraceSeconds    int    `yaml:"refreshReuseGraceSeconds,omitempty"`
 	MFAEnabled                  bool   `yaml:"mfaEnabled,omitempty"`
 	SocialLoginEnabled          bool   `yaml:"social_login_enabled,omitempty"`
 	IssuerMode                  string `yaml:"issuerMode,omitempty"`
 	IssuerOverride              string `yaml:"issuerOverride,omitempty"`
+	SigningAlgorithm            string `yaml:"signingAlgorithm,omitempty"`
+	AccessTokenProfile          string `yaml:"accessTokenProfile,omitempty"`
 
 	SMTP *struct {
 		Host        string `yaml:"host,omitempty"`
@@ -1172,6 +1258,11 @@ type tenantSettingsYAML struct {
 	} `yaml:"socialProviders,omitempty"`
 
 	UserFields []userFieldYAML `yaml:"userFields,omitempty"`
+
+	ClientRegistration *repository.ClientRegistrationSettings `yaml:"clientRegistration,omitempty"`
+	ConsentPolicy      *repository.ConsentPolicySettings      `yaml:"consentPolicy,omitempty"`
+
+	AuthorizationDetailsTypes []repository.AuthorizationDetailsType `yaml:"authorizationDetailsTypes,omitempty"`
 }
 
 // userFieldYAML representa un campo custom de usuario para serialización YAML.
@@ -1197,10 +1288,16 @@ func (t *tenantYAML) toRepository(slug string) *repository.Tenant {
 			BrandColor:                  t.Settings.BrandColor,
 			SessionLifetimeSeconds:      t.Settings.SessionLifetimeSeconds,
 			RefreshTokenLifetimeSeconds: t.Settings.RefreshTokenLifetimeSeconds,
+			RefreshReuseGraceSeconds:    t.Settings.RefreshReuseGraceSeconds,
 			MFAEnabled:                  t.Settings.MFAEnabled,
 			SocialLoginEnabled:          t.Settings.SocialLoginEnabled,
 			IssuerMode:                  t.Settings.IssuerMode,
 			IssuerOverride:              t.Settings.IssuerOverride,
+			SigningAlgorithm:            t.Settings.SigningAlgorithm,
+			AccessTokenProfile:          t.Settings.AccessTokenProfile,
+			ClientRegistration:          t.Settings.ClientRegistration,
+			ConsentPolicy:               t.Settings.ConsentPolicy,
+			AuthorizationDetailsTypes:   t.Settings.AuthorizationDetailsTypes,
 		},
 	}
 
@@ -1276,10 +1373,16 @@ func toTenantYAML(t *repository.Tenant) *tenantYAML {
 			BrandColor:                  t.Settings.BrandColor,
 			SessionLifetimeSeconds:      t.Settings.SessionLifetimeSeconds,
 			RefreshTokenLifetimeSeconds: t.Settings.RefreshTokenLifetimeSeconds,
+			RefreshReuseGraceSeconds:    t.Settings.RefreshReuseGraceSeconds,
 			MFAEnabled:                  t.Settings.MFAEnabled,
 			SocialLoginEnabled:          t.Settings.SocialLoginEnabled,
 			IssuerMode:                  t.Settings.IssuerMode,
 			IssuerOverride:              t.Settings.IssuerOverride,
+			SigningAlgorithm:            t.Settings.SigningAlgorithm,
+			AccessTokenProfile:          t.Settings.AccessTokenProfile,
+			ClientRegistration:          t.Settings.ClientRegistration,
+			ConsentPolicy:               t.Settings.ConsentPolicy,
+			AuthorizationDetailsTypes:   t.Settings.AuthorizationDetailsTypes,
 		},
 	}
 
@@ -1387,6 +1490,70 @@ type clientYAML struct {
 	Scopes                   []string `yaml:"scopes,omitempty"`
 	SecretEnc                string   `yaml:"secretEnc,omitempty"`
 	RequireEmailVerification bool     `yaml:"requireEmailVerification,omitempty"`
+	PostLogoutURIs           []string `yaml:"postLogoutUris,omitempty"`
+	GrantTypes               []string `yaml:"grantTypes,omitempty"`
+
+	TokenExchange *tokenExchangeYAML `yaml:"tokenExchange,omitempty"`
+
+	TokenEndpointAuthMethod  string `yaml:"tokenEndpointAuthMethod,omitempty"`
+	JWKS                     string `yaml:"jwks,omitempty"`
+	JWKSURI                  string `yaml:"jwksUri,omitempty"`
+	TLSClientAuthSubjectDN   string `yaml:"tlsClientAuthSubjectDn,omitempty"`
+	TLSClientCertThumbprint  string `yaml:"tlsClientCertThumbprint,omitempty"`
+	RequirePAR               bool   `yaml:"requirePar,omitempty"`
+	IDTokenSignedResponseAlg string `yaml:"idTokenSignedResponseAlg,omitempty"`
+	FirstParty               bool   `yaml:"firstParty,omitempty"`
+
+	BackchannelLogoutURI              string `yaml:"backchannelLogoutUri,omitempty"`
+	BackchannelLogoutSessionRequired  bool   `yaml:"backchannelLogoutSessionRequired,omitempty"`
+	FrontchannelLogoutURI             string `yaml:"frontchannelLogoutUri,omitempty"`
+	FrontchannelLogoutSessionRequired bool   `yaml:"frontchannelLogoutSessionRequired,omitempty"`
+
+	SubjectType         string `yaml:"subjectType,omitempty"`
+	SectorIdentifierURI string `yaml:"sectorIdentifierUri,omitempty"`
+
+	RequestObjectSigningAlg        string `yaml:"requestObjectSigningAlg,omitempty"`
+	RequireSignedRequestObject     bool   `yaml:"requireSignedRequestObject,omitempty"`
+	AuthorizationSignedResponseAlg string `yaml:"authorizationSignedResponseAlg,omitempty"`
+
+	IDTokenEncryptedResponseAlg  string `yaml:"idTokenEncryptedResponseAlg,omitempty"`
+	IDTokenEncryptedResponseEnc  string `yaml:"idTokenEncryptedResponseEnc,omitempty"`
+	UserinfoSignedResponseAlg    string `yaml:"userinfoSignedResponseAlg,omitempty"`
+	UserinfoEncryptedResponseAlg string `yaml:"userinfoEncryptedResponseAlg,omitempty"`
+	UserinfoEncryptedResponseEnc string `yaml:"userinfoEncryptedResponseEnc,omitempty"`
+
+	RegistrationAccessTokenHash string `yaml:"registrationAccessTokenHash,omitempty"`
+}
+
+type tokenExchangeYAML struct {
+	AllowedAudiences   []string `yaml:"allowedAudiences,omitempty"`
+	AllowedScopes      []string `yaml:"allowedScopes,omitempty"`
+	AllowImpersonation bool     `yaml:"allowImpersonation,omitempty"`
+	AllowDelegation    bool     `yaml:"allowDelegation,omitempty"`
+}
+
+func tokenExchangeToYAML(p *repository.TokenExchangePolicy) *tokenExchangeYAML {
+	if p == nil {
+		return nil
+	}
+	return &tokenExchangeYAML{
+		AllowedAudiences:   p.AllowedAudiences,
+		AllowedScopes:      p.AllowedScopes,
+		AllowImpersonation: p.AllowImpersonation,
+		AllowDelegation:    p.AllowDelegation,
+	}
+}
+
+func (t *tokenExchangeYAML) toRepository() *repository.TokenExchangePolicy {
+	if t == nil {
+		return nil
+	}
+	return &repository.TokenExchangePolicy{
+		AllowedAudiences:   t.AllowedAudiences,
+		AllowedScopes:      t.AllowedScopes,
+		AllowImpersonation: t.AllowImpersonation,
+		AllowDelegation:    t.AllowDelegation,
+	}
 }
 
 func (c *clientYAML) toRepository(tenantID string) *repository.Client {
@@ -1401,6 +1568,37 @@ func (c *clientYAML) toRepository(tenantID string) *repository.Client {
 		Scopes:                   c.Scopes,
 		SecretEnc:                c.SecretEnc,
 		RequireEmailVerification: c.RequireEmailVerification,
+		PostLogoutURIs:           c.PostLogoutURIs,
+		GrantTypes:               c.GrantTypes,
+		TokenExchange:            c.TokenExchange.toRepository(),
+		TokenEndpointAuthMethod:  c.TokenEndpointAuthMethod,
+		JWKS:                     c.JWKS,
+		JWKSURI:                  c.JWKSURI,
+		TLSClientAuthSubjectDN:   c.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  c.TLSClientCertThumbprint,
+		RequirePAR:               c.RequirePAR,
+		IDTokenSignedResponseAlg: c.IDTokenSignedResponseAlg,
+		FirstParty:               c.FirstParty,
+
+		BackchannelLogoutURI:              c.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  c.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             c.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: c.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         c.SubjectType,
+		SectorIdentifierURI: c.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        c.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     c.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: c.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  c.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  c.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    c.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: c.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: c.UserinfoEncryptedResponseEnc,
+
+		RegistrationAccessTokenHash: c.RegistrationAccessTokenHash,
 	}
 }
 
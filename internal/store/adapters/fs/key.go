@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
@@ -13,8 +14,10 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -59,6 +62,64 @@ func (r *keyRepo) dirFor(tenantID string) string {
 	return filepath.Join(base, tenantID)
 }
 
+// keyFileName devuelve el archivo de una clave según estado ("active"/"retiring") y algoritmo.
+// EdDSA conserva los nombres históricos (active.json, retiring.json); el resto
+// usa active.<alg>.json / retiring.<alg>.json en el mismo keyring.
+func keyFileName(state, algorithm string) string {
+	if algorithm == "" || algorithm == "EdDSA" {
+		return state + ".json"
+	}
+	return state + "." + algorithm + ".json"
+}
+
+// keyringDir devuelve el keyring que usa el tenant: el propio si tiene clave
+// EdDSA activa, sino el global (mismo fallback que GetActive).
+func (r *keyRepo) keyringDir(tenantID string) string {
+	dir := r.dirFor(tenantID)
+	if tenantID == "" || tenantID == "global" {
+		return dir
+	}
+	if _, err := os.Stat(filepath.Join(dir, "active.json")); err != nil {
+		return r.dirFor("")
+	}
+	return dir
+}
+
+// keyFiles lista los archivos de claves (active* y retiring*) de un directorio.
+func keyFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if strings.HasPrefix(name, "active.") || strings.HasPrefix(name, "retiring.") {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// keyDirs devuelve el directorio global seguido de los directorios de tenants.
+func (r *keyRepo) keyDirs() ([]string, error) {
+	base := filepath.Clean(r.keysDir)
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil, fmt.Errorf("read keys dir: %w", err)
+	}
+	dirs := []string{base}
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, filepath.Join(base, e.Name()))
+		}
+	}
+	return dirs, nil
+}
+
 // ─── Lectura ───
 
 func (r *keyRepo) GetActive(ctx context.Context, tenantID string) (*repository.SigningKey, error) {
@@ -77,38 +138,35 @@ func (r *keyRepo) GetActive(ctx context.Context, tenantID string) (*repository.S
 	return key, err
 }
 
-func (r *keyRepo) GetByKID(ctx context.Context, kid string) (*repository.SigningKey, error) {
+func (r *keyRepo) GetActiveByAlgorithm(ctx context.Context, tenantID, algorithm string) (*repository.SigningKey, error) {
+	if algorithm == "" || algorithm == "EdDSA" {
+		return r.GetActive(ctx, tenantID)
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Search in all directories (global + tenant subdirs)
-	base := filepath.Clean(r.keysDir)
-
-	// Check global active
-	if key, err := r.loadKeyFromFile(base, "active.json"); err == nil && key.ID == kid {
-		return key, nil
-	}
-	// Check global retiring
-	if key, err := r.loadKeyFromFile(base, "retiring.json"); err == nil && key.ID == kid {
-		return key, nil
+	key, err := r.loadKeyFromFile(r.keyringDir(tenantID), keyFileName("active", algorithm))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, repository.ErrNotFound
 	}
+	return key, err
+}
 
-	// Search tenant subdirs
-	entries, err := os.ReadDir(base)
+func (r *keyRepo) GetByKID(ctx context.Context, kid string) (*repository.SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Search in all directories (global + tenant subdirs), every algorithm
+	dirs, err := r.keyDirs()
 	if err != nil {
-		return nil, fmt.Errorf("read keys dir: %w", err)
+		return nil, err
 	}
-
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		dir := filepath.Join(base, e.Name())
-		if key, err := r.loadKeyFromFile(dir, "active.json"); err == nil && key.ID == kid {
-			return key, nil
-		}
-		if key, err := r.loadKeyFromFile(dir, "retiring.json"); err == nil && key.ID == kid {
-			return key, nil
+	for _, dir := range dirs {
+		for _, name := range keyFiles(dir) {
+			if key, err := r.loadKeyFromFile(dir, name); err == nil && key.ID == kid {
+				return key, nil
+			}
 		}
 	}
 
@@ -119,38 +177,21 @@ func (r *keyRepo) GetJWKS(ctx context.Context, tenantID string) (*repository.JWK
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	dir := r.dirFor(tenantID)
-	var jwks repository.JWKS
+	// Tenant sin keyring propio usa el global
+	dir := r.keyringDir(tenantID)
+	jwks := repository.JWKS{Keys: []repository.JWK{}}
 
-	// Load active
-	if key, err := r.loadKeyFromFile(dir, "active.json"); err == nil {
-		if jwk, err := r.toJWK(key); err == nil {
-			jwks.Keys = append(jwks.Keys, jwk)
-		}
-	} else if errors.Is(err, fs.ErrNotExist) && tenantID != "" && tenantID != "global" {
-		// Fallback to global
-		if key, err := r.loadKeyFromFile(r.dirFor(""), "active.json"); err == nil {
-			if jwk, err := r.toJWK(key); err == nil {
-				jwks.Keys = append(jwks.Keys, jwk)
-			}
+	// Active keys + retiring keys within grace period, for every algorithm
+	for _, name := range keyFiles(dir) {
+		key, err := r.loadKeyFromFile(dir, name)
+		if err != nil {
+			continue
 		}
-		// Add global retiring if exists
-		if key, err := r.loadKeyFromFile(r.dirFor(""), "retiring.json"); err == nil {
-			if r.isWithinGracePeriod(r.dirFor(""), key) {
-				if jwk, err := r.toJWK(key); err == nil {
-					jwks.Keys = append(jwks.Keys, jwk)
-				}
-			}
+		if strings.HasPrefix(name, "retiring.") && !r.isWithinGracePeriod(dir, name) {
+			continue
 		}
-		return &jwks, nil
-	}
-
-	// Load retiring if within grace period
-	if key, err := r.loadKeyFromFile(dir, "retiring.json"); err == nil {
-		if r.isWithinGracePeriod(dir, key) {
-			if jwk, err := r.toJWK(key); err == nil {
-				jwks.Keys = append(jwks.Keys, jwk)
-			}
+		if jwk, err := r.toJWK(key); err == nil {
+			jwks.Keys = append(jwks.Keys, jwk)
 		}
 	}
 
@@ -161,30 +202,17 @@ func (r *keyRepo) ListAll(ctx context.Context, tenantID string) ([]*repository.S
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	dir := r.dirFor(tenantID)
-	var keys []*repository.SigningKey
-
-	// Load active key
-	if key, err := r.loadKeyFromFile(dir, "active.json"); err == nil {
-		key.TenantID = tenantID
-		keys = append(keys, key)
-	} else if errors.Is(err, fs.ErrNotExist) && tenantID != "" && tenantID != "global" {
-		// Fallback to global if tenant-specific not found
-		if globalKey, err := r.loadKeyFromFile(r.dirFor(""), "active.json"); err == nil {
-			globalKey.TenantID = "" // Mark as global
-			keys = append(keys, globalKey)
-		}
+	dir := r.keyringDir(tenantID)
+	owner := tenantID
+	if dir != r.dirFor(tenantID) {
+		owner = "" // Fallback to global keyring
 	}
 
-	// Load retiring key
-	if key, err := r.loadKeyFromFile(dir, "retiring.json"); err == nil {
-		key.TenantID = tenantID
-		keys = append(keys, key)
-	} else if tenantID != "" && tenantID != "global" {
-		// Check global retiring
-		if globalKey, err := r.loadKeyFromFile(r.dirFor(""), "retiring.json"); err == nil {
-			globalKey.TenantID = "" // Mark as global
-			keys = append(keys, globalKey)
+	var keys []*repository.SigningKey
+	for _, name := range keyFiles(dir) {
+		if key, err := r.loadKeyFromFile(dir, name); err == nil {
+			key.TenantID = owner
+			keys = append(keys, key)
 		}
 	}
 
@@ -201,18 +229,12 @@ func (r *keyRepo) Generate(ctx context.Context, tenantID, algorithm string) (*re
 		algorithm = "EdDSA"
 	}
 
-	key, err := r.generateNewKey(algorithm)
-	if err != nil {
-		return nil, err
-	}
-	key.TenantID = tenantID
-
+	// EdDSA define el keyring del tenant; el resto de algoritmos se agrega al keyring en uso
 	dir := r.dirFor(tenantID)
-	if err := r.saveKeyToFile(dir, "active.json", key); err != nil {
-		return nil, err
+	if algorithm != "EdDSA" {
+		dir = r.keyringDir(tenantID)
 	}
-
-	return key, nil
+	return r.generateInto(dir, tenantID, algorithm)
 }
 
 func (r *keyRepo) Rotate(ctx context.Context, tenantID string, gracePeriod time.Duration) (*repository.SigningKey, error) {
@@ -221,89 +243,57 @@ func (r *keyRepo) Rotate(ctx context.Context, tenantID string, gracePeriod time.
 
 	dir := r.dirFor(tenantID)
 
-	// Read current active
-	current, err := r.loadKeyFromFile(dir, "active.json")
-	if errors.Is(err, fs.ErrNotExist) {
-		// No active key, just generate a new one
-		return r.Generate(ctx, tenantID, "EdDSA")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("load current active: %w", err)
-	}
-
-	// Move current to retiring
+	// Rotate every algorithm with an active key in this keyring
+	var rotated *repository.SigningKey
 	graceSeconds := int64(gracePeriod.Seconds())
-	if err := r.saveRetiringWithGrace(dir, current, graceSeconds); err != nil {
-		return nil, fmt.Errorf("save retiring: %w", err)
-	}
+	for _, name := range keyFiles(dir) {
+		if !strings.HasPrefix(name, "active.") {
+			continue
+		}
+		current, err := r.loadKeyFromFile(dir, name)
+		if err != nil {
+			return nil, fmt.Errorf("load current active: %w", err)
+		}
 
-	// Generate new active
-	newKey, err := r.generateNewKey("EdDSA")
-	if err != nil {
-		return nil, fmt.Errorf("generate new key: %w", err)
-	}
-	newKey.TenantID = tenantID
+		// Move current to retiring
+		if err := r.saveRetiringWithGrace(dir, keyFileName("retiring", current.Algorithm), current, graceSeconds); err != nil {
+			return nil, fmt.Errorf("save retiring: %w", err)
+		}
 
-	if err := r.saveKeyToFile(dir, "active.json", newKey); err != nil {
-		return nil, fmt.Errorf("save new active: %w", err)
+		// Generate new active
+		newKey, err := r.generateInto(dir, tenantID, current.Algorithm)
+		if err != nil {
+			return nil, fmt.Errorf("generate new key: %w", err)
+		}
+		if newKey.Algorithm == "EdDSA" {
+			rotated = newKey
+		}
 	}
 
-	return newKey, nil
+	// No active EdDSA key, just generate a new one
+	if rotated == nil {
+		return r.generateInto(dir, tenantID, "EdDSA")
+	}
+	return rotated, nil
 }
 
 func (r *keyRepo) Revoke(ctx context.Context, kid string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Search for the key by KID and delete it
-	base := filepath.Clean(r.keysDir)
-
-	// Check global active
-	if key, err := r.loadKeyFromFile(base, "active.json"); err == nil && key.ID == kid {
-		path := filepath.Join(base, "active.json")
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("remove global active key: %w", err)
-		}
-		return nil
-	}
-
-	// Check global retiring
-	if key, err := r.loadKeyFromFile(base, "retiring.json"); err == nil && key.ID == kid {
-		path := filepath.Join(base, "retiring.json")
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("remove global retiring key: %w", err)
-		}
-		return nil
-	}
-
-	// Search tenant subdirs
-	entries, err := os.ReadDir(base)
+	// Search for the key by KID (global + tenant subdirs) and delete it
+	dirs, err := r.keyDirs()
 	if err != nil {
-		return fmt.Errorf("read keys dir: %w", err)
+		return err
 	}
-
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		dir := filepath.Join(base, e.Name())
-
-		// Check tenant active
-		if key, err := r.loadKeyFromFile(dir, "active.json"); err == nil && key.ID == kid {
-			path := filepath.Join(dir, "active.json")
-			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("remove tenant active key: %w", err)
-			}
-			return nil
-		}
-
-		// Check tenant retiring
-		if key, err := r.loadKeyFromFile(dir, "retiring.json"); err == nil && key.ID == kid {
-			path := filepath.Join(dir, "retiring.json")
-			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("remove tenant retiring key: %w", err)
+	for _, dir := range dirs {
+		for _, name := range keyFiles(dir) {
+			if key, err := r.loadKeyFromFile(dir, name); err == nil && key.ID == kid {
+				if err := os.Remove(filepath.Join(dir, name)); err != nil {
+					return fmt.Errorf("remove key %s: %w", name, err)
+				}
+				return nil
 			}
-			return nil
 		}
 	}
 
@@ -397,7 +387,7 @@ func (r *keyRepo) loadKeyFromFile(dir, filename string) (*repository.SigningKey,
 	}
 
 	status := repository.KeyStatusActive
-	if filename == "retiring.json" || kd.Status == "retiring" {
+	if strings.HasPrefix(filename, "retiring.") || kd.Status == "retiring" {
 		status = repository.KeyStatusRetired
 	}
 
@@ -484,7 +474,7 @@ func (r *keyRepo) saveKeyToFile(dir, filename string, key *repository.SigningKey
 	return atomicwrite.AtomicWriteFile(finalPath, data, 0600)
 }
 
-func (r *keyRepo) saveRetiringWithGrace(dir string, key *repository.SigningKey, graceSeconds int64) error {
+func (r *keyRepo) saveRetiringWithGrace(dir, filename string, key *repository.SigningKey, graceSeconds int64) error {
 	// SIGNING_MASTER_KEY es obligatorio
 	masterKey := os.Getenv("SIGNING_MASTER_KEY")
 	if masterKey == "" {
@@ -549,7 +539,7 @@ func (r *keyRepo) saveRetiringWithGrace(dir string, key *repository.SigningKey,
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
-	finalPath := filepath.Join(dir, "retiring.json")
+	finalPath := filepath.Join(dir, filename)
 	return atomicwrite.AtomicWriteFile(finalPath, data, 0600)
 }
 
@@ -577,14 +567,68 @@ func (r *keyRepo) generateNewKey(algorithm string) (*repository.SigningKey, erro
 			Status:     repository.KeyStatusActive, // Status fijo "active"
 			CreatedAt:  now,
 		}, nil
+	case "RS256":
+		priv, err := keycrypto.GenerateRSA()
+		if err != nil {
+			return nil, fmt.Errorf("generate rsa: %w", err)
+		}
+		return &repository.SigningKey{
+			ID:         kid,
+			Algorithm:  "RS256",
+			PrivateKey: priv,
+			PublicKey:  &priv.PublicKey,
+			Status:     repository.KeyStatusActive,
+			CreatedAt:  now,
+		}, nil
+	case "RSA-OAEP-256":
+		// Clave de cifrado (JWE): los clients cifran request objects con ella
+		priv, err := keycrypto.GenerateRSA()
+		if err != nil {
+			return nil, fmt.Errorf("generate rsa: %w", err)
+		}
+		return &repository.SigningKey{
+			ID:         kid,
+			Algorithm:  "RSA-OAEP-256",
+			PrivateKey: priv,
+			PublicKey:  &priv.PublicKey,
+			Status:     repository.KeyStatusActive,
+			CreatedAt:  now,
+		}, nil
+	case "ES256":
+		priv, err := keycrypto.GenerateP256()
+		if err != nil {
+			return nil, fmt.Errorf("generate ecdsa: %w", err)
+		}
+		return &repository.SigningKey{
+			ID:         kid,
+			Algorithm:  "ES256",
+			PrivateKey: priv,
+			PublicKey:  &priv.PublicKey,
+			Status:     repository.KeyStatusActive,
+			CreatedAt:  now,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
 	}
 }
 
-func (r *keyRepo) isWithinGracePeriod(dir string, key *repository.SigningKey) bool {
-	// Read metadata from retiring.json
-	path := filepath.Join(dir, "retiring.json")
+// generateInto genera una clave y la guarda como activa en dir (el caller tiene el lock).
+func (r *keyRepo) generateInto(dir, tenantID, algorithm string) (*repository.SigningKey, error) {
+	key, err := r.generateNewKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key.TenantID = tenantID
+
+	if err := r.saveKeyToFile(dir, keyFileName("active", algorithm), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *keyRepo) isWithinGracePeriod(dir, filename string) bool {
+	// Read metadata from the retiring file
+	path := filepath.Join(dir, filename)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return true // Assume within grace if can't read
@@ -612,14 +656,29 @@ func (r *keyRepo) toJWK(key *repository.SigningKey) (repository.JWK, error) {
 			X:   base64.RawURLEncoding.EncodeToString(pk),
 		}, nil
 	case *ecdsa.PublicKey:
+		// Coordenadas con largo fijo del tamaño de la curva (RFC 7518 §6.2.1.2)
+		size := (pk.Curve.Params().BitSize + 7) / 8
 		return repository.JWK{
 			KID: key.ID,
 			Kty: "EC",
 			Use: "sig",
 			Alg: key.Algorithm,
 			Crv: pk.Curve.Params().Name,
-			X:   base64.RawURLEncoding.EncodeToString(pk.X.Bytes()),
-			Y:   base64.RawURLEncoding.EncodeToString(pk.Y.Bytes()),
+			X:   base64.RawURLEncoding.EncodeToString(pk.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(pk.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case *rsa.PublicKey:
+		use := "sig"
+		if key.Algorithm == "RSA-OAEP-256" {
+			use = "enc"
+		}
+		return repository.JWK{
+			KID: key.ID,
+			Kty: "RSA",
+			Use: use,
+			Alg: key.Algorithm,
+			N:   base64.RawURLEncoding.EncodeToString(pk.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pk.E)).Bytes()),
 		}, nil
 	default:
 		return repository.JWK{}, fmt.Errorf("unsupported key type for JWK: %T", pk)
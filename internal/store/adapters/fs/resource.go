@@ -0,0 +1,192 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// apiResourceRepo implementa repository.APIResourceRepository usando FileSystem.
+// Los API resources se almacenan en: <fsRoot>/tenants/<slug>/resources.yaml
+type apiResourceRepo struct{ conn *fsConnection }
+
+type apiResourcesYAML struct {
+	Resources []apiResourceYAML `yaml:"resources"`
+}
+
+type apiResourceYAML struct {
+	ID             string   `yaml:"id"`
+	Identifier     string   `yaml:"identifier"`
+	Name           string   `yaml:"name,omitempty"`
+	Description    string   `yaml:"description,omitempty"`
+	Scopes         []string `yaml:"scopes,omitempty"`
+	AccessTokenTTL int      `yaml:"access_token_ttl,omitempty"`
+	SigningAlg     string   `yaml:"signing_alg,omitempty"`
+	CreatedAt      string   `yaml:"created_at,omitempty"`
+	UpdatedAt      string   `yaml:"updated_at,omitempty"`
+
+	AccessTokenProfile string `yaml:"access_token_profile,omitempty"`
+}
+
+func (r *apiResourceRepo) resourcesFile(tenantID string) string {
+	return filepath.Join(r.conn.tenantPath(tenantID), "resources.yaml")
+}
+
+func (r *apiResourceRepo) Get(ctx context.Context, tenantID, identifier string) (*repository.APIResource, error) {
+	resources, err := r.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		if res.Identifier == identifier {
+			return &res, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *apiResourceRepo) List(ctx context.Context, tenantID string) ([]repository.APIResource, error) {
+	r.conn.mu.RLock()
+	defer r.conn.mu.RUnlock()
+
+	raw, err := r.listRaw(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]repository.APIResource, 0, len(raw))
+	for _, res := range raw {
+		result = append(result, r.toRepository(tenantID, res))
+	}
+	return result, nil
+}
+
+func (r *apiResourceRepo) Upsert(ctx context.Context, tenantID string, input repository.APIResourceInput) (*repository.APIResource, error) {
+	r.conn.mu.Lock()
+	defer r.conn.mu.Unlock()
+
+	resources, err := r.listRaw(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	for i, res := range resources {
+		if res.Identifier == input.Identifier {
+			resources[i].Name = input.Name
+			resources[i].Description = input.Description
+			resources[i].Scopes = input.Scopes
+			resources[i].AccessTokenTTL = input.AccessTokenTTL
+			resources[i].SigningAlg = input.SigningAlg
+			resources[i].AccessTokenProfile = input.AccessTokenProfile
+			resources[i].UpdatedAt = now
+
+			if err := r.writeResources(tenantID, resources); err != nil {
+				return nil, err
+			}
+			out := r.toRepository(tenantID, resources[i])
+			return &out, nil
+		}
+	}
+
+	res := apiResourceYAML{
+		// Derivado del identifier: todos los nodos del cluster generan el mismo ID
+		ID:             uuid.NewSHA1(uuid.NameSpaceURL, []byte(input.Identifier)).String(),
+		Identifier:     input.Identifier,
+		Name:           input.Name,
+		Description:    input.Description,
+		Scopes:         input.Scopes,
+		AccessTokenTTL: input.AccessTokenTTL,
+		SigningAlg:     input.SigningAlg,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+
+		AccessTokenProfile: input.AccessTokenProfile,
+	}
+	resources = append(resources, res)
+
+	if err := r.writeResources(tenantID, resources); err != nil {
+		return nil, err
+	}
+	out := r.toRepository(tenantID, res)
+	return &out, nil
+}
+
+func (r *apiResourceRepo) Delete(ctx context.Context, tenantID, identifier string) error {
+	r.conn.mu.Lock()
+	defer r.conn.mu.Unlock()
+
+	resources, err := r.listRaw(tenantID)
+	if err != nil {
+		return err
+	}
+
+	var filtered []apiResourceYAML
+	found := false
+	for _, res := range resources {
+		if res.Identifier == identifier {
+			found = true
+			continue
+		}
+		filtered = append(filtered, res)
+	}
+
+	if !found {
+		return repository.ErrNotFound
+	}
+
+	return r.writeResources(tenantID, filtered)
+}
+
+func (r *apiResourceRepo) listRaw(tenantID string) ([]apiResourceYAML, error) {
+	data, err := os.ReadFile(r.resourcesFile(tenantID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []apiResourceYAML{}, nil
+		}
+		return nil, err
+	}
+	var raw apiResourcesYAML
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw.Resources, nil
+}
+
+func (r *apiResourceRepo) writeResources(tenantID string, resources []apiResourceYAML) error {
+	data, err := yaml.Marshal(apiResourcesYAML{Resources: resources})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.conn.tenantPath(tenantID), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.resourcesFile(tenantID), data, 0644)
+}
+
+func (r *apiResourceRepo) toRepository(tenantID string, res apiResourceYAML) repository.APIResource {
+	out := repository.APIResource{
+		ID:             res.ID,
+		TenantID:       tenantID,
+		Identifier:     res.Identifier,
+		Name:           res.Name,
+		Description:    res.Description,
+		Scopes:         res.Scopes,
+		AccessTokenTTL: res.AccessTokenTTL,
+		SigningAlg:     res.SigningAlg,
+
+		AccessTokenProfile: res.AccessTokenProfile,
+	}
+	if t, err := time.Parse(time.RFC3339, res.CreatedAt); err == nil {
+		out.CreatedAt = t
+	}
+	if t, err := time.Parse(time.RFC3339, res.UpdatedAt); err == nil {
+		out.UpdatedAt = &t
+	}
+	return out
+}
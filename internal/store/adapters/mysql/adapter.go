@@ -128,6 +128,14 @@ func (c *mysqlConnection) Sessions() repository.SessionRepository {
 	return &sessionRepo{db: c.db}
 }
 
+func (c *mysqlConnection) LoginAttempts() repository.LoginAttemptRepository {
+	return &loginAttemptRepo{db: c.db}
+}
+
+func (c *mysqlConnection) WebAuthn() repository.WebAuthnRepository {
+	return &webAuthnRepo{db: c.db}
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Control Plane Repositories
 // El Control Plane es manejado por el adapter de FileSystem, no por MySQL.
@@ -158,6 +166,10 @@ func (c *mysqlConnection) Claims() repository.ClaimRepository {
 	return nil // Claims custom viven en FS
 }
 
+func (c *mysqlConnection) APIResources() repository.APIResourceRepository {
+	return nil // Control Plane - manejado por FS
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Migraciones
 // ─────────────────────────────────────────────────────────────────────────────
@@ -214,3 +226,5 @@ type rbacRepo struct{ db *sql.DB }
 type schemaRepo struct{ db *sql.DB }
 type emailTokenRepo struct{ db *sql.DB }
 type identityRepo struct{ db *sql.DB }
+type loginAttemptRepo struct{ db *sql.DB }
+type webAuthnRepo struct{ db *sql.DB }
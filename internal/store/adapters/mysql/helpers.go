@@ -100,6 +100,31 @@ func stringsToJSON(arr []string) []byte {
 	return data
 }
 
+// mapsToJSON convierte []map[string]any a JSON bytes.
+// Retorna "[]" si el slice es nil.
+func mapsToJSON(arr []map[string]any) []byte {
+	if arr == nil {
+		arr = []map[string]any{}
+	}
+	data, err := json.Marshal(arr)
+	if err != nil {
+		return []byte("[]")
+	}
+	return data
+}
+
+// jsonToMaps parsea un JSON array de objetos.
+func jsonToMaps(data []byte) []map[string]any {
+	if len(data) == 0 {
+		return nil
+	}
+	var result []map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
 // mapToJSON convierte map[string]any a JSON bytes.
 func mapToJSON(m map[string]any) []byte {
 	if m == nil {
@@ -191,7 +216,8 @@ func isSystemColumn(name string) bool {
 	case "id", "email", "email_verified", "status", "profile", "metadata",
 		"disabled_at", "disabled_reason", "disabled_until",
 		"created_at", "updated_at", "password_hash",
-		"name", "given_name", "family_name", "picture", "locale", "language", "source_client_id":
+		"name", "given_name", "family_name", "picture", "locale", "language", "source_client_id",
+		"phone_number", "phone_number_verified":
 		return true
 	}
 	return false
@@ -0,0 +1,115 @@
+// Package mysql implementa LoginAttemptRepository para MySQL.
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// Verificar que implementa la interfaz
+var _ repository.LoginAttemptRepository = (*loginAttemptRepo)(nil)
+
+// Get obtiene los intentos registrados para una key.
+func (r *loginAttemptRepo) Get(ctx context.Context, key string) (*repository.LoginAttempt, error) {
+	const query = `SELECT attempt_key, failures, last_failure_at, locked_until FROM login_attempt WHERE attempt_key = ?`
+	var a repository.LoginAttempt
+	var lockedUntil sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, key).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.LockedUntil = nullTimeToPtr(lockedUntil)
+	return &a, nil
+}
+
+// RegisterFailure suma un fallo dentro de una transacción (MySQL no tiene RETURNING).
+func (r *loginAttemptRepo) RegisterFailure(ctx context.Context, key string, window time.Duration) (*repository.LoginAttempt, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC()
+	a := repository.LoginAttempt{Key: key}
+	var lockedUntil sql.NullTime
+	err = tx.QueryRowContext(ctx,
+		`SELECT failures, last_failure_at, locked_until FROM login_attempt WHERE attempt_key = ? FOR UPDATE`, key,
+	).Scan(&a.Failures, &a.LastFailureAt, &lockedUntil)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	a.LockedUntil = nullTimeToPtr(lockedUntil)
+
+	// Fallos fuera de la ventana no cuentan; un bloqueo vencido tampoco se arrastra
+	expired := a.LockedUntil != nil && !now.Before(*a.LockedUntil)
+	if err == sql.ErrNoRows || expired || (window > 0 && a.LastFailureAt.Before(now.Add(-window))) {
+		a.Failures = 0
+	}
+	if expired {
+		a.LockedUntil = nil
+	}
+	a.Failures++
+	a.LastFailureAt = now
+
+	const upsert = `
+		INSERT INTO login_attempt (attempt_key, failures, last_failure_at, locked_until)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE failures = VALUES(failures), last_failure_at = VALUES(last_failure_at), locked_until = VALUES(locked_until)
+	`
+	if _, err := tx.ExecContext(ctx, upsert, key, a.Failures, a.LastFailureAt, ptrToNullTime(a.LockedUntil)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Lock bloquea la key hasta until.
+func (r *loginAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	const query = `
+		INSERT INTO login_attempt (attempt_key, failures, last_failure_at, locked_until)
+		VALUES (?, 0, NOW(6), ?)
+		ON DUPLICATE KEY UPDATE locked_until = VALUES(locked_until)
+	`
+	_, err := r.db.ExecContext(ctx, query, key, until.UTC())
+	return err
+}
+
+// Reset elimina los intentos y el bloqueo de la key.
+func (r *loginAttemptRepo) Reset(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_attempt WHERE attempt_key = ?`, key)
+	return err
+}
+
+// ListLocked lista las keys con bloqueo vigente.
+func (r *loginAttemptRepo) ListLocked(ctx context.Context) ([]repository.LoginAttempt, error) {
+	const query = `
+		SELECT attempt_key, failures, last_failure_at, locked_until
+		FROM login_attempt WHERE locked_until > ? ORDER BY locked_until
+	`
+	rows, err := r.db.QueryContext(ctx, query, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repository.LoginAttempt
+	for rows.Next() {
+		var a repository.LoginAttempt
+		var lockedUntil sql.NullTime
+		if err := rows.Scan(&a.Key, &a.Failures, &a.LastFailureAt, &lockedUntil); err != nil {
+			return nil, err
+		}
+		a.LockedUntil = nullTimeToPtr(lockedUntil)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -160,18 +160,37 @@ func (r *consentRepo) Upsert(ctx context.Context, tenantID, userID, clientID str
 	return r.Get(ctx, tenantID, userID, clientID)
 }
 
+func (r *consentRepo) UpsertDecision(ctx context.Context, tenantID, userID, clientID string, granted, denied []string, details []map[string]any) (*repository.Consent, error) {
+	now := time.Now()
+
+	consentID := uuid.New().String()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_consent (id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE scopes = VALUES(scopes), denied_scopes = VALUES(denied_scopes),
+			authorization_details = VALUES(authorization_details),
+			granted_at = VALUES(granted_at), updated_at = VALUES(updated_at), revoked_at = NULL
+	`, consentID, tenantID, userID, clientID, stringsToJSON(granted), stringsToJSON(denied), mapsToJSON(details), now, now)
+
+	if err != nil {
+		return nil, fmt.Errorf("mysql: upsert consent decision: %w", err)
+	}
+
+	return r.Get(ctx, tenantID, userID, clientID)
+}
+
 func (r *consentRepo) Get(ctx context.Context, tenantID, userID, clientID string) (*repository.Consent, error) {
 	const query = `
-		SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+		SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 		FROM user_consent WHERE user_id = ? AND client_id = ?
 	`
 	var consent repository.Consent
-	var scopesJSON []byte
+	var scopesJSON, deniedJSON, detailsJSON []byte
 	var revokedAt sql.NullTime
 
 	err := r.db.QueryRowContext(ctx, query, userID, clientID).Scan(
 		&consent.ID, &consent.TenantID, &consent.UserID, &consent.ClientID,
-		&scopesJSON, &consent.GrantedAt, &consent.UpdatedAt, &revokedAt,
+		&scopesJSON, &deniedJSON, &detailsJSON, &consent.GrantedAt, &consent.UpdatedAt, &revokedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, repository.ErrNotFound
@@ -181,6 +200,8 @@ func (r *consentRepo) Get(ctx context.Context, tenantID, userID, clientID string
 	}
 
 	consent.Scopes = jsonToStrings(scopesJSON)
+	consent.DeniedScopes = jsonToStrings(deniedJSON)
+	consent.AuthorizationDetails = jsonToMaps(detailsJSON)
 	consent.RevokedAt = nullTimeToPtr(revokedAt)
 	return &consent, nil
 }
@@ -188,10 +209,10 @@ func (r *consentRepo) Get(ctx context.Context, tenantID, userID, clientID string
 func (r *consentRepo) ListByUser(ctx context.Context, tenantID, userID string, activeOnly bool) ([]repository.Consent, error) {
 	var query string
 	if activeOnly {
-		query = `SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+		query = `SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 		         FROM user_consent WHERE user_id = ? AND revoked_at IS NULL ORDER BY granted_at DESC`
 	} else {
-		query = `SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+		query = `SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 		         FROM user_consent WHERE user_id = ? ORDER BY granted_at DESC`
 	}
 
@@ -204,12 +225,14 @@ func (r *consentRepo) ListByUser(ctx context.Context, tenantID, userID string, a
 	var consents []repository.Consent
 	for rows.Next() {
 		var c repository.Consent
-		var scopesJSON []byte
+		var scopesJSON, deniedJSON, detailsJSON []byte
 		var revokedAt sql.NullTime
-		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &scopesJSON, &c.GrantedAt, &c.UpdatedAt, &revokedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &scopesJSON, &deniedJSON, &detailsJSON, &c.GrantedAt, &c.UpdatedAt, &revokedAt); err != nil {
 			return nil, err
 		}
 		c.Scopes = jsonToStrings(scopesJSON)
+		c.DeniedScopes = jsonToStrings(deniedJSON)
+		c.AuthorizationDetails = jsonToMaps(detailsJSON)
 		c.RevokedAt = nullTimeToPtr(revokedAt)
 		consents = append(consents, c)
 	}
@@ -234,12 +257,12 @@ func (r *consentRepo) ListAll(ctx context.Context, tenantID string, limit, offse
 	var dataQuery string
 	if activeOnly {
 		dataQuery = `
-			SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+			SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 			FROM user_consent WHERE tenant_id = ? AND revoked_at IS NULL
 			ORDER BY updated_at DESC LIMIT ? OFFSET ?`
 	} else {
 		dataQuery = `
-			SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+			SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 			FROM user_consent WHERE tenant_id = ?
 			ORDER BY updated_at DESC LIMIT ? OFFSET ?`
 	}
@@ -253,12 +276,14 @@ func (r *consentRepo) ListAll(ctx context.Context, tenantID string, limit, offse
 	var consents []repository.Consent
 	for rows.Next() {
 		var c repository.Consent
-		var scopesJSON []byte
+		var scopesJSON, deniedJSON, detailsJSON []byte
 		var revokedAt sql.NullTime
-		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &scopesJSON, &c.GrantedAt, &c.UpdatedAt, &revokedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &scopesJSON, &deniedJSON, &detailsJSON, &c.GrantedAt, &c.UpdatedAt, &revokedAt); err != nil {
 			return nil, 0, err
 		}
 		c.Scopes = jsonToStrings(scopesJSON)
+		c.DeniedScopes = jsonToStrings(deniedJSON)
+		c.AuthorizationDetails = jsonToMaps(detailsJSON)
 		c.RevokedAt = nullTimeToPtr(revokedAt)
 		consents = append(consents, c)
 	}
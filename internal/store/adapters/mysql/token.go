@@ -22,12 +22,12 @@ func (r *tokenRepo) Create(ctx context.Context, input repository.CreateRefreshTo
 
 	// Usamos DATE_ADD en lugar de interval de PostgreSQL
 	const query = `
-		INSERT INTO refresh_token (id, user_id, client_id_text, token_hash, issued_at, expires_at)
-		VALUES (?, ?, ?, ?, NOW(), DATE_ADD(NOW(), INTERVAL ? SECOND))
+		INSERT INTO refresh_token (id, user_id, client_id_text, token_hash, issued_at, expires_at, dpop_jkt, rotated_from, resources, authorization_details, session_id)
+		VALUES (?, ?, ?, ?, NOW(), DATE_ADD(NOW(), INTERVAL ? SECOND), NULLIF(?, ''), NULLIF(?, ''), ?, ?, NULLIF(?, ''))
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		tokenID, input.UserID, input.ClientID, input.TokenHash, input.TTLSeconds,
+		tokenID, input.UserID, input.ClientID, input.TokenHash, input.TTLSeconds, input.DPoPJKT, input.RotatedFrom, stringsToJSON(input.Resources), mapsToJSON(input.AuthorizationDetails), input.SessionID,
 	)
 	if err != nil {
 		return "", fmt.Errorf("mysql: create refresh token: %w", err)
@@ -39,18 +39,20 @@ func (r *tokenRepo) Create(ctx context.Context, input repository.CreateRefreshTo
 // GetByHash busca un token por su hash.
 func (r *tokenRepo) GetByHash(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
 	const query = `
-		SELECT id, user_id, client_id_text, token_hash, issued_at, expires_at, rotated_from, revoked_at
+		SELECT id, user_id, client_id_text, token_hash, issued_at, expires_at, rotated_from, revoked_at,
+		       COALESCE(dpop_jkt, ''), resources, authorization_details, COALESCE(session_id, '')
 		FROM refresh_token WHERE token_hash = ?
 	`
 
 	var token repository.RefreshToken
 	var rotatedFrom sql.NullString
 	var revokedAtTime sql.NullTime
+	var resourcesJSON, detailsJSON []byte
 
 	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
 		&token.ID, &token.UserID, &token.ClientID,
 		&token.TokenHash, &token.IssuedAt, &token.ExpiresAt,
-		&rotatedFrom, &revokedAtTime,
+		&rotatedFrom, &revokedAtTime, &token.DPoPJKT, &resourcesJSON, &detailsJSON, &token.SessionID,
 	)
 	if err == sql.ErrNoRows {
 		return nil, repository.ErrNotFound
@@ -61,6 +63,8 @@ func (r *tokenRepo) GetByHash(ctx context.Context, tokenHash string) (*repositor
 
 	token.RotatedFrom = nullStringToPtr(rotatedFrom)
 	token.RevokedAt = nullTimeToPtr(revokedAtTime)
+	token.Resources = jsonToStrings(resourcesJSON)
+	token.AuthorizationDetails = jsonToMaps(detailsJSON)
 
 	return &token, nil
 }
@@ -126,6 +130,16 @@ func (r *tokenRepo) RevokeAllByUser(ctx context.Context, userID, clientID string
 	return int(rowsAffected), nil
 }
 
+// HasSuccessor indica si el token fue reemplazado por rotación.
+func (r *tokenRepo) HasSuccessor(ctx context.Context, tokenID string) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM refresh_token WHERE rotated_from = ?)`
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, tokenID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("mysql: token successor: %w", err)
+	}
+	return exists, nil
+}
+
 // RevokeAllByClient revoca todos los tokens de un cliente.
 func (r *tokenRepo) RevokeAllByClient(ctx context.Context, clientID string) error {
 	const query = `UPDATE refresh_token SET revoked_at = NOW() WHERE client_id_text = ? AND revoked_at IS NULL`
@@ -133,6 +147,55 @@ func (r *tokenRepo) RevokeAllByClient(ctx context.Context, clientID string) erro
 	return err
 }
 
+// RevokeFamily revoca la familia de rotación de un token.
+// MySQL no permite actualizar la tabla que lee el CTE, así que primero
+// resolvemos los IDs de la familia y después revocamos.
+func (r *tokenRepo) RevokeFamily(ctx context.Context, tokenID string) (int, error) {
+	const familyQuery = `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, rotated_from FROM refresh_token WHERE id = ?
+			UNION
+			SELECT t.id, t.rotated_from FROM refresh_token t JOIN ancestors a ON t.id = a.rotated_from
+		), family AS (
+			SELECT id FROM ancestors WHERE rotated_from IS NULL
+			UNION
+			SELECT t.id FROM refresh_token t JOIN family f ON t.rotated_from = f.id
+		)
+		SELECT id FROM family
+	`
+
+	rows, err := r.db.QueryContext(ctx, familyQuery, tokenID)
+	if err != nil {
+		return 0, fmt.Errorf("mysql: resolve token family: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []any
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return 0, fmt.Errorf("mysql: scan token family: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("mysql: resolve token family: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := `UPDATE refresh_token SET revoked_at = NOW() WHERE id IN (` + placeholders + `) AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, ids...)
+	if err != nil {
+		return 0, fmt.Errorf("mysql: revoke token family: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return int(rowsAffected), nil
+}
+
 // List lista tokens con filtros y paginación.
 func (r *tokenRepo) List(ctx context.Context, filter repository.ListTokensFilter) ([]repository.RefreshToken, error) {
 	// Validate pagination
@@ -10,9 +10,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	pwd "github.com/dropDatabas3/hellojohn/internal/security/password"
 )
 
 // Verificar que implementa la interfaz
@@ -26,6 +26,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, tenantID, email string) (*rep
 		       COALESCE(u.picture, ''), COALESCE(u.locale, ''), 
 		       COALESCE(u.language, ''), u.source_client_id, u.created_at, u.metadata,
 		       u.disabled_at, u.disabled_until, u.disabled_reason,
+		       COALESCE(u.phone_number, ''), u.phone_number_verified,
 		       i.id, i.provider, i.provider_user_id, i.email, 
 		       i.email_verified, i.password_hash, i.created_at
 		FROM app_user u
@@ -56,6 +57,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, tenantID, email string) (*rep
 		&user.Picture, &user.Locale, &user.Language,
 		&sourceClientID, &user.CreatedAt, &metadata,
 		&disabledAt, &disabledUntil, &disabledReason,
+		&user.PhoneNumber, &user.PhoneNumberVerified,
 		&identityID, &identityProvider, &identityProviderUID,
 		&identityEmail, &identityEmailVerified, &pwdHash, &identityCreatedAt,
 	)
@@ -220,6 +222,18 @@ func (r *userRepo) scanUserRow(rows *sql.Rows) (*repository.User, error) {
 				s := string(b)
 				user.DisabledReason = &s
 			}
+		case "phone_number":
+			if s, ok := val.(string); ok {
+				user.PhoneNumber = s
+			} else if b, ok := val.([]byte); ok {
+				user.PhoneNumber = string(b)
+			}
+		case "phone_number_verified":
+			if v, ok := val.(bool); ok {
+				user.PhoneNumberVerified = v
+			} else if v, ok := val.(int64); ok {
+				user.PhoneNumberVerified = v == 1
+			}
 		case "metadata", "profile", "status", "updated_at":
 			// Skip internal columns
 		default:
@@ -497,7 +511,7 @@ func (r *userRepo) CheckPassword(hash *string, password string) bool {
 	if hash == nil {
 		return false
 	}
-	return bcrypt.CompareHashAndPassword([]byte(*hash), []byte(password)) == nil
+	return pwd.Verify(password, *hash)
 }
 
 // SetEmailVerified marca el email como verificado.
@@ -507,6 +521,16 @@ func (r *userRepo) SetEmailVerified(ctx context.Context, userID string, verified
 	return err
 }
 
+// SetPhoneNumber guarda el teléfono (E.164) y si está verificado.
+func (r *userRepo) SetPhoneNumber(ctx context.Context, userID, phone string, verified bool) error {
+	const query = `UPDATE app_user SET phone_number = NULLIF(?, ''), phone_number_verified = ? WHERE id = ?`
+	// Sin chequeo de RowsAffected: MySQL cuenta 0 si el valor no cambió
+	if _, err := r.db.ExecContext(ctx, query, phone, verified && phone != "", userID); err != nil {
+		return fmt.Errorf("mysql: set phone number: %w", err)
+	}
+	return nil
+}
+
 // UpdatePasswordHash actualiza el hash de password en la identity.
 func (r *userRepo) UpdatePasswordHash(ctx context.Context, userID, newHash string) error {
 	const query = `UPDATE identity SET password_hash = ?, updated_at = NOW() WHERE user_id = ? AND provider = 'password'`
@@ -0,0 +1,124 @@
+// Package mysql implementa WebAuthnRepository para MySQL.
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// Verificar que implementa la interfaz
+var _ repository.WebAuthnRepository = (*webAuthnRepo)(nil)
+
+const webAuthnColumns = `id, user_id, credential_id, public_key, sign_count, aaguid, transports,
+	attestation_format, name, backup_eligible, backup_state, created_at, last_used_at`
+
+// Create registra una credencial WebAuthn.
+func (r *webAuthnRepo) Create(ctx context.Context, cred *repository.WebAuthnCredential) error {
+	const query = `
+		INSERT INTO webauthn_credential (id, user_id, credential_id, public_key, sign_count, aaguid,
+			transports, attestation_format, name, backup_eligible, backup_state)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	if cred.ID == "" {
+		cred.ID = uuid.New().String()
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		cred.ID, cred.UserID, cred.CredentialID, cred.PublicKey, int64(cred.SignCount), cred.AAGUID,
+		stringsToJSON(cred.Transports), cred.AttestationFormat, cred.Name, cred.BackupEligible, cred.BackupState,
+	)
+	if err != nil {
+		return err
+	}
+	// MySQL no tiene RETURNING: releer created_at
+	return r.db.QueryRowContext(ctx, `SELECT created_at FROM webauthn_credential WHERE id = ?`, cred.ID).Scan(&cred.CreatedAt)
+}
+
+// GetByCredentialID obtiene una credencial por su credential ID.
+func (r *webAuthnRepo) GetByCredentialID(ctx context.Context, credentialID string) (*repository.WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnColumns + ` FROM webauthn_credential WHERE credential_id = ?`
+	cred, err := scanWebAuthnCredential(r.db.QueryRowContext(ctx, query, credentialID))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	return cred, err
+}
+
+// ListByUser lista las credenciales de un usuario.
+func (r *webAuthnRepo) ListByUser(ctx context.Context, userID string) ([]repository.WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnColumns + ` FROM webauthn_credential WHERE user_id = ? ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repository.WebAuthnCredential
+	for rows.Next() {
+		cred, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *cred)
+	}
+	return out, rows.Err()
+}
+
+// UpdateUsage registra una autenticación exitosa.
+func (r *webAuthnRepo) UpdateUsage(ctx context.Context, id string, signCount uint32, backupState bool) error {
+	const query = `UPDATE webauthn_credential SET sign_count = ?, backup_state = ?, last_used_at = NOW(6) WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, int64(signCount), backupState, id)
+	return err
+}
+
+// Rename cambia el nombre visible de una credencial del usuario.
+func (r *webAuthnRepo) Rename(ctx context.Context, userID, id, name string) error {
+	// Sin CLIENT_FOUND_ROWS un rename al mismo nombre afecta 0 filas: verificar existencia aparte
+	var exists int
+	err := r.db.QueryRowContext(ctx, `SELECT 1 FROM webauthn_credential WHERE id = ? AND user_id = ?`, id, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE webauthn_credential SET name = ? WHERE id = ? AND user_id = ?`, name, id, userID)
+	return err
+}
+
+// Delete elimina una credencial del usuario.
+func (r *webAuthnRepo) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webauthn_credential WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebAuthnCredential(row rowScanner) (*repository.WebAuthnCredential, error) {
+	var cred repository.WebAuthnCredential
+	var signCount int64
+	var transports []byte
+	var lastUsed sql.NullTime
+	err := row.Scan(
+		&cred.ID, &cred.UserID, &cred.CredentialID, &cred.PublicKey, &signCount, &cred.AAGUID, &transports,
+		&cred.AttestationFormat, &cred.Name, &cred.BackupEligible, &cred.BackupState, &cred.CreatedAt, &lastUsed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	cred.SignCount = uint32(signCount)
+	cred.Transports = jsonToStrings(transports)
+	cred.LastUsedAt = nullTimeToPtr(lastUsed)
+	return &cred, nil
+}
@@ -47,9 +47,12 @@ func (c *noopConnection) Clients() repository.ClientRepository
 func (c *noopConnection) Admins() repository.AdminRepository                         { return nil }
 func (c *noopConnection) AdminRefreshTokens() repository.AdminRefreshTokenRepository { return nil }
 func (c *noopConnection) Claims() repository.ClaimRepository                         { return nil }
+func (c *noopConnection) APIResources() repository.APIResourceRepository             { return nil }
 func (c *noopConnection) EmailTokens() repository.EmailTokenRepository               { return &noopEmailTokenRepo{} }
 func (c *noopConnection) Identities() repository.IdentityRepository                  { return &noopIdentityRepo{} }
 func (c *noopConnection) Sessions() repository.SessionRepository                     { return &noopSessionRepo{} }
+func (c *noopConnection) LoginAttempts() repository.LoginAttemptRepository           { return nil } // fallback a cache
+func (c *noopConnection) WebAuthn() repository.WebAuthnRepository                    { return nil }
 
 // ─── Repos que retornan ErrNoDatabase ───
 
@@ -86,6 +89,9 @@ func (r *noopUserRepo) SetEmailVerified(ctx context.Context, userID string, veri
 func (r *noopUserRepo) UpdatePasswordHash(ctx context.Context, userID, newHash string) error {
 	return repository.ErrNoDatabase
 }
+func (r *noopUserRepo) SetPhoneNumber(ctx context.Context, userID, phone string, verified bool) error {
+	return repository.ErrNoDatabase
+}
 func (r *noopUserRepo) List(ctx context.Context, tenantID string, filter repository.ListUsersFilter) ([]repository.User, error) {
 	return nil, repository.ErrNoDatabase
 }
@@ -108,6 +114,12 @@ func (r *noopTokenRepo) RevokeAllByUser(ctx context.Context, userID, clientID st
 func (r *noopTokenRepo) RevokeAllByClient(ctx context.Context, clientID string) error {
 	return repository.ErrNoDatabase
 }
+func (r *noopTokenRepo) RevokeFamily(ctx context.Context, tokenID string) (int, error) {
+	return 0, repository.ErrNoDatabase
+}
+func (r *noopTokenRepo) HasSuccessor(ctx context.Context, tokenID string) (bool, error) {
+	return false, repository.ErrNoDatabase
+}
 func (r *noopTokenRepo) GetByID(ctx context.Context, tokenID string) (*repository.RefreshToken, error) {
 	return nil, repository.ErrNoDatabase
 }
@@ -158,6 +170,9 @@ func (r *noopMFARepo) IsTrustedDevice(ctx context.Context, userID, deviceHash st
 func (r *noopConsentRepo) Upsert(ctx context.Context, tenantID, userID, clientID string, scopes []string) (*repository.Consent, error) {
 	return nil, repository.ErrNoDatabase
 }
+func (r *noopConsentRepo) UpsertDecision(ctx context.Context, tenantID, userID, clientID string, granted, denied []string, details []map[string]any) (*repository.Consent, error) {
+	return nil, repository.ErrNoDatabase
+}
 func (r *noopConsentRepo) Get(ctx context.Context, tenantID, userID, clientID string) (*repository.Consent, error) {
 	return nil, repository.ErrNoDatabase
 }
@@ -291,6 +306,9 @@ type noopKeyRepo struct{}
 func (r *noopKeyRepo) GetActive(ctx context.Context, tenantID string) (*repository.SigningKey, error) {
 	return nil, repository.ErrNoDatabase
 }
+func (r *noopKeyRepo) GetActiveByAlgorithm(ctx context.Context, tenantID, algorithm string) (*repository.SigningKey, error) {
+	return nil, repository.ErrNoDatabase
+}
 func (r *noopKeyRepo) GetByKID(ctx context.Context, kid string) (*repository.SigningKey, error) {
 	return nil, repository.ErrNoDatabase
 }
@@ -12,9 +12,9 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+	pwd "github.com/dropDatabas3/hellojohn/internal/security/password"
 	store "github.com/dropDatabas3/hellojohn/internal/store"
 )
 
@@ -90,7 +90,8 @@ func isSystemColumn(name string) bool {
 	case "id", "email", "email_verified", "status", "profile", "metadata",
 		"disabled_at", "disabled_reason", "disabled_until",
 		"created_at", "updated_at", "password_hash",
-		"name", "given_name", "family_name", "picture", "locale", "language", "source_client_id":
+		"name", "given_name", "family_name", "picture", "locale", "language", "source_client_id",
+		"phone_number", "phone_number_verified":
 		return true
 	}
 	return false
@@ -164,6 +165,10 @@ func (c *pgConnection) EmailTokens() repository.EmailTokenRepository {
 }
 func (c *pgConnection) Identities() repository.IdentityRepository { return newIdentityRepo(c.pool) }
 func (c *pgConnection) Sessions() repository.SessionRepository    { return NewSessionRepo(c.pool) }
+func (c *pgConnection) LoginAttempts() repository.LoginAttemptRepository {
+	return newLoginAttemptRepo(c.pool)
+}
+func (c *pgConnection) WebAuthn() repository.WebAuthnRepository { return newWebAuthnRepo(c.pool) }
 
 // Control plane (no soportado por PG, viene de FS)
 func (c *pgConnection) Tenants() repository.TenantRepository                       { return nil }
@@ -172,6 +177,7 @@ func (c *pgConnection) Admins() repository.AdminRepository
 func (c *pgConnection) AdminRefreshTokens() repository.AdminRefreshTokenRepository { return nil }
 func (c *pgConnection) Keys() repository.KeyRepository                             { return nil } // Keys viven en FS
 func (c *pgConnection) Claims() repository.ClaimRepository                         { return nil } // Claims viven en FS
+func (c *pgConnection) APIResources() repository.APIResourceRepository             { return nil }
 
 // GetMigrationExecutor implementa store.MigratableConnection.
 // Retorna un wrapper del pool para migraciones.
@@ -202,7 +208,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, tenantID, email string) (*rep
 	const query = `
 		SELECT u.id, u.email, u.email_verified, COALESCE(u.name, ''), COALESCE(u.given_name, ''), COALESCE(u.family_name, ''),
 		       COALESCE(u.picture, ''), COALESCE(u.locale, ''), COALESCE(u.language, ''), u.source_client_id, u.created_at, u.metadata,
-		       u.disabled_at, u.disabled_until, u.disabled_reason,
+		       u.disabled_at, u.disabled_until, u.disabled_reason, COALESCE(u.phone_number, ''), u.phone_number_verified,
 		       i.id, i.provider, i.provider_user_id, i.email, i.email_verified, i.password_hash, i.created_at
 		FROM app_user u
 		LEFT JOIN identity i ON i.user_id = u.id AND i.provider = 'password'
@@ -222,7 +228,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, tenantID, email string) (*rep
 		&user.ID, &user.Email, &user.EmailVerified,
 		&user.Name, &user.GivenName, &user.FamilyName, &user.Picture, &user.Locale, &user.Language, &user.SourceClientID,
 		&user.CreatedAt, &metadata,
-		&user.DisabledAt, &user.DisabledUntil, &user.DisabledReason,
+		&user.DisabledAt, &user.DisabledUntil, &user.DisabledReason, &user.PhoneNumber, &user.PhoneNumberVerified,
 		&identity.ID, &identity.Provider, &identity.ProviderUserID,
 		&identity.Email, &identity.EmailVerified, &pwdHash, &identity.CreatedAt,
 	)
@@ -333,6 +339,14 @@ func (r *userRepo) scanUserRow(rows pgx.Rows) (*repository.User, error) {
 			if v, ok := val.(string); ok {
 				user.SourceClientID = &v
 			}
+		case "phone_number":
+			if v, ok := val.(string); ok {
+				user.PhoneNumber = v
+			}
+		case "phone_number_verified":
+			if v, ok := val.(bool); ok {
+				user.PhoneNumberVerified = v
+			}
 		case "created_at":
 			if v, ok := val.(time.Time); ok {
 				user.CreatedAt = v
@@ -550,7 +564,7 @@ func (r *userRepo) CheckPassword(hash *string, password string) bool {
 	if hash == nil {
 		return false
 	}
-	return bcrypt.CompareHashAndPassword([]byte(*hash), []byte(password)) == nil
+	return pwd.Verify(password, *hash)
 }
 
 func (r *userRepo) SetEmailVerified(ctx context.Context, userID string, verified bool) error {
@@ -572,6 +586,18 @@ func (r *userRepo) UpdatePasswordHash(ctx context.Context, userID, newHash strin
 	return nil
 }
 
+func (r *userRepo) SetPhoneNumber(ctx context.Context, userID, phone string, verified bool) error {
+	const query = `UPDATE app_user SET phone_number = NULLIF($2, ''), phone_number_verified = $3 WHERE id = $1`
+	tag, err := r.pool.Exec(ctx, query, userID, phone, verified && phone != "")
+	if err != nil {
+		return fmt.Errorf("pg: set phone number: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
 func (r *userRepo) List(ctx context.Context, tenantID string, filter repository.ListUsersFilter) ([]repository.User, error) {
 	// Defaults y clamp
 	limit := filter.Limit
@@ -669,27 +695,37 @@ type tokenRepo struct{ pool *pgxpool.Pool }
 func (r *tokenRepo) Create(ctx context.Context, input repository.CreateRefreshTokenInput) (string, error) {
 	// Note: tenant_id is not stored in DB since each tenant has isolated DB
 	const query = `
-		INSERT INTO refresh_token (user_id, client_id_text, token_hash, issued_at, expires_at)
-		VALUES ($1, $2, $3, NOW(), NOW() + $4::interval)
+		INSERT INTO refresh_token (user_id, client_id_text, token_hash, issued_at, expires_at, dpop_jkt, rotated_from, resources, authorization_details, session_id)
+		VALUES ($1, $2, $3, NOW(), NOW() + $4::interval, NULLIF($5, ''), NULLIF($6, '')::uuid, $7, $8, NULLIF($9, ''))
 		RETURNING id
 	`
 	ttl := fmt.Sprintf("%d seconds", input.TTLSeconds)
+	resources := input.Resources
+	if resources == nil {
+		resources = []string{}
+	}
+	details := input.AuthorizationDetails
+	if details == nil {
+		details = []map[string]any{}
+	}
 	var id string
 	err := r.pool.QueryRow(ctx, query,
-		input.UserID, input.ClientID, input.TokenHash, ttl,
+		input.UserID, input.ClientID, input.TokenHash, ttl, input.DPoPJKT, input.RotatedFrom, resources, details, input.SessionID,
 	).Scan(&id)
 	return id, err
 }
 
 func (r *tokenRepo) GetByHash(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
 	const query = `
-		SELECT id, user_id, client_id_text, token_hash, issued_at, expires_at, rotated_from, revoked_at
+		SELECT id, user_id, client_id_text, token_hash, issued_at, expires_at, rotated_from, revoked_at,
+		       COALESCE(dpop_jkt, ''), resources, authorization_details, COALESCE(session_id, '')
 		FROM refresh_token WHERE token_hash = $1
 	`
 	var token repository.RefreshToken
 	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
 		&token.ID, &token.UserID, &token.ClientID,
 		&token.TokenHash, &token.IssuedAt, &token.ExpiresAt, &token.RotatedFrom, &token.RevokedAt,
+		&token.DPoPJKT, &token.Resources, &token.AuthorizationDetails, &token.SessionID,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, repository.ErrNotFound
@@ -724,6 +760,37 @@ func (r *tokenRepo) RevokeAllByClient(ctx context.Context, clientID string) erro
 	return err
 }
 
+func (r *tokenRepo) RevokeFamily(ctx context.Context, tokenID string) (int, error) {
+	// ancestors: cadena hasta la raíz; family: raíz + todos sus descendientes
+	const query = `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, rotated_from FROM refresh_token WHERE id = $1
+			UNION
+			SELECT t.id, t.rotated_from FROM refresh_token t JOIN ancestors a ON t.id = a.rotated_from
+		), family AS (
+			SELECT id FROM ancestors WHERE rotated_from IS NULL
+			UNION
+			SELECT t.id FROM refresh_token t JOIN family f ON t.rotated_from = f.id
+		)
+		UPDATE refresh_token SET revoked_at = NOW()
+		WHERE id IN (SELECT id FROM family) AND revoked_at IS NULL
+	`
+	tag, err := r.pool.Exec(ctx, query, tokenID)
+	if err != nil {
+		return 0, fmt.Errorf("pg: revoke token family: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *tokenRepo) HasSuccessor(ctx context.Context, tokenID string) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM refresh_token WHERE rotated_from = $1)`
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query, tokenID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("pg: token successor: %w", err)
+	}
+	return exists, nil
+}
+
 // ─── Admin Token Operations ───
 
 func (r *tokenRepo) GetByID(ctx context.Context, tokenID string) (*repository.RefreshToken, error) {
@@ -0,0 +1,97 @@
+// adapters/pg/login_attempt.go — Implementación PostgreSQL de LoginAttemptRepository
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+type loginAttemptRepo struct {
+	pool *pgxpool.Pool
+}
+
+// newLoginAttemptRepo crea un repositorio de intentos de login.
+func newLoginAttemptRepo(pool *pgxpool.Pool) *loginAttemptRepo {
+	return &loginAttemptRepo{pool: pool}
+}
+
+func (r *loginAttemptRepo) Get(ctx context.Context, key string) (*repository.LoginAttempt, error) {
+	const query = `SELECT attempt_key, failures, last_failure_at, locked_until FROM login_attempt WHERE attempt_key = $1`
+	var a repository.LoginAttempt
+	err := r.pool.QueryRow(ctx, query, key).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *loginAttemptRepo) RegisterFailure(ctx context.Context, key string, window time.Duration) (*repository.LoginAttempt, error) {
+	// Fallos fuera de la ventana no cuentan; un bloqueo vencido tampoco se arrastra
+	const query = `
+		INSERT INTO login_attempt (attempt_key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE
+				WHEN $2 > 0 AND login_attempt.last_failure_at < NOW() - make_interval(secs => $2) THEN 1
+				WHEN login_attempt.locked_until IS NOT NULL AND login_attempt.locked_until <= NOW() THEN 1
+				ELSE login_attempt.failures + 1
+			END,
+			locked_until = CASE
+				WHEN login_attempt.locked_until IS NOT NULL AND login_attempt.locked_until <= NOW() THEN NULL
+				ELSE login_attempt.locked_until
+			END,
+			last_failure_at = NOW()
+		RETURNING attempt_key, failures, last_failure_at, locked_until
+	`
+	var a repository.LoginAttempt
+	err := r.pool.QueryRow(ctx, query, key, window.Seconds()).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *loginAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	const query = `
+		INSERT INTO login_attempt (attempt_key, failures, last_failure_at, locked_until)
+		VALUES ($1, 0, NOW(), $2)
+		ON CONFLICT (attempt_key) DO UPDATE SET locked_until = $2
+	`
+	_, err := r.pool.Exec(ctx, query, key, until)
+	return err
+}
+
+func (r *loginAttemptRepo) Reset(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM login_attempt WHERE attempt_key = $1`, key)
+	return err
+}
+
+func (r *loginAttemptRepo) ListLocked(ctx context.Context) ([]repository.LoginAttempt, error) {
+	const query = `
+		SELECT attempt_key, failures, last_failure_at, locked_until
+		FROM login_attempt WHERE locked_until > NOW() ORDER BY locked_until
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repository.LoginAttempt
+	for rows.Next() {
+		var a repository.LoginAttempt
+		if err := rows.Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -147,15 +147,43 @@ func (r *consentRepo) Upsert(ctx context.Context, tenantID, userID, clientID str
 	return consent, err
 }
 
+func (r *consentRepo) UpsertDecision(ctx context.Context, tenantID, userID, clientID string, granted, denied []string, details []map[string]any) (*repository.Consent, error) {
+	const query = `
+		INSERT INTO user_consent (tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (user_id, client_id) DO UPDATE
+		SET scopes = $4, denied_scopes = $5, authorization_details = $6, granted_at = NOW(), updated_at = NOW(), revoked_at = NULL
+		RETURNING id, granted_at, updated_at
+	`
+	if denied == nil {
+		denied = []string{}
+	}
+	if details == nil {
+		details = []map[string]any{}
+	}
+	consent := &repository.Consent{
+		TenantID:             tenantID,
+		UserID:               userID,
+		ClientID:             clientID,
+		Scopes:               granted,
+		DeniedScopes:         denied,
+		AuthorizationDetails: details,
+	}
+	err := r.pool.QueryRow(ctx, query, tenantID, userID, clientID, granted, denied, details).Scan(
+		&consent.ID, &consent.GrantedAt, &consent.UpdatedAt,
+	)
+	return consent, err
+}
+
 func (r *consentRepo) Get(ctx context.Context, tenantID, userID, clientID string) (*repository.Consent, error) {
 	const query = `
-		SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+		SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 		FROM user_consent WHERE user_id = $1 AND client_id = $2
 	`
 	var consent repository.Consent
 	err := r.pool.QueryRow(ctx, query, userID, clientID).Scan(
 		&consent.ID, &consent.TenantID, &consent.UserID, &consent.ClientID,
-		&consent.Scopes, &consent.GrantedAt, &consent.UpdatedAt, &consent.RevokedAt,
+		&consent.Scopes, &consent.DeniedScopes, &consent.AuthorizationDetails, &consent.GrantedAt, &consent.UpdatedAt, &consent.RevokedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, repository.ErrNotFound
@@ -166,10 +194,10 @@ func (r *consentRepo) Get(ctx context.Context, tenantID, userID, clientID string
 func (r *consentRepo) ListByUser(ctx context.Context, tenantID, userID string, activeOnly bool) ([]repository.Consent, error) {
 	var query string
 	if activeOnly {
-		query = `SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+		query = `SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 		         FROM user_consent WHERE user_id = $1 AND revoked_at IS NULL ORDER BY granted_at DESC`
 	} else {
-		query = `SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+		query = `SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 		         FROM user_consent WHERE user_id = $1 ORDER BY granted_at DESC`
 	}
 
@@ -182,7 +210,7 @@ func (r *consentRepo) ListByUser(ctx context.Context, tenantID, userID string, a
 	var consents []repository.Consent
 	for rows.Next() {
 		var c repository.Consent
-		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &c.Scopes, &c.GrantedAt, &c.UpdatedAt, &c.RevokedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &c.Scopes, &c.DeniedScopes, &c.AuthorizationDetails, &c.GrantedAt, &c.UpdatedAt, &c.RevokedAt); err != nil {
 			return nil, err
 		}
 		consents = append(consents, c)
@@ -214,7 +242,7 @@ func (r *consentRepo) ListAll(ctx context.Context, tenantID string, limit, offse
 	var dataQuery string
 	if activeOnly {
 		dataQuery = `
-			SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+			SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 			FROM user_consent
 			WHERE tenant_id = $1 AND revoked_at IS NULL
 			ORDER BY updated_at DESC
@@ -222,7 +250,7 @@ func (r *consentRepo) ListAll(ctx context.Context, tenantID string, limit, offse
 		`
 	} else {
 		dataQuery = `
-			SELECT id, tenant_id, user_id, client_id, scopes, granted_at, updated_at, revoked_at
+			SELECT id, tenant_id, user_id, client_id, scopes, denied_scopes, authorization_details, granted_at, updated_at, revoked_at
 			FROM user_consent
 			WHERE tenant_id = $1
 			ORDER BY updated_at DESC
@@ -239,7 +267,7 @@ func (r *consentRepo) ListAll(ctx context.Context, tenantID string, limit, offse
 	var consents []repository.Consent
 	for rows.Next() {
 		var c repository.Consent
-		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &c.Scopes, &c.GrantedAt, &c.UpdatedAt, &c.RevokedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.UserID, &c.ClientID, &c.Scopes, &c.DeniedScopes, &c.AuthorizationDetails, &c.GrantedAt, &c.UpdatedAt, &c.RevokedAt); err != nil {
 			return nil, 0, err
 		}
 		consents = append(consents, c)
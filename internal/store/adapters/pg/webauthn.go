@@ -0,0 +1,117 @@
+// adapters/pg/webauthn.go — Implementación PostgreSQL de WebAuthnRepository
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+type webAuthnRepo struct {
+	pool *pgxpool.Pool
+}
+
+// newWebAuthnRepo crea un repositorio de credenciales WebAuthn.
+func newWebAuthnRepo(pool *pgxpool.Pool) *webAuthnRepo {
+	return &webAuthnRepo{pool: pool}
+}
+
+const webAuthnColumns = `id, user_id, credential_id, public_key, sign_count, aaguid, transports,
+	attestation_format, name, backup_eligible, backup_state, created_at, last_used_at`
+
+func (r *webAuthnRepo) Create(ctx context.Context, cred *repository.WebAuthnCredential) error {
+	const query = `
+		INSERT INTO webauthn_credential (id, user_id, credential_id, public_key, sign_count, aaguid,
+			transports, attestation_format, name, backup_eligible, backup_state)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`
+	if cred.ID == "" {
+		cred.ID = uuid.NewString()
+	}
+	transports := cred.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	return r.pool.QueryRow(ctx, query,
+		cred.ID, cred.UserID, cred.CredentialID, cred.PublicKey, int64(cred.SignCount), cred.AAGUID,
+		transports, cred.AttestationFormat, cred.Name, cred.BackupEligible, cred.BackupState,
+	).Scan(&cred.CreatedAt)
+}
+
+func (r *webAuthnRepo) GetByCredentialID(ctx context.Context, credentialID string) (*repository.WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnColumns + ` FROM webauthn_credential WHERE credential_id = $1`
+	cred, err := scanWebAuthnCredential(r.pool.QueryRow(ctx, query, credentialID))
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	return cred, err
+}
+
+func (r *webAuthnRepo) ListByUser(ctx context.Context, userID string) ([]repository.WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnColumns + ` FROM webauthn_credential WHERE user_id = $1 ORDER BY created_at DESC`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repository.WebAuthnCredential
+	for rows.Next() {
+		cred, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *cred)
+	}
+	return out, rows.Err()
+}
+
+func (r *webAuthnRepo) UpdateUsage(ctx context.Context, id string, signCount uint32, backupState bool) error {
+	const query = `UPDATE webauthn_credential SET sign_count = $2, backup_state = $3, last_used_at = NOW() WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id, int64(signCount), backupState)
+	return err
+}
+
+func (r *webAuthnRepo) Rename(ctx context.Context, userID, id, name string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE webauthn_credential SET name = $3 WHERE id = $1 AND user_id = $2`, id, userID, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *webAuthnRepo) Delete(ctx context.Context, userID, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM webauthn_credential WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanWebAuthnCredential(row pgx.Row) (*repository.WebAuthnCredential, error) {
+	var cred repository.WebAuthnCredential
+	var signCount int64
+	var lastUsed *time.Time
+	err := row.Scan(
+		&cred.ID, &cred.UserID, &cred.CredentialID, &cred.PublicKey, &signCount, &cred.AAGUID, &cred.Transports,
+		&cred.AttestationFormat, &cred.Name, &cred.BackupEligible, &cred.BackupState, &cred.CreatedAt, &lastUsed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	cred.SignCount = uint32(signCount)
+	cred.LastUsedAt = lastUsed
+	return &cred, nil
+}
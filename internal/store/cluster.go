@@ -90,6 +90,18 @@ func NewScopeMutation(mutType repository.MutationType, tenantID, scopeName strin
 	}
 }
 
+// NewResourceMutation crea una mutación para operaciones de API resource.
+func NewResourceMutation(mutType repository.MutationType, tenantID, identifier string, payload any) repository.Mutation {
+	data, _ := json.Marshal(payload)
+	return repository.Mutation{
+		Type:      mutType,
+		TenantID:  tenantID,
+		Key:       identifier,
+		Payload:   data,
+		Timestamp: time.Now(),
+	}
+}
+
 // NewTenantMutation crea una mutación para operaciones de tenant.
 func NewTenantMutation(mutType repository.MutationType, tenantID string, payload any) repository.Mutation {
 	data, _ := json.Marshal(payload)
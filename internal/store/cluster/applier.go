@@ -22,6 +22,7 @@ type ConfigAccessor interface {
 	Clients(tenantSlug string) repository.ClientRepository
 	Scopes(tenantSlug string) repository.ScopeRepository
 	Claims(tenantSlug string) repository.ClaimRepository
+	APIResources(tenantSlug string) repository.APIResourceRepository
 }
 
 // V2Applier implementa Applier usando Store V2.
@@ -55,6 +56,10 @@ func (a *V2Applier) Apply(m Mutation) error {
 		return a.upsertScope(ctx, m)
 	case MutationScopeDelete:
 		return a.deleteScope(ctx, m)
+	case MutationResourceUpsert:
+		return a.upsertResource(ctx, m)
+	case MutationResourceDelete:
+		return a.deleteResource(ctx, m)
 	case MutationKeyRotate:
 		return a.rotateKey(m)
 	default:
@@ -86,6 +91,37 @@ func (a *V2Applier) upsertClient(ctx context.Context, m Mutation) error {
 		RequireEmailVerification: p.RequireEmailVerification,
 		ResetPasswordURL:         p.ResetPasswordURL,
 		VerifyEmailURL:           p.VerifyEmailURL,
+		PostLogoutURIs:           p.PostLogoutURIs,
+		GrantTypes:               p.GrantTypes,
+		TokenExchange:            p.TokenExchange,
+		TokenEndpointAuthMethod:  p.TokenEndpointAuthMethod,
+		JWKS:                     p.JWKS,
+		JWKSURI:                  p.JWKSURI,
+		TLSClientAuthSubjectDN:   p.TLSClientAuthSubjectDN,
+		TLSClientCertThumbprint:  p.TLSClientCertThumbprint,
+		RequirePAR:               p.RequirePAR,
+		IDTokenSignedResponseAlg: p.IDTokenSignedResponseAlg,
+		FirstParty:               p.FirstParty,
+
+		BackchannelLogoutURI:              p.BackchannelLogoutURI,
+		BackchannelLogoutSessionRequired:  p.BackchannelLogoutSessionRequired,
+		FrontchannelLogoutURI:             p.FrontchannelLogoutURI,
+		FrontchannelLogoutSessionRequired: p.FrontchannelLogoutSessionRequired,
+
+		SubjectType:         p.SubjectType,
+		SectorIdentifierURI: p.SectorIdentifierURI,
+
+		RequestObjectSigningAlg:        p.RequestObjectSigningAlg,
+		RequireSignedRequestObject:     p.RequireSignedRequestObject,
+		AuthorizationSignedResponseAlg: p.AuthorizationSignedResponseAlg,
+
+		IDTokenEncryptedResponseAlg:  p.IDTokenEncryptedResponseAlg,
+		IDTokenEncryptedResponseEnc:  p.IDTokenEncryptedResponseEnc,
+		UserinfoSignedResponseAlg:    p.UserinfoSignedResponseAlg,
+		UserinfoEncryptedResponseAlg: p.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: p.UserinfoEncryptedResponseEnc,
+
+		RegistrationAccessTokenHash: p.RegistrationAccessTokenHash,
 	}
 
 	// Intentar Get para determinar create vs update
@@ -225,6 +261,49 @@ func (a *V2Applier) deleteScope(ctx context.Context, m Mutation) error {
 	return err
 }
 
+func (a *V2Applier) upsertResource(ctx context.Context, m Mutation) error {
+	var p APIResourcePayload
+	if err := json.Unmarshal(m.Payload, &p); err != nil {
+		return fmt.Errorf("unmarshal resource payload: %w", err)
+	}
+
+	resourceRepo := a.config.APIResources(m.TenantSlug)
+	if resourceRepo == nil {
+		return nil
+	}
+
+	input := repository.APIResourceInput{
+		Identifier:     p.Identifier,
+		Name:           p.Name,
+		Description:    p.Description,
+		Scopes:         p.Scopes,
+		AccessTokenTTL: p.AccessTokenTTL,
+		SigningAlg:     p.SigningAlg,
+
+		AccessTokenProfile: p.AccessTokenProfile,
+	}
+	_, err := resourceRepo.Upsert(ctx, m.TenantSlug, input)
+	return err
+}
+
+func (a *V2Applier) deleteResource(ctx context.Context, m Mutation) error {
+	var p DeletePayload
+	if err := json.Unmarshal(m.Payload, &p); err != nil {
+		return fmt.Errorf("unmarshal delete payload: %w", err)
+	}
+
+	resourceRepo := a.config.APIResources(m.TenantSlug)
+	if resourceRepo == nil {
+		return nil
+	}
+
+	err := resourceRepo.Delete(ctx, m.TenantSlug, p.ID)
+	if err == repository.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
 // rotateKey escribe los blobs JSON directamente al FS.
 func (a *V2Applier) rotateKey(m Mutation) error {
 	if a.fsRoot == "" {
@@ -28,6 +28,10 @@ const (
 	MutationScopeCreate MutationType = "scope.create"
 	MutationScopeDelete MutationType = "scope.delete"
 
+	// API Resources (RFC 8707)
+	MutationResourceUpsert MutationType = "resource.upsert"
+	MutationResourceDelete MutationType = "resource.delete"
+
 	// Keys
 	MutationKeyRotate MutationType = "key.rotate"
 )
@@ -80,6 +84,39 @@ type ClientPayload struct {
 	RequireEmailVerification bool     `json:"requireEmailVerification,omitempty"`
 	ResetPasswordURL         string   `json:"resetPasswordUrl,omitempty"`
 	VerifyEmailURL           string   `json:"verifyEmailUrl,omitempty"`
+	PostLogoutURIs           []string `json:"postLogoutUris,omitempty"`
+	GrantTypes               []string `json:"grantTypes,omitempty"`
+
+	TokenExchange *repository.TokenExchangePolicy `json:"tokenExchange,omitempty"`
+
+	TokenEndpointAuthMethod  string `json:"tokenEndpointAuthMethod,omitempty"`
+	JWKS                     string `json:"jwks,omitempty"`
+	JWKSURI                  string `json:"jwksUri,omitempty"`
+	TLSClientAuthSubjectDN   string `json:"tlsClientAuthSubjectDn,omitempty"`
+	TLSClientCertThumbprint  string `json:"tlsClientCertThumbprint,omitempty"`
+	RequirePAR               bool   `json:"requirePar,omitempty"`
+	IDTokenSignedResponseAlg string `json:"idTokenSignedResponseAlg,omitempty"`
+	FirstParty               bool   `json:"firstParty,omitempty"`
+
+	BackchannelLogoutURI              string `json:"backchannelLogoutUri,omitempty"`
+	BackchannelLogoutSessionRequired  bool   `json:"backchannelLogoutSessionRequired,omitempty"`
+	FrontchannelLogoutURI             string `json:"frontchannelLogoutUri,omitempty"`
+	FrontchannelLogoutSessionRequired bool   `json:"frontchannelLogoutSessionRequired,omitempty"`
+
+	SubjectType         string `json:"subjectType,omitempty"`
+	SectorIdentifierURI string `json:"sectorIdentifierUri,omitempty"`
+
+	RequestObjectSigningAlg        string `json:"requestObjectSigningAlg,omitempty"`
+	RequireSignedRequestObject     bool   `json:"requireSignedRequestObject,omitempty"`
+	AuthorizationSignedResponseAlg string `json:"authorizationSignedResponseAlg,omitempty"`
+
+	IDTokenEncryptedResponseAlg  string `json:"idTokenEncryptedResponseAlg,omitempty"`
+	IDTokenEncryptedResponseEnc  string `json:"idTokenEncryptedResponseEnc,omitempty"`
+	UserinfoSignedResponseAlg    string `json:"userinfoSignedResponseAlg,omitempty"`
+	UserinfoEncryptedResponseAlg string `json:"userinfoEncryptedResponseAlg,omitempty"`
+	UserinfoEncryptedResponseEnc string `json:"userinfoEncryptedResponseEnc,omitempty"`
+
+	RegistrationAccessTokenHash string `json:"registrationAccessTokenHash,omitempty"`
 }
 
 // DeletePayload para delete genérico (clientID, scopeName, etc).
@@ -112,6 +149,18 @@ type ScopePayload struct {
 	System      bool     `json:"system,omitempty"`
 }
 
+// APIResourcePayload para upsert de un API resource.
+type APIResourcePayload struct {
+	Identifier     string   `json:"identifier"`
+	Name           string   `json:"name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
+	AccessTokenTTL int      `json:"access_token_ttl,omitempty"`
+	SigningAlg     string   `json:"signing_alg,omitempty"`
+
+	AccessTokenProfile string `json:"access_token_profile,omitempty"`
+}
+
 // KeyRotatePayload para replicar rotación de keys.
 // Los JSONs de active.json y retiring.json ya vienen pre-generados por leader.
 type KeyRotatePayload struct {
@@ -392,6 +392,10 @@ func (c *factoryConfigAccess) AdminRefreshTokens() repository.AdminRefreshTokenR
 	return c.fs.AdminRefreshTokens()
 }
 
+func (c *factoryConfigAccess) APIResources(tenantSlug string) repository.APIResourceRepository {
+	return c.fs.APIResources()
+}
+
 // ─── TenantDataAccess Implementation ───
 
 type tenantAccess struct {
@@ -482,6 +486,24 @@ func (t *tenantAccess) Sessions() repository.SessionRepository {
 	return t.dataConn.Sessions()
 }
 
+// LoginAttempts usa la DB del tenant si el adapter lo soporta; si no, el
+// conteo vive en el cache del tenant para que el lockout aplique en todo modo.
+func (t *tenantAccess) LoginAttempts() repository.LoginAttemptRepository {
+	if t.dataConn != nil {
+		if repo := t.dataConn.LoginAttempts(); repo != nil {
+			return repo
+		}
+	}
+	return newCacheLoginAttemptRepo(t.cache)
+}
+
+func (t *tenantAccess) WebAuthn() repository.WebAuthnRepository {
+	if t.dataConn == nil {
+		return nil
+	}
+	return t.dataConn.WebAuthn()
+}
+
 // Config repos (desde fsConn - control plane)
 func (t *tenantAccess) Clients() repository.ClientRepository {
 	return t.fsConn.Clients()
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+// ─── LoginAttemptRepository sobre cache ───
+
+const (
+	loginAttemptPrefix    = "login_attempt:"
+	loginAttemptFailures  = ":failures"
+	loginAttemptLockedKey = "login_attempt_locked" // índice de keys bloqueadas (para ListLocked)
+
+	// loginAttemptIdleTTL expira los contadores sin ventana configurada.
+	loginAttemptIdleTTL = 24 * time.Hour
+)
+
+// cacheLoginAttemptRepo implementa repository.LoginAttemptRepository sobre el
+// cache del tenant, para tenants sin DB o adapters sin tabla login_attempt.
+// Los fallos se cuentan con un contador atómico (cache.Incr) cuya ventana corre
+// desde el primer fallo; el bloqueo vive en otra key, así un fallo concurrente
+// no lo pisa.
+type cacheLoginAttemptRepo struct {
+	client cache.Client
+}
+
+func newCacheLoginAttemptRepo(client cache.Client) repository.LoginAttemptRepository {
+	if client == nil {
+		return nil
+	}
+	return &cacheLoginAttemptRepo{client: client}
+}
+
+func (r *cacheLoginAttemptRepo) Get(ctx context.Context, key string) (*repository.LoginAttempt, error) {
+	a, err := r.getLock(ctx, key)
+	if err != nil && !repository.IsNotFound(err) {
+		return nil, err
+	}
+	n, err := r.failures(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		if n == 0 {
+			return nil, repository.ErrNotFound
+		}
+		a = &repository.LoginAttempt{Key: key}
+	}
+	a.Failures += n
+	return a, nil
+}
+
+func (r *cacheLoginAttemptRepo) RegisterFailure(ctx context.Context, key string, window time.Duration) (*repository.LoginAttempt, error) {
+	ttl := window
+	if ttl <= 0 {
+		ttl = loginAttemptIdleTTL
+	}
+	n, err := r.client.Incr(ctx, loginAttemptPrefix+key+loginAttemptFailures, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.LoginAttempt{Key: key, Failures: int(n), LastFailureAt: time.Now().UTC()}, nil
+}
+
+// Lock guarda el bloqueo con los fallos acumulados y reinicia el contador: al
+// vencer el bloqueo, los fallos previos no se arrastran.
+func (r *cacheLoginAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	n, err := r.failures(ctx, key)
+	if err != nil {
+		return err
+	}
+	until = until.UTC()
+	a := repository.LoginAttempt{Key: key, Failures: n, LastFailureAt: time.Now().UTC(), LockedUntil: &until}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Set(ctx, loginAttemptPrefix+key, string(b), time.Until(until)); err != nil {
+		return err
+	}
+	if err := r.client.Delete(ctx, loginAttemptPrefix+key+loginAttemptFailures); err != nil && !cache.IsNotFound(err) {
+		return err
+	}
+
+	keys := r.lockedKeys(ctx)
+	for _, k := range keys {
+		if k == key {
+			return nil
+		}
+	}
+	return r.setLockedKeys(ctx, append(keys, key))
+}
+
+func (r *cacheLoginAttemptRepo) Reset(ctx context.Context, key string) error {
+	for _, k := range []string{loginAttemptPrefix + key, loginAttemptPrefix + key + loginAttemptFailures} {
+		if err := r.client.Delete(ctx, k); err != nil && !cache.IsNotFound(err) {
+			return err
+		}
+	}
+	keys := r.lockedKeys(ctx)
+	out := keys[:0]
+	for _, k := range keys {
+		if k != key {
+			out = append(out, k)
+		}
+	}
+	return r.setLockedKeys(ctx, out)
+}
+
+func (r *cacheLoginAttemptRepo) ListLocked(ctx context.Context) ([]repository.LoginAttempt, error) {
+	now := time.Now()
+	var locked []repository.LoginAttempt
+	var live []string
+	for _, k := range r.lockedKeys(ctx) {
+		a, err := r.Get(ctx, k)
+		if err != nil || !a.IsLocked(now) {
+			continue
+		}
+		locked = append(locked, *a)
+		live = append(live, k)
+	}
+	_ = r.setLockedKeys(ctx, live) // poda bloqueos vencidos del índice
+	sort.Slice(locked, func(i, j int) bool { return locked[i].LockedUntil.Before(*locked[j].LockedUntil) })
+	return locked, nil
+}
+
+// getLock lee el bloqueo guardado por Lock.
+func (r *cacheLoginAttemptRepo) getLock(ctx context.Context, key string) (*repository.LoginAttempt, error) {
+	raw, err := r.client.Get(ctx, loginAttemptPrefix+key)
+	if err != nil {
+		if cache.IsNotFound(err) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	var a repository.LoginAttempt
+	if err := json.Unmarshal([]byte(raw), &a); err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return &a, nil
+}
+
+// failures lee el contador de fallos desde el último bloqueo (0 si no hay).
+func (r *cacheLoginAttemptRepo) failures(ctx context.Context, key string) (int, error) {
+	raw, err := r.client.Get(ctx, loginAttemptPrefix+key+loginAttemptFailures)
+	if err != nil {
+		if cache.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	n, _ := strconv.Atoi(raw)
+	return n, nil
+}
+
+func (r *cacheLoginAttemptRepo) lockedKeys(ctx context.Context) []string {
+	raw, err := r.client.Get(ctx, loginAttemptLockedKey)
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	_ = json.Unmarshal([]byte(raw), &keys)
+	return keys
+}
+
+func (r *cacheLoginAttemptRepo) setLockedKeys(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		if err := r.client.Delete(ctx, loginAttemptLockedKey); err != nil && !cache.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, loginAttemptLockedKey, string(b), 0)
+}
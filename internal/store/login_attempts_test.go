@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dropDatabas3/hellojohn/internal/cache"
+	"github.com/dropDatabas3/hellojohn/internal/domain/repository"
+)
+
+func newTestLoginAttempts(t *testing.T) repository.LoginAttemptRepository {
+	t.Helper()
+	repo := newCacheLoginAttemptRepo(cache.NewMemory("test"))
+	if repo == nil {
+		t.Fatal("repo nil con cache")
+	}
+	return repo
+}
+
+func TestCacheLoginAttemptRepo_NilCache(t *testing.T) {
+	if repo := newCacheLoginAttemptRepo(nil); repo != nil {
+		t.Fatalf("sin cache el repo debe ser nil, got %T", repo)
+	}
+}
+
+func TestCacheLoginAttemptRepo_RegisterFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestLoginAttempts(t)
+
+	if _, err := repo.Get(ctx, "user:u1"); !repository.IsNotFound(err) {
+		t.Fatalf("key sin fallos: err = %v, want ErrNotFound", err)
+	}
+	for i := 1; i <= 3; i++ {
+		a, err := repo.RegisterFailure(ctx, "user:u1", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Failures != i {
+			t.Fatalf("fallo %d: Failures = %d", i, a.Failures)
+		}
+	}
+	a, err := repo.Get(ctx, "user:u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Failures != 3 || a.IsLocked(time.Now()) {
+		t.Fatalf("Get = %+v, want 3 fallos sin bloqueo", a)
+	}
+}
+
+// Los fallos concurrentes no se pierden: el contador es atómico.
+func TestCacheLoginAttemptRepo_ConcurrentFailures(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestLoginAttempts(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = repo.RegisterFailure(ctx, "identifier:a@b.c", time.Minute)
+		}()
+	}
+	wg.Wait()
+
+	a, err := repo.Get(ctx, "identifier:a@b.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Failures != 20 {
+		t.Fatalf("Failures = %d, want 20", a.Failures)
+	}
+}
+
+func TestCacheLoginAttemptRepo_LockAndReset(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestLoginAttempts(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.RegisterFailure(ctx, "user:u1", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	until := time.Now().Add(10 * time.Minute)
+	if err := repo.Lock(ctx, "user:u1", until); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := repo.Get(ctx, "user:u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsLocked(time.Now()) || a.Failures != 3 {
+		t.Fatalf("Get = %+v, want bloqueada con 3 fallos", a)
+	}
+	if !a.LockedUntil.Equal(until) {
+		t.Fatalf("LockedUntil = %v, want %v", a.LockedUntil, until)
+	}
+
+	// El bloqueo reinicia el contador: el siguiente fallo cuenta desde 1
+	next, err := repo.RegisterFailure(ctx, "user:u1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Failures != 1 {
+		t.Fatalf("fallo tras Lock: Failures = %d, want 1", next.Failures)
+	}
+
+	if err := repo.Reset(ctx, "user:u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(ctx, "user:u1"); !repository.IsNotFound(err) {
+		t.Fatalf("tras Reset: err = %v, want ErrNotFound", err)
+	}
+	locked, err := repo.ListLocked(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) != 0 {
+		t.Fatalf("ListLocked tras Reset = %+v", locked)
+	}
+}
+
+func TestCacheLoginAttemptRepo_ListLocked(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestLoginAttempts(t)
+
+	now := time.Now()
+	if err := repo.Lock(ctx, "user:late", now.Add(20*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Lock(ctx, "identifier:early@b.c", now.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	// Bloquear dos veces no duplica la key en el índice
+	if err := repo.Lock(ctx, "user:late", now.Add(20*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	locked, err := repo.ListLocked(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) != 2 {
+		t.Fatalf("ListLocked = %+v, want 2", locked)
+	}
+	if locked[0].Key != "identifier:early@b.c" || locked[1].Key != "user:late" {
+		t.Fatalf("orden = [%s %s], want por vencimiento", locked[0].Key, locked[1].Key)
+	}
+}
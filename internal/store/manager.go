@@ -58,6 +58,12 @@ type TenantDataAccess interface {
 	Identities() repository.IdentityRepository
 	Sessions() repository.SessionRepository
 
+	// Intentos de login (DB o, sin DB, fallback en cache)
+	LoginAttempts() repository.LoginAttemptRepository
+
+	// Credenciales WebAuthn / passkeys (nil sin DB)
+	WebAuthn() repository.WebAuthnRepository
+
 	// Control plane (siempre disponibles vía FS)
 	Clients() repository.ClientRepository
 	Scopes() repository.ScopeRepository
@@ -84,6 +90,7 @@ type ConfigAccess interface {
 	Keys() repository.KeyRepository
 	Admins() repository.AdminRepository
 	AdminRefreshTokens() repository.AdminRefreshTokenRepository
+	APIResources(tenantSlug string) repository.APIResourceRepository
 }
 
 // MailSender interface para envío de emails.
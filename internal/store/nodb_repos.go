@@ -48,6 +48,9 @@ func (r *noDBUserRepo) SetEmailVerified(ctx context.Context, userID string, veri
 func (r *noDBUserRepo) UpdatePasswordHash(ctx context.Context, userID, newHash string) error {
 	return ErrNoDBForTenant
 }
+func (r *noDBUserRepo) SetPhoneNumber(ctx context.Context, userID, phone string, verified bool) error {
+	return ErrNoDBForTenant
+}
 
 // ─── TokenRepository (no-DB) ───
 
@@ -71,6 +74,12 @@ func (r *noDBTokenRepo) RevokeAllByUser(ctx context.Context, userID, clientID st
 func (r *noDBTokenRepo) RevokeAllByClient(ctx context.Context, clientID string) error {
 	return ErrNoDBForTenant
 }
+func (r *noDBTokenRepo) RevokeFamily(ctx context.Context, tokenID string) (int, error) {
+	return 0, ErrNoDBForTenant
+}
+func (r *noDBTokenRepo) HasSuccessor(ctx context.Context, tokenID string) (bool, error) {
+	return false, ErrNoDBForTenant
+}
 func (r *noDBTokenRepo) List(ctx context.Context, filter repository.ListTokensFilter) ([]repository.RefreshToken, error) {
 	return nil, ErrNoDBForTenant
 }
@@ -126,6 +135,9 @@ type noDBConsentRepo struct{}
 func (r *noDBConsentRepo) Upsert(ctx context.Context, tenantID, userID, clientID string, scopes []string) (*repository.Consent, error) {
 	return nil, ErrNoDBForTenant
 }
+func (r *noDBConsentRepo) UpsertDecision(ctx context.Context, tenantID, userID, clientID string, granted, denied []string, details []map[string]any) (*repository.Consent, error) {
+	return nil, ErrNoDBForTenant
+}
 func (r *noDBConsentRepo) Get(ctx context.Context, tenantID, userID, clientID string) (*repository.Consent, error) {
 	return nil, ErrNoDBForTenant
 }
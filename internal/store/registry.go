@@ -42,6 +42,8 @@ type AdapterConnection interface {
 	EmailTokens() repository.EmailTokenRepository
 	Identities() repository.IdentityRepository
 	Sessions() repository.SessionRepository
+	LoginAttempts() repository.LoginAttemptRepository
+	WebAuthn() repository.WebAuthnRepository
 	Keys() repository.KeyRepository
 
 	// ─── Control Plane (solo para adapter fs) ───
@@ -51,6 +53,7 @@ type AdapterConnection interface {
 	Admins() repository.AdminRepository
 	AdminRefreshTokens() repository.AdminRefreshTokenRepository
 	Claims() repository.ClaimRepository
+	APIResources() repository.APIResourceRepository
 }
 
 // MigratableConnection interfaz opcional para conexiones que pueden ejecutar migraciones.
@@ -0,0 +1,5 @@
+-- Rollback: Remove DPoP binding from refresh_token (MySQL)
+
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS dpop_jkt;
+
+DELETE FROM schema_migrations WHERE version = '0005_refresh_token_dpop';
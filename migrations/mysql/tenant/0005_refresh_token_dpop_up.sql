@@ -0,0 +1,26 @@
+-- Migration: Bind refresh tokens to a DPoP key (RFC 9449) (MySQL)
+-- Applied to each tenant's isolated database.
+
+-- Add dpop_jkt column if it doesn't exist
+-- MySQL doesn't have ADD COLUMN IF NOT EXISTS, use stored procedure
+DELIMITER //
+CREATE PROCEDURE add_refresh_token_dpop_column()
+BEGIN
+    DECLARE col_exists INT DEFAULT 0;
+    SELECT COUNT(*) INTO col_exists
+    FROM information_schema.columns
+    WHERE table_schema = DATABASE()
+      AND table_name = 'refresh_token'
+      AND column_name = 'dpop_jkt';
+    
+    IF col_exists = 0 THEN
+        ALTER TABLE refresh_token ADD COLUMN dpop_jkt VARCHAR(64) NULL;
+    END IF;
+END //
+DELIMITER ;
+
+CALL add_refresh_token_dpop_column();
+DROP PROCEDURE IF EXISTS add_refresh_token_dpop_column;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0005_refresh_token_dpop', NOW());
@@ -0,0 +1,5 @@
+-- Rollback: Remove per-scope consent decisions (MySQL)
+
+ALTER TABLE user_consent DROP COLUMN IF EXISTS denied_scopes;
+
+DELETE FROM schema_migrations WHERE version = '0006_consent_scope_decisions';
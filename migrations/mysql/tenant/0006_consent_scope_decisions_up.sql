@@ -0,0 +1,26 @@
+-- Migration: Per-scope consent decisions (tenant ConsentPolicy) (MySQL)
+-- Applied to each tenant's isolated database.
+
+-- Add denied_scopes column if it doesn't exist
+-- MySQL doesn't have ADD COLUMN IF NOT EXISTS, use stored procedure
+DELIMITER //
+CREATE PROCEDURE add_user_consent_denied_scopes_column()
+BEGIN
+    DECLARE col_exists INT DEFAULT 0;
+    SELECT COUNT(*) INTO col_exists
+    FROM information_schema.columns
+    WHERE table_schema = DATABASE()
+      AND table_name = 'user_consent'
+      AND column_name = 'denied_scopes';
+    
+    IF col_exists = 0 THEN
+        ALTER TABLE user_consent ADD COLUMN denied_scopes JSON NOT NULL DEFAULT (JSON_ARRAY());
+    END IF;
+END //
+DELIMITER ;
+
+CALL add_user_consent_denied_scopes_column();
+DROP PROCEDURE IF EXISTS add_user_consent_denied_scopes_column;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0006_consent_scope_decisions', NOW());
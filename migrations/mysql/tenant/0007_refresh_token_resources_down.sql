@@ -0,0 +1,5 @@
+-- Rollback: Remove resource indicators from refresh_token (MySQL)
+
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS resources;
+
+DELETE FROM schema_migrations WHERE version = '0007_refresh_token_resources';
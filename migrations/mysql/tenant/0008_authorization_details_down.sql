@@ -0,0 +1,6 @@
+-- Rollback: Remove Rich Authorization Requests (MySQL)
+
+ALTER TABLE user_consent DROP COLUMN IF EXISTS authorization_details;
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS authorization_details;
+
+DELETE FROM schema_migrations WHERE version = '0008_authorization_details';
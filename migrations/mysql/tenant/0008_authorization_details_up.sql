@@ -0,0 +1,36 @@
+-- Migration: Rich Authorization Requests (RFC 9396) (MySQL)
+-- Applied to each tenant's isolated database.
+
+-- Add authorization_details columns if they don't exist
+-- MySQL doesn't have ADD COLUMN IF NOT EXISTS, use stored procedure
+DELIMITER //
+CREATE PROCEDURE add_authorization_details_columns()
+BEGIN
+    DECLARE col_exists INT DEFAULT 0;
+    SELECT COUNT(*) INTO col_exists
+    FROM information_schema.columns
+    WHERE table_schema = DATABASE()
+      AND table_name = 'user_consent'
+      AND column_name = 'authorization_details';
+    
+    IF col_exists = 0 THEN
+        ALTER TABLE user_consent ADD COLUMN authorization_details JSON NULL;
+    END IF;
+
+    SELECT COUNT(*) INTO col_exists
+    FROM information_schema.columns
+    WHERE table_schema = DATABASE()
+      AND table_name = 'refresh_token'
+      AND column_name = 'authorization_details';
+    
+    IF col_exists = 0 THEN
+        ALTER TABLE refresh_token ADD COLUMN authorization_details JSON NULL;
+    END IF;
+END //
+DELIMITER ;
+
+CALL add_authorization_details_columns();
+DROP PROCEDURE IF EXISTS add_authorization_details_columns;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0008_authorization_details', NOW());
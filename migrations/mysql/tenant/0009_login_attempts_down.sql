@@ -0,0 +1,5 @@
+-- Rollback: Remove failed login attempt tracking (MySQL)
+
+DROP TABLE IF EXISTS login_attempt;
+
+DELETE FROM schema_migrations WHERE version = '0009_login_attempts';
@@ -0,0 +1,14 @@
+-- Migration: Failed login attempt tracking for account lockout (MySQL)
+-- Applied to each tenant's isolated database.
+
+-- One row per lockout key: "user:<id>" or "identifier:<email>"
+CREATE TABLE IF NOT EXISTS login_attempt (
+    attempt_key VARCHAR(320) NOT NULL PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    locked_until DATETIME(6) NULL,
+    INDEX idx_login_attempt_locked (locked_until)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0009_login_attempts', NOW());
@@ -0,0 +1,5 @@
+-- Rollback: Remove WebAuthn / passkey credentials (MySQL)
+
+DROP TABLE IF EXISTS webauthn_credential;
+
+DELETE FROM schema_migrations WHERE version = '0010_webauthn_credentials';
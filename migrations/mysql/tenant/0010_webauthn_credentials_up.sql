@@ -0,0 +1,24 @@
+-- Migration: WebAuthn / passkey credentials (MySQL)
+-- Applied to each tenant's isolated database.
+
+CREATE TABLE IF NOT EXISTS webauthn_credential (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    credential_id VARCHAR(1400) NOT NULL,
+    public_key VARBINARY(1024) NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid VARCHAR(36) NOT NULL DEFAULT '',
+    transports JSON,
+    attestation_format VARCHAR(32) NOT NULL DEFAULT 'none',
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    last_used_at DATETIME(6) NULL,
+    CONSTRAINT fk_webauthn_credential_user FOREIGN KEY (user_id) REFERENCES app_user(id) ON DELETE CASCADE,
+    UNIQUE KEY ux_webauthn_credential_id (credential_id(255)),
+    INDEX idx_webauthn_credential_user (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0010_webauthn_credentials', NOW());
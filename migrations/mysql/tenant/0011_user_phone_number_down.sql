@@ -0,0 +1,7 @@
+-- Rollback: Remove phone number fields from app_user (MySQL)
+
+ALTER TABLE app_user DROP INDEX idx_app_user_phone_number;
+ALTER TABLE app_user DROP COLUMN IF EXISTS phone_number_verified;
+ALTER TABLE app_user DROP COLUMN IF EXISTS phone_number;
+
+DELETE FROM schema_migrations WHERE version = '0011_user_phone_number';
@@ -0,0 +1,29 @@
+-- Migration: Add phone number fields to app_user (MySQL)
+-- Applied to each tenant's isolated database.
+
+-- Add phone columns if they don't exist
+-- MySQL doesn't have ADD COLUMN IF NOT EXISTS, use stored procedure
+DELIMITER //
+CREATE PROCEDURE add_phone_number_columns()
+BEGIN
+    DECLARE col_exists INT DEFAULT 0;
+    SELECT COUNT(*) INTO col_exists
+    FROM information_schema.columns
+    WHERE table_schema = DATABASE()
+      AND table_name = 'app_user'
+      AND column_name = 'phone_number';
+    
+    IF col_exists = 0 THEN
+        -- E.164 phone number (OIDC phone scope, SMS/voice MFA)
+        ALTER TABLE app_user ADD COLUMN phone_number VARCHAR(16) NULL;
+        ALTER TABLE app_user ADD COLUMN phone_number_verified BOOLEAN NOT NULL DEFAULT FALSE;
+        CREATE INDEX idx_app_user_phone_number ON app_user(phone_number);
+    END IF;
+END //
+DELIMITER ;
+
+CALL add_phone_number_columns();
+DROP PROCEDURE IF EXISTS add_phone_number_columns;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0011_user_phone_number', NOW());
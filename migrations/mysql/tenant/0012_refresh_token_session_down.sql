@@ -0,0 +1,5 @@
+-- Rollback: Remove session_id from refresh_token (MySQL)
+
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS session_id;
+
+DELETE FROM schema_migrations WHERE version = '0012_refresh_token_session';
@@ -0,0 +1,27 @@
+-- Migration: Browser session of refresh tokens (MySQL)
+-- Applied to each tenant's isolated database.
+
+-- Add session_id column if it doesn't exist
+-- MySQL doesn't have ADD COLUMN IF NOT EXISTS, use stored procedure
+DELIMITER //
+CREATE PROCEDURE add_refresh_token_session_column()
+BEGIN
+    DECLARE col_exists INT DEFAULT 0;
+    SELECT COUNT(*) INTO col_exists
+    FROM information_schema.columns
+    WHERE table_schema = DATABASE()
+      AND table_name = 'refresh_token'
+      AND column_name = 'session_id';
+    
+    IF col_exists = 0 THEN
+        -- session_id_hash of the session the token family was issued under
+        ALTER TABLE refresh_token ADD COLUMN session_id VARCHAR(255) NULL;
+    END IF;
+END //
+DELIMITER ;
+
+CALL add_refresh_token_session_column();
+DROP PROCEDURE IF EXISTS add_refresh_token_session_column;
+
+-- Record migration
+INSERT IGNORE INTO schema_migrations (version, applied_at) VALUES ('0012_refresh_token_session', NOW());
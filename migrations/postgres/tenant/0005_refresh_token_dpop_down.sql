@@ -0,0 +1,7 @@
+-- Rollback: Remove DPoP binding from refresh_token
+
+BEGIN;
+
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS dpop_jkt;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Migration: Bind refresh tokens to a DPoP key (RFC 9449)
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- JWK SHA-256 thumbprint of the DPoP key (public clients only)
+-- NULL = refresh token not sender-constrained
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS dpop_jkt TEXT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Rollback: Remove per-scope consent decisions
+
+BEGIN;
+
+ALTER TABLE user_consent DROP COLUMN IF EXISTS denied_scopes;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Migration: Per-scope consent decisions (tenant ConsentPolicy)
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- Scopes the user explicitly denied; remembered when
+-- consent_mode = per_scope and remember_scope_decisions is enabled
+ALTER TABLE user_consent ADD COLUMN IF NOT EXISTS denied_scopes TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
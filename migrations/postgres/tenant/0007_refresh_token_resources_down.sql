@@ -0,0 +1,7 @@
+-- Rollback: Remove resource indicators from refresh_token
+
+BEGIN;
+
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS resources;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Migration: Resource indicators granted to refresh tokens (RFC 8707)
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- API resources (identifiers) the authorization grant covers;
+-- refreshed access tokens can only target these
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS resources TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Rollback: Remove Rich Authorization Requests
+
+BEGIN;
+
+ALTER TABLE user_consent DROP COLUMN IF EXISTS authorization_details;
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS authorization_details;
+
+COMMIT;
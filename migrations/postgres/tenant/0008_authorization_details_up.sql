@@ -0,0 +1,12 @@
+-- Migration: Rich Authorization Requests (RFC 9396)
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- authorization_details the user approved on the consent screen
+ALTER TABLE user_consent ADD COLUMN IF NOT EXISTS authorization_details JSONB NOT NULL DEFAULT '[]';
+
+-- authorization_details of the grant; refreshed access tokens carry them
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS authorization_details JSONB NOT NULL DEFAULT '[]';
+
+COMMIT;
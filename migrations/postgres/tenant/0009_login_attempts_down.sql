@@ -0,0 +1,7 @@
+-- Rollback: Remove failed login attempt tracking
+
+BEGIN;
+
+DROP TABLE IF EXISTS login_attempt;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Migration: Failed login attempt tracking for account lockout
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- One row per lockout key: "user:<id>" or "identifier:<email>"
+CREATE TABLE IF NOT EXISTS login_attempt (
+    attempt_key TEXT PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempt_locked ON login_attempt(locked_until) WHERE locked_until IS NOT NULL;
+
+COMMIT;
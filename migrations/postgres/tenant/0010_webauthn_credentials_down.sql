@@ -0,0 +1,7 @@
+-- Rollback: Remove WebAuthn / passkey credentials
+
+BEGIN;
+
+DROP TABLE IF EXISTS webauthn_credential;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Migration: WebAuthn / passkey credentials
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS webauthn_credential (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    credential_id TEXT NOT NULL,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid TEXT NOT NULL DEFAULT '',
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    attestation_format TEXT NOT NULL DEFAULT 'none',
+    name TEXT NOT NULL DEFAULT '',
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_webauthn_credential_id ON webauthn_credential(credential_id);
+CREATE INDEX IF NOT EXISTS ix_webauthn_credential_user ON webauthn_credential(user_id);
+
+COMMIT;
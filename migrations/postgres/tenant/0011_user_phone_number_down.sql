@@ -0,0 +1,9 @@
+-- Rollback: Remove phone number fields from app_user
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_app_user_phone_number;
+ALTER TABLE app_user DROP COLUMN IF EXISTS phone_number_verified;
+ALTER TABLE app_user DROP COLUMN IF EXISTS phone_number;
+
+COMMIT;
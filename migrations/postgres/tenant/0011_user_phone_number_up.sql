@@ -0,0 +1,12 @@
+-- Migration: Add phone number fields to app_user
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- E.164 phone number (OIDC phone scope, SMS/voice MFA)
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS phone_number TEXT;
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS phone_number_verified BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_app_user_phone_number ON app_user(phone_number) WHERE phone_number IS NOT NULL;
+
+COMMIT;
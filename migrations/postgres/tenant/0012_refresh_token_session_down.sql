@@ -0,0 +1,7 @@
+-- Rollback: Remove session_id from refresh_token
+
+BEGIN;
+
+ALTER TABLE refresh_token DROP COLUMN IF EXISTS session_id;
+
+COMMIT;
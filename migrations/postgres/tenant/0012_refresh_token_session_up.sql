@@ -0,0 +1,10 @@
+-- Migration: Browser session of refresh tokens
+-- Applied to each tenant's isolated database/schema.
+
+BEGIN;
+
+-- session_id_hash of the session the token family was issued under;
+-- reuse of a rotated token ends only that session
+ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS session_id TEXT;
+
+COMMIT;